@@ -0,0 +1,146 @@
+package influxdb
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AlertState is the state of the lifecycle of an alert.
+type AlertState string
+
+// alert states
+const (
+	// AlertOpen is the state of an alert whose series is not ok.
+	AlertOpen AlertState = "open"
+	// AlertAcknowledged is the state of an open alert that someone is handling.
+	AlertAcknowledged AlertState = "acknowledged"
+	// AlertResolved is the state of an alert whose series returned to ok, or
+	// that was resolved by hand.
+	AlertResolved AlertState = "resolved"
+)
+
+// Valid returns an error if the state is unknown.
+func (s AlertState) Valid() error {
+	switch s {
+	case AlertOpen, AlertAcknowledged, AlertResolved:
+		return nil
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  "alert state must be one of open, acknowledged or resolved",
+	}
+}
+
+// Alert tracks a series of a check from its first status that is not ok until
+// it returns to ok. At most one alert per series is unresolved, repeated
+// statuses of the series update it instead of opening new alerts.
+type Alert struct {
+	ID        ID     `json:"id"`
+	OrgID     ID     `json:"orgID"`
+	CheckID   ID     `json:"checkID"`
+	CheckName string `json:"checkName"`
+	// Tags are the tags of the series, without the columns of the check.
+	Tags []Tag `json:"tags"`
+
+	State   AlertState `json:"state"`
+	Level   string     `json:"level"`
+	Message string     `json:"message,omitempty"`
+	// Count is the number of statuses of the series that were not ok.
+	Count int `json:"count"`
+
+	StartedAt  time.Time `json:"startedAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
+	AcknowledgedBy ID         `json:"acknowledgedBy,omitempty"`
+	AssigneeID     ID         `json:"assigneeID,omitempty"`
+	ResolvedAt     *time.Time `json:"resolvedAt,omitempty"`
+
+	// Escalations are the escalation steps of notification rules that
+	// notified the alert, the last step of every rule.
+	Escalations []AlertEscalation `json:"escalations,omitempty"`
+}
+
+// AlertEscalation is the last escalation step of a notification rule that
+// notified an alert.
+type AlertEscalation struct {
+	RuleID ID `json:"ruleID"`
+	// Step is the 1-based index of the escalation step of the rule.
+	Step        int       `json:"step"`
+	EscalatedAt time.Time `json:"escalatedAt"`
+}
+
+// EscalationStep returns the last escalation step of the notification rule
+// that notified the alert, 0 when none did.
+func (a *Alert) EscalationStep(ruleID ID) int {
+	for _, e := range a.Escalations {
+		if e.RuleID == ruleID {
+			return e.Step
+		}
+	}
+	return 0
+}
+
+// Escalate records that the escalation step of the notification rule notified
+// the alert, earlier steps are ignored.
+func (a *Alert) Escalate(ruleID ID, step int, at time.Time) {
+	for i, e := range a.Escalations {
+		if e.RuleID != ruleID {
+			continue
+		}
+		if step > e.Step {
+			a.Escalations[i] = AlertEscalation{RuleID: ruleID, Step: step, EscalatedAt: at}
+		}
+		return
+	}
+	a.Escalations = append(a.Escalations, AlertEscalation{RuleID: ruleID, Step: step, EscalatedAt: at})
+}
+
+// SeriesKey returns the key of the series of the alert.
+func (a *Alert) SeriesKey() string {
+	return AlertSeriesKey(a.OrgID, a.CheckID, a.Tags)
+}
+
+// AlertSeriesKey returns the key of the series of a check of the organization
+// with the tags, the order of the tags does not matter.
+func AlertSeriesKey(orgID, checkID ID, tags []Tag) string {
+	pairs := make([]string, 0, len(tags))
+	for _, t := range tags {
+		pairs = append(pairs, t.Key+"="+t.Value)
+	}
+	sort.Strings(pairs)
+	return orgID.String() + "," + checkID.String() + "," + strings.Join(pairs, ",")
+}
+
+// AlertFilter represents a set of filters that restrict the returned alerts.
+type AlertFilter struct {
+	OrgID   *ID
+	CheckID *ID
+	State   *AlertState
+}
+
+// AlertAcknowledgement is the acknowledgement of an alert by a user.
+type AlertAcknowledgement struct {
+	UserID ID `json:"-"`
+	// AssigneeID is the user handling the alert, the acknowledging user when unset.
+	AssigneeID ID `json:"assigneeID,omitempty"`
+}
+
+// AlertService manages the alerts of checks.
+type AlertService interface {
+	// FindAlertByID returns a single alert by ID.
+	FindAlertByID(ctx context.Context, id ID) (*Alert, error)
+
+	// FindAlerts returns the alerts matching the filter, most recent first.
+	// The offset and limit of the options page the alerts.
+	FindAlerts(ctx context.Context, filter AlertFilter, opt ...FindOptions) ([]*Alert, error)
+
+	// AcknowledgeAlert acknowledges an open alert.
+	AcknowledgeAlert(ctx context.Context, id ID, ack AlertAcknowledgement) (*Alert, error)
+
+	// ResolveAlert resolves an alert before its series returns to ok, the
+	// next status of the series that is not ok opens a new alert.
+	ResolveAlert(ctx context.Context, id ID) (*Alert, error)
+}
@@ -0,0 +1,41 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertSeriesKey(t *testing.T) {
+	key := influxdb.AlertSeriesKey(1, 10, []influxdb.Tag{
+		{Key: "region", Value: "eu"},
+		{Key: "host", Value: "a"},
+	})
+	assert.Equal(t, "0000000000000001,000000000000000a,host=a,region=eu", key)
+
+	a := &influxdb.Alert{OrgID: 1, CheckID: 10, Tags: []influxdb.Tag{
+		{Key: "host", Value: "a"},
+		{Key: "region", Value: "eu"},
+	}}
+	assert.Equal(t, key, a.SeriesKey())
+}
+
+func TestAlertState_Valid(t *testing.T) {
+	assert.NoError(t, influxdb.AlertAcknowledged.Valid())
+	assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(influxdb.AlertState("closed").Valid()))
+}
+
+func TestAlert_Escalate(t *testing.T) {
+	at := time.Date(2020, 1, 1, 0, 15, 0, 0, time.UTC)
+	a := &influxdb.Alert{}
+	assert.Equal(t, 0, a.EscalationStep(1))
+
+	a.Escalate(1, 2, at)
+	a.Escalate(1, 1, at.Add(time.Minute))
+	a.Escalate(2, 1, at)
+	assert.Equal(t, 2, a.EscalationStep(1))
+	assert.Equal(t, 1, a.EscalationStep(2))
+	assert.Equal(t, at, a.Escalations[0].EscalatedAt)
+}
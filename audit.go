@@ -0,0 +1,138 @@
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// AuditAction is the kind of mutation recorded by an audit event.
+type AuditAction string
+
+// Audit actions that can be recorded.
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+)
+
+// Valid returns an error if the action is not a known audit action.
+func (a AuditAction) Valid() error {
+	switch a {
+	case AuditActionCreate, AuditActionUpdate, AuditActionDelete:
+		return nil
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "audit action must be one of create, update or delete",
+		}
+	}
+}
+
+// AuditEvent is an immutable record of a change of a resource, or of a
+// mutating API operation that failed.
+type AuditEvent struct {
+	ID   ID        `json:"id"`
+	Time time.Time `json:"time"`
+
+	// OrgID is the organization the mutated resource belongs to, when known.
+	OrgID ID `json:"orgID,omitempty"`
+	// UserID is the actor that performed the operation.
+	UserID ID `json:"userID,omitempty"`
+	// AuthorizationID is the token used to perform the operation.
+	// It is not set for session based requests.
+	AuthorizationID ID `json:"authorizationID,omitempty"`
+
+	ResourceType ResourceType `json:"resourceType"`
+	ResourceID   ID           `json:"resourceID,omitempty"`
+	Action       AuditAction  `json:"action"`
+
+	// Method and Path describe the API request that caused the event, they
+	// are not set for changes made outside of an API request.
+	Method string `json:"method,omitempty"`
+	Path   string `json:"path,omitempty"`
+	// StatusCode is the error status code returned to the client for an
+	// operation that failed. Changes are only recorded once they are stored.
+	StatusCode int `json:"statusCode,omitempty"`
+	// Summary is a short description of the change, the fields that changed
+	// or, for an operation that failed, the fields sent by the client.
+	Summary string `json:"summary,omitempty"`
+	// Changes are the top level fields of the resource that changed, with
+	// their values before and after the operation.
+	Changes []AuditChange `json:"changes,omitempty"`
+	// SourceIP is the address of the client, the peer address unless the
+	// peer is a trusted proxy.
+	SourceIP string `json:"sourceIP,omitempty"`
+}
+
+// AuditChange is the change of a top level field of a resource. The values of
+// fields that may hold credentials are redacted.
+type AuditChange struct {
+	Field string `json:"field"`
+	// Before is the value before the operation, not set when the field was added.
+	Before json.RawMessage `json:"before,omitempty"`
+	// After is the value after the operation, not set when the field was removed.
+	After json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditEventFilter represents a set of filters that restrict the returned audit events.
+type AuditEventFilter struct {
+	OrgID           *ID
+	UserID          *ID
+	AuthorizationID *ID
+	ResourceType    *ResourceType
+	ResourceID      *ID
+	Action          *AuditAction
+
+	// Since and Until restrict the events to the half open interval [Since, Until).
+	Since *time.Time
+	Until *time.Time
+}
+
+// QueryParams converts AuditEventFilter fields to url query params.
+func (f AuditEventFilter) QueryParams() map[string][]string {
+	qp := map[string][]string{}
+	if f.OrgID != nil {
+		qp["orgID"] = []string{f.OrgID.String()}
+	}
+	if f.UserID != nil {
+		qp["userID"] = []string{f.UserID.String()}
+	}
+	if f.AuthorizationID != nil {
+		qp["authorizationID"] = []string{f.AuthorizationID.String()}
+	}
+	if f.ResourceType != nil {
+		qp["resourceType"] = []string{string(*f.ResourceType)}
+	}
+	if f.ResourceID != nil {
+		qp["resourceID"] = []string{f.ResourceID.String()}
+	}
+	if f.Action != nil {
+		qp["action"] = []string{string(*f.Action)}
+	}
+	if f.Since != nil {
+		qp["start"] = []string{f.Since.Format(time.RFC3339)}
+	}
+	if f.Until != nil {
+		qp["stop"] = []string{f.Until.Format(time.RFC3339)}
+	}
+	return qp
+}
+
+// AuditService records and retrieves audit events. Events are append-only:
+// there is deliberately no way to update or delete them through the service.
+type AuditService interface {
+	// RecordAuditEvent appends an event to the audit log.
+	// The ID and Time of the event are set when they are empty.
+	RecordAuditEvent(ctx context.Context, e *AuditEvent) error
+
+	// FindAuditEvents returns the events matching the filter, newest first
+	// unless the find options request otherwise.
+	FindAuditEvents(ctx context.Context, filter AuditEventFilter, opts ...FindOptions) ([]*AuditEvent, error)
+}
+
+// DefaultAuditEventFindOptions are the default options for listing audit events.
+var DefaultAuditEventFindOptions = FindOptions{
+	Descending: true,
+	Limit:      100,
+}
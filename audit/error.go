@@ -0,0 +1,31 @@
+package audit
+
+import (
+	"github.com/influxdata/influxdb/v2"
+)
+
+var (
+	// ErrOrgIDRequired is returned when listing events without an organization
+	// by an authorizer that cannot write every organization.
+	ErrOrgIDRequired = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "orgID is required",
+	}
+)
+
+// ErrInvalidEventID is used when the ID of an audit event cannot be encoded.
+func ErrInvalidEventID(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "invalid audit event ID",
+		Err:  err,
+	}
+}
+
+// ErrInternalService is used when the error comes from an internal system.
+func ErrInternalService(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Err:  err,
+	}
+}
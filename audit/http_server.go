@@ -0,0 +1,232 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/influxdata/influxdb/v2"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"go.uber.org/zap"
+)
+
+// PrefixAudit is the prefix of the audit log API.
+const PrefixAudit = "/api/v2/audit"
+
+// Handler serves the audit log.
+type Handler struct {
+	chi.Router
+	api      *kithttp.API
+	log      *zap.Logger
+	auditSvc influxdb.AuditService
+}
+
+// NewHTTPHandler constructs a new http server for the audit log.
+func NewHTTPHandler(log *zap.Logger, svc influxdb.AuditService) *Handler {
+	h := &Handler{
+		api:      kithttp.NewAPI(kithttp.WithLog(log)),
+		log:      log,
+		auditSvc: svc,
+	}
+
+	r := chi.NewRouter()
+	r.Use(
+		middleware.Recoverer,
+		middleware.RequestID,
+		middleware.RealIP,
+	)
+
+	r.Route("/", func(r chi.Router) {
+		r.Get("/", h.handleGetEvents)
+		r.Get("/export", h.handleExportEvents)
+	})
+
+	h.Router = r
+	return h
+}
+
+// Prefix returns the prefix the handler is mounted at.
+func (h *Handler) Prefix() string {
+	return PrefixAudit
+}
+
+type eventsResponse struct {
+	Links  *influxdb.PagingLinks  `json:"links"`
+	Events []*influxdb.AuditEvent `json:"events"`
+}
+
+// handleGetEvents is the HTTP handler for the GET /api/v2/audit route.
+func (h *Handler) handleGetEvents(w http.ResponseWriter, r *http.Request) {
+	filter, opts, err := decodeEventsRequest(r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	events, err := h.auditSvc.FindAuditEvents(r.Context(), filter, *opts)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Audit events retrieved", zap.Int("count", len(events)))
+
+	h.api.Respond(w, r, http.StatusOK, eventsResponse{
+		Links:  influxdb.NewPagingLinks(PrefixAudit, *opts, filter, len(events)),
+		Events: events,
+	})
+}
+
+// handleExportEvents is the HTTP handler for the GET /api/v2/audit/export route.
+// Unless a limit is provided every matching event is exported as CSV.
+func (h *Handler) handleExportEvents(w http.ResponseWriter, r *http.Request) {
+	filter, opts, err := decodeEventsRequest(r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	if r.URL.Query().Get("limit") == "" {
+		opts.Limit = 0
+	}
+
+	events, err := h.auditSvc.FindAuditEvents(r.Context(), filter, *opts)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	cw.Write(csvHeader)
+	for _, e := range events {
+		cw.Write(csvRecord(e))
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		h.log.Error("Failed to export audit events", zap.Error(err))
+	}
+}
+
+var csvHeader = []string{
+	"id", "time", "orgID", "userID", "authorizationID", "resourceType", "resourceID",
+	"action", "method", "path", "statusCode", "summary", "changes", "sourceIP",
+}
+
+// changes encodes the changes of an event as a JSON array.
+func changes(cs []influxdb.AuditChange) string {
+	if len(cs) == 0 {
+		return ""
+	}
+	b, err := json.Marshal(cs)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func csvRecord(e *influxdb.AuditEvent) []string {
+	optID := func(id influxdb.ID) string {
+		if !id.Valid() {
+			return ""
+		}
+		return id.String()
+	}
+	return []string{
+		e.ID.String(),
+		e.Time.Format(time.RFC3339Nano),
+		optID(e.OrgID),
+		optID(e.UserID),
+		optID(e.AuthorizationID),
+		string(e.ResourceType),
+		optID(e.ResourceID),
+		string(e.Action),
+		e.Method,
+		e.Path,
+		strconv.Itoa(e.StatusCode),
+		e.Summary,
+		changes(e.Changes),
+		e.SourceIP,
+	}
+}
+
+func decodeEventsRequest(r *http.Request) (influxdb.AuditEventFilter, *influxdb.FindOptions, error) {
+	var filter influxdb.AuditEventFilter
+	qp := r.URL.Query()
+
+	idParams := []struct {
+		name string
+		dst  **influxdb.ID
+	}{
+		{"orgID", &filter.OrgID},
+		{"userID", &filter.UserID},
+		{"authorizationID", &filter.AuthorizationID},
+		{"resourceID", &filter.ResourceID},
+	}
+	for _, p := range idParams {
+		v := qp.Get(p.name)
+		if v == "" {
+			continue
+		}
+		id, err := influxdb.IDFromString(v)
+		if err != nil {
+			return filter, nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  p.name + " is invalid",
+				Err:  err,
+			}
+		}
+		*p.dst = id
+	}
+
+	if rt := qp.Get("resourceType"); rt != "" {
+		t := influxdb.ResourceType(rt)
+		filter.ResourceType = &t
+	}
+
+	if a := qp.Get("action"); a != "" {
+		action := influxdb.AuditAction(a)
+		if err := action.Valid(); err != nil {
+			return filter, nil, err
+		}
+		filter.Action = &action
+	}
+
+	timeParams := []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"start", &filter.Since},
+		{"stop", &filter.Until},
+	}
+	for _, p := range timeParams {
+		v := qp.Get(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  p.name + " must be an RFC3339 timestamp",
+				Err:  err,
+			}
+		}
+		*p.dst = &t
+	}
+
+	opts, err := influxdb.DecodeFindOptions(r)
+	if err != nil {
+		return filter, nil, err
+	}
+	if qp.Get("descending") == "" {
+		opts.Descending = influxdb.DefaultAuditEventFindOptions.Descending
+	}
+
+	return filter, opts, nil
+}
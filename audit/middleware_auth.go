@@ -0,0 +1,36 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+)
+
+var _ influxdb.AuditService = (*AuthorizedService)(nil)
+
+// AuthorizedService restricts reads of the audit log to the owners of
+// organizations. Reading the events of an organization requires write access
+// to that organization, reading events across all organizations requires write
+// access to every organization. Members that can only read an organization
+// cannot read its audit log.
+type AuthorizedService struct {
+	influxdb.AuditService
+}
+
+// NewAuthorizedService wraps an audit service with authorization checks.
+func NewAuthorizedService(s influxdb.AuditService) *AuthorizedService {
+	return &AuthorizedService{AuditService: s}
+}
+
+// FindAuditEvents checks that the authorizer may read the requested events before listing them.
+func (s *AuthorizedService) FindAuditEvents(ctx context.Context, filter influxdb.AuditEventFilter, opts ...influxdb.FindOptions) ([]*influxdb.AuditEvent, error) {
+	if filter.OrgID != nil {
+		if _, _, err := authorizer.AuthorizeWriteOrg(ctx, *filter.OrgID); err != nil {
+			return nil, err
+		}
+	} else if _, _, err := authorizer.AuthorizeWriteGlobal(ctx, influxdb.OrgsResourceType); err != nil {
+		return nil, ErrOrgIDRequired
+	}
+	return s.AuditService.FindAuditEvents(ctx, filter, opts...)
+}
@@ -0,0 +1,72 @@
+package audit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/audit"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizedService_FindAuditEvents(t *testing.T) {
+	orgID := influxdb.ID(10)
+	otherOrgID := influxdb.ID(11)
+
+	orgPerm := func(a influxdb.Action, id influxdb.ID) influxdb.Permission {
+		return influxdb.Permission{Action: a, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &id}}
+	}
+	globalPerm := func(a influxdb.Action) influxdb.Permission {
+		return influxdb.Permission{Action: a, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType}}
+	}
+
+	tests := []struct {
+		name   string
+		perms  []influxdb.Permission
+		orgID  *influxdb.ID
+		wantEr string
+	}{
+		{
+			name:  "org owner",
+			perms: []influxdb.Permission{orgPerm(influxdb.WriteAction, orgID)},
+			orgID: &orgID,
+		},
+		{
+			name:   "org member",
+			perms:  []influxdb.Permission{orgPerm(influxdb.ReadAction, orgID)},
+			orgID:  &orgID,
+			wantEr: influxdb.EUnauthorized,
+		},
+		{
+			name:   "owner of another org",
+			perms:  []influxdb.Permission{orgPerm(influxdb.WriteAction, otherOrgID)},
+			orgID:  &orgID,
+			wantEr: influxdb.EUnauthorized,
+		},
+		{
+			name:  "all orgs",
+			perms: []influxdb.Permission{globalPerm(influxdb.WriteAction)},
+		},
+		{
+			name:   "all orgs read only",
+			perms:  []influxdb.Permission{globalPerm(influxdb.ReadAction)},
+			wantEr: influxdb.EInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := audit.NewAuthorizedService(mock.NewAuditService())
+			ctx := icontext.SetAuthorizer(context.Background(), mock.NewMockAuthorizer(false, tt.perms))
+
+			_, err := svc.FindAuditEvents(ctx, influxdb.AuditEventFilter{OrgID: tt.orgID})
+			if tt.wantEr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tt.wantEr, influxdb.ErrorCode(err))
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"go.uber.org/zap"
+)
+
+// maxCapturedBytes bounds how much of the request body is buffered to build
+// the summary of an event.
+const maxCapturedBytes = 64 * 1024
+
+const apiPrefix = "/api/v2/"
+
+// skippedPaths are mutating requests that do not change any resource metadata
+// or that happen before a request is authenticated.
+var skippedPaths = []string{
+	"/api/v2/write",
+	"/api/v2/query",
+	"/api/v2/signin",
+	"/api/v2/signout",
+	"/api/v2/setup",
+	"/api/v2/audit",
+}
+
+// pathResourceTypes maps the path segments that do not match their resource type name.
+var pathResourceTypes = map[string]influxdb.ResourceType{
+	"dbrps": influxdb.DBRPResourceType,
+	"me":    influxdb.UsersResourceType,
+}
+
+// redactedFields are the substrings of the names of fields whose values are
+// never recorded in the changes of an event.
+var redactedFields = []string{"password", "token", "secret"}
+
+var redacted = json.RawMessage(`"[redacted]"`)
+
+// NewHTTPMiddleware returns a middleware that attributes the changes of
+// resources made by the mutating requests it serves to the requests. The
+// changes are recorded by the Store, with the method, path and source address
+// of the request that made them. Requests that fail change no resource, the
+// middleware records them itself with the fields sent by the client. It must
+// be installed behind the authentication handler so that the authorizer of
+// the request is available on its context.
+//
+// The source address of requests forwarded by the trusted proxies is read
+// from their forwarding headers.
+func NewHTTPMiddleware(log *zap.Logger, svc influxdb.AuditService, trustedProxies []*net.IPNet) kithttp.Middleware {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if !isMutating(r.Method) || isSkipped(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			auth, err := icontext.GetAuthorizer(r.Context())
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ip := sourceIP(r, trustedProxies)
+			r = r.WithContext(withRequest(r.Context(), request{
+				method:   r.Method,
+				path:     r.URL.Path,
+				sourceIP: ip,
+			}))
+
+			reqBody := &limitedBuffer{max: maxCapturedBytes}
+			if r.Body != nil {
+				r.Body = &teeReadCloser{
+					Reader: io.TeeReader(r.Body, reqBody),
+					Closer: r.Body,
+				}
+			}
+			rw := kithttp.NewStatusResponseWriter(w)
+
+			next.ServeHTTP(rw, r)
+
+			if rw.Code() < http.StatusBadRequest {
+				return
+			}
+			e := newEvent(r, auth, rw.Code(), reqBody.Bytes())
+			if e == nil {
+				return
+			}
+			e.SourceIP = ip
+			// the request context may already be canceled by the client, the
+			// event must be recorded regardless.
+			if err := svc.RecordAuditEvent(context.Background(), e); err != nil {
+				log.Error("Failed to record audit event",
+					zap.String("method", e.Method),
+					zap.String("path", e.Path),
+					zap.Error(err),
+				)
+			}
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// newEvent returns the event of a failed request.
+func newEvent(r *http.Request, auth influxdb.Authorizer, code int, reqBody []byte) *influxdb.AuditEvent {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, apiPrefix), "/"), "/")
+	if len(segments) == 0 || segments[0] == "" {
+		return nil
+	}
+
+	e := &influxdb.AuditEvent{
+		UserID:       auth.GetUserID(),
+		ResourceType: influxdb.ResourceType(segments[0]),
+		Method:       r.Method,
+		Path:         r.URL.Path,
+		StatusCode:   code,
+		Summary:      summarize(reqBody),
+	}
+	if rt, ok := pathResourceTypes[segments[0]]; ok {
+		e.ResourceType = rt
+	}
+	if a, ok := auth.(*influxdb.Authorization); ok {
+		e.AuthorizationID = a.ID
+	}
+
+	var pathID *influxdb.ID
+	if len(segments) > 1 {
+		pathID, _ = influxdb.IDFromString(segments[1])
+	}
+	if segments[0] == "me" {
+		pathID = &e.UserID
+	}
+
+	switch {
+	case r.Method == http.MethodDelete || segments[len(segments)-1] == "delete":
+		e.Action = influxdb.AuditActionDelete
+	case r.Method == http.MethodPost && pathID == nil:
+		e.Action = influxdb.AuditActionCreate
+	default:
+		e.Action = influxdb.AuditActionUpdate
+	}
+
+	if pathID != nil {
+		e.ResourceID = *pathID
+	}
+
+	if id, err := influxdb.IDFromString(r.URL.Query().Get("orgID")); err == nil {
+		e.OrgID = *id
+	} else if id := kithttp.OrgIDFromContext(r.Context()); id != nil {
+		e.OrgID = *id
+	} else if e.ResourceType == influxdb.OrgsResourceType && e.ResourceID.Valid() {
+		e.OrgID = e.ResourceID
+	} else if a, ok := auth.(*influxdb.Authorization); ok {
+		e.OrgID = a.OrgID
+	}
+
+	return e
+}
+
+// summarize names the top level fields of a JSON request body.
+// Values are never included as they may hold secrets.
+func summarize(body []byte) string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil || len(fields) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return "fields: " + strings.Join(keys, ", ")
+}
+
+func isRedacted(field string) bool {
+	field = strings.ToLower(field)
+	for _, f := range redactedFields {
+		if strings.Contains(field, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceIP returns the address of the client. Only requests of trusted
+// proxies are attributed to the address they forward, the last address of
+// X-Forwarded-For that is not a trusted proxy itself, or X-Real-IP.
+func sourceIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	peer, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		peer = r.RemoteAddr
+	}
+	if !isTrusted(peer, trustedProxies) {
+		return peer
+	}
+
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		hops := strings.Split(fwd, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if !isTrusted(hop, trustedProxies) || i == 0 {
+				return hop
+			}
+		}
+	}
+	if ip := r.Header.Get("X-Real-IP"); ip != "" {
+		return ip
+	}
+	return peer
+}
+
+func isTrusted(addr string, trustedProxies []*net.IPNet) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTrustedProxies parses the addresses of trusted proxies, IP addresses
+// or CIDR ranges.
+func ParseTrustedProxies(addrs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(addrs))
+	for _, addr := range addrs {
+		if !strings.Contains(addr, "/") {
+			ip := net.ParseIP(addr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy address %q", addr)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy range %q: %v", addr, err)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func isSkipped(path string) bool {
+	for _, p := range skippedPaths {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+type teeReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// limitedBuffer keeps the first max bytes written to it and silently drops the rest.
+type limitedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if rem := b.max - b.Len(); rem > 0 {
+		if len(p) > rem {
+			b.Buffer.Write(p[:rem])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,240 @@
+package audit_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/audit"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	auth := &influxdb.Authorization{
+		ID:     influxdb.ID(1),
+		OrgID:  influxdb.ID(2),
+		UserID: influxdb.ID(3),
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		auth   influxdb.Authorizer
+		status int
+		want   *influxdb.AuditEvent
+	}{
+		{
+			name:   "successful requests are recorded by the store",
+			method: http.MethodPost,
+			path:   "/api/v2/buckets",
+			body:   `{"name":"b1","orgID":"0000000000000009","retentionRules":[]}`,
+			auth:   auth,
+			status: http.StatusCreated,
+		},
+		{
+			name:   "failed update names the fields but not their values",
+			method: http.MethodPatch,
+			path:   "/api/v2/tasks/000000000000000b",
+			body:   `{"flux":"secret script"}`,
+			auth:   auth,
+			status: http.StatusBadRequest,
+			want: &influxdb.AuditEvent{
+				OrgID:           2,
+				UserID:          3,
+				AuthorizationID: 1,
+				ResourceType:    influxdb.TasksResourceType,
+				ResourceID:      11,
+				Action:          influxdb.AuditActionUpdate,
+				Method:          http.MethodPatch,
+				Path:            "/api/v2/tasks/000000000000000b",
+				StatusCode:      http.StatusBadRequest,
+				Summary:         "fields: flux",
+				SourceIP:        "192.0.2.1",
+			},
+		},
+		{
+			name:   "failed create",
+			method: http.MethodPost,
+			path:   "/api/v2/buckets",
+			body:   `{"name":"b1","orgID":"0000000000000009"}`,
+			auth:   auth,
+			status: http.StatusForbidden,
+			want: &influxdb.AuditEvent{
+				OrgID:           2,
+				UserID:          3,
+				AuthorizationID: 1,
+				ResourceType:    influxdb.BucketsResourceType,
+				Action:          influxdb.AuditActionCreate,
+				Method:          http.MethodPost,
+				Path:            "/api/v2/buckets",
+				StatusCode:      http.StatusForbidden,
+				Summary:         "fields: name, orgID",
+				SourceIP:        "192.0.2.1",
+			},
+		},
+		{
+			name:   "secret deletion is a delete",
+			method: http.MethodPost,
+			path:   "/api/v2/orgs/0000000000000002/secrets/delete",
+			body:   `{"secrets":["k"]}`,
+			auth:   auth,
+			status: http.StatusNotFound,
+			want: &influxdb.AuditEvent{
+				OrgID:           2,
+				UserID:          3,
+				AuthorizationID: 1,
+				ResourceType:    influxdb.OrgsResourceType,
+				ResourceID:      2,
+				Action:          influxdb.AuditActionDelete,
+				Method:          http.MethodPost,
+				Path:            "/api/v2/orgs/0000000000000002/secrets/delete",
+				StatusCode:      http.StatusNotFound,
+				Summary:         "fields: secrets",
+				SourceIP:        "192.0.2.1",
+			},
+		},
+		{
+			name:   "failed requests of sessions",
+			method: http.MethodDelete,
+			path:   "/api/v2/checks/000000000000000c",
+			auth:   &influxdb.Session{ID: 5, UserID: 6},
+			status: http.StatusForbidden,
+			want: &influxdb.AuditEvent{
+				UserID:       6,
+				ResourceType: influxdb.ChecksResourceType,
+				ResourceID:   12,
+				Action:       influxdb.AuditActionDelete,
+				Method:       http.MethodDelete,
+				Path:         "/api/v2/checks/000000000000000c",
+				StatusCode:   http.StatusForbidden,
+				SourceIP:     "192.0.2.1",
+			},
+		},
+		{
+			name:   "reads are not recorded",
+			method: http.MethodGet,
+			path:   "/api/v2/buckets",
+			auth:   auth,
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "writes are not recorded",
+			method: http.MethodPost,
+			path:   "/api/v2/write",
+			auth:   auth,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "unauthenticated requests are not recorded",
+			method: http.MethodPost,
+			path:   "/api/v2/signin",
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *influxdb.AuditEvent
+			svc := mock.NewAuditService()
+			svc.RecordAuditEventFn = func(ctx context.Context, e *influxdb.AuditEvent) error {
+				got = e
+				return nil
+			}
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// drain the body as a handler would.
+				var buf bytes.Buffer
+				buf.ReadFrom(r.Body)
+				w.WriteHeader(tt.status)
+			})
+			h := audit.NewHTTPMiddleware(zaptest.NewLogger(t), svc, nil)(next)
+
+			r := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			if tt.auth != nil {
+				r = r.WithContext(icontext.SetAuthorizer(r.Context(), tt.auth))
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHTTPMiddleware_SourceIP(t *testing.T) {
+	proxies, err := audit.ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{
+			name:       "forwarding headers of untrusted peers are ignored",
+			remoteAddr: "198.51.100.7:1234",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.9", "X-Real-IP": "203.0.113.9"},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "trusted proxy forwards the client",
+			remoteAddr: "192.0.2.1:1234",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.9"},
+			want:       "203.0.113.9",
+		},
+		{
+			name:       "addresses forged before the trusted proxies are ignored",
+			remoteAddr: "10.0.0.2:1234",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.1, 198.51.100.7, 10.0.0.3"},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "trusted proxy with x-real-ip",
+			remoteAddr: "10.0.0.2:1234",
+			headers:    map[string]string{"X-Real-IP": "203.0.113.9"},
+			want:       "203.0.113.9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *influxdb.AuditEvent
+			svc := mock.NewAuditService()
+			svc.RecordAuditEventFn = func(ctx context.Context, e *influxdb.AuditEvent) error {
+				got = e
+				return nil
+			}
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			})
+			h := audit.NewHTTPMiddleware(zaptest.NewLogger(t), svc, proxies)(next)
+
+			r := httptest.NewRequest(http.MethodPost, "/api/v2/buckets", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			r = r.WithContext(icontext.SetAuthorizer(r.Context(), &influxdb.Session{ID: 5, UserID: 6}))
+			h.ServeHTTP(httptest.NewRecorder(), r)
+
+			require.NotNil(t, got)
+			assert.Equal(t, tt.want, got.SourceIP)
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package audit
+
+// The audit `Service` stores audit events in a single kv bucket.
+// Keys are the big endian encoded event time followed by the encoded event ID,
+// so a cursor over the bucket walks the events in chronological order and
+// time bounded lookups can seek directly to the start of the interval.
+// Events are only ever appended to the bucket, and deleted once past their
+// retention, see RunRetention.
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/snowflake"
+	"go.uber.org/zap"
+)
+
+var auditBucket = []byte("auditlogv1")
+
+var _ influxdb.AuditService = (*Service)(nil)
+
+// DefaultRetention is how long audit events are kept by default.
+const DefaultRetention = 90 * 24 * time.Hour
+
+// retentionInterval is how often the events past their retention are deleted.
+const retentionInterval = time.Hour
+
+// Service is the kv backed implementation of influxdb.AuditService.
+type Service struct {
+	store     kv.Store
+	IDGen     influxdb.IDGenerator
+	now       func() time.Time
+	retention time.Duration
+}
+
+// Option configures the audit service.
+type Option func(*Service)
+
+// WithRetention sets how long audit events are kept, zero keeps them forever.
+func WithRetention(d time.Duration) Option {
+	return func(s *Service) {
+		s.retention = d
+	}
+}
+
+// NewService returns an audit service backed by the provided kv store.
+func NewService(st kv.Store, opts ...Option) *Service {
+	s := &Service{
+		store:     st,
+		IDGen:     snowflake.NewDefaultIDGenerator(),
+		now:       time.Now,
+		retention: DefaultRetention,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// RecordAuditEvent appends an event to the audit log.
+func (s *Service) RecordAuditEvent(ctx context.Context, e *influxdb.AuditEvent) error {
+	if err := e.Action.Valid(); err != nil {
+		return err
+	}
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		return s.putEvent(tx, e)
+	})
+}
+
+// putEvent appends an event to the audit log in the transaction.
+func (s *Service) putEvent(tx kv.Tx, e *influxdb.AuditEvent) error {
+	if !e.ID.Valid() {
+		e.ID = s.IDGen.ID()
+	}
+	if e.Time.IsZero() {
+		e.Time = s.now()
+	}
+	e.Time = e.Time.UTC()
+
+	key, err := eventKey(e.Time, e.ID)
+	if err != nil {
+		return ErrInvalidEventID(err)
+	}
+
+	v, err := json.Marshal(e)
+	if err != nil {
+		return ErrInternalService(err)
+	}
+
+	b, err := tx.Bucket(auditBucket)
+	if err != nil {
+		return ErrInternalService(err)
+	}
+	if err := b.Put(key, v); err != nil {
+		return ErrInternalService(err)
+	}
+	return nil
+}
+
+// DeleteEventsBefore deletes the events recorded before the time and returns
+// how many were deleted.
+func (s *Service) DeleteEventsBefore(ctx context.Context, before time.Time) (int, error) {
+	var n int
+	err := s.store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(auditBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+
+		// keys start with the event time, the cursor stops at the first
+		// event that is kept.
+		cur, err := b.ForwardCursor(nil)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		end := timePrefix(before.UTC())
+		var keys [][]byte
+		for k, _ := cur.Next(); k != nil && bytes.Compare(k, end) < 0; k, _ = cur.Next() {
+			keys = append(keys, append([]byte{}, k...))
+		}
+		if err := cur.Err(); err != nil {
+			return ErrInternalService(err)
+		}
+		if err := cur.Close(); err != nil {
+			return ErrInternalService(err)
+		}
+
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return ErrInternalService(err)
+			}
+		}
+		n = len(keys)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// RunRetention deletes the events past their retention every hour until the
+// context is canceled. It returns right away when events are kept forever.
+func (s *Service) RunRetention(ctx context.Context, log *zap.Logger) {
+	if s.retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+	for {
+		n, err := s.DeleteEventsBefore(ctx, s.now().Add(-s.retention))
+		if err != nil {
+			log.Error("Failed to delete audit events", zap.Error(err))
+		} else if n > 0 {
+			log.Debug("Deleted audit events", zap.Int("count", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// FindAuditEvents returns the events matching the filter.
+func (s *Service) FindAuditEvents(ctx context.Context, filter influxdb.AuditEventFilter, opts ...influxdb.FindOptions) ([]*influxdb.AuditEvent, error) {
+	opt := influxdb.DefaultAuditEventFindOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	// descending cursors always start from the newest event, events newer
+	// than the filter are skipped while walking.
+	cursorOpts := []kv.CursorOption{}
+	seek := []byte{}
+	if opt.Descending {
+		cursorOpts = append(cursorOpts, kv.WithCursorDirection(kv.CursorDescending))
+	} else if filter.Since != nil {
+		seek = timePrefix(*filter.Since)
+	}
+
+	events := []*influxdb.AuditEvent{}
+	err := s.store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(auditBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+
+		cur, err := b.ForwardCursor(seek, cursorOpts...)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+
+		offset := opt.Offset
+		return kv.WalkCursor(ctx, cur, func(k, v []byte) (bool, error) {
+			e := &influxdb.AuditEvent{}
+			if err := json.Unmarshal(v, e); err != nil {
+				return false, ErrInternalService(err)
+			}
+
+			if outOfRange(filter, e.Time, opt.Descending) {
+				return false, nil
+			}
+			if !filterEvent(filter, e) {
+				return true, nil
+			}
+			if offset > 0 {
+				offset--
+				return true, nil
+			}
+
+			events = append(events, e)
+			return opt.Limit <= 0 || len(events) < opt.Limit, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// outOfRange reports whether the cursor has moved past the time bounds of
+// the filter; once it has, no later key can match.
+func outOfRange(f influxdb.AuditEventFilter, t time.Time, descending bool) bool {
+	if descending {
+		return f.Since != nil && t.Before(*f.Since)
+	}
+	return f.Until != nil && !t.Before(*f.Until)
+}
+
+func filterEvent(f influxdb.AuditEventFilter, e *influxdb.AuditEvent) bool {
+	if f.Since != nil && e.Time.Before(*f.Since) {
+		return false
+	}
+	if f.Until != nil && !e.Time.Before(*f.Until) {
+		return false
+	}
+	if f.OrgID != nil && *f.OrgID != e.OrgID {
+		return false
+	}
+	if f.UserID != nil && *f.UserID != e.UserID {
+		return false
+	}
+	if f.AuthorizationID != nil && *f.AuthorizationID != e.AuthorizationID {
+		return false
+	}
+	if f.ResourceType != nil && *f.ResourceType != e.ResourceType {
+		return false
+	}
+	if f.ResourceID != nil && *f.ResourceID != e.ResourceID {
+		return false
+	}
+	if f.Action != nil && *f.Action != e.Action {
+		return false
+	}
+	return true
+}
+
+func timePrefix(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return key
+}
+
+func eventKey(t time.Time, id influxdb.ID) ([]byte, error) {
+	encID, err := id.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return append(timePrefix(t), encID...), nil
+}
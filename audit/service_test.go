@@ -0,0 +1,164 @@
+package audit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/audit"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kv/migration/all"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestService(t *testing.T) *audit.Service {
+	t.Helper()
+
+	store := inmem.NewKVStore()
+	if err := all.Up(context.Background(), zaptest.NewLogger(t), store); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := audit.NewService(store)
+	svc.IDGen = mock.NewIncrementingIDGenerator(1)
+	return svc
+}
+
+func TestService_FindAuditEvents(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+
+	base := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	orgA, orgB := influxdb.ID(100), influxdb.ID(200)
+	events := []*influxdb.AuditEvent{
+		{Time: base, OrgID: orgA, UserID: 1, ResourceType: influxdb.BucketsResourceType, ResourceID: 10, Action: influxdb.AuditActionCreate},
+		{Time: base.Add(time.Minute), OrgID: orgB, UserID: 2, ResourceType: influxdb.TasksResourceType, ResourceID: 20, Action: influxdb.AuditActionUpdate},
+		{Time: base.Add(2 * time.Minute), OrgID: orgA, UserID: 1, ResourceType: influxdb.BucketsResourceType, ResourceID: 10, Action: influxdb.AuditActionDelete},
+		{Time: base.Add(3 * time.Minute), OrgID: orgA, UserID: 2, AuthorizationID: 7, ResourceType: influxdb.AuthorizationsResourceType, ResourceID: 7, Action: influxdb.AuditActionCreate},
+	}
+	for _, e := range events {
+		require.NoError(t, svc.RecordAuditEvent(ctx, e))
+	}
+
+	ids := func(es []*influxdb.AuditEvent) []influxdb.ID {
+		out := make([]influxdb.ID, 0, len(es))
+		for _, e := range es {
+			out = append(out, e.ResourceID)
+		}
+		return out
+	}
+	idPtr := func(id influxdb.ID) *influxdb.ID { return &id }
+	timePtr := func(t time.Time) *time.Time { return &t }
+	rt := influxdb.BucketsResourceType
+	create := influxdb.AuditActionCreate
+
+	tests := []struct {
+		name   string
+		filter influxdb.AuditEventFilter
+		opts   []influxdb.FindOptions
+		want   []influxdb.ID
+	}{
+		{
+			name: "newest first by default",
+			want: []influxdb.ID{7, 10, 20, 10},
+		},
+		{
+			name: "ascending",
+			opts: []influxdb.FindOptions{{Limit: 10}},
+			want: []influxdb.ID{10, 20, 10, 7},
+		},
+		{
+			name:   "by org",
+			filter: influxdb.AuditEventFilter{OrgID: &orgB},
+			want:   []influxdb.ID{20},
+		},
+		{
+			name:   "by user and resource type",
+			filter: influxdb.AuditEventFilter{UserID: idPtr(1), ResourceType: &rt},
+			want:   []influxdb.ID{10, 10},
+		},
+		{
+			name:   "by token",
+			filter: influxdb.AuditEventFilter{AuthorizationID: idPtr(7)},
+			want:   []influxdb.ID{7},
+		},
+		{
+			name:   "by action",
+			filter: influxdb.AuditEventFilter{Action: &create},
+			want:   []influxdb.ID{7, 10},
+		},
+		{
+			name: "time range descending",
+			filter: influxdb.AuditEventFilter{
+				Since: timePtr(base.Add(time.Minute)),
+				Until: timePtr(base.Add(3 * time.Minute)),
+			},
+			want: []influxdb.ID{10, 20},
+		},
+		{
+			name: "time range ascending",
+			filter: influxdb.AuditEventFilter{
+				Since: timePtr(base.Add(time.Minute)),
+				Until: timePtr(base.Add(3 * time.Minute)),
+			},
+			opts: []influxdb.FindOptions{{Limit: 10}},
+			want: []influxdb.ID{20, 10},
+		},
+		{
+			name: "limit and offset",
+			opts: []influxdb.FindOptions{{Limit: 2, Offset: 1, Descending: true}},
+			want: []influxdb.ID{10, 20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.FindAuditEvents(ctx, tt.filter, tt.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ids(got))
+		})
+	}
+}
+
+func TestService_RecordAuditEvent(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+
+	err := svc.RecordAuditEvent(ctx, &influxdb.AuditEvent{Action: "read"})
+	require.Error(t, err)
+	assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+
+	e := &influxdb.AuditEvent{Action: influxdb.AuditActionUpdate, ResourceType: influxdb.ChecksResourceType}
+	require.NoError(t, svc.RecordAuditEvent(ctx, e))
+	assert.True(t, e.ID.Valid())
+	assert.False(t, e.Time.IsZero())
+}
+
+func TestService_DeleteEventsBefore(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+
+	base := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, svc.RecordAuditEvent(ctx, &influxdb.AuditEvent{
+			Time:         base.Add(time.Duration(i) * time.Hour),
+			ResourceType: influxdb.BucketsResourceType,
+			ResourceID:   influxdb.ID(10 + i),
+			Action:       influxdb.AuditActionCreate,
+		}))
+	}
+
+	n, err := svc.DeleteEventsBefore(ctx, base.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	es, err := svc.FindAuditEvents(ctx, influxdb.AuditEventFilter{}, influxdb.FindOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, es, 2)
+	assert.Equal(t, influxdb.ID(11), es[0].ResourceID)
+	assert.Equal(t, influxdb.ID(12), es[1].ResourceID)
+}
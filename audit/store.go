@@ -0,0 +1,380 @@
+package audit
+
+// The audit `Store` wraps the kv store of the services that own resources. The
+// values that a transaction puts into or deletes from the buckets of resources
+// are compared to their previous values in the same transaction, and an event
+// naming the changed fields is appended to the audit log when the transaction
+// commits, in the transaction itself. Every change of a resource is recorded
+// no matter which API or service made it, as long as it was made on behalf of
+// an authorizer.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+// resource describes how the values of a kv bucket are recorded.
+type resource struct {
+	typ influxdb.ResourceType
+	// raw buckets do not hold JSON objects, their values are recorded as the
+	// change of a single field and always redacted. Their keys start with the
+	// encoded ID of the resource, followed by the name of the field unless
+	// field names it.
+	raw   bool
+	field string
+	// idField and typeField name the fields holding the ID and the type of
+	// the resource when it is not the "id" field or the type of the bucket.
+	idField   string
+	typeField string
+}
+
+// resources are the kv buckets holding resources, by name.
+var resources = map[string]resource{
+	"authorizationsv1":       {typ: influxdb.AuthorizationsResourceType},
+	"bucketsv1":              {typ: influxdb.BucketsResourceType},
+	"checksv1":               {typ: influxdb.ChecksResourceType},
+	"dashboardsv2":           {typ: influxdb.DashboardsResourceType},
+	"dbrpv1":                 {typ: influxdb.DBRPResourceType},
+	"labelsv1":               {typ: influxdb.LabelsResourceType},
+	"messagetemplatesv1":     {typ: influxdb.ResourceType("messageTemplates")},
+	"notificationEndpointv1": {typ: influxdb.NotificationEndpointResourceType},
+	"notificationRulev1":     {typ: influxdb.NotificationRuleResourceType},
+	"organizationsv1":        {typ: influxdb.OrgsResourceType},
+	"orgquotasv1":            {typ: influxdb.ResourceType("quotas"), idField: "orgID"},
+	"scraperv2":              {typ: influxdb.ScraperResourceType},
+	"secretsv1":              {typ: influxdb.SecretsResourceType, raw: true},
+	"silencesv1":             {typ: influxdb.ResourceType("silences")},
+	"sourcesv1":              {typ: influxdb.SourcesResourceType},
+	"tasksv1":                {typ: influxdb.TasksResourceType},
+	"telegrafv1":             {typ: influxdb.TelegrafsResourceType},
+	"userresourcemappingsv1": {idField: "resourceID", typeField: "resourceType"},
+	"usersv1":                {typ: influxdb.UsersResourceType},
+	"userspasswordv1":        {typ: influxdb.UsersResourceType, raw: true, field: "password"},
+	"v1_pkger_stacks":        {typ: influxdb.ResourceType("stacks")},
+	"variablesv1":            {typ: influxdb.VariablesResourceType},
+}
+
+// ignoredFields are derived from other fields or updated by the system on
+// its own, like the run state of tasks, their changes are never recorded.
+var ignoredFields = map[string]bool{
+	"links":           true,
+	"latestCompleted": true,
+	"latestScheduled": true,
+	"latestSuccess":   true,
+	"latestFailure":   true,
+	"lastRunStatus":   true,
+	"lastRunError":    true,
+}
+
+// Store records the changes of resources made by its update transactions in
+// the audit log of the service. The service must not use the Store itself.
+type Store struct {
+	kv.SchemaStore
+	svc *Service
+}
+
+// NewStore wraps a kv store to record the changes of resources.
+func NewStore(st kv.SchemaStore, svc *Service) *Store {
+	return &Store{
+		SchemaStore: st,
+		svc:         svc,
+	}
+}
+
+// Update opens a transaction that records the changes it makes to resources
+// once fn succeeds. Transactions without an authorizer on their context are
+// not recorded.
+func (s *Store) Update(ctx context.Context, fn func(kv.Tx) error) error {
+	auth, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return s.SchemaStore.Update(ctx, fn)
+	}
+
+	return s.SchemaStore.Update(ctx, func(tx kv.Tx) error {
+		atx := &auditTx{Tx: tx, changes: map[string]*change{}}
+		if err := fn(atx); err != nil {
+			return err
+		}
+		for _, c := range atx.order {
+			e := c.event(auth)
+			if e == nil {
+				continue
+			}
+			if req, ok := requestFromContext(ctx); ok {
+				e.Method, e.Path, e.SourceIP = req.method, req.path, req.sourceIP
+			}
+			if err := s.svc.putEvent(tx, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// auditTx tracks the values that the transaction puts into or deletes from
+// the buckets of resources.
+type auditTx struct {
+	kv.Tx
+	changes map[string]*change
+	order   []*change
+}
+
+func (tx *auditTx) Bucket(name []byte) (kv.Bucket, error) {
+	b, err := tx.Tx.Bucket(name)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := resources[string(name)]
+	if !ok {
+		return b, nil
+	}
+	return &resourceBucket{Bucket: b, tx: tx, name: string(name), resource: r}, nil
+}
+
+type resourceBucket struct {
+	kv.Bucket
+	tx       *auditTx
+	name     string
+	resource resource
+}
+
+func (b *resourceBucket) Put(key, value []byte) error {
+	c, err := b.track(key)
+	if err != nil {
+		return err
+	}
+	if err := b.Bucket.Put(key, value); err != nil {
+		return err
+	}
+	c.after = append([]byte{}, value...)
+	return nil
+}
+
+func (b *resourceBucket) Delete(key []byte) error {
+	c, err := b.track(key)
+	if err != nil {
+		return err
+	}
+	if err := b.Bucket.Delete(key); err != nil {
+		return err
+	}
+	c.after = nil
+	return nil
+}
+
+// track returns the change of the key, reading its value before the
+// transaction changes it for the first time.
+func (b *resourceBucket) track(key []byte) (*change, error) {
+	id := b.name + "/" + string(key)
+	if c, ok := b.tx.changes[id]; ok {
+		return c, nil
+	}
+
+	before, err := b.Bucket.Get(key)
+	if kv.IsNotFound(err) {
+		before = nil
+	} else if err != nil {
+		return nil, err
+	}
+	c := &change{
+		resource: b.resource,
+		key:      append([]byte{}, key...),
+		before:   append([]byte(nil), before...),
+		after:    append([]byte(nil), before...),
+	}
+	b.tx.changes[id] = c
+	b.tx.order = append(b.tx.order, c)
+	return c, nil
+}
+
+// change is the change of a single key of the bucket of a resource.
+type change struct {
+	resource resource
+	key      []byte
+	before   []byte
+	after    []byte
+}
+
+// event returns the event recording the change, or nil when no recorded
+// field changed.
+func (c *change) event(auth influxdb.Authorizer) *influxdb.AuditEvent {
+	if bytes.Equal(c.before, c.after) || (c.resource.raw && len(c.key) < influxdb.IDLength) {
+		return nil
+	}
+
+	e := &influxdb.AuditEvent{
+		UserID:       auth.GetUserID(),
+		ResourceType: c.resource.typ,
+	}
+	switch {
+	case c.before == nil:
+		e.Action = influxdb.AuditActionCreate
+	case c.after == nil:
+		e.Action = influxdb.AuditActionDelete
+	default:
+		e.Action = influxdb.AuditActionUpdate
+	}
+
+	before, after := c.object(c.before), c.object(c.after)
+	state := after
+	if state == nil {
+		state = before
+	}
+
+	if c.resource.raw {
+		_ = e.ResourceID.Decode(c.key[:influxdb.IDLength])
+	} else {
+		idField := "id"
+		if c.resource.idField != "" {
+			idField = c.resource.idField
+		}
+		if id, ok := decodeID(state[idField]); ok {
+			e.ResourceID = id
+		} else if len(c.key) == influxdb.IDLength {
+			_ = e.ResourceID.Decode(c.key)
+		}
+		if c.resource.typeField != "" {
+			_ = json.Unmarshal(state[c.resource.typeField], &e.ResourceType)
+		}
+	}
+
+	if orgID, ok := decodeID(state["orgID"]); ok {
+		e.OrgID = orgID
+	} else if e.ResourceType == influxdb.OrgsResourceType || e.ResourceType == influxdb.SecretsResourceType {
+		e.OrgID = e.ResourceID
+	} else if a, ok := auth.(*influxdb.Authorization); ok {
+		e.OrgID = a.OrgID
+	}
+	if a, ok := auth.(*influxdb.Authorization); ok {
+		e.AuthorizationID = a.ID
+	}
+
+	e.Changes = diff(before, after, c.resource.raw)
+	if len(e.Changes) == 0 {
+		return nil
+	}
+	// updates that only touch the time of the update are made by the system.
+	if e.Action == influxdb.AuditActionUpdate && len(e.Changes) == 1 && e.Changes[0].Field == "updatedAt" {
+		return nil
+	}
+
+	changed := make([]string, 0, len(e.Changes))
+	for _, ch := range e.Changes {
+		changed = append(changed, ch.Field)
+	}
+	e.Summary = "changed: " + strings.Join(changed, ", ")
+	return e
+}
+
+// object decodes the fields of a value of the bucket of the resource.
+func (c *change) object(v []byte) map[string]json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	if !c.resource.raw {
+		return decodeObject(v)
+	}
+	field := c.resource.field
+	if field == "" {
+		field = string(c.key[influxdb.IDLength:])
+	}
+	// the value is only compared, it is redacted by diff.
+	value, err := json.Marshal(string(v))
+	if err != nil {
+		return nil
+	}
+	return map[string]json.RawMessage{field: value}
+}
+
+// diff returns the changes of the top level fields of a resource, values of
+// fields that may hold credentials are redacted, all of them with redactAll.
+func diff(before, after map[string]json.RawMessage, redactAll bool) []influxdb.AuditChange {
+	keys := make([]string, 0, len(before)+len(after))
+	for k := range before {
+		keys = append(keys, k)
+	}
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []influxdb.AuditChange
+	for _, k := range keys {
+		if ignoredFields[k] {
+			continue
+		}
+		b, a := before[k], after[k]
+		if bytes.Equal(b, a) {
+			continue
+		}
+		if redactAll || isRedacted(k) {
+			if b != nil {
+				b = redacted
+			}
+			if a != nil {
+				a = redacted
+			}
+		}
+		changes = append(changes, influxdb.AuditChange{Field: k, Before: b, After: a})
+	}
+	return changes
+}
+
+// decodeObject decodes the fields of a JSON object, compacting their values.
+func decodeObject(body []byte) map[string]json.RawMessage {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil
+	}
+	for k, v := range fields {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, v); err == nil {
+			fields[k] = buf.Bytes()
+		}
+	}
+	return fields
+}
+
+// decodeID decodes an ID stored as a string, or as the bytes of its
+// encoding.
+func decodeID(v json.RawMessage) (influxdb.ID, bool) {
+	if v == nil {
+		return 0, false
+	}
+	var id influxdb.ID
+	if err := json.Unmarshal(v, &id); err == nil && id.Valid() {
+		return id, true
+	}
+	var b []byte
+	if err := json.Unmarshal(v, &b); err == nil && id.Decode(b) == nil && id.Valid() {
+		return id, true
+	}
+	return 0, false
+}
+
+type requestCtxKey struct{}
+
+// request is the API request that changes resources.
+type request struct {
+	method   string
+	path     string
+	sourceIP string
+}
+
+func withRequest(ctx context.Context, r request) context.Context {
+	return context.WithValue(ctx, requestCtxKey{}, r)
+}
+
+func requestFromContext(ctx context.Context) (request, bool) {
+	r, ok := ctx.Value(requestCtxKey{}).(request)
+	return r, ok
+}
@@ -0,0 +1,105 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/audit"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kv/migration/all"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store := inmem.NewKVStore()
+	require.NoError(t, all.Up(ctx, zaptest.NewLogger(t), store))
+	svc := audit.NewService(store)
+	svc.IDGen = mock.NewIncrementingIDGenerator(1)
+	ts := tenant.NewService(tenant.NewStore(audit.NewStore(store, svc)))
+
+	events := func(rt influxdb.ResourceType) []*influxdb.AuditEvent {
+		t.Helper()
+		es, err := svc.FindAuditEvents(ctx, influxdb.AuditEventFilter{ResourceType: &rt}, influxdb.FindOptions{Limit: 100})
+		require.NoError(t, err)
+		return es
+	}
+
+	// changes made without an authorizer are not recorded.
+	org := &influxdb.Organization{Name: "org"}
+	require.NoError(t, ts.CreateOrganization(ctx, org))
+	assert.Empty(t, events(influxdb.OrgsResourceType))
+
+	auth := &influxdb.Authorization{ID: 1, OrgID: org.ID, UserID: 3}
+	authCtx := icontext.SetAuthorizer(ctx, auth)
+
+	// changes made by API requests are recorded with the request.
+	h := audit.NewHTTPMiddleware(zaptest.NewLogger(t), svc, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := ts.CreateBucket(r.Context(), &influxdb.Bucket{OrgID: org.ID, Name: "b1"})
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	r := httptest.NewRequest(http.MethodPost, "/api/v2/buckets", nil).WithContext(authCtx)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	es := events(influxdb.BucketsResourceType)
+	require.Len(t, es, 1)
+	created := es[0]
+	assert.Equal(t, influxdb.AuditActionCreate, created.Action)
+	assert.Equal(t, org.ID, created.OrgID)
+	assert.Equal(t, influxdb.ID(3), created.UserID)
+	assert.Equal(t, influxdb.ID(1), created.AuthorizationID)
+	assert.Equal(t, http.MethodPost, created.Method)
+	assert.Equal(t, "/api/v2/buckets", created.Path)
+	assert.Equal(t, "192.0.2.1", created.SourceIP)
+	assert.Zero(t, created.StatusCode)
+	assert.Contains(t, created.Changes, influxdb.AuditChange{Field: "name", After: json.RawMessage(`"b1"`)})
+	bucketID := created.ResourceID
+	require.True(t, bucketID.Valid())
+
+	// changes made by services outside of an API request, like template
+	// applies, are recorded as well.
+	name := "b2"
+	_, err := ts.UpdateBucket(authCtx, bucketID, influxdb.BucketUpdate{Name: &name})
+	require.NoError(t, err)
+	es = events(influxdb.BucketsResourceType)
+	require.Len(t, es, 2)
+	updated := es[1]
+	assert.Equal(t, influxdb.AuditActionUpdate, updated.Action)
+	assert.Equal(t, bucketID, updated.ResourceID)
+	assert.Empty(t, updated.Method)
+	assert.Contains(t, updated.Changes, influxdb.AuditChange{Field: "name", Before: json.RawMessage(`"b1"`), After: json.RawMessage(`"b2"`)})
+
+	// failed changes are not recorded.
+	err = ts.CreateBucket(authCtx, &influxdb.Bucket{OrgID: org.ID, Name: "b2"})
+	require.Error(t, err)
+	require.Len(t, events(influxdb.BucketsResourceType), 2)
+
+	require.NoError(t, ts.DeleteBucket(authCtx, bucketID))
+	es = events(influxdb.BucketsResourceType)
+	require.Len(t, es, 3)
+	assert.Equal(t, influxdb.AuditActionDelete, es[2].Action)
+	assert.Equal(t, bucketID, es[2].ResourceID)
+	assert.Contains(t, es[2].Changes, influxdb.AuditChange{Field: "name", Before: json.RawMessage(`"b2"`)})
+
+	// credentials are redacted.
+	user := &influxdb.User{Name: "user"}
+	require.NoError(t, ts.CreateUser(authCtx, user))
+	require.NoError(t, ts.SetPassword(authCtx, user.ID, "password1"))
+	require.NoError(t, ts.SetPassword(authCtx, user.ID, "password2"))
+	es = events(influxdb.UsersResourceType)
+	require.Len(t, es, 3)
+	assert.Equal(t, []influxdb.AuditChange{{Field: "password", After: json.RawMessage(`"[redacted]"`)}}, es[1].Changes)
+	assert.Equal(t, []influxdb.AuditChange{{Field: "password", Before: json.RawMessage(`"[redacted]"`), After: json.RawMessage(`"[redacted]"`)}}, es[2].Changes)
+	assert.Equal(t, user.ID, es[2].ResourceID)
+	assert.Equal(t, influxdb.AuditActionUpdate, es[2].Action)
+}
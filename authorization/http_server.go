@@ -12,6 +12,7 @@ import (
 	"github.com/influxdata/influxdb/v2"
 	icontext "github.com/influxdata/influxdb/v2/context"
 	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/predicate"
 	"go.uber.org/zap"
 )
 
@@ -262,6 +263,15 @@ func (p *postAuthorizationRequest) Validate() error {
 				Err: err,
 			}
 		}
+		if perm.Restriction != nil {
+			if _, err := predicate.NewRestriction(*perm.Restriction); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "invalid permission restriction",
+					Err:  err,
+				}
+			}
+		}
 	}
 
 	if !p.OrgID.Valid() {
@@ -119,6 +119,27 @@ func AuthorizeWrite(ctx context.Context, rt influxdb.ResourceType, rid, oid infl
 	return authorize(ctx, influxdb.WriteAction, rt, &rid, &oid)
 }
 
+// AuthorizeWriteBucket authorizes the user in the context to modify the bucket itself.
+// Unlike AuthorizeWrite, write permissions restricted to a subset of the data
+// in the bucket are not sufficient.
+func AuthorizeWriteBucket(ctx context.Context, bid, oid influxdb.ID) (influxdb.Authorizer, influxdb.Permission, error) {
+	auth, p, err := AuthorizeWrite(ctx, influxdb.BucketsResourceType, bid, oid)
+	if err != nil {
+		return auth, p, err
+	}
+	pset, err := auth.PermissionSet()
+	if err != nil {
+		return nil, influxdb.Permission{}, err
+	}
+	if rs, _ := pset.Restrictions(p); rs != nil {
+		return nil, influxdb.Permission{}, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  fmt.Sprintf("%s is restricted", p),
+		}
+	}
+	return auth, p, nil
+}
+
 // AuthorizeRead authorizes the user in the context to read the specified resource (identified by its type, ID).
 // NOTE: authorization will pass only if the user has a specific permission for the given resource.
 func AuthorizeReadResource(ctx context.Context, rt influxdb.ResourceType, rid influxdb.ID) (influxdb.Authorizer, influxdb.Permission, error) {
@@ -98,7 +98,7 @@ func (s *BucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd in
 	if err != nil {
 		return nil, err
 	}
-	if _, _, err := AuthorizeWrite(ctx, influxdb.BucketsResourceType, id, b.OrgID); err != nil {
+	if _, _, err := AuthorizeWriteBucket(ctx, id, b.OrgID); err != nil {
 		return nil, err
 	}
 	return s.s.UpdateBucket(ctx, id, upd)
@@ -110,7 +110,7 @@ func (s *BucketService) DeleteBucket(ctx context.Context, id influxdb.ID) error
 	if err != nil {
 		return err
 	}
-	if _, _, err := AuthorizeWrite(ctx, influxdb.BucketsResourceType, id, b.OrgID); err != nil {
+	if _, _, err := AuthorizeWriteBucket(ctx, id, b.OrgID); err != nil {
 		return err
 	}
 	return s.s.DeleteBucket(ctx, id)
@@ -0,0 +1,53 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.NotificationTestService = (*NotificationTestService)(nil)
+
+// NotificationTestService wraps a influxdb.NotificationTestService and authorizes actions
+// against it appropriately.
+type NotificationTestService struct {
+	s         influxdb.NotificationTestService
+	rules     influxdb.NotificationRuleStore
+	endpoints influxdb.NotificationEndpointService
+}
+
+// NewNotificationTestService constructs an instance of an authorizing
+// notification test service. The rule and endpoint services are used to look
+// up the organization of rules and endpoints, they must not be authorized
+// themselves.
+func NewNotificationTestService(s influxdb.NotificationTestService, rules influxdb.NotificationRuleStore, endpoints influxdb.NotificationEndpointService) *NotificationTestService {
+	return &NotificationTestService{
+		s:         s,
+		rules:     rules,
+		endpoints: endpoints,
+	}
+}
+
+// TestNotificationEndpoint checks to see if the authorizer on context has write access to the notification endpoint provided.
+func (s *NotificationTestService) TestNotificationEndpoint(ctx context.Context, id influxdb.ID) (*influxdb.NotificationTestResult, error) {
+	edp, err := s.endpoints.FindNotificationEndpointByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeWrite(ctx, influxdb.NotificationEndpointResourceType, edp.GetID(), edp.GetOrgID()); err != nil {
+		return nil, err
+	}
+	return s.s.TestNotificationEndpoint(ctx, id)
+}
+
+// TestNotificationRule checks to see if the authorizer on context has write access to the notification rule provided.
+func (s *NotificationTestService) TestNotificationRule(ctx context.Context, id influxdb.ID) (*influxdb.NotificationTestResult, error) {
+	nr, err := s.rules.FindNotificationRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeWrite(ctx, influxdb.NotificationRuleResourceType, nr.GetID(), nr.GetOrgID()); err != nil {
+		return nil, err
+	}
+	return s.s.TestNotificationRule(ctx, id)
+}
@@ -100,6 +100,9 @@ func (ts *taskServiceValidator) CreateTask(ctx context.Context, t influxdb.TaskC
 	if err := ts.processPermissionError(a, p, err, loggerFields...); err != nil {
 		return nil, err
 	}
+	if err := ts.authorizeUpstream(ctx, t.OrganizationID, t.DependsOn, loggerFields...); err != nil {
+		return nil, err
+	}
 	return ts.TaskService.CreateTask(ctx, t)
 }
 
@@ -118,9 +121,25 @@ func (ts *taskServiceValidator) UpdateTask(ctx context.Context, id influxdb.ID,
 	if err := ts.processPermissionError(a, p, err, loggerFields...); err != nil {
 		return nil, err
 	}
+	if upd.DependsOn != nil {
+		if err := ts.authorizeUpstream(ctx, task.OrganizationID, *upd.DependsOn, loggerFields...); err != nil {
+			return nil, err
+		}
+	}
 	return ts.TaskService.UpdateTask(ctx, id, upd)
 }
 
+// authorizeUpstream checks the upstream tasks a task depends on can be read.
+func (ts *taskServiceValidator) authorizeUpstream(ctx context.Context, orgID influxdb.ID, upstream []influxdb.ID, loggerFields ...zap.Field) error {
+	for _, id := range upstream {
+		a, p, err := AuthorizeRead(ctx, influxdb.TasksResourceType, id, orgID)
+		if err := ts.processPermissionError(a, p, err, loggerFields...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (ts *taskServiceValidator) DeleteTask(ctx context.Context, id influxdb.ID) error {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
@@ -0,0 +1,68 @@
+package authorizer
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.TaskAnalyticsService = (*TaskAnalyticsService)(nil)
+
+// TaskAnalyticsService wraps a influxdb.TaskAnalyticsService and authorizes actions
+// against it appropriately.
+type TaskAnalyticsService struct {
+	s influxdb.TaskAnalyticsService
+}
+
+// NewTaskAnalyticsService constructs an instance of an authorizing task analytics service.
+func NewTaskAnalyticsService(s influxdb.TaskAnalyticsService) *TaskAnalyticsService {
+	return &TaskAnalyticsService{
+		s: s,
+	}
+}
+
+// FindTaskAnalytics returns the analytics of the tasks the authorizer may read.
+func (s *TaskAnalyticsService) FindTaskAnalytics(ctx context.Context, filter influxdb.TaskAnalyticsFilter) ([]*influxdb.TaskAnalytics, error) {
+	if filter.TaskID != nil {
+		if _, _, err := AuthorizeRead(ctx, influxdb.TasksResourceType, *filter.TaskID, filter.OrganizationID); err != nil {
+			return nil, err
+		}
+	}
+
+	as, err := s.s.FindTaskAnalytics(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	rs := as[:0]
+	for _, a := range as {
+		_, _, err := AuthorizeRead(ctx, influxdb.TasksResourceType, a.TaskID, filter.OrganizationID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		rs = append(rs, a)
+	}
+	return rs, nil
+}
+
+// FindTaskRunRetention checks that the authorizer may read the organization.
+func (s *TaskAnalyticsService) FindTaskRunRetention(ctx context.Context, orgID influxdb.ID) (*influxdb.TaskRunRetention, error) {
+	if _, _, err := AuthorizeReadOrg(ctx, orgID); err != nil {
+		return nil, err
+	}
+	return s.s.FindTaskRunRetention(ctx, orgID)
+}
+
+// UpdateTaskRunRetention checks that the authorizer may write the organization.
+func (s *TaskAnalyticsService) UpdateTaskRunRetention(ctx context.Context, orgID influxdb.ID, retention time.Duration) (*influxdb.TaskRunRetention, error) {
+	if _, _, err := AuthorizeWriteOrg(ctx, orgID); err != nil {
+		return nil, err
+	}
+	return s.s.UpdateTaskRunRetention(ctx, orgID, retention)
+}
@@ -0,0 +1,39 @@
+package authorizer
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.TaskDryRunService = (*TaskDryRunService)(nil)
+
+// TaskDryRunService wraps a influxdb.TaskDryRunService and authorizes actions
+// against it appropriately.
+type TaskDryRunService struct {
+	s     influxdb.TaskDryRunService
+	tasks influxdb.TaskService
+}
+
+// NewTaskDryRunService constructs an instance of an authorizing task dry run
+// service. The task service is used to look up the organization of tasks, it
+// must not be authorized itself.
+func NewTaskDryRunService(s influxdb.TaskDryRunService, tasks influxdb.TaskService) *TaskDryRunService {
+	return &TaskDryRunService{
+		s:     s,
+		tasks: tasks,
+	}
+}
+
+// DryRunTask checks that the authorizer may write the task, as may those running it.
+func (s *TaskDryRunService) DryRunTask(ctx context.Context, taskID influxdb.ID, now time.Time) (*influxdb.TaskDryRun, error) {
+	t, err := s.tasks.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeWrite(ctx, influxdb.TasksResourceType, t.ID, t.OrganizationID); err != nil {
+		return nil, err
+	}
+	return s.s.DryRunTask(ctx, taskID, now)
+}
@@ -0,0 +1,63 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.TaskRevisionService = (*TaskRevisionService)(nil)
+
+// TaskRevisionService wraps a influxdb.TaskRevisionService and authorizes
+// actions against it appropriately.
+type TaskRevisionService struct {
+	s     influxdb.TaskRevisionService
+	tasks influxdb.TaskService
+}
+
+// NewTaskRevisionService constructs an instance of an authorizing task
+// revision service. The task service is used to look up the organization of
+// tasks, it must not be authorized itself.
+func NewTaskRevisionService(s influxdb.TaskRevisionService, tasks influxdb.TaskService) *TaskRevisionService {
+	return &TaskRevisionService{
+		s:     s,
+		tasks: tasks,
+	}
+}
+
+// FindTaskRevisions checks that the authorizer may read the task.
+func (s *TaskRevisionService) FindTaskRevisions(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskRevision, error) {
+	if err := s.authorizeRead(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return s.s.FindTaskRevisions(ctx, taskID)
+}
+
+// FindTaskRevision checks that the authorizer may read the task.
+func (s *TaskRevisionService) FindTaskRevision(ctx context.Context, taskID influxdb.ID, revision int) (*influxdb.TaskRevision, error) {
+	if err := s.authorizeRead(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return s.s.FindTaskRevision(ctx, taskID, revision)
+}
+
+// RollbackTask checks that the authorizer may write the task.
+func (s *TaskRevisionService) RollbackTask(ctx context.Context, taskID influxdb.ID, revision int) (*influxdb.Task, error) {
+	t, err := s.tasks.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeWrite(ctx, influxdb.TasksResourceType, t.ID, t.OrganizationID); err != nil {
+		return nil, err
+	}
+	return s.s.RollbackTask(ctx, taskID, revision)
+}
+
+func (s *TaskRevisionService) authorizeRead(ctx context.Context, taskID influxdb.ID) error {
+	t, err := s.tasks.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	_, _, err = AuthorizeRead(ctx, influxdb.TasksResourceType, t.ID, t.OrganizationID)
+	return err
+}
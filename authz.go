@@ -213,10 +213,60 @@ func (ps PermissionSet) Allowed(p Permission) bool {
 	return PermissionAllowed(p, ps)
 }
 
+// Restrictions returns the restrictions of the permissions in the set that
+// allow p. It returns false when no permission in the set allows p, and a nil
+// slice when at least one unrestricted permission allows p.
+func (ps PermissionSet) Restrictions(p Permission) ([]PermissionRestriction, bool) {
+	var rs []PermissionRestriction
+	for _, perm := range ps {
+		if !perm.Matches(p) {
+			continue
+		}
+		if perm.Restriction == nil {
+			return nil, true
+		}
+		rs = append(rs, *perm.Restriction)
+	}
+	return rs, len(rs) > 0
+}
+
 // Permission defines an action and a resource.
 type Permission struct {
 	Action   Action   `json:"action"`
 	Resource Resource `json:"resource"`
+
+	// Restriction limits a bucket permission to a subset of the data in the bucket.
+	Restriction *PermissionRestriction `json:"restriction,omitempty"`
+}
+
+// PermissionRestriction narrows a read or write permission on a single bucket
+// down to the series that belong to one of the measurements and match the
+// predicate. The predicate uses the syntax of the delete predicate, for
+// example `customer="acme" AND region!="eu"`.
+type PermissionRestriction struct {
+	// Measurements the permission is restricted to, empty means any measurement.
+	Measurements []string `json:"measurements,omitempty"`
+	// Predicate that the tags of a series must match, empty means any series.
+	Predicate string `json:"predicate,omitempty"`
+}
+
+// Valid checks that the restriction restricts something.
+func (r *PermissionRestriction) Valid() error {
+	if len(r.Measurements) == 0 && r.Predicate == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "permission restriction requires measurements or a predicate",
+		}
+	}
+	for _, m := range r.Measurements {
+		if m == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "permission restriction measurements must not be empty",
+			}
+		}
+	}
+	return nil
 }
 
 var newMatchBehavior bool
@@ -361,6 +411,18 @@ func (p *Permission) Valid() error {
 		}
 	}
 
+	if p.Restriction != nil {
+		if p.Resource.Type != BucketsResourceType || p.Resource.ID == nil {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "permission restrictions are only supported on a single bucket",
+			}
+		}
+		if err := p.Restriction.Valid(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
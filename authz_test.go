@@ -1,6 +1,7 @@
 package influxdb_test
 
 import (
+	"reflect"
 	"testing"
 
 	platform "github.com/influxdata/influxdb/v2"
@@ -243,8 +244,9 @@ func TestAuthorizer_PermissionAllowed(t *testing.T) {
 
 func TestPermission_Valid(t *testing.T) {
 	type fields struct {
-		Action   platform.Action
-		Resource platform.Resource
+		Action      platform.Action
+		Resource    platform.Resource
+		Restriction *platform.PermissionRestriction
 	}
 	tests := []struct {
 		name    string
@@ -302,12 +304,70 @@ func TestPermission_Valid(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid restricted bucket permission",
+			fields: fields{
+				Action: platform.ReadAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					ID:    validID(),
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Restriction: &platform.PermissionRestriction{
+					Measurements: []string{"cpu"},
+					Predicate:    `customer="acme"`,
+				},
+			},
+		},
+		{
+			name: "invalid restricted permission on all buckets",
+			fields: fields{
+				Action: platform.ReadAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Restriction: &platform.PermissionRestriction{
+					Predicate: `customer="acme"`,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid restricted permission on another resource",
+			fields: fields{
+				Action: platform.ReadAction,
+				Resource: platform.Resource{
+					Type:  platform.DashboardsResourceType,
+					ID:    validID(),
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Restriction: &platform.PermissionRestriction{
+					Predicate: `customer="acme"`,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid empty restriction",
+			fields: fields{
+				Action: platform.ReadAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					ID:    validID(),
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Restriction: &platform.PermissionRestriction{},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &platform.Permission{
-				Action:   tt.fields.Action,
-				Resource: tt.fields.Resource,
+				Action:      tt.fields.Action,
+				Resource:    tt.fields.Resource,
+				Restriction: tt.fields.Restriction,
 			}
 			if err := p.Valid(); (err != nil) != tt.wantErr {
 				t.Errorf("Permission.Valid() error = %v, wantErr %v", err, tt.wantErr)
@@ -316,6 +376,66 @@ func TestPermission_Valid(t *testing.T) {
 	}
 }
 
+func TestPermissionSet_Restrictions(t *testing.T) {
+	bucketPerm := func(id platform.ID, r *platform.PermissionRestriction) platform.Permission {
+		return platform.Permission{
+			Action: platform.ReadAction,
+			Resource: platform.Resource{
+				Type:  platform.BucketsResourceType,
+				ID:    &id,
+				OrgID: influxdbtesting.IDPtr(1),
+			},
+			Restriction: r,
+		}
+	}
+	acme := &platform.PermissionRestriction{Predicate: `customer="acme"`}
+	globex := &platform.PermissionRestriction{Predicate: `customer="globex"`}
+
+	tests := []struct {
+		name   string
+		ps     platform.PermissionSet
+		perm   platform.Permission
+		want   []platform.PermissionRestriction
+		wantOK bool
+	}{
+		{
+			name:   "unrestricted permission",
+			ps:     platform.PermissionSet{bucketPerm(1, nil)},
+			perm:   bucketPerm(1, nil),
+			wantOK: true,
+		},
+		{
+			name:   "restricted permissions are combined",
+			ps:     platform.PermissionSet{bucketPerm(1, acme), bucketPerm(1, globex), bucketPerm(2, nil)},
+			perm:   bucketPerm(1, nil),
+			want:   []platform.PermissionRestriction{*acme, *globex},
+			wantOK: true,
+		},
+		{
+			name:   "unrestricted permission wins",
+			ps:     platform.PermissionSet{bucketPerm(1, acme), bucketPerm(1, nil)},
+			perm:   bucketPerm(1, nil),
+			wantOK: true,
+		},
+		{
+			name: "no matching permission",
+			ps:   platform.PermissionSet{bucketPerm(2, acme)},
+			perm: bucketPerm(1, nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.ps.Restrictions(tt.perm)
+			if ok != tt.wantOK {
+				t.Fatalf("PermissionSet.Restrictions() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PermissionSet.Restrictions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestPermissionAllResources_Valid(t *testing.T) {
 	var resources = []platform.ResourceType{
 		platform.UsersResourceType,
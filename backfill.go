@@ -0,0 +1,58 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// Backfill statuses.
+const (
+	BackfillStatusRunning   = "running"
+	BackfillStatusCompleted = "completed"
+	BackfillStatusCanceled  = "canceled"
+	BackfillStatusFailed    = "failed"
+)
+
+// Backfill runs a task for every time its schedule triggers on in a historical time range.
+type Backfill struct {
+	ID             ID        `json:"id"`
+	TaskID         ID        `json:"taskID"`
+	OrganizationID ID        `json:"orgID"`
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end"`
+	Status         string    `json:"status"`
+
+	// Total is the number of runs in the time range, of which Completed have
+	// finished successfully and Failed have failed.
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+
+	// LatestScheduled is the scheduled time of the latest run started by the backfill.
+	LatestScheduled time.Time `json:"latestScheduled,omitempty"`
+
+	// Error is why a failed backfill stopped.
+	Error string `json:"error,omitempty"`
+
+	CRUDLog
+}
+
+// Done returns true when the backfill is no longer running.
+func (b *Backfill) Done() bool {
+	return b.Status != BackfillStatusRunning
+}
+
+// BackfillService starts and tracks backfills of tasks.
+type BackfillService interface {
+	// CreateBackfill starts a backfill of the task from start to end, both inclusive.
+	CreateBackfill(ctx context.Context, taskID ID, start, end time.Time) (*Backfill, error)
+
+	// FindBackfillByID returns a single backfill of the task.
+	FindBackfillByID(ctx context.Context, taskID, id ID) (*Backfill, error)
+
+	// FindBackfills returns the backfills of the task.
+	FindBackfills(ctx context.Context, taskID ID) ([]*Backfill, error)
+
+	// CancelBackfill stops a running backfill and cancels its runs in progress.
+	CancelBackfill(ctx context.Context, taskID, id ID) (*Backfill, error)
+}
@@ -22,8 +22,10 @@ type Service struct {
 
 	log *zap.Logger
 
-	orgs  influxdb.OrganizationService
-	tasks influxdb.TaskService
+	orgs             influxdb.OrganizationService
+	tasks            influxdb.TaskService
+	messageTemplates influxdb.MessageTemplateService
+	limit            kv.CreateLimit
 
 	timeGenerator influxdb.TimeGenerator
 	idGenerator   influxdb.IDGenerator
@@ -31,9 +33,27 @@ type Service struct {
 	checkStore *kv.IndexStore
 }
 
+// Option configures the check service.
+type Option func(*Service)
+
+// WithMessageTemplateService compiles the message template referenced by a
+// check into the flux of its task.
+func WithMessageTemplateService(messageTemplates influxdb.MessageTemplateService) Option {
+	return func(s *Service) {
+		s.messageTemplates = messageTemplates
+	}
+}
+
+// WithCheckLimit limits the number of checks of an organization.
+func WithCheckLimit(limit kv.CreateLimit) Option {
+	return func(s *Service) {
+		s.limit = limit
+	}
+}
+
 // NewService constructs and configures a new checks.Service
-func NewService(logger *zap.Logger, store kv.Store, orgs influxdb.OrganizationService, tasks influxdb.TaskService) *Service {
-	return &Service{
+func NewService(logger *zap.Logger, store kv.Store, orgs influxdb.OrganizationService, tasks influxdb.TaskService, opts ...Option) *Service {
+	s := &Service{
 		kv:    store,
 		log:   logger,
 		orgs:  orgs,
@@ -43,6 +63,10 @@ func NewService(logger *zap.Logger, store kv.Store, orgs influxdb.OrganizationSe
 		idGenerator:   snowflake.NewIDGenerator(),
 		checkStore:    newCheckStore(),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func newCheckStore() *kv.IndexStore {
@@ -269,6 +293,26 @@ func (s *Service) FindChecks(ctx context.Context, filter influxdb.CheckFilter, o
 	return checks, len(checks), nil
 }
 
+// countChecks returns the number of checks of the organization.
+func (s *Service) countChecks(ctx context.Context, tx kv.Tx, orgID influxdb.ID) (int, error) {
+	var n int
+	filterFn := filterChecksFn(influxdb.CheckFilter{OrgID: &orgID})
+	err := s.checkStore.Find(ctx, tx, kv.FindOpts{
+		FilterEntFn: func(k []byte, v interface{}) bool {
+			ch, ok := v.(influxdb.Check)
+			if err := kv.IsErrUnexpectedDecodeVal(ok); err != nil {
+				return false
+			}
+			return filterFn(ch)
+		},
+		CaptureFn: func(key []byte, decodedVal interface{}) error {
+			n++
+			return nil
+		},
+	})
+	return n, err
+}
+
 // CreateCheck creates a influxdb check and sets ID.
 func (s *Service) CreateCheck(ctx context.Context, c influxdb.CheckCreate, userID influxdb.ID) (err error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
@@ -311,6 +355,14 @@ func (s *Service) CreateCheck(ctx context.Context, c influxdb.CheckCreate, userI
 	c.SetTaskID(t.ID)
 
 	err = s.kv.Update(ctx, func(tx kv.Tx) error {
+		if s.limit != nil {
+			err := s.limit(ctx, tx, c.GetOrgID(), func() (int, error) {
+				return s.countChecks(ctx, tx, c.GetOrgID())
+			})
+			if err != nil {
+				return err
+			}
+		}
 		return s.putCheck(ctx, tx, c, kv.PutNew())
 	})
 
@@ -336,6 +388,10 @@ func (s *Service) CreateCheck(ctx context.Context, c influxdb.CheckCreate, userI
 }
 
 func (s *Service) createCheckTask(ctx context.Context, c influxdb.CheckCreate) (*influxdb.Task, error) {
+	if err := s.setMessageTemplate(ctx, c.Check); err != nil {
+		return nil, err
+	}
+
 	script, err := c.GenerateFlux(fluxlang.DefaultService)
 	if err != nil {
 		return nil, err
@@ -432,6 +488,10 @@ func (s *Service) UpdateCheck(ctx context.Context, id influxdb.ID, chk influxdb.
 }
 
 func (s *Service) updateCheckTask(ctx context.Context, chk influxdb.CheckCreate) error {
+	if err := s.setMessageTemplate(ctx, chk.Check); err != nil {
+		return err
+	}
+
 	flux, err := chk.GenerateFlux(fluxlang.DefaultService)
 	if err != nil {
 		return err
@@ -453,6 +513,55 @@ func (s *Service) updateCheckTask(ctx context.Context, chk influxdb.CheckCreate)
 	return err
 }
 
+// messageTemplater is implemented by the checks that compile a message template into their flux.
+type messageTemplater interface {
+	GetMessageTemplateID() influxdb.ID
+	SetMessageTemplate(t *influxdb.MessageTemplate) error
+}
+
+// setMessageTemplate sets the message template referenced by the check. A
+// check whose template was deleted falls back to its own message template.
+func (s *Service) setMessageTemplate(ctx context.Context, c influxdb.Check) error {
+	mt, ok := c.(messageTemplater)
+	if s.messageTemplates == nil || !ok || !mt.GetMessageTemplateID().Valid() {
+		return nil
+	}
+
+	t, err := s.messageTemplates.FindMessageTemplateByID(ctx, mt.GetMessageTemplateID())
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return mt.SetMessageTemplate(nil)
+	}
+	if err != nil {
+		return err
+	}
+	if t.OrgID != c.GetOrgID() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "message template must belong to the organization of the check",
+		}
+	}
+	return mt.SetMessageTemplate(t)
+}
+
+// SyncCheckTasks regenerates the tasks of the checks of the organization
+// that reference a message template with their current message templates.
+func (s *Service) SyncCheckTasks(ctx context.Context, orgID influxdb.ID) error {
+	cs, _, err := s.FindChecks(ctx, influxdb.CheckFilter{OrgID: &orgID})
+	if err != nil {
+		return err
+	}
+
+	for _, c := range cs {
+		if mt, ok := c.(messageTemplater); !ok || !mt.GetMessageTemplateID().Valid() {
+			continue
+		}
+		if err := s.updateCheckTask(ctx, influxdb.CheckCreate{Check: c}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *Service) patchCheckTask(ctx context.Context, taskID influxdb.ID, upd influxdb.CheckUpdate) error {
 	tu := influxdb.TaskUpdate{
 		Description: upd.Description,
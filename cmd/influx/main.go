@@ -328,6 +328,9 @@ func influxCmd(opts ...genericCLIOptFn) *cobra.Command {
 		cmdDashboard,
 		cmdDelete,
 		cmdExport,
+		cmdNotificationDelivery,
+		cmdNotificationEndpoint,
+		cmdNotificationRule,
 		cmdOrganization,
 		cmdPing,
 		cmdQuery,
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/delivery"
+	"github.com/influxdata/influxdb/v2/tenant"
+	"github.com/spf13/cobra"
+)
+
+type notificationDeliverySVCsFn func() (influxdb.NotificationDeliveryService, influxdb.OrganizationService, error)
+
+func cmdNotificationDelivery(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	builder := newCmdNotificationDeliveryBuilder(newNotificationDeliverySVCs, f, opt)
+	return builder.cmd()
+}
+
+type cmdNotificationDeliveryBuilder struct {
+	genericCLIOpts
+	*globalFlags
+
+	svcFn notificationDeliverySVCsFn
+
+	json        bool
+	hideHeaders bool
+	id          string
+	ruleID      string
+	endpointID  string
+	state       string
+	org         organization
+}
+
+func newCmdNotificationDeliveryBuilder(svcsFn notificationDeliverySVCsFn, f *globalFlags, opt genericCLIOpts) *cmdNotificationDeliveryBuilder {
+	return &cmdNotificationDeliveryBuilder{
+		genericCLIOpts: opt,
+		globalFlags:    f,
+		svcFn:          svcsFn,
+	}
+}
+
+func (b *cmdNotificationDeliveryBuilder) cmd() *cobra.Command {
+	cmd := b.genericCLIOpts.newCmd("notification-delivery", nil, false)
+	cmd.Short = "Failed notification delivery commands"
+	cmd.Run = seeHelp
+	cmd.AddCommand(
+		b.cmdFind(),
+		b.cmdResend(),
+	)
+	return cmd
+}
+
+func (b *cmdNotificationDeliveryBuilder) cmdFind() *cobra.Command {
+	cmd := b.newCmd("list", b.cmdFindRunEFn)
+	cmd.Short = "List the failed deliveries of notification rules"
+	cmd.Aliases = []string{"find", "ls"}
+
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The notification delivery ID")
+	cmd.Flags().StringVar(&b.ruleID, "rule-id", "", "Only show deliveries of this notification rule")
+	cmd.Flags().StringVar(&b.endpointID, "endpoint-id", "", "Only show deliveries to this notification endpoint")
+	cmd.Flags().StringVar(&b.state, "state", "", "Only show deliveries in this state, failed or resent")
+	b.org.register(b.viper, cmd, false)
+	b.registerPrintFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdNotificationDeliveryBuilder) cmdFindRunEFn(cmd *cobra.Command, args []string) error {
+	deliverySvc, orgSvc, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if b.id != "" {
+		id, err := influxdb.IDFromString(b.id)
+		if err != nil {
+			return fmt.Errorf("invalid notification delivery ID provided: %v", err)
+		}
+		d, err := deliverySvc.FindNotificationDeliveryByID(ctx, *id)
+		if err != nil {
+			return fmt.Errorf("failed to find notification delivery %q: %v", b.id, err)
+		}
+		return b.printDeliveries(d)
+	}
+
+	orgID, err := b.org.getID(orgSvc)
+	if err != nil {
+		return err
+	}
+	filter := influxdb.NotificationDeliveryFilter{OrgID: &orgID}
+	if b.ruleID != "" {
+		filter.RuleID, err = influxdb.IDFromString(b.ruleID)
+		if err != nil {
+			return fmt.Errorf("invalid notification rule ID provided: %v", err)
+		}
+	}
+	if b.endpointID != "" {
+		filter.EndpointID, err = influxdb.IDFromString(b.endpointID)
+		if err != nil {
+			return fmt.Errorf("invalid notification endpoint ID provided: %v", err)
+		}
+	}
+	if b.state != "" {
+		state := influxdb.NotificationDeliveryState(b.state)
+		if err := state.Valid(); err != nil {
+			return err
+		}
+		filter.State = &state
+	}
+
+	ds, err := deliverySvc.FindNotificationDeliveries(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve notification deliveries: %v", err)
+	}
+	return b.printDeliveries(ds...)
+}
+
+func (b *cmdNotificationDeliveryBuilder) cmdResend() *cobra.Command {
+	cmd := b.newCmd("resend", b.cmdResendRunEFn)
+	cmd.Short = "Resend a failed notification delivery"
+	cmd.Long = `Resend a failed notification delivery.
+
+The notification is sent to the endpoint it failed to reach, formatted by the
+current version of its notification rule. A resend that fails is recorded on
+the delivery.`
+
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The notification delivery ID (required)")
+	cmd.MarkFlagRequired("id")
+	b.registerPrintFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdNotificationDeliveryBuilder) cmdResendRunEFn(cmd *cobra.Command, args []string) error {
+	deliverySvc, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	id, err := influxdb.IDFromString(b.id)
+	if err != nil {
+		return fmt.Errorf("invalid notification delivery ID provided: %v", err)
+	}
+	d, err := deliverySvc.ResendNotificationDelivery(context.Background(), *id)
+	if err != nil {
+		return fmt.Errorf("failed to resend notification delivery %q: %v", b.id, err)
+	}
+	return b.printDeliveries(d)
+}
+
+func (b *cmdNotificationDeliveryBuilder) newCmd(use string, runE func(*cobra.Command, []string) error) *cobra.Command {
+	cmd := b.genericCLIOpts.newCmd(use, runE, true)
+	b.globalFlags.registerFlags(b.viper, cmd)
+	return cmd
+}
+
+func (b *cmdNotificationDeliveryBuilder) registerPrintFlags(cmd *cobra.Command) {
+	registerPrintOptions(b.viper, cmd, &b.hideHeaders, &b.json)
+}
+
+func (b *cmdNotificationDeliveryBuilder) printDeliveries(ds ...*influxdb.NotificationDelivery) error {
+	if b.json {
+		if len(ds) == 1 {
+			return b.writeJSON(ds[0])
+		}
+		return b.writeJSON(ds)
+	}
+
+	w := b.newTabWriter()
+	defer w.Flush()
+
+	w.HideHeaders(b.hideHeaders)
+
+	w.WriteHeaders("ID", "Rule", "Endpoint", "Check", "Level", "State", "Status Code", "Attempts", "Resends", "Error", "Time")
+	for _, d := range ds {
+		w.Write(map[string]interface{}{
+			"ID":          d.ID.String(),
+			"Rule":        d.RuleName,
+			"Endpoint":    d.EndpointName,
+			"Check":       d.CheckName,
+			"Level":       d.Level,
+			"State":       d.State,
+			"Status Code": d.StatusCode,
+			"Attempts":    d.Attempts,
+			"Resends":     d.Resends,
+			"Error":       d.Error,
+			"Time":        d.Time.Format(time.RFC3339),
+		})
+	}
+	return nil
+}
+
+func newNotificationDeliverySVCs() (influxdb.NotificationDeliveryService, influxdb.OrganizationService, error) {
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orgSvc := &tenant.OrgClientService{Client: httpClient}
+
+	return &delivery.ClientService{Client: httpClient}, orgSvc, nil
+}
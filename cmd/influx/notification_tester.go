@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/http"
+	"github.com/spf13/cobra"
+)
+
+type notificationTestSVCFn func() (influxdb.NotificationTestService, error)
+
+func cmdNotificationEndpoint(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	builder := newCmdNotificationTestBuilder(newNotificationTestService, f, opt)
+	return builder.cmdEndpoint()
+}
+
+func cmdNotificationRule(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	builder := newCmdNotificationTestBuilder(newNotificationTestService, f, opt)
+	return builder.cmdRule()
+}
+
+type cmdNotificationTestBuilder struct {
+	genericCLIOpts
+	*globalFlags
+
+	svcFn notificationTestSVCFn
+
+	json        bool
+	hideHeaders bool
+	id          string
+}
+
+func newCmdNotificationTestBuilder(svcFn notificationTestSVCFn, f *globalFlags, opt genericCLIOpts) *cmdNotificationTestBuilder {
+	return &cmdNotificationTestBuilder{
+		genericCLIOpts: opt,
+		globalFlags:    f,
+		svcFn:          svcFn,
+	}
+}
+
+func (b *cmdNotificationTestBuilder) cmdEndpoint() *cobra.Command {
+	cmd := b.genericCLIOpts.newCmd("notification-endpoint", nil, false)
+	cmd.Short = "Notification endpoint commands"
+	cmd.Run = seeHelp
+
+	test := b.newCmd("test", b.endpointTestRunEFn)
+	test.Short = "Send a test notification to a notification endpoint"
+	test.Long = `Send a test notification to a notification endpoint.
+
+The secrets of the endpoint are resolved as for the notifications of rules,
+which verifies its url and credentials. The test email of an smtp endpoint is
+sent to its from address.`
+	test.Flags().StringVarP(&b.id, "id", "i", "", "The notification endpoint ID (required)")
+	test.MarkFlagRequired("id")
+	b.registerPrintFlags(test)
+
+	cmd.AddCommand(test)
+	return cmd
+}
+
+func (b *cmdNotificationTestBuilder) endpointTestRunEFn(cmd *cobra.Command, args []string) error {
+	testSvc, err := b.svcFn()
+	if err != nil {
+		return fmt.Errorf("failed to initialize notification test service client: %v", err)
+	}
+
+	id, err := influxdb.IDFromString(b.id)
+	if err != nil {
+		return fmt.Errorf("invalid notification endpoint ID provided: %v", err)
+	}
+	res, err := testSvc.TestNotificationEndpoint(context.Background(), *id)
+	if err != nil {
+		return fmt.Errorf("failed to test notification endpoint %q: %v", b.id, err)
+	}
+	return b.printResult(res)
+}
+
+func (b *cmdNotificationTestBuilder) cmdRule() *cobra.Command {
+	cmd := b.genericCLIOpts.newCmd("notification-rule", nil, false)
+	cmd.Short = "Notification rule commands"
+	cmd.Run = seeHelp
+
+	test := b.newCmd("test", b.ruleTestRunEFn)
+	test.Short = "Send a test notification formatted by a notification rule"
+	test.Long = `Send a test notification formatted by a notification rule.
+
+The notification is formatted by the rule for a status matching its first tag
+and status rules, and is sent to the endpoint of the rule.`
+	test.Flags().StringVarP(&b.id, "id", "i", "", "The notification rule ID (required)")
+	test.MarkFlagRequired("id")
+	b.registerPrintFlags(test)
+
+	cmd.AddCommand(test)
+	return cmd
+}
+
+func (b *cmdNotificationTestBuilder) ruleTestRunEFn(cmd *cobra.Command, args []string) error {
+	testSvc, err := b.svcFn()
+	if err != nil {
+		return fmt.Errorf("failed to initialize notification test service client: %v", err)
+	}
+
+	id, err := influxdb.IDFromString(b.id)
+	if err != nil {
+		return fmt.Errorf("invalid notification rule ID provided: %v", err)
+	}
+	res, err := testSvc.TestNotificationRule(context.Background(), *id)
+	if err != nil {
+		return fmt.Errorf("failed to test notification rule %q: %v", b.id, err)
+	}
+	return b.printResult(res)
+}
+
+func (b *cmdNotificationTestBuilder) newCmd(use string, runE func(*cobra.Command, []string) error) *cobra.Command {
+	cmd := b.genericCLIOpts.newCmd(use, runE, true)
+	b.globalFlags.registerFlags(b.viper, cmd)
+	return cmd
+}
+
+func (b *cmdNotificationTestBuilder) registerPrintFlags(cmd *cobra.Command) {
+	registerPrintOptions(b.viper, cmd, &b.hideHeaders, &b.json)
+}
+
+func (b *cmdNotificationTestBuilder) printResult(res *influxdb.NotificationTestResult) error {
+	if b.json {
+		return b.writeJSON(res)
+	}
+
+	w := b.newTabWriter()
+	defer w.Flush()
+
+	w.HideHeaders(b.hideHeaders)
+
+	w.WriteHeaders("Endpoint ID", "Sent", "Status Code", "Message")
+	w.Write(map[string]interface{}{
+		"Endpoint ID": res.EndpointID.String(),
+		"Sent":        res.Sent,
+		"Status Code": res.StatusCode,
+		"Message":     res.Message,
+	})
+	return nil
+}
+
+func newNotificationTestService() (influxdb.NotificationTestService, error) {
+	client, err := newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.NotificationTestService{
+		Client: client,
+	}, nil
+}
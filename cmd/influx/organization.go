@@ -6,12 +6,16 @@ import (
 	"io"
 
 	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/cmd/internal"
+	"github.com/influxdata/influxdb/v2/quota"
 	"github.com/influxdata/influxdb/v2/tenant"
 	"github.com/spf13/cobra"
 )
 
 type orgSVCFn func() (influxdb.OrganizationService, influxdb.UserResourceMappingService, influxdb.UserService, error)
 
+type orgQuotaSVCFn func() (influxdb.OrgQuotaService, error)
+
 func cmdOrganization(f *globalFlags, opts genericCLIOpts) *cobra.Command {
 	builder := newCmdOrgBuilder(newOrgServices, f, opts)
 	return builder.cmd()
@@ -21,14 +25,17 @@ type cmdOrgBuilder struct {
 	genericCLIOpts
 	*globalFlags
 
-	svcFn orgSVCFn
-
-	json        bool
-	hideHeaders bool
-	description string
-	id          string
-	memberID    string
-	name        string
+	svcFn      orgSVCFn
+	quotaSvcFn orgQuotaSVCFn
+
+	json         bool
+	hideHeaders  bool
+	description  string
+	id           string
+	memberID     string
+	name         string
+	quota        influxdb.OrgQuota
+	maxRetention string
 }
 
 func newCmdOrgBuilder(svcFn orgSVCFn, f *globalFlags, opts genericCLIOpts) *cmdOrgBuilder {
@@ -36,6 +43,7 @@ func newCmdOrgBuilder(svcFn orgSVCFn, f *globalFlags, opts genericCLIOpts) *cmdO
 		genericCLIOpts: opts,
 		globalFlags:    f,
 		svcFn:          svcFn,
+		quotaSvcFn:     newOrgQuotaService,
 	}
 }
 
@@ -50,6 +58,7 @@ func (b *cmdOrgBuilder) cmd() *cobra.Command {
 		b.cmdDelete(),
 		b.cmdFind(),
 		b.cmdMember(),
+		b.cmdQuota(),
 		b.cmdUpdate(),
 	)
 
@@ -509,6 +518,255 @@ func (b *cmdOrgBuilder) membersRemoveRunEFn(cmd *cobra.Command, args []string) e
 	return removeMember(ctx, b.w, urmSVC, organization.ID, memberID)
 }
 
+func (b *cmdOrgBuilder) cmdQuota() *cobra.Command {
+	cmd := b.genericCLIOpts.newCmd("quota", nil, false)
+	cmd.Short = "Organization quota commands"
+	cmd.Run = seeHelp
+
+	cmd.AddCommand(
+		b.cmdQuotaDelete(),
+		b.cmdQuotaGet(),
+		b.cmdQuotaList(),
+		b.cmdQuotaSet(),
+	)
+
+	return cmd
+}
+
+func (b *cmdOrgBuilder) registerQuotaOrgFlags(cmd *cobra.Command) {
+	opts := flagOpts{
+		{
+			DestP:  &b.name,
+			Flag:   "name",
+			Short:  'n',
+			EnvVar: "ORG",
+			Desc:   "The organization name",
+		},
+		{
+			DestP:  &b.id,
+			Flag:   "id",
+			Short:  'i',
+			EnvVar: "ORG_ID",
+			Desc:   "The organization ID",
+		},
+	}
+	opts.mustRegister(b.viper, cmd)
+}
+
+// quotaOrgID resolves the organization of a quota command from its id or name flag.
+func (b *cmdOrgBuilder) quotaOrgID(ctx context.Context) (influxdb.ID, error) {
+	if (b.id == "") == (b.name == "") {
+		return 0, fmt.Errorf("must specify exactly one of id and name")
+	}
+
+	if b.id != "" {
+		var id influxdb.ID
+		if err := id.DecodeFromString(b.id); err != nil {
+			return 0, fmt.Errorf("failed to decode org id %s: %v", b.id, err)
+		}
+		return id, nil
+	}
+
+	orgSvc, _, _, err := b.svcFn()
+	if err != nil {
+		return 0, fmt.Errorf("failed to initialize org service client: %v", err)
+	}
+	o, err := orgSvc.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &b.name})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find org: %v", err)
+	}
+	return o.ID, nil
+}
+
+func (b *cmdOrgBuilder) cmdQuotaGet() *cobra.Command {
+	cmd := b.newCmd("get", b.quotaGetRunEFn)
+	cmd.Short = "Show the quota of an organization"
+
+	b.registerQuotaOrgFlags(cmd)
+	b.registerPrintFlags(cmd)
+	return cmd
+}
+
+func (b *cmdOrgBuilder) quotaGetRunEFn(cmd *cobra.Command, args []string) error {
+	quotaSvc, err := b.quotaSvcFn()
+	if err != nil {
+		return fmt.Errorf("failed to initialize quota service client: %v", err)
+	}
+
+	ctx := context.Background()
+	orgID, err := b.quotaOrgID(ctx)
+	if err != nil {
+		return err
+	}
+
+	q, err := quotaSvc.FindOrgQuota(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to find quota of org %q: %v", orgID, err)
+	}
+
+	return b.printQuotas(q)
+}
+
+func (b *cmdOrgBuilder) cmdQuotaList() *cobra.Command {
+	cmd := b.newCmd("list", b.quotaListRunEFn)
+	cmd.Short = "List the quotas of all organizations"
+	cmd.Aliases = []string{"find", "ls"}
+
+	b.registerPrintFlags(cmd)
+	return cmd
+}
+
+func (b *cmdOrgBuilder) quotaListRunEFn(cmd *cobra.Command, args []string) error {
+	quotaSvc, err := b.quotaSvcFn()
+	if err != nil {
+		return fmt.Errorf("failed to initialize quota service client: %v", err)
+	}
+
+	qs, err := quotaSvc.FindOrgQuotas(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to find quotas: %v", err)
+	}
+
+	return b.printQuotas(qs...)
+}
+
+func (b *cmdOrgBuilder) cmdQuotaSet() *cobra.Command {
+	cmd := b.newCmd("set", b.quotaSetRunEFn)
+	cmd.Short = "Set the quota of an organization"
+	cmd.Long = `Set the quota of an organization.
+
+Only the limits provided are changed, the other limits of an existing quota
+are kept. A limit of 0 removes the limit.`
+
+	b.registerQuotaOrgFlags(cmd)
+	b.registerPrintFlags(cmd)
+
+	cmd.Flags().IntVar(&b.quota.MaxBuckets, "max-buckets", 0, "The maximum number of buckets")
+	cmd.Flags().IntVar(&b.quota.MaxTasks, "max-tasks", 0, "The maximum number of tasks")
+	cmd.Flags().IntVar(&b.quota.MaxDashboards, "max-dashboards", 0, "The maximum number of dashboards")
+	cmd.Flags().IntVar(&b.quota.MaxChecks, "max-checks", 0, "The maximum number of checks")
+	cmd.Flags().IntVar(&b.quota.MaxNotificationRules, "max-notification-rules", 0, "The maximum number of notification rules")
+	cmd.Flags().StringVar(&b.maxRetention, "max-retention", "", "The longest retention period of a bucket")
+	cmd.Flags().IntVar(&b.quota.MaxWriteRequestsPerSecond, "max-write-requests", 0, "The maximum number of write requests per second")
+	cmd.Flags().IntVar(&b.quota.MaxWriteBytesPerSecond, "max-write-bytes", 0, "The maximum number of bytes written per second")
+	cmd.Flags().Int64Var(&b.quota.MaxSeriesCardinality, "max-series", 0, "The maximum number of series across all buckets")
+
+	return cmd
+}
+
+func (b *cmdOrgBuilder) quotaSetRunEFn(cmd *cobra.Command, args []string) error {
+	quotaSvc, err := b.quotaSvcFn()
+	if err != nil {
+		return fmt.Errorf("failed to initialize quota service client: %v", err)
+	}
+
+	ctx := context.Background()
+	orgID, err := b.quotaOrgID(ctx)
+	if err != nil {
+		return err
+	}
+
+	q, err := quotaSvc.FindOrgQuota(ctx, orgID)
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		q, err = &influxdb.OrgQuota{}, nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find quota of org %q: %v", orgID, err)
+	}
+	q.OrgID = orgID
+
+	flags := cmd.Flags()
+	set := func(flag string, dst *int, v int) {
+		if flags.Changed(flag) {
+			*dst = v
+		}
+	}
+	set("max-buckets", &q.MaxBuckets, b.quota.MaxBuckets)
+	set("max-tasks", &q.MaxTasks, b.quota.MaxTasks)
+	set("max-dashboards", &q.MaxDashboards, b.quota.MaxDashboards)
+	set("max-checks", &q.MaxChecks, b.quota.MaxChecks)
+	set("max-notification-rules", &q.MaxNotificationRules, b.quota.MaxNotificationRules)
+	set("max-write-requests", &q.MaxWriteRequestsPerSecond, b.quota.MaxWriteRequestsPerSecond)
+	set("max-write-bytes", &q.MaxWriteBytesPerSecond, b.quota.MaxWriteBytesPerSecond)
+	if flags.Changed("max-series") {
+		q.MaxSeriesCardinality = b.quota.MaxSeriesCardinality
+	}
+	if flags.Changed("max-retention") {
+		dur, err := internal.RawDurationToTimeDuration(b.maxRetention)
+		if err != nil {
+			return err
+		}
+		q.MaxRetentionSeconds = int64(dur.Seconds())
+	}
+
+	if err := quotaSvc.PutOrgQuota(ctx, q); err != nil {
+		return fmt.Errorf("failed to set quota of org %q: %v", orgID, err)
+	}
+
+	return b.printQuotas(q)
+}
+
+func (b *cmdOrgBuilder) cmdQuotaDelete() *cobra.Command {
+	cmd := b.newCmd("delete", b.quotaDeleteRunEFn)
+	cmd.Short = "Remove the quota of an organization"
+
+	b.registerQuotaOrgFlags(cmd)
+	return cmd
+}
+
+func (b *cmdOrgBuilder) quotaDeleteRunEFn(cmd *cobra.Command, args []string) error {
+	quotaSvc, err := b.quotaSvcFn()
+	if err != nil {
+		return fmt.Errorf("failed to initialize quota service client: %v", err)
+	}
+
+	ctx := context.Background()
+	orgID, err := b.quotaOrgID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := quotaSvc.DeleteOrgQuota(ctx, orgID); err != nil {
+		return fmt.Errorf("failed to delete quota of org %q: %v", orgID, err)
+	}
+	return nil
+}
+
+func (b *cmdOrgBuilder) printQuotas(qs ...*influxdb.OrgQuota) error {
+	if b.json {
+		if len(qs) == 1 {
+			return b.writeJSON(qs[0])
+		}
+		return b.writeJSON(qs)
+	}
+
+	w := b.newTabWriter()
+	defer w.Flush()
+
+	w.HideHeaders(b.hideHeaders)
+
+	w.WriteHeaders(
+		"Organization ID", "Buckets", "Tasks", "Dashboards", "Checks", "Notification Rules",
+		"Retention", "Write Requests", "Write Bytes", "Series",
+	)
+	for _, q := range qs {
+		w.Write(map[string]interface{}{
+			"Organization ID":    q.OrgID.String(),
+			"Buckets":            q.MaxBuckets,
+			"Tasks":              q.MaxTasks,
+			"Dashboards":         q.MaxDashboards,
+			"Checks":             q.MaxChecks,
+			"Notification Rules": q.MaxNotificationRules,
+			"Retention":          q.MaxRetention(),
+			"Write Requests":     q.MaxWriteRequestsPerSecond,
+			"Write Bytes":        q.MaxWriteBytesPerSecond,
+			"Series":             q.MaxSeriesCardinality,
+		})
+	}
+
+	return nil
+}
+
 func (b *cmdOrgBuilder) newCmd(use string, runE func(*cobra.Command, []string) error) *cobra.Command {
 	cmd := b.genericCLIOpts.newCmd(use, runE, true)
 	b.globalFlags.registerFlags(b.viper, cmd)
@@ -633,3 +891,14 @@ type orgPrintOpt struct {
 	org     *influxdb.Organization
 	orgs    []*influxdb.Organization
 }
+
+func newOrgQuotaService() (influxdb.OrgQuotaService, error) {
+	client, err := newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &quota.ClientService{
+		Client: client,
+	}, nil
+}
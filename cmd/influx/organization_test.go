@@ -582,6 +582,76 @@ func TestCmdOrg(t *testing.T) {
 			testMemberFn(t, "remove", cmdFn, addTests...)
 		})
 	})
+
+	t.Run("quota set", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			existing *influxdb.OrgQuota
+			flags    []string
+			expected influxdb.OrgQuota
+		}{
+			{
+				name:  "new quota",
+				flags: []string{"--max-buckets=10", "--max-retention=30d", "--max-series=1000"},
+				expected: influxdb.OrgQuota{
+					OrgID:                1,
+					MaxBuckets:           10,
+					MaxRetentionSeconds:  30 * 24 * 60 * 60,
+					MaxSeriesCardinality: 1000,
+				},
+			},
+			{
+				name:     "keeps existing limits",
+				existing: &influxdb.OrgQuota{OrgID: 1, MaxBuckets: 10, MaxTasks: 5},
+				flags:    []string{"--max-tasks=0", "--max-write-requests=100"},
+				expected: influxdb.OrgQuota{
+					OrgID:                     1,
+					MaxBuckets:                10,
+					MaxWriteRequestsPerSecond: 100,
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			fn := func(t *testing.T) {
+				defer addEnvVars(t, envVarsZeroMap)()
+
+				quotaSvc := mock.NewOrgQuotaService()
+				if tt.existing != nil {
+					quotaSvc.FindOrgQuotaFn = func(ctx context.Context, orgID influxdb.ID) (*influxdb.OrgQuota, error) {
+						q := *tt.existing
+						return &q, nil
+					}
+				}
+				var got *influxdb.OrgQuota
+				quotaSvc.PutOrgQuotaFn = func(ctx context.Context, q *influxdb.OrgQuota) error {
+					got = q
+					return nil
+				}
+
+				cmdFn := func(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+					builder := newCmdOrgBuilder(fakeOrgSVCFn(mock.NewOrganizationService()), f, opt)
+					builder.quotaSvcFn = func() (influxdb.OrgQuotaService, error) {
+						return quotaSvc, nil
+					}
+					return builder.cmd()
+				}
+
+				builder := newInfluxCmdBuilder(
+					in(new(bytes.Buffer)),
+					out(ioutil.Discard),
+				)
+				cmd := builder.cmd(cmdFn)
+				cmd.SetArgs(append([]string{"org", "quota", "set", "--id=" + influxdb.ID(1).String()}, tt.flags...))
+
+				require.NoError(t, cmd.Execute())
+				require.NotNil(t, got)
+				assert.Equal(t, tt.expected, *got)
+			}
+
+			t.Run(tt.name, fn)
+		}
+	})
 }
 
 var envVarsZeroMap = map[string]string{
@@ -5,10 +5,15 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/cmd/influx/internal"
+	cinternal "github.com/influxdata/influxdb/v2/cmd/internal"
 	"github.com/influxdata/influxdb/v2/http"
 	"github.com/spf13/cobra"
 )
@@ -23,7 +28,10 @@ func cmdTask(f *globalFlags, opt genericCLIOpts) *cobra.Command {
 	cmd.Short = "Task management commands"
 
 	cmd.AddCommand(
+		taskAnalyticsCmd(f, opt),
+		taskBackfillCmd(f, opt),
 		taskLogCmd(f, opt),
+		taskRevisionCmd(f, opt),
 		taskRunCmd(f, opt),
 		taskCreateCmd(f, opt),
 		taskDeleteCmd(f, opt),
@@ -40,8 +48,9 @@ var taskPrintFlags struct {
 }
 
 var taskCreateFlags struct {
-	org  organization
-	file string
+	org       organization
+	file      string
+	dependsOn []string
 }
 
 func taskCreateCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
@@ -52,6 +61,7 @@ func taskCreateCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
 
 	f.registerFlags(opt.viper, cmd)
 	cmd.Flags().StringVarP(&taskCreateFlags.file, "file", "f", "", "Path to Flux script file")
+	cmd.Flags().StringSliceVar(&taskCreateFlags.dependsOn, "depends-on", nil, "IDs of the upstream tasks the task runs after")
 	taskCreateFlags.org.register(opt.viper, cmd, false)
 	registerPrintOptions(opt.viper, cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
 
@@ -77,9 +87,15 @@ func taskCreateF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error parsing flux script: %s", err)
 	}
 
+	dependsOn, err := decodeTaskIDs(taskCreateFlags.dependsOn)
+	if err != nil {
+		return err
+	}
+
 	tc := influxdb.TaskCreate{
 		Flux:         flux,
 		Organization: taskCreateFlags.org.name,
+		DependsOn:    dependsOn,
 	}
 	if taskCreateFlags.org.id != "" || taskCreateFlags.org.name != "" {
 		svc, err := newOrganizationService()
@@ -203,9 +219,10 @@ func taskFindF(cmd *cobra.Command, args []string) error {
 }
 
 var taskUpdateFlags struct {
-	id     string
-	status string
-	file   string
+	id        string
+	status    string
+	file      string
+	dependsOn []string
 }
 
 func taskUpdateCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
@@ -218,6 +235,7 @@ func taskUpdateCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
 	cmd.Flags().StringVarP(&taskUpdateFlags.id, "id", "i", "", "task ID (required)")
 	cmd.Flags().StringVarP(&taskUpdateFlags.status, "status", "", "", "update task status")
 	cmd.Flags().StringVarP(&taskUpdateFlags.file, "file", "f", "", "Path to Flux script file")
+	cmd.Flags().StringSliceVar(&taskUpdateFlags.dependsOn, "depends-on", nil, "IDs of the upstream tasks the task runs after, empty to remove them")
 	cmd.MarkFlagRequired("id")
 
 	return cmd
@@ -243,6 +261,17 @@ func taskUpdateF(cmd *cobra.Command, args []string) error {
 		update.Status = &taskUpdateFlags.status
 	}
 
+	if cmd.Flags().Changed("depends-on") {
+		dependsOn, err := decodeTaskIDs(taskUpdateFlags.dependsOn)
+		if err != nil {
+			return err
+		}
+		if dependsOn == nil {
+			dependsOn = []influxdb.ID{}
+		}
+		update.DependsOn = &dependsOn
+	}
+
 	// update flux script only if first arg or file is supplied
 	if (len(args) > 0 && len(args[0]) > 0) || len(taskUpdateFlags.file) > 0 {
 		flux, err := readFluxQuery(args, taskUpdateFlags.file)
@@ -267,6 +296,18 @@ func taskUpdateF(cmd *cobra.Command, args []string) error {
 	)
 }
 
+func decodeTaskIDs(ids []string) ([]influxdb.ID, error) {
+	var decoded []influxdb.ID
+	for _, s := range ids {
+		var id influxdb.ID
+		if err := id.DecodeFromString(s); err != nil {
+			return nil, fmt.Errorf("invalid task ID %q: %v", s, err)
+		}
+		decoded = append(decoded, id)
+	}
+	return decoded, nil
+}
+
 var taskDeleteFlags struct {
 	id string
 }
@@ -381,6 +422,232 @@ func taskLogCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
 	return cmd
 }
 
+func taskRevisionCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("revision", nil, false)
+	cmd.Run = seeHelp
+	cmd.Short = "Revisions of the scripts of tasks"
+	cmd.Aliases = []string{"revisions"}
+
+	cmd.AddCommand(
+		taskRevisionListCmd(f, opt),
+		taskRevisionDiffCmd(f, opt),
+		taskRevisionRollbackCmd(f, opt),
+	)
+
+	return cmd
+}
+
+var taskRevisionFlags struct {
+	taskID   string
+	revision int
+	from     int
+}
+
+func taskRevisionListCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("list", taskRevisionListF, true)
+	cmd.Short = "List revisions of a task"
+	cmd.Aliases = []string{"find", "ls"}
+
+	f.registerFlags(opt.viper, cmd)
+	registerPrintOptions(opt.viper, cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
+	cmd.Flags().StringVarP(&taskRevisionFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().IntVarP(&taskRevisionFlags.revision, "revision", "r", 0, "show the script of a single revision")
+	cmd.MarkFlagRequired("task-id")
+
+	return cmd
+}
+
+func taskRevisionListF(cmd *cobra.Command, args []string) error {
+	var taskID influxdb.ID
+	if err := taskID.DecodeFromString(taskRevisionFlags.taskID); err != nil {
+		return err
+	}
+
+	client, err := newHTTPClient()
+	if err != nil {
+		return err
+	}
+	s := &http.TaskService{
+		Client: client,
+	}
+
+	if taskRevisionFlags.revision != 0 {
+		rev, err := s.FindTaskRevision(context.Background(), taskID, taskRevisionFlags.revision)
+		if err != nil {
+			return err
+		}
+		if taskPrintFlags.json {
+			return writeJSON(cmd.OutOrStdout(), rev)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), rev.Flux)
+		return nil
+	}
+
+	revs, err := s.FindTaskRevisions(context.Background(), taskID)
+	if err != nil {
+		return err
+	}
+	return printTaskRevisions(cmd.OutOrStdout(), revs)
+}
+
+func printTaskRevisions(w io.Writer, revs []*influxdb.TaskRevision) error {
+	if taskPrintFlags.json {
+		return writeJSON(w, revs)
+	}
+
+	tabW := internal.NewTabWriter(w)
+	defer tabW.Flush()
+
+	tabW.HideHeaders(taskPrintFlags.hideHeaders)
+
+	tabW.WriteHeaders(
+		"Revision",
+		"Name",
+		"Every",
+		"Cron",
+		"AuthorID",
+		"CreatedAt",
+		"RollbackOf",
+	)
+
+	for _, rev := range revs {
+		var authorID, rollbackOf string
+		if rev.AuthorID.Valid() {
+			authorID = rev.AuthorID.String()
+		}
+		if rev.RollbackOf != 0 {
+			rollbackOf = strconv.Itoa(rev.RollbackOf)
+		}
+
+		tabW.Write(map[string]interface{}{
+			"Revision":   rev.Revision,
+			"Name":       rev.Name,
+			"Every":      rev.Every,
+			"Cron":       rev.Cron,
+			"AuthorID":   authorID,
+			"CreatedAt":  rev.CreatedAt.Format(time.RFC3339),
+			"RollbackOf": rollbackOf,
+		})
+	}
+
+	return nil
+}
+
+func taskRevisionDiffCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("diff", taskRevisionDiffF, true)
+	cmd.Short = "Show the changes made by a revision of a task"
+	cmd.Long = `Show the changes to the options and the script of a task made by a
+revision, compared to the revision before it unless another one is given.
+
+Examples:
+	# show what revision 3 changed
+	influx task revision diff --task-id $TASK_ID --revision 3
+
+	# show the changes since revision 1
+	influx task revision diff --task-id $TASK_ID --revision 3 --from 1`
+
+	f.registerFlags(opt.viper, cmd)
+	registerPrintOptions(opt.viper, cmd, nil, &taskPrintFlags.json)
+	cmd.Flags().StringVarP(&taskRevisionFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().IntVarP(&taskRevisionFlags.revision, "revision", "r", 0, "revision to show the changes of (required)")
+	cmd.Flags().IntVarP(&taskRevisionFlags.from, "from", "", 0, "revision to compare with, the one before by default")
+	cmd.MarkFlagRequired("task-id")
+	cmd.MarkFlagRequired("revision")
+
+	return cmd
+}
+
+func taskRevisionDiffF(cmd *cobra.Command, args []string) error {
+	var taskID influxdb.ID
+	if err := taskID.DecodeFromString(taskRevisionFlags.taskID); err != nil {
+		return err
+	}
+
+	from := taskRevisionFlags.from
+	if from == 0 {
+		from = taskRevisionFlags.revision - 1
+	}
+	if from < 1 {
+		from = taskRevisionFlags.revision
+	}
+
+	client, err := newHTTPClient()
+	if err != nil {
+		return err
+	}
+	s := &http.TaskService{
+		Client: client,
+	}
+
+	d, err := s.DiffTaskRevisions(context.Background(), taskID, from, taskRevisionFlags.revision)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	if taskPrintFlags.json {
+		return writeJSON(w, d)
+	}
+
+	fmt.Fprintf(w, "--- revision %d\n+++ revision %d\n", d.From, d.To)
+	for _, o := range d.Options {
+		fmt.Fprintf(w, "option %s: %q -> %q\n", o.Option, o.From, o.To)
+	}
+	for _, l := range d.Lines {
+		fmt.Fprintln(w, l.String())
+	}
+	return nil
+}
+
+func taskRevisionRollbackCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("rollback", taskRevisionRollbackF, true)
+	cmd.Short = "Roll a task back to the script of a previous revision"
+	cmd.Long = `Roll a task back to the script of a previous revision. The rollback is
+kept as a new revision of the task, so it can be undone by rolling back again.
+
+Examples:
+	# restore the script of revision 2
+	influx task revision rollback --task-id $TASK_ID --revision 2`
+
+	f.registerFlags(opt.viper, cmd)
+	registerPrintOptions(opt.viper, cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
+	cmd.Flags().StringVarP(&taskRevisionFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().IntVarP(&taskRevisionFlags.revision, "revision", "r", 0, "revision to roll back to (required)")
+	cmd.MarkFlagRequired("task-id")
+	cmd.MarkFlagRequired("revision")
+
+	return cmd
+}
+
+func taskRevisionRollbackF(cmd *cobra.Command, args []string) error {
+	var taskID influxdb.ID
+	if err := taskID.DecodeFromString(taskRevisionFlags.taskID); err != nil {
+		return err
+	}
+
+	client, err := newHTTPClient()
+	if err != nil {
+		return err
+	}
+	s := &http.TaskService{
+		Client: client,
+	}
+
+	t, err := s.RollbackTask(context.Background(), taskID, taskRevisionFlags.revision)
+	if err != nil {
+		return err
+	}
+
+	return printTasks(
+		cmd.OutOrStdout(),
+		taskPrintOpts{
+			hideHeaders: taskPrintFlags.hideHeaders,
+			json:        taskPrintFlags.json,
+			task:        t,
+		},
+	)
+}
+
 var taskLogFindFlags struct {
 	taskID string
 	runID  string
@@ -453,10 +720,31 @@ func taskLogFindF(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var taskRunFlags struct {
+	taskID string
+	now    string
+	dryRun bool
+}
+
 func taskRunCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
-	cmd := opt.newCmd("run", nil, false)
-	cmd.Run = seeHelp
-	cmd.Short = "List runs for a task"
+	cmd := opt.newCmd("run", taskRunF, true)
+	cmd.Short = "Run a task, or list runs for a task"
+	cmd.Long = `Run a task as if it was scheduled for a given time, the current time by default.
+
+With --dry-run the script of the task runs without writing anything, the
+tables its to() and experimental.to() calls would have written are shown
+instead. Other side effects of the script, such as HTTP requests, still happen.
+
+Examples:
+	# show what the task would write if it ran at midnight
+	influx task run --task-id $TASK_ID --now 2020-01-01T00:00:00Z --dry-run`
+
+	f.registerFlags(opt.viper, cmd)
+	registerPrintOptions(opt.viper, cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
+	cmd.Flags().StringVarP(&taskRunFlags.taskID, "task-id", "i", "", "task id")
+	cmd.Flags().StringVarP(&taskRunFlags.now, "now", "", "", "time the task runs for, RFC3339")
+	cmd.Flags().BoolVarP(&taskRunFlags.dryRun, "dry-run", "", false, "show what the task would write instead of writing it")
+
 	cmd.AddCommand(
 		taskRunFindCmd(f, opt),
 		taskRunRetryCmd(f, opt),
@@ -465,6 +753,91 @@ func taskRunCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
 	return cmd
 }
 
+func taskRunF(cmd *cobra.Command, args []string) error {
+	if taskRunFlags.taskID == "" {
+		seeHelp(cmd, args)
+		return nil
+	}
+
+	var taskID influxdb.ID
+	if err := taskID.DecodeFromString(taskRunFlags.taskID); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if taskRunFlags.now != "" {
+		var err error
+		if now, err = time.Parse(time.RFC3339, taskRunFlags.now); err != nil {
+			return fmt.Errorf("invalid now time: %v", err)
+		}
+	}
+
+	client, err := newHTTPClient()
+	if err != nil {
+		return err
+	}
+	s := &http.TaskService{
+		Client: client,
+	}
+
+	if !taskRunFlags.dryRun {
+		run, err := s.ForceRun(context.Background(), taskID, now.Unix())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Run %s of task %s queued for %s.\n", run.ID, taskID, run.ScheduledFor.Format(time.RFC3339))
+		return nil
+	}
+
+	dr, err := s.DryRunTask(context.Background(), taskID, now)
+	if err != nil {
+		return err
+	}
+	return printDryRun(cmd.OutOrStdout(), dr)
+}
+
+func printDryRun(w io.Writer, dr *influxdb.TaskDryRun) error {
+	if taskPrintFlags.json {
+		return writeJSON(w, dr)
+	}
+
+	tabW := internal.NewTabWriter(w)
+	defer tabW.Flush()
+
+	tabW.HideHeaders(taskPrintFlags.hideHeaders)
+
+	tabW.WriteHeaders(
+		"Call",
+		"GroupKey",
+		"Columns",
+		"Rows",
+	)
+
+	for _, wr := range dr.Writes {
+		if len(wr.Tables) == 0 {
+			tabW.Write(map[string]interface{}{
+				"Call": wr.Call,
+				"Rows": 0,
+			})
+		}
+		for _, t := range wr.Tables {
+			keys := make([]string, 0, len(t.GroupKey))
+			for k, v := range t.GroupKey {
+				keys = append(keys, k+"="+v)
+			}
+			sort.Strings(keys)
+
+			tabW.Write(map[string]interface{}{
+				"Call":     wr.Call,
+				"GroupKey": strings.Join(keys, ","),
+				"Columns":  strings.Join(t.Columns, ","),
+				"Rows":     t.RowCount,
+			})
+		}
+	}
+
+	return nil
+}
+
 var taskRunFindFlags struct {
 	runID      string
 	taskID     string
@@ -552,6 +925,9 @@ func taskRunFindF(cmd *cobra.Command, args []string) error {
 		"StartedAt",
 		"FinishedAt",
 		"RequestedAt",
+		"Attempt",
+		"RetryOf",
+		"Revision",
 	)
 
 	for _, r := range runs {
@@ -559,6 +935,10 @@ func taskRunFindF(cmd *cobra.Command, args []string) error {
 		startedAt := r.StartedAt.Format(time.RFC3339Nano)
 		finishedAt := r.FinishedAt.Format(time.RFC3339Nano)
 		requestedAt := r.RequestedAt.Format(time.RFC3339Nano)
+		var retryOf string
+		if r.RetryOf.Valid() {
+			retryOf = r.RetryOf.String()
+		}
 
 		tabW.Write(map[string]interface{}{
 			"ID":           r.ID,
@@ -568,6 +948,9 @@ func taskRunFindF(cmd *cobra.Command, args []string) error {
 			"StartedAt":    startedAt,
 			"FinishedAt":   finishedAt,
 			"RequestedAt":  requestedAt,
+			"Attempt":      r.AttemptNumber(),
+			"RetryOf":      retryOf,
+			"Revision":     r.Revision,
 		})
 	}
 
@@ -619,3 +1002,426 @@ func runRetryF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+var taskAnalyticsFlags struct {
+	org       organization
+	taskID    string
+	start     string
+	stop      string
+	retention string
+}
+
+func taskAnalyticsCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("analytics", taskAnalyticsF, true)
+	cmd.Short = "Show success rates, durations and failure reasons of task runs"
+	cmd.Long = `Aggregate the run history of the tasks of an organization.
+
+Runs are aggregated over the last 30 days unless a start or stop is given, the
+run history is only available for the retention of the organization. Durations
+and queue latencies are in seconds.
+
+Examples:
+	# success rates of the tasks of the organization
+	influx task analytics --org my-org
+
+	# analytics of a task since the start of the year
+	influx task analytics --org my-org --task-id $TASK_ID --start 2020-01-01T00:00:00Z`
+
+	taskAnalyticsFlags.org.register(opt.viper, cmd, false)
+	f.registerFlags(opt.viper, cmd)
+	registerPrintOptions(opt.viper, cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
+	cmd.Flags().StringVarP(&taskAnalyticsFlags.taskID, "task-id", "i", "", "task id")
+	cmd.Flags().StringVarP(&taskAnalyticsFlags.start, "start", "", "", "start of the time range, RFC3339")
+	cmd.Flags().StringVarP(&taskAnalyticsFlags.stop, "stop", "", "", "stop of the time range, RFC3339")
+
+	cmd.AddCommand(taskAnalyticsRetentionCmd(f, opt))
+
+	return cmd
+}
+
+func taskAnalyticsF(cmd *cobra.Command, args []string) error {
+	orgID, err := taskAnalyticsOrgID()
+	if err != nil {
+		return err
+	}
+	filter := influxdb.TaskAnalyticsFilter{OrganizationID: orgID}
+
+	if taskAnalyticsFlags.taskID != "" {
+		id, err := influxdb.IDFromString(taskAnalyticsFlags.taskID)
+		if err != nil {
+			return err
+		}
+		filter.TaskID = id
+	}
+	if taskAnalyticsFlags.start != "" {
+		if filter.Start, err = time.Parse(time.RFC3339, taskAnalyticsFlags.start); err != nil {
+			return fmt.Errorf("invalid start time: %v", err)
+		}
+	}
+	if taskAnalyticsFlags.stop != "" {
+		if filter.Stop, err = time.Parse(time.RFC3339, taskAnalyticsFlags.stop); err != nil {
+			return fmt.Errorf("invalid stop time: %v", err)
+		}
+	}
+
+	client, err := newHTTPClient()
+	if err != nil {
+		return err
+	}
+	s := &http.TaskAnalyticsService{
+		Client: client,
+	}
+
+	analytics, err := s.FindTaskAnalytics(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	if taskPrintFlags.json {
+		return writeJSON(cmd.OutOrStdout(), analytics)
+	}
+
+	tabW := internal.NewTabWriter(cmd.OutOrStdout())
+	defer tabW.Flush()
+
+	tabW.HideHeaders(taskPrintFlags.hideHeaders)
+
+	tabW.WriteHeaders(
+		"TaskID",
+		"Runs",
+		"SuccessRate",
+		"Failed",
+		"Canceled",
+		"TimedOut",
+		"DurationP50",
+		"DurationP95",
+		"QueueLatencyP95",
+		"TopFailure",
+	)
+
+	for _, a := range analytics {
+		topFailure := ""
+		if len(a.FailureReasons) > 0 {
+			topFailure = fmt.Sprintf("%s (%d)", a.FailureReasons[0].Reason, a.FailureReasons[0].Count)
+		}
+		tabW.Write(map[string]interface{}{
+			"TaskID":          a.TaskID,
+			"Runs":            a.Runs,
+			"SuccessRate":     fmt.Sprintf("%.1f%%", a.SuccessRate*100),
+			"Failed":          a.Statuses[influxdb.RunFail.String()],
+			"Canceled":        a.Statuses[influxdb.RunCanceled.String()],
+			"TimedOut":        a.Statuses[influxdb.RunTimeout.String()],
+			"DurationP50":     fmt.Sprintf("%.3f", a.Duration.P50),
+			"DurationP95":     fmt.Sprintf("%.3f", a.Duration.P95),
+			"QueueLatencyP95": fmt.Sprintf("%.3f", a.QueueLatency.P95),
+			"TopFailure":      topFailure,
+		})
+	}
+
+	return nil
+}
+
+func taskAnalyticsRetentionCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("retention", taskAnalyticsRetentionF, true)
+	cmd.Short = "Show or change how long the run history of an organization is kept"
+
+	taskAnalyticsFlags.org.register(opt.viper, cmd, false)
+	f.registerFlags(opt.viper, cmd)
+	registerPrintOptions(opt.viper, cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
+	cmd.Flags().StringVarP(&taskAnalyticsFlags.retention, "retention", "r", "", "Duration the run history is kept, at least 1h. 0 is infinite.")
+
+	return cmd
+}
+
+func taskAnalyticsRetentionF(cmd *cobra.Command, args []string) error {
+	orgID, err := taskAnalyticsOrgID()
+	if err != nil {
+		return err
+	}
+
+	client, err := newHTTPClient()
+	if err != nil {
+		return err
+	}
+	s := &http.TaskAnalyticsService{
+		Client: client,
+	}
+
+	var ret *influxdb.TaskRunRetention
+	if taskAnalyticsFlags.retention != "" {
+		dur, err := cinternal.RawDurationToTimeDuration(taskAnalyticsFlags.retention)
+		if err != nil {
+			return err
+		}
+		ret, err = s.UpdateTaskRunRetention(context.Background(), orgID, dur)
+		if err != nil {
+			return err
+		}
+	} else {
+		ret, err = s.FindTaskRunRetention(context.Background(), orgID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if taskPrintFlags.json {
+		return writeJSON(cmd.OutOrStdout(), ret)
+	}
+
+	tabW := internal.NewTabWriter(cmd.OutOrStdout())
+	defer tabW.Flush()
+
+	tabW.HideHeaders(taskPrintFlags.hideHeaders)
+
+	tabW.WriteHeaders("OrganizationID", "Retention")
+	retention := "infinite"
+	if ret.Retention > 0 {
+		retention = ret.Retention.String()
+	}
+	tabW.Write(map[string]interface{}{
+		"OrganizationID": ret.OrganizationID,
+		"Retention":      retention,
+	})
+
+	return nil
+}
+
+// taskAnalyticsOrgID returns the ID of the organization given by the flags.
+func taskAnalyticsOrgID() (influxdb.ID, error) {
+	if err := taskAnalyticsFlags.org.validOrgFlags(&flags); err != nil {
+		return 0, err
+	}
+	svc, err := newOrganizationService()
+	if err != nil {
+		return 0, err
+	}
+	return taskAnalyticsFlags.org.getID(svc)
+}
+
+var taskBackfillFlags struct {
+	taskID     string
+	backfillID string
+	start      string
+	end        string
+	detach     bool
+}
+
+func taskBackfillCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("backfill", taskBackfillF, true)
+	cmd.Short = "Backfill a task over a historical time range"
+	cmd.Long = `Run a task for every time its schedule triggers on between start and end.
+
+Progress is reported until the backfill finishes, interrupting the command cancels
+the backfill. With --detach the backfill continues in the background and can be
+followed with "influx task backfill list".`
+
+	f.registerFlags(opt.viper, cmd)
+	registerPrintOptions(opt.viper, cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
+	cmd.Flags().StringVarP(&taskBackfillFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().StringVarP(&taskBackfillFlags.start, "start", "", "", "start of the time range, RFC3339 (required)")
+	cmd.Flags().StringVarP(&taskBackfillFlags.end, "end", "", "", "end of the time range, RFC3339 (required)")
+	cmd.Flags().BoolVarP(&taskBackfillFlags.detach, "detach", "d", false, "return once the backfill is started")
+	cmd.MarkFlagRequired("task-id")
+	cmd.MarkFlagRequired("start")
+	cmd.MarkFlagRequired("end")
+
+	cmd.AddCommand(
+		taskBackfillListCmd(f, opt),
+		taskBackfillCancelCmd(f, opt),
+	)
+
+	return cmd
+}
+
+func taskBackfillF(cmd *cobra.Command, args []string) error {
+	var taskID influxdb.ID
+	if err := taskID.DecodeFromString(taskBackfillFlags.taskID); err != nil {
+		return err
+	}
+	start, err := time.Parse(time.RFC3339, taskBackfillFlags.start)
+	if err != nil {
+		return fmt.Errorf("invalid start time: %v", err)
+	}
+	end, err := time.Parse(time.RFC3339, taskBackfillFlags.end)
+	if err != nil {
+		return fmt.Errorf("invalid end time: %v", err)
+	}
+
+	client, err := newHTTPClient()
+	if err != nil {
+		return err
+	}
+	s := &http.TaskService{
+		Client: client,
+	}
+
+	ctx := context.Background()
+	b, err := s.CreateBackfill(ctx, taskID, start, end)
+	if err != nil {
+		return err
+	}
+
+	if !taskBackfillFlags.detach {
+		b, err = waitBackfill(ctx, cmd.ErrOrStderr(), s, b)
+		if err != nil {
+			return err
+		}
+	}
+
+	return printBackfills(cmd.OutOrStdout(), []*influxdb.Backfill{b})
+}
+
+// waitBackfill reports the progress of the backfill until it finishes. The
+// backfill is canceled when the command is interrupted.
+func waitBackfill(ctx context.Context, w io.Writer, s *http.TaskService, b *influxdb.Backfill) (*influxdb.Backfill, error) {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for !b.Done() {
+		fmt.Fprintf(w, "Backfill %s: %d of %d runs finished, %d failed\n", b.ID, b.Completed+b.Failed, b.Total, b.Failed)
+
+		select {
+		case <-interrupt:
+			fmt.Fprintf(w, "Canceling backfill %s\n", b.ID)
+			return s.CancelBackfill(ctx, b.TaskID, b.ID)
+		case <-ticker.C:
+		}
+
+		var err error
+		if b, err = s.FindBackfillByID(ctx, b.TaskID, b.ID); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func taskBackfillListCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("list", taskBackfillListF, true)
+	cmd.Short = "List backfills of a task"
+	cmd.Aliases = []string{"find", "ls"}
+
+	f.registerFlags(opt.viper, cmd)
+	registerPrintOptions(opt.viper, cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
+	cmd.Flags().StringVarP(&taskBackfillFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().StringVarP(&taskBackfillFlags.backfillID, "id", "", "", "backfill id")
+	cmd.MarkFlagRequired("task-id")
+
+	return cmd
+}
+
+func taskBackfillListF(cmd *cobra.Command, args []string) error {
+	var taskID influxdb.ID
+	if err := taskID.DecodeFromString(taskBackfillFlags.taskID); err != nil {
+		return err
+	}
+
+	client, err := newHTTPClient()
+	if err != nil {
+		return err
+	}
+	s := &http.TaskService{
+		Client: client,
+	}
+
+	var backfills []*influxdb.Backfill
+	if taskBackfillFlags.backfillID != "" {
+		var id influxdb.ID
+		if err := id.DecodeFromString(taskBackfillFlags.backfillID); err != nil {
+			return err
+		}
+		b, err := s.FindBackfillByID(context.Background(), taskID, id)
+		if err != nil {
+			return err
+		}
+		backfills = append(backfills, b)
+	} else {
+		backfills, err = s.FindBackfills(context.Background(), taskID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return printBackfills(cmd.OutOrStdout(), backfills)
+}
+
+func taskBackfillCancelCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("cancel", taskBackfillCancelF, true)
+	cmd.Short = "Cancel a running backfill"
+
+	f.registerFlags(opt.viper, cmd)
+	registerPrintOptions(opt.viper, cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
+	cmd.Flags().StringVarP(&taskBackfillFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().StringVarP(&taskBackfillFlags.backfillID, "id", "", "", "backfill id (required)")
+	cmd.MarkFlagRequired("task-id")
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+func taskBackfillCancelF(cmd *cobra.Command, args []string) error {
+	var taskID, id influxdb.ID
+	if err := taskID.DecodeFromString(taskBackfillFlags.taskID); err != nil {
+		return err
+	}
+	if err := id.DecodeFromString(taskBackfillFlags.backfillID); err != nil {
+		return err
+	}
+
+	client, err := newHTTPClient()
+	if err != nil {
+		return err
+	}
+	s := &http.TaskService{
+		Client: client,
+	}
+
+	b, err := s.CancelBackfill(context.Background(), taskID, id)
+	if err != nil {
+		return err
+	}
+
+	return printBackfills(cmd.OutOrStdout(), []*influxdb.Backfill{b})
+}
+
+func printBackfills(w io.Writer, backfills []*influxdb.Backfill) error {
+	if taskPrintFlags.json {
+		return writeJSON(w, backfills)
+	}
+
+	tabW := internal.NewTabWriter(w)
+	defer tabW.Flush()
+
+	tabW.HideHeaders(taskPrintFlags.hideHeaders)
+
+	tabW.WriteHeaders(
+		"ID",
+		"TaskID",
+		"Status",
+		"Start",
+		"End",
+		"Total",
+		"Completed",
+		"Failed",
+		"Error",
+	)
+
+	for _, b := range backfills {
+		tabW.Write(map[string]interface{}{
+			"ID":        b.ID,
+			"TaskID":    b.TaskID,
+			"Status":    b.Status,
+			"Start":     b.Start.Format(time.RFC3339),
+			"End":       b.End.Format(time.RFC3339),
+			"Total":     b.Total,
+			"Completed": b.Completed,
+			"Failed":    b.Failed,
+			"Error":     b.Error,
+		})
+	}
+
+	return nil
+}
@@ -3,9 +3,11 @@ package launcher
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
 	nethttp "net/http"
 	_ "net/http/pprof" // needed to add pprof to our binary.
@@ -17,6 +19,7 @@ import (
 
 	"github.com/influxdata/flux"
 	platform "github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/audit"
 	"github.com/influxdata/influxdb/v2/authorization"
 	"github.com/influxdata/influxdb/v2/authorizer"
 	"github.com/influxdata/influxdb/v2/bolt"
@@ -47,26 +50,37 @@ import (
 	"github.com/influxdata/influxdb/v2/label"
 	influxlogger "github.com/influxdata/influxdb/v2/logger"
 	"github.com/influxdata/influxdb/v2/nats"
+	"github.com/influxdata/influxdb/v2/notification/alert"
+	"github.com/influxdata/influxdb/v2/notification/delivery"
 	endpointservice "github.com/influxdata/influxdb/v2/notification/endpoint/service"
+	"github.com/influxdata/influxdb/v2/notification/messagetemplate"
 	ruleservice "github.com/influxdata/influxdb/v2/notification/rule/service"
+	"github.com/influxdata/influxdb/v2/notification/silence"
+	"github.com/influxdata/influxdb/v2/notification/smtp"
 	"github.com/influxdata/influxdb/v2/pkger"
+	"github.com/influxdata/influxdb/v2/predicate"
 	infprom "github.com/influxdata/influxdb/v2/prometheus"
 	"github.com/influxdata/influxdb/v2/query"
 	"github.com/influxdata/influxdb/v2/query/control"
 	"github.com/influxdata/influxdb/v2/query/fluxlang"
 	"github.com/influxdata/influxdb/v2/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/v2/quota"
+	"github.com/influxdata/influxdb/v2/ratelimit"
 	"github.com/influxdata/influxdb/v2/secret"
 	"github.com/influxdata/influxdb/v2/session"
 	"github.com/influxdata/influxdb/v2/snowflake"
 	"github.com/influxdata/influxdb/v2/source"
 	"github.com/influxdata/influxdb/v2/storage"
 	storageflux "github.com/influxdata/influxdb/v2/storage/flux"
+	"github.com/influxdata/influxdb/v2/storage/reads"
 	"github.com/influxdata/influxdb/v2/storage/readservice"
 	taskbackend "github.com/influxdata/influxdb/v2/task/backend"
 	"github.com/influxdata/influxdb/v2/task/backend/coordinator"
 	"github.com/influxdata/influxdb/v2/task/backend/executor"
 	"github.com/influxdata/influxdb/v2/task/backend/middleware"
 	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
+	"github.com/influxdata/influxdb/v2/task/backfill"
+	"github.com/influxdata/influxdb/v2/task/trigger"
 	telegrafservice "github.com/influxdata/influxdb/v2/telegraf/service"
 	"github.com/influxdata/influxdb/v2/telemetry"
 	"github.com/influxdata/influxdb/v2/tenant"
@@ -265,6 +279,74 @@ func launcherOpts(l *Launcher) []cli.Opt {
 			Default: false,
 			Desc:    "disables automatically extending session ttl on request",
 		},
+		{
+			DestP:   &l.auditLogEnabled,
+			Flag:    "audit-log-enabled",
+			Default: false,
+			Desc:    "record every change of a resource and every failed mutating API request in the audit log, readable at /api/v2/audit",
+		},
+		{
+			DestP: &l.auditTrustedProxies,
+			Flag:  "audit-trusted-proxies",
+			Desc:  "IP addresses or CIDR ranges of the proxies whose X-Forwarded-For and X-Real-IP headers name the source address of audit events",
+		},
+		{
+			DestP:   &l.auditLogRetention,
+			Flag:    "audit-log-retention",
+			Default: audit.DefaultRetention,
+			Desc:    "how long events of the audit log are kept. 0 means they are kept forever",
+		},
+		{
+			DestP:   &l.alertRetention,
+			Flag:    "alert-retention",
+			Default: alert.DefaultResolvedRetention,
+			Desc:    "how long resolved alerts of checks are kept. 0 means they are kept forever",
+		},
+		{
+			DestP: &l.rateLimits.Token.WriteRequestsPerSecond,
+			Flag:  "rate-limit-token-write-requests",
+			Desc:  "maximum number of write requests per second per token, 0 is unlimited",
+		},
+		{
+			DestP: &l.rateLimits.Token.WriteBytesPerSecond,
+			Flag:  "rate-limit-token-write-bytes",
+			Desc:  "maximum number of bytes written per second per token, 0 is unlimited",
+		},
+		{
+			DestP: &l.rateLimits.Token.ConcurrentQueries,
+			Flag:  "rate-limit-token-queries",
+			Desc:  "maximum number of concurrent queries per token, 0 is unlimited",
+		},
+		{
+			DestP: &l.rateLimits.User.WriteRequestsPerSecond,
+			Flag:  "rate-limit-user-write-requests",
+			Desc:  "maximum number of write requests per second per user, 0 is unlimited",
+		},
+		{
+			DestP: &l.rateLimits.User.WriteBytesPerSecond,
+			Flag:  "rate-limit-user-write-bytes",
+			Desc:  "maximum number of bytes written per second per user, 0 is unlimited",
+		},
+		{
+			DestP: &l.rateLimits.User.ConcurrentQueries,
+			Flag:  "rate-limit-user-queries",
+			Desc:  "maximum number of concurrent queries per user, 0 is unlimited",
+		},
+		{
+			DestP: &l.rateLimits.Org.WriteRequestsPerSecond,
+			Flag:  "rate-limit-org-write-requests",
+			Desc:  "maximum number of write requests per second per organization, 0 is unlimited",
+		},
+		{
+			DestP: &l.rateLimits.Org.WriteBytesPerSecond,
+			Flag:  "rate-limit-org-write-bytes",
+			Desc:  "maximum number of bytes written per second per organization, 0 is unlimited",
+		},
+		{
+			DestP: &l.rateLimits.Org.ConcurrentQueries,
+			Flag:  "rate-limit-org-queries",
+			Desc:  "maximum number of concurrent queries per organization, 0 is unlimited",
+		},
 		{
 			DestP: &vaultConfig.Address,
 			Flag:  "vault-addr",
@@ -339,12 +421,76 @@ func launcherOpts(l *Launcher) []cli.Opt {
 			Default: false,
 			Desc:    "Restrict accept ciphers to: ECDHE_RSA_WITH_AES_256_GCM_SHA384, ECDHE_RSA_WITH_AES_256_CBC_SHA, RSA_WITH_AES_256_GCM_SHA384, RSA_WITH_AES_256_CBC_SHA",
 		},
+		{
+			DestP:   &l.httpTLSClientCA,
+			Flag:    "tls-client-ca",
+			Default: "",
+			Desc:    "PEM-encoded certificate authorities used to verify client certificates, enables mutual TLS",
+		},
+		{
+			DestP:   &l.httpTLSClientCertRequired,
+			Flag:    "tls-client-cert-required",
+			Default: false,
+			Desc:    "reject TLS connections that do not present a valid client certificate",
+		},
+		{
+			DestP: &l.httpTLSClientCertAuthorizations,
+			Flag:  "tls-client-cert-authorizations",
+			Desc:  "authorization IDs requests presenting a verified client certificate are authenticated with, keyed by certificate identity: cn:<common name>, dns:<SAN>, uri:<SAN> or email:<SAN>",
+		},
 		{
 			DestP:   &l.noTasks,
 			Flag:    "no-tasks",
 			Default: false,
 			Desc:    "disables the task scheduler",
 		},
+		{
+			DestP:   &l.taskRetryBackoff,
+			Flag:    "task-retry-backoff",
+			Default: executor.DefaultRetryBackoff,
+			Desc:    "delay before the first retry of a failed task run, doubling with every following attempt",
+		},
+		{
+			DestP:   &l.taskMaxRetryBackoff,
+			Flag:    "task-retry-max-backoff",
+			Default: executor.DefaultMaxRetryBackoff,
+			Desc:    "longest delay between retries of a failed task run",
+		},
+		{
+			DestP:   &l.taskRunTimeout,
+			Flag:    "task-run-timeout",
+			Default: time.Duration(0),
+			Desc:    "how long a task run may take before it is canceled, for tasks without the timeout option. 0 means no timeout",
+		},
+		{
+			DestP:   &l.taskCatchupConcurrency,
+			Flag:    "task-catchup-concurrency",
+			Default: 16,
+			Desc:    "most runs of tasks catching up on runs missed while they weren't scheduled executing at once. 0 means no limit",
+		},
+		{
+			DestP:   &l.taskOrgLimits.Weight,
+			Flag:    "task-org-weight",
+			Default: 1,
+			Desc:    "share of the task workers an organization gets relative to the other organizations with runs waiting",
+		},
+		{
+			DestP:   &l.taskOrgLimits.MaxConcurrency,
+			Flag:    "task-org-max-concurrency",
+			Default: 0,
+			Desc:    "most task runs of an organization executing at once. 0 means no limit",
+		},
+		{
+			DestP:   &l.taskOrgLimits.QueueDepth,
+			Flag:    "task-org-queue-depth",
+			Default: 0,
+			Desc:    "most task runs of an organization waiting for a worker, further runs fail. 0 means no limit",
+		},
+		{
+			DestP: &l.taskOrgOverrides,
+			Flag:  "task-org-limits",
+			Desc:  "task limits of organizations overriding the defaults, keyed by organization ID, as weight:max-concurrency:queue-depth",
+		},
 		{
 			DestP:   &l.concurrencyQuota,
 			Flag:    "query-concurrency",
@@ -490,6 +636,11 @@ type Launcher struct {
 	testingAlwaysAllowSetup bool
 	sessionLength           int // in minutes
 	sessionRenewDisabled    bool
+	auditLogEnabled         bool
+	auditTrustedProxies     []string
+	auditLogRetention       time.Duration
+	alertRetention          time.Duration
+	rateLimits              ratelimit.Config
 
 	logLevel          string
 	tracingType       string
@@ -530,13 +681,23 @@ type Launcher struct {
 	httpTLSMinVersion    string
 	httpTLSStrictCiphers bool
 
+	httpTLSClientCA                 string
+	httpTLSClientCertRequired       bool
+	httpTLSClientCertAuthorizations map[string]string
+
 	natsServer *nats.Server
 	natsPort   int
 
-	noTasks            bool
-	scheduler          stoppingScheduler
-	executor           *executor.Executor
-	taskControlService taskbackend.TaskControlService
+	noTasks                bool
+	taskRetryBackoff       time.Duration
+	taskMaxRetryBackoff    time.Duration
+	taskRunTimeout         time.Duration
+	taskCatchupConcurrency int
+	taskOrgLimits          executor.OrgLimits
+	taskOrgOverrides       map[string]string
+	scheduler              stoppingScheduler
+	executor               *executor.Executor
+	taskControlService     taskbackend.TaskControlService
 
 	jaegerTracerCloser io.Closer
 	log                *zap.Logger
@@ -621,6 +782,7 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 	m.log.Info("Stopping", zap.String("service", "task"))
 
 	m.scheduler.Stop()
+	m.executor.Close()
 
 	m.log.Info("Stopping", zap.String("service", "nats"))
 	m.natsServer.Close()
@@ -774,6 +936,20 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return err
 	}
 
+	// every change of a resource is recorded in the audit log by the store of
+	// the services, in the transaction that makes it. Events are deleted once
+	// past their retention.
+	auditSvc := audit.NewService(m.kvStore, audit.WithRetention(m.auditLogRetention))
+	if m.auditLogEnabled {
+		m.kvStore = audit.NewStore(m.kvStore, auditSvc)
+
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			auditSvc.RunRetention(ctx, m.log.With(zap.String("service", "audit")))
+		}()
+	}
+
 	m.reg = prom.NewRegistry(m.log.With(zap.String("service", "prom_registry")))
 	m.reg.MustRegister(
 		prometheus.NewGoCollector(),
@@ -781,11 +957,16 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	)
 	m.reg.MustRegister(m.boltClient)
 
-	tenantStore := tenant.NewStore(m.kvStore)
+	// the resource quotas of organizations are checked by the stores in the
+	// transaction that creates a resource, they share the kv store of the quotas.
+	quotaSvc := quota.NewService(m.kvStore)
+
+	tenantStore := tenant.NewStore(m.kvStore, tenant.WithBucketLimit(quotaSvc.BucketLimit()))
 	ts := tenant.NewSystem(tenantStore, m.log.With(zap.String("store", "new")), m.reg, metric.WithSuffix("new"))
 
 	serviceConfig := kv.ServiceConfig{
 		FluxLanguageService: fluxlang.DefaultService,
+		TaskLimit:           quotaSvc.TaskLimit(),
 	}
 
 	m.kvService = kv.NewService(m.log.With(zap.String("store", "kv")), m.kvStore, ts, serviceConfig)
@@ -885,9 +1066,60 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		restoreService platform.RestoreService = m.engine
 	)
 
+	// reads of restricted authorizations are narrowed down to the series
+	// that match their permission restrictions.
+	findRestrictions := func(ctx context.Context, orgID, bucketID platform.ID) (reads.Restrictions, error) {
+		rs, err := predicate.RestrictionsFromContext(ctx, platform.ReadAction, orgID, bucketID)
+		if err != nil || rs == nil {
+			return nil, err
+		}
+		return rs, nil
+	}
+
+	// the series cardinality quota of organizations is enforced on every
+	// write of their data, through the API, by flux and by scrapers. The run
+	// logs of tasks are written to the engine, they are not held back.
+	pointsWriter = quota.NewPointsWriter(pointsWriter, quotaSvc, ts.BucketService, m.engine)
+
+	// every write of data, through the API, by flux and by scrapers, runs the
+	// tasks triggered by it. The tasks are matched once the task stack runs.
+	var triggerSvc *trigger.Service
+	if !m.noTasks {
+		triggerSvc = trigger.NewService(m.log.With(zap.String("service", "task-trigger")), ts.BucketService)
+		pointsWriter = storage.NewNotifyingPointsWriter(pointsWriter, triggerSvc)
+	}
+
+	storageStore := storage2.NewStore(m.engine.TSDBStore(), m.engine.MetaClient())
+	storageStore.FindRestrictions = findRestrictions
+
+	// the alerts of checks are tracked from the statuses written by check
+	// tasks to the _monitoring bucket about checks of their organization.
+	// Acknowledging alerts regenerates the notification rule tasks that compile
+	// them, the check and notification rule services are created further down.
+	// Resolved alerts are deleted once past their retention.
+	var (
+		ruleSyncer  alert.RuleSyncer
+		checkFinder platform.CheckService
+	)
+	kvAlertSvc := alert.NewService(m.kvStore, alert.WithResolvedRetention(m.alertRetention))
+	alertSvc := alert.NewSyncingService(kvAlertSvc, alert.RuleSyncerFunc(func(ctx context.Context, a *platform.Alert) error {
+		return ruleSyncer.SyncAlertRuleTasks(ctx, a)
+	}))
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		kvAlertSvc.RunRetention(ctx, m.log.With(zap.String("service", "alerts")))
+	}()
+
+	// the failed deliveries of notification rules with a retry policy are
+	// recorded from the notifications written by their tasks.
+	kvDeliverySvc := delivery.NewService(m.kvStore)
 	deps, err := influxdb.NewDependencies(
-		storageflux.NewReader(storage2.NewStore(m.engine.TSDBStore(), m.engine.MetaClient())),
-		m.engine,
+		storageflux.NewReader(storageStore),
+		delivery.NewPointsWriter(m.log.With(zap.String("service", "deliveries")),
+			alert.NewPointsWriter(m.log.With(zap.String("service", "alerts")), pointsWriter, ts.BucketService, alert.CheckFinderFunc(func(ctx context.Context, id platform.ID) (platform.Check, error) {
+				return checkFinder.FindCheckByID(ctx, id)
+			}), alertSvc), ts.BucketService, kvDeliverySvc),
 		authorizer.NewBucketService(ts.BucketService),
 		authorizer.NewOrgService(ts.OrganizationService),
 		authorizer.NewSecretService(secretSvc),
@@ -915,7 +1147,14 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	m.reg.MustRegister(m.queryController.PrometheusCollectors()...)
 
 	var storageQueryService = readservice.NewProxyQueryService(m.queryController)
-	var taskSvc platform.TaskService
+
+	var (
+		taskSvc      platform.TaskService
+		backfillSvc  platform.BackfillService
+		analyticsSvc platform.TaskAnalyticsService
+		dryRunSvc    platform.TaskDryRunService
+		revisionSvc  platform.TaskRevisionService
+	)
 	{
 		// create the task stack
 		combinedTaskService := taskbackend.NewAnalyticalStorage(
@@ -923,10 +1162,16 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			m.kvService,
 			ts.BucketService,
 			m.kvService,
-			pointsWriter,
+			m.engine,
 			query.QueryServiceBridge{AsyncQueryService: m.queryController},
 		)
 
+		orgLimits, err := executor.ParseOrgLimits(m.taskOrgOverrides)
+		if err != nil {
+			m.log.Error("Failed to parse task organization limits", zap.Error(err))
+			return err
+		}
+
 		executor, executorMetrics := executor.NewExecutor(
 			m.log.With(zap.String("service", "task-executor")),
 			query.QueryServiceBridge{AsyncQueryService: m.queryController},
@@ -934,6 +1179,13 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			combinedTaskService,
 			combinedTaskService,
 			executor.WithFlagger(m.flagger),
+			executor.WithFluxLanguageService(fluxlang.DefaultService),
+			executor.WithRetryBackoff(m.taskRetryBackoff, m.taskMaxRetryBackoff),
+			executor.WithRunTimeout(m.taskRunTimeout),
+			executor.WithOrgLimits(m.taskOrgLimits, orgLimits),
+			// only the tasks of email notification rules send mail, tasks
+			// created through the API all have the system type
+			executor.WithTaskTypeContext(smtp.TaskType, smtp.WithDelivery),
 		)
 		m.executor = executor
 		m.reg.MustRegister(executorMetrics.PrometheusCollectors()...)
@@ -955,6 +1207,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 						zap.Time("scheduledAt", scheduledAt),
 						zap.Error(err))
 				}),
+				scheduler.WithMaxCatchupWorkers(m.taskCatchupConcurrency),
 			)
 			if err != nil {
 				m.log.Fatal("could not start task scheduler", zap.Error(err))
@@ -970,8 +1223,10 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			sch,
 			executor)
 
-		taskSvc = middleware.New(combinedTaskService, taskCoord)
+		coordTaskSvc := middleware.New(combinedTaskService, taskCoord)
+		taskSvc = coordTaskSvc
 		m.taskControlService = combinedTaskService
+		analyticsSvc = combinedTaskService
 		if err := taskbackend.TaskNotifyCoordinatorOfExisting(
 			ctx,
 			taskSvc,
@@ -984,6 +1239,28 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			coordLogger); err != nil {
 			m.log.Error("Failed to resume existing tasks", zap.Error(err))
 		}
+
+		bfs := backfill.NewService(
+			m.log.With(zap.String("service", "task-backfill")),
+			m.kvStore,
+			combinedTaskService,
+			executor,
+			fluxlang.DefaultService,
+		)
+		if err := bfs.Resume(ctx); err != nil {
+			m.log.Error("Failed to resume backfills", zap.Error(err))
+		}
+		backfillSvc = backfill.NewAuthorizedService(bfs, m.kvService)
+		dryRunSvc = authorizer.NewTaskDryRunService(executor, m.kvService)
+		revisionSvc = authorizer.NewTaskRevisionService(middleware.NewRevisionService(m.kvService, coordTaskSvc), m.kvService)
+
+		if triggerSvc != nil {
+			m.wg.Add(1)
+			go func() {
+				defer m.wg.Done()
+				triggerSvc.Run(ctx, combinedTaskService, executor)
+			}()
+		}
 	}
 
 	dbrpSvc := dbrp.NewAuthorizedService(dbrp.NewService(ctx, authorizer.NewBucketService(ts.BucketService), m.kvStore))
@@ -992,9 +1269,10 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	m.reg.MustRegister(cm.PrometheusCollectors()...)
 
 	mapper := &iqlcoordinator.LocalShardMapper{
-		MetaClient: metaClient,
-		TSDBStore:  m.engine.TSDBStore(),
-		DBRP:       dbrpSvc,
+		MetaClient:       metaClient,
+		TSDBStore:        m.engine.TSDBStore(),
+		DBRP:             dbrpSvc,
+		FindRestrictions: findRestrictions,
 	}
 
 	m.log.Info("Configuring InfluxQL statement executor (zeros indicate unlimited).",
@@ -1015,11 +1293,17 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	qe.StatementExecutor = se
 	qe.StatementNormalizer = se
 
-	var checkSvc platform.CheckService
+	kvMessageTemplateSvc := messagetemplate.NewService(m.kvStore)
+
+	var (
+		checkSvc   platform.CheckService
+		kvCheckSvc *checks.Service
+	)
 	{
 		coordinator := coordinator.NewCoordinator(m.log, m.scheduler, m.executor)
-		checkSvc = checks.NewService(m.log.With(zap.String("svc", "checks")), m.kvStore, ts.OrganizationService, m.kvService)
-		checkSvc = middleware.NewCheckService(checkSvc, m.kvService, coordinator)
+		kvCheckSvc = checks.NewService(m.log.With(zap.String("svc", "checks")), m.kvStore, ts.OrganizationService, m.kvService, checks.WithMessageTemplateService(kvMessageTemplateSvc), checks.WithCheckLimit(quotaSvc.CheckLimit()))
+		checkSvc = middleware.NewCheckService(kvCheckSvc, m.kvService, coordinator)
+		checkFinder = kvCheckSvc
 	}
 
 	var notificationEndpointSvc platform.NotificationEndpointService
@@ -1027,17 +1311,47 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		notificationEndpointSvc = endpointservice.New(endpointservice.NewStore(m.kvStore), secretSvc)
 	}
 
-	var notificationRuleSvc platform.NotificationRuleStore
+	var (
+		notificationRuleSvc platform.NotificationRuleStore
+		silenceSvc          platform.SilenceService
+		messageTemplateSvc  platform.MessageTemplateService
+		deliverySvc         platform.NotificationDeliveryService
+		notificationTestSvc platform.NotificationTestService
+	)
 	{
 		coordinator := coordinator.NewCoordinator(m.log, m.scheduler, m.executor)
-		notificationRuleSvc, err = ruleservice.New(m.log, m.kvStore, m.kvService, ts.OrganizationService, notificationEndpointSvc)
+		kvSilenceSvc := silence.NewService(m.kvStore)
+		ruleSvc, err := ruleservice.New(m.log, m.kvStore, m.kvService, ts.OrganizationService, notificationEndpointSvc,
+			ruleservice.WithSilenceService(kvSilenceSvc),
+			ruleservice.WithAlertService(alertSvc),
+			ruleservice.WithMessageTemplateService(kvMessageTemplateSvc),
+			ruleservice.WithNotificationRuleLimit(quotaSvc.NotificationRuleLimit()),
+		)
 		if err != nil {
 			return err
 		}
+		ruleSyncer = ruleSvc
+
+		// silences are compiled into the notification rule tasks, which are
+		// regenerated whenever the silences of their organization change.
+		silenceSvc = silence.NewSyncingService(kvSilenceSvc, ruleSvc)
+
+		// message templates are compiled into the check and notification rule
+		// tasks that reference them.
+		messageTemplateSvc = messagetemplate.NewSyncingService(kvMessageTemplateSvc, kvCheckSvc, ruleSvc)
+
+		// failed deliveries are resent by the flux of the current version of
+		// their notification rule, run by the query controller as tasks are.
+		notifier := delivery.NewFluxNotifier(query.QueryServiceBridge{AsyncQueryService: m.queryController}, fluxlang.DefaultService, ts.UserService)
+		deliverySvc = delivery.NewResendingService(kvDeliverySvc, ruleSvc, notificationEndpointSvc, notifier)
+
+		// test notifications are sent the same way.
+		tester := delivery.NewTester(ruleSvc, notificationEndpointSvc, kvMessageTemplateSvc, notifier)
+		notificationTestSvc = authorizer.NewNotificationTestService(tester, ruleSvc, notificationEndpointSvc)
 
 		// tasks service notification middleware which keeps task service up to date
 		// with persisted changes to notification rules.
-		notificationRuleSvc = middleware.NewNotificationRuleStore(notificationRuleSvc, m.kvService, coordinator)
+		notificationRuleSvc = middleware.NewNotificationRuleStore(ruleSvc, m.kvService, coordinator)
 	}
 
 	var telegrafSvc platform.TelegrafConfigStore
@@ -1127,6 +1441,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	ts.BucketService = storage.NewBucketService(m.log, ts.BucketService, m.engine)
 	ts.BucketService = dbrp.NewBucketService(m.log, ts.BucketService, dbrpSvc)
+	ts.BucketService = quota.NewBucketService(ts.BucketService, quotaSvc)
 
 	var onboardOpts []tenant.OnboardServiceOptionFn
 	if m.testingAlwaysAllowSetup {
@@ -1166,7 +1481,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		dashboardLogSvc platform.DashboardOperationLogService
 	)
 	{
-		dashboardService := dashboards.NewService(m.kvStore, m.kvService)
+		dashboardService := dashboards.NewService(m.kvStore, m.kvService, dashboards.WithDashboardLimit(quotaSvc.DashboardLimit()))
 		dashboardSvc = dashboardService
 		dashboardLogSvc = dashboardService
 	}
@@ -1190,13 +1505,20 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		NotificationRuleFinder:     notificationRuleSvc,
 	}
 
+	clientCertAuths, err := http.NewClientCertAuthorizations(m.httpTLSClientCertAuthorizations)
+	if err != nil {
+		m.log.Error("Failed to parse client certificate authorizations", zap.Error(err))
+		return err
+	}
+
 	m.apibackend = &http.APIBackend{
-		AssetsPath:           m.assetsPath,
-		HTTPErrorHandler:     kithttp.ErrorHandler(0),
-		Logger:               m.log,
-		SessionRenewDisabled: m.sessionRenewDisabled,
-		NewBucketService:     source.NewBucketService,
-		NewQueryService:      source.NewQueryService,
+		AssetsPath:               m.assetsPath,
+		HTTPErrorHandler:         kithttp.ErrorHandler(0),
+		Logger:                   m.log,
+		SessionRenewDisabled:     m.sessionRenewDisabled,
+		ClientCertAuthorizations: clientCertAuths,
+		NewBucketService:         source.NewBucketService,
+		NewQueryService:          source.NewQueryService,
 		PointsWriter: &storage.LoggingPointsWriter{
 			Underlying:    pointsWriter,
 			BucketFinder:  ts.BucketService,
@@ -1230,9 +1552,14 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		FluxService:                     storageQueryService,
 		FluxLanguageService:             fluxlang.DefaultService,
 		TaskService:                     taskSvc,
+		BackfillService:                 backfillSvc,
+		TaskAnalyticsService:            analyticsSvc,
+		TaskDryRunService:               dryRunSvc,
+		TaskRevisionService:             revisionSvc,
 		TelegrafService:                 telegrafSvc,
 		NotificationRuleStore:           notificationRuleSvc,
 		NotificationEndpointService:     notificationEndpointSvc,
+		NotificationTestService:         notificationTestSvc,
 		CheckService:                    checkSvc,
 		ScraperTargetStoreService:       scraperTargetSvc,
 		ChronografService:               chronografSvc,
@@ -1246,6 +1573,27 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		FlagsHandler:                    feature.NewFlagsHandler(kithttp.ErrorHandler(0), feature.ByKey),
 	}
 
+	if m.auditLogEnabled {
+		trustedProxies, err := audit.ParseTrustedProxies(m.auditTrustedProxies)
+		if err != nil {
+			m.log.Error("Failed to parse audit trusted proxies", zap.Error(err))
+			return err
+		}
+		m.apibackend.AuditService = auditSvc
+		m.apibackend.AuditTrustedProxies = trustedProxies
+	}
+
+	// the limiter is installed even without configured limits, the quotas of
+	// organizations may limit their write rate.
+	m.apibackend.RateLimiter = ratelimit.NewLimiter(m.rateLimits)
+	m.reg.MustRegister(m.apibackend.RateLimiter.PrometheusCollectors()...)
+
+	quotaLimiterSvc, err := quota.NewLimiterService(ctx, quotaSvc, m.apibackend.RateLimiter)
+	if err != nil {
+		m.log.Error("Failed to apply organization quotas to the rate limiter", zap.Error(err))
+		return err
+	}
+
 	m.reg.MustRegister(m.apibackend.PrometheusCollectors()...)
 
 	authAgent := new(authorizer.AuthAgent)
@@ -1364,6 +1712,13 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		)
 	}
 
+	auditHTTPServer := audit.NewHTTPHandler(m.log.With(zap.String("handler", "audit")), audit.NewAuthorizedService(auditSvc))
+	quotaHTTPServer := quota.NewHTTPHandler(m.log.With(zap.String("handler", "quota")), quota.NewAuthorizedService(quotaLimiterSvc))
+	silenceHTTPServer := silence.NewHTTPHandler(m.log.With(zap.String("handler", "silence")), silence.NewAuthorizedService(silenceSvc))
+	alertHTTPServer := alert.NewHTTPHandler(m.log.With(zap.String("handler", "alert")), alert.NewAuthorizedService(alertSvc))
+	messageTemplateHTTPServer := messagetemplate.NewHTTPHandler(m.log.With(zap.String("handler", "message_template")), messagetemplate.NewAuthorizedService(messageTemplateSvc))
+	deliveryHTTPServer := delivery.NewHTTPHandler(m.log.With(zap.String("handler", "notification_delivery")), delivery.NewAuthorizedService(deliverySvc))
+
 	{
 		platformHandler := http.NewPlatformHandler(m.apibackend,
 			http.WithResourceHandler(stacksHTTPServer),
@@ -1379,6 +1734,12 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			http.WithResourceHandler(bucketHTTPServer),
 			http.WithResourceHandler(v1AuthHTTPServer),
 			http.WithResourceHandler(dashboardServer),
+			http.WithResourceHandler(auditHTTPServer),
+			http.WithResourceHandler(quotaHTTPServer),
+			http.WithResourceHandler(silenceHTTPServer),
+			http.WithResourceHandler(alertHTTPServer),
+			http.WithResourceHandler(messageTemplateHTTPServer),
+			http.WithResourceHandler(deliveryHTTPServer),
 		)
 
 		httpLogger := m.log.With(zap.String("service", "http"))
@@ -1456,6 +1817,29 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			MinVersion:               tlsMinVersion,
 			CipherSuites:             cipherConfig,
 		}
+
+		if m.httpTLSClientCA != "" {
+			pem, err := ioutil.ReadFile(m.httpTLSClientCA)
+			if err != nil {
+				m.log.Error("Failed to read client certificate authorities", zap.Error(err))
+				return err
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				err := fmt.Errorf("no certificates found in %s", m.httpTLSClientCA)
+				m.log.Error("Failed to load client certificate authorities", zap.Error(err))
+				return err
+			}
+			m.httpServer.TLSConfig.ClientCAs = pool
+			m.httpServer.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+			if m.httpTLSClientCertRequired {
+				m.httpServer.TLSConfig.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+		}
+	} else if m.httpTLSClientCA != "" {
+		err := errors.New("tls-client-ca requires tls-cert and tls-key")
+		m.log.Error("Failed to configure mutual TLS", zap.Error(err))
+		return err
 	}
 
 	if addr, ok := ln.Addr().(*net.TCPAddr); ok {
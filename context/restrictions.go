@@ -0,0 +1,17 @@
+package context
+
+import "context"
+
+const unrestrictedCtxKey contextKey = "influx/unrestricted/v1"
+
+// SetUnrestricted marks context as exempt from permission restrictions, for
+// trusted internal callers that do not act on behalf of its authorizer.
+func SetUnrestricted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unrestrictedCtxKey, true)
+}
+
+// IsUnrestricted reports whether context is exempt from permission restrictions.
+func IsUnrestricted(ctx context.Context) bool {
+	ok, _ := ctx.Value(unrestrictedCtxKey).(bool)
+	return ok
+}
@@ -0,0 +1,20 @@
+package context
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+const taskCtxKey contextKey = "influx/task/v1"
+
+// SetTaskID sets the task whose run makes the requests on context.
+func SetTaskID(ctx context.Context, id influxdb.ID) context.Context {
+	return context.WithValue(ctx, taskCtxKey, id)
+}
+
+// GetTaskID retrieves the task whose run makes the requests from context, if any.
+func GetTaskID(ctx context.Context) (influxdb.ID, bool) {
+	id, ok := ctx.Value(taskCtxKey).(influxdb.ID)
+	return id, ok
+}
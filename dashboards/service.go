@@ -48,16 +48,32 @@ type Service struct {
 
 	IDGenerator   influxdb.IDGenerator
 	TimeGenerator influxdb.TimeGenerator
+
+	limit kv.CreateLimit
+}
+
+// Option configures the dashboard service.
+type Option func(*Service)
+
+// WithDashboardLimit limits the number of dashboards of an organization.
+func WithDashboardLimit(limit kv.CreateLimit) Option {
+	return func(s *Service) {
+		s.limit = limit
+	}
 }
 
 // NewService constructs and configures a new dashboard service.
-func NewService(store kv.Store, opLog OpLogStore) *Service {
-	return &Service{
+func NewService(store kv.Store, opLog OpLogStore, opts ...Option) *Service {
+	s := &Service{
 		kv:            store,
 		opLog:         opLog,
 		IDGenerator:   snowflake.NewIDGenerator(),
 		TimeGenerator: influxdb.RealTimeGenerator{},
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // FindDashboardByID retrieves a dashboard by id.
@@ -240,6 +256,31 @@ func (s *Service) findOrganizationDashboards(ctx context.Context, tx kv.Tx, orgI
 	return ds, nil
 }
 
+// countOrganizationDashboards returns the number of dashboards of the organization.
+func (s *Service) countOrganizationDashboards(ctx context.Context, tx kv.Tx, orgID influxdb.ID) (int, error) {
+	idx, err := tx.Bucket(orgDashboardIndex)
+	if err != nil {
+		return 0, err
+	}
+
+	prefix, err := orgID.Encode()
+	if err != nil {
+		return 0, err
+	}
+
+	cur, err := idx.ForwardCursor(prefix, kv.WithCursorPrefix(prefix))
+	if err != nil {
+		return 0, err
+	}
+	defer cur.Close()
+
+	var n int
+	for k, _ := cur.Next(); k != nil; k, _ = cur.Next() {
+		n++
+	}
+	return n, cur.Err()
+}
+
 func decodeOrgDashboardIndexKey(indexKey []byte) (orgID influxdb.ID, dashID influxdb.ID, err error) {
 	if len(indexKey) != 2*influxdb.IDLength {
 		return 0, 0, &influxdb.Error{Code: influxdb.EInternal, Msg: "malformed org dashboard index key (please report this error)"}
@@ -321,6 +362,15 @@ func (s *Service) findDashboards(ctx context.Context, tx kv.Tx, filter influxdb.
 // CreateDashboard creates a influxdb dashboard and sets d.ID.
 func (s *Service) CreateDashboard(ctx context.Context, d *influxdb.Dashboard) error {
 	err := s.kv.Update(ctx, func(tx kv.Tx) error {
+		if s.limit != nil {
+			err := s.limit(ctx, tx, d.OrganizationID, func() (int, error) {
+				return s.countOrganizationDashboards(ctx, tx, d.OrganizationID)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
 		d.ID = s.IDGenerator.ID()
 
 		for _, cell := range d.Cells {
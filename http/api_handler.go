@@ -2,11 +2,13 @@ package http
 
 import (
 	"context"
+	"net"
 	"net/http"
 
 	"github.com/go-chi/chi"
 	"github.com/influxdata/httprouter"
 	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/audit"
 	"github.com/influxdata/influxdb/v2/authorizer"
 	"github.com/influxdata/influxdb/v2/chronograf/server"
 	"github.com/influxdata/influxdb/v2/dbrp"
@@ -16,6 +18,7 @@ import (
 	"github.com/influxdata/influxdb/v2/kit/prom"
 	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
 	"github.com/influxdata/influxdb/v2/query"
+	"github.com/influxdata/influxdb/v2/ratelimit"
 	"github.com/influxdata/influxdb/v2/storage"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -33,6 +36,8 @@ type APIBackend struct {
 	Logger     *zap.Logger
 	influxdb.HTTPErrorHandler
 	SessionRenewDisabled bool
+	// ClientCertAuthorizations maps verified client certificates to authorizations.
+	ClientCertAuthorizations ClientCertAuthorizations
 	// MaxBatchSizeBytes is the maximum number of bytes which can be written
 	// in a single points batch
 	MaxBatchSizeBytes int64
@@ -61,6 +66,9 @@ type APIBackend struct {
 	DeleteService                   influxdb.DeleteService
 	BackupService                   influxdb.BackupService
 	RestoreService                  influxdb.RestoreService
+	AuditService                    influxdb.AuditService
+	AuditTrustedProxies             []*net.IPNet
+	RateLimiter                     *ratelimit.Limiter
 	AuthorizationService            influxdb.AuthorizationService
 	AuthorizerV1                    influxdb.AuthorizerV1
 	OnboardingService               influxdb.OnboardingService
@@ -84,6 +92,10 @@ type APIBackend struct {
 	FluxService                     query.ProxyQueryService
 	FluxLanguageService             influxdb.FluxLanguageService
 	TaskService                     influxdb.TaskService
+	BackfillService                 influxdb.BackfillService
+	TaskAnalyticsService            influxdb.TaskAnalyticsService
+	TaskDryRunService               influxdb.TaskDryRunService
+	TaskRevisionService             influxdb.TaskRevisionService
 	CheckService                    influxdb.CheckService
 	TelegrafService                 influxdb.TelegrafConfigStore
 	ScraperTargetStoreService       influxdb.ScraperTargetStoreService
@@ -94,6 +106,7 @@ type APIBackend struct {
 	DocumentService                 influxdb.DocumentService
 	NotificationRuleStore           influxdb.NotificationRuleStore
 	NotificationEndpointService     influxdb.NotificationEndpointService
+	NotificationTestService         influxdb.NotificationTestService
 	Flagger                         feature.Flagger
 	FlagsHandler                    http.Handler
 }
@@ -130,6 +143,14 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 		Router: NewBaseChiRouter(kithttp.NewAPI(kithttp.WithLog(b.Logger))),
 	}
 
+	if b.AuditService != nil {
+		h.Use(audit.NewHTTPMiddleware(b.Logger.With(zap.String("middleware", "audit")), b.AuditService, b.AuditTrustedProxies))
+	}
+
+	if b.RateLimiter != nil {
+		h.Use(ratelimit.NewHTTPMiddleware(b.Logger.With(zap.String("middleware", "ratelimit")), b.RateLimiter, b.OrganizationService))
+	}
+
 	b.UserResourceMappingService = authorizer.NewURMService(b.OrgLookupService, b.UserResourceMappingService)
 
 	h.Mount("/api/v2", serveLinksHandler(b.HTTPErrorHandler))
@@ -180,6 +201,11 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	taskHandler := NewTaskHandler(b.Logger, taskBackend)
 	h.Mount(prefixTasks, taskHandler)
 
+	if b.TaskAnalyticsService != nil {
+		taskAnalyticsService := authorizer.NewTaskAnalyticsService(b.TaskAnalyticsService)
+		h.Mount(prefixTaskAnalytics, NewTaskAnalyticsHandler(b.Logger.With(zap.String("handler", "task_analytics")), taskAnalyticsService, b.OrganizationService))
+	}
+
 	telegrafBackend := NewTelegrafBackend(b.Logger.With(zap.String("handler", "telegraf")), b)
 	telegrafBackend.TelegrafService = authorizer.NewTelegrafConfigService(b.TelegrafService, b.UserResourceMappingService)
 	h.Mount(prefixTelegrafPlugins, NewTelegrafHandler(b.Logger, telegrafBackend))
@@ -220,6 +246,7 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 var apiLinks = map[string]interface{}{
 	// when adding new links, please take care to keep this list alphabetical
 	// as this makes it easier to verify values against the swagger document.
+	"audit":          "/api/v2/audit",
 	"authorizations": "/api/v2/authorizations",
 	"backup":         "/api/v2/backup",
 	"buckets":        "/api/v2/buckets",
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"strings"
+
+	platform "github.com/influxdata/influxdb/v2"
+)
+
+// Prefixes of the client certificate identities.
+const (
+	clientCertCommonName = "cn:"
+	clientCertDNS        = "dns:"
+	clientCertURI        = "uri:"
+	clientCertEmail      = "email:"
+)
+
+// ClientCertAuthorizations maps the identities of verified client certificates
+// to the ID of the authorization requests presenting them are made with.
+// Identities are the subject common name or a subject alternative name of the
+// certificate, prefixed with its kind, e.g. "cn:telegraf", "dns:writer.example.com",
+// "uri:spiffe://cluster.local/ns/metrics/sa/writer" or "email:ops@example.com".
+type ClientCertAuthorizations map[string]platform.ID
+
+// NewClientCertAuthorizations parses a mapping of identities to authorization IDs.
+func NewClientCertAuthorizations(m map[string]string) (ClientCertAuthorizations, error) {
+	cas := make(ClientCertAuthorizations, len(m))
+	for identity, v := range m {
+		if !validClientCertIdentity(identity) {
+			return nil, fmt.Errorf("invalid client certificate identity %q: expected %s<common name>, %s<name>, %s<uri> or %s<address>",
+				identity, clientCertCommonName, clientCertDNS, clientCertURI, clientCertEmail)
+		}
+		id, err := platform.IDFromString(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid authorization ID for client certificate identity %q: %v", identity, err)
+		}
+		cas[identity] = *id
+	}
+	return cas, nil
+}
+
+func validClientCertIdentity(identity string) bool {
+	for _, prefix := range []string{clientCertCommonName, clientCertDNS, clientCertURI, clientCertEmail} {
+		if strings.HasPrefix(identity, prefix) && len(identity) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the authorization ID mapped to the first identity of the certificate
+// that has one. Subject alternative names are matched before the common name.
+func (cas ClientCertAuthorizations) find(cert *x509.Certificate) (platform.ID, bool) {
+	for _, identity := range clientCertIdentities(cert) {
+		if id, ok := cas[identity]; ok {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+func clientCertIdentities(cert *x509.Certificate) []string {
+	var identities []string
+	for _, u := range cert.URIs {
+		identities = append(identities, clientCertURI+u.String())
+	}
+	for _, name := range cert.DNSNames {
+		identities = append(identities, clientCertDNS+name)
+	}
+	for _, email := range cert.EmailAddresses {
+		identities = append(identities, clientCertEmail+email)
+	}
+	if cert.Subject.CommonName != "" {
+		identities = append(identities, clientCertCommonName+cert.Subject.CommonName)
+	}
+	return identities
+}
+
+// verifiedClientCert returns the client certificate of the request if it was
+// verified against the client certificate authorities of the server.
+func verifiedClientCert(r *http.Request) (*x509.Certificate, bool) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+	return r.TLS.VerifiedChains[0][0], true
+}
@@ -26,6 +26,10 @@ type AuthenticationHandler struct {
 	TokenParser          *jsonweb.TokenParser
 	SessionRenewDisabled bool
 
+	// ClientCertAuthorizations authenticates requests without a token or
+	// session that present a verified client certificate.
+	ClientCertAuthorizations ClientCertAuthorizations
+
 	// This is only really used for it's lookup method the specific http
 	// handler used to register routes does not matter.
 	noAuthRouter *httprouter.Router
@@ -51,16 +55,21 @@ func (h *AuthenticationHandler) RegisterNoAuthRoute(method, path string) {
 }
 
 const (
-	tokenAuthScheme   = "token"
-	sessionAuthScheme = "session"
+	tokenAuthScheme       = "token"
+	sessionAuthScheme     = "session"
+	certificateAuthScheme = "certificate"
 )
 
-// ProbeAuthScheme probes the http request for the requests for token or cookie session.
+// ProbeAuthScheme probes the http request for the requests for token, cookie session
+// or verified client certificate. Tokens and sessions take precedence over certificates.
 func ProbeAuthScheme(r *http.Request) (string, error) {
 	_, tokenErr := GetToken(r)
 	_, sessErr := decodeCookieSession(r.Context(), r)
 
 	if tokenErr != nil && sessErr != nil {
+		if _, ok := verifiedClientCert(r); ok {
+			return certificateAuthScheme, nil
+		}
 		return "", fmt.Errorf("token required")
 	}
 
@@ -96,6 +105,8 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		auth, err = h.extractAuthorization(ctx, r)
 	case sessionAuthScheme:
 		auth, err = h.extractSession(ctx, r)
+	case certificateAuthScheme:
+		auth, err = h.extractClientCertAuthorization(ctx, r)
 	default:
 		// TODO: this error will be nil if it gets here, this should be remedied with some
 		//  sentinel error I'm thinking
@@ -160,6 +171,29 @@ func (h *AuthenticationHandler) extractAuthorization(ctx context.Context, r *htt
 	return h.AuthorizationService.FindAuthorizationByToken(ctx, t)
 }
 
+// extractClientCertAuthorization returns the authorization mapped to the identity
+// of the verified client certificate of the request.
+func (h *AuthenticationHandler) extractClientCertAuthorization(ctx context.Context, r *http.Request) (*platform.Authorization, error) {
+	cert, ok := verifiedClientCert(r)
+	if !ok {
+		return nil, errors.New("client certificate required")
+	}
+
+	id, ok := h.ClientCertAuthorizations.find(cert)
+	if !ok {
+		return nil, fmt.Errorf("no authorization for client certificate %q", cert.Subject)
+	}
+
+	a, err := h.AuthorizationService.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !a.IsActive() {
+		return nil, &platform.Error{Code: platform.EUnauthorized, Msg: "authorization of client certificate is inactive"}
+	}
+	return a, nil
+}
+
 func (h *AuthenticationHandler) extractSession(ctx context.Context, r *http.Request) (*platform.Session, error) {
 	k, err := decodeCookieSession(ctx, r)
 	if err != nil {
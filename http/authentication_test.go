@@ -2,14 +2,18 @@ package http_test
 
 import (
 	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
 	platformhttp "github.com/influxdata/influxdb/v2/http"
 	"github.com/influxdata/influxdb/v2/jsonweb"
 	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
@@ -315,6 +319,109 @@ func TestProbeAuthScheme(t *testing.T) {
 	}
 }
 
+func TestAuthenticationHandler_ClientCert(t *testing.T) {
+	uri, _ := url.Parse("spiffe://cluster.local/ns/metrics/sa/writer")
+	writer := &x509.Certificate{
+		Subject: pkix.Name{CommonName: "writer"},
+		URIs:    []*url.URL{uri},
+	}
+	unknown := &x509.Certificate{
+		Subject: pkix.Name{CommonName: "unknown"},
+	}
+
+	cas, err := platformhttp.NewClientCertAuthorizations(map[string]string{
+		"uri:spiffe://cluster.local/ns/metrics/sa/writer": influxdb.ID(10).String(),
+		"cn:inactive": influxdb.ID(11).String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		want int
+	}{
+		{
+			name: "mapped certificate",
+			cert: writer,
+			want: http.StatusOK,
+		},
+		{
+			name: "unmapped certificate",
+			cert: unknown,
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "inactive authorization",
+			cert: &x509.Certificate{Subject: pkix.Name{CommonName: "inactive"}},
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "no certificate",
+			want: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got influxdb.Authorizer
+			h := platformhttp.NewAuthenticationHandler(zaptest.NewLogger(t), kithttp.ErrorHandler(0))
+			h.ClientCertAuthorizations = cas
+			h.AuthorizationService = &mock.AuthorizationService{
+				FindAuthorizationByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Authorization, error) {
+					status := influxdb.Active
+					if id == 11 {
+						status = influxdb.Inactive
+					}
+					return &influxdb.Authorization{ID: id, Status: status}, nil
+				},
+			}
+			h.UserService = &mock.UserService{
+				FindUserByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.User, error) {
+					return &influxdb.User{}, nil
+				},
+			}
+			h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = icontext.GetAuthorizer(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "https://any.url", nil)
+			if tt.cert != nil {
+				r.TLS.VerifiedChains = [][]*x509.Certificate{{tt.cert}}
+			}
+
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Fatalf("expected status code to be %d got %d", tt.want, w.Code)
+			}
+			if tt.want == http.StatusOK && got.Identifier() != 10 {
+				t.Errorf("unexpected authorizer %v", got)
+			}
+		})
+	}
+}
+
+func TestNewClientCertAuthorizations(t *testing.T) {
+	for _, identity := range []string{"cn:a", "dns:a.example.com", "uri:spiffe://a", "email:a@example.com"} {
+		if _, err := platformhttp.NewClientCertAuthorizations(map[string]string{identity: one.String()}); err != nil {
+			t.Errorf("unexpected error for %q: %v", identity, err)
+		}
+	}
+	for _, m := range []map[string]string{
+		{"a": one.String()},
+		{"cn:": one.String()},
+		{"cn:a": "not an id"},
+	} {
+		if _, err := platformhttp.NewClientCertAuthorizations(m); err == nil {
+			t.Errorf("expected error for %v", m)
+		}
+	}
+}
+
 func TestAuthenticationHandler_NoAuthRoutes(t *testing.T) {
 	type route struct {
 		method string
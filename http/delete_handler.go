@@ -105,7 +105,8 @@ func (h *DeleteHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if pset, err := a.PermissionSet(); err != nil || !pset.Allowed(*p) {
+	pset, err := a.PermissionSet()
+	if err != nil || !pset.Allowed(*p) {
 		h.HandleHTTPError(ctx, &influxdb.Error{
 			Code: influxdb.EForbidden,
 			Op:   "http/handleDelete",
@@ -114,6 +115,16 @@ func (h *DeleteHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// a delete predicate could reach series outside of the restrictions.
+	if rs, _ := pset.Restrictions(*p); rs != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Op:   "http/handleDelete",
+			Msg:  "insufficient permissions to delete: write permission is restricted",
+		}, w)
+		return
+	}
+
 	h.HandleHTTPError(r.Context(), &influxdb.Error{
 		Code: influxdb.ENotImplemented,
 		Op:   "http/handleDelete",
@@ -12,6 +12,8 @@ import (
 	"github.com/influxdata/influxdb/v2/http/points"
 	"github.com/influxdata/influxdb/v2/kit/tracing"
 	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/predicate"
 	"github.com/influxdata/influxdb/v2/storage"
 	"go.uber.org/zap"
 )
@@ -140,6 +142,11 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := checkBucketWriteRestrictions(ctx, bucket.OrgID, bucket.ID, parsed.Points); err != nil {
+		h.HandleHTTPError(ctx, err, sw)
+		return
+	}
+
 	if err := h.PointsWriter.WritePoints(ctx, auth.OrgID, bucket.ID, parsed.Points); err != nil {
 		h.HandleHTTPError(ctx, &influxdb.Error{
 			Code: influxdb.EInternal,
@@ -187,6 +194,33 @@ func checkBucketWritePermissions(auth influxdb.Authorizer, orgID, bucketID influ
 	return nil
 }
 
+// checkBucketWriteRestrictions rejects the batch when the permissions of the
+// request restrict writes to the bucket and a point does not match them.
+func checkBucketWriteRestrictions(ctx context.Context, orgID, bucketID influxdb.ID, pts models.Points) error {
+	rs, err := predicate.RestrictionsFromContext(ctx, influxdb.WriteAction, orgID, bucketID)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   opWriteHandler,
+			Msg:  "unable to check permission restrictions for bucket",
+			Err:  err,
+		}
+	}
+	if rs == nil {
+		return nil
+	}
+	for _, pt := range pts {
+		if !rs.Matches(pt.Name(), pt.Tags()) {
+			return &influxdb.Error{
+				Code: influxdb.EForbidden,
+				Op:   opWriteHandler,
+				Msg:  fmt.Sprintf("insufficient permissions to write series %q", pt.Key()),
+			}
+		}
+	}
+	return nil
+}
+
 // findMapping finds a DBRPMappingV2 for the database and retention policy
 // combination.
 func (h *WriteHandler) findMapping(ctx context.Context, orgID influxdb.ID, db, rp string) (*influxdb.DBRPMappingV2, error) {
@@ -23,6 +23,7 @@ type NotificationEndpointBackend struct {
 	log *zap.Logger
 
 	NotificationEndpointService influxdb.NotificationEndpointService
+	NotificationTestService     influxdb.NotificationTestService
 	UserResourceMappingService  influxdb.UserResourceMappingService
 	LabelService                influxdb.LabelService
 	UserService                 influxdb.UserService
@@ -34,6 +35,7 @@ func NewNotificationEndpointBackend(log *zap.Logger, b *APIBackend) *Notificatio
 		HTTPErrorHandler:            b.HTTPErrorHandler,
 		log:                         log,
 		NotificationEndpointService: b.NotificationEndpointService,
+		NotificationTestService:     b.NotificationTestService,
 		UserResourceMappingService:  b.UserResourceMappingService,
 		LabelService:                b.LabelService,
 		UserService:                 b.UserService,
@@ -51,6 +53,7 @@ type NotificationEndpointHandler struct {
 	log *zap.Logger
 
 	NotificationEndpointService influxdb.NotificationEndpointService
+	NotificationTestService     influxdb.NotificationTestService
 	UserResourceMappingService  influxdb.UserResourceMappingService
 	LabelService                influxdb.LabelService
 	UserService                 influxdb.UserService
@@ -75,6 +78,7 @@ func NewNotificationEndpointHandler(log *zap.Logger, b *NotificationEndpointBack
 		log:              log,
 
 		NotificationEndpointService: b.NotificationEndpointService,
+		NotificationTestService:     b.NotificationTestService,
 		UserResourceMappingService:  b.UserResourceMappingService,
 		LabelService:                b.LabelService,
 		UserService:                 b.UserService,
@@ -85,6 +89,7 @@ func NewNotificationEndpointHandler(log *zap.Logger, b *NotificationEndpointBack
 	h.HandlerFunc("DELETE", notificationEndpointsIDPath, h.handleDeleteNotificationEndpoint)
 	h.HandlerFunc("PUT", notificationEndpointsIDPath, h.handlePutNotificationEndpoint)
 	h.HandlerFunc("PATCH", notificationEndpointsIDPath, h.handlePatchNotificationEndpoint)
+	h.HandlerFunc("POST", notificationEndpointsIDTestPath, h.handlePostNotificationEndpointTest)
 
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
@@ -32,6 +32,7 @@ type NotificationRuleBackend struct {
 	AlgoWProxy                  FeatureProxyHandler
 	NotificationRuleStore       influxdb.NotificationRuleStore
 	NotificationEndpointService influxdb.NotificationEndpointService
+	NotificationTestService     influxdb.NotificationTestService
 	UserResourceMappingService  influxdb.UserResourceMappingService
 	LabelService                influxdb.LabelService
 	UserService                 influxdb.UserService
@@ -48,6 +49,7 @@ func NewNotificationRuleBackend(log *zap.Logger, b *APIBackend) *NotificationRul
 
 		NotificationRuleStore:       b.NotificationRuleStore,
 		NotificationEndpointService: b.NotificationEndpointService,
+		NotificationTestService:     b.NotificationTestService,
 		UserResourceMappingService:  b.UserResourceMappingService,
 		LabelService:                b.LabelService,
 		UserService:                 b.UserService,
@@ -64,6 +66,7 @@ type NotificationRuleHandler struct {
 
 	NotificationRuleStore       influxdb.NotificationRuleStore
 	NotificationEndpointService influxdb.NotificationEndpointService
+	NotificationTestService     influxdb.NotificationTestService
 	UserResourceMappingService  influxdb.UserResourceMappingService
 	LabelService                influxdb.LabelService
 	UserService                 influxdb.UserService
@@ -92,6 +95,7 @@ func NewNotificationRuleHandler(log *zap.Logger, b *NotificationRuleBackend) *No
 
 		NotificationRuleStore:       b.NotificationRuleStore,
 		NotificationEndpointService: b.NotificationEndpointService,
+		NotificationTestService:     b.NotificationTestService,
 		UserResourceMappingService:  b.UserResourceMappingService,
 		LabelService:                b.LabelService,
 		UserService:                 b.UserService,
@@ -106,6 +110,7 @@ func NewNotificationRuleHandler(log *zap.Logger, b *NotificationRuleBackend) *No
 	h.HandlerFunc("DELETE", notificationRulesIDPath, h.handleDeleteNotificationRule)
 	h.Handler("PUT", notificationRulesIDPath, withFeatureProxy(b.AlgoWProxy, http.HandlerFunc(h.handlePutNotificationRule)))
 	h.Handler("PATCH", notificationRulesIDPath, withFeatureProxy(b.AlgoWProxy, http.HandlerFunc(h.handlePatchNotificationRule)))
+	h.HandlerFunc("POST", notificationRulesIDTestPath, h.handlePostNotificationRuleTest)
 
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/pkg/httpc"
+)
+
+const (
+	notificationEndpointsIDTestPath = "/api/v2/notificationEndpoints/:id/test"
+	notificationRulesIDTestPath     = "/api/v2/notificationRules/:id/test"
+)
+
+// handlePostNotificationEndpointTest sends a test notification to the endpoint, returning its response.
+func (h *NotificationEndpointHandler) handlePostNotificationEndpointTest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := decodeGetNotificationEndpointRequest(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res, err := h.NotificationTestService.TestNotificationEndpoint(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handlePostNotificationRuleTest sends a test notification formatted by the
+// rule to its endpoint, returning the response of the endpoint.
+func (h *NotificationRuleHandler) handlePostNotificationRuleTest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := decodeGetNotificationRuleRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res, err := h.NotificationTestService.TestNotificationRule(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// NotificationTestService is an http client for the influxdb.NotificationTestService server implementation.
+type NotificationTestService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.NotificationTestService = (*NotificationTestService)(nil)
+
+// TestNotificationEndpoint sends a test notification to the endpoint.
+func (s *NotificationTestService) TestNotificationEndpoint(ctx context.Context, id influxdb.ID) (*influxdb.NotificationTestResult, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var res influxdb.NotificationTestResult
+	err := s.Client.
+		Post(nil, prefixNotificationEndpoints, id.String(), "test").
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	return &res, nil
+}
+
+// TestNotificationRule sends a test notification, formatted by the rule, to
+// the endpoint of the rule.
+func (s *NotificationTestService) TestNotificationRule(ctx context.Context, id influxdb.ID) (*influxdb.NotificationTestResult, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var res influxdb.NotificationTestResult
+	err := s.Client.
+		Post(nil, prefixNotificationRules, id.String(), "test").
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	return &res, nil
+}
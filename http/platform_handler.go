@@ -7,6 +7,8 @@ import (
 	"github.com/influxdata/influxdb/v2/http/legacy"
 	"github.com/influxdata/influxdb/v2/kit/feature"
 	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/ratelimit"
+	"go.uber.org/zap"
 )
 
 // PlatformHandler is a collection of all the service handlers.
@@ -24,6 +26,7 @@ func NewPlatformHandler(b *APIBackend, opts ...APIHandlerOptFn) *PlatformHandler
 	h.AuthorizationService = b.AuthorizationService
 	h.SessionService = b.SessionService
 	h.SessionRenewDisabled = b.SessionRenewDisabled
+	h.ClientCertAuthorizations = b.ClientCertAuthorizations
 	h.UserService = b.UserService
 
 	h.RegisterNoAuthRoute("GET", "/api/v2")
@@ -40,7 +43,10 @@ func NewPlatformHandler(b *APIBackend, opts ...APIHandlerOptFn) *PlatformHandler
 	wrappedHandler = kithttp.SkipOptions(wrappedHandler)
 
 	legacyBackend := newLegacyBackend(b)
-	lh := newLegacyHandler(legacyBackend, legacy.HandlerConfig{})
+	var lh http.Handler = newLegacyHandler(legacyBackend, legacy.HandlerConfig{})
+	if b.RateLimiter != nil {
+		lh = ratelimit.NewHTTPMiddleware(b.Logger.With(zap.String("middleware", "ratelimit")), b.RateLimiter, b.OrganizationService)(lh)
+	}
 
 	return &PlatformHandler{
 		AssetHandler:  assetHandler,
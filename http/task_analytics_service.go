@@ -0,0 +1,298 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixTaskAnalytics          = "/api/v2/taskAnalytics"
+	prefixTaskAnalyticsRetention = prefixTaskAnalytics + "/retention"
+)
+
+// TaskAnalyticsHandler serves the analytics of task runs and the retention of
+// the run history of organizations. It is not mounted beneath the tasks API
+// as static routes can not be mixed with the task ID there.
+type TaskAnalyticsHandler struct {
+	chi.Router
+	api *kithttp.API
+	log *zap.Logger
+
+	TaskAnalyticsService influxdb.TaskAnalyticsService
+	OrganizationService  influxdb.OrganizationService
+}
+
+// NewTaskAnalyticsHandler returns a new instance of TaskAnalyticsHandler.
+func NewTaskAnalyticsHandler(log *zap.Logger, s influxdb.TaskAnalyticsService, orgs influxdb.OrganizationService) *TaskAnalyticsHandler {
+	h := &TaskAnalyticsHandler{
+		api:                  kithttp.NewAPI(kithttp.WithLog(log)),
+		log:                  log,
+		TaskAnalyticsService: s,
+		OrganizationService:  orgs,
+	}
+
+	r := chi.NewRouter()
+	r.Use(
+		middleware.Recoverer,
+		middleware.RequestID,
+		middleware.RealIP,
+	)
+
+	r.Route("/", func(r chi.Router) {
+		r.Get("/", h.handleGetTaskAnalytics)
+		r.Get("/retention", h.handleGetRunRetention)
+		r.Put("/retention", h.handlePutRunRetention)
+	})
+
+	h.Router = r
+	return h
+}
+
+// Prefix returns the prefix the handler is mounted at.
+func (h *TaskAnalyticsHandler) Prefix() string {
+	return prefixTaskAnalytics
+}
+
+type taskAnalyticsResponse struct {
+	Start time.Time                 `json:"start"`
+	Stop  time.Time                 `json:"stop"`
+	Tasks []*influxdb.TaskAnalytics `json:"tasks"`
+}
+
+type runRetentionResponse struct {
+	OrgID            influxdb.ID `json:"orgID"`
+	RetentionSeconds int64       `json:"retentionSeconds"`
+}
+
+func newRunRetentionResponse(r *influxdb.TaskRunRetention) runRetentionResponse {
+	return runRetentionResponse{
+		OrgID:            r.OrganizationID,
+		RetentionSeconds: int64(r.Retention / time.Second),
+	}
+}
+
+type putRunRetentionRequest struct {
+	RetentionSeconds int64 `json:"retentionSeconds"`
+}
+
+// handleGetTaskAnalytics is the HTTP handler for the GET /api/v2/taskAnalytics route.
+func (h *TaskAnalyticsHandler) handleGetTaskAnalytics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	qp := r.URL.Query()
+
+	orgID, err := h.decodeOrgID(ctx, r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	filter := influxdb.TaskAnalyticsFilter{OrganizationID: orgID}
+
+	if id := qp.Get("taskID"); id != "" {
+		taskID, err := influxdb.IDFromString(id)
+		if err != nil {
+			h.api.Err(w, r, err)
+			return
+		}
+		filter.TaskID = taskID
+	}
+
+	timeParams := []struct {
+		name string
+		dst  *time.Time
+	}{
+		{name: "start", dst: &filter.Start},
+		{name: "stop", dst: &filter.Stop},
+	}
+	for _, p := range timeParams {
+		v := qp.Get(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			h.api.Err(w, r, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid " + p.name + " time, expected RFC3339",
+				Err:  err,
+			})
+			return
+		}
+		*p.dst = t
+	}
+	if filter.Stop.IsZero() {
+		filter.Stop = time.Now().UTC()
+	}
+	if filter.Start.IsZero() {
+		filter.Start = filter.Stop.Add(-influxdb.DefaultTaskAnalyticsWindow)
+	}
+
+	tasks, err := h.TaskAnalyticsService.FindTaskAnalytics(ctx, filter)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Task analytics retrieved", zap.Int("tasks", len(tasks)))
+
+	h.api.Respond(w, r, http.StatusOK, taskAnalyticsResponse{
+		Start: filter.Start.UTC(),
+		Stop:  filter.Stop.UTC(),
+		Tasks: tasks,
+	})
+}
+
+// handleGetRunRetention is the HTTP handler for the GET /api/v2/taskAnalytics/retention route.
+func (h *TaskAnalyticsHandler) handleGetRunRetention(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orgID, err := h.decodeOrgID(ctx, r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	ret, err := h.TaskAnalyticsService.FindTaskRunRetention(ctx, orgID)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.api.Respond(w, r, http.StatusOK, newRunRetentionResponse(ret))
+}
+
+// handlePutRunRetention is the HTTP handler for the PUT /api/v2/taskAnalytics/retention route.
+func (h *TaskAnalyticsHandler) handlePutRunRetention(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orgID, err := h.decodeOrgID(ctx, r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	var req putRunRetentionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.api.Err(w, r, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		})
+		return
+	}
+
+	ret, err := h.TaskAnalyticsService.UpdateTaskRunRetention(ctx, orgID, time.Duration(req.RetentionSeconds)*time.Second)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Task run retention updated", zap.String("orgID", orgID.String()), zap.Duration("retention", ret.Retention))
+
+	h.api.Respond(w, r, http.StatusOK, newRunRetentionResponse(ret))
+}
+
+// decodeOrgID returns the organization of the request, given by the orgID or the org parameter.
+func (h *TaskAnalyticsHandler) decodeOrgID(ctx context.Context, r *http.Request) (influxdb.ID, error) {
+	qp := r.URL.Query()
+	if id := qp.Get("orgID"); id != "" {
+		orgID, err := influxdb.IDFromString(id)
+		if err != nil {
+			return 0, err
+		}
+		return *orgID, nil
+	}
+	if name := qp.Get("org"); name != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &name})
+		if err != nil {
+			return 0, err
+		}
+		return o.ID, nil
+	}
+	return 0, &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "orgID or org is required",
+	}
+}
+
+var _ influxdb.TaskAnalyticsService = (*TaskAnalyticsService)(nil)
+
+// TaskAnalyticsService connects to Influx via HTTP using tokens to read task analytics.
+type TaskAnalyticsService struct {
+	Client *httpc.Client
+}
+
+// FindTaskAnalytics returns the analytics of the tasks matching the filter.
+func (s *TaskAnalyticsService) FindTaskAnalytics(ctx context.Context, filter influxdb.TaskAnalyticsFilter) ([]*influxdb.TaskAnalytics, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	params := [][2]string{{"orgID", filter.OrganizationID.String()}}
+	if filter.TaskID != nil {
+		params = append(params, [2]string{"taskID", filter.TaskID.String()})
+	}
+	if !filter.Start.IsZero() {
+		params = append(params, [2]string{"start", filter.Start.UTC().Format(time.RFC3339)})
+	}
+	if !filter.Stop.IsZero() {
+		params = append(params, [2]string{"stop", filter.Stop.UTC().Format(time.RFC3339)})
+	}
+
+	var resp taskAnalyticsResponse
+	err := s.Client.
+		Get(prefixTaskAnalytics).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Tasks, nil
+}
+
+// FindTaskRunRetention returns the run history retention of the organization.
+func (s *TaskAnalyticsService) FindTaskRunRetention(ctx context.Context, orgID influxdb.ID) (*influxdb.TaskRunRetention, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var resp runRetentionResponse
+	err := s.Client.
+		Get(prefixTaskAnalyticsRetention).
+		QueryParams([2]string{"orgID", orgID.String()}).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.toTaskRunRetention(), nil
+}
+
+// UpdateTaskRunRetention changes the run history retention of the organization.
+func (s *TaskAnalyticsService) UpdateTaskRunRetention(ctx context.Context, orgID influxdb.ID, retention time.Duration) (*influxdb.TaskRunRetention, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var resp runRetentionResponse
+	err := s.Client.
+		PutJSON(putRunRetentionRequest{RetentionSeconds: int64(retention / time.Second)}, prefixTaskAnalyticsRetention).
+		QueryParams([2]string{"orgID", orgID.String()}).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.toTaskRunRetention(), nil
+}
+
+func (r runRetentionResponse) toTaskRunRetention() *influxdb.TaskRunRetention {
+	return &influxdb.TaskRunRetention{
+		OrganizationID: r.OrgID,
+		Retention:      time.Duration(r.RetentionSeconds) * time.Second,
+	}
+}
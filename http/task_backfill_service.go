@@ -0,0 +1,252 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+const (
+	tasksIDBackfillsPath   = "/api/v2/tasks/:id/backfills"
+	tasksIDBackfillsIDPath = "/api/v2/tasks/:id/backfills/:bid"
+)
+
+var _ influxdb.BackfillService = (*TaskService)(nil)
+
+type backfillResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.Backfill
+}
+
+func newBackfillResponse(b *influxdb.Backfill) backfillResponse {
+	return backfillResponse{
+		Links: map[string]string{
+			"self": taskIDBackfillIDPath(b.TaskID, b.ID),
+			"task": taskIDPath(b.TaskID),
+		},
+		Backfill: *b,
+	}
+}
+
+type backfillsResponse struct {
+	Backfills []backfillResponse `json:"backfills"`
+}
+
+func newBackfillsResponse(bs []*influxdb.Backfill) backfillsResponse {
+	res := backfillsResponse{
+		Backfills: make([]backfillResponse, 0, len(bs)),
+	}
+	for _, b := range bs {
+		res.Backfills = append(res.Backfills, newBackfillResponse(b))
+	}
+	return res
+}
+
+type postBackfillRequest struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func (h *TaskHandler) handlePostBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, err := decodeTaskIDParam(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var req postBackfillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	b, err := h.BackfillService.CreateBackfill(ctx, taskID, req.Start, req.End)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if err := encodeResponse(ctx, w, http.StatusCreated, newBackfillResponse(b)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handleGetBackfills(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, err := decodeTaskIDParam(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	bs, err := h.BackfillService.FindBackfills(ctx, taskID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, newBackfillsResponse(bs)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handleGetBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, backfillID, err := decodeBackfillIDParams(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	b, err := h.BackfillService.FindBackfillByID(ctx, taskID, backfillID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, newBackfillResponse(b)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handleCancelBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, backfillID, err := decodeBackfillIDParams(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	b, err := h.BackfillService.CancelBackfill(ctx, taskID, backfillID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, newBackfillResponse(b)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func decodeTaskIDParam(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	var id influxdb.ID
+	if err := id.DecodeFromString(params.ByName("id")); err != nil {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid task ID",
+			Err:  err,
+		}
+	}
+	return id, nil
+}
+
+func decodeBackfillIDParams(ctx context.Context) (influxdb.ID, influxdb.ID, error) {
+	taskID, err := decodeTaskIDParam(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	params := httprouter.ParamsFromContext(ctx)
+	var id influxdb.ID
+	if err := id.DecodeFromString(params.ByName("bid")); err != nil {
+		return 0, 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid backfill ID",
+			Err:  err,
+		}
+	}
+	return taskID, id, nil
+}
+
+// CreateBackfill starts a backfill of the task from start to end, both inclusive.
+func (t TaskService) CreateBackfill(ctx context.Context, taskID influxdb.ID, start, end time.Time) (*influxdb.Backfill, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var res backfillResponse
+	err := t.Client.
+		PostJSON(postBackfillRequest{Start: start, End: end}, taskIDBackfillsPath(taskID)).
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &res.Backfill, nil
+}
+
+// FindBackfillByID returns a single backfill of the task.
+func (t TaskService) FindBackfillByID(ctx context.Context, taskID, id influxdb.ID) (*influxdb.Backfill, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var res backfillResponse
+	err := t.Client.
+		Get(taskIDBackfillIDPath(taskID, id)).
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &res.Backfill, nil
+}
+
+// FindBackfills returns the backfills of the task.
+func (t TaskService) FindBackfills(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Backfill, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var res backfillsResponse
+	err := t.Client.
+		Get(taskIDBackfillsPath(taskID)).
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bs := make([]*influxdb.Backfill, 0, len(res.Backfills))
+	for i := range res.Backfills {
+		bs = append(bs, &res.Backfills[i].Backfill)
+	}
+	return bs, nil
+}
+
+// CancelBackfill stops a running backfill and cancels its runs in progress.
+func (t TaskService) CancelBackfill(ctx context.Context, taskID, id influxdb.ID) (*influxdb.Backfill, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var res backfillResponse
+	err := t.Client.
+		Delete(taskIDBackfillIDPath(taskID, id)).
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &res.Backfill, nil
+}
+
+func taskIDBackfillsPath(taskID influxdb.ID) string {
+	return path.Join(prefixTasks, taskID.String(), "backfills")
+}
+
+func taskIDBackfillIDPath(taskID, id influxdb.ID) string {
+	return path.Join(prefixTasks, taskID.String(), "backfills", id.String())
+}
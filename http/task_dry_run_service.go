@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+const tasksIDDryRunPath = "/api/v2/tasks/:id/dryrun"
+
+var _ influxdb.TaskDryRunService = (*TaskService)(nil)
+
+type postDryRunRequest struct {
+	// Now is the time the script runs with, the current time when zero.
+	Now time.Time `json:"now"`
+}
+
+// handlePostDryRun runs the script of the task, returning the tables it would have written.
+func (h *TaskHandler) handlePostDryRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, err := decodeTaskIDParam(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var req postDryRunRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "failed to decode request",
+				Err:  err,
+			}, w)
+			return
+		}
+	}
+	if req.Now.IsZero() {
+		req.Now = time.Now().UTC()
+	}
+
+	dr, err := h.TaskDryRunService.DryRunTask(ctx, taskID, req.Now)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, dr); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// DryRunTask runs the script of the task as if it was scheduled for now,
+// returning the tables it would have written.
+func (t TaskService) DryRunTask(ctx context.Context, taskID influxdb.ID, now time.Time) (*influxdb.TaskDryRun, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var dr influxdb.TaskDryRun
+	err := t.Client.
+		PostJSON(postDryRunRequest{Now: now}, path.Join(prefixTasks, taskID.String(), "dryrun")).
+		DecodeJSON(&dr).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &dr, nil
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+const tasksIDGraphPath = "/api/v2/tasks/:id/graph"
+
+// handleGetTaskGraph returns the tasks connected to the task through their dependencies.
+func (h *TaskHandler) handleGetTaskGraph(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, err := decodeTaskIDParam(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	task, err := h.TaskService.FindTaskByID(ctx, taskID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// the graph only holds the tasks of the organization that can be read
+	var (
+		tasks  []*influxdb.Task
+		filter = influxdb.TaskFilter{
+			OrganizationID: &task.OrganizationID,
+			Limit:          influxdb.TaskMaxPageSize,
+		}
+	)
+	for {
+		ts, _, err := h.TaskService.FindTasks(ctx, filter)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		tasks = append(tasks, ts...)
+		if len(ts) < filter.Limit {
+			break
+		}
+		filter.After = &ts[len(ts)-1].ID
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, influxdb.NewTaskGraph(task.ID, tasks)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// FindTaskGraph returns the tasks connected to the task through their dependencies.
+func (t TaskService) FindTaskGraph(ctx context.Context, taskID influxdb.ID) (*influxdb.TaskGraph, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var g influxdb.TaskGraph
+	err := t.Client.
+		Get(path.Join(prefixTasks, taskID.String(), "graph")).
+		DecodeJSON(&g).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
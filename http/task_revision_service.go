@@ -0,0 +1,257 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+const (
+	tasksIDRevisionsPath           = "/api/v2/tasks/:id/revisions"
+	tasksIDRevisionsIDPath         = "/api/v2/tasks/:id/revisions/:rev"
+	tasksIDRevisionsIDDiffPath     = "/api/v2/tasks/:id/revisions/:rev/diff"
+	tasksIDRevisionsIDRollbackPath = "/api/v2/tasks/:id/revisions/:rev/rollback"
+)
+
+var _ influxdb.TaskRevisionService = (*TaskService)(nil)
+
+type revisionResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.TaskRevision
+}
+
+func newRevisionResponse(rev *influxdb.TaskRevision) revisionResponse {
+	return revisionResponse{
+		Links: map[string]string{
+			"self": taskIDRevisionPath(rev.TaskID, rev.Revision),
+			"diff": path.Join(taskIDRevisionPath(rev.TaskID, rev.Revision), "diff"),
+			"task": taskIDPath(rev.TaskID),
+		},
+		TaskRevision: *rev,
+	}
+}
+
+type revisionsResponse struct {
+	Revisions []revisionResponse `json:"revisions"`
+}
+
+func newRevisionsResponse(revs []*influxdb.TaskRevision) revisionsResponse {
+	res := revisionsResponse{
+		Revisions: make([]revisionResponse, 0, len(revs)),
+	}
+	for _, rev := range revs {
+		res.Revisions = append(res.Revisions, newRevisionResponse(rev))
+	}
+	return res
+}
+
+func (h *TaskHandler) handleGetRevisions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, err := decodeTaskIDParam(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	revs, err := h.TaskRevisionService.FindTaskRevisions(ctx, taskID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, newRevisionsResponse(revs)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handleGetRevision(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, revision, err := decodeRevisionParams(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rev, err := h.TaskRevisionService.FindTaskRevision(ctx, taskID, revision)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, newRevisionResponse(rev)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleGetRevisionDiff returns the changes made by a revision, from the
+// revision before it unless another one is given by the from parameter.
+func (h *TaskHandler) handleGetRevisionDiff(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, revision, err := decodeRevisionParams(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	from := revision - 1
+	if from < 1 {
+		from = revision
+	}
+	if f := r.URL.Query().Get("from"); f != "" {
+		if from, err = strconv.Atoi(f); err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid from revision",
+				Err:  err,
+			}, w)
+			return
+		}
+	}
+
+	fromRev, err := h.TaskRevisionService.FindTaskRevision(ctx, taskID, from)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	toRev, err := h.TaskRevisionService.FindTaskRevision(ctx, taskID, revision)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, influxdb.DiffTaskRevisions(fromRev, toRev)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handlePostRollback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, revision, err := decodeRevisionParams(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	task, err := h.TaskRevisionService.RollbackTask(ctx, taskID, revision)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	labels, err := h.LabelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: task.ID, ResourceType: influxdb.TasksResourceType})
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Err: err,
+			Msg: "failed to find resource labels",
+		}, w)
+		return
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskResponse(*task, labels)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func decodeRevisionParams(ctx context.Context) (influxdb.ID, int, error) {
+	taskID, err := decodeTaskIDParam(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	params := httprouter.ParamsFromContext(ctx)
+	revision, err := strconv.Atoi(params.ByName("rev"))
+	if err != nil || revision < 1 {
+		return 0, 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid task revision",
+			Err:  err,
+		}
+	}
+	return taskID, revision, nil
+}
+
+func taskIDRevisionPath(taskID influxdb.ID, revision int) string {
+	return path.Join(taskIDPath(taskID), "revisions", strconv.Itoa(revision))
+}
+
+// FindTaskRevisions returns the revisions of a task, oldest first.
+func (t TaskService) FindTaskRevisions(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskRevision, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var res revisionsResponse
+	err := t.Client.
+		Get(taskIDPath(taskID), "revisions").
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	revs := make([]*influxdb.TaskRevision, 0, len(res.Revisions))
+	for i := range res.Revisions {
+		revs = append(revs, &res.Revisions[i].TaskRevision)
+	}
+	return revs, nil
+}
+
+// FindTaskRevision returns a single revision of a task.
+func (t TaskService) FindTaskRevision(ctx context.Context, taskID influxdb.ID, revision int) (*influxdb.TaskRevision, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var res revisionResponse
+	err := t.Client.
+		Get(taskIDRevisionPath(taskID, revision)).
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &res.TaskRevision, nil
+}
+
+// DiffTaskRevisions returns the changes to a task going from one of its
+// revisions to another.
+func (t TaskService) DiffTaskRevisions(ctx context.Context, taskID influxdb.ID, from, to int) (*influxdb.TaskRevisionDiff, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var d influxdb.TaskRevisionDiff
+	err := t.Client.
+		Get(taskIDRevisionPath(taskID, to), "diff").
+		QueryParams([2]string{"from", strconv.Itoa(from)}).
+		DecodeJSON(&d).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// RollbackTask updates the task to the script of one of its revisions,
+// making a new revision.
+func (t TaskService) RollbackTask(ctx context.Context, taskID influxdb.ID, revision int) (*influxdb.Task, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var tr taskResponse
+	err := t.Client.
+		Post(nil, taskIDRevisionPath(taskID, revision), "rollback").
+		DecodeJSON(&tr).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return convertTask(tr.Task), nil
+}
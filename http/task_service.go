@@ -35,6 +35,9 @@ type TaskBackend struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	BucketService              influxdb.BucketService
+	BackfillService            influxdb.BackfillService
+	TaskDryRunService          influxdb.TaskDryRunService
+	TaskRevisionService        influxdb.TaskRevisionService
 }
 
 // NewTaskBackend returns a new instance of TaskBackend.
@@ -50,6 +53,9 @@ func NewTaskBackend(log *zap.Logger, b *APIBackend) *TaskBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		BucketService:              b.BucketService,
+		BackfillService:            b.BackfillService,
+		TaskDryRunService:          b.TaskDryRunService,
+		TaskRevisionService:        b.TaskRevisionService,
 	}
 }
 
@@ -66,6 +72,9 @@ type TaskHandler struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	BucketService              influxdb.BucketService
+	BackfillService            influxdb.BackfillService
+	TaskDryRunService          influxdb.TaskDryRunService
+	TaskRevisionService        influxdb.TaskRevisionService
 }
 
 const (
@@ -98,6 +107,9 @@ func NewTaskHandler(log *zap.Logger, b *TaskBackend) *TaskHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		BucketService:              b.BucketService,
+		BackfillService:            b.BackfillService,
+		TaskDryRunService:          b.TaskDryRunService,
+		TaskRevisionService:        b.TaskRevisionService,
 	}
 
 	h.HandlerFunc("GET", prefixTasks, h.handleGetTasks)
@@ -140,6 +152,19 @@ func NewTaskHandler(log *zap.Logger, b *TaskBackend) *TaskHandler {
 	h.HandlerFunc("POST", tasksIDRunsIDRetryPath, h.handleRetryRun)
 	h.HandlerFunc("DELETE", tasksIDRunsIDPath, h.handleCancelRun)
 
+	h.HandlerFunc("GET", tasksIDGraphPath, h.handleGetTaskGraph)
+	h.HandlerFunc("POST", tasksIDDryRunPath, h.handlePostDryRun)
+
+	h.HandlerFunc("GET", tasksIDRevisionsPath, h.handleGetRevisions)
+	h.HandlerFunc("GET", tasksIDRevisionsIDPath, h.handleGetRevision)
+	h.HandlerFunc("GET", tasksIDRevisionsIDDiffPath, h.handleGetRevisionDiff)
+	h.HandlerFunc("POST", tasksIDRevisionsIDRollbackPath, h.handlePostRollback)
+
+	h.HandlerFunc("GET", tasksIDBackfillsPath, h.handleGetBackfills)
+	h.HandlerFunc("POST", tasksIDBackfillsPath, h.handlePostBackfill)
+	h.HandlerFunc("GET", tasksIDBackfillsIDPath, h.handleGetBackfill)
+	h.HandlerFunc("DELETE", tasksIDBackfillsIDPath, h.handleCancelBackfill)
+
 	labelBackend := &LabelBackend{
 		HTTPErrorHandler: b.HTTPErrorHandler,
 		log:              b.log.With(zap.String("handler", "label")),
@@ -167,6 +192,12 @@ type Task struct {
 	Every           string                 `json:"every,omitempty"`
 	Cron            string                 `json:"cron,omitempty"`
 	Offset          string                 `json:"offset,omitempty"`
+	DependsOn       []influxdb.ID          `json:"dependsOn,omitempty"`
+	Trigger         string                 `json:"trigger,omitempty"`
+	Debounce        string                 `json:"debounce,omitempty"`
+	Timeout         string                 `json:"timeout,omitempty"`
+	Catchup         string                 `json:"catchup,omitempty"`
+	Revision        int                    `json:"revision,omitempty"`
 	LatestCompleted string                 `json:"latestCompleted,omitempty"`
 	LastRunStatus   string                 `json:"lastRunStatus,omitempty"`
 	LastRunError    string                 `json:"lastRunError,omitempty"`
@@ -199,6 +230,14 @@ func NewFrontEndTask(t influxdb.Task) Task {
 	if t.Offset != 0*time.Second {
 		offset = customParseDuration(t.Offset)
 	}
+	debounce := ""
+	if t.Debounce != 0 {
+		debounce = customParseDuration(t.Debounce)
+	}
+	timeout := ""
+	if t.Timeout != 0 {
+		timeout = customParseDuration(t.Timeout)
+	}
 
 	return Task{
 		ID:              t.ID,
@@ -212,6 +251,12 @@ func NewFrontEndTask(t influxdb.Task) Task {
 		Every:           t.Every,
 		Cron:            t.Cron,
 		Offset:          offset,
+		DependsOn:       t.DependsOn,
+		Trigger:         t.Trigger,
+		Debounce:        debounce,
+		Timeout:         timeout,
+		Catchup:         t.Catchup,
+		Revision:        t.Revision,
 		LatestCompleted: latestCompleted,
 		LastRunStatus:   t.LastRunStatus,
 		LastRunError:    t.LastRunError,
@@ -227,6 +272,8 @@ func convertTask(t Task) *influxdb.Task {
 		createdAt       time.Time
 		updatedAt       time.Time
 		offset          time.Duration
+		debounce        time.Duration
+		timeout         time.Duration
 	)
 
 	if t.LatestCompleted != "" {
@@ -248,6 +295,20 @@ func convertTask(t Task) *influxdb.Task {
 		}
 	}
 
+	if t.Debounce != "" {
+		var duration options.Duration
+		if err := duration.Parse(t.Debounce); err == nil {
+			debounce, _ = duration.DurationFrom(time.Now())
+		}
+	}
+
+	if t.Timeout != "" {
+		var duration options.Duration
+		if err := duration.Parse(t.Timeout); err == nil {
+			timeout, _ = duration.DurationFrom(time.Now())
+		}
+	}
+
 	return &influxdb.Task{
 		ID:              t.ID,
 		OrganizationID:  t.OrganizationID,
@@ -260,6 +321,12 @@ func convertTask(t Task) *influxdb.Task {
 		Every:           t.Every,
 		Cron:            t.Cron,
 		Offset:          offset,
+		DependsOn:       t.DependsOn,
+		Trigger:         t.Trigger,
+		Debounce:        debounce,
+		Timeout:         timeout,
+		Catchup:         t.Catchup,
+		Revision:        t.Revision,
 		LatestCompleted: latestCompleted,
 		LastRunStatus:   t.LastRunStatus,
 		LastRunError:    t.LastRunError,
@@ -392,6 +459,9 @@ type httpRun struct {
 	StartedAt    *time.Time     `json:"startedAt,omitempty"`
 	FinishedAt   *time.Time     `json:"finishedAt,omitempty"`
 	RequestedAt  *time.Time     `json:"requestedAt,omitempty"`
+	RetryOf      influxdb.ID    `json:"retryOf,omitempty"`
+	Attempt      int            `json:"attempt,omitempty"`
+	Revision     int            `json:"revision,omitempty"`
 	Log          []influxdb.Log `json:"log,omitempty"`
 }
 
@@ -402,6 +472,9 @@ func newRunResponse(r influxdb.Run) runResponse {
 		Status:       r.Status,
 		Log:          r.Log,
 		ScheduledFor: &r.ScheduledFor,
+		RetryOf:      r.RetryOf,
+		Attempt:      r.Attempt,
+		Revision:     r.Revision,
 	}
 
 	if !r.StartedAt.IsZero() {
@@ -427,10 +500,13 @@ func newRunResponse(r influxdb.Run) runResponse {
 
 func convertRun(r httpRun) *influxdb.Run {
 	run := &influxdb.Run{
-		ID:     r.ID,
-		TaskID: r.TaskID,
-		Status: r.Status,
-		Log:    r.Log,
+		ID:       r.ID,
+		TaskID:   r.TaskID,
+		Status:   r.Status,
+		RetryOf:  r.RetryOf,
+		Attempt:  r.Attempt,
+		Revision: r.Revision,
+		Log:      r.Log,
 	}
 
 	if r.StartedAt != nil {
@@ -15,6 +15,7 @@ import (
 	"github.com/influxdata/influxdb/v2/kit/tracing"
 	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
 	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/predicate"
 	"github.com/influxdata/influxdb/v2/storage"
 	"go.uber.org/zap"
 )
@@ -184,6 +185,11 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		h.HandleHTTPError(ctx, err, sw)
 		return
 	}
+
+	if err := checkBucketWriteRestrictions(ctx, org.ID, bucket.ID, parsed.Points); err != nil {
+		h.HandleHTTPError(ctx, err, sw)
+		return
+	}
 	requestBytes = parsed.RawSize
 
 	if err := h.PointsWriter.WritePoints(ctx, org.ID, bucket.ID, parsed.Points); err != nil {
@@ -222,6 +228,33 @@ func checkBucketWritePermissions(auth influxdb.Authorizer, orgID, bucketID influ
 	return nil
 }
 
+// checkBucketWriteRestrictions rejects the batch when the permissions of the
+// request restrict writes to the bucket and a point does not match them.
+func checkBucketWriteRestrictions(ctx context.Context, orgID, bucketID influxdb.ID, pts models.Points) error {
+	rs, err := predicate.RestrictionsFromContext(ctx, influxdb.WriteAction, orgID, bucketID)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   opWriteHandler,
+			Msg:  "unable to check permission restrictions for bucket",
+			Err:  err,
+		}
+	}
+	if rs == nil {
+		return nil
+	}
+	for _, pt := range pts {
+		if !rs.Matches(pt.Name(), pt.Tags()) {
+			return &influxdb.Error{
+				Code: influxdb.EForbidden,
+				Op:   opWriteHandler,
+				Msg:  fmt.Sprintf("insufficient permissions to write series %q", pt.Key()),
+			}
+		}
+	}
+	return nil
+}
+
 // writeRequest is a request object holding information about a batch of points
 // to be written to a Bucket.
 type writeRequest struct {
@@ -0,0 +1,14 @@
+package kv
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+// CreateLimit is checked by a service in the transaction that creates a
+// resource of an organization, so that concurrent creates cannot exceed the
+// limit. It returns an error when the organization cannot create another
+// resource. count returns the number of resources the organization already
+// has, it is only called when the organization is limited.
+type CreateLimit func(ctx context.Context, tx Tx, orgID influxdb.ID, count func() (int, error)) error
@@ -0,0 +1,11 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var auditLogBucket = []byte("auditlogv1")
+
+// Migration0012_AddAuditLogBucket creates the bucket used to store the append-only audit log.
+var Migration0012_AddAuditLogBucket = migration.CreateBuckets(
+	"create audit log bucket",
+	auditLogBucket,
+)
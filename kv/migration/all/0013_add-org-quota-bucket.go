@@ -0,0 +1,11 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var orgQuotaBucket = []byte("orgquotasv1")
+
+// Migration0013_AddOrgQuotaBucket creates the bucket used to store the resource quotas of organizations.
+var Migration0013_AddOrgQuotaBucket = migration.CreateBuckets(
+	"create org quota bucket",
+	orgQuotaBucket,
+)
@@ -0,0 +1,11 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var taskBackfillBucket = []byte("taskbackfillsv1")
+
+// Migration0014_AddTaskBackfillBucket creates the bucket used to store the backfills of tasks.
+var Migration0014_AddTaskBackfillBucket = migration.CreateBuckets(
+	"create task backfill bucket",
+	taskBackfillBucket,
+)
@@ -0,0 +1,11 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var taskRevisionBucket = []byte("taskRevisionsv1")
+
+// Migration0015_AddTaskRevisionBucket creates the bucket used to store the revisions of tasks.
+var Migration0015_AddTaskRevisionBucket = migration.CreateBuckets(
+	"create task revision bucket",
+	taskRevisionBucket,
+)
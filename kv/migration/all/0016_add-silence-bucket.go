@@ -0,0 +1,11 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var silenceBucket = []byte("silencesv1")
+
+// Migration0016_AddSilenceBucket creates the bucket used to store the silences of notifications.
+var Migration0016_AddSilenceBucket = migration.CreateBuckets(
+	"create silence bucket",
+	silenceBucket,
+)
@@ -0,0 +1,16 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var (
+	alertBucket         = []byte("alertsv1")
+	alertBySeriesBucket = []byte("alertsbyseriesv1")
+)
+
+// Migration0017_AddAlertBuckets creates the buckets used to store the alerts of checks
+// and the index of the unresolved alert of every series.
+var Migration0017_AddAlertBuckets = migration.CreateBuckets(
+	"create alert buckets",
+	alertBucket,
+	alertBySeriesBucket,
+)
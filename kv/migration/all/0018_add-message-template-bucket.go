@@ -0,0 +1,11 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var messageTemplateBucket = []byte("messagetemplatesv1")
+
+// Migration0018_AddMessageTemplateBucket creates the bucket used to store the message templates of notifications.
+var Migration0018_AddMessageTemplateBucket = migration.CreateBuckets(
+	"create message template bucket",
+	messageTemplateBucket,
+)
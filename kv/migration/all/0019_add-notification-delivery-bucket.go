@@ -0,0 +1,11 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var notificationDeliveryBucket = []byte("notificationdeliveriesv1")
+
+// Migration0019_AddNotificationDeliveryBucket creates the bucket used to store the failed deliveries of notification rules.
+var Migration0019_AddNotificationDeliveryBucket = migration.CreateBuckets(
+	"create notification delivery bucket",
+	notificationDeliveryBucket,
+)
@@ -29,5 +29,21 @@ var Migrations = [...]migration.Spec{
 	Migration0010_AddIndexTelegrafByOrg,
 	// populate dashboards owner id
 	Migration0011_PopulateDashboardsOwnerId,
+	// create audit log bucket
+	Migration0012_AddAuditLogBucket,
+	// create org quota bucket
+	Migration0013_AddOrgQuotaBucket,
+	// create task backfill bucket
+	Migration0014_AddTaskBackfillBucket,
+	// create task revision bucket
+	Migration0015_AddTaskRevisionBucket,
+	// create silence bucket
+	Migration0016_AddSilenceBucket,
+	// create alert buckets
+	Migration0017_AddAlertBuckets,
+	// create message template bucket
+	Migration0018_AddMessageTemplateBucket,
+	// create notification delivery bucket
+	Migration0019_AddNotificationDeliveryBucket,
 	// {{ do_not_edit . }}
 }
@@ -67,6 +67,11 @@ func NewService(log *zap.Logger, kv Store, orgs influxdb.OrganizationService, co
 type ServiceConfig struct {
 	Clock               clock.Clock
 	FluxLanguageService influxdb.FluxLanguageService
+
+	// TaskLimit limits the number of tasks of an organization. Only tasks
+	// created by users count towards the limit, not the tasks backing checks
+	// and notification rules.
+	TaskLimit CreateLimit
 }
 
 // WithResourceLogger sets the resource audit logger for the service.
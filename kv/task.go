@@ -24,6 +24,8 @@ import (
 //   <taskID>/latestCompleted: run data for the latest completed run of a task
 // taskIndexBucket
 //   <orgID>/<taskID>: index for tasks by org
+// taskRevisionBucket
+//   <taskID>/<revision>: revisions of the script of a task
 
 // We may want to add a <taskName>/<taskID> index to allow us to look up tasks by task name.
 
@@ -31,9 +33,12 @@ var (
 	taskBucket      = []byte("tasksv1")
 	taskRunBucket   = []byte("taskRunsv1")
 	taskIndexBucket = []byte("taskIndexsv1")
+
+	taskRevisionBucket = []byte("taskRevisionsv1")
 )
 
 var _ influxdb.TaskService = (*Service)(nil)
+var _ influxdb.TaskRevisionService = (*Service)(nil)
 
 type kvTask struct {
 	ID              influxdb.ID            `json:"id"`
@@ -50,6 +55,12 @@ type kvTask struct {
 	LastRunStatus   string                 `json:"lastRunStatus,omitempty"`
 	LastRunError    string                 `json:"lastRunError,omitempty"`
 	Offset          influxdb.Duration      `json:"offset,omitempty"`
+	DependsOn       []influxdb.ID          `json:"dependsOn,omitempty"`
+	Trigger         string                 `json:"trigger,omitempty"`
+	Debounce        influxdb.Duration      `json:"debounce,omitempty"`
+	Timeout         influxdb.Duration      `json:"timeout,omitempty"`
+	Catchup         string                 `json:"catchup,omitempty"`
+	Revision        int                    `json:"revision,omitempty"`
 	LatestCompleted time.Time              `json:"latestCompleted,omitempty"`
 	LatestScheduled time.Time              `json:"latestScheduled,omitempty"`
 	LatestSuccess   time.Time              `json:"latestSuccess,omitempty"`
@@ -75,6 +86,12 @@ func kvToInfluxTask(k *kvTask) *influxdb.Task {
 		LastRunStatus:   k.LastRunStatus,
 		LastRunError:    k.LastRunError,
 		Offset:          k.Offset.Duration,
+		DependsOn:       k.DependsOn,
+		Trigger:         k.Trigger,
+		Debounce:        k.Debounce.Duration,
+		Timeout:         k.Timeout.Duration,
+		Catchup:         k.Catchup,
+		Revision:        k.Revision,
 		LatestCompleted: k.LatestCompleted,
 		LatestScheduled: k.LatestScheduled,
 		LatestSuccess:   k.LatestSuccess,
@@ -484,6 +501,15 @@ func (s *Service) createTask(ctx context.Context, tx Tx, org *influxdb.Organizat
 	// 	return nil, influxdb.ErrInvalidOwnerID
 	// }
 
+	if s.Config.TaskLimit != nil && (tc.Type == "" || tc.Type == influxdb.TaskSystemType) {
+		err := s.Config.TaskLimit(ctx, tx, org.ID, func() (int, error) {
+			return s.countTasks(ctx, tx, org.ID)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	opts, err := ExtractTaskOptions(ctx, s.FluxLanguageService, tc.Flux)
 	if err != nil {
 		return nil, influxdb.ErrTaskOptionParse(err)
@@ -507,6 +533,9 @@ func (s *Service) createTask(ctx context.Context, tx Tx, org *influxdb.Organizat
 		Flux:            tc.Flux,
 		Every:           opts.Every.String(),
 		Cron:            opts.Cron,
+		Trigger:         opts.Trigger,
+		Catchup:         opts.Catchup,
+		Revision:        1,
 		CreatedAt:       createdAt,
 		LatestCompleted: createdAt,
 		LatestScheduled: createdAt,
@@ -521,6 +550,27 @@ func (s *Service) createTask(ctx context.Context, tx Tx, org *influxdb.Organizat
 
 	}
 
+	if opts.Debounce != nil {
+		debounce, err := time.ParseDuration(opts.Debounce.String())
+		if err != nil {
+			return nil, influxdb.ErrTaskTimeParse(err)
+		}
+		task.Debounce = debounce
+	}
+
+	if opts.Timeout != nil {
+		timeout, err := time.ParseDuration(opts.Timeout.String())
+		if err != nil {
+			return nil, influxdb.ErrTaskTimeParse(err)
+		}
+		task.Timeout = timeout
+	}
+
+	// a new task has no dependents, so its dependencies cannot form a cycle
+	if task.DependsOn, err = s.validateDependencies(ctx, tx, task, tc.DependsOn); err != nil {
+		return nil, err
+	}
+
 	taskBucket, err := tx.Bucket(taskBucket)
 	if err != nil {
 		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
@@ -559,6 +609,12 @@ func (s *Service) createTask(ctx context.Context, tx Tx, org *influxdb.Organizat
 	}
 
 	uid, _ := icontext.GetUserID(ctx)
+
+	// write the first revision
+	if err := s.putTaskRevision(ctx, tx, influxdb.NewTaskRevision(task, uid, createdAt)); err != nil {
+		return nil, err
+	}
+
 	if err := s.audit.Log(resource.Change{
 		Type:           resource.Create,
 		ResourceID:     task.ID,
@@ -574,6 +630,28 @@ func (s *Service) createTask(ctx context.Context, tx Tx, org *influxdb.Organizat
 	return task, nil
 }
 
+// countTasks returns the number of tasks created by users in the organization.
+func (s *Service) countTasks(ctx context.Context, tx Tx, orgID influxdb.ID) (int, error) {
+	typ := influxdb.TaskSystemType
+	filter := influxdb.TaskFilter{
+		Type:  &typ,
+		Limit: influxdb.TaskMaxPageSize,
+	}
+
+	var n int
+	for {
+		ts, _, err := s.findTasksByOrg(ctx, tx, orgID, filter)
+		if err != nil {
+			return 0, err
+		}
+		n += len(ts)
+		if len(ts) < influxdb.TaskMaxPageSize {
+			return n, nil
+		}
+		filter.After = &ts[len(ts)-1].ID
+	}
+}
+
 // UpdateTask updates a single task with changeset.
 func (s *Service) UpdateTask(ctx context.Context, id influxdb.ID, upd influxdb.TaskUpdate) (*influxdb.Task, error) {
 	var t *influxdb.Task
@@ -601,6 +679,16 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 
 	updatedAt := s.clock.Now().UTC()
 
+	// tasks created before revisions were kept have no first revision, the
+	// script as it is becomes their first revision when it changes
+	var first *influxdb.TaskRevision
+	if task.Revision == 0 {
+		first = influxdb.NewTaskRevision(task, 0, task.CreatedAt)
+		first.Revision = 1
+	}
+	flux := task.Flux
+	scheduled := *task
+
 	// update the flux script
 	if !upd.Options.IsZero() || upd.Flux != nil {
 		if err = upd.UpdateFlux(ctx, s.FluxLanguageService, task.Flux); err != nil {
@@ -624,6 +712,25 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 			}
 		}
 		task.Offset = off
+
+		var debounce time.Duration
+		if opts.Debounce != nil {
+			debounce, err = time.ParseDuration(opts.Debounce.String())
+			if err != nil {
+				return nil, influxdb.ErrTaskTimeParse(err)
+			}
+		}
+		var timeout time.Duration
+		if opts.Timeout != nil {
+			timeout, err = time.ParseDuration(opts.Timeout.String())
+			if err != nil {
+				return nil, influxdb.ErrTaskTimeParse(err)
+			}
+		}
+		task.Trigger = opts.Trigger
+		task.Debounce = debounce
+		task.Timeout = timeout
+		task.Catchup = opts.Catchup
 		task.UpdatedAt = updatedAt
 	}
 
@@ -644,6 +751,20 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		}
 	}
 
+	if upd.DependsOn != nil {
+		if task.DependsOn, err = s.validateDependencies(ctx, tx, task, *upd.DependsOn); err != nil {
+			return nil, err
+		}
+		task.UpdatedAt = updatedAt
+	}
+
+	// a new schedule must still be the schedule of the upstream and dependent tasks
+	if !sameSchedule(&scheduled, task) {
+		if err := s.validateSchedule(ctx, tx, task); err != nil {
+			return nil, err
+		}
+	}
+
 	if upd.Metadata != nil {
 		task.Metadata = upd.Metadata
 		task.UpdatedAt = updatedAt
@@ -688,7 +809,7 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 
 	if upd.LastRunStatus != nil {
 		task.LastRunStatus = *upd.LastRunStatus
-		if *upd.LastRunStatus == "failed" && upd.LastRunError != nil {
+		if failedRunStatus(*upd.LastRunStatus) && upd.LastRunError != nil {
 			task.LastRunError = *upd.LastRunError
 		} else {
 			task.LastRunError = ""
@@ -705,6 +826,26 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		return nil, err
 	}
 
+	uid, _ := icontext.GetUserID(ctx)
+
+	// every change to the script makes a new revision
+	if task.Flux != flux {
+		if first != nil {
+			if err := s.putTaskRevision(ctx, tx, first); err != nil {
+				return nil, err
+			}
+			task.Revision = first.Revision
+		}
+		task.Revision++
+		rev := influxdb.NewTaskRevision(task, uid, updatedAt)
+		if upd.RollbackOf != nil {
+			rev.RollbackOf = *upd.RollbackOf
+		}
+		if err := s.putTaskRevision(ctx, tx, rev); err != nil {
+			return nil, err
+		}
+	}
+
 	taskBytes, err := json.Marshal(task)
 	if err != nil {
 		return nil, influxdb.ErrInternalTaskServiceError(err)
@@ -715,7 +856,6 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
 
-	uid, _ := icontext.GetUserID(ctx)
 	if err := s.audit.Log(resource.Change{
 		Type:           resource.Update,
 		ResourceID:     task.ID,
@@ -731,6 +871,133 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 	return task, nil
 }
 
+// validateDependencies returns the upstream tasks of the task without
+// duplicates. They must be tasks of the same organization with the same
+// schedule, and must not depend on the task themselves, directly or through
+// other tasks.
+func (s *Service) validateDependencies(ctx context.Context, tx Tx, task *influxdb.Task, deps []influxdb.ID) ([]influxdb.ID, error) {
+	var (
+		upstream []influxdb.ID
+		seen     = make(map[influxdb.ID]bool, len(deps))
+	)
+	for _, id := range deps {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if id == task.ID {
+			return nil, influxdb.ErrTaskDependencyCycle
+		}
+		t, err := s.findTaskByID(ctx, tx, id)
+		if err == influxdb.ErrTaskNotFound || (err == nil && t.OrganizationID != task.OrganizationID) {
+			return nil, influxdb.ErrTaskDependencyNotFound(id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !dependable(t, task) {
+			return nil, influxdb.ErrTaskDependencySchedule(t.ID, task.ID)
+		}
+		upstream = append(upstream, id)
+	}
+
+	// walk the upstream tasks of the new dependencies, reaching the task means a cycle
+	visited := make(map[influxdb.ID]bool)
+	queue := append([]influxdb.ID{}, upstream...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+
+		t, err := s.findTaskByID(ctx, tx, id)
+		if err == influxdb.ErrTaskNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, up := range t.DependsOn {
+			if up == task.ID {
+				return nil, influxdb.ErrTaskDependencyCycle
+			}
+			queue = append(queue, up)
+		}
+	}
+
+	return upstream, nil
+}
+
+// validateSchedule checks that the upstream and dependent tasks of the task
+// still have its schedule.
+func (s *Service) validateSchedule(ctx context.Context, tx Tx, task *influxdb.Task) error {
+	for _, id := range task.DependsOn {
+		t, err := s.findTaskByID(ctx, tx, id)
+		if err == influxdb.ErrTaskNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !dependable(t, task) {
+			return influxdb.ErrTaskDependencySchedule(t.ID, task.ID)
+		}
+	}
+
+	dependents, err := s.findDependentTasks(ctx, tx, task)
+	if err != nil {
+		return err
+	}
+	for _, t := range dependents {
+		if !dependable(task, t) {
+			return influxdb.ErrTaskDependencySchedule(task.ID, t.ID)
+		}
+	}
+	return nil
+}
+
+// dependable reports whether a task can depend on the upstream task. A run
+// waits for the runs of the upstream tasks scheduled for the same time, so
+// both tasks must have the same every or cron schedule, and runs of triggered
+// tasks never line up.
+func dependable(upstream, task *influxdb.Task) bool {
+	return sameSchedule(upstream, task) && task.Trigger == ""
+}
+
+// sameSchedule reports whether the tasks are scheduled alike.
+func sameSchedule(a, b *influxdb.Task) bool {
+	return a.Every == b.Every && a.Cron == b.Cron && a.Trigger == b.Trigger
+}
+
+// findDependentTasks returns the tasks of the organization of the task that depend on it.
+func (s *Service) findDependentTasks(ctx context.Context, tx Tx, task *influxdb.Task) ([]*influxdb.Task, error) {
+	var (
+		dependents []*influxdb.Task
+		filter     = influxdb.TaskFilter{Limit: influxdb.TaskMaxPageSize}
+	)
+	for {
+		ts, _, err := s.findTasksByOrg(ctx, tx, task.OrganizationID, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range ts {
+			for _, up := range t.DependsOn {
+				if up == task.ID {
+					dependents = append(dependents, t)
+					break
+				}
+			}
+		}
+		if len(ts) < filter.Limit {
+			return dependents, nil
+		}
+		filter.After = &ts[len(ts)-1].ID
+	}
+}
+
 // DeleteTask removes a task by ID and purges all associated data and scheduled runs.
 func (s *Service) DeleteTask(ctx context.Context, id influxdb.ID) error {
 	err := s.kv.Update(ctx, func(tx Tx) error {
@@ -769,6 +1036,14 @@ func (s *Service) deleteTask(ctx context.Context, tx Tx, id influxdb.ID) error {
 		return err
 	}
 
+	dependents, err := s.findDependentTasks(ctx, tx, task)
+	if err != nil {
+		return err
+	}
+	if len(dependents) > 0 {
+		return influxdb.ErrTaskHasDependents
+	}
+
 	// remove the orgs index
 	orgKey, err := taskOrgKey(task.OrganizationID, task.ID)
 	if err != nil {
@@ -805,6 +1080,11 @@ func (s *Service) deleteTask(ctx context.Context, tx Tx, id influxdb.ID) error {
 			return influxdb.ErrUnexpectedTaskBucketErr(err)
 		}
 	}
+	// remove the revisions
+	if err := s.deleteTaskRevisions(ctx, tx, task.ID); err != nil {
+		return err
+	}
+
 	// remove the task
 	key, err := taskKey(task.ID)
 	if err != nil {
@@ -826,6 +1106,189 @@ func (s *Service) deleteTask(ctx context.Context, tx Tx, id influxdb.ID) error {
 	})
 }
 
+// FindTaskRevisions returns the revisions of a task, oldest first.
+func (s *Service) FindTaskRevisions(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskRevision, error) {
+	var revs []*influxdb.TaskRevision
+	err := s.kv.View(ctx, func(tx Tx) error {
+		rs, err := s.findTaskRevisions(ctx, tx, taskID)
+		if err != nil {
+			return err
+		}
+		revs = rs
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return revs, nil
+}
+
+func (s *Service) findTaskRevisions(ctx context.Context, tx Tx, taskID influxdb.ID) ([]*influxdb.TaskRevision, error) {
+	task, err := s.findTaskByID(ctx, tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, err := tx.Bucket(taskRevisionBucket)
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	prefix, err := taskRevisionPrefix(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := bucket.ForwardCursor(prefix, WithCursorPrefix(prefix))
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	defer c.Close()
+
+	revs := []*influxdb.TaskRevision{}
+	for k, v := c.Next(); k != nil; k, v = c.Next() {
+		rev := &influxdb.TaskRevision{}
+		if err := json.Unmarshal(v, rev); err != nil {
+			return nil, influxdb.ErrInternalTaskServiceError(err)
+		}
+		revs = append(revs, rev)
+	}
+	if err := c.Err(); err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	// a task that was never updated since revisions were kept has its script
+	// as only revision
+	if len(revs) == 0 {
+		rev := influxdb.NewTaskRevision(task, 0, task.CreatedAt)
+		rev.Revision = 1
+		revs = append(revs, rev)
+	}
+
+	return revs, nil
+}
+
+// FindTaskRevision returns a single revision of a task.
+func (s *Service) FindTaskRevision(ctx context.Context, taskID influxdb.ID, revision int) (*influxdb.TaskRevision, error) {
+	var rev *influxdb.TaskRevision
+	err := s.kv.View(ctx, func(tx Tx) error {
+		r, err := s.findTaskRevision(ctx, tx, taskID, revision)
+		if err != nil {
+			return err
+		}
+		rev = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rev, nil
+}
+
+func (s *Service) findTaskRevision(ctx context.Context, tx Tx, taskID influxdb.ID, revision int) (*influxdb.TaskRevision, error) {
+	revs, err := s.findTaskRevisions(ctx, tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rev := range revs {
+		if rev.Revision == revision {
+			return rev, nil
+		}
+	}
+
+	return nil, influxdb.ErrTaskRevisionNotFound
+}
+
+// RollbackTask updates the task to the script of one of its revisions,
+// making a new revision. Like UpdateTask, it does not reschedule the task.
+func (s *Service) RollbackTask(ctx context.Context, taskID influxdb.ID, revision int) (*influxdb.Task, error) {
+	var t *influxdb.Task
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		task, err := s.rollbackTask(ctx, tx, taskID, revision)
+		if err != nil {
+			return err
+		}
+		t = task
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (s *Service) rollbackTask(ctx context.Context, tx Tx, taskID influxdb.ID, revision int) (*influxdb.Task, error) {
+	rev, err := s.findTaskRevision(ctx, tx, taskID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateTask(ctx, tx, taskID, influxdb.TaskUpdate{Flux: &rev.Flux, RollbackOf: &revision})
+}
+
+func (s *Service) putTaskRevision(ctx context.Context, tx Tx, rev *influxdb.TaskRevision) error {
+	bucket, err := tx.Bucket(taskRevisionBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	key, err := taskRevisionKey(rev.TaskID, rev.Revision)
+	if err != nil {
+		return err
+	}
+
+	revBytes, err := json.Marshal(rev)
+	if err != nil {
+		return influxdb.ErrInternalTaskServiceError(err)
+	}
+
+	if err := bucket.Put(key, revBytes); err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	return nil
+}
+
+func (s *Service) deleteTaskRevisions(ctx context.Context, tx Tx, taskID influxdb.ID) error {
+	bucket, err := tx.Bucket(taskRevisionBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	prefix, err := taskRevisionPrefix(taskID)
+	if err != nil {
+		return err
+	}
+
+	c, err := bucket.ForwardCursor(prefix, WithCursorPrefix(prefix))
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	var keys [][]byte
+	for k, _ := c.Next(); k != nil; k, _ = c.Next() {
+		keys = append(keys, k)
+	}
+	if err := c.Err(); err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	if err := c.Close(); err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	for _, k := range keys {
+		if err := bucket.Delete(k); err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+	}
+
+	return nil
+}
+
 // FindLogs returns logs for a run.
 func (s *Service) FindLogs(ctx context.Context, filter influxdb.LogFilter) ([]*influxdb.Log, int, error) {
 	var logs []*influxdb.Log
@@ -1171,7 +1634,21 @@ func (s *Service) forceRun(ctx context.Context, tx Tx, taskID influxdb.ID, sched
 func (s *Service) CreateRun(ctx context.Context, taskID influxdb.ID, scheduledFor time.Time, runAt time.Time) (*influxdb.Run, error) {
 	var r *influxdb.Run
 	err := s.kv.Update(ctx, func(tx Tx) error {
-		run, err := s.createRun(ctx, tx, taskID, scheduledFor, runAt)
+		run, err := s.createRun(ctx, tx, taskID, scheduledFor, runAt, 0, 0)
+		if err != nil {
+			return err
+		}
+		r = run
+		return nil
+	})
+	return r, err
+}
+
+// CreateRetryRun creates a run retrying the failed run retryOf.
+func (s *Service) CreateRetryRun(ctx context.Context, taskID, retryOf influxdb.ID, attempt int, scheduledFor time.Time, runAt time.Time) (*influxdb.Run, error) {
+	var r *influxdb.Run
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		run, err := s.createRun(ctx, tx, taskID, scheduledFor, runAt, retryOf, attempt)
 		if err != nil {
 			return err
 		}
@@ -1180,7 +1657,13 @@ func (s *Service) CreateRun(ctx context.Context, taskID influxdb.ID, scheduledFo
 	})
 	return r, err
 }
-func (s *Service) createRun(ctx context.Context, tx Tx, taskID influxdb.ID, scheduledFor time.Time, runAt time.Time) (*influxdb.Run, error) {
+
+func (s *Service) createRun(ctx context.Context, tx Tx, taskID influxdb.ID, scheduledFor time.Time, runAt time.Time, retryOf influxdb.ID, attempt int) (*influxdb.Run, error) {
+	task, err := s.findTaskByID(ctx, tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
 	id := s.IDGenerator.ID()
 	t := time.Unix(scheduledFor.Unix(), 0).UTC()
 
@@ -1190,6 +1673,9 @@ func (s *Service) createRun(ctx context.Context, tx Tx, taskID influxdb.ID, sche
 		ScheduledFor: t,
 		RunAt:        runAt,
 		Status:       influxdb.RunScheduled.String(),
+		RetryOf:      retryOf,
+		Attempt:      attempt,
+		Revision:     task.Revision,
 		Log:          []influxdb.Log{},
 	}
 
@@ -1348,6 +1834,13 @@ func (s *Service) startManualRun(ctx context.Context, tx Tx, taskID, runID influ
 		return nil, influxdb.ErrRunNotFound
 	}
 
+	// the run uses the script of the task as it is when it starts
+	task, err := s.findTaskByID(ctx, tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	run.Revision = task.Revision
+
 	// save manual runs
 	mRunsBytes, err := json.Marshal(mRuns)
 	if err != nil {
@@ -1386,6 +1879,12 @@ func (s *Service) startManualRun(ctx context.Context, tx Tx, taskID, runID influ
 	return run, nil
 }
 
+// failedRunStatus reports whether a run with the status failed, runs that
+// timed out failed too.
+func failedRunStatus(status string) bool {
+	return status == influxdb.RunFail.String() || status == influxdb.RunTimeout.String()
+}
+
 // FinishRun removes runID from the list of running tasks and if its `now` is later then last completed update it.
 func (s *Service) FinishRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
 	var run *influxdb.Run
@@ -1412,7 +1911,7 @@ func (s *Service) finishRun(ctx context.Context, tx Tx, taskID, runID influxdb.I
 
 	var latestSuccess, latestFailure *time.Time
 
-	if r.Status == "failed" {
+	if failedRunStatus(r.Status) {
 		latestFailure = &scheduled
 	} else {
 		latestSuccess = &scheduled
@@ -1424,7 +1923,7 @@ func (s *Service) finishRun(ctx context.Context, tx Tx, taskID, runID influxdb.I
 		LatestFailure:   latestFailure,
 		LastRunStatus:   &r.Status,
 		LastRunError: func() *string {
-			if r.Status == "failed" {
+			if failedRunStatus(r.Status) {
 				// prefer the second to last log message as the error message
 				// per https://github.com/influxdata/influxdb/issues/15153#issuecomment-547706005
 				if len(r.Log) > 1 {
@@ -1480,7 +1979,7 @@ func (s *Service) updateRunState(ctx context.Context, tx Tx, taskID, runID influ
 	switch state {
 	case influxdb.RunStarted:
 		run.StartedAt = when
-	case influxdb.RunSuccess, influxdb.RunFail, influxdb.RunCanceled:
+	case influxdb.RunSuccess, influxdb.RunFail, influxdb.RunCanceled, influxdb.RunTimeout:
 		run.FinishedAt = when
 	}
 
@@ -1600,6 +2099,23 @@ func taskRunKey(taskID, runID influxdb.ID) ([]byte, error) {
 	return []byte(string(encodedID) + "/" + string(encodedRunID)), nil
 }
 
+func taskRevisionPrefix(taskID influxdb.ID) ([]byte, error) {
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return nil, influxdb.ErrInvalidTaskID
+	}
+	return []byte(string(encodedID) + "/"), nil
+}
+
+func taskRevisionKey(taskID influxdb.ID, revision int) ([]byte, error) {
+	prefix, err := taskRevisionPrefix(taskID)
+	if err != nil {
+		return nil, err
+	}
+	// pad the revision so that revisions sort in order
+	return []byte(fmt.Sprintf("%s%010d", prefix, revision)), nil
+}
+
 // ExtractTaskOptions is a feature-flag driven switch between normal options
 // parsing and a more simplified variant.
 //
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
@@ -266,6 +267,128 @@ func TestService_UpdateTask_InactiveToActive(t *testing.T) {
 	}
 }
 
+func TestService_TaskDependencies(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+	defer ts.Close()
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	create := func(name string, dependsOn ...influxdb.ID) (*influxdb.Task, error) {
+		return ts.Service.CreateTask(ctx, influxdb.TaskCreate{
+			Flux:           fmt.Sprintf(`option task = {name: %q, every: 1h} from(bucket:"test") |> range(start:-1h)`, name),
+			OrganizationID: ts.Org.ID,
+			OwnerID:        ts.User.ID,
+			DependsOn:      dependsOn,
+		})
+	}
+
+	a, err := create("a")
+	require.NoError(t, err)
+	b, err := create("b", a.ID, a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []influxdb.ID{a.ID}, b.DependsOn)
+	c, err := create("c", b.ID)
+	require.NoError(t, err)
+
+	_, err = create("d", influxdb.ID(1))
+	assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+
+	// a -> b -> c -> a would be a cycle
+	_, err = ts.Service.UpdateTask(ctx, a.ID, influxdb.TaskUpdate{DependsOn: &[]influxdb.ID{c.ID}})
+	assert.Equal(t, influxdb.ErrTaskDependencyCycle, err)
+	_, err = ts.Service.UpdateTask(ctx, a.ID, influxdb.TaskUpdate{DependsOn: &[]influxdb.ID{a.ID}})
+	assert.Equal(t, influxdb.ErrTaskDependencyCycle, err)
+
+	assert.Equal(t, influxdb.ErrTaskHasDependents, ts.Service.DeleteTask(ctx, a.ID))
+
+	// upstream tasks must have the schedule of the tasks depending on them
+	_, err = ts.Service.CreateTask(ctx, influxdb.TaskCreate{
+		Flux:           `option task = {name: "e", every: 30m} from(bucket:"test") |> range(start:-1h)`,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+		DependsOn:      []influxdb.ID{a.ID},
+	})
+	assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+	every := `option task = {name: "b", every: 30m} from(bucket:"test") |> range(start:-1h)`
+	_, err = ts.Service.UpdateTask(ctx, b.ID, influxdb.TaskUpdate{Flux: &every})
+	assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+
+	// removing the dependency of b allows a to be deleted
+	b, err = ts.Service.UpdateTask(ctx, b.ID, influxdb.TaskUpdate{DependsOn: &[]influxdb.ID{}})
+	require.NoError(t, err)
+	assert.Empty(t, b.DependsOn)
+	require.NoError(t, ts.Service.DeleteTask(ctx, a.ID))
+}
+
+func TestService_TaskRevisions(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+	defer ts.Close()
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	script := func(every string) string {
+		return fmt.Sprintf(`option task = {name: "a task", every: %s} from(bucket:"test") |> range(start:-1h)`, every)
+	}
+
+	task, err := ts.Service.CreateTask(ctx, influxdb.TaskCreate{
+		Flux:           script("1h"),
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, task.Revision)
+
+	for _, every := range []string{"30m", "10m"} {
+		flux := script(every)
+		task, err = ts.Service.UpdateTask(ctx, task.ID, influxdb.TaskUpdate{Flux: &flux})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, task.Revision)
+
+	// updates that leave the script alone make no revision
+	desc := "a description"
+	task, err = ts.Service.UpdateTask(ctx, task.ID, influxdb.TaskUpdate{Description: &desc})
+	require.NoError(t, err)
+	assert.Equal(t, 3, task.Revision)
+
+	revs, err := ts.Service.FindTaskRevisions(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, revs, 3)
+	for i, every := range []string{"1h", "30m", "10m"} {
+		assert.Equal(t, i+1, revs[i].Revision)
+		assert.Equal(t, every, revs[i].Every)
+		assert.Equal(t, ts.User.ID, revs[i].AuthorID)
+	}
+
+	_, err = ts.Service.FindTaskRevision(ctx, task.ID, 4)
+	assert.Equal(t, influxdb.ErrTaskRevisionNotFound, err)
+
+	task, err = ts.Service.RollbackTask(ctx, task.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 4, task.Revision)
+	assert.Equal(t, "1h", task.Every)
+
+	rev, err := ts.Service.FindTaskRevision(ctx, task.ID, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rev.RollbackOf)
+	assert.Equal(t, task.Flux, rev.Flux)
+
+	// runs record the revision they used
+	run, err := ts.Service.CreateRun(ctx, task.ID, time.Now(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 4, run.Revision)
+
+	require.NoError(t, ts.Service.DeleteTask(ctx, task.ID))
+	_, err = ts.Service.FindTaskRevisions(ctx, task.ID)
+	assert.Equal(t, influxdb.ErrTaskNotFound, err)
+}
+
 func TestTaskRunCancellation(t *testing.T) {
 	store, close, err := NewTestBoltStore(t)
 	if err != nil {
@@ -0,0 +1,94 @@
+package influxdb
+
+import (
+	"context"
+)
+
+// MessageTemplate is a message template of an organization that checks and
+// notification rules reference instead of their own message templates. The
+// template is written in the templating language of the
+// notification/template package, placeholders such as {{ check_name }} or
+// {{ tags.host }} are replaced by the values of every status.
+type MessageTemplate struct {
+	ID          ID     `json:"id,omitempty"`
+	OrgID       ID     `json:"orgID"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Template    string `json:"template"`
+	// RunbookURL is rendered by the {{ runbook }} placeholder.
+	RunbookURL string `json:"runbookURL,omitempty"`
+
+	CRUDLog
+}
+
+// Valid returns an error if the message template is invalid. The syntax of
+// the template is validated by the service that stores it.
+func (t *MessageTemplate) Valid() error {
+	if !t.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "message template requires an organization id",
+		}
+	}
+	if t.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "message template name can't be empty",
+		}
+	}
+	if t.Template == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "message template can't be empty",
+		}
+	}
+	return nil
+}
+
+// MessageTemplateFilter represents a set of filters that restrict the returned message templates.
+type MessageTemplateFilter struct {
+	OrgID *ID
+	Name  *string
+}
+
+// MessageTemplateUpdate is the set of changes of a message template, nil fields are left unchanged.
+type MessageTemplateUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Template    *string `json:"template,omitempty"`
+	RunbookURL  *string `json:"runbookURL,omitempty"`
+}
+
+// Apply applies the update to the message template.
+func (u MessageTemplateUpdate) Apply(t *MessageTemplate) {
+	if u.Name != nil {
+		t.Name = *u.Name
+	}
+	if u.Description != nil {
+		t.Description = *u.Description
+	}
+	if u.Template != nil {
+		t.Template = *u.Template
+	}
+	if u.RunbookURL != nil {
+		t.RunbookURL = *u.RunbookURL
+	}
+}
+
+// MessageTemplateService manages the message templates of organizations.
+type MessageTemplateService interface {
+	// FindMessageTemplateByID returns a single message template by ID.
+	FindMessageTemplateByID(ctx context.Context, id ID) (*MessageTemplate, error)
+
+	// FindMessageTemplates returns the message templates matching the filter.
+	FindMessageTemplates(ctx context.Context, filter MessageTemplateFilter) ([]*MessageTemplate, error)
+
+	// CreateMessageTemplate creates a new message template and sets t.ID with the new identifier.
+	CreateMessageTemplate(ctx context.Context, t *MessageTemplate) error
+
+	// UpdateMessageTemplate updates a single message template and returns the new message template.
+	UpdateMessageTemplate(ctx context.Context, id ID, upd MessageTemplateUpdate) (*MessageTemplate, error)
+
+	// DeleteMessageTemplate removes a message template by ID.
+	DeleteMessageTemplate(ctx context.Context, id ID) error
+}
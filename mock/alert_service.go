@@ -0,0 +1,55 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.AlertService = (*AlertService)(nil)
+
+// AlertService is a mock implementation of influxdb.AlertService.
+type AlertService struct {
+	FindAlertByIDFn    func(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error)
+	FindAlertsFn       func(ctx context.Context, filter influxdb.AlertFilter, opt ...influxdb.FindOptions) ([]*influxdb.Alert, error)
+	AcknowledgeAlertFn func(ctx context.Context, id influxdb.ID, ack influxdb.AlertAcknowledgement) (*influxdb.Alert, error)
+	ResolveAlertFn     func(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error)
+}
+
+// NewAlertService returns a mock of AlertService without any alert.
+func NewAlertService() *AlertService {
+	return &AlertService{
+		FindAlertByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error) {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound}
+		},
+		FindAlertsFn: func(ctx context.Context, filter influxdb.AlertFilter, opt ...influxdb.FindOptions) ([]*influxdb.Alert, error) {
+			return nil, nil
+		},
+		AcknowledgeAlertFn: func(ctx context.Context, id influxdb.ID, ack influxdb.AlertAcknowledgement) (*influxdb.Alert, error) {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound}
+		},
+		ResolveAlertFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error) {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound}
+		},
+	}
+}
+
+// FindAlertByID returns a single alert by ID.
+func (s *AlertService) FindAlertByID(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error) {
+	return s.FindAlertByIDFn(ctx, id)
+}
+
+// FindAlerts returns the alerts matching the filter.
+func (s *AlertService) FindAlerts(ctx context.Context, filter influxdb.AlertFilter, opt ...influxdb.FindOptions) ([]*influxdb.Alert, error) {
+	return s.FindAlertsFn(ctx, filter, opt...)
+}
+
+// AcknowledgeAlert acknowledges an open alert.
+func (s *AlertService) AcknowledgeAlert(ctx context.Context, id influxdb.ID, ack influxdb.AlertAcknowledgement) (*influxdb.Alert, error) {
+	return s.AcknowledgeAlertFn(ctx, id, ack)
+}
+
+// ResolveAlert resolves an alert.
+func (s *AlertService) ResolveAlert(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error) {
+	return s.ResolveAlertFn(ctx, id)
+}
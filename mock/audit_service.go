@@ -0,0 +1,37 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.AuditService = (*AuditService)(nil)
+
+// AuditService is a mock implementation of influxdb.AuditService.
+type AuditService struct {
+	RecordAuditEventFn func(ctx context.Context, e *influxdb.AuditEvent) error
+	FindAuditEventsFn  func(ctx context.Context, filter influxdb.AuditEventFilter, opts ...influxdb.FindOptions) ([]*influxdb.AuditEvent, error)
+}
+
+// NewAuditService returns a mock of AuditService where its methods will return zero values.
+func NewAuditService() *AuditService {
+	return &AuditService{
+		RecordAuditEventFn: func(ctx context.Context, e *influxdb.AuditEvent) error {
+			return nil
+		},
+		FindAuditEventsFn: func(ctx context.Context, filter influxdb.AuditEventFilter, opts ...influxdb.FindOptions) ([]*influxdb.AuditEvent, error) {
+			return nil, nil
+		},
+	}
+}
+
+// RecordAuditEvent appends an event to the audit log.
+func (s *AuditService) RecordAuditEvent(ctx context.Context, e *influxdb.AuditEvent) error {
+	return s.RecordAuditEventFn(ctx, e)
+}
+
+// FindAuditEvents returns the events matching the filter.
+func (s *AuditService) FindAuditEvents(ctx context.Context, filter influxdb.AuditEventFilter, opts ...influxdb.FindOptions) ([]*influxdb.AuditEvent, error) {
+	return s.FindAuditEventsFn(ctx, filter, opts...)
+}
@@ -0,0 +1,64 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.MessageTemplateService = (*MessageTemplateService)(nil)
+
+// MessageTemplateService is a mock implementation of influxdb.MessageTemplateService.
+type MessageTemplateService struct {
+	FindMessageTemplateByIDFn func(ctx context.Context, id influxdb.ID) (*influxdb.MessageTemplate, error)
+	FindMessageTemplatesFn    func(ctx context.Context, filter influxdb.MessageTemplateFilter) ([]*influxdb.MessageTemplate, error)
+	CreateMessageTemplateFn   func(ctx context.Context, t *influxdb.MessageTemplate) error
+	UpdateMessageTemplateFn   func(ctx context.Context, id influxdb.ID, upd influxdb.MessageTemplateUpdate) (*influxdb.MessageTemplate, error)
+	DeleteMessageTemplateFn   func(ctx context.Context, id influxdb.ID) error
+}
+
+// NewMessageTemplateService returns a mock of MessageTemplateService without any message template.
+func NewMessageTemplateService() *MessageTemplateService {
+	return &MessageTemplateService{
+		FindMessageTemplateByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.MessageTemplate, error) {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound}
+		},
+		FindMessageTemplatesFn: func(ctx context.Context, filter influxdb.MessageTemplateFilter) ([]*influxdb.MessageTemplate, error) {
+			return nil, nil
+		},
+		CreateMessageTemplateFn: func(ctx context.Context, t *influxdb.MessageTemplate) error {
+			return nil
+		},
+		UpdateMessageTemplateFn: func(ctx context.Context, id influxdb.ID, upd influxdb.MessageTemplateUpdate) (*influxdb.MessageTemplate, error) {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound}
+		},
+		DeleteMessageTemplateFn: func(ctx context.Context, id influxdb.ID) error {
+			return nil
+		},
+	}
+}
+
+// FindMessageTemplateByID returns a single message template by ID.
+func (s *MessageTemplateService) FindMessageTemplateByID(ctx context.Context, id influxdb.ID) (*influxdb.MessageTemplate, error) {
+	return s.FindMessageTemplateByIDFn(ctx, id)
+}
+
+// FindMessageTemplates returns the message templates matching the filter.
+func (s *MessageTemplateService) FindMessageTemplates(ctx context.Context, filter influxdb.MessageTemplateFilter) ([]*influxdb.MessageTemplate, error) {
+	return s.FindMessageTemplatesFn(ctx, filter)
+}
+
+// CreateMessageTemplate creates a new message template.
+func (s *MessageTemplateService) CreateMessageTemplate(ctx context.Context, t *influxdb.MessageTemplate) error {
+	return s.CreateMessageTemplateFn(ctx, t)
+}
+
+// UpdateMessageTemplate updates a single message template.
+func (s *MessageTemplateService) UpdateMessageTemplate(ctx context.Context, id influxdb.ID, upd influxdb.MessageTemplateUpdate) (*influxdb.MessageTemplate, error) {
+	return s.UpdateMessageTemplateFn(ctx, id, upd)
+}
+
+// DeleteMessageTemplate removes a message template by ID.
+func (s *MessageTemplateService) DeleteMessageTemplate(ctx context.Context, id influxdb.ID) error {
+	return s.DeleteMessageTemplateFn(ctx, id)
+}
@@ -0,0 +1,25 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.NotificationTestService = (*NotificationTestService)(nil)
+
+// NotificationTestService is a mock implementation of influxdb.NotificationTestService.
+type NotificationTestService struct {
+	TestNotificationEndpointFn func(ctx context.Context, id influxdb.ID) (*influxdb.NotificationTestResult, error)
+	TestNotificationRuleFn     func(ctx context.Context, id influxdb.ID) (*influxdb.NotificationTestResult, error)
+}
+
+// TestNotificationEndpoint sends a test notification to the endpoint.
+func (s *NotificationTestService) TestNotificationEndpoint(ctx context.Context, id influxdb.ID) (*influxdb.NotificationTestResult, error) {
+	return s.TestNotificationEndpointFn(ctx, id)
+}
+
+// TestNotificationRule sends a test notification formatted by the rule.
+func (s *NotificationTestService) TestNotificationRule(ctx context.Context, id influxdb.ID) (*influxdb.NotificationTestResult, error) {
+	return s.TestNotificationRuleFn(ctx, id)
+}
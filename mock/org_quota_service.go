@@ -0,0 +1,55 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.OrgQuotaService = (*OrgQuotaService)(nil)
+
+// OrgQuotaService is a mock implementation of influxdb.OrgQuotaService.
+type OrgQuotaService struct {
+	FindOrgQuotaFn   func(ctx context.Context, orgID influxdb.ID) (*influxdb.OrgQuota, error)
+	FindOrgQuotasFn  func(ctx context.Context) ([]*influxdb.OrgQuota, error)
+	PutOrgQuotaFn    func(ctx context.Context, q *influxdb.OrgQuota) error
+	DeleteOrgQuotaFn func(ctx context.Context, orgID influxdb.ID) error
+}
+
+// NewOrgQuotaService returns a mock of OrgQuotaService where no organization has a quota.
+func NewOrgQuotaService() *OrgQuotaService {
+	return &OrgQuotaService{
+		FindOrgQuotaFn: func(ctx context.Context, orgID influxdb.ID) (*influxdb.OrgQuota, error) {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound}
+		},
+		FindOrgQuotasFn: func(ctx context.Context) ([]*influxdb.OrgQuota, error) {
+			return nil, nil
+		},
+		PutOrgQuotaFn: func(ctx context.Context, q *influxdb.OrgQuota) error {
+			return nil
+		},
+		DeleteOrgQuotaFn: func(ctx context.Context, orgID influxdb.ID) error {
+			return nil
+		},
+	}
+}
+
+// FindOrgQuota returns the quota of the organization.
+func (s *OrgQuotaService) FindOrgQuota(ctx context.Context, orgID influxdb.ID) (*influxdb.OrgQuota, error) {
+	return s.FindOrgQuotaFn(ctx, orgID)
+}
+
+// FindOrgQuotas returns the quotas of every organization that has one.
+func (s *OrgQuotaService) FindOrgQuotas(ctx context.Context) ([]*influxdb.OrgQuota, error) {
+	return s.FindOrgQuotasFn(ctx)
+}
+
+// PutOrgQuota creates or replaces the quota of an organization.
+func (s *OrgQuotaService) PutOrgQuota(ctx context.Context, q *influxdb.OrgQuota) error {
+	return s.PutOrgQuotaFn(ctx, q)
+}
+
+// DeleteOrgQuota removes the quota of an organization.
+func (s *OrgQuotaService) DeleteOrgQuota(ctx context.Context, orgID influxdb.ID) error {
+	return s.DeleteOrgQuotaFn(ctx, orgID)
+}
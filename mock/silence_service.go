@@ -0,0 +1,64 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.SilenceService = (*SilenceService)(nil)
+
+// SilenceService is a mock implementation of influxdb.SilenceService.
+type SilenceService struct {
+	FindSilenceByIDFn func(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error)
+	FindSilencesFn    func(ctx context.Context, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error)
+	CreateSilenceFn   func(ctx context.Context, s *influxdb.Silence) error
+	UpdateSilenceFn   func(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error)
+	DeleteSilenceFn   func(ctx context.Context, id influxdb.ID) error
+}
+
+// NewSilenceService returns a mock of SilenceService without any silence.
+func NewSilenceService() *SilenceService {
+	return &SilenceService{
+		FindSilenceByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound}
+		},
+		FindSilencesFn: func(ctx context.Context, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+			return nil, nil
+		},
+		CreateSilenceFn: func(ctx context.Context, s *influxdb.Silence) error {
+			return nil
+		},
+		UpdateSilenceFn: func(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound}
+		},
+		DeleteSilenceFn: func(ctx context.Context, id influxdb.ID) error {
+			return nil
+		},
+	}
+}
+
+// FindSilenceByID returns a single silence by ID.
+func (s *SilenceService) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	return s.FindSilenceByIDFn(ctx, id)
+}
+
+// FindSilences returns the silences matching the filter.
+func (s *SilenceService) FindSilences(ctx context.Context, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+	return s.FindSilencesFn(ctx, filter)
+}
+
+// CreateSilence creates a new silence.
+func (s *SilenceService) CreateSilence(ctx context.Context, sl *influxdb.Silence) error {
+	return s.CreateSilenceFn(ctx, sl)
+}
+
+// UpdateSilence updates a single silence.
+func (s *SilenceService) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	return s.UpdateSilenceFn(ctx, id, upd)
+}
+
+// DeleteSilence removes a silence by ID.
+func (s *SilenceService) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	return s.DeleteSilenceFn(ctx, id)
+}
@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.TaskAnalyticsService = (*TaskAnalyticsService)(nil)
+
+// TaskAnalyticsService is a mock implementation of influxdb.TaskAnalyticsService.
+type TaskAnalyticsService struct {
+	FindTaskAnalyticsFn      func(ctx context.Context, filter influxdb.TaskAnalyticsFilter) ([]*influxdb.TaskAnalytics, error)
+	FindTaskRunRetentionFn   func(ctx context.Context, orgID influxdb.ID) (*influxdb.TaskRunRetention, error)
+	UpdateTaskRunRetentionFn func(ctx context.Context, orgID influxdb.ID, retention time.Duration) (*influxdb.TaskRunRetention, error)
+}
+
+// FindTaskAnalytics returns the analytics of the tasks matching the filter.
+func (s *TaskAnalyticsService) FindTaskAnalytics(ctx context.Context, filter influxdb.TaskAnalyticsFilter) ([]*influxdb.TaskAnalytics, error) {
+	return s.FindTaskAnalyticsFn(ctx, filter)
+}
+
+// FindTaskRunRetention returns the run history retention of the organization.
+func (s *TaskAnalyticsService) FindTaskRunRetention(ctx context.Context, orgID influxdb.ID) (*influxdb.TaskRunRetention, error) {
+	return s.FindTaskRunRetentionFn(ctx, orgID)
+}
+
+// UpdateTaskRunRetention changes the run history retention of the organization.
+func (s *TaskAnalyticsService) UpdateTaskRunRetention(ctx context.Context, orgID influxdb.ID, retention time.Duration) (*influxdb.TaskRunRetention, error) {
+	return s.UpdateTaskRunRetentionFn(ctx, orgID, retention)
+}
@@ -0,0 +1,20 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.TaskDryRunService = (*TaskDryRunService)(nil)
+
+// TaskDryRunService is a mock implementation of influxdb.TaskDryRunService.
+type TaskDryRunService struct {
+	DryRunTaskFn func(ctx context.Context, taskID influxdb.ID, now time.Time) (*influxdb.TaskDryRun, error)
+}
+
+// DryRunTask runs the script of the task without writing its results.
+func (s *TaskDryRunService) DryRunTask(ctx context.Context, taskID influxdb.ID, now time.Time) (*influxdb.TaskDryRun, error) {
+	return s.DryRunTaskFn(ctx, taskID, now)
+}
@@ -0,0 +1,31 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.TaskRevisionService = (*TaskRevisionService)(nil)
+
+// TaskRevisionService is a mock implementation of influxdb.TaskRevisionService.
+type TaskRevisionService struct {
+	FindTaskRevisionsFn func(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskRevision, error)
+	FindTaskRevisionFn  func(ctx context.Context, taskID influxdb.ID, revision int) (*influxdb.TaskRevision, error)
+	RollbackTaskFn      func(ctx context.Context, taskID influxdb.ID, revision int) (*influxdb.Task, error)
+}
+
+// FindTaskRevisions returns the revisions of a task.
+func (s *TaskRevisionService) FindTaskRevisions(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskRevision, error) {
+	return s.FindTaskRevisionsFn(ctx, taskID)
+}
+
+// FindTaskRevision returns a single revision of a task.
+func (s *TaskRevisionService) FindTaskRevision(ctx context.Context, taskID influxdb.ID, revision int) (*influxdb.TaskRevision, error) {
+	return s.FindTaskRevisionFn(ctx, taskID, revision)
+}
+
+// RollbackTask updates the task to the script of one of its revisions.
+func (s *TaskRevisionService) RollbackTask(ctx context.Context, taskID influxdb.ID, revision int) (*influxdb.Task, error) {
+	return s.RollbackTaskFn(ctx, taskID, revision)
+}
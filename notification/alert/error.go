@@ -0,0 +1,36 @@
+package alert
+
+import (
+	"github.com/influxdata/influxdb/v2"
+)
+
+var (
+	// ErrAlertNotFound is used when the alert is not found.
+	ErrAlertNotFound = &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "alert not found",
+	}
+
+	// ErrAlertResolved is used when acknowledging an alert that is resolved.
+	ErrAlertResolved = &influxdb.Error{
+		Code: influxdb.EConflict,
+		Msg:  "alert is resolved",
+	}
+)
+
+// ErrInvalidAlertID is used when the ID of an alert cannot be encoded.
+func ErrInvalidAlertID(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "invalid alert ID",
+		Err:  err,
+	}
+}
+
+// ErrInternalService is used when the error comes from an internal system.
+func ErrInternalService(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Err:  err,
+	}
+}
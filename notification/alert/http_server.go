@@ -0,0 +1,178 @@
+package alert
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/influxdata/influxdb/v2"
+	pctx "github.com/influxdata/influxdb/v2/context"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"go.uber.org/zap"
+)
+
+// PrefixAlerts is the prefix of the alert API.
+const PrefixAlerts = "/api/v2/alerts"
+
+// Handler serves the alerts of checks.
+type Handler struct {
+	chi.Router
+	api      *kithttp.API
+	log      *zap.Logger
+	alertSvc influxdb.AlertService
+}
+
+// NewHTTPHandler constructs a new http server for alerts.
+func NewHTTPHandler(log *zap.Logger, svc influxdb.AlertService) *Handler {
+	h := &Handler{
+		api:      kithttp.NewAPI(kithttp.WithLog(log)),
+		log:      log,
+		alertSvc: svc,
+	}
+
+	r := chi.NewRouter()
+	r.Use(
+		middleware.Recoverer,
+		middleware.RequestID,
+		middleware.RealIP,
+	)
+
+	r.Route("/", func(r chi.Router) {
+		r.Get("/", h.handleGetAlerts)
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h.handleGetAlert)
+			r.Post("/ack", h.handlePostAlertAck)
+			r.Post("/resolve", h.handlePostAlertResolve)
+		})
+	})
+
+	h.Router = r
+	return h
+}
+
+// Prefix returns the prefix the handler is mounted at.
+func (h *Handler) Prefix() string {
+	return PrefixAlerts
+}
+
+type alertsResponse struct {
+	Alerts []*influxdb.Alert `json:"alerts"`
+}
+
+// handleGetAlerts is the HTTP handler for the GET /api/v2/alerts route.
+// The orgID, checkID and state query parameters filter the alerts, the
+// offset and limit query parameters page them.
+func (h *Handler) handleGetAlerts(w http.ResponseWriter, r *http.Request) {
+	var filter influxdb.AlertFilter
+	q := r.URL.Query()
+	if orgID := q.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			h.api.Err(w, r, err)
+			return
+		}
+		filter.OrgID = id
+	}
+	if checkID := q.Get("checkID"); checkID != "" {
+		id, err := influxdb.IDFromString(checkID)
+		if err != nil {
+			h.api.Err(w, r, err)
+			return
+		}
+		filter.CheckID = id
+	}
+	if state := q.Get("state"); state != "" {
+		s := influxdb.AlertState(state)
+		if err := s.Valid(); err != nil {
+			h.api.Err(w, r, err)
+			return
+		}
+		filter.State = &s
+	}
+
+	opts, err := influxdb.DecodeFindOptions(r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	as, err := h.alertSvc.FindAlerts(r.Context(), filter, *opts)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Alerts retrieved", zap.Int("count", len(as)))
+
+	h.api.Respond(w, r, http.StatusOK, alertsResponse{Alerts: as})
+}
+
+// handleGetAlert is the HTTP handler for the GET /api/v2/alerts/:id route.
+func (h *Handler) handleGetAlert(w http.ResponseWriter, r *http.Request) {
+	id, err := influxdb.IDFromString(chi.URLParam(r, "id"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	a, err := h.alertSvc.FindAlertByID(r.Context(), *id)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	h.api.Respond(w, r, http.StatusOK, a)
+}
+
+// handlePostAlertAck is the HTTP handler for the POST /api/v2/alerts/:id/ack route.
+// The body is optional, it may assign the alert to another user.
+func (h *Handler) handlePostAlertAck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := influxdb.IDFromString(chi.URLParam(r, "id"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	var ack influxdb.AlertAcknowledgement
+	if r.ContentLength != 0 {
+		if err := h.api.DecodeJSON(r.Body, &ack); err != nil {
+			h.api.Err(w, r, err)
+			return
+		}
+	}
+
+	auth, err := pctx.GetAuthorizer(ctx)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	ack.UserID = auth.GetUserID()
+
+	a, err := h.alertSvc.AcknowledgeAlert(ctx, *id, ack)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Alert acknowledged", zap.String("id", id.String()))
+
+	h.api.Respond(w, r, http.StatusOK, a)
+}
+
+// handlePostAlertResolve is the HTTP handler for the POST /api/v2/alerts/:id/resolve route.
+func (h *Handler) handlePostAlertResolve(w http.ResponseWriter, r *http.Request) {
+	id, err := influxdb.IDFromString(chi.URLParam(r, "id"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	a, err := h.alertSvc.ResolveAlert(r.Context(), *id)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Alert resolved", zap.String("id", id.String()))
+
+	h.api.Respond(w, r, http.StatusOK, a)
+}
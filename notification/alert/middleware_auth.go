@@ -0,0 +1,81 @@
+package alert
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+)
+
+var _ influxdb.AlertService = (*AuthorizedService)(nil)
+
+// AuthorizedService restricts access to alerts. Alerts are raised by checks,
+// so they require the same permissions as the checks of their organization.
+type AuthorizedService struct {
+	s influxdb.AlertService
+}
+
+// NewAuthorizedService wraps an alert service with authorization checks.
+func NewAuthorizedService(s influxdb.AlertService) *AuthorizedService {
+	return &AuthorizedService{s: s}
+}
+
+// FindAlertByID checks that the authorizer may read the check of the alert.
+func (s *AuthorizedService) FindAlertByID(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error) {
+	a, err := s.s.FindAlertByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := authorizer.AuthorizeRead(ctx, influxdb.ChecksResourceType, a.CheckID, a.OrgID); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// FindAlerts returns only the alerts of checks the authorizer may read.
+func (s *AuthorizedService) FindAlerts(ctx context.Context, filter influxdb.AlertFilter, opt ...influxdb.FindOptions) ([]*influxdb.Alert, error) {
+	as, err := s.s.FindAlerts(ctx, filter, opt...)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	aas := as[:0]
+	for _, a := range as {
+		_, _, err := authorizer.AuthorizeRead(ctx, influxdb.ChecksResourceType, a.CheckID, a.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		aas = append(aas, a)
+	}
+	return aas, nil
+}
+
+// AcknowledgeAlert checks that the authorizer may write the check of the alert.
+func (s *AuthorizedService) AcknowledgeAlert(ctx context.Context, id influxdb.ID, ack influxdb.AlertAcknowledgement) (*influxdb.Alert, error) {
+	if err := s.authorizeWrite(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.s.AcknowledgeAlert(ctx, id, ack)
+}
+
+// ResolveAlert checks that the authorizer may write the check of the alert.
+func (s *AuthorizedService) ResolveAlert(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error) {
+	if err := s.authorizeWrite(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.s.ResolveAlert(ctx, id)
+}
+
+func (s *AuthorizedService) authorizeWrite(ctx context.Context, id influxdb.ID) error {
+	a, err := s.s.FindAlertByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	_, _, err = authorizer.AuthorizeWrite(ctx, influxdb.ChecksResourceType, a.CheckID, a.OrgID)
+	return err
+}
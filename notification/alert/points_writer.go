@@ -0,0 +1,220 @@
+package alert
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage"
+	"go.uber.org/zap"
+)
+
+// Recorder records the statuses of checks and the escalations of notification
+// rules into alerts.
+type Recorder interface {
+	RecordStatus(ctx context.Context, st Status) (*influxdb.Alert, error)
+	RecordEscalation(ctx context.Context, esc Escalation) (*influxdb.Alert, error)
+}
+
+// CheckFinder finds the checks whose statuses are recorded into alerts.
+type CheckFinder interface {
+	FindCheckByID(ctx context.Context, id influxdb.ID) (influxdb.Check, error)
+}
+
+// CheckFinderFunc adapts a function to a CheckFinder.
+type CheckFinderFunc func(ctx context.Context, id influxdb.ID) (influxdb.Check, error)
+
+// FindCheckByID calls f.
+func (f CheckFinderFunc) FindCheckByID(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+	return f(ctx, id)
+}
+
+var _ storage.PointsWriter = (*PointsWriter)(nil)
+
+// PointsWriter tracks the alerts of checks from the statuses that check tasks
+// write. Statuses are points of the statuses measurement with a _check_id and
+// a _level tag, they are recorded once the points are written. The sent
+// notifications of escalation steps, points of the notifications measurement
+// with an _escalation_step tag, are recorded on the alerts as well. Only the
+// points written to the _monitoring bucket of the organization, about checks
+// of the organization, are recorded. Failing to record a point is logged and
+// does not fail the write.
+type PointsWriter struct {
+	storage.PointsWriter
+	log     *zap.Logger
+	buckets influxdb.BucketService
+	checks  CheckFinder
+	alerts  Recorder
+}
+
+// NewPointsWriter wraps a points writer with alert tracking.
+func NewPointsWriter(log *zap.Logger, w storage.PointsWriter, buckets influxdb.BucketService, checks CheckFinder, alerts Recorder) *PointsWriter {
+	return &PointsWriter{
+		PointsWriter: w,
+		log:          log,
+		buckets:      buckets,
+		checks:       checks,
+		alerts:       alerts,
+	}
+}
+
+// WritePoints writes the points and records the statuses and escalations among them.
+func (w *PointsWriter) WritePoints(ctx context.Context, orgID influxdb.ID, bucketID influxdb.ID, points []models.Point) error {
+	if err := w.PointsWriter.WritePoints(ctx, orgID, bucketID, points); err != nil {
+		return err
+	}
+
+	v := &writeValidator{w: w, orgID: orgID, bucketID: bucketID}
+	for _, p := range points {
+		if st, ok := parseStatus(orgID, p); ok {
+			if !v.valid(ctx, st.CheckID) {
+				continue
+			}
+			if _, err := w.alerts.RecordStatus(ctx, st); err != nil {
+				w.log.Error("Failed to record check status",
+					zap.String("check_id", st.CheckID.String()),
+					zap.Error(err),
+				)
+			}
+			continue
+		}
+		if esc, ok := parseEscalation(orgID, p); ok {
+			if !v.valid(ctx, esc.CheckID) {
+				continue
+			}
+			if _, err := w.alerts.RecordEscalation(ctx, esc); err != nil {
+				w.log.Error("Failed to record notification rule escalation",
+					zap.String("check_id", esc.CheckID.String()),
+					zap.String("rule_id", esc.RuleID.String()),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// writeValidator validates that the statuses and escalations of a write
+// were written to the _monitoring bucket of the organization and are about
+// its checks. The bucket and every check are looked up once per write.
+type writeValidator struct {
+	w        *PointsWriter
+	orgID    influxdb.ID
+	bucketID influxdb.ID
+
+	bucketChecked bool
+	monitoring    bool
+	checks        map[influxdb.ID]bool
+}
+
+func (v *writeValidator) valid(ctx context.Context, checkID influxdb.ID) bool {
+	if !v.bucketChecked {
+		v.bucketChecked = true
+		b, err := v.w.buckets.FindBucketByID(ctx, v.bucketID)
+		if err != nil {
+			v.w.log.Error("Failed to find bucket of check statuses", zap.String("bucket_id", v.bucketID.String()), zap.Error(err))
+		}
+		v.monitoring = err == nil && b.OrgID == v.orgID && b.Name == influxdb.MonitoringSystemBucketName
+	}
+	if !v.monitoring {
+		return false
+	}
+
+	if valid, ok := v.checks[checkID]; ok {
+		return valid
+	}
+	if v.checks == nil {
+		v.checks = make(map[influxdb.ID]bool)
+	}
+	c, err := v.w.checks.FindCheckByID(ctx, checkID)
+	if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+		v.w.log.Error("Failed to find check of status", zap.String("check_id", checkID.String()), zap.Error(err))
+	}
+	v.checks[checkID] = err == nil && c.GetOrgID() == v.orgID
+	return v.checks[checkID]
+}
+
+func parseStatus(orgID influxdb.ID, p models.Point) (Status, bool) {
+	if string(p.Name()) != "statuses" {
+		return Status{}, false
+	}
+
+	st := Status{
+		OrgID: orgID,
+		Time:  p.Time().UTC(),
+	}
+	for _, t := range p.Tags() {
+		k, v := string(t.Key), string(t.Value)
+		switch k {
+		case "_check_id":
+			id, err := influxdb.IDFromString(v)
+			if err != nil {
+				return Status{}, false
+			}
+			st.CheckID = *id
+		case "_check_name":
+			st.CheckName = v
+		case "_level":
+			st.Level = v
+		default:
+			if !strings.HasPrefix(k, "_") {
+				st.Tags = append(st.Tags, influxdb.Tag{Key: k, Value: v})
+			}
+		}
+	}
+	if !st.CheckID.Valid() || st.Level == "" {
+		return Status{}, false
+	}
+
+	if fields, err := p.Fields(); err == nil {
+		if msg, ok := fields["_message"].(string); ok {
+			st.Message = msg
+		}
+	}
+	return st, true
+}
+
+func parseEscalation(orgID influxdb.ID, p models.Point) (Escalation, bool) {
+	if string(p.Name()) != "notifications" {
+		return Escalation{}, false
+	}
+
+	esc := Escalation{
+		OrgID: orgID,
+		Time:  p.Time().UTC(),
+	}
+	var sent bool
+	for _, t := range p.Tags() {
+		k, v := string(t.Key), string(t.Value)
+		switch k {
+		case "_check_id", "_notification_rule_id":
+			id, err := influxdb.IDFromString(v)
+			if err != nil {
+				return Escalation{}, false
+			}
+			if k == "_check_id" {
+				esc.CheckID = *id
+			} else {
+				esc.RuleID = *id
+			}
+		case "_escalation_step":
+			step, err := strconv.Atoi(v)
+			if err != nil {
+				return Escalation{}, false
+			}
+			esc.Step = step
+		case "_sent":
+			sent = v == "true"
+		default:
+			if !strings.HasPrefix(k, "_") {
+				esc.Tags = append(esc.Tags, influxdb.Tag{Key: k, Value: v})
+			}
+		}
+	}
+	if !sent || !esc.CheckID.Valid() || !esc.RuleID.Valid() || esc.Step <= 0 {
+		return Escalation{}, false
+	}
+	return esc, true
+}
@@ -0,0 +1,98 @@
+package alert_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/notification/alert"
+	"github.com/influxdata/influxdb/v2/notification/check"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type recorder struct {
+	statuses    []alert.Status
+	escalations []alert.Escalation
+}
+
+func (r *recorder) RecordStatus(ctx context.Context, st alert.Status) (*influxdb.Alert, error) {
+	r.statuses = append(r.statuses, st)
+	return nil, nil
+}
+
+func (r *recorder) RecordEscalation(ctx context.Context, esc alert.Escalation) (*influxdb.Alert, error) {
+	r.escalations = append(r.escalations, esc)
+	return nil, nil
+}
+
+func TestPointsWriter(t *testing.T) {
+	points, err := models.ParsePointsString(`statuses,_check_id=000000000000000a,_check_name=cpu,_level=crit,_source_measurement=cpu,_type=threshold,host=a _message="cpu is crit",usage_user=93.5 1590969600000000000
+cpu,host=a usage_user=93.5 1590969600000000000
+statuses,_level=crit,host=a _message="not a check" 1590969600000000000
+notifications,_check_id=000000000000000a,_escalation_step=2,_level=crit,_notification_rule_id=000000000000000b,_sent=true,host=a _message="cpu is crit" 1590969660000000000
+notifications,_check_id=000000000000000a,_escalation_step=3,_level=crit,_notification_rule_id=000000000000000b,_sent=false,host=a _message="cpu is crit" 1590969660000000000
+notifications,_check_id=000000000000000a,_level=crit,_notification_rule_id=000000000000000b,_sent=true,host=a _message="cpu is crit" 1590969660000000000`)
+	require.NoError(t, err)
+
+	var written int
+	underlying := &mock.PointsWriter{}
+	underlying.WritePointsFn = func(ctx context.Context, orgID influxdb.ID, bucketID influxdb.ID, points []models.Point) error {
+		written += len(points)
+		return nil
+	}
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		if id == 2 {
+			return &influxdb.Bucket{ID: id, OrgID: 1, Name: influxdb.MonitoringSystemBucketName}, nil
+		}
+		return &influxdb.Bucket{ID: id, OrgID: 1, Name: "telegraf"}, nil
+	}
+	checks := alert.CheckFinderFunc(func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+		return &check.Deadman{Base: check.Base{ID: id, OrgID: 1}}, nil
+	})
+	rec := &recorder{}
+	w := alert.NewPointsWriter(zaptest.NewLogger(t), underlying, buckets, checks, rec)
+
+	// statuses written to other buckets do not track alerts
+	require.NoError(t, w.WritePoints(context.Background(), 1, 3, points))
+	assert.Empty(t, rec.statuses)
+	assert.Empty(t, rec.escalations)
+
+	// nor do statuses of the checks of other organizations
+	otherOrg := alert.NewPointsWriter(zaptest.NewLogger(t), underlying, buckets, alert.CheckFinderFunc(func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+		return &check.Deadman{Base: check.Base{ID: id, OrgID: 4}}, nil
+	}), rec)
+	require.NoError(t, otherOrg.WritePoints(context.Background(), 1, 2, points))
+	assert.Empty(t, rec.statuses)
+	assert.Empty(t, rec.escalations)
+
+	written = 0
+	require.NoError(t, w.WritePoints(context.Background(), 1, 2, points))
+	assert.Equal(t, 6, written)
+	assert.Equal(t, []alert.Status{
+		{
+			OrgID:     1,
+			CheckID:   10,
+			CheckName: "cpu",
+			Level:     "crit",
+			Tags:      []influxdb.Tag{{Key: "host", Value: "a"}},
+			Message:   "cpu is crit",
+			Time:      time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}, rec.statuses)
+	assert.Equal(t, []alert.Escalation{
+		{
+			OrgID:   1,
+			CheckID: 10,
+			RuleID:  11,
+			Tags:    []influxdb.Tag{{Key: "host", Value: "a"}},
+			Step:    2,
+			Time:    time.Date(2020, 6, 1, 0, 1, 0, 0, time.UTC),
+		},
+	}, rec.escalations)
+}
@@ -0,0 +1,449 @@
+package alert
+
+// The alert `Service` stores the alerts of every organization in a kv bucket
+// keyed by the encoded alert ID, and indexes the unresolved alert of every
+// series by its series key. Alerts are opened, updated and resolved from the
+// statuses written by check tasks, see PointsWriter. Resolved alerts are
+// deleted once past their retention, see RunRetention.
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/snowflake"
+	"go.uber.org/zap"
+)
+
+var (
+	alertBucket         = []byte("alertsv1")
+	alertBySeriesBucket = []byte("alertsbyseriesv1")
+)
+
+// levelOK is the level of a status that resolves the alert of its series.
+const levelOK = "ok"
+
+// Status is a status of a check, as written to the monitoring bucket.
+type Status struct {
+	OrgID     influxdb.ID
+	CheckID   influxdb.ID
+	CheckName string
+	Level     string
+	// Tags are the tags of the series, without the columns of the check.
+	Tags    []influxdb.Tag
+	Message string
+	Time    time.Time
+}
+
+// Escalation is a notification sent by an escalation step of a notification
+// rule, as written to the monitoring bucket.
+type Escalation struct {
+	OrgID   influxdb.ID
+	CheckID influxdb.ID
+	RuleID  influxdb.ID
+	// Tags are the tags of the series, without the columns of the check.
+	Tags []influxdb.Tag
+	Step int
+	Time time.Time
+}
+
+var _ influxdb.AlertService = (*Service)(nil)
+
+// DefaultResolvedRetention is how long resolved alerts are kept by default.
+const DefaultResolvedRetention = 30 * 24 * time.Hour
+
+// retentionInterval is how often the resolved alerts past their retention are deleted.
+const retentionInterval = time.Hour
+
+// Service is the kv backed implementation of influxdb.AlertService.
+type Service struct {
+	store       kv.Store
+	idGenerator influxdb.IDGenerator
+	now         func() time.Time
+	retention   time.Duration
+}
+
+// Option configures the alert service.
+type Option func(*Service)
+
+// WithResolvedRetention sets how long resolved alerts are kept, zero keeps
+// them forever.
+func WithResolvedRetention(d time.Duration) Option {
+	return func(s *Service) {
+		s.retention = d
+	}
+}
+
+// NewService returns an alert service backed by the provided kv store.
+func NewService(st kv.Store, opts ...Option) *Service {
+	s := &Service{
+		store:       st,
+		idGenerator: snowflake.NewIDGenerator(),
+		now:         time.Now,
+		retention:   DefaultResolvedRetention,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// FindAlertByID returns a single alert by ID.
+func (s *Service) FindAlertByID(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error) {
+	key, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidAlertID(err)
+	}
+
+	var a *influxdb.Alert
+	err = s.store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(alertBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		a, err = getAlert(b, key)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// FindAlerts returns the alerts matching the filter, most recent first. The
+// offset and limit of the options page the alerts, a limit of zero returns
+// all of them.
+func (s *Service) FindAlerts(ctx context.Context, filter influxdb.AlertFilter, opt ...influxdb.FindOptions) ([]*influxdb.Alert, error) {
+	var o influxdb.FindOptions
+	if len(opt) > 0 {
+		o = opt[0]
+	}
+
+	as := []*influxdb.Alert{}
+	matched := 0
+	err := s.store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(alertBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		cur, err := b.ForwardCursor(nil, kv.WithCursorDirection(kv.CursorDescending))
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		defer cur.Close()
+
+		for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+			a := &influxdb.Alert{}
+			if err := json.Unmarshal(v, a); err != nil {
+				return ErrInternalService(err)
+			}
+			if filter.OrgID != nil && a.OrgID != *filter.OrgID {
+				continue
+			}
+			if filter.CheckID != nil && a.CheckID != *filter.CheckID {
+				continue
+			}
+			if filter.State != nil && a.State != *filter.State {
+				continue
+			}
+			if matched++; matched <= o.Offset {
+				continue
+			}
+			as = append(as, a)
+			if o.Limit > 0 && len(as) >= o.Limit {
+				break
+			}
+		}
+		return cur.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return as, nil
+}
+
+// AcknowledgeAlert acknowledges an open alert, acknowledging it again changes its assignee.
+func (s *Service) AcknowledgeAlert(ctx context.Context, id influxdb.ID, ack influxdb.AlertAcknowledgement) (*influxdb.Alert, error) {
+	return s.updateAlert(ctx, id, func(tx kv.Tx, a *influxdb.Alert) error {
+		if a.State == influxdb.AlertResolved {
+			return ErrAlertResolved
+		}
+		if a.State == influxdb.AlertOpen {
+			now := s.now().UTC()
+			a.State = influxdb.AlertAcknowledged
+			a.AcknowledgedAt = &now
+			a.AcknowledgedBy = ack.UserID
+		}
+		a.AssigneeID = ack.AssigneeID
+		if !a.AssigneeID.Valid() {
+			a.AssigneeID = ack.UserID
+		}
+		return nil
+	})
+}
+
+// ResolveAlert resolves an alert before its series returns to ok.
+func (s *Service) ResolveAlert(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error) {
+	return s.updateAlert(ctx, id, func(tx kv.Tx, a *influxdb.Alert) error {
+		if a.State == influxdb.AlertResolved {
+			return nil
+		}
+		return resolve(tx, a, s.now().UTC())
+	})
+}
+
+func (s *Service) updateAlert(ctx context.Context, id influxdb.ID, fn func(kv.Tx, *influxdb.Alert) error) (*influxdb.Alert, error) {
+	key, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidAlertID(err)
+	}
+
+	var a *influxdb.Alert
+	err = s.store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(alertBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		if a, err = getAlert(b, key); err != nil {
+			return err
+		}
+		if err := fn(tx, a); err != nil {
+			return err
+		}
+		return putAlert(tx, a)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// RecordStatus opens, updates or resolves the alert of the series of the
+// status. It returns the alert of the series, or nil when an ok status has
+// no unresolved alert.
+func (s *Service) RecordStatus(ctx context.Context, st Status) (*influxdb.Alert, error) {
+	seriesKey := []byte(influxdb.AlertSeriesKey(st.OrgID, st.CheckID, st.Tags))
+
+	var a *influxdb.Alert
+	err := s.store.Update(ctx, func(tx kv.Tx) error {
+		idx, err := tx.Bucket(alertBySeriesBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		b, err := tx.Bucket(alertBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+
+		key, err := idx.Get(seriesKey)
+		if err != nil && !kv.IsNotFound(err) {
+			return ErrInternalService(err)
+		}
+		if key != nil {
+			if a, err = getAlert(b, key); err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case st.Level == levelOK && a == nil:
+			return nil
+		case st.Level == levelOK:
+			a.Level = st.Level
+			return resolve(tx, a, st.Time)
+		case a == nil:
+			a = &influxdb.Alert{
+				ID:        s.idGenerator.ID(),
+				OrgID:     st.OrgID,
+				CheckID:   st.CheckID,
+				Tags:      st.Tags,
+				State:     influxdb.AlertOpen,
+				StartedAt: st.Time,
+			}
+			key, err := a.ID.Encode()
+			if err != nil {
+				return ErrInvalidAlertID(err)
+			}
+			if err := idx.Put(seriesKey, key); err != nil {
+				return ErrInternalService(err)
+			}
+		}
+
+		a.CheckName = st.CheckName
+		a.Level = st.Level
+		a.Message = st.Message
+		a.LastSeenAt = st.Time
+		a.Count++
+		return putAlert(tx, a)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// RecordEscalation records the escalation step on the unresolved alert of the
+// series of the escalation. It returns the alert of the series, or nil when the
+// series has no unresolved alert.
+func (s *Service) RecordEscalation(ctx context.Context, esc Escalation) (*influxdb.Alert, error) {
+	seriesKey := []byte(influxdb.AlertSeriesKey(esc.OrgID, esc.CheckID, esc.Tags))
+
+	var a *influxdb.Alert
+	err := s.store.Update(ctx, func(tx kv.Tx) error {
+		idx, err := tx.Bucket(alertBySeriesBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		b, err := tx.Bucket(alertBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+
+		key, err := idx.Get(seriesKey)
+		if kv.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		if a, err = getAlert(b, key); err != nil {
+			return err
+		}
+
+		a.Escalate(esc.RuleID, esc.Step, esc.Time)
+		return putAlert(tx, a)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// DeleteResolvedAlerts deletes the alerts resolved before the time and
+// returns how many were deleted.
+func (s *Service) DeleteResolvedAlerts(ctx context.Context, before time.Time) (int, error) {
+	var n int
+	err := s.store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(alertBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		keys, err := resolvedBefore(b, before)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return ErrInternalService(err)
+			}
+		}
+		n = len(keys)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// resolvedBefore returns the keys of the alerts resolved before the time.
+func resolvedBefore(b kv.Bucket, before time.Time) ([][]byte, error) {
+	cur, err := b.ForwardCursor(nil)
+	if err != nil {
+		return nil, ErrInternalService(err)
+	}
+	defer cur.Close()
+
+	var keys [][]byte
+	for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+		a := &influxdb.Alert{}
+		if err := json.Unmarshal(v, a); err != nil {
+			return nil, ErrInternalService(err)
+		}
+		if a.State == influxdb.AlertResolved && a.ResolvedAt != nil && a.ResolvedAt.Before(before) {
+			keys = append(keys, k)
+		}
+	}
+	if err := cur.Err(); err != nil {
+		return nil, ErrInternalService(err)
+	}
+	return keys, nil
+}
+
+// RunRetention deletes the resolved alerts past their retention every hour
+// until the context is done. It returns immediately when resolved alerts are
+// kept forever.
+func (s *Service) RunRetention(ctx context.Context, log *zap.Logger) {
+	if s.retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+	for {
+		n, err := s.DeleteResolvedAlerts(ctx, s.now().Add(-s.retention))
+		if err != nil {
+			log.Error("Failed to delete resolved alerts", zap.Error(err))
+		} else if n > 0 {
+			log.Debug("Deleted resolved alerts", zap.Int("count", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// resolve resolves the alert and removes it from the series index.
+func resolve(tx kv.Tx, a *influxdb.Alert, at time.Time) error {
+	idx, err := tx.Bucket(alertBySeriesBucket)
+	if err != nil {
+		return ErrInternalService(err)
+	}
+	if err := idx.Delete([]byte(a.SeriesKey())); err != nil {
+		return ErrInternalService(err)
+	}
+
+	a.State = influxdb.AlertResolved
+	a.ResolvedAt = &at
+	return putAlert(tx, a)
+}
+
+func getAlert(b kv.Bucket, key []byte) (*influxdb.Alert, error) {
+	v, err := b.Get(key)
+	if kv.IsNotFound(err) {
+		return nil, ErrAlertNotFound
+	}
+	if err != nil {
+		return nil, ErrInternalService(err)
+	}
+
+	a := &influxdb.Alert{}
+	if err := json.Unmarshal(v, a); err != nil {
+		return nil, ErrInternalService(err)
+	}
+	return a, nil
+}
+
+func putAlert(tx kv.Tx, a *influxdb.Alert) error {
+	key, err := a.ID.Encode()
+	if err != nil {
+		return ErrInvalidAlertID(err)
+	}
+	b, err := tx.Bucket(alertBucket)
+	if err != nil {
+		return ErrInternalService(err)
+	}
+	v, err := json.Marshal(a)
+	if err != nil {
+		return ErrInternalService(err)
+	}
+	if err := b.Put(key, v); err != nil {
+		return ErrInternalService(err)
+	}
+	return nil
+}
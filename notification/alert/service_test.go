@@ -0,0 +1,219 @@
+package alert_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kv/migration/all"
+	"github.com/influxdata/influxdb/v2/notification/alert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestService(t *testing.T) *alert.Service {
+	t.Helper()
+
+	store := inmem.NewKVStore()
+	if err := all.Up(context.Background(), zaptest.NewLogger(t), store); err != nil {
+		t.Fatal(err)
+	}
+	return alert.NewService(store)
+}
+
+type ruleSyncer struct {
+	orgs []influxdb.ID
+}
+
+func (s *ruleSyncer) SyncAlertRuleTasks(ctx context.Context, a *influxdb.Alert) error {
+	s.orgs = append(s.orgs, a.OrgID)
+	return nil
+}
+
+func status(level string, host string, at time.Time) alert.Status {
+	return alert.Status{
+		OrgID:     1,
+		CheckID:   10,
+		CheckName: "cpu",
+		Level:     level,
+		Tags:      []influxdb.Tag{{Key: "host", Value: host}},
+		Message:   "cpu is " + level,
+		Time:      at,
+	}
+}
+
+func TestService_RecordStatus(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// an ok status without an alert is ignored.
+	a, err := svc.RecordStatus(ctx, status("ok", "a", now))
+	require.NoError(t, err)
+	assert.Nil(t, a)
+
+	first, err := svc.RecordStatus(ctx, status("warn", "a", now))
+	require.NoError(t, err)
+	assert.Equal(t, influxdb.AlertOpen, first.State)
+
+	// repeated statuses of the series update its alert.
+	a, err = svc.RecordStatus(ctx, status("crit", "a", now.Add(time.Minute)))
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, a.ID)
+	assert.Equal(t, "crit", a.Level)
+	assert.Equal(t, "cpu is crit", a.Message)
+	assert.Equal(t, 2, a.Count)
+	assert.Equal(t, now, a.StartedAt)
+	assert.Equal(t, now.Add(time.Minute), a.LastSeenAt)
+
+	other, err := svc.RecordStatus(ctx, status("crit", "b", now))
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, other.ID)
+
+	a, err = svc.RecordStatus(ctx, status("ok", "a", now.Add(2*time.Minute)))
+	require.NoError(t, err)
+	assert.Equal(t, influxdb.AlertResolved, a.State)
+	assert.Equal(t, now.Add(2*time.Minute), *a.ResolvedAt)
+
+	// the series opens a new alert once resolved.
+	a, err = svc.RecordStatus(ctx, status("crit", "a", now.Add(3*time.Minute)))
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, a.ID)
+	assert.Equal(t, 1, a.Count)
+
+	as, err := svc.FindAlerts(ctx, influxdb.AlertFilter{})
+	require.NoError(t, err)
+	require.Len(t, as, 3)
+	assert.Equal(t, a.ID, as[0].ID)
+
+	resolved := influxdb.AlertResolved
+	as, err = svc.FindAlerts(ctx, influxdb.AlertFilter{State: &resolved})
+	require.NoError(t, err)
+	require.Len(t, as, 1)
+	assert.Equal(t, first.ID, as[0].ID)
+
+	as, err = svc.FindAlerts(ctx, influxdb.AlertFilter{}, influxdb.FindOptions{Offset: 1, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, as, 1)
+	assert.Equal(t, other.ID, as[0].ID)
+}
+
+func TestService_DeleteResolvedAlerts(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	old, err := svc.RecordStatus(ctx, status("crit", "a", now))
+	require.NoError(t, err)
+	_, err = svc.RecordStatus(ctx, status("ok", "a", now.Add(time.Minute)))
+	require.NoError(t, err)
+	recent, err := svc.RecordStatus(ctx, status("crit", "b", now))
+	require.NoError(t, err)
+	_, err = svc.RecordStatus(ctx, status("ok", "b", now.Add(time.Hour)))
+	require.NoError(t, err)
+	open, err := svc.RecordStatus(ctx, status("crit", "c", now))
+	require.NoError(t, err)
+
+	n, err := svc.DeleteResolvedAlerts(ctx, now.Add(30*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, err = svc.FindAlertByID(ctx, old.ID)
+	assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+	as, err := svc.FindAlerts(ctx, influxdb.AlertFilter{})
+	require.NoError(t, err)
+	require.Len(t, as, 2)
+	assert.Equal(t, open.ID, as[0].ID)
+	assert.Equal(t, recent.ID, as[1].ID)
+}
+
+func TestSyncingService_AcknowledgeAlert(t *testing.T) {
+	ctx := context.Background()
+	syncer := &ruleSyncer{}
+	svc := alert.NewSyncingService(newTestService(t), syncer)
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	open, err := svc.RecordStatus(ctx, status("crit", "a", now))
+	require.NoError(t, err)
+	assert.Equal(t, []influxdb.ID{1}, syncer.orgs)
+
+	// repeated statuses do not regenerate the tasks.
+	_, err = svc.RecordStatus(ctx, status("crit", "a", now.Add(time.Second)))
+	require.NoError(t, err)
+	assert.Equal(t, []influxdb.ID{1}, syncer.orgs)
+	syncer.orgs = nil
+
+	a, err := svc.AcknowledgeAlert(ctx, open.ID, influxdb.AlertAcknowledgement{UserID: 5})
+	require.NoError(t, err)
+	assert.Equal(t, influxdb.AlertAcknowledged, a.State)
+	assert.Equal(t, influxdb.ID(5), a.AcknowledgedBy)
+	assert.Equal(t, influxdb.ID(5), a.AssigneeID)
+	assert.NotNil(t, a.AcknowledgedAt)
+	assert.Equal(t, []influxdb.ID{1}, syncer.orgs)
+
+	// acknowledging again reassigns the alert.
+	a, err = svc.AcknowledgeAlert(ctx, open.ID, influxdb.AlertAcknowledgement{UserID: 6, AssigneeID: 7})
+	require.NoError(t, err)
+	assert.Equal(t, influxdb.ID(5), a.AcknowledgedBy)
+	assert.Equal(t, influxdb.ID(7), a.AssigneeID)
+
+	// resolving an acknowledged alert lifts its suppression.
+	a, err = svc.RecordStatus(ctx, status("ok", "a", now.Add(time.Minute)))
+	require.NoError(t, err)
+	assert.Equal(t, influxdb.AlertResolved, a.State)
+	assert.Equal(t, []influxdb.ID{1, 1, 1}, syncer.orgs)
+
+	_, err = svc.AcknowledgeAlert(ctx, open.ID, influxdb.AlertAcknowledgement{UserID: 5})
+	assert.Equal(t, influxdb.EConflict, influxdb.ErrorCode(err))
+
+	// resolving an alert by hand.
+	b, err := svc.RecordStatus(ctx, status("crit", "b", now))
+	require.NoError(t, err)
+	syncer.orgs = nil
+	b, err = svc.ResolveAlert(ctx, b.ID)
+	require.NoError(t, err)
+	assert.Equal(t, influxdb.AlertResolved, b.State)
+	assert.Empty(t, syncer.orgs)
+
+	_, err = svc.ResolveAlert(ctx, 100)
+	assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+}
+
+func TestSyncingService_RecordEscalation(t *testing.T) {
+	ctx := context.Background()
+	syncer := &ruleSyncer{}
+	svc := alert.NewSyncingService(newTestService(t), syncer)
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	open, err := svc.RecordStatus(ctx, status("crit", "a", now))
+	require.NoError(t, err)
+	syncer.orgs = nil
+
+	esc := alert.Escalation{
+		OrgID:   1,
+		CheckID: 10,
+		RuleID:  20,
+		Tags:    []influxdb.Tag{{Key: "host", Value: "a"}},
+		Step:    1,
+		Time:    now.Add(15 * time.Minute),
+	}
+	a, err := svc.RecordEscalation(ctx, esc)
+	require.NoError(t, err)
+	assert.Equal(t, open.ID, a.ID)
+	assert.Equal(t, 1, a.EscalationStep(20))
+	assert.Equal(t, []influxdb.ID{1}, syncer.orgs)
+
+	a, err = svc.FindAlertByID(ctx, open.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []influxdb.AlertEscalation{{RuleID: 20, Step: 1, EscalatedAt: now.Add(15 * time.Minute)}}, a.Escalations)
+
+	// escalations of series without an unresolved alert are ignored.
+	esc.Tags = []influxdb.Tag{{Key: "host", Value: "b"}}
+	a, err = svc.RecordEscalation(ctx, esc)
+	require.NoError(t, err)
+	assert.Nil(t, a)
+	assert.Equal(t, []influxdb.ID{1}, syncer.orgs)
+}
@@ -0,0 +1,112 @@
+package alert
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+// RuleSyncer regenerates the notification rule tasks that compile an alert.
+type RuleSyncer interface {
+	// SyncAlertRuleTasks regenerates the tasks of the notification rules of
+	// the organization of the alert that compile it into their flux.
+	SyncAlertRuleTasks(ctx context.Context, a *influxdb.Alert) error
+}
+
+// RuleSyncerFunc adapts a function to a RuleSyncer.
+type RuleSyncerFunc func(ctx context.Context, a *influxdb.Alert) error
+
+// SyncAlertRuleTasks calls f.
+func (f RuleSyncerFunc) SyncAlertRuleTasks(ctx context.Context, a *influxdb.Alert) error {
+	return f(ctx, a)
+}
+
+// Store is an alert service that records the statuses of checks and the
+// escalations of notification rules.
+type Store interface {
+	influxdb.AlertService
+	Recorder
+}
+
+var _ Store = (*SyncingService)(nil)
+
+// SyncingService regenerates the notification rule tasks that compile an alert
+// whenever it opens, escalates or is acknowledged, or when it is resolved once
+// acknowledged. The acknowledged alerts are compiled into the Flux of the
+// tasks of rules that suppress them and the open alerts into the Flux of the
+// tasks of rules that escalate them. Repeated statuses of an open alert do not
+// regenerate any task.
+type SyncingService struct {
+	Store
+	rules RuleSyncer
+}
+
+// NewSyncingService wraps an alert service so that acknowledgements are applied to the notification rule tasks.
+func NewSyncingService(s Store, rules RuleSyncer) *SyncingService {
+	return &SyncingService{
+		Store: s,
+		rules: rules,
+	}
+}
+
+// AcknowledgeAlert acknowledges the alert and regenerates the rule tasks that compile it.
+func (s *SyncingService) AcknowledgeAlert(ctx context.Context, id influxdb.ID, ack influxdb.AlertAcknowledgement) (*influxdb.Alert, error) {
+	a, err := s.Store.AcknowledgeAlert(ctx, id, ack)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.rules.SyncAlertRuleTasks(ctx, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ResolveAlert resolves the alert and regenerates the rule tasks that compile it when it was acknowledged.
+func (s *SyncingService) ResolveAlert(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error) {
+	a, err := s.Store.ResolveAlert(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.syncResolved(ctx, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// RecordStatus records the status and regenerates the rule tasks that compile
+// its alert when it opens the alert or resolves an acknowledged alert.
+func (s *SyncingService) RecordStatus(ctx context.Context, st Status) (*influxdb.Alert, error) {
+	a, err := s.Store.RecordStatus(ctx, st)
+	if err != nil || a == nil {
+		return a, err
+	}
+	if a.State == influxdb.AlertOpen && a.Count == 1 {
+		err = s.rules.SyncAlertRuleTasks(ctx, a)
+	} else {
+		err = s.syncResolved(ctx, a)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// RecordEscalation records the escalation and regenerates the rule tasks that
+// compile its alert, so that the escalation step no longer notifies the alert.
+func (s *SyncingService) RecordEscalation(ctx context.Context, esc Escalation) (*influxdb.Alert, error) {
+	a, err := s.Store.RecordEscalation(ctx, esc)
+	if err != nil || a == nil {
+		return a, err
+	}
+	if err := s.rules.SyncAlertRuleTasks(ctx, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *SyncingService) syncResolved(ctx context.Context, a *influxdb.Alert) error {
+	if a.State != influxdb.AlertResolved || a.AcknowledgedAt == nil {
+		return nil
+	}
+	return s.rules.SyncAlertRuleTasks(ctx, a)
+}
@@ -0,0 +1,175 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+	"github.com/influxdata/influxdb/v2/query"
+)
+
+var _ influxdb.Check = (*Absence)(nil)
+
+// Absence is the absence check. Every series that reported within the
+// lookback of its query but did not since TimeSince is absent.
+type Absence struct {
+	Base
+	// Series are the tag keys that identify a series, a series is absent
+	// when none of its points reported. If empty, the series are the
+	// tables of the query.
+	Series    []string                `json:"series,omitempty"`
+	Lookback  *notification.Duration  `json:"lookback"`
+	TimeSince *notification.Duration  `json:"timeSince"`
+	Level     notification.CheckLevel `json:"level"`
+}
+
+// Type returns the type of the check.
+func (c Absence) Type() string {
+	return "absence"
+}
+
+// Valid returns error if something is invalid.
+func (c Absence) Valid(lang influxdb.FluxLanguageService) error {
+	if err := c.Base.Valid(lang); err != nil {
+		return err
+	}
+	if c.TimeSince == nil || c.TimeSince.TimeDuration() <= 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "absence timeSince must be larger than 0",
+		}
+	}
+	if c.Lookback == nil || c.Lookback.TimeDuration() <= c.TimeSince.TimeDuration() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "absence lookback must be greater than timeSince",
+		}
+	}
+	if c.Level == notification.Unknown {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "absence level must be known",
+		}
+	}
+	keys := make(map[string]bool, len(c.Series))
+	for _, k := range c.Series {
+		if k == "" || keys[k] {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "absence series keys must be non empty and distinct",
+			}
+		}
+		keys[k] = true
+	}
+	return nil
+}
+
+// GenerateFlux returns a flux script for the absence check provided.
+func (c Absence) GenerateFlux(lang influxdb.FluxLanguageService) (string, error) {
+	p, err := c.GenerateFluxAST(lang)
+	if err != nil {
+		return "", err
+	}
+
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST returns a flux AST for the absence check provided. If there
+// are any errors in the flux that the user provided the function will return
+// an error for each error found when the script is parsed.
+func (c Absence) GenerateFluxAST(lang influxdb.FluxLanguageService) (*ast.Package, error) {
+	p, err := query.Parse(lang, c.Query.Text)
+	if p == nil {
+		return nil, err
+	}
+	removeAggregateWindow(p)
+	replaceDurationsWithEvery(p, c.Lookback)
+	removeStopFromRange(p)
+
+	if errs := ast.GetErrors(p); len(errs) != 0 {
+		return nil, multiError(errs)
+	}
+
+	// TODO(desa): this is a hack that we had to do as a result of https://github.com/influxdata/flux/issues/1701
+	// when it is fixed we should use a separate file and not manipulate the existing one.
+	if len(p.Files) != 1 {
+		return nil, fmt.Errorf("expect a single file to be returned from query parsing got %d", len(p.Files))
+	}
+
+	f := p.Files[0]
+	assignPipelineToData(f)
+
+	f.Imports = append(f.Imports, flux.Imports("influxdata/influxdb/monitor", "experimental")...)
+	f.Body = append(f.Body, c.generateFluxASTBody()...)
+
+	return p, nil
+}
+
+func (c Absence) generateFluxASTBody() []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, c.generateTaskOption())
+	statements = append(statements, c.generateFluxASTCheckDefinition("absence"))
+	statements = append(statements, c.generateLevelFn())
+	statements = append(statements, c.generateFluxASTMessageFunction())
+	return append(statements, c.generateFluxASTChecksFunction())
+}
+
+func (c Absence) generateLevelFn() ast.Statement {
+	fn := flux.Function(flux.FunctionParams("r"), flux.Member("r", "dead"))
+
+	lvl := strings.ToLower(c.Level.String())
+
+	return flux.DefineVariable(lvl, fn)
+}
+
+// generateFluxASTChecksFunction regroups the data by the series keys, if
+// any, and reports the series whose latest point is older than TimeSince.
+func (c Absence) generateFluxASTChecksFunction() ast.Statement {
+	calls := []*ast.CallExpression{}
+	if len(c.Series) > 0 {
+		columns := make([]ast.Expression, 0, len(c.Series))
+		for _, k := range c.Series {
+			columns = append(columns, flux.String(k))
+		}
+		calls = append(calls, flux.Call(flux.Identifier("group"), flux.Object(
+			flux.Property("columns", flux.Array(columns...)),
+		)))
+	}
+
+	dur := (*ast.DurationLiteral)(c.TimeSince)
+	now := flux.Call(flux.Identifier("now"), flux.Object())
+	sub := flux.Call(flux.Member("experimental", "subDuration"), flux.Object(flux.Property("from", now), flux.Property("d", dur)))
+	calls = append(calls,
+		flux.Call(flux.Member("monitor", "deadman"), flux.Object(flux.Property("t", sub))),
+		c.generateFluxASTChecksCall(),
+	)
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("data"), calls...))
+}
+
+func (c Absence) generateFluxASTChecksCall() *ast.CallExpression {
+	objectProps := append(([]*ast.Property)(nil), flux.Property("data", flux.Identifier("check")))
+	objectProps = append(objectProps, flux.Property("messageFn", flux.Identifier("messageFn")))
+
+	lvl := strings.ToLower(c.Level.String())
+	objectProps = append(objectProps, flux.Property(lvl, flux.Identifier(lvl)))
+
+	return flux.Call(flux.Member("monitor", "check"), flux.Object(objectProps...))
+}
+
+type absenceAlias Absence
+
+// MarshalJSON implement json.Marshaler interface.
+func (c Absence) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			absenceAlias
+			Type string `json:"type"`
+		}{
+			absenceAlias: absenceAlias(c),
+			Type:         c.Type(),
+		})
+}
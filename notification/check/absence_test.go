@@ -0,0 +1,213 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/check"
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+	influxTesting "github.com/influxdata/influxdb/v2/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAbsence_GenerateFlux(t *testing.T) {
+	type args struct {
+		absence check.Absence
+	}
+	type wants struct {
+		script string
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "absent hosts",
+			args: args{
+				absence: check.Absence{
+					Base: check.Base{
+						ID:   10,
+						Name: "moo",
+						Tags: []influxdb.Tag{
+							{Key: "aaa", Value: "vaaa"},
+						},
+						Every:                 mustDuration("1m"),
+						StatusMessageTemplate: "whoa! ${r._value}",
+						Query: influxdb.DashboardQuery{
+							Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> filter(fn: (r) => r._field == "usage_user") |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+						},
+					},
+					Series:    []string{"host"},
+					Lookback:  mustDuration("1d"),
+					TimeSince: mustDuration("10m"),
+					Level:     notification.Critical,
+				},
+			},
+			wants: wants{
+				script: `package main
+import "influxdata/influxdb/monitor"
+import "experimental"
+
+data = from(bucket: "foo")
+	|> range(start: -1d)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+
+option task = {name: "moo", every: 1m}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "absence",
+	tags: {aaa: "vaaa"},
+}
+crit = (r) =>
+	(r["dead"])
+messageFn = (r) =>
+	("whoa! ${r._value}")
+
+data
+	|> group(columns: ["host"])
+	|> monitor["deadman"](t: experimental["subDuration"](from: now(), d: 10m))
+	|> monitor["check"](data: check, messageFn: messageFn, crit: crit)`,
+			},
+		},
+		{
+			name: "absent series of the query",
+			args: args{
+				absence: check.Absence{
+					Base: check.Base{
+						ID:                    10,
+						Name:                  "moo",
+						Every:                 mustDuration("1m"),
+						StatusMessageTemplate: "whoa! ${r._value}",
+						Query: influxdb.DashboardQuery{
+							Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> filter(fn: (r) => r._field == "usage_user") |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+						},
+					},
+					Lookback:  mustDuration("1d"),
+					TimeSince: mustDuration("10m"),
+					Level:     notification.Warn,
+				},
+			},
+			wants: wants{
+				script: `package main
+import "influxdata/influxdb/monitor"
+import "experimental"
+
+data = from(bucket: "foo")
+	|> range(start: -1d)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+
+option task = {name: "moo", every: 1m}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "absence",
+	tags: {},
+}
+warn = (r) =>
+	(r["dead"])
+messageFn = (r) =>
+	("whoa! ${r._value}")
+
+data
+	|> monitor["deadman"](t: experimental["subDuration"](from: now(), d: 10m))
+	|> monitor["check"](data: check, messageFn: messageFn, warn: warn)`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := tt.args.absence.GenerateFluxAST(fluxlang.DefaultService)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			assert.Equal(t, tt.wants.script, ast.Format(p))
+		})
+	}
+}
+
+func TestAbsence_Valid(t *testing.T) {
+	cases := []struct {
+		name string
+		src  check.Absence
+		err  error
+	}{
+		{
+			name: "valid",
+			src: check.Absence{
+				Base:      goodBase,
+				Series:    []string{"host"},
+				Lookback:  mustDuration("1d"),
+				TimeSince: mustDuration("10m"),
+				Level:     notification.Critical,
+			},
+		},
+		{
+			name: "no time since",
+			src: check.Absence{
+				Base:     goodBase,
+				Lookback: mustDuration("1d"),
+				Level:    notification.Critical,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "absence timeSince must be larger than 0",
+			},
+		},
+		{
+			name: "lookback not greater than time since",
+			src: check.Absence{
+				Base:      goodBase,
+				Lookback:  mustDuration("10m"),
+				TimeSince: mustDuration("10m"),
+				Level:     notification.Critical,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "absence lookback must be greater than timeSince",
+			},
+		},
+		{
+			name: "unknown level",
+			src: check.Absence{
+				Base:      goodBase,
+				Lookback:  mustDuration("1d"),
+				TimeSince: mustDuration("10m"),
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "absence level must be known",
+			},
+		},
+		{
+			name: "duplicate series keys",
+			src: check.Absence{
+				Base:      goodBase,
+				Series:    []string{"host", "host"},
+				Lookback:  mustDuration("1d"),
+				TimeSince: mustDuration("10m"),
+				Level:     notification.Critical,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "absence series keys must be non empty and distinct",
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.src.Valid(fluxlang.DefaultService)
+			influxTesting.ErrorsEqual(t, got, c.err)
+		})
+	}
+}
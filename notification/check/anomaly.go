@@ -0,0 +1,388 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+	"github.com/influxdata/influxdb/v2/query"
+)
+
+var _ influxdb.Check = (*Anomaly)(nil)
+
+// anomaly methods
+const (
+	// AnomalyStddev compares the latest value to the mean of the window,
+	// deviations are standard deviations.
+	AnomalyStddev = "stddev"
+	// AnomalyMAD compares the latest value to the median of the window,
+	// deviations are median absolute deviations scaled to estimate the
+	// standard deviation, which is robust to outliers in the window.
+	AnomalyMAD = "mad"
+)
+
+// AnomalyHourOfWeek restricts the baseline of a value to the values of the
+// window in the same hour of the week.
+const AnomalyHourOfWeek = "hourOfWeek"
+
+// madScale scales the median absolute deviation of normally distributed
+// values to their standard deviation.
+const madScale = 1.4826
+
+// Anomaly is the anomaly check. It compares the latest value of every series
+// of its query to a baseline computed over a rolling window.
+type Anomaly struct {
+	Base
+	// Method is either stddev or mad.
+	Method string `json:"method"`
+	// Window is the rolling window the baseline is computed over, it
+	// replaces the start of the range of the query.
+	Window *notification.Duration `json:"window"`
+	// Seasonality is either empty or hourOfWeek.
+	Seasonality string             `json:"seasonality,omitempty"`
+	Thresholds  []AnomalyThreshold `json:"thresholds"`
+}
+
+// AnomalyThreshold sets the level of the values that deviate from the baseline
+// by more than the number of deviations.
+type AnomalyThreshold struct {
+	Level      notification.CheckLevel `json:"level"`
+	Deviations float64                 `json:"deviations"`
+}
+
+// Type returns the type of the check.
+func (c Anomaly) Type() string {
+	return "anomaly"
+}
+
+// Valid returns error if something is invalid.
+func (c Anomaly) Valid(lang influxdb.FluxLanguageService) error {
+	if err := c.Base.Valid(lang); err != nil {
+		return err
+	}
+	if c.Method != AnomalyStddev && c.Method != AnomalyMAD {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("anomaly method must be %s or %s", AnomalyStddev, AnomalyMAD),
+		}
+	}
+	if c.Window == nil || c.Window.TimeDuration() <= c.Every.TimeDuration() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "anomaly window must be greater than the interval",
+		}
+	}
+	switch c.Seasonality {
+	case "":
+	case AnomalyHourOfWeek:
+		if c.Window.TimeDuration() < 7*24*time.Hour {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly window must be at least a week with an hourOfWeek seasonality",
+			}
+		}
+	default:
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("anomaly seasonality must be empty or %s", AnomalyHourOfWeek),
+		}
+	}
+	if len(c.Thresholds) == 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "anomaly check requires at least one threshold",
+		}
+	}
+	levels := make(map[notification.CheckLevel]bool, len(c.Thresholds))
+	for _, th := range c.Thresholds {
+		if th.Deviations <= 0 {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly threshold deviations must be larger than 0",
+			}
+		}
+		if th.Level == notification.Unknown || levels[th.Level] {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly threshold levels must be known and distinct",
+			}
+		}
+		levels[th.Level] = true
+	}
+	return nil
+}
+
+// GenerateFlux returns a flux script for the anomaly check provided.
+func (c Anomaly) GenerateFlux(lang influxdb.FluxLanguageService) (string, error) {
+	p, err := c.GenerateFluxAST(lang)
+	if err != nil {
+		return "", err
+	}
+
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST returns a flux AST for the anomaly check provided. If there
+// are any errors in the flux that the user provided the function will return
+// an error for each error found when the script is parsed.
+func (c Anomaly) GenerateFluxAST(lang influxdb.FluxLanguageService) (*ast.Package, error) {
+	p, err := query.Parse(lang, c.Query.Text)
+	if p == nil {
+		return nil, err
+	}
+	replaceDurationsWithEvery(p, c.Every)
+	replaceRangeStart(p, c.Window)
+	removeStopFromRange(p)
+	addCreateEmptyFalseToAggregateWindow(p)
+
+	if errs := ast.GetErrors(p); len(errs) != 0 {
+		return nil, multiError(errs)
+	}
+
+	// TODO(desa): this is a hack that we had to do as a result of https://github.com/influxdata/flux/issues/1701
+	// when it is fixed we should use a separate file and not manipulate the existing one.
+	if len(p.Files) != 1 {
+		return nil, fmt.Errorf("expect a single file to be returned from query parsing got %d", len(p.Files))
+	}
+
+	f := p.Files[0]
+	assignPipelineToData(f)
+
+	pkgs := []string{"influxdata/influxdb/monitor", "experimental", "math"}
+	if c.Seasonality == AnomalyHourOfWeek {
+		pkgs = append(pkgs, "date")
+	}
+	f.Imports = append(f.Imports, flux.Imports(pkgs...)...)
+	f.Body = append(f.Body, c.generateFluxASTBody()...)
+
+	return p, nil
+}
+
+// replaceRangeStart replaces the start of the range of the query with the window.
+func replaceRangeStart(pkg *ast.Package, window *notification.Duration) {
+	ast.Visit(pkg, func(n ast.Node) {
+		if call, ok := n.(*ast.CallExpression); ok {
+			if id, ok := call.Callee.(*ast.Identifier); ok && id.Name == "range" {
+				for _, args := range call.Arguments {
+					if obj, ok := args.(*ast.ObjectExpression); ok {
+						for _, prop := range obj.Properties {
+							if prop.Key.Key() == "start" {
+								w := (ast.DurationLiteral)(*window)
+								prop.Value = flux.Negative(&w)
+							}
+						}
+					}
+				}
+			}
+		}
+	})
+}
+
+func (c Anomaly) generateFluxASTBody() []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, c.generateTaskOption())
+	statements = append(statements, c.generateFluxASTCheckDefinition("anomaly"))
+	statements = append(statements, c.generateAnomalyFunctions()...)
+	statements = append(statements, c.generateFluxASTMessageFunction())
+	statements = append(statements, c.generateBaseline()...)
+	statements = append(statements, c.generateFluxASTChecksFunction())
+	return statements
+}
+
+// generateAnomalyFunctions generates the level functions, a value is
+// anomalous when it deviates from its baseline by more than the deviations
+// of the threshold.
+func (c Anomaly) generateAnomalyFunctions() []ast.Statement {
+	statements := make([]ast.Statement, 0, len(c.Thresholds))
+	for _, th := range c.Thresholds {
+		distance := flux.Call(flux.Member("math", "abs"), flux.Object(flux.Property("x", &ast.BinaryExpression{
+			Operator: ast.SubtractionOperator,
+			Left:     flux.Call(flux.Identifier("float"), flux.Object(flux.Property("v", flux.Member("r", "_value")))),
+			Right:    flux.Member("r", "_baseline"),
+		})))
+		fn := flux.Function(flux.FunctionParams("r"), flux.GreaterThan(distance, &ast.BinaryExpression{
+			Operator: ast.MultiplicationOperator,
+			Left:     flux.Float(th.Deviations),
+			Right:    flux.Member("r", "_deviation"),
+		}))
+		statements = append(statements, flux.DefineVariable(strings.ToLower(th.Level.String()), fn))
+	}
+	return statements
+}
+
+// generateBaseline generates the baseline of every series of the data, the
+// center and spread of the values of the window, as a single row per series
+// whose time is the stop of the range so that it joins the latest value.
+func (c Anomaly) generateBaseline() []ast.Statement {
+	var statements []ast.Statement
+
+	statements = append(statements, generateAtStop())
+	callAtStop := flux.Call(flux.Identifier("at_stop"), flux.Object())
+	dropTime := generateDropTime()
+
+	var baseline ast.Expression = flux.Identifier("data")
+	if c.Seasonality == AnomalyHourOfWeek {
+		hourOfWeek := func(t ast.Expression) ast.Expression {
+			return flux.Call(flux.Identifier("hour_of_week"), flux.Object(flux.Property("t", t)))
+		}
+		tArg := flux.Object(flux.Property("t", flux.Identifier("t")))
+		statements = append(statements, flux.DefineVariable("hour_of_week", flux.Function(
+			flux.FunctionParams("t"),
+			flux.Add(
+				&ast.BinaryExpression{
+					Operator: ast.MultiplicationOperator,
+					Left:     flux.Call(flux.Member("date", "weekDay"), tArg),
+					Right:    flux.Integer(24),
+				},
+				flux.Call(flux.Member("date", "hour"), tArg),
+			),
+		)))
+		statements = append(statements, flux.DefineVariable("baseline", flux.Pipe(
+			flux.Identifier("data"),
+			flux.Call(flux.Identifier("filter"), flux.Object(flux.Property("fn", flux.Function(
+				flux.FunctionParams("r"),
+				flux.Equal(hourOfWeek(flux.Member("r", "_time")), hourOfWeek(flux.Call(flux.Identifier("now"), flux.Object()))),
+			)))),
+		)))
+		baseline = flux.Identifier("baseline")
+	}
+
+	switch c.Method {
+	case AnomalyMAD:
+		statements = append(statements, flux.DefineVariable("center", flux.Pipe(
+			baseline,
+			flux.Call(flux.Identifier("median"), flux.Object()),
+			callAtStop,
+		)))
+		// the absolute deviations of the values of the window from its median.
+		deviations := flux.Call(flux.Member("experimental", "join"), flux.Object(
+			flux.Property("left", flux.Pipe(baseline, dropTime, callAtStop)),
+			flux.Property("right", flux.Identifier("center")),
+			flux.Property("fn", flux.Function(
+				flux.FunctionParams("left", "right"),
+				flux.ObjectWith("left", flux.Property("_value", flux.Call(
+					flux.Member("math", "abs"),
+					flux.Object(flux.Property("x", &ast.BinaryExpression{
+						Operator: ast.SubtractionOperator,
+						Left:     flux.Call(flux.Identifier("float"), flux.Object(flux.Property("v", flux.Member("left", "_value")))),
+						Right:    flux.Member("right", "_value"),
+					})),
+				))),
+			)),
+		))
+		statements = append(statements, flux.DefineVariable("deviations", deviations))
+		statements = append(statements, flux.DefineVariable("spread", flux.Pipe(
+			flux.Identifier("deviations"),
+			flux.Call(flux.Identifier("median"), flux.Object()),
+			flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", flux.Function(
+				flux.FunctionParams("r"),
+				flux.ObjectWith("r", flux.Property("_value", &ast.BinaryExpression{
+					Operator: ast.MultiplicationOperator,
+					Left:     flux.Member("r", "_value"),
+					Right:    flux.Float(madScale),
+				})),
+			)))),
+			callAtStop,
+		)))
+	default:
+		statements = append(statements, flux.DefineVariable("center", flux.Pipe(
+			baseline,
+			flux.Call(flux.Identifier("mean"), flux.Object()),
+			callAtStop,
+		)))
+		statements = append(statements, flux.DefineVariable("spread", flux.Pipe(
+			baseline,
+			flux.Call(flux.Identifier("stddev"), flux.Object()),
+			callAtStop,
+		)))
+	}
+
+	statements = append(statements, flux.DefineVariable("stats", flux.Call(flux.Member("experimental", "join"), flux.Object(
+		flux.Property("left", flux.Identifier("center")),
+		flux.Property("right", flux.Identifier("spread")),
+		flux.Property("fn", flux.Function(
+			flux.FunctionParams("left", "right"),
+			flux.ObjectWith("left",
+				flux.Property("_baseline", flux.Member("left", "_value")),
+				flux.Property("_deviation", flux.Member("right", "_value")),
+			),
+		)),
+	))))
+	statements = append(statements, flux.DefineVariable("latest", flux.Pipe(
+		flux.Identifier("data"),
+		flux.Call(flux.Identifier("last"), flux.Object()),
+		dropTime,
+		callAtStop,
+	)))
+	return statements
+}
+
+// generateAtStop defines at_stop, which sets the time of the rows of a table
+// to the stop of its range so that tables of a single row per series join.
+func generateAtStop() ast.Statement {
+	return flux.DefineVariable("at_stop", &ast.FunctionExpression{
+		Params: []*ast.Property{{
+			Key:   &ast.Identifier{Name: "tables"},
+			Value: &ast.PipeLiteral{},
+		}},
+		Body: flux.Pipe(
+			flux.Identifier("tables"),
+			flux.Call(flux.Identifier("duplicate"), flux.Object(
+				flux.Property("column", flux.String("_stop")),
+				flux.Property("as", flux.String("_time")),
+			)),
+		),
+	})
+}
+
+func generateDropTime() *ast.CallExpression {
+	return flux.Call(flux.Identifier("drop"), flux.Object(
+		flux.Property("columns", flux.Array(flux.String("_time"))),
+	))
+}
+
+func (c Anomaly) generateFluxASTChecksFunction() ast.Statement {
+	join := flux.Call(flux.Member("experimental", "join"), flux.Object(
+		flux.Property("left", flux.Identifier("latest")),
+		flux.Property("right", flux.Identifier("stats")),
+		flux.Property("fn", flux.Function(
+			flux.FunctionParams("left", "right"),
+			flux.ObjectWith("left",
+				flux.Property("_baseline", flux.Member("right", "_baseline")),
+				flux.Property("_deviation", flux.Member("right", "_deviation")),
+			),
+		)),
+	))
+	return flux.ExpressionStatement(flux.Pipe(join, c.generateFluxASTChecksCall()))
+}
+
+func (c Anomaly) generateFluxASTChecksCall() *ast.CallExpression {
+	objectProps := append(([]*ast.Property)(nil), flux.Property("data", flux.Identifier("check")))
+	objectProps = append(objectProps, flux.Property("messageFn", flux.Identifier("messageFn")))
+
+	for _, th := range c.Thresholds {
+		lvl := strings.ToLower(th.Level.String())
+		objectProps = append(objectProps, flux.Property(lvl, flux.Identifier(lvl)))
+	}
+
+	return flux.Call(flux.Member("monitor", "check"), flux.Object(objectProps...))
+}
+
+type anomalyAlias Anomaly
+
+// MarshalJSON implement json.Marshaler interface.
+func (c Anomaly) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			anomalyAlias
+			Type string `json:"type"`
+		}{
+			anomalyAlias: anomalyAlias(c),
+			Type:         c.Type(),
+		})
+}
@@ -0,0 +1,318 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/check"
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+	influxTesting "github.com/influxdata/influxdb/v2/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnomaly_GenerateFlux(t *testing.T) {
+	type args struct {
+		anomaly check.Anomaly
+	}
+	type wants struct {
+		script string
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "standard deviations",
+			args: args{
+				anomaly: check.Anomaly{
+					Base: check.Base{
+						ID:   10,
+						Name: "moo",
+						Tags: []influxdb.Tag{
+							{Key: "aaa", Value: "vaaa"},
+						},
+						Every:                 mustDuration("1h"),
+						StatusMessageTemplate: "whoa! ${r._value} deviates from ${r._baseline}",
+						Query: influxdb.DashboardQuery{
+							Text: `from(bucket: "foo") |> range(start: -1h) |> filter(fn: (r) => r._field == "usage_user") |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+						},
+					},
+					Method: check.AnomalyStddev,
+					Window: mustDuration("1d"),
+					Thresholds: []check.AnomalyThreshold{
+						{Level: notification.Warn, Deviations: 2},
+						{Level: notification.Critical, Deviations: 3},
+					},
+				},
+			},
+			wants: wants{
+				script: `package main
+import "influxdata/influxdb/monitor"
+import "experimental"
+import "math"
+
+data = from(bucket: "foo")
+	|> range(start: -1d)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 1h, fn: mean, createEmpty: false)
+
+option task = {name: "moo", every: 1h}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "anomaly",
+	tags: {aaa: "vaaa"},
+}
+warn = (r) =>
+	(math["abs"](x: float(v: r["_value"]) - r["_baseline"]) > 2.0 * r["_deviation"])
+crit = (r) =>
+	(math["abs"](x: float(v: r["_value"]) - r["_baseline"]) > 3.0 * r["_deviation"])
+messageFn = (r) =>
+	("whoa! ${r._value} deviates from ${r._baseline}")
+at_stop = (tables=<-) =>
+	(tables
+		|> duplicate(column: "_stop", as: "_time"))
+center = data
+	|> mean()
+	|> at_stop()
+spread = data
+	|> stddev()
+	|> at_stop()
+stats = experimental["join"](left: center, right: spread, fn: (left, right) =>
+	({left with _baseline: left["_value"], _deviation: right["_value"]}))
+latest = data
+	|> last()
+	|> drop(columns: ["_time"])
+	|> at_stop()
+
+experimental["join"](left: latest, right: stats, fn: (left, right) =>
+	({left with _baseline: right["_baseline"], _deviation: right["_deviation"]}))
+	|> monitor["check"](
+		data: check,
+		messageFn: messageFn,
+		warn: warn,
+		crit: crit,
+	)`,
+			},
+		},
+		{
+			name: "median absolute deviations by hour of week",
+			args: args{
+				anomaly: check.Anomaly{
+					Base: check.Base{
+						ID:                    10,
+						Name:                  "moo",
+						Every:                 mustDuration("1h"),
+						StatusMessageTemplate: "whoa!",
+						Query: influxdb.DashboardQuery{
+							Text: `from(bucket: "foo") |> range(start: -1h) |> filter(fn: (r) => r._field == "usage_user") |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+						},
+					},
+					Method:      check.AnomalyMAD,
+					Window:      mustDuration("4w"),
+					Seasonality: check.AnomalyHourOfWeek,
+					Thresholds: []check.AnomalyThreshold{
+						{Level: notification.Critical, Deviations: 3.5},
+					},
+				},
+			},
+			wants: wants{
+				script: `package main
+import "influxdata/influxdb/monitor"
+import "experimental"
+import "math"
+import "date"
+
+data = from(bucket: "foo")
+	|> range(start: -4w)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 1h, fn: mean, createEmpty: false)
+
+option task = {name: "moo", every: 1h}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "anomaly",
+	tags: {},
+}
+crit = (r) =>
+	(math["abs"](x: float(v: r["_value"]) - r["_baseline"]) > 3.5 * r["_deviation"])
+messageFn = (r) =>
+	("whoa!")
+at_stop = (tables=<-) =>
+	(tables
+		|> duplicate(column: "_stop", as: "_time"))
+hour_of_week = (t) =>
+	(date["weekDay"](t: t) * 24 + date["hour"](t: t))
+baseline = data
+	|> filter(fn: (r) =>
+		(hour_of_week(t: r["_time"]) == hour_of_week(t: now())))
+center = baseline
+	|> median()
+	|> at_stop()
+deviations = experimental["join"](left: baseline
+	|> drop(columns: ["_time"])
+	|> at_stop(), right: center, fn: (left, right) =>
+	({left with _value: math["abs"](x: float(v: left["_value"]) - right["_value"])}))
+spread = deviations
+	|> median()
+	|> map(fn: (r) =>
+		({r with _value: r["_value"] * 1.4826}))
+	|> at_stop()
+stats = experimental["join"](left: center, right: spread, fn: (left, right) =>
+	({left with _baseline: left["_value"], _deviation: right["_value"]}))
+latest = data
+	|> last()
+	|> drop(columns: ["_time"])
+	|> at_stop()
+
+experimental["join"](left: latest, right: stats, fn: (left, right) =>
+	({left with _baseline: right["_baseline"], _deviation: right["_deviation"]}))
+	|> monitor["check"](data: check, messageFn: messageFn, crit: crit)`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := tt.args.anomaly.GenerateFluxAST(fluxlang.DefaultService)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			assert.Equal(t, tt.wants.script, ast.Format(p))
+		})
+	}
+}
+
+func TestAnomaly_Valid(t *testing.T) {
+	thresholds := []check.AnomalyThreshold{
+		{Level: notification.Critical, Deviations: 3},
+	}
+	cases := []struct {
+		name string
+		src  check.Anomaly
+		err  error
+	}{
+		{
+			name: "valid",
+			src: check.Anomaly{
+				Base:       goodBase,
+				Method:     check.AnomalyStddev,
+				Window:     mustDuration("1h"),
+				Thresholds: thresholds,
+			},
+		},
+		{
+			name: "unknown method",
+			src: check.Anomaly{
+				Base:       goodBase,
+				Method:     "zscore",
+				Window:     mustDuration("1h"),
+				Thresholds: thresholds,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly method must be stddev or mad",
+			},
+		},
+		{
+			name: "window not greater than every",
+			src: check.Anomaly{
+				Base:       goodBase,
+				Method:     check.AnomalyMAD,
+				Window:     mustDuration("1m"),
+				Thresholds: thresholds,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly window must be greater than the interval",
+			},
+		},
+		{
+			name: "hour of week seasonality with window less than a week",
+			src: check.Anomaly{
+				Base:        goodBase,
+				Method:      check.AnomalyMAD,
+				Window:      mustDuration("6d"),
+				Seasonality: check.AnomalyHourOfWeek,
+				Thresholds:  thresholds,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly window must be at least a week with an hourOfWeek seasonality",
+			},
+		},
+		{
+			name: "unknown seasonality",
+			src: check.Anomaly{
+				Base:        goodBase,
+				Method:      check.AnomalyMAD,
+				Window:      mustDuration("1w"),
+				Seasonality: "dayOfWeek",
+				Thresholds:  thresholds,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly seasonality must be empty or hourOfWeek",
+			},
+		},
+		{
+			name: "no thresholds",
+			src: check.Anomaly{
+				Base:   goodBase,
+				Method: check.AnomalyStddev,
+				Window: mustDuration("1h"),
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly check requires at least one threshold",
+			},
+		},
+		{
+			name: "no deviations",
+			src: check.Anomaly{
+				Base:   goodBase,
+				Method: check.AnomalyStddev,
+				Window: mustDuration("1h"),
+				Thresholds: []check.AnomalyThreshold{
+					{Level: notification.Critical},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly threshold deviations must be larger than 0",
+			},
+		},
+		{
+			name: "duplicate levels",
+			src: check.Anomaly{
+				Base:   goodBase,
+				Method: check.AnomalyStddev,
+				Window: mustDuration("1h"),
+				Thresholds: []check.AnomalyThreshold{
+					{Level: notification.Critical, Deviations: 3},
+					{Level: notification.Critical, Deviations: 4},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly threshold levels must be known and distinct",
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.src.Valid(fluxlang.DefaultService)
+			influxTesting.ErrorsEqual(t, got, c.err)
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/notification"
 	"github.com/influxdata/influxdb/v2/notification/flux"
+	"github.com/influxdata/influxdb/v2/notification/template"
 )
 
 // Base will embed inside a check.
@@ -24,15 +25,23 @@ type Base struct {
 	// } todo: separate these
 	// NonCustomCheckBase will embed inside non-custom checks.
 	// type NonCustomCheckBase struct {
-	StatusMessageTemplate string                 `json:"statusMessageTemplate"`
-	Cron                  string                 `json:"cron,omitempty"`
-	Every                 *notification.Duration `json:"every,omitempty"`
+	StatusMessageTemplate string `json:"statusMessageTemplate"`
+	// MessageTemplateID references a message template of the organization
+	// that is used instead of the StatusMessageTemplate.
+	MessageTemplateID influxdb.ID            `json:"messageTemplateID,omitempty"`
+	Cron              string                 `json:"cron,omitempty"`
+	Every             *notification.Duration `json:"every,omitempty"`
 	// Offset represents a delay before execution.
 	// It gets marshalled from a string duration, i.e.: "10s" is 10 seconds
 	Offset *notification.Duration `json:"offset,omitempty"`
 
 	Tags []influxdb.Tag `json:"tags"`
 	influxdb.CRUDLog
+
+	// SharedMessageTemplate is the message template referenced by
+	// MessageTemplateID. It is set by the check service and is not stored
+	// with the check.
+	SharedMessageTemplate *influxdb.MessageTemplate `json:"-"`
 }
 
 // Valid returns err if the check is invalid.
@@ -94,8 +103,41 @@ func (b Base) Valid(lang influxdb.FluxLanguageService) error {
 	return nil
 }
 
+// GetMessageTemplateID returns the ID of the message template the check references.
+func (b *Base) GetMessageTemplateID() influxdb.ID {
+	return b.MessageTemplateID
+}
+
+// SetMessageTemplate sets the message template referenced by the check, it is
+// compiled into the flux of the check instead of its StatusMessageTemplate.
+// A nil template falls back to the StatusMessageTemplate.
+func (b *Base) SetMessageTemplate(t *influxdb.MessageTemplate) error {
+	if t == nil {
+		b.SharedMessageTemplate = nil
+		return nil
+	}
+	tmpl, err := template.Parse(t.Template)
+	if err != nil {
+		return err
+	}
+	if tmpl.UsesMessage() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("message template %q renders the message of the status, which checks don't have", t.Name),
+		}
+	}
+	b.SharedMessageTemplate = t
+	return nil
+}
+
 func (b Base) generateFluxASTMessageFunction() ast.Statement {
-	fn := flux.Function(flux.FunctionParams("r"), flux.String(b.StatusMessageTemplate))
+	var msg ast.Expression = flux.String(b.StatusMessageTemplate)
+	if b.SharedMessageTemplate != nil {
+		if tmpl, err := template.Parse(b.SharedMessageTemplate.Template); err == nil {
+			msg = tmpl.Expression(b.SharedMessageTemplate.RunbookURL)
+		}
+	}
+	fn := flux.Function(flux.FunctionParams("r"), msg)
 	return flux.DefineVariable("messageFn", fn)
 }
 
@@ -202,9 +244,12 @@ func (b *Base) SetDescription(description string) {
 }
 
 var typeToCheck = map[string](func() influxdb.Check){
-	"deadman":   func() influxdb.Check { return &Deadman{} },
-	"threshold": func() influxdb.Check { return &Threshold{} },
-	"custom":    func() influxdb.Check { return &Custom{} },
+	"deadman":      func() influxdb.Check { return &Deadman{} },
+	"threshold":    func() influxdb.Check { return &Threshold{} },
+	"custom":       func() influxdb.Check { return &Custom{} },
+	"anomaly":      func() influxdb.Check { return &Anomaly{} },
+	"rateOfChange": func() influxdb.Check { return &RateOfChange{} },
+	"absence":      func() influxdb.Check { return &Absence{} },
 }
 
 // UnmarshalJSON will convert
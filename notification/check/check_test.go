@@ -263,6 +263,73 @@ func TestJSON(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "simple rate of change",
+			src: &check.RateOfChange{
+				Base: check.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					Name:    "name1",
+					OwnerID: influxTesting.MustIDBase16(id2),
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("1h"),
+					Query: influxdb.DashboardQuery{
+						BuilderConfig: influxdb.BuilderConfig{
+							Buckets: []string{},
+							Tags: []struct {
+								Key                   string   `json:"key"`
+								Values                []string `json:"values"`
+								AggregateFunctionType string   `json:"aggregateFunctionType"`
+							}{},
+							Functions: []struct {
+								Name string `json:"name"`
+							}{},
+						},
+					},
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Period:  mustDuration("1d"),
+				Percent: true,
+				Thresholds: []check.ThresholdConfig{
+					&check.Greater{ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Critical}, Value: 10},
+				},
+			},
+		},
+		{
+			name: "simple absence",
+			src: &check.Absence{
+				Base: check.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					Name:    "name1",
+					OwnerID: influxTesting.MustIDBase16(id2),
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("1h"),
+					Query: influxdb.DashboardQuery{
+						BuilderConfig: influxdb.BuilderConfig{
+							Buckets: []string{},
+							Tags: []struct {
+								Key                   string   `json:"key"`
+								Values                []string `json:"values"`
+								AggregateFunctionType string   `json:"aggregateFunctionType"`
+							}{},
+							Functions: []struct {
+								Name string `json:"name"`
+							}{},
+						},
+					},
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Series:    []string{"host"},
+				Lookback:  mustDuration("1d"),
+				TimeSince: mustDuration("10m"),
+				Level:     notification.Warn,
+			},
+		},
 	}
 	for _, c := range cases {
 		fn := func(t *testing.T) {
@@ -281,3 +348,30 @@ func TestJSON(t *testing.T) {
 		t.Run(c.name, fn)
 	}
 }
+
+func TestSetMessageTemplate(t *testing.T) {
+	c := &check.Threshold{Base: goodBase}
+
+	err := c.SetMessageTemplate(&influxdb.MessageTemplate{Name: "rule", Template: "{{ check_name }}: {{ message }}"})
+	if influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected an invalid template error, got %v", err)
+	}
+	if c.SharedMessageTemplate != nil {
+		t.Fatalf("expected the template to be unset, got %v", c.SharedMessageTemplate)
+	}
+
+	tmpl := &influxdb.MessageTemplate{Name: "cpu", Template: "{{ check_name }} is {{ level }}"}
+	if err := c.SetMessageTemplate(tmpl); err != nil {
+		t.Fatal(err)
+	}
+	if c.SharedMessageTemplate != tmpl {
+		t.Fatalf("expected the template to be set, got %v", c.SharedMessageTemplate)
+	}
+
+	if err := c.SetMessageTemplate(nil); err != nil {
+		t.Fatal(err)
+	}
+	if c.SharedMessageTemplate != nil {
+		t.Fatalf("expected the template to be unset, got %v", c.SharedMessageTemplate)
+	}
+}
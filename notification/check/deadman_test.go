@@ -142,6 +142,64 @@ info = (r) =>
 messageFn = (r) =>
 	("whoa! {r[\"dead\"]}")
 
+data
+	|> v1["fieldsAsCols"]()
+	|> monitor["deadman"](t: experimental["subDuration"](from: now(), d: 60s))
+	|> monitor["check"](data: check, messageFn: messageFn, info: info)`,
+			},
+		},
+		{
+			name: "with shared message template",
+			args: args{
+				deadman: check.Deadman{
+					Base: check.Base{
+						ID:   10,
+						Name: "moo",
+						Tags: []influxdb.Tag{
+							{Key: "aaa", Value: "vaaa"},
+							{Key: "bbb", Value: "vbbb"},
+						},
+						Every:                 mustDuration("1h"),
+						StatusMessageTemplate: "whoa! {r[\"dead\"]}",
+						MessageTemplateID:     20,
+						SharedMessageTemplate: &influxdb.MessageTemplate{
+							ID:         20,
+							OrgID:      1,
+							Name:       "absent",
+							Template:   "{{ check_name }} stopped reporting on {{ tags.host }}, see {{ runbook }}",
+							RunbookURL: "https://runbooks.example.com/moo",
+						},
+						Query: influxdb.DashboardQuery{
+							Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> yield()`,
+						},
+					},
+					TimeSince: mustDuration("60s"),
+					StaleTime: mustDuration("10m"),
+					Level:     notification.Info,
+				},
+			},
+			wants: wants{
+				script: `package main
+import "influxdata/influxdb/monitor"
+import "experimental"
+import "influxdata/influxdb/v1"
+
+data = from(bucket: "foo")
+	|> range(start: -10m)
+
+option task = {name: "moo", every: 1h}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "deadman",
+	tags: {aaa: "vaaa", bbb: "vbbb"},
+}
+info = (r) =>
+	(r["dead"])
+messageFn = (r) =>
+	("${r["_check_name"]} stopped reporting on ${r["host"]}, see https://runbooks.example.com/moo")
+
 data
 	|> v1["fieldsAsCols"]()
 	|> monitor["deadman"](t: experimental["subDuration"](from: now(), d: 60s))
@@ -0,0 +1,220 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+	"github.com/influxdata/influxdb/v2/query"
+)
+
+var _ influxdb.Check = (*RateOfChange)(nil)
+
+// RateOfChange is the rate of change check. It compares the change of every
+// series of its query over a period, from its earliest to its latest value of
+// the period, to thresholds.
+type RateOfChange struct {
+	Base
+	// Period is the period the change is computed over, it replaces the
+	// start of the range of the query.
+	Period *notification.Duration `json:"period"`
+	// If true, the change is a percentage of the earliest value of the period.
+	Percent    bool              `json:"percent"`
+	Thresholds []ThresholdConfig `json:"thresholds"`
+}
+
+// Type returns the type of the check.
+func (c RateOfChange) Type() string {
+	return "rateOfChange"
+}
+
+// Valid returns error if something is invalid.
+func (c RateOfChange) Valid(lang influxdb.FluxLanguageService) error {
+	if err := c.Base.Valid(lang); err != nil {
+		return err
+	}
+	if c.Period == nil || c.Period.TimeDuration() <= c.Every.TimeDuration() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "rate of change period must be greater than the interval",
+		}
+	}
+	if len(c.Thresholds) == 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "rate of change check requires at least one threshold",
+		}
+	}
+	for _, cc := range c.Thresholds {
+		if err := cc.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type rateOfChangeDecode struct {
+	Base
+	Period     *notification.Duration  `json:"period"`
+	Percent    bool                    `json:"percent"`
+	Thresholds []thresholdConfigDecode `json:"thresholds"`
+}
+
+// UnmarshalJSON implement json.Unmarshaler interface.
+func (c *RateOfChange) UnmarshalJSON(b []byte) error {
+	raw := new(rateOfChangeDecode)
+	if err := json.Unmarshal(b, raw); err != nil {
+		return err
+	}
+	thresholds, err := decodeThresholdConfigs(raw.Thresholds)
+	if err != nil {
+		return err
+	}
+	c.Base = raw.Base
+	c.Period = raw.Period
+	c.Percent = raw.Percent
+	c.Thresholds = thresholds
+	return nil
+}
+
+// GenerateFlux returns a flux script for the rate of change check provided.
+func (c RateOfChange) GenerateFlux(lang influxdb.FluxLanguageService) (string, error) {
+	p, err := c.GenerateFluxAST(lang)
+	if err != nil {
+		return "", err
+	}
+
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST returns a flux AST for the rate of change check provided. If
+// there are any errors in the flux that the user provided the function will
+// return an error for each error found when the script is parsed.
+func (c RateOfChange) GenerateFluxAST(lang influxdb.FluxLanguageService) (*ast.Package, error) {
+	p, err := query.Parse(lang, c.Query.Text)
+	if p == nil {
+		return nil, err
+	}
+	replaceDurationsWithEvery(p, c.Every)
+	replaceRangeStart(p, c.Period)
+	removeStopFromRange(p)
+	addCreateEmptyFalseToAggregateWindow(p)
+
+	if errs := ast.GetErrors(p); len(errs) != 0 {
+		return nil, multiError(errs)
+	}
+
+	// TODO(desa): this is a hack that we had to do as a result of https://github.com/influxdata/flux/issues/1701
+	// when it is fixed we should use a separate file and not manipulate the existing one.
+	if len(p.Files) != 1 {
+		return nil, fmt.Errorf("expect a single file to be returned from query parsing got %d", len(p.Files))
+	}
+
+	f := p.Files[0]
+	assignPipelineToData(f)
+
+	pkgs := []string{"influxdata/influxdb/monitor", "experimental"}
+	if c.Percent {
+		pkgs = append(pkgs, "math")
+	}
+	f.Imports = append(f.Imports, flux.Imports(pkgs...)...)
+	f.Body = append(f.Body, c.generateFluxASTBody()...)
+
+	return p, nil
+}
+
+func (c RateOfChange) generateFluxASTBody() []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, c.generateTaskOption())
+	statements = append(statements, c.generateFluxASTCheckDefinition("rateOfChange"))
+	for _, th := range c.Thresholds {
+		statements = append(statements, th.generateFluxASTThresholdFunction("_value"))
+	}
+	statements = append(statements, c.generateFluxASTMessageFunction())
+	statements = append(statements, c.generateEndpoints()...)
+	statements = append(statements, c.generateFluxASTChecksFunction())
+	return statements
+}
+
+// generateEndpoints generates the earliest and the latest value of every
+// series of the data, as a single row per series whose time is the stop of
+// the range so that they join.
+func (c RateOfChange) generateEndpoints() []ast.Statement {
+	callAtStop := flux.Call(flux.Identifier("at_stop"), flux.Object())
+	return []ast.Statement{
+		generateAtStop(),
+		flux.DefineVariable("earliest", flux.Pipe(
+			flux.Identifier("data"),
+			flux.Call(flux.Identifier("first"), flux.Object()),
+			generateDropTime(),
+			callAtStop,
+		)),
+		flux.DefineVariable("latest", flux.Pipe(
+			flux.Identifier("data"),
+			flux.Call(flux.Identifier("last"), flux.Object()),
+			generateDropTime(),
+			callAtStop,
+		)),
+	}
+}
+
+// generateFluxASTChecksFunction joins the latest value of every series to its
+// earliest one, the value the thresholds compare is the change between them.
+func (c RateOfChange) generateFluxASTChecksFunction() ast.Statement {
+	earliest := flux.Call(flux.Identifier("float"), flux.Object(flux.Property("v", flux.Member("right", "_value"))))
+	var change ast.Expression = flux.Subtract(
+		flux.Call(flux.Identifier("float"), flux.Object(flux.Property("v", flux.Member("left", "_value")))),
+		earliest,
+	)
+	if c.Percent {
+		change = &ast.BinaryExpression{
+			Operator: ast.MultiplicationOperator,
+			Left: &ast.BinaryExpression{
+				Operator: ast.DivisionOperator,
+				Left:     change,
+				Right:    flux.Call(flux.Member("math", "abs"), flux.Object(flux.Property("x", earliest))),
+			},
+			Right: flux.Float(100),
+		}
+	}
+	join := flux.Call(flux.Member("experimental", "join"), flux.Object(
+		flux.Property("left", flux.Identifier("latest")),
+		flux.Property("right", flux.Identifier("earliest")),
+		flux.Property("fn", flux.Function(
+			flux.FunctionParams("left", "right"),
+			flux.ObjectWith("left", flux.Property("_value", change)),
+		)),
+	))
+	return flux.ExpressionStatement(flux.Pipe(join, c.generateFluxASTChecksCall()))
+}
+
+func (c RateOfChange) generateFluxASTChecksCall() *ast.CallExpression {
+	objectProps := append(([]*ast.Property)(nil), flux.Property("data", flux.Identifier("check")))
+	objectProps = append(objectProps, flux.Property("messageFn", flux.Identifier("messageFn")))
+
+	// This assumes that the ThresholdConfigs we've been provided do not have duplicates.
+	for _, th := range c.Thresholds {
+		lvl := strings.ToLower(th.GetLevel().String())
+		objectProps = append(objectProps, flux.Property(lvl, flux.Identifier(lvl)))
+	}
+
+	return flux.Call(flux.Member("monitor", "check"), flux.Object(objectProps...))
+}
+
+type rateOfChangeAlias RateOfChange
+
+// MarshalJSON implement json.Marshaler interface.
+func (c RateOfChange) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			rateOfChangeAlias
+			Type string `json:"type"`
+		}{
+			rateOfChangeAlias: rateOfChangeAlias(c),
+			Type:              c.Type(),
+		})
+}
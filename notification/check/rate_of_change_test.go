@@ -0,0 +1,245 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/check"
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+	influxTesting "github.com/influxdata/influxdb/v2/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateOfChange_GenerateFlux(t *testing.T) {
+	type args struct {
+		rateOfChange check.RateOfChange
+	}
+	type wants struct {
+		script string
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "absolute change",
+			args: args{
+				rateOfChange: check.RateOfChange{
+					Base: check.Base{
+						ID:   10,
+						Name: "moo",
+						Tags: []influxdb.Tag{
+							{Key: "aaa", Value: "vaaa"},
+						},
+						Every:                 mustDuration("1m"),
+						StatusMessageTemplate: "whoa! ${r._value}",
+						Query: influxdb.DashboardQuery{
+							Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> filter(fn: (r) => r._field == "usage_user") |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+						},
+					},
+					Period: mustDuration("1h"),
+					Thresholds: []check.ThresholdConfig{
+						check.Greater{
+							ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Critical},
+							Value:               10,
+						},
+					},
+				},
+			},
+			wants: wants{
+				script: `package main
+import "influxdata/influxdb/monitor"
+import "experimental"
+
+data = from(bucket: "foo")
+	|> range(start: -1h)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 1m, fn: mean, createEmpty: false)
+
+option task = {name: "moo", every: 1m}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "rateOfChange",
+	tags: {aaa: "vaaa"},
+}
+crit = (r) =>
+	(r["_value"] > 10.0)
+messageFn = (r) =>
+	("whoa! ${r._value}")
+at_stop = (tables=<-) =>
+	(tables
+		|> duplicate(column: "_stop", as: "_time"))
+earliest = data
+	|> first()
+	|> drop(columns: ["_time"])
+	|> at_stop()
+latest = data
+	|> last()
+	|> drop(columns: ["_time"])
+	|> at_stop()
+
+experimental["join"](left: latest, right: earliest, fn: (left, right) =>
+	({left with _value: float(v: left["_value"]) - float(v: right["_value"])}))
+	|> monitor["check"](data: check, messageFn: messageFn, crit: crit)`,
+			},
+		},
+		{
+			name: "percent change",
+			args: args{
+				rateOfChange: check.RateOfChange{
+					Base: check.Base{
+						ID:                    10,
+						Name:                  "moo",
+						Every:                 mustDuration("1m"),
+						StatusMessageTemplate: "whoa! ${r._value}",
+						Query: influxdb.DashboardQuery{
+							Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> filter(fn: (r) => r._field == "usage_user") |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+						},
+					},
+					Period:  mustDuration("1h"),
+					Percent: true,
+					Thresholds: []check.ThresholdConfig{
+						check.Greater{
+							ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Warn},
+							Value:               10,
+						},
+						check.Range{
+							ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Critical},
+							Min:                 -50,
+							Max:                 50,
+						},
+					},
+				},
+			},
+			wants: wants{
+				script: `package main
+import "influxdata/influxdb/monitor"
+import "experimental"
+import "math"
+
+data = from(bucket: "foo")
+	|> range(start: -1h)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 1m, fn: mean, createEmpty: false)
+
+option task = {name: "moo", every: 1m}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "rateOfChange",
+	tags: {},
+}
+warn = (r) =>
+	(r["_value"] > 10.0)
+crit = (r) =>
+	(r["_value"] < -50.0 or r["_value"] > 50.0)
+messageFn = (r) =>
+	("whoa! ${r._value}")
+at_stop = (tables=<-) =>
+	(tables
+		|> duplicate(column: "_stop", as: "_time"))
+earliest = data
+	|> first()
+	|> drop(columns: ["_time"])
+	|> at_stop()
+latest = data
+	|> last()
+	|> drop(columns: ["_time"])
+	|> at_stop()
+
+experimental["join"](left: latest, right: earliest, fn: (left, right) =>
+	({left with _value: (float(v: left["_value"]) - float(v: right["_value"])) / math["abs"](x: float(v: right["_value"])) * 100.0}))
+	|> monitor["check"](
+		data: check,
+		messageFn: messageFn,
+		warn: warn,
+		crit: crit,
+	)`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := tt.args.rateOfChange.GenerateFluxAST(fluxlang.DefaultService)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			assert.Equal(t, tt.wants.script, ast.Format(p))
+		})
+	}
+}
+
+func TestRateOfChange_Valid(t *testing.T) {
+	thresholds := []check.ThresholdConfig{
+		&check.Greater{ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Critical}, Value: 10},
+	}
+	cases := []struct {
+		name string
+		src  check.RateOfChange
+		err  error
+	}{
+		{
+			name: "valid",
+			src: check.RateOfChange{
+				Base:       goodBase,
+				Period:     mustDuration("1h"),
+				Percent:    true,
+				Thresholds: thresholds,
+			},
+		},
+		{
+			name: "period not greater than every",
+			src: check.RateOfChange{
+				Base:       goodBase,
+				Period:     mustDuration("1m"),
+				Thresholds: thresholds,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "rate of change period must be greater than the interval",
+			},
+		},
+		{
+			name: "no thresholds",
+			src: check.RateOfChange{
+				Base:   goodBase,
+				Period: mustDuration("1h"),
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "rate of change check requires at least one threshold",
+			},
+		},
+		{
+			name: "bad threshold",
+			src: check.RateOfChange{
+				Base:   goodBase,
+				Period: mustDuration("1h"),
+				Thresholds: []check.ThresholdConfig{
+					&check.Range{Min: 200, Max: 100},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "range threshold min can't be larger than max",
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.src.Valid(fluxlang.DefaultService)
+			influxTesting.ErrorsEqual(t, got, c.err)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package delivery
+
+import (
+	"github.com/influxdata/influxdb/v2"
+)
+
+var (
+	// ErrNotificationDeliveryNotFound is used when the notification delivery is not found.
+	ErrNotificationDeliveryNotFound = &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "notification delivery not found",
+	}
+
+	// ErrDeliveryMismatch is used when the notification rule or endpoint of a
+	// delivery do not belong to its organization, or the rule does not notify the endpoint.
+	ErrDeliveryMismatch = &influxdb.Error{
+		Code: influxdb.EConflict,
+		Msg:  "notification delivery does not match its notification rule and endpoint",
+	}
+)
+
+// ErrInvalidNotificationDeliveryID is used when the ID of a notification delivery cannot be encoded.
+func ErrInvalidNotificationDeliveryID(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "invalid notification delivery ID",
+		Err:  err,
+	}
+}
+
+// ErrResendFailed is used when a resent notification is not accepted by its endpoint.
+func ErrResendFailed(msg string) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EUnavailable,
+		Msg:  "failed to resend notification: " + msg,
+	}
+}
+
+// ErrInternalService is used when the error comes from an internal system.
+func ErrInternalService(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Err:  err,
+	}
+}
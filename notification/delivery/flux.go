@@ -0,0 +1,297 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	"github.com/influxdata/influxdb/v2/notification/smtp"
+	"github.com/influxdata/influxdb/v2/query"
+)
+
+// Notifications are sent outside of the tasks of notification rules by
+// running the flux the rules generate for their tasks, with the statuses of
+// checks replaced by the status of the notification. The status skips the
+// status rules and the time range of the task, and the notification is not
+// logged to the _monitoring bucket. The flux of the rule is run with the
+// permissions of the owner of the rule, as its task is.
+
+// statusesVar is the variable holding the statuses the flux of a rule notifies.
+const statusesVar = "all_statuses"
+
+// Notification is a notification of a status of a check.
+type Notification struct {
+	RuleName  string
+	CheckID   influxdb.ID
+	CheckName string
+	CheckType string
+	Level     string
+	// Measurement is the measurement the check queried.
+	Measurement string
+	// Tags are the tags of the series, without the columns of the check.
+	Tags []influxdb.Tag
+	// Message is the message of the status, the message of the notification
+	// is rendered from it by the message template of the rule.
+	Message string
+	Time    time.Time
+}
+
+// Response is the response of an endpoint to a notification.
+type Response struct {
+	StatusCode int
+	// Message is the message that was sent, the request body for http and
+	// smtp endpoints.
+	Message string
+}
+
+// Sent returns whether the endpoint accepted the notification.
+func (r *Response) Sent() bool {
+	return r.StatusCode/100 == 2
+}
+
+// PermissionService finds the permissions of the owners of notification rules.
+type PermissionService interface {
+	FindPermissionForUser(ctx context.Context, userID influxdb.ID) (influxdb.PermissionSet, error)
+}
+
+var _ Notifier = (*FluxNotifier)(nil)
+
+// FluxNotifier sends notifications with the flux of their notification rule.
+type FluxNotifier struct {
+	qs    query.QueryService
+	lang  influxdb.FluxLanguageService
+	perms PermissionService
+	now   func() time.Time
+}
+
+// NewFluxNotifier returns a notifier that runs the flux of notification rules
+// with the query service.
+func NewFluxNotifier(qs query.QueryService, lang influxdb.FluxLanguageService, perms PermissionService) *FluxNotifier {
+	return &FluxNotifier{
+		qs:    qs,
+		lang:  lang,
+		perms: perms,
+		now:   time.Now,
+	}
+}
+
+// Send sends the notification to the endpoint with the flux of the rule. The
+// notification is delivered with the retry policy of the rule, a rule without
+// one attempts it once. An error is returned when the flux of the rule
+// fails, a response of the endpoint that rejects the notification is not an
+// error.
+func (n *FluxNotifier) Send(ctx context.Context, e influxdb.NotificationEndpoint, r influxdb.NotificationRule, nt Notification) (*Response, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	b, err := ruleBase(r)
+	if err != nil {
+		return nil, err
+	}
+	// only rules with a retry policy report the status code and the message
+	// of the notification.
+	if b.Retry == nil {
+		b.Retry = &rule.RetryPolicy{MaxAttempts: 1}
+		defer func() { b.Retry = nil }()
+	}
+	script, err := r.GenerateFlux(e)
+	if err != nil {
+		return nil, err
+	}
+	script, err = n.notifyStatus(script, nt)
+	if err != nil {
+		return nil, err
+	}
+
+	perm, err := n.perms.FindPermissionForUser(ctx, r.GetOwnerID())
+	if err != nil {
+		return nil, err
+	}
+	auth := &influxdb.Authorization{
+		Status:      influxdb.Active,
+		UserID:      r.GetOwnerID(),
+		ID:          influxdb.ID(1),
+		OrgID:       r.GetOrgID(),
+		Permissions: perm,
+	}
+	ctx = icontext.SetAuthorizer(ctx, auth)
+	if _, ok := r.(*rule.SMTP); ok {
+		ctx = smtp.WithDelivery(ctx)
+	}
+
+	it, err := n.qs.Query(ctx, &query.Request{
+		Authorization:  auth,
+		OrganizationID: r.GetOrgID(),
+		Compiler: lang.FluxCompiler{
+			Now:   n.now().UTC(),
+			Query: script,
+		},
+	})
+	if err != nil {
+		return nil, notifyError(err)
+	}
+	defer it.Release()
+
+	var resp *Response
+	for it.More() {
+		if err := it.Next().Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				if resp == nil && cr.Len() > 0 {
+					resp = readResponse(cr)
+				}
+				return nil
+			})
+		}); err != nil {
+			return nil, notifyError(err)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, notifyError(err)
+	}
+	if resp == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  fmt.Sprintf("notification rule %q did not notify the status", r.GetName()),
+		}
+	}
+	return resp, nil
+}
+
+// notifyStatus replaces the statuses that the script notifies with the status
+// of the notification, and the logging of notifications with a noop.
+func (n *FluxNotifier) notifyStatus(script string, nt Notification) (string, error) {
+	pkg, err := n.lang.Parse(script)
+	if err != nil {
+		return "", influxdb.ErrFluxParseError(err)
+	}
+	if len(pkg.Files) != 1 {
+		return "", fmt.Errorf("expected a script with a single file, got %d", len(pkg.Files))
+	}
+	f := pkg.Files[0]
+
+	var found bool
+	for _, s := range f.Body {
+		if v, ok := s.(*ast.VariableAssignment); ok && v.ID.Name == statusesVar {
+			v.Init = statusRows(nt)
+			found = true
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("script does not define %s", statusesVar)
+	}
+
+	f.Imports = append(f.Imports, &ast.ImportDeclaration{
+		Path: &ast.StringLiteral{Value: "experimental/array"},
+	})
+	noop := &ast.OptionStatement{
+		Assignment: &ast.MemberAssignment{
+			Member: &ast.MemberExpression{
+				Object:   &ast.Identifier{Name: "monitor"},
+				Property: &ast.Identifier{Name: "log"},
+			},
+			Init: &ast.FunctionExpression{
+				Params: []*ast.Property{{
+					Key:   &ast.Identifier{Name: "tables"},
+					Value: &ast.PipeLiteral{},
+				}},
+				Body: &ast.Identifier{Name: "tables"},
+			},
+		},
+	}
+	f.Body = append([]ast.Statement{noop}, f.Body...)
+	return ast.Format(f), nil
+}
+
+// statusRows returns the status of the notification as the rows of a table,
+// with the columns of the statuses that checks write.
+func statusRows(nt Notification) ast.Expression {
+	str := func(k, v string) *ast.Property {
+		return &ast.Property{Key: &ast.Identifier{Name: k}, Value: &ast.StringLiteral{Value: v}}
+	}
+	t := nt.Time.UTC()
+	props := []*ast.Property{
+		{Key: &ast.Identifier{Name: "_time"}, Value: &ast.DateTimeLiteral{Value: t}},
+		str("_measurement", "statuses"),
+		str("_check_id", nt.CheckID.String()),
+		str("_check_name", nt.CheckName),
+		str("_type", nt.CheckType),
+		str("_level", nt.Level),
+		str("_message", nt.Message),
+		str("_source_measurement", nt.Measurement),
+		{Key: &ast.Identifier{Name: "_source_timestamp"}, Value: &ast.IntegerLiteral{Value: t.UnixNano()}},
+	}
+	for _, tag := range nt.Tags {
+		if !strings.HasPrefix(tag.Key, "_") {
+			props = append(props, &ast.Property{Key: &ast.StringLiteral{Value: tag.Key}, Value: &ast.StringLiteral{Value: tag.Value}})
+		}
+	}
+	return &ast.CallExpression{
+		Callee: &ast.MemberExpression{
+			Object:   &ast.Identifier{Name: "array"},
+			Property: &ast.Identifier{Name: "from"},
+		},
+		Arguments: []ast.Expression{&ast.ObjectExpression{Properties: []*ast.Property{{
+			Key:   &ast.Identifier{Name: "rows"},
+			Value: &ast.ArrayExpression{Elements: []ast.Expression{&ast.ObjectExpression{Properties: props}}},
+		}}}},
+	}
+}
+
+// readResponse reads the delivery of the first notification, see
+// rule.RetryPolicy.
+func readResponse(cr flux.ColReader) *Response {
+	resp := &Response{}
+	for j, c := range cr.Cols() {
+		switch {
+		case c.Label == "_status_code" && c.Type == flux.TInt:
+			if vs := cr.Ints(j); vs.IsValid(0) {
+				resp.StatusCode = int(vs.Value(0))
+			}
+		case c.Label == "_notification_message" && c.Type == flux.TString:
+			if vs := cr.Strings(j); vs.IsValid(0) {
+				resp.Message = vs.ValueString(0)
+			}
+		}
+	}
+	return resp
+}
+
+// ruleBase returns the base of the rule, to configure its flux.
+func ruleBase(r influxdb.NotificationRule) (*rule.Base, error) {
+	switch r := r.(type) {
+	case *rule.Slack:
+		return &r.Base, nil
+	case *rule.PagerDuty:
+		return &r.Base, nil
+	case *rule.HTTP:
+		return &r.Base, nil
+	case *rule.SMTP:
+		return &r.Base, nil
+	case *rule.Teams:
+		return &r.Base, nil
+	case *rule.Telegram:
+		return &r.Base, nil
+	case *rule.Opsgenie:
+		return &r.Base, nil
+	}
+	return nil, &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  fmt.Sprintf("sending notifications of %s notification rules is not supported", r.Type()),
+	}
+}
+
+func notifyError(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EUnavailable,
+		Msg:  "failed to send notification",
+		Err:  err,
+	}
+}
@@ -0,0 +1,94 @@
+package delivery_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/delivery"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	"github.com/influxdata/influxdb/v2/query"
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+	qmock "github.com/influxdata/influxdb/v2/query/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFluxNotifier_Send(t *testing.T) {
+	orgID := influxdb.ID(1)
+	e := &endpoint.Slack{Base: endpoint.Base{ID: idPtr(3), OrgID: &orgID, Name: "ops"}, URL: "http://localhost:7777"}
+	r := &rule.Slack{
+		Base: rule.Base{
+			ID: 10, OrgID: orgID, OwnerID: 6, Name: "cpu", EndpointID: 3,
+			Every:       mustDuration(t, "1h"),
+			StatusRules: []notification.StatusRule{{CurrentLevel: notification.Warn}},
+		},
+		Channel:         "#ops",
+		MessageTemplate: `${r._check_name} is ${r._level} on ${r.host}`,
+	}
+
+	var req *query.Request
+	qs := &qmock.QueryService{
+		QueryF: func(ctx context.Context, r *query.Request) (flux.ResultIterator, error) {
+			req = r
+			return flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+				Nm: "_result",
+				Tbls: []*executetest.Table{{
+					ColMeta: []flux.ColMeta{
+						{Label: "_notification_message", Type: flux.TString},
+						{Label: "_status_code", Type: flux.TInt},
+					},
+					Data: [][]interface{}{{"cpu is warn on a", int64(200)}},
+				}},
+			}}), nil
+		},
+	}
+	perms := mock.NewUserService()
+	perms.FindPermissionForUserFn = func(ctx context.Context, id influxdb.ID) (influxdb.PermissionSet, error) {
+		return influxdb.PermissionSet{{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID}}}, nil
+	}
+
+	n := delivery.NewFluxNotifier(qs, fluxlang.DefaultService, perms)
+	resp, err := n.Send(context.Background(), e, r, delivery.Notification{
+		RuleName:    "cpu",
+		CheckID:     5,
+		CheckName:   "cpu",
+		Level:       "warn",
+		Measurement: "cpu",
+		Tags:        []influxdb.Tag{{Key: "host", Value: "a"}},
+		Message:     "cpu is high",
+		Time:        time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &delivery.Response{StatusCode: 200, Message: "cpu is warn on a"}, resp)
+	assert.True(t, resp.Sent())
+
+	require.NotNil(t, req)
+	assert.Equal(t, influxdb.ID(6), req.Authorization.UserID)
+	assert.Equal(t, orgID, req.OrganizationID)
+	script := req.Compiler.(lang.FluxCompiler).Query
+	assert.Contains(t, script, `import "experimental/array"`)
+	assert.Contains(t, script, `option monitor.log = (tables=<-) => tables`)
+	assert.Contains(t, script, `all_statuses = array.from(`)
+	assert.Contains(t, script, `_check_name: "cpu"`)
+	assert.Contains(t, script, `"host": "a"`)
+	assert.Contains(t, script, `_status_code`)
+	// the retry policy that reports the delivery is not kept on the rule.
+	assert.Nil(t, r.Retry)
+}
+
+func mustDuration(t *testing.T, d string) *notification.Duration {
+	t.Helper()
+	dur, err := time.ParseDuration(d)
+	require.NoError(t, err)
+	nd, err := notification.FromTimeDuration(dur)
+	require.NoError(t, err)
+	return &nd
+}
@@ -0,0 +1,79 @@
+package delivery
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/pkg/httpc"
+)
+
+var _ influxdb.NotificationDeliveryService = (*ClientService)(nil)
+
+// ClientService connects to Influx via HTTP using tokens to manage failed notification deliveries.
+type ClientService struct {
+	Client *httpc.Client
+}
+
+// FindNotificationDeliveryByID returns a single notification delivery by ID via HTTP.
+func (s *ClientService) FindNotificationDeliveryByID(ctx context.Context, id influxdb.ID) (*influxdb.NotificationDelivery, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var d influxdb.NotificationDelivery
+	err := s.Client.
+		Get(PrefixNotificationDeliveries, id.String()).
+		DecodeJSON(&d).
+		Do(ctx)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	return &d, nil
+}
+
+// FindNotificationDeliveries returns the notification deliveries matching the filter via HTTP.
+func (s *ClientService) FindNotificationDeliveries(ctx context.Context, filter influxdb.NotificationDeliveryFilter) ([]*influxdb.NotificationDelivery, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var params [][2]string
+	if filter.OrgID != nil {
+		params = append(params, [2]string{"orgID", filter.OrgID.String()})
+	}
+	if filter.RuleID != nil {
+		params = append(params, [2]string{"ruleID", filter.RuleID.String()})
+	}
+	if filter.EndpointID != nil {
+		params = append(params, [2]string{"endpointID", filter.EndpointID.String()})
+	}
+	if filter.State != nil {
+		params = append(params, [2]string{"state", string(*filter.State)})
+	}
+
+	var resp deliveriesResponse
+	err := s.Client.
+		Get(PrefixNotificationDeliveries).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	return resp.Deliveries, nil
+}
+
+// ResendNotificationDelivery resends a failed notification delivery via HTTP.
+func (s *ClientService) ResendNotificationDelivery(ctx context.Context, id influxdb.ID) (*influxdb.NotificationDelivery, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var d influxdb.NotificationDelivery
+	err := s.Client.
+		Post(nil, PrefixNotificationDeliveries, id.String(), "resend").
+		DecodeJSON(&d).
+		Do(ctx)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	return &d, nil
+}
@@ -0,0 +1,142 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/influxdata/influxdb/v2"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"go.uber.org/zap"
+)
+
+// PrefixNotificationDeliveries is the prefix of the notification delivery API.
+const PrefixNotificationDeliveries = "/api/v2/notificationDeliveries"
+
+// Handler serves the failed deliveries of notification rules.
+type Handler struct {
+	chi.Router
+	api         *kithttp.API
+	log         *zap.Logger
+	deliverySvc influxdb.NotificationDeliveryService
+}
+
+// NewHTTPHandler constructs a new http server for notification deliveries.
+func NewHTTPHandler(log *zap.Logger, svc influxdb.NotificationDeliveryService) *Handler {
+	h := &Handler{
+		api:         kithttp.NewAPI(kithttp.WithLog(log)),
+		log:         log,
+		deliverySvc: svc,
+	}
+
+	r := chi.NewRouter()
+	r.Use(
+		middleware.Recoverer,
+		middleware.RequestID,
+		middleware.RealIP,
+	)
+
+	r.Route("/", func(r chi.Router) {
+		r.Get("/", h.handleGetDeliveries)
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h.handleGetDelivery)
+			r.Post("/resend", h.handlePostDeliveryResend)
+		})
+	})
+
+	h.Router = r
+	return h
+}
+
+// Prefix returns the prefix the handler is mounted at.
+func (h *Handler) Prefix() string {
+	return PrefixNotificationDeliveries
+}
+
+type deliveriesResponse struct {
+	Deliveries []*influxdb.NotificationDelivery `json:"notificationDeliveries"`
+}
+
+// handleGetDeliveries is the HTTP handler for the GET /api/v2/notificationDeliveries route.
+// The orgID, ruleID, endpointID and state query parameters filter the deliveries.
+func (h *Handler) handleGetDeliveries(w http.ResponseWriter, r *http.Request) {
+	var filter influxdb.NotificationDeliveryFilter
+	q := r.URL.Query()
+	if orgID := q.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			h.api.Err(w, r, err)
+			return
+		}
+		filter.OrgID = id
+	}
+	if ruleID := q.Get("ruleID"); ruleID != "" {
+		id, err := influxdb.IDFromString(ruleID)
+		if err != nil {
+			h.api.Err(w, r, err)
+			return
+		}
+		filter.RuleID = id
+	}
+	if endpointID := q.Get("endpointID"); endpointID != "" {
+		id, err := influxdb.IDFromString(endpointID)
+		if err != nil {
+			h.api.Err(w, r, err)
+			return
+		}
+		filter.EndpointID = id
+	}
+	if state := q.Get("state"); state != "" {
+		s := influxdb.NotificationDeliveryState(state)
+		if err := s.Valid(); err != nil {
+			h.api.Err(w, r, err)
+			return
+		}
+		filter.State = &s
+	}
+
+	ds, err := h.deliverySvc.FindNotificationDeliveries(r.Context(), filter)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Notification deliveries retrieved", zap.Int("count", len(ds)))
+
+	h.api.Respond(w, r, http.StatusOK, deliveriesResponse{Deliveries: ds})
+}
+
+// handleGetDelivery is the HTTP handler for the GET /api/v2/notificationDeliveries/:id route.
+func (h *Handler) handleGetDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := influxdb.IDFromString(chi.URLParam(r, "id"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	d, err := h.deliverySvc.FindNotificationDeliveryByID(r.Context(), *id)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	h.api.Respond(w, r, http.StatusOK, d)
+}
+
+// handlePostDeliveryResend is the HTTP handler for the POST /api/v2/notificationDeliveries/:id/resend route.
+func (h *Handler) handlePostDeliveryResend(w http.ResponseWriter, r *http.Request) {
+	id, err := influxdb.IDFromString(chi.URLParam(r, "id"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	d, err := h.deliverySvc.ResendNotificationDelivery(r.Context(), *id)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Notification delivery resent", zap.String("id", id.String()))
+
+	h.api.Respond(w, r, http.StatusOK, d)
+}
@@ -0,0 +1,69 @@
+package delivery
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+)
+
+var _ influxdb.NotificationDeliveryService = (*AuthorizedService)(nil)
+
+// AuthorizedService restricts access to notification deliveries. Deliveries
+// are made by notification rules, reading them requires read access to their
+// rule and resending them requires write access to it.
+type AuthorizedService struct {
+	s influxdb.NotificationDeliveryService
+}
+
+// NewAuthorizedService wraps a notification delivery service with authorization checks.
+func NewAuthorizedService(s influxdb.NotificationDeliveryService) *AuthorizedService {
+	return &AuthorizedService{s: s}
+}
+
+// FindNotificationDeliveryByID checks that the authorizer may read the rule of the delivery.
+func (s *AuthorizedService) FindNotificationDeliveryByID(ctx context.Context, id influxdb.ID) (*influxdb.NotificationDelivery, error) {
+	d, err := s.s.FindNotificationDeliveryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := authorizer.AuthorizeRead(ctx, influxdb.NotificationRuleResourceType, d.RuleID, d.OrgID); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// FindNotificationDeliveries returns only the deliveries of rules that the authorizer may read.
+func (s *AuthorizedService) FindNotificationDeliveries(ctx context.Context, filter influxdb.NotificationDeliveryFilter) ([]*influxdb.NotificationDelivery, error) {
+	ds, err := s.s.FindNotificationDeliveries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	dds := ds[:0]
+	for _, d := range ds {
+		_, _, err := authorizer.AuthorizeRead(ctx, influxdb.NotificationRuleResourceType, d.RuleID, d.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		dds = append(dds, d)
+	}
+	return dds, nil
+}
+
+// ResendNotificationDelivery checks that the authorizer may write the rule of the delivery.
+func (s *AuthorizedService) ResendNotificationDelivery(ctx context.Context, id influxdb.ID) (*influxdb.NotificationDelivery, error) {
+	d, err := s.s.FindNotificationDeliveryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := authorizer.AuthorizeWrite(ctx, influxdb.NotificationRuleResourceType, d.RuleID, d.OrgID); err != nil {
+		return nil, err
+	}
+	return s.s.ResendNotificationDelivery(ctx, id)
+}
@@ -0,0 +1,156 @@
+package delivery
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage"
+	"go.uber.org/zap"
+)
+
+// Recorder records failed notification deliveries.
+type Recorder interface {
+	RecordNotificationDelivery(ctx context.Context, d *influxdb.NotificationDelivery) error
+}
+
+var _ storage.PointsWriter = (*PointsWriter)(nil)
+
+// PointsWriter records the failed deliveries of notification rules with a
+// retry policy from the notifications that their tasks write. These are
+// points of the notifications measurement with a _status_code field, they
+// are failed when their _sent tag is false. Only the points written to the
+// _monitoring bucket of the organization are recorded, where rule tasks write
+// their notifications. Failing to record a point is logged and does not fail
+// the write.
+type PointsWriter struct {
+	storage.PointsWriter
+	log        *zap.Logger
+	buckets    influxdb.BucketService
+	deliveries Recorder
+}
+
+// NewPointsWriter wraps a points writer with delivery tracking.
+func NewPointsWriter(log *zap.Logger, w storage.PointsWriter, buckets influxdb.BucketService, deliveries Recorder) *PointsWriter {
+	return &PointsWriter{
+		PointsWriter: w,
+		log:          log,
+		buckets:      buckets,
+		deliveries:   deliveries,
+	}
+}
+
+// WritePoints writes the points and records the failed deliveries among them.
+func (w *PointsWriter) WritePoints(ctx context.Context, orgID influxdb.ID, bucketID influxdb.ID, points []models.Point) error {
+	if err := w.PointsWriter.WritePoints(ctx, orgID, bucketID, points); err != nil {
+		return err
+	}
+
+	// the bucket is looked up once, by the first failed delivery
+	checked := false
+	for _, p := range points {
+		d, ok := parseFailedDelivery(orgID, p)
+		if !ok {
+			continue
+		}
+		if !checked {
+			if !w.isMonitoringBucket(ctx, orgID, bucketID) {
+				return nil
+			}
+			checked = true
+		}
+		if err := w.deliveries.RecordNotificationDelivery(ctx, d); err != nil {
+			w.log.Error("Failed to record notification delivery",
+				zap.String("rule_id", d.RuleID.String()),
+				zap.String("endpoint_id", d.EndpointID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// isMonitoringBucket reports whether the bucket is the _monitoring bucket of the organization.
+func (w *PointsWriter) isMonitoringBucket(ctx context.Context, orgID, bucketID influxdb.ID) bool {
+	b, err := w.buckets.FindBucketByID(ctx, bucketID)
+	if err != nil {
+		w.log.Error("Failed to find bucket of notifications", zap.String("bucket_id", bucketID.String()), zap.Error(err))
+		return false
+	}
+	return b.OrgID == orgID && b.Name == influxdb.MonitoringSystemBucketName
+}
+
+func parseFailedDelivery(orgID influxdb.ID, p models.Point) (*influxdb.NotificationDelivery, bool) {
+	if string(p.Name()) != "notifications" {
+		return nil, false
+	}
+
+	d := &influxdb.NotificationDelivery{
+		OrgID: orgID,
+		Time:  p.Time().UTC(),
+	}
+	var failed bool
+	for _, t := range p.Tags() {
+		k, v := string(t.Key), string(t.Value)
+		switch k {
+		case "_check_id", "_notification_rule_id", "_notification_endpoint_id":
+			id, err := influxdb.IDFromString(v)
+			if err != nil {
+				return nil, false
+			}
+			switch k {
+			case "_check_id":
+				d.CheckID = *id
+			case "_notification_rule_id":
+				d.RuleID = *id
+			default:
+				d.EndpointID = *id
+			}
+		case "_check_name":
+			d.CheckName = v
+		case "_type":
+			d.CheckType = v
+		case "_source_measurement":
+			d.SourceMeasurement = v
+		case "_level":
+			d.Level = v
+		case "_notification_rule_name":
+			d.RuleName = v
+		case "_notification_endpoint_name":
+			d.EndpointName = v
+		case "_sent":
+			failed = v == "false"
+		default:
+			if !strings.HasPrefix(k, "_") {
+				d.Tags = append(d.Tags, influxdb.Tag{Key: k, Value: v})
+			}
+		}
+	}
+	if !failed || !d.CheckID.Valid() || !d.RuleID.Valid() || !d.EndpointID.Valid() {
+		return nil, false
+	}
+
+	fields, err := p.Fields()
+	if err != nil {
+		return nil, false
+	}
+	code, ok := fields["_status_code"].(int64)
+	if !ok {
+		return nil, false
+	}
+	d.StatusCode = int(code)
+	if attempts, ok := fields["_attempts"].(int64); ok {
+		d.Attempts = int(attempts)
+	}
+	d.LatencyMS, _ = fields["_latency_ms"].(int64)
+	d.Error, _ = fields["_error"].(string)
+	d.Message, _ = fields["_notification_message"].(string)
+	d.StatusMessage, _ = fields["_message"].(string)
+	d.StatusTime = d.Time
+	if ts, ok := fields["_status_timestamp"].(int64); ok {
+		d.StatusTime = time.Unix(0, ts).UTC()
+	}
+	return d, true
+}
@@ -0,0 +1,82 @@
+package delivery_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/notification/delivery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type recorder struct {
+	deliveries []*influxdb.NotificationDelivery
+}
+
+func (r *recorder) RecordNotificationDelivery(ctx context.Context, d *influxdb.NotificationDelivery) error {
+	r.deliveries = append(r.deliveries, d)
+	return nil
+}
+
+func TestPointsWriter(t *testing.T) {
+	points, err := models.ParsePointsString(`notifications,_check_id=000000000000000a,_check_name=cpu,_level=crit,_notification_endpoint_id=000000000000000c,_notification_endpoint_name=ops,_notification_rule_id=000000000000000b,_notification_rule_name=cpu,_sent=false,_source_measurement=cpu,_type=threshold,host=a _message="cpu is crit",_status_timestamp=1590969540000000000i,_status_code=503i,_attempts=3i,_latency_ms=3012i,_error="HTTP status 503",_notification_message="cpu is crit on a" 1590969600000000000
+notifications,_check_id=000000000000000a,_level=crit,_notification_endpoint_id=000000000000000c,_notification_rule_id=000000000000000b,_sent=true,host=a _message="cpu is crit",_status_code=200i,_attempts=1i 1590969600000000000
+notifications,_check_id=000000000000000a,_level=crit,_notification_endpoint_id=000000000000000c,_notification_rule_id=000000000000000b,_sent=false,host=a _message="cpu is crit",_silence_id="000000000000000d" 1590969600000000000
+statuses,_check_id=000000000000000a,_level=crit,host=a _message="cpu is crit" 1590969600000000000`)
+	require.NoError(t, err)
+
+	var written int
+	underlying := &mock.PointsWriter{}
+	underlying.WritePointsFn = func(ctx context.Context, orgID influxdb.ID, bucketID influxdb.ID, points []models.Point) error {
+		written += len(points)
+		return nil
+	}
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		if id == 2 {
+			return &influxdb.Bucket{ID: id, OrgID: 1, Name: influxdb.MonitoringSystemBucketName}, nil
+		}
+		return &influxdb.Bucket{ID: id, OrgID: 1, Name: "telegraf"}, nil
+	}
+	rec := &recorder{}
+	w := delivery.NewPointsWriter(zaptest.NewLogger(t), underlying, buckets, rec)
+
+	// notifications written to other buckets are not deliveries
+	require.NoError(t, w.WritePoints(context.Background(), 1, 3, points))
+	assert.Empty(t, rec.deliveries)
+	// nor are those written to the _monitoring bucket of another organization
+	require.NoError(t, w.WritePoints(context.Background(), 4, 2, points))
+	assert.Empty(t, rec.deliveries)
+
+	written = 0
+	require.NoError(t, w.WritePoints(context.Background(), 1, 2, points))
+	assert.Equal(t, 4, written)
+	assert.Equal(t, []*influxdb.NotificationDelivery{
+		{
+			OrgID:             1,
+			RuleID:            11,
+			RuleName:          "cpu",
+			EndpointID:        12,
+			EndpointName:      "ops",
+			CheckID:           10,
+			CheckName:         "cpu",
+			Level:             "crit",
+			Tags:              []influxdb.Tag{{Key: "host", Value: "a"}},
+			Message:           "cpu is crit on a",
+			CheckType:         "threshold",
+			SourceMeasurement: "cpu",
+			StatusMessage:     "cpu is crit",
+			StatusTime:        time.Date(2020, 5, 31, 23, 59, 0, 0, time.UTC),
+			StatusCode:        503,
+			Attempts:          3,
+			LatencyMS:         3012,
+			Error:             "HTTP status 503",
+			Time:              time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}, rec.deliveries)
+}
@@ -0,0 +1,111 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+// Store stores the failed notification deliveries, see Service.
+type Store interface {
+	FindNotificationDeliveryByID(ctx context.Context, id influxdb.ID) (*influxdb.NotificationDelivery, error)
+	FindNotificationDeliveries(ctx context.Context, filter influxdb.NotificationDeliveryFilter) ([]*influxdb.NotificationDelivery, error)
+	UpdateNotificationDelivery(ctx context.Context, d *influxdb.NotificationDelivery) error
+}
+
+// Notifier sends notifications to endpoints, see FluxNotifier.
+type Notifier interface {
+	Send(ctx context.Context, e influxdb.NotificationEndpoint, r influxdb.NotificationRule, n Notification) (*Response, error)
+}
+
+var _ influxdb.NotificationDeliveryService = (*ResendingService)(nil)
+
+// ResendingService resends the failed deliveries of the store to the
+// endpoints they failed to reach. The status of the delivery is notified
+// again by the flux of the current notification rule, so the notification is
+// formatted and retried as the rule task does.
+type ResendingService struct {
+	Store
+	rules     influxdb.NotificationRuleStore
+	endpoints influxdb.NotificationEndpointService
+	notifier  Notifier
+	now       func() time.Time
+}
+
+// NewResendingService returns a notification delivery service that resends the deliveries of the store with the notifier.
+func NewResendingService(s Store, rules influxdb.NotificationRuleStore, endpoints influxdb.NotificationEndpointService, notifier Notifier) *ResendingService {
+	return &ResendingService{
+		Store:     s,
+		rules:     rules,
+		endpoints: endpoints,
+		notifier:  notifier,
+		now:       time.Now,
+	}
+}
+
+// ResendNotificationDelivery notifies the status of the delivery to the
+// endpoint it failed to reach, with the current notification rule.
+func (s *ResendingService) ResendNotificationDelivery(ctx context.Context, id influxdb.ID) (*influxdb.NotificationDelivery, error) {
+	d, err := s.FindNotificationDeliveryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r, err := s.rules.FindNotificationRuleByID(ctx, d.RuleID)
+	if err != nil {
+		return nil, err
+	}
+	e, err := s.endpoints.FindNotificationEndpointByID(ctx, d.EndpointID)
+	if err != nil {
+		return nil, err
+	}
+	// only the deliveries to the endpoint of the rule are recorded, the
+	// escalation steps of a rule do not retry.
+	if r.GetOrgID() != d.OrgID || e.GetOrgID() != d.OrgID || r.GetEndpointID() != e.GetID() {
+		return nil, ErrDeliveryMismatch
+	}
+
+	// deliveries recorded without the time of their status were recorded
+	// with the status, at the time of the notification.
+	statusTime := d.StatusTime
+	if statusTime.IsZero() {
+		statusTime = d.Time
+	}
+	resp, sendErr := s.notifier.Send(ctx, e, r, Notification{
+		RuleName:    d.RuleName,
+		CheckID:     d.CheckID,
+		CheckName:   d.CheckName,
+		CheckType:   d.CheckType,
+		Level:       d.Level,
+		Measurement: d.SourceMeasurement,
+		Tags:        d.Tags,
+		Message:     d.StatusMessage,
+		Time:        statusTime,
+	})
+
+	now := s.now().UTC()
+	d.Resends++
+	d.LastResentAt = &now
+	switch {
+	case sendErr != nil:
+		d.Error = sendErr.Error()
+	case !resp.Sent():
+		d.Message = resp.Message
+		d.StatusCode = resp.StatusCode
+		d.Error = fmt.Sprintf("HTTP status %d", resp.StatusCode)
+	default:
+		d.State = influxdb.NotificationDeliveryResent
+		d.Message = resp.Message
+		d.StatusCode = resp.StatusCode
+		d.Error = ""
+	}
+
+	if err := s.Store.UpdateNotificationDelivery(ctx, d); err != nil {
+		return nil, err
+	}
+	if d.State != influxdb.NotificationDeliveryResent {
+		return nil, ErrResendFailed(d.Error)
+	}
+	return d, nil
+}
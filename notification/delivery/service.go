@@ -0,0 +1,156 @@
+package delivery
+
+// The notification delivery `Service` stores the failed deliveries of every
+// organization in a kv bucket keyed by the encoded delivery ID. Deliveries
+// are recorded from the notifications written by notification rule tasks,
+// see PointsWriter, and are resent by the ResendingService of this package.
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/snowflake"
+)
+
+var deliveryBucket = []byte("notificationdeliveriesv1")
+
+// Service is the kv store of notification deliveries.
+type Service struct {
+	store       kv.Store
+	idGenerator influxdb.IDGenerator
+}
+
+// NewService returns a notification delivery store backed by the provided kv store.
+func NewService(st kv.Store) *Service {
+	return &Service{
+		store:       st,
+		idGenerator: snowflake.NewIDGenerator(),
+	}
+}
+
+// FindNotificationDeliveryByID returns a single notification delivery by ID.
+func (s *Service) FindNotificationDeliveryByID(ctx context.Context, id influxdb.ID) (*influxdb.NotificationDelivery, error) {
+	key, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidNotificationDeliveryID(err)
+	}
+
+	var d *influxdb.NotificationDelivery
+	err = s.store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(deliveryBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		d, err = getDelivery(b, key)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// FindNotificationDeliveries returns the notification deliveries matching the filter, most recent first.
+func (s *Service) FindNotificationDeliveries(ctx context.Context, filter influxdb.NotificationDeliveryFilter) ([]*influxdb.NotificationDelivery, error) {
+	ds := []*influxdb.NotificationDelivery{}
+	err := s.store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(deliveryBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		cur, err := b.ForwardCursor(nil, kv.WithCursorDirection(kv.CursorDescending))
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		defer cur.Close()
+
+		for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+			d := &influxdb.NotificationDelivery{}
+			if err := json.Unmarshal(v, d); err != nil {
+				return ErrInternalService(err)
+			}
+			if filter.OrgID != nil && d.OrgID != *filter.OrgID {
+				continue
+			}
+			if filter.RuleID != nil && d.RuleID != *filter.RuleID {
+				continue
+			}
+			if filter.EndpointID != nil && d.EndpointID != *filter.EndpointID {
+				continue
+			}
+			if filter.State != nil && d.State != *filter.State {
+				continue
+			}
+			ds = append(ds, d)
+		}
+		return cur.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+// RecordNotificationDelivery records a failed delivery and sets d.ID with the new identifier.
+func (s *Service) RecordNotificationDelivery(ctx context.Context, d *influxdb.NotificationDelivery) error {
+	d.ID = s.idGenerator.ID()
+	d.State = influxdb.NotificationDeliveryFailed
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		return putDelivery(tx, d)
+	})
+}
+
+// UpdateNotificationDelivery replaces a recorded notification delivery.
+func (s *Service) UpdateNotificationDelivery(ctx context.Context, d *influxdb.NotificationDelivery) error {
+	key, err := d.ID.Encode()
+	if err != nil {
+		return ErrInvalidNotificationDeliveryID(err)
+	}
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(deliveryBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		if _, err := getDelivery(b, key); err != nil {
+			return err
+		}
+		return putDelivery(tx, d)
+	})
+}
+
+func getDelivery(b kv.Bucket, key []byte) (*influxdb.NotificationDelivery, error) {
+	v, err := b.Get(key)
+	if kv.IsNotFound(err) {
+		return nil, ErrNotificationDeliveryNotFound
+	}
+	if err != nil {
+		return nil, ErrInternalService(err)
+	}
+
+	d := &influxdb.NotificationDelivery{}
+	if err := json.Unmarshal(v, d); err != nil {
+		return nil, ErrInternalService(err)
+	}
+	return d, nil
+}
+
+func putDelivery(tx kv.Tx, d *influxdb.NotificationDelivery) error {
+	key, err := d.ID.Encode()
+	if err != nil {
+		return ErrInvalidNotificationDeliveryID(err)
+	}
+	b, err := tx.Bucket(deliveryBucket)
+	if err != nil {
+		return ErrInternalService(err)
+	}
+	v, err := json.Marshal(d)
+	if err != nil {
+		return ErrInternalService(err)
+	}
+	if err := b.Put(key, v); err != nil {
+		return ErrInternalService(err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package delivery_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kv/migration/all"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/notification/delivery"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type notifier struct {
+	codes         []int
+	rules         []influxdb.NotificationRule
+	notifications []delivery.Notification
+}
+
+func (n *notifier) Send(ctx context.Context, e influxdb.NotificationEndpoint, r influxdb.NotificationRule, nt delivery.Notification) (*delivery.Response, error) {
+	n.rules = append(n.rules, r)
+	n.notifications = append(n.notifications, nt)
+	code := n.codes[0]
+	n.codes = n.codes[1:]
+	return &delivery.Response{StatusCode: code, Message: nt.Message}, nil
+}
+
+func newTestService(t *testing.T, n delivery.Notifier) (*delivery.Service, *delivery.ResendingService) {
+	t.Helper()
+
+	store := inmem.NewKVStore()
+	if err := all.Up(context.Background(), zaptest.NewLogger(t), store); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := mock.NewNotificationRuleStore()
+	rules.FindNotificationRuleByIDF = func(ctx context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+		// rule 7 belongs to another organization
+		orgID := influxdb.ID(1)
+		if id == 7 {
+			orgID = 8
+		}
+		return &rule.Slack{Base: rule.Base{ID: id, OrgID: orgID, Name: "cpu", EndpointID: 3}, Channel: "#ops"}, nil
+	}
+	endpoints := mock.NewNotificationEndpointService()
+	endpoints.FindNotificationEndpointByIDF = func(ctx context.Context, id influxdb.ID) (influxdb.NotificationEndpoint, error) {
+		orgID := influxdb.ID(1)
+		return &endpoint.Slack{Base: endpoint.Base{ID: &id, OrgID: &orgID, Name: "ops"}, URL: "http://localhost:7777"}, nil
+	}
+	svc := delivery.NewService(store)
+	return svc, delivery.NewResendingService(svc, rules, endpoints, n)
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	n := &notifier{codes: []int{503, 200}}
+	store, svc := newTestService(t, n)
+
+	at := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	cpu := &influxdb.NotificationDelivery{
+		OrgID:             1,
+		RuleID:            2,
+		EndpointID:        3,
+		CheckID:           4,
+		CheckName:         "cpu",
+		Level:             "crit",
+		Tags:              []influxdb.Tag{{Key: "host", Value: "a"}},
+		Message:           "cpu is crit on a",
+		CheckType:         "threshold",
+		SourceMeasurement: "cpu",
+		StatusMessage:     "cpu is crit",
+		StatusTime:        at.Add(-time.Minute),
+		StatusCode:        503,
+		Attempts:          3,
+		Error:             "HTTP status 503",
+		Time:              at,
+	}
+	require.NoError(t, store.RecordNotificationDelivery(ctx, cpu))
+	assert.True(t, cpu.ID.Valid())
+	assert.Equal(t, influxdb.NotificationDeliveryFailed, cpu.State)
+	disk := &influxdb.NotificationDelivery{OrgID: 1, RuleID: 5, EndpointID: 3, CheckID: 6, StatusCode: 500, Time: at}
+	require.NoError(t, store.RecordNotificationDelivery(ctx, disk))
+
+	ds, err := svc.FindNotificationDeliveries(ctx, influxdb.NotificationDeliveryFilter{})
+	require.NoError(t, err)
+	require.Len(t, ds, 2)
+	assert.Equal(t, disk.ID, ds[0].ID, "most recent first")
+
+	ruleID := influxdb.ID(2)
+	ds, err = svc.FindNotificationDeliveries(ctx, influxdb.NotificationDeliveryFilter{RuleID: &ruleID})
+	require.NoError(t, err)
+	require.Len(t, ds, 1)
+	assert.Equal(t, cpu.ID, ds[0].ID)
+
+	_, err = svc.ResendNotificationDelivery(ctx, cpu.ID)
+	assert.Equal(t, influxdb.EUnavailable, influxdb.ErrorCode(err))
+	d, err := svc.FindNotificationDeliveryByID(ctx, cpu.ID)
+	require.NoError(t, err)
+	assert.Equal(t, influxdb.NotificationDeliveryFailed, d.State)
+	assert.Equal(t, 1, d.Resends)
+	assert.NotNil(t, d.LastResentAt)
+
+	d, err = svc.ResendNotificationDelivery(ctx, cpu.ID)
+	require.NoError(t, err)
+	assert.Equal(t, influxdb.NotificationDeliveryResent, d.State)
+	assert.Equal(t, 200, d.StatusCode)
+	assert.Equal(t, 2, d.Resends)
+	assert.Empty(t, d.Error)
+	// the status is notified again, the message the rule formats from it is
+	// the one recorded.
+	assert.Equal(t, "cpu is crit", d.Message)
+	assert.Equal(t, delivery.Notification{
+		CheckID:     4,
+		CheckName:   "cpu",
+		CheckType:   "threshold",
+		Level:       "crit",
+		Measurement: "cpu",
+		Tags:        []influxdb.Tag{{Key: "host", Value: "a"}},
+		Message:     "cpu is crit",
+		Time:        at.Add(-time.Minute),
+	}, n.notifications[1])
+
+	failed := influxdb.NotificationDeliveryFailed
+	ds, err = svc.FindNotificationDeliveries(ctx, influxdb.NotificationDeliveryFilter{State: &failed})
+	require.NoError(t, err)
+	require.Len(t, ds, 1)
+	assert.Equal(t, disk.ID, ds[0].ID)
+
+	_, err = svc.FindNotificationDeliveryByID(ctx, 42)
+	assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+
+	// deliveries naming the rule of another organization, or an endpoint
+	// other than the one of the rule, are not resent
+	for _, d := range []*influxdb.NotificationDelivery{
+		{OrgID: 1, RuleID: 7, EndpointID: 3, CheckID: 4, StatusCode: 500, Time: at},
+		{OrgID: 1, RuleID: 2, EndpointID: 9, CheckID: 4, StatusCode: 500, Time: at},
+	} {
+		require.NoError(t, store.RecordNotificationDelivery(ctx, d))
+		_, err = svc.ResendNotificationDelivery(ctx, d.ID)
+		assert.Equal(t, influxdb.EConflict, influxdb.ErrorCode(err))
+	}
+	assert.Len(t, n.notifications, 2)
+}
@@ -0,0 +1,204 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+)
+
+// testCheckName is the name of the check of test notifications.
+const testCheckName = "test"
+
+// testEvery is the interval of the rules that test endpoints.
+var testEvery = notification.Duration{Values: []ast.Duration{{Magnitude: 1, Unit: "h"}}}
+
+var _ influxdb.NotificationTestService = (*Tester)(nil)
+
+// Tester sends synthetic notifications with the notifier, the same way
+// failed deliveries are resent. The notification of a rule is a test status
+// that matches the first of its tag and status rules, it is formatted and
+// sent by the flux of the rule along with the message template the rule
+// references. Test notifications are attempted once, whatever the retry
+// policy of the rule. Endpoints are tested with a rule of their own type that
+// sends the message of the status as it is, on behalf of the caller.
+type Tester struct {
+	rules     influxdb.NotificationRuleStore
+	endpoints influxdb.NotificationEndpointService
+	templates influxdb.MessageTemplateService
+	notifier  Notifier
+	now       func() time.Time
+}
+
+// NewTester returns a notification test service. The message templates that
+// rules reference are found with the template service, which may be nil.
+func NewTester(rules influxdb.NotificationRuleStore, endpoints influxdb.NotificationEndpointService, templates influxdb.MessageTemplateService, notifier Notifier) *Tester {
+	return &Tester{
+		rules:     rules,
+		endpoints: endpoints,
+		templates: templates,
+		notifier:  notifier,
+		now:       time.Now,
+	}
+}
+
+// TestNotificationEndpoint sends a test notification to the endpoint.
+func (t *Tester) TestNotificationEndpoint(ctx context.Context, id influxdb.ID) (*influxdb.NotificationTestResult, error) {
+	e, err := t.endpoints.FindNotificationEndpointByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r, err := endpointRule(e, auth.GetUserID())
+	if err != nil {
+		return nil, err
+	}
+
+	return t.send(ctx, e, r, Notification{
+		RuleName:    testCheckName,
+		CheckName:   testCheckName,
+		Level:       "info",
+		Measurement: testCheckName,
+		Message:     fmt.Sprintf("Test notification of the %s endpoint", e.GetName()),
+		Time:        t.now().UTC(),
+	})
+}
+
+// TestNotificationRule sends a test notification, formatted by the rule, to
+// the endpoint of the rule.
+func (t *Tester) TestNotificationRule(ctx context.Context, id influxdb.ID) (*influxdb.NotificationTestResult, error) {
+	r, err := t.rules.FindNotificationRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	e, err := t.endpoints.FindNotificationEndpointByID(ctx, r.GetEndpointID())
+	if err != nil {
+		return nil, err
+	}
+	b, err := ruleBase(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.setMessageTemplate(ctx, b); err != nil {
+		return nil, err
+	}
+	b.Retry = &rule.RetryPolicy{MaxAttempts: 1}
+
+	res, err := t.send(ctx, e, r, Notification{
+		RuleName:    r.GetName(),
+		CheckName:   testCheckName,
+		Level:       testLevel(b.StatusRules),
+		Measurement: testCheckName,
+		Tags:        testTags(b.TagRules),
+		Message:     fmt.Sprintf("Test notification of the %s notification rule", r.GetName()),
+		Time:        t.now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.RuleID = &id
+	return res, nil
+}
+
+func (t *Tester) send(ctx context.Context, e influxdb.NotificationEndpoint, r influxdb.NotificationRule, n Notification) (*influxdb.NotificationTestResult, error) {
+	resp, err := t.notifier.Send(ctx, e, r, n)
+	if err != nil {
+		return nil, err
+	}
+	return &influxdb.NotificationTestResult{
+		EndpointID: e.GetID(),
+		Message:    resp.Message,
+		Sent:       resp.Sent(),
+		StatusCode: resp.StatusCode,
+	}, nil
+}
+
+// setMessageTemplate sets the message template the rule references, as the
+// notification rule service does before generating the task of the rule.
+func (t *Tester) setMessageTemplate(ctx context.Context, b *rule.Base) error {
+	if t.templates == nil || !b.MessageTemplateID.Valid() {
+		return nil
+	}
+	mt, err := t.templates.FindMessageTemplateByID(ctx, b.MessageTemplateID)
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return b.SetMessageTemplate(nil)
+	}
+	if err != nil {
+		return err
+	}
+	if mt.OrgID != b.OrgID {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "message template must belong to the organization of the notification rule",
+		}
+	}
+	return b.SetMessageTemplate(mt)
+}
+
+// endpointRule returns the rule that tests the endpoint, owned by the user.
+// Its templates are empty so that the message of the status is sent.
+func endpointRule(e influxdb.NotificationEndpoint, userID influxdb.ID) (influxdb.NotificationRule, error) {
+	b := rule.Base{
+		Name:        testCheckName,
+		EndpointID:  e.GetID(),
+		OrgID:       e.GetOrgID(),
+		OwnerID:     userID,
+		Every:       &testEvery,
+		StatusRules: []notification.StatusRule{{CurrentLevel: notification.Any}},
+		Retry:       &rule.RetryPolicy{MaxAttempts: 1},
+	}
+	switch e := e.(type) {
+	case *endpoint.Slack:
+		return &rule.Slack{Base: b}, nil
+	case *endpoint.PagerDuty:
+		return &rule.PagerDuty{Base: b}, nil
+	case *endpoint.HTTP:
+		return &rule.HTTP{Base: b}, nil
+	case *endpoint.SMTP:
+		// endpoints have no recipients, the test mail is sent to the sender.
+		return &rule.SMTP{Base: b, To: e.From, SubjectTemplate: "InfluxDB test notification"}, nil
+	case *endpoint.Teams:
+		return &rule.Teams{Base: b}, nil
+	case *endpoint.Telegram:
+		return &rule.Telegram{Base: b}, nil
+	case *endpoint.Opsgenie:
+		return &rule.Opsgenie{Base: b}, nil
+	}
+	return nil, &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  fmt.Sprintf("testing %s endpoints is not supported", e.Type()),
+	}
+}
+
+// testLevel returns the level of the first status rule, info when it matches any level.
+func testLevel(rs []notification.StatusRule) string {
+	if len(rs) == 0 {
+		return "info"
+	}
+	switch l := rs[0].CurrentLevel; l {
+	case notification.Ok, notification.Info, notification.Warn, notification.Critical:
+		return strings.ToLower(l.String())
+	}
+	return "info"
+}
+
+// testTags returns the tags that the equality tag rules match.
+func testTags(rs []notification.TagRule) []influxdb.Tag {
+	var tags []influxdb.Tag
+	for _, r := range rs {
+		if r.Operator == influxdb.Equal {
+			tags = append(tags, r.Tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,123 @@
+package delivery_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/delivery"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTester(t *testing.T) {
+	ctx := icontext.SetAuthorizer(context.Background(), &influxdb.Authorization{UserID: 6})
+	orgID := influxdb.ID(1)
+	endpoints := map[influxdb.ID]influxdb.NotificationEndpoint{
+		3: &endpoint.Slack{Base: endpoint.Base{ID: idPtr(3), OrgID: &orgID, Name: "ops"}, URL: "http://localhost:7777"},
+		4: &endpoint.SMTP{Base: endpoint.Base{ID: idPtr(4), OrgID: &orgID, Name: "mail"}, Host: "localhost", Port: 25, From: "influx@example.com"},
+	}
+	rules := map[influxdb.ID]influxdb.NotificationRule{
+		10: &rule.Slack{
+			Base: rule.Base{
+				ID: 10, OrgID: orgID, Name: "cpu", EndpointID: 3,
+				TagRules:    []notification.TagRule{{Tag: influxdb.Tag{Key: "host", Value: "a"}, Operator: influxdb.Equal}},
+				StatusRules: []notification.StatusRule{{CurrentLevel: notification.Warn}},
+				Retry:       &rule.RetryPolicy{MaxAttempts: 5},
+			},
+			Channel:         "#ops",
+			MessageTemplate: `${r._check_name} is ${r._level} on ${r.host}`,
+		},
+		11: &rule.SMTP{
+			Base:            rule.Base{ID: 11, OrgID: orgID, Name: "mail", EndpointID: 4, MessageTemplateID: 20},
+			To:              "ops@example.com",
+			SubjectTemplate: "${r._level} from ${r._notification_rule_name}",
+		},
+	}
+
+	ruleSvc := mock.NewNotificationRuleStore()
+	ruleSvc.FindNotificationRuleByIDF = func(ctx context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+		return rules[id], nil
+	}
+	endpointSvc := mock.NewNotificationEndpointService()
+	endpointSvc.FindNotificationEndpointByIDF = func(ctx context.Context, id influxdb.ID) (influxdb.NotificationEndpoint, error) {
+		return endpoints[id], nil
+	}
+	templates := mock.NewMessageTemplateService()
+	templates.FindMessageTemplateByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.MessageTemplate, error) {
+		return &influxdb.MessageTemplate{ID: id, OrgID: orgID, Template: "{{ check_name }}: {{ message }}"}, nil
+	}
+
+	t.Run("endpoint", func(t *testing.T) {
+		n := &notifier{codes: []int{200}}
+		tester := delivery.NewTester(ruleSvc, endpointSvc, templates, n)
+
+		res, err := tester.TestNotificationEndpoint(ctx, 4)
+		require.NoError(t, err)
+		assert.Equal(t, &influxdb.NotificationTestResult{
+			EndpointID: 4,
+			Message:    "Test notification of the mail endpoint",
+			Sent:       true,
+			StatusCode: 200,
+		}, res)
+		require.Len(t, n.notifications, 1)
+		assert.Equal(t, "info", n.notifications[0].Level)
+
+		r, ok := n.rules[0].(*rule.SMTP)
+		require.True(t, ok)
+		assert.Equal(t, "influx@example.com", r.To)
+		assert.Equal(t, influxdb.ID(4), r.EndpointID)
+		assert.Equal(t, influxdb.ID(6), r.OwnerID)
+		assert.Equal(t, &rule.RetryPolicy{MaxAttempts: 1}, r.Retry)
+	})
+
+	t.Run("endpoint without caller", func(t *testing.T) {
+		tester := delivery.NewTester(ruleSvc, endpointSvc, templates, &notifier{})
+
+		_, err := tester.TestNotificationEndpoint(context.Background(), 3)
+		assert.Error(t, err)
+	})
+
+	t.Run("rule", func(t *testing.T) {
+		n := &notifier{codes: []int{404}}
+		tester := delivery.NewTester(ruleSvc, endpointSvc, templates, n)
+
+		res, err := tester.TestNotificationRule(ctx, 10)
+		require.NoError(t, err)
+		ruleID := influxdb.ID(10)
+		assert.Equal(t, &influxdb.NotificationTestResult{
+			EndpointID: 3,
+			RuleID:     &ruleID,
+			Message:    "Test notification of the cpu notification rule",
+			StatusCode: 404,
+		}, res)
+		require.Len(t, n.notifications, 1)
+		assert.Equal(t, "warn", n.notifications[0].Level)
+		assert.Equal(t, []influxdb.Tag{{Key: "host", Value: "a"}}, n.notifications[0].Tags)
+
+		r := n.rules[0].(*rule.Slack)
+		assert.Equal(t, "#ops", r.Channel)
+		assert.Equal(t, &rule.RetryPolicy{MaxAttempts: 1}, r.Retry)
+	})
+
+	t.Run("rule with shared message template", func(t *testing.T) {
+		n := &notifier{codes: []int{200}}
+		tester := delivery.NewTester(ruleSvc, endpointSvc, templates, n)
+
+		_, err := tester.TestNotificationRule(ctx, 11)
+		require.NoError(t, err)
+		r := n.rules[0].(*rule.SMTP)
+		require.NotNil(t, r.SharedMessageTemplate)
+		assert.Equal(t, influxdb.ID(20), r.SharedMessageTemplate.ID)
+		assert.Equal(t, "ops@example.com", r.To)
+	})
+}
+
+func idPtr(id influxdb.ID) *influxdb.ID {
+	return &id
+}
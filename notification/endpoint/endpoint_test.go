@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/mail"
 	"net/url"
 	"testing"
 	"time"
@@ -196,6 +197,130 @@ func TestValidEndpoint(t *testing.T) {
 			},
 			err: nil,
 		},
+		{
+			name: "empty smtp host",
+			src: &endpoint.SMTP{
+				Base: goodBase,
+				Port: 25,
+				From: "alerts@example.com",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "empty smtp host",
+			},
+		},
+		{
+			name: "invalid smtp port",
+			src: &endpoint.SMTP{
+				Base: goodBase,
+				Host: "smtp.example.com",
+				Port: 70000,
+				From: "alerts@example.com",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid smtp port",
+			},
+		},
+		{
+			name: "smtp username without password",
+			src: &endpoint.SMTP{
+				Base:     goodBase,
+				Host:     "smtp.example.com",
+				Port:     587,
+				Username: influxdb.SecretField{Key: id1.String() + "-username"},
+				From:     "alerts@example.com",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "smtp username and password must be provided together",
+			},
+		},
+		{
+			name: "smtp credentials without starttls",
+			src: &endpoint.SMTP{
+				Base:     goodBase,
+				Host:     "smtp.example.com",
+				Port:     587,
+				Username: influxdb.SecretField{Key: id1.String() + "-username"},
+				Password: influxdb.SecretField{Key: id1.String() + "-password"},
+				From:     "alerts@example.com",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "smtp username and password require startTLS",
+			},
+		},
+		{
+			name: "invalid smtp from address",
+			src: &endpoint.SMTP{
+				Base: goodBase,
+				Host: "smtp.example.com",
+				Port: 25,
+				From: "alerts",
+			},
+			errFn: func(t *testing.T) error {
+				_, err := mail.ParseAddress("alerts")
+				return &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "invalid smtp from address",
+					Err:  err,
+				}
+			},
+		},
+		{
+			name: "valid smtp relay",
+			src: &endpoint.SMTP{
+				Base:     goodBase,
+				Host:     "smtp.example.com",
+				Port:     587,
+				StartTLS: true,
+				Username: influxdb.SecretField{Key: id1.String() + "-username"},
+				Password: influxdb.SecretField{Key: id1.String() + "-password"},
+				From:     "InfluxDB <alerts@example.com>",
+			},
+			err: nil,
+		},
+		{
+			name: "empty teams URL",
+			src: &endpoint.Teams{
+				Base: goodBase,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "empty teams URL",
+			},
+		},
+		{
+			name: "invalid teams URL",
+			src: &endpoint.Teams{
+				Base: goodBase,
+				URL:  "posts://er:{DEf1=ghi@:5432/db?ssl",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "teams endpoint URL is invalid: parse \"posts://er:{DEf1=ghi@:5432/db?ssl\": net/url: invalid userinfo",
+			},
+		},
+		{
+			name: "empty opsgenie API key",
+			src: &endpoint.Opsgenie{
+				Base: goodBase,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "empty opsgenie API key",
+			},
+		},
+		{
+			name: "valid opsgenie",
+			src: &endpoint.Opsgenie{
+				Base:   goodBase,
+				APIKey: influxdb.SecretField{Key: id1.String() + "-apiKey"},
+				Entity: "db",
+			},
+			err: nil,
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -305,6 +430,61 @@ func TestJSON(t *testing.T) {
 				Token: influxdb.SecretField{Key: "token-key-1"},
 			},
 		},
+		{
+			name: "simple smtp",
+			src: &endpoint.SMTP{
+				Base: endpoint.Base{
+					ID:     id1,
+					Name:   "nameSMTP",
+					OrgID:  id3,
+					Status: influxdb.Active,
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Host:     "smtp.example.com",
+				Port:     587,
+				StartTLS: true,
+				Username: influxdb.SecretField{Key: "username-key"},
+				Password: influxdb.SecretField{Key: "password-key"},
+				From:     "alerts@example.com",
+			},
+		},
+		{
+			name: "simple teams",
+			src: &endpoint.Teams{
+				Base: endpoint.Base{
+					ID:     id1,
+					Name:   "nameTeams",
+					OrgID:  id3,
+					Status: influxdb.Active,
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				URL:             "https://outlook.office.com/webhook/",
+				SecretURLSuffix: influxdb.SecretField{Key: "suffix-key"},
+			},
+		},
+		{
+			name: "simple opsgenie",
+			src: &endpoint.Opsgenie{
+				Base: endpoint.Base{
+					ID:     id1,
+					Name:   "nameOpsgenie",
+					OrgID:  id3,
+					Status: influxdb.Active,
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				APIKey: influxdb.SecretField{Key: "api-key"},
+				Entity: "db",
+			},
+		},
 	}
 	for _, c := range cases {
 		b, err := json.Marshal(c.src)
@@ -478,6 +658,79 @@ func TestBackFill(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "simple smtp",
+			src: &endpoint.SMTP{
+				Base: endpoint.Base{
+					ID:     id1,
+					Name:   "name1",
+					OrgID:  id3,
+					Status: influxdb.Active,
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Host: "smtp.example.com",
+				Port: 25,
+				Username: influxdb.SecretField{
+					Value: strPtr("username1"),
+				},
+				Password: influxdb.SecretField{
+					Value: strPtr("password1"),
+				},
+				From: "alerts@example.com",
+			},
+			target: &endpoint.SMTP{
+				Base: endpoint.Base{
+					ID:     id1,
+					Name:   "name1",
+					OrgID:  id3,
+					Status: influxdb.Active,
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Host: "smtp.example.com",
+				Port: 25,
+				Username: influxdb.SecretField{
+					Key:   id1.String() + "-username",
+					Value: strPtr("username1"),
+				},
+				Password: influxdb.SecretField{
+					Key:   id1.String() + "-password",
+					Value: strPtr("password1"),
+				},
+				From: "alerts@example.com",
+			},
+		},
+		{
+			name: "simple opsgenie",
+			src: &endpoint.Opsgenie{
+				Base: endpoint.Base{
+					ID:     id1,
+					Name:   "name1",
+					OrgID:  id3,
+					Status: influxdb.Active,
+				},
+				APIKey: influxdb.SecretField{
+					Value: strPtr("api-key1"),
+				},
+			},
+			target: &endpoint.Opsgenie{
+				Base: endpoint.Base{
+					ID:     id1,
+					Name:   "name1",
+					OrgID:  id3,
+					Status: influxdb.Active,
+				},
+				APIKey: influxdb.SecretField{
+					Key:   id1.String() + "-apiKey",
+					Value: strPtr("api-key1"),
+				},
+			},
+		},
 	}
 	for _, c := range cases {
 		c.src.BackfillSecretKeys()
@@ -605,6 +858,43 @@ func TestSecretFields(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "smtp without credentials",
+			src: &endpoint.SMTP{
+				Base: endpoint.Base{
+					ID:     id1,
+					Name:   "name1",
+					OrgID:  id3,
+					Status: influxdb.Active,
+				},
+				Host: "smtp.example.com",
+				Port: 25,
+				From: "alerts@example.com",
+			},
+			secrets: []influxdb.SecretField{},
+		},
+		{
+			name: "teams with secret URL suffix",
+			src: &endpoint.Teams{
+				Base: endpoint.Base{
+					ID:     id1,
+					Name:   "name1",
+					OrgID:  id3,
+					Status: influxdb.Active,
+				},
+				URL: "https://outlook.office.com/webhook/",
+				SecretURLSuffix: influxdb.SecretField{
+					Key:   id1.String() + "-secretURLSuffix",
+					Value: strPtr("suffix1"),
+				},
+			},
+			secrets: []influxdb.SecretField{
+				{
+					Key:   id1.String() + "-secretURLSuffix",
+					Value: strPtr("suffix1"),
+				},
+			},
+		},
 	}
 	for _, c := range cases {
 		secretFields := c.src.SecretFields()
@@ -0,0 +1,82 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.NotificationEndpoint = &Opsgenie{}
+
+const opsgenieAPIKeySuffix = "-apiKey"
+
+// Opsgenie is the notification endpoint config of Opsgenie.
+type Opsgenie struct {
+	Base
+	// URL is the alert API URL, it defaults to https://api.opsgenie.com/v2/alerts
+	// and only needs to be set for the EU instance or a proxy.
+	URL string `json:"url,omitempty"`
+	// APIKey is the key of the API integration, see https://docs.opsgenie.com/docs/api-integration
+	APIKey influxdb.SecretField `json:"apiKey"`
+	// Entity is an optional domain of the alerts, i.e. an application or server name.
+	Entity string `json:"entity,omitempty"`
+}
+
+// BackfillSecretKeys fill back the secret field key during the unmarshalling
+// if value of that secret field is not nil.
+func (s *Opsgenie) BackfillSecretKeys() {
+	if s.APIKey.Key == "" && s.APIKey.Value != nil {
+		s.APIKey.Key = s.idStr() + opsgenieAPIKeySuffix
+	}
+}
+
+// SecretFields return available secret fields.
+func (s Opsgenie) SecretFields() []influxdb.SecretField {
+	arr := []influxdb.SecretField{}
+	if s.APIKey.Key != "" {
+		arr = append(arr, s.APIKey)
+	}
+	return arr
+}
+
+// Valid returns error if some configuration is invalid
+func (s Opsgenie) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.APIKey.Key == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "empty opsgenie API key",
+		}
+	}
+	if s.URL != "" {
+		if _, err := url.Parse(s.URL); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("opsgenie endpoint URL is invalid: %s", err.Error()),
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implement json.Marshaler interface.
+func (s Opsgenie) MarshalJSON() ([]byte, error) {
+	type opsgenieAlias Opsgenie
+	return json.Marshal(
+		struct {
+			opsgenieAlias
+			Type string `json:"type"`
+		}{
+			opsgenieAlias: opsgenieAlias(s),
+			Type:          s.Type(),
+		})
+}
+
+// Type returns the type.
+func (s Opsgenie) Type() string {
+	return OpsgenieType
+}
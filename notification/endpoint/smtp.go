@@ -0,0 +1,133 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net"
+	"net/mail"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/smtp"
+)
+
+var _ influxdb.NotificationEndpoint = &SMTP{}
+
+const (
+	smtpUsernameSuffix = "-username"
+	smtpPasswordSuffix = "-password"
+)
+
+// SMTP is the notification endpoint config of an SMTP relay.
+type SMTP struct {
+	Base
+	// Host is the host name of the SMTP relay.
+	Host string `json:"host"`
+	// Port is the port of the SMTP relay, usually 25 or 587.
+	Port int `json:"port"`
+	// StartTLS upgrades the connection to the relay with STARTTLS.
+	StartTLS bool `json:"startTLS"`
+	// Username and Password are optional credentials for the relay, they
+	// require StartTLS unless the relay is on localhost.
+	Username influxdb.SecretField `json:"username"`
+	Password influxdb.SecretField `json:"password"`
+	// From is the sender address of the emails.
+	From string `json:"from"`
+}
+
+// BackfillSecretKeys fill back the secret field key during the unmarshalling
+// if value of that secret field is not nil.
+func (s *SMTP) BackfillSecretKeys() {
+	if s.Username.Key == "" && s.Username.Value != nil {
+		s.Username.Key = s.idStr() + smtpUsernameSuffix
+	}
+	if s.Password.Key == "" && s.Password.Value != nil {
+		s.Password.Key = s.idStr() + smtpPasswordSuffix
+	}
+}
+
+// SecretFields return available secret fields.
+func (s SMTP) SecretFields() []influxdb.SecretField {
+	arr := []influxdb.SecretField{}
+	if s.Username.Key != "" {
+		arr = append(arr, s.Username)
+	}
+	if s.Password.Key != "" {
+		arr = append(arr, s.Password)
+	}
+	return arr
+}
+
+// HasCredentials reports whether the relay requires authentication.
+func (s SMTP) HasCredentials() bool {
+	return s.Username.Key != ""
+}
+
+// URL returns the url used by notification rules to deliver through the relay.
+func (s SMTP) URL() string {
+	return smtp.URL(s.Host, s.Port, s.StartTLS)
+}
+
+// Valid returns error if some configuration is invalid
+func (s SMTP) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.Host == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "empty smtp host",
+		}
+	}
+	if s.Port <= 0 || s.Port > 65535 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid smtp port",
+		}
+	}
+	if (s.Username.Key == "") != (s.Password.Key == "") {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "smtp username and password must be provided together",
+		}
+	}
+	// credentials are only sent over encrypted connections, as net/smtp does
+	if s.HasCredentials() && !s.StartTLS && !isLocalhost(s.Host) {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "smtp username and password require startTLS",
+		}
+	}
+	if _, err := mail.ParseAddress(s.From); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid smtp from address",
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implement json.Marshaler interface.
+func (s SMTP) MarshalJSON() ([]byte, error) {
+	type smtpAlias SMTP
+	return json.Marshal(
+		struct {
+			smtpAlias
+			Type string `json:"type"`
+		}{
+			smtpAlias: smtpAlias(s),
+			Type:      s.Type(),
+		})
+}
+
+// Type returns the type.
+func (s SMTP) Type() string {
+	return SMTPType
+}
+
+func isLocalhost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
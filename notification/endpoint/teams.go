@@ -0,0 +1,79 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.NotificationEndpoint = &Teams{}
+
+const teamsSecretSuffix = "-secretURLSuffix"
+
+// Teams is the notification endpoint config of Microsoft Teams.
+type Teams struct {
+	Base
+	// URL is the incoming webhook URL of the channel, see
+	// https://docs.microsoft.com/en-us/microsoftteams/platform/webhooks-and-connectors/how-to/add-incoming-webhook
+	URL string `json:"url"`
+	// SecretURLSuffix is an optional secret that is appended to URL,
+	// so that the identifying part of the webhook is not stored in plain text.
+	SecretURLSuffix influxdb.SecretField `json:"secretURLSuffix"`
+}
+
+// BackfillSecretKeys fill back the secret field key during the unmarshalling
+// if value of that secret field is not nil.
+func (s *Teams) BackfillSecretKeys() {
+	if s.SecretURLSuffix.Key == "" && s.SecretURLSuffix.Value != nil {
+		s.SecretURLSuffix.Key = s.idStr() + teamsSecretSuffix
+	}
+}
+
+// SecretFields return available secret fields.
+func (s Teams) SecretFields() []influxdb.SecretField {
+	arr := []influxdb.SecretField{}
+	if s.SecretURLSuffix.Key != "" {
+		arr = append(arr, s.SecretURLSuffix)
+	}
+	return arr
+}
+
+// Valid returns error if some configuration is invalid
+func (s Teams) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.URL == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "empty teams URL",
+		}
+	}
+	if _, err := url.Parse(s.URL); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("teams endpoint URL is invalid: %s", err.Error()),
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implement json.Marshaler interface.
+func (s Teams) MarshalJSON() ([]byte, error) {
+	type teamsAlias Teams
+	return json.Marshal(
+		struct {
+			teamsAlias
+			Type string `json:"type"`
+		}{
+			teamsAlias: teamsAlias(s),
+			Type:       s.Type(),
+		})
+}
+
+// Type returns the type.
+func (s Teams) Type() string {
+	return TeamsType
+}
@@ -0,0 +1,30 @@
+package messagetemplate
+
+import (
+	"github.com/influxdata/influxdb/v2"
+)
+
+var (
+	// ErrMessageTemplateNotFound is used when the message template is not found.
+	ErrMessageTemplateNotFound = &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "message template not found",
+	}
+)
+
+// ErrInvalidMessageTemplateID is used when the ID of a message template cannot be encoded.
+func ErrInvalidMessageTemplateID(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "invalid message template ID",
+		Err:  err,
+	}
+}
+
+// ErrInternalService is used when the error comes from an internal system.
+func ErrInternalService(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Err:  err,
+	}
+}
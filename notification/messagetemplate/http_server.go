@@ -0,0 +1,235 @@
+package messagetemplate
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/influxdata/influxdb/v2"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/notification/template"
+	"go.uber.org/zap"
+)
+
+// PrefixMessageTemplates is the prefix of the message template API.
+const PrefixMessageTemplates = "/api/v2/messageTemplates"
+
+// Handler serves the message templates of notifications.
+type Handler struct {
+	chi.Router
+	api                *kithttp.API
+	log                *zap.Logger
+	messageTemplateSvc influxdb.MessageTemplateService
+}
+
+// NewHTTPHandler constructs a new http server for message templates.
+func NewHTTPHandler(log *zap.Logger, svc influxdb.MessageTemplateService) *Handler {
+	h := &Handler{
+		api:                kithttp.NewAPI(kithttp.WithLog(log)),
+		log:                log,
+		messageTemplateSvc: svc,
+	}
+
+	r := chi.NewRouter()
+	r.Use(
+		middleware.Recoverer,
+		middleware.RequestID,
+		middleware.RealIP,
+	)
+
+	r.Route("/", func(r chi.Router) {
+		r.Get("/", h.handleGetMessageTemplates)
+		r.Post("/", h.handlePostMessageTemplate)
+		r.Post("/preview", h.handlePostPreview)
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h.handleGetMessageTemplate)
+			r.Patch("/", h.handlePatchMessageTemplate)
+			r.Delete("/", h.handleDeleteMessageTemplate)
+			r.Post("/preview", h.handlePostMessageTemplatePreview)
+		})
+	})
+
+	h.Router = r
+	return h
+}
+
+// Prefix returns the prefix the handler is mounted at.
+func (h *Handler) Prefix() string {
+	return PrefixMessageTemplates
+}
+
+type messageTemplatesResponse struct {
+	MessageTemplates []*influxdb.MessageTemplate `json:"messageTemplates"`
+}
+
+// handleGetMessageTemplates is the HTTP handler for the GET /api/v2/messageTemplates route.
+// The orgID and name query parameters restrict the message templates to an
+// organization and a name.
+func (h *Handler) handleGetMessageTemplates(w http.ResponseWriter, r *http.Request) {
+	var filter influxdb.MessageTemplateFilter
+	q := r.URL.Query()
+	if orgID := q.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			h.api.Err(w, r, err)
+			return
+		}
+		filter.OrgID = id
+	}
+	if name := q.Get("name"); name != "" {
+		filter.Name = &name
+	}
+
+	ts, err := h.messageTemplateSvc.FindMessageTemplates(r.Context(), filter)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Message templates retrieved", zap.Int("count", len(ts)))
+
+	h.api.Respond(w, r, http.StatusOK, messageTemplatesResponse{MessageTemplates: ts})
+}
+
+// handlePostMessageTemplate is the HTTP handler for the POST /api/v2/messageTemplates route.
+func (h *Handler) handlePostMessageTemplate(w http.ResponseWriter, r *http.Request) {
+	var t influxdb.MessageTemplate
+	if err := h.api.DecodeJSON(r.Body, &t); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	if err := h.messageTemplateSvc.CreateMessageTemplate(r.Context(), &t); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Message template created", zap.String("id", t.ID.String()))
+
+	h.api.Respond(w, r, http.StatusCreated, t)
+}
+
+// handleGetMessageTemplate is the HTTP handler for the GET /api/v2/messageTemplates/:id route.
+func (h *Handler) handleGetMessageTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := influxdb.IDFromString(chi.URLParam(r, "id"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	t, err := h.messageTemplateSvc.FindMessageTemplateByID(r.Context(), *id)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	h.api.Respond(w, r, http.StatusOK, t)
+}
+
+// handlePatchMessageTemplate is the HTTP handler for the PATCH /api/v2/messageTemplates/:id route.
+func (h *Handler) handlePatchMessageTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := influxdb.IDFromString(chi.URLParam(r, "id"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	var upd influxdb.MessageTemplateUpdate
+	if err := h.api.DecodeJSON(r.Body, &upd); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	t, err := h.messageTemplateSvc.UpdateMessageTemplate(r.Context(), *id, upd)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Message template updated", zap.String("id", id.String()))
+
+	h.api.Respond(w, r, http.StatusOK, t)
+}
+
+// handleDeleteMessageTemplate is the HTTP handler for the DELETE /api/v2/messageTemplates/:id route.
+func (h *Handler) handleDeleteMessageTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := influxdb.IDFromString(chi.URLParam(r, "id"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	if err := h.messageTemplateSvc.DeleteMessageTemplate(r.Context(), *id); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Message template deleted", zap.String("id", id.String()))
+
+	h.api.Respond(w, r, http.StatusNoContent, nil)
+}
+
+type previewRequest struct {
+	Template   string          `json:"template"`
+	RunbookURL string          `json:"runbookURL"`
+	Status     template.Status `json:"status"`
+}
+
+type previewResponse struct {
+	Message string `json:"message"`
+}
+
+// handlePostPreview is the HTTP handler for the POST /api/v2/messageTemplates/preview route.
+// It renders a template that is not stored yet against the sample status of the request.
+func (h *Handler) handlePostPreview(w http.ResponseWriter, r *http.Request) {
+	var req previewRequest
+	if err := h.api.DecodeJSON(r.Body, &req); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	h.preview(w, r, req.Template, req.RunbookURL, req.Status)
+}
+
+// handlePostMessageTemplatePreview is the HTTP handler for the POST /api/v2/messageTemplates/:id/preview route.
+// It renders the message template against the sample status of the request.
+func (h *Handler) handlePostMessageTemplatePreview(w http.ResponseWriter, r *http.Request) {
+	id, err := influxdb.IDFromString(chi.URLParam(r, "id"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	var req previewRequest
+	if err := h.api.DecodeJSON(r.Body, &req); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	t, err := h.messageTemplateSvc.FindMessageTemplateByID(r.Context(), *id)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	h.preview(w, r, t.Template, t.RunbookURL, req.Status)
+}
+
+// preview renders the template against the status, a status without a time
+// is rendered at the current time.
+func (h *Handler) preview(w http.ResponseWriter, r *http.Request, text, runbookURL string, s template.Status) {
+	tmpl, err := template.Parse(text)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	if s.Time.IsZero() {
+		s.Time = time.Now().UTC()
+	}
+
+	msg, err := tmpl.Render(runbookURL, s)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	h.api.Respond(w, r, http.StatusOK, previewResponse{Message: msg})
+}
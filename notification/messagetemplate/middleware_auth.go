@@ -0,0 +1,94 @@
+package messagetemplate
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+)
+
+var _ influxdb.MessageTemplateService = (*AuthorizedService)(nil)
+
+// AuthorizedService restricts access to message templates. Message templates
+// are compiled into the checks and notification rules of their organization,
+// so they require the same permissions as its notification rules.
+type AuthorizedService struct {
+	s influxdb.MessageTemplateService
+}
+
+// NewAuthorizedService wraps a message template service with authorization checks.
+func NewAuthorizedService(s influxdb.MessageTemplateService) *AuthorizedService {
+	return &AuthorizedService{s: s}
+}
+
+// FindMessageTemplateByID checks that the authorizer may read the notification rules of the template organization.
+func (s *AuthorizedService) FindMessageTemplateByID(ctx context.Context, id influxdb.ID) (*influxdb.MessageTemplate, error) {
+	t, err := s.s.FindMessageTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := authorizer.AuthorizeOrgReadResource(ctx, influxdb.NotificationRuleResourceType, t.OrgID); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// FindMessageTemplates returns only the message templates of organizations whose notification rules the authorizer may read.
+func (s *AuthorizedService) FindMessageTemplates(ctx context.Context, filter influxdb.MessageTemplateFilter) ([]*influxdb.MessageTemplate, error) {
+	if filter.OrgID != nil {
+		if _, _, err := authorizer.AuthorizeOrgReadResource(ctx, influxdb.NotificationRuleResourceType, *filter.OrgID); err != nil {
+			return nil, err
+		}
+	}
+	ts, err := s.s.FindMessageTemplates(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	tts := ts[:0]
+	for _, t := range ts {
+		_, _, err := authorizer.AuthorizeOrgReadResource(ctx, influxdb.NotificationRuleResourceType, t.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		tts = append(tts, t)
+	}
+	return tts, nil
+}
+
+// CreateMessageTemplate checks that the authorizer may write the notification rules of the organization.
+func (s *AuthorizedService) CreateMessageTemplate(ctx context.Context, t *influxdb.MessageTemplate) error {
+	if _, _, err := authorizer.AuthorizeCreate(ctx, influxdb.NotificationRuleResourceType, t.OrgID); err != nil {
+		return err
+	}
+	return s.s.CreateMessageTemplate(ctx, t)
+}
+
+// UpdateMessageTemplate checks that the authorizer may write the notification rules of the template organization.
+func (s *AuthorizedService) UpdateMessageTemplate(ctx context.Context, id influxdb.ID, upd influxdb.MessageTemplateUpdate) (*influxdb.MessageTemplate, error) {
+	t, err := s.s.FindMessageTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := authorizer.AuthorizeOrgWriteResource(ctx, influxdb.NotificationRuleResourceType, t.OrgID); err != nil {
+		return nil, err
+	}
+	return s.s.UpdateMessageTemplate(ctx, id, upd)
+}
+
+// DeleteMessageTemplate checks that the authorizer may write the notification rules of the template organization.
+func (s *AuthorizedService) DeleteMessageTemplate(ctx context.Context, id influxdb.ID) error {
+	t, err := s.s.FindMessageTemplateByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, _, err := authorizer.AuthorizeOrgWriteResource(ctx, influxdb.NotificationRuleResourceType, t.OrgID); err != nil {
+		return err
+	}
+	return s.s.DeleteMessageTemplate(ctx, id)
+}
@@ -0,0 +1,207 @@
+package messagetemplate
+
+// The message template `Service` stores the message templates of every
+// organization in a single kv bucket keyed by the encoded template ID. The
+// templates are compiled into the Flux of the check and notification rule
+// tasks that reference them, these tasks are regenerated by the
+// SyncingService of this package.
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/notification/template"
+	"github.com/influxdata/influxdb/v2/snowflake"
+)
+
+var messageTemplateBucket = []byte("messagetemplatesv1")
+
+var _ influxdb.MessageTemplateService = (*Service)(nil)
+
+// Service is the kv backed implementation of influxdb.MessageTemplateService.
+type Service struct {
+	store       kv.Store
+	idGenerator influxdb.IDGenerator
+	now         func() time.Time
+}
+
+// NewService returns a message template service backed by the provided kv store.
+func NewService(st kv.Store) *Service {
+	return &Service{
+		store:       st,
+		idGenerator: snowflake.NewIDGenerator(),
+		now:         time.Now,
+	}
+}
+
+// FindMessageTemplateByID returns a single message template by ID.
+func (s *Service) FindMessageTemplateByID(ctx context.Context, id influxdb.ID) (*influxdb.MessageTemplate, error) {
+	key, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidMessageTemplateID(err)
+	}
+
+	var t *influxdb.MessageTemplate
+	err = s.store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(messageTemplateBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		t, err = getMessageTemplate(b, key)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// FindMessageTemplates returns the message templates matching the filter.
+func (s *Service) FindMessageTemplates(ctx context.Context, filter influxdb.MessageTemplateFilter) ([]*influxdb.MessageTemplate, error) {
+	ts := []*influxdb.MessageTemplate{}
+	err := s.store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(messageTemplateBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		cur, err := b.ForwardCursor(nil)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		defer cur.Close()
+
+		for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+			t := &influxdb.MessageTemplate{}
+			if err := json.Unmarshal(v, t); err != nil {
+				return ErrInternalService(err)
+			}
+			if filter.OrgID != nil && t.OrgID != *filter.OrgID {
+				continue
+			}
+			if filter.Name != nil && t.Name != *filter.Name {
+				continue
+			}
+			ts = append(ts, t)
+		}
+		return cur.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+
+// CreateMessageTemplate creates a new message template and sets t.ID with the new identifier.
+func (s *Service) CreateMessageTemplate(ctx context.Context, t *influxdb.MessageTemplate) error {
+	if err := valid(t); err != nil {
+		return err
+	}
+
+	t.ID = s.idGenerator.ID()
+	now := s.now().UTC()
+	t.CreatedAt = now
+	t.UpdatedAt = now
+
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		return putMessageTemplate(tx, t)
+	})
+}
+
+// UpdateMessageTemplate updates a single message template and returns the new message template.
+func (s *Service) UpdateMessageTemplate(ctx context.Context, id influxdb.ID, upd influxdb.MessageTemplateUpdate) (*influxdb.MessageTemplate, error) {
+	key, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidMessageTemplateID(err)
+	}
+
+	var t *influxdb.MessageTemplate
+	err = s.store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(messageTemplateBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		if t, err = getMessageTemplate(b, key); err != nil {
+			return err
+		}
+
+		upd.Apply(t)
+		if err := valid(t); err != nil {
+			return err
+		}
+		t.UpdatedAt = s.now().UTC()
+		return putMessageTemplate(tx, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// DeleteMessageTemplate removes a message template by ID.
+func (s *Service) DeleteMessageTemplate(ctx context.Context, id influxdb.ID) error {
+	key, err := id.Encode()
+	if err != nil {
+		return ErrInvalidMessageTemplateID(err)
+	}
+
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(messageTemplateBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		if _, err := getMessageTemplate(b, key); err != nil {
+			return err
+		}
+		if err := b.Delete(key); err != nil {
+			return ErrInternalService(err)
+		}
+		return nil
+	})
+}
+
+// valid validates the message template along with the syntax of its template.
+func valid(t *influxdb.MessageTemplate) error {
+	if err := t.Valid(); err != nil {
+		return err
+	}
+	_, err := template.Parse(t.Template)
+	return err
+}
+
+func getMessageTemplate(b kv.Bucket, key []byte) (*influxdb.MessageTemplate, error) {
+	v, err := b.Get(key)
+	if kv.IsNotFound(err) {
+		return nil, ErrMessageTemplateNotFound
+	}
+	if err != nil {
+		return nil, ErrInternalService(err)
+	}
+
+	t := &influxdb.MessageTemplate{}
+	if err := json.Unmarshal(v, t); err != nil {
+		return nil, ErrInternalService(err)
+	}
+	return t, nil
+}
+
+func putMessageTemplate(tx kv.Tx, t *influxdb.MessageTemplate) error {
+	key, err := t.ID.Encode()
+	if err != nil {
+		return ErrInvalidMessageTemplateID(err)
+	}
+	b, err := tx.Bucket(messageTemplateBucket)
+	if err != nil {
+		return ErrInternalService(err)
+	}
+	v, err := json.Marshal(t)
+	if err != nil {
+		return ErrInternalService(err)
+	}
+	if err := b.Put(key, v); err != nil {
+		return ErrInternalService(err)
+	}
+	return nil
+}
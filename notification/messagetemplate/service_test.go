@@ -0,0 +1,96 @@
+package messagetemplate_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kv/migration/all"
+	"github.com/influxdata/influxdb/v2/notification/messagetemplate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestService(t *testing.T) *messagetemplate.Service {
+	t.Helper()
+
+	store := inmem.NewKVStore()
+	if err := all.Up(context.Background(), zaptest.NewLogger(t), store); err != nil {
+		t.Fatal(err)
+	}
+	return messagetemplate.NewService(store)
+}
+
+type taskSyncer struct {
+	checks []influxdb.ID
+	rules  []influxdb.ID
+}
+
+func (s *taskSyncer) SyncCheckTasks(ctx context.Context, orgID influxdb.ID) error {
+	s.checks = append(s.checks, orgID)
+	return nil
+}
+
+func (s *taskSyncer) SyncNotificationRuleTasks(ctx context.Context, orgID influxdb.ID) error {
+	s.rules = append(s.rules, orgID)
+	return nil
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	syncer := &taskSyncer{}
+	svc := messagetemplate.NewSyncingService(newTestService(t), syncer, syncer)
+
+	err := svc.CreateMessageTemplate(ctx, &influxdb.MessageTemplate{OrgID: 1, Name: "cpu", Template: "{{ check_name"})
+	assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+
+	cpu := &influxdb.MessageTemplate{
+		OrgID:      1,
+		Name:       "cpu",
+		Template:   "{{ check_name }} is {{ level }} on {{ tags.host }}",
+		RunbookURL: "https://runbooks.example.com/cpu",
+	}
+	require.NoError(t, svc.CreateMessageTemplate(ctx, cpu))
+	assert.True(t, cpu.ID.Valid())
+	disk := &influxdb.MessageTemplate{OrgID: 2, Name: "disk", Template: "{{ check_name }} is {{ level }}"}
+	require.NoError(t, svc.CreateMessageTemplate(ctx, disk))
+	assert.Empty(t, syncer.checks)
+	assert.Empty(t, syncer.rules)
+
+	tmpl, err := svc.FindMessageTemplateByID(ctx, cpu.ID)
+	require.NoError(t, err)
+	assert.Equal(t, cpu.RunbookURL, tmpl.RunbookURL)
+
+	orgID := influxdb.ID(1)
+	ts, err := svc.FindMessageTemplates(ctx, influxdb.MessageTemplateFilter{OrgID: &orgID})
+	require.NoError(t, err)
+	require.Len(t, ts, 1)
+	assert.Equal(t, cpu.ID, ts[0].ID)
+
+	name := "disk"
+	ts, err = svc.FindMessageTemplates(ctx, influxdb.MessageTemplateFilter{Name: &name})
+	require.NoError(t, err)
+	require.Len(t, ts, 1)
+	assert.Equal(t, disk.ID, ts[0].ID)
+
+	text := "{{ check_name }} is {{ level }} on {{ tags.host }}: {{ runbook }}"
+	tmpl, err = svc.UpdateMessageTemplate(ctx, cpu.ID, influxdb.MessageTemplateUpdate{Template: &text})
+	require.NoError(t, err)
+	assert.Equal(t, text, tmpl.Template)
+	assert.Equal(t, []influxdb.ID{1}, syncer.checks)
+	assert.Equal(t, []influxdb.ID{1}, syncer.rules)
+
+	invalid := "{{ tags.host-name }}"
+	_, err = svc.UpdateMessageTemplate(ctx, cpu.ID, influxdb.MessageTemplateUpdate{Template: &invalid})
+	assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+
+	require.NoError(t, svc.DeleteMessageTemplate(ctx, disk.ID))
+	assert.Equal(t, []influxdb.ID{1, 2}, syncer.checks)
+	assert.Equal(t, []influxdb.ID{1, 2}, syncer.rules)
+	_, err = svc.FindMessageTemplateByID(ctx, disk.ID)
+	assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+	err = svc.DeleteMessageTemplate(ctx, disk.ID)
+	assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+}
@@ -0,0 +1,74 @@
+package messagetemplate
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+// CheckSyncer regenerates the check tasks of an organization.
+type CheckSyncer interface {
+	// SyncCheckTasks regenerates the tasks of the checks of the
+	// organization with their current message templates.
+	SyncCheckTasks(ctx context.Context, orgID influxdb.ID) error
+}
+
+// RuleSyncer regenerates the notification rule tasks of an organization.
+type RuleSyncer interface {
+	// SyncNotificationRuleTasks regenerates the tasks of the notification
+	// rules of the organization with their current message templates.
+	SyncNotificationRuleTasks(ctx context.Context, orgID influxdb.ID) error
+}
+
+var _ influxdb.MessageTemplateService = (*SyncingService)(nil)
+
+// SyncingService regenerates the check and notification rule tasks of an
+// organization whenever one of its message templates changes, the templates
+// are compiled into the Flux of the tasks that reference them.
+type SyncingService struct {
+	influxdb.MessageTemplateService
+	checks CheckSyncer
+	rules  RuleSyncer
+}
+
+// NewSyncingService wraps a message template service so that changes are applied to the check and notification rule tasks.
+func NewSyncingService(s influxdb.MessageTemplateService, checks CheckSyncer, rules RuleSyncer) *SyncingService {
+	return &SyncingService{
+		MessageTemplateService: s,
+		checks:                 checks,
+		rules:                  rules,
+	}
+}
+
+// UpdateMessageTemplate updates the message template and regenerates the tasks of its organization.
+func (s *SyncingService) UpdateMessageTemplate(ctx context.Context, id influxdb.ID, upd influxdb.MessageTemplateUpdate) (*influxdb.MessageTemplate, error) {
+	t, err := s.MessageTemplateService.UpdateMessageTemplate(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sync(ctx, t.OrgID); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// DeleteMessageTemplate removes the message template and regenerates the
+// tasks of its organization, the checks and rules that referenced it fall
+// back to their own message templates.
+func (s *SyncingService) DeleteMessageTemplate(ctx context.Context, id influxdb.ID) error {
+	t, err := s.MessageTemplateService.FindMessageTemplateByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.MessageTemplateService.DeleteMessageTemplate(ctx, id); err != nil {
+		return err
+	}
+	return s.sync(ctx, t.OrgID)
+}
+
+func (s *SyncingService) sync(ctx context.Context, orgID influxdb.ID) error {
+	if err := s.checks.SyncCheckTasks(ctx, orgID); err != nil {
+		return err
+	}
+	return s.rules.SyncNotificationRuleTasks(ctx, orgID)
+}
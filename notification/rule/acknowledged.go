@@ -0,0 +1,102 @@
+package rule
+
+import (
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// SetAlerts sets the unresolved alerts of the organization of the rule. They
+// are not stored with the rule, they are compiled into its flux when the rule
+// suppresses acknowledged alerts or escalates open alerts.
+func (b *Base) SetAlerts(as []*influxdb.Alert) {
+	b.Alerts = as
+}
+
+// CompilesAlert returns whether the alert may be compiled into the flux of
+// the rule, that is the rule suppresses acknowledged alerts or escalates open
+// alerts and its tag rules match the tags of the series of the alert. Tag
+// rules on columns that alerts do not keep are assumed to match.
+func (b *Base) CompilesAlert(a *influxdb.Alert) bool {
+	if a.OrgID != b.OrgID || (!b.SuppressAcknowledged && len(b.Escalation) == 0) {
+		return false
+	}
+
+	tags := make(map[string]string, len(a.Tags))
+	for _, t := range a.Tags {
+		tags[t.Key] = t.Value
+	}
+	for _, tr := range b.TagRules {
+		v, ok := tags[tr.Key]
+		if !ok && strings.HasPrefix(tr.Key, "_") {
+			continue
+		}
+		if !matchValue(influxdb.TagRule(tr), v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Base) suppressedAlerts() []*influxdb.Alert {
+	if !b.SuppressAcknowledged {
+		return nil
+	}
+	var as []*influxdb.Alert
+	for _, a := range b.Alerts {
+		if a.State == influxdb.AlertAcknowledged {
+			as = append(as, a)
+		}
+	}
+	return as
+}
+
+// generateResolved generates the statuses of the series that returned to ok.
+func (b *Base) generateResolved() (ast.Statement, *ast.Identifier) {
+	pipe := flux.Pipe(
+		flux.Identifier("statuses"),
+		flux.Call(
+			flux.Member("monitor", "stateChanges"),
+			flux.Object(
+				flux.Property("toLevel", flux.String("ok")),
+			),
+		),
+	)
+	return flux.DefineVariable("resolved", pipe), flux.Identifier("resolved")
+}
+
+// generateAcknowledged generates the test of the statuses of the series of
+// acknowledged alerts and the filter that suppresses them. Statuses that
+// resolve an alert are not suppressed.
+func (b *Base) generateAcknowledged(as []*influxdb.Alert) (ast.Statement, *ast.CallExpression) {
+	var test ast.Expression
+	for _, a := range as {
+		var series ast.Expression = flux.Equal(flux.Member("r", "_check_id"), flux.String(a.CheckID.String()))
+		for _, t := range a.Tags {
+			series = flux.And(series, flux.Equal(flux.Member("r", t.Key), flux.String(t.Value)))
+		}
+		if test == nil {
+			test = series
+		} else {
+			test = flux.Or(test, series)
+		}
+	}
+
+	notAcknowledged := flux.Or(
+		flux.Equal(flux.Member("r", "_level"), flux.String("ok")),
+		&ast.UnaryExpression{
+			Operator: ast.NotOperator,
+			Argument: flux.Call(
+				flux.Identifier("acknowledged"),
+				flux.Object(flux.Property("r", flux.Identifier("r"))),
+			),
+		},
+	)
+	filter := flux.Call(
+		flux.Identifier("filter"),
+		flux.Object(flux.Property("fn", flux.Function(flux.FunctionParams("r"), notAcknowledged))),
+	)
+	return flux.DefineVariable("acknowledged", flux.Function(flux.FunctionParams("r"), test)), filter
+}
@@ -0,0 +1,127 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+)
+
+func TestSlack_GenerateFlux_acknowledged(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+slack_endpoint = slack["endpoint"](url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+resolved = statuses
+	|> monitor["stateChanges"](toLevel: "ok")
+acknowledged = (r) =>
+	(r["_check_id"] == "000000000000000a" and r["host"] == "a" or r["_check_id"] == "000000000000000b" and r["host"] == "b" and r["region"] == "eu")
+all_statuses = union(tables: [crit, resolved])
+	|> sort(columns: ["_time"])
+	|> filter(fn: (r) =>
+		(r["_time"] >= experimental["subDuration"](from: now(), d: 1h)))
+	|> filter(fn: (r) =>
+		(r["_level"] == "ok" or not acknowledged(r: r)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "bar", text: "blah", color: if r["_level"] == "crit" then "danger" else if r["_level"] == "warn" then "warning" else "good"})))`
+
+	s := &rule.Slack{
+		Channel:         "bar",
+		MessageTemplate: "blah",
+		Base: rule.Base{
+			ID:         1,
+			EndpointID: 2,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+			NotifyResolved:       true,
+			SuppressAcknowledged: true,
+		},
+	}
+	s.SetAlerts([]*influxdb.Alert{
+		{
+			CheckID: 10,
+			State:   influxdb.AlertAcknowledged,
+			Tags:    []influxdb.Tag{{Key: "host", Value: "a"}},
+		},
+		{
+			CheckID: 11,
+			State:   influxdb.AlertAcknowledged,
+			Tags:    []influxdb.Tag{{Key: "host", Value: "b"}, {Key: "region", Value: "eu"}},
+		},
+		{
+			// resolved alerts are not suppressed.
+			CheckID: 12,
+			State:   influxdb.AlertResolved,
+		},
+	})
+
+	e := &endpoint.Slack{
+		Base: endpoint.Base{
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
+
+func TestBase_CompilesAlert(t *testing.T) {
+	a := &influxdb.Alert{OrgID: 1, CheckID: 10, Tags: []influxdb.Tag{{Key: "host", Value: "a"}}}
+
+	b := rule.Base{OrgID: 1}
+	if b.CompilesAlert(a) {
+		t.Error("a rule that neither suppresses nor escalates alerts compiled the alert")
+	}
+
+	b.SuppressAcknowledged = true
+	b.TagRules = []notification.TagRule{
+		{Tag: influxdb.Tag{Key: "host", Value: "a"}, Operator: influxdb.Equal},
+		{Tag: influxdb.Tag{Key: "_check_name", Value: "cpu"}, Operator: influxdb.Equal},
+	}
+	if !b.CompilesAlert(a) {
+		t.Error("a rule whose tag rules match the alert did not compile the alert")
+	}
+
+	b.TagRules[0].Value = "b"
+	if b.CompilesAlert(a) {
+		t.Error("a rule whose tag rules do not match the alert compiled the alert")
+	}
+
+	b.TagRules = nil
+	b.OrgID = 2
+	if b.CompilesAlert(a) {
+		t.Error("a rule of another organization compiled the alert")
+	}
+}
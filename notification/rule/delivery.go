@@ -0,0 +1,169 @@
+package rule
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+const (
+	// MaxDeliveryAttempts is the largest number of attempts of a retry policy.
+	MaxDeliveryAttempts = 5
+
+	// MaxDeliveryBackoff is the largest delay before the first retry of a retry policy.
+	MaxDeliveryBackoff = time.Minute
+)
+
+// RetryPolicy retries the notifications of a rule that fail with a transient
+// error, a 429 or 5xx response, after a backoff that doubles with every retry.
+// Errors that fail the request itself, i.e. an unreachable host, fail the task
+// run as they do without a retry policy. The retries of a notification hold up
+// the run, so their delays must add up to no more than the every of the rule.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts to deliver a notification, the first one included.
+	MaxAttempts int `json:"maxAttempts"`
+	// Backoff is the delay before the first retry.
+	Backoff notification.Duration `json:"backoff"`
+}
+
+func (p RetryPolicy) valid(every *notification.Duration) error {
+	if p.MaxAttempts < 1 || p.MaxAttempts > MaxDeliveryAttempts {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "retry maxAttempts must be between 1 and " + strconv.Itoa(MaxDeliveryAttempts),
+		}
+	}
+	if p.MaxAttempts > 1 && p.Backoff.TimeDuration() <= 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "retry backoff must be larger than 0",
+		}
+	}
+	if p.Backoff.TimeDuration() > MaxDeliveryBackoff {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "retry backoff must not be larger than " + MaxDeliveryBackoff.String(),
+		}
+	}
+	if every != nil && p.delay() > every.TimeDuration() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "retry backoffs must not add up to more than the interval",
+		}
+	}
+	return nil
+}
+
+// delay returns the total delay of the retries of a notification.
+func (p RetryPolicy) delay() time.Duration {
+	if p.MaxAttempts < 2 {
+		return 0
+	}
+	return p.Backoff.TimeDuration() * time.Duration(1<<uint(p.MaxAttempts-1)-1)
+}
+
+// backoff returns the delay before the retry, the first retry is 1.
+func (p RetryPolicy) backoff(retry int) *ast.DurationLiteral {
+	d := &ast.DurationLiteral{}
+	for _, v := range p.Backoff.Values {
+		v.Magnitude <<= uint(retry - 1)
+		d.Values = append(d.Values, v)
+	}
+	return d
+}
+
+// generateDeliveryEndpoint generates the endpoint of a rule with a retry
+// policy. Unlike the endpoints of the flux packages, that only record whether
+// a notification was sent, it delivers the notifications with send, a
+// function of the status r and the result obj of mapFn that returns the
+// status code of the response. Every notification records the status code,
+// attempts, latency and error of its delivery along with its message, so
+// that failed deliveries can be resent. The calls are applied to the
+// statuses before they are delivered.
+func (b *Base) generateDeliveryEndpoint(name string, send *ast.FunctionExpression, message ast.Expression, calls ...*ast.CallExpression) ast.Statement {
+	p := b.Retry
+	retry := func(code ast.Expression) ast.Expression {
+		return flux.Call(flux.Identifier("retry"), flux.Object(flux.Property("code", code)))
+	}
+	code := func(attempt int) ast.Expression {
+		return flux.Identifier("code_" + strconv.Itoa(attempt))
+	}
+	sendObj := func(obj ast.Expression) ast.Expression {
+		return flux.Call(flux.Identifier("send"), flux.Object(
+			flux.Property("r", flux.Identifier("r")),
+			flux.Property("obj", obj),
+		))
+	}
+
+	retryable := flux.Or(
+		flux.Equal(flux.Identifier("code"), flux.Integer(429)),
+		&ast.BinaryExpression{
+			Operator: ast.GreaterThanEqualOperator,
+			Left:     flux.Identifier("code"),
+			Right:    flux.Integer(500),
+		},
+	)
+	stmts := []ast.Statement{
+		flux.DefineVariable("send", send),
+		flux.DefineVariable("retry", flux.Function(flux.FunctionParams("code"), retryable)),
+		flux.DefineVariable("obj", flux.Call(flux.Identifier("mapFn"), flux.Object(flux.Property("r", flux.Identifier("r"))))),
+		flux.DefineVariable("start", flux.Call(flux.Member("system", "time"), flux.Object())),
+		flux.DefineVariable("code_1", sendObj(flux.Identifier("obj"))),
+	}
+	var attempts ast.Expression = flux.Integer(1)
+	for i := 2; i <= p.MaxAttempts; i++ {
+		delayed := flux.Call(flux.Identifier("sleep"), flux.Object(
+			flux.Property("v", flux.Identifier("obj")),
+			flux.Property("duration", p.backoff(i-1)),
+		))
+		stmts = append(stmts, flux.DefineVariable("code_"+strconv.Itoa(i), flux.If(retry(code(i-1)), sendObj(delayed), code(i-1))))
+		attempts = flux.If(retry(code(i-1)), flux.Integer(int64(i)), attempts)
+	}
+	stmts = append(stmts, flux.DefineVariable("stop", flux.Call(flux.Member("system", "time"), flux.Object())))
+
+	last := code(p.MaxAttempts)
+	sent := flux.Equal(flux.Integer(2), &ast.BinaryExpression{
+		Operator: ast.DivisionOperator,
+		Left:     last,
+		Right:    flux.Integer(100),
+	})
+	latency := &ast.BinaryExpression{
+		Operator: ast.DivisionOperator,
+		Left: flux.Subtract(
+			flux.Call(flux.Identifier("int"), flux.Object(flux.Property("v", flux.Identifier("stop")))),
+			flux.Call(flux.Identifier("int"), flux.Object(flux.Property("v", flux.Identifier("start")))),
+		),
+		Right: flux.Integer(1000000),
+	}
+	deliveryError := flux.If(
+		sent,
+		flux.String(""),
+		flux.Add(flux.String("HTTP status "), flux.Call(flux.Identifier("string"), flux.Object(flux.Property("v", last)))),
+	)
+	stmts = append(stmts, &ast.ReturnStatement{
+		Argument: flux.ObjectWith("r",
+			flux.Property("_sent", flux.Call(flux.Identifier("string"), flux.Object(flux.Property("v", sent)))),
+			flux.Property("_status_code", last),
+			flux.Property("_attempts", attempts),
+			flux.Property("_latency_ms", latency),
+			flux.Property("_error", deliveryError),
+			flux.Property("_notification_message", message),
+		),
+	})
+
+	deliver := flux.FuncBlock(flux.FunctionParams("r"), stmts...)
+	calls = append(calls, flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", deliver))))
+	endpoint := flux.Function(flux.FunctionParams("mapFn"), &ast.FunctionExpression{
+		Params: []*ast.Property{{
+			Key:   &ast.Identifier{Name: "tables"},
+			Value: &ast.PipeLiteral{},
+		}},
+		Body: flux.Pipe(flux.Identifier("tables"), calls...),
+	})
+
+	return flux.DefineVariable(name, endpoint)
+}
@@ -0,0 +1,289 @@
+package rule
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// EscalationStep notifies an endpoint of the statuses of the rule whose series
+// has an alert that is still open, neither acknowledged nor resolved, once the
+// delay since the alert started has elapsed. Every step notifies an alert once.
+type EscalationStep struct {
+	Delay      notification.Duration `json:"delay"`
+	EndpointID influxdb.ID           `json:"endpointID"`
+	// To are the recipients of the emails of an SMTP endpoint.
+	To string `json:"to,omitempty"`
+}
+
+func (s EscalationStep) valid() error {
+	if s.Delay.TimeDuration() <= 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "escalation delay must be larger than 0",
+		}
+	}
+	if !s.EndpointID.Valid() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "escalation endpointID is invalid",
+		}
+	}
+	return nil
+}
+
+func (b Base) validEscalation() error {
+	for i, s := range b.Escalation {
+		if err := s.valid(); err != nil {
+			return err
+		}
+		if i > 0 && s.Delay.TimeDuration() <= b.Escalation[i-1].Delay.TimeDuration() {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "escalation delays must be increasing",
+			}
+		}
+	}
+	return nil
+}
+
+// EscalationEndpointIDs returns the IDs of the endpoints of the escalation steps.
+func (b *Base) EscalationEndpointIDs() []influxdb.ID {
+	ids := make([]influxdb.ID, 0, len(b.Escalation))
+	for _, s := range b.Escalation {
+		ids = append(ids, s.EndpointID)
+	}
+	return ids
+}
+
+// SetEscalationEndpoints sets the endpoints of the escalation steps, they are
+// compiled into the flux of the rule along with the open alerts.
+func (b *Base) SetEscalationEndpoints(es []influxdb.NotificationEndpoint) error {
+	b.EscalationEndpoints = make(map[influxdb.ID]influxdb.NotificationEndpoint, len(es))
+	for _, e := range es {
+		b.EscalationEndpoints[e.GetID()] = e
+	}
+	for _, s := range b.Escalation {
+		e, ok := b.EscalationEndpoints[s.EndpointID]
+		if !ok {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("escalation endpoint %s is not set", s.EndpointID),
+			}
+		}
+		switch e.(type) {
+		case *endpoint.Slack, *endpoint.PagerDuty, *endpoint.HTTP, *endpoint.Teams:
+		case *endpoint.SMTP:
+			if _, err := parseRecipients(s.To); err != nil {
+				return err
+			}
+		default:
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("escalation to %s endpoints is not supported", e.Type()),
+			}
+		}
+	}
+	return nil
+}
+
+// escalationImports returns the packages of the endpoints of the escalation steps.
+func (b *Base) escalationImports() []string {
+	var pkgs []string
+	for _, s := range b.Escalation {
+		switch e := b.EscalationEndpoints[s.EndpointID].(type) {
+		case *endpoint.Slack:
+			pkgs = append(pkgs, "slack")
+			if e.Token.Key != "" {
+				pkgs = append(pkgs, "influxdata/influxdb/secrets")
+			}
+		case *endpoint.PagerDuty:
+			pkgs = append(pkgs, "pagerduty", "influxdata/influxdb/secrets")
+		case *endpoint.HTTP:
+			pkgs = append(pkgs, "http", "json")
+			if e.AuthMethod == "bearer" || e.AuthMethod == "basic" {
+				pkgs = append(pkgs, "influxdata/influxdb/secrets")
+			}
+		case *endpoint.SMTP:
+			pkgs = append(pkgs, "http", "json")
+			if e.HasCredentials() {
+				pkgs = append(pkgs, "influxdata/influxdb/secrets")
+			}
+		case *endpoint.Teams:
+			pkgs = append(pkgs, "contrib/sranka/teams")
+			if e.SecretURLSuffix.Key != "" {
+				pkgs = append(pkgs, "influxdata/influxdb/secrets")
+			}
+		}
+	}
+	return pkgs
+}
+
+// escalatedAlerts returns the open alerts that the step notifies, the ones
+// that no later step of the rule notified yet.
+func (b *Base) escalatedAlerts(step int) []*influxdb.Alert {
+	var as []*influxdb.Alert
+	for _, a := range b.Alerts {
+		if a.State == influxdb.AlertOpen && a.EscalationStep(b.ID) < step {
+			as = append(as, a)
+		}
+	}
+	return as
+}
+
+// generateEscalations generates the notifications of the escalation steps
+// that have alerts to notify. The step is recorded in the _escalation_step
+// column of the notifications, from which the alerts learn they escalated.
+func (b *Base) generateEscalations() []ast.Statement {
+	var stmts []ast.Statement
+	for i, s := range b.Escalation {
+		step := i + 1
+		e, ok := b.EscalationEndpoints[s.EndpointID]
+		if !ok {
+			continue
+		}
+		as := b.escalatedAlerts(step)
+		if len(as) == 0 {
+			continue
+		}
+
+		name := "escalation_" + strconv.Itoa(step)
+		endpointDef, mapFn := generateEscalationEndpoint(e, s, b.message(flux.Member("r", "_message")))
+		stmts = append(stmts,
+			flux.DefineVariable(name+"_endpoint", endpointDef),
+			flux.DefineVariable(name, flux.Function(flux.FunctionParams("r"), generateEscalationTest(as, s))),
+		)
+
+		data := flux.ObjectWith("notification",
+			flux.Property("_notification_endpoint_id", flux.String(s.EndpointID.String())),
+			flux.Property("_notification_endpoint_name", flux.String(e.GetName())),
+			flux.Property("_escalation_step", flux.String(strconv.Itoa(step))),
+		)
+		stmts = append(stmts, flux.ExpressionStatement(flux.Pipe(
+			flux.Identifier("all_statuses"),
+			flux.Call(flux.Identifier("filter"), flux.Object(flux.Property("fn", flux.Identifier(name)))),
+			flux.Call(
+				flux.Member("monitor", "notify"),
+				flux.Object(
+					flux.Property("data", data),
+					flux.Property("endpoint", flux.Call(
+						flux.Identifier(name+"_endpoint"),
+						flux.Object(flux.Property("mapFn", mapFn)),
+					)),
+				),
+			),
+		)))
+	}
+	return stmts
+}
+
+// generateEscalationTest generates the test of the statuses of the series of
+// the alerts once the delay of the step elapsed. Statuses that resolve an
+// alert are not escalated.
+func generateEscalationTest(as []*influxdb.Alert, s EscalationStep) ast.Expression {
+	var test ast.Expression
+	for _, a := range as {
+		var series ast.Expression = flux.Equal(flux.Member("r", "_check_id"), flux.String(a.CheckID.String()))
+		for _, t := range a.Tags {
+			series = flux.And(series, flux.Equal(flux.Member("r", t.Key), flux.String(t.Value)))
+		}
+		series = flux.And(series, &ast.BinaryExpression{
+			Operator: ast.GreaterThanEqualOperator,
+			Left:     flux.Member("r", "_time"),
+			Right:    &ast.DateTimeLiteral{Value: a.StartedAt.Add(s.Delay.TimeDuration()).UTC()},
+		})
+		if test == nil {
+			test = series
+		} else {
+			test = flux.Or(test, series)
+		}
+	}
+	notOK := &ast.BinaryExpression{
+		Operator: ast.NotEqualOperator,
+		Left:     flux.Member("r", "_level"),
+		Right:    flux.String("ok"),
+	}
+	return flux.And(notOK, test)
+}
+
+// generateEscalationEndpoint generates the endpoint of the step and the
+// function that maps the statuses to the message.
+func generateEscalationEndpoint(e influxdb.NotificationEndpoint, s EscalationStep, message ast.Expression) (ast.Expression, *ast.FunctionExpression) {
+	title := flux.String("${r._check_name} is ${r._level}")
+
+	switch e := e.(type) {
+	case *endpoint.Slack:
+		props := []*ast.Property{}
+		if e.Token.Key != "" {
+			props = append(props, flux.Property("token", secret(e.Token.Key)))
+		}
+		if e.URL != "" {
+			props = append(props, flux.Property("url", flux.String(e.URL)))
+		}
+		return flux.Call(flux.Member("slack", "endpoint"), flux.Object(props...)),
+			flux.Function(flux.FunctionParams("r"), flux.Object(
+				flux.Property("channel", flux.String("")),
+				flux.Property("text", message),
+				flux.Property("color", slackColors()),
+			))
+	case *endpoint.PagerDuty:
+		return flux.Call(flux.Member("pagerduty", "endpoint"), flux.Object()),
+			flux.Function(flux.FunctionParams("r"), flux.Object(
+				flux.Property("routingKey", secret(e.RoutingKey.Key)),
+				flux.Property("client", flux.String("influxdata")),
+				flux.Property("clientURL", flux.String(e.ClientURL)),
+				flux.Property("class", flux.Member("r", "_check_name")),
+				flux.Property("group", flux.Member("r", "_source_measurement")),
+				flux.Property("severity", severityFromLevel()),
+				flux.Property("eventAction", actionFromLevel()),
+				flux.Property("source", flux.Member("notification", "_notification_rule_name")),
+				flux.Property("summary", message),
+				flux.Property("timestamp", generateTime()),
+			))
+	case *endpoint.HTTP:
+		body := flux.ObjectWith("r", flux.Property("_version", flux.Integer(1)))
+		return flux.Call(flux.Member("http", "endpoint"), flux.Object(flux.Property("url", flux.String(e.URL)))),
+			flux.Function(flux.FunctionParams("r"), flux.Object(
+				flux.Property("headers", httpHeaders(e)),
+				flux.Property("data", flux.Call(flux.Member("json", "encode"), flux.Object(flux.Property("v", body)))),
+			))
+	case *endpoint.SMTP:
+		to, _ := parseRecipients(s.To)
+		addrs := make([]ast.Expression, 0, len(to))
+		for _, addr := range to {
+			addrs = append(addrs, flux.String(addr))
+		}
+		body := flux.Object(
+			flux.Property("from", flux.String(e.From)),
+			flux.Property("to", flux.Array(addrs...)),
+			flux.Property("subject", title),
+			flux.Property("body", message),
+		)
+		return flux.Call(flux.Member("http", "endpoint"), flux.Object(flux.Property("url", flux.String(e.URL())))),
+			flux.Function(flux.FunctionParams("r"), flux.Object(
+				flux.Property("headers", smtpHeaders(e)),
+				flux.Property("data", flux.Call(flux.Member("json", "encode"), flux.Object(flux.Property("v", body)))),
+			))
+	case *endpoint.Teams:
+		var url ast.Expression = flux.String(e.URL)
+		if e.SecretURLSuffix.Key != "" {
+			url = flux.Add(url, secret(e.SecretURLSuffix.Key))
+		}
+		return flux.Call(flux.Member("teams", "endpoint"), flux.Object(flux.Property("url", url))),
+			flux.Function(flux.FunctionParams("r"), flux.Object(
+				flux.Property("title", title),
+				flux.Property("text", message),
+				flux.Property("summary", flux.String("")),
+			))
+	}
+	return nil, nil
+}
+
+func secret(key string) ast.Expression {
+	return flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(key))))
+}
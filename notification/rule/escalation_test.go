@@ -0,0 +1,182 @@
+package rule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+)
+
+func TestSlack_GenerateFlux_escalation(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+import "pagerduty"
+import "http"
+import "json"
+
+option task = {name: "foo", every: 1h}
+
+slack_endpoint = slack["endpoint"](url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] >= experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "bar", text: "blah", color: if r["_level"] == "crit" then "danger" else if r["_level"] == "warn" then "warning" else "good"})))
+
+escalation_1_endpoint = pagerduty["endpoint"]()
+escalation_1 = (r) =>
+	(r["_level"] != "ok" and (r["_check_id"] == "000000000000000b" and r["host"] == "b" and r["_time"] >= 2020-06-01T00:16:00Z))
+
+all_statuses
+	|> filter(fn: escalation_1)
+	|> monitor["notify"](data: {notification with _notification_endpoint_id: "0000000000000003", _notification_endpoint_name: "oncall", _escalation_step: "1"}, endpoint: escalation_1_endpoint(mapFn: (r) =>
+		({
+			routingKey: secrets["get"](key: "pagerduty_token"),
+			client: "influxdata",
+			clientURL: "http://localhost:7777/host/${r.host}",
+			class: r["_check_name"],
+			group: r["_source_measurement"],
+			severity: pagerduty["severityFromLevel"](level: r["_level"]),
+			eventAction: pagerduty["actionFromLevel"](level: r["_level"]),
+			source: notification["_notification_rule_name"],
+			summary: r["_message"],
+			timestamp: time(v: r["_source_timestamp"]),
+		})))
+
+escalation_2_endpoint = http["endpoint"](url: "smtp://smtp.example.com:587")
+escalation_2 = (r) =>
+	(r["_level"] != "ok" and (r["_check_id"] == "000000000000000a" and r["host"] == "a" and r["_time"] >= 2020-06-01T01:00:00Z or r["_check_id"] == "000000000000000b" and r["host"] == "b" and r["_time"] >= 2020-06-01T01:01:00Z))
+
+all_statuses
+	|> filter(fn: escalation_2)
+	|> monitor["notify"](data: {notification with _notification_endpoint_id: "0000000000000004", _notification_endpoint_name: "email", _escalation_step: "2"}, endpoint: escalation_2_endpoint(mapFn: (r) =>
+		({headers: {"Content-Type": "application/json"}, data: json["encode"](v: {
+			from: "influxdb@example.com",
+			to: ["manager@example.com"],
+			subject: "${r._check_name} is ${r._level}",
+			body: r["_message"],
+		})})))`
+
+	s := &rule.Slack{
+		Channel:         "bar",
+		MessageTemplate: "blah",
+		Base: rule.Base{
+			ID:         1,
+			EndpointID: 2,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+			Escalation: []rule.EscalationStep{
+				{Delay: *mustDuration("15m"), EndpointID: 3},
+				{Delay: *mustDuration("1h"), EndpointID: 4, To: "Manager <manager@example.com>"},
+			},
+		},
+	}
+	started := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	s.SetAlerts([]*influxdb.Alert{
+		{
+			// the first step already notified the alert.
+			CheckID:     10,
+			State:       influxdb.AlertOpen,
+			StartedAt:   started,
+			Tags:        []influxdb.Tag{{Key: "host", Value: "a"}},
+			Escalations: []influxdb.AlertEscalation{{RuleID: 1, Step: 1}},
+		},
+		{
+			CheckID:   11,
+			State:     influxdb.AlertOpen,
+			StartedAt: started.Add(time.Minute),
+			Tags:      []influxdb.Tag{{Key: "host", Value: "b"}},
+		},
+		{
+			// acknowledged alerts are not escalated.
+			CheckID:   12,
+			State:     influxdb.AlertAcknowledged,
+			StartedAt: started,
+		},
+	})
+	err := s.SetEscalationEndpoints([]influxdb.NotificationEndpoint{
+		&endpoint.PagerDuty{
+			Base: endpoint.Base{
+				ID:   idPtr(3),
+				Name: "oncall",
+			},
+			ClientURL:  "http://localhost:7777/host/${r.host}",
+			RoutingKey: influxdb.SecretField{Key: "pagerduty_token"},
+		},
+		&endpoint.SMTP{
+			Base: endpoint.Base{
+				ID:   idPtr(4),
+				Name: "email",
+			},
+			Host: "smtp.example.com",
+			Port: 587,
+			From: "influxdb@example.com",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := &endpoint.Slack{
+		Base: endpoint.Base{
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
+
+func TestBase_SetEscalationEndpoints(t *testing.T) {
+	b := &rule.Base{
+		Escalation: []rule.EscalationStep{
+			{Delay: *mustDuration("15m"), EndpointID: 3},
+		},
+	}
+
+	telegram := &endpoint.Telegram{Base: endpoint.Base{ID: idPtr(3)}}
+	if err := b.SetEscalationEndpoints([]influxdb.NotificationEndpoint{telegram}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected unsupported endpoint to be invalid, got %v", err)
+	}
+
+	b.Escalation[0].To = "not an address"
+	smtp := &endpoint.SMTP{Base: endpoint.Base{ID: idPtr(3)}}
+	if err := b.SetEscalationEndpoints([]influxdb.NotificationEndpoint{smtp}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected invalid recipients to be invalid, got %v", err)
+	}
+
+	if err := b.SetEscalationEndpoints(nil); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected missing endpoint to be invalid, got %v", err)
+	}
+}
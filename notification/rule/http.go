@@ -13,6 +13,12 @@ import (
 // HTTP is the notification rule config of http.
 type HTTP struct {
 	Base
+	// BodyTemplate is the request body sent to the endpoint. It is a flux string
+	// template with the notification record in scope as r, i.e.
+	// {"check": "${r._check_name}", "level": "${r._level}", "host": "${r.host}", "text": "${r._message}"}.
+	// When empty, the content template of the endpoint is used and when both are
+	// empty the record is sent as JSON.
+	BodyTemplate string `json:"bodyTemplate,omitempty"`
 }
 
 // GenerateFlux generates a flux script for the http notification rule.
@@ -50,7 +56,7 @@ func (s *HTTP) imports(e *endpoint.HTTP) []*ast.ImportDeclaration {
 		packages = append(packages, "influxdata/influxdb/secrets")
 	}
 
-	return flux.Imports(packages...)
+	return s.generateImports(packages...)
 }
 
 func (s *HTTP) generateFluxASTBody(e *endpoint.HTTP) []ast.Statement {
@@ -61,12 +67,26 @@ func (s *HTTP) generateFluxASTBody(e *endpoint.HTTP) []ast.Statement {
 	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
 	statements = append(statements, s.generateFluxASTStatuses())
 	statements = append(statements, s.generateLevelChecks()...)
-	statements = append(statements, s.generateFluxASTNotifyPipe())
+	statements = append(statements, s.generateFluxASTNotifyPipe(s.bodyTemplate(e)))
+	statements = append(statements, s.generateEscalations()...)
 
 	return statements
 }
 
+// bodyTemplate returns the template of the request body,
+// the template of the rule takes precedence over the one of the endpoint.
+func (s *HTTP) bodyTemplate(e *endpoint.HTTP) string {
+	if s.BodyTemplate != "" {
+		return s.BodyTemplate
+	}
+	return e.ContentTemplate
+}
+
 func (s *HTTP) generateHeaders(e *endpoint.HTTP) ast.Statement {
+	return flux.DefineVariable("headers", httpHeaders(e))
+}
+
+func httpHeaders(e *endpoint.HTTP) *ast.ObjectExpression {
 	props := []*ast.Property{
 		flux.Dictionary(
 			"Content-Type", flux.String("application/json"),
@@ -112,20 +132,45 @@ func (s *HTTP) generateHeaders(e *endpoint.HTTP) ast.Statement {
 		auth := flux.Dictionary("Authorization", basic)
 		props = append(props, auth)
 	}
-	return flux.DefineVariable("headers", flux.Object(props...))
+	return flux.Object(props...)
 }
 
 func (s *HTTP) generateFluxASTEndpoint(e *endpoint.HTTP) ast.Statement {
+	if s.Retry != nil {
+		return s.generateDeliveryEndpoint("endpoint", httpSend(e.URL), httpMessage())
+	}
 	call := flux.Call(flux.Member("http", "endpoint"), flux.Object(flux.Property("url", flux.String(e.URL))))
 
 	return flux.DefineVariable("endpoint", call)
 }
 
-func (s *HTTP) generateFluxASTNotifyPipe() ast.Statement {
+// httpSend posts the data of obj with its headers to the url.
+func httpSend(url string) *ast.FunctionExpression {
+	return flux.Function(flux.FunctionParams("r", "obj"), flux.Call(flux.Member("http", "post"), flux.Object(
+		flux.Property("url", flux.String(url)),
+		flux.Property("headers", flux.Member("obj", "headers")),
+		flux.Property("data", flux.Member("obj", "data")),
+	)))
+}
+
+// httpMessage is the request body of obj, the message of http deliveries.
+func httpMessage() ast.Expression {
+	return flux.Call(flux.Identifier("string"), flux.Object(flux.Property("v", flux.Member("obj", "data"))))
+}
+
+func (s *HTTP) generateFluxASTNotifyPipe(bodyTemplate string) ast.Statement {
 	endpointBody := flux.Call(
 		flux.Member("json", "encode"),
 		flux.Object(flux.Property("v", flux.Identifier("body"))),
 	)
+	body := s.generateBody()
+	if bodyTemplate != "" {
+		endpointBody = flux.Call(
+			flux.Identifier("bytes"),
+			flux.Object(flux.Property("v", flux.Identifier("body"))),
+		)
+		body = flux.DefineVariable("body", flux.String(bodyTemplate))
+	}
 	headers := flux.Property("headers", flux.Identifier("headers"))
 
 	endpointProps := []*ast.Property{
@@ -133,7 +178,7 @@ func (s *HTTP) generateFluxASTNotifyPipe() ast.Statement {
 		flux.Property("data", endpointBody),
 	}
 	endpointFn := flux.FuncBlock(flux.FunctionParams("r"),
-		s.generateBody(),
+		body,
 		&ast.ReturnStatement{
 			Argument: flux.Object(endpointProps...),
 		},
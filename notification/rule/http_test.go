@@ -297,3 +297,74 @@ all_statuses
 		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
 	}
 }
+
+func TestHTTP_GenerateFlux_bodyTemplate(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "experimental"
+
+option task = {name: "foo", every: 1h, offset: 1s}
+
+headers = {"Content-Type": "application/json"}
+endpoint = http["endpoint"](url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] >= experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: endpoint(mapFn: (r) => {
+		body = "{\"check\": \"${r._check_name}\", \"level\": \"${r._level}\", \"host\": \"${r.host}\", \"text\": \"${r._message}\"}"
+
+		return {headers: headers, data: bytes(v: body)}
+	}))`
+
+	s := &rule.HTTP{
+		Base: rule.Base{
+			ID:         1,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			Offset:     mustDuration("1s"),
+			EndpointID: 2,
+			TagRules:   []notification.TagRule{},
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+		},
+		BodyTemplate: `{"check": "${r._check_name}", "level": "${r._level}", "host": "${r.host}", "text": "${r._message}"}`,
+	}
+
+	id := influxdb.ID(2)
+	e := &endpoint.HTTP{
+		Base: endpoint.Base{
+			ID:   &id,
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+		// the template of the rule takes precedence
+		ContentTemplate: "${r._message}",
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
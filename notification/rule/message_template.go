@@ -0,0 +1,54 @@
+package rule
+
+import (
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+	"github.com/influxdata/influxdb/v2/notification/template"
+)
+
+// GetMessageTemplateID returns the ID of the message template the rule references.
+func (b *Base) GetMessageTemplateID() influxdb.ID {
+	return b.MessageTemplateID
+}
+
+// SetMessageTemplate sets the message template referenced by the rule, it is
+// compiled into the flux of the rule instead of its own message template.
+// A nil template falls back to the message template of the rule.
+func (b *Base) SetMessageTemplate(t *influxdb.MessageTemplate) error {
+	if t == nil {
+		b.SharedMessageTemplate = nil
+		return nil
+	}
+	if _, err := template.Parse(t.Template); err != nil {
+		return err
+	}
+	b.SharedMessageTemplate = t
+	return nil
+}
+
+// message returns the message of the statuses, the shared message template
+// when it is set or else the fallback. An empty fallback, of a rule whose
+// shared template was deleted, is replaced by the message of the check. The
+// runbook of the template defaults to the runbook link of the rule.
+func (b *Base) message(fallback ast.Expression) ast.Expression {
+	if b.SharedMessageTemplate == nil {
+		return fallbackMessage(fallback)
+	}
+	tmpl, err := template.Parse(b.SharedMessageTemplate.Template)
+	if err != nil {
+		return fallbackMessage(fallback)
+	}
+	runbook := b.SharedMessageTemplate.RunbookURL
+	if runbook == "" {
+		runbook = b.RunbookLink
+	}
+	return tmpl.Expression(runbook)
+}
+
+func fallbackMessage(fallback ast.Expression) ast.Expression {
+	if lit, ok := fallback.(*ast.StringLiteral); ok && lit.Value == "" {
+		return flux.Member("r", "_message")
+	}
+	return fallback
+}
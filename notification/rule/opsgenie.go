@@ -0,0 +1,185 @@
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+var opsgeniePriorities = map[string]bool{
+	"P1": true,
+	"P2": true,
+	"P3": true,
+	"P4": true,
+	"P5": true,
+}
+
+// Opsgenie is the notification rule config of Opsgenie.
+type Opsgenie struct {
+	Base
+	MessageTemplate     string `json:"messageTemplate"`
+	DescriptionTemplate string `json:"descriptionTemplate,omitempty"`
+	// Priority is one of P1 to P5. When empty, the priority follows
+	// the level of the status: crit is P1, warn is P3 and anything else is P5.
+	Priority string `json:"priority,omitempty"`
+	// Responders are teams or users that are notified,
+	// prefixed with "team:" or "user:", i.e. "team:ops".
+	Responders []string `json:"responders,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// GenerateFlux generates a flux script for the opsgenie notification rule.
+func (s *Opsgenie) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
+	opsgenieEndpoint, ok := e.(*endpoint.Opsgenie)
+	if !ok {
+		return "", fmt.Errorf("endpoint provided is a %s, not an Opsgenie endpoint", e.Type())
+	}
+	p, err := s.GenerateFluxAST(opsgenieEndpoint)
+	if err != nil {
+		return "", err
+	}
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST generates a flux AST for the opsgenie notification rule.
+func (s *Opsgenie) GenerateFluxAST(e *endpoint.Opsgenie) (*ast.Package, error) {
+	f := flux.File(
+		s.Name,
+		s.generateImports("influxdata/influxdb/monitor", "contrib/sranka/opsgenie", "influxdata/influxdb/secrets", "experimental"),
+		s.generateFluxASTBody(e),
+	)
+	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
+}
+
+func (s *Opsgenie) generateFluxASTBody(e *endpoint.Opsgenie) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, s.generateTaskOption())
+	statements = append(statements, s.generateFluxASTSecrets(e))
+	statements = append(statements, s.generateFluxASTEndpoint(e))
+	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
+	statements = append(statements, s.generateFluxASTStatuses())
+	statements = append(statements, s.generateLevelChecks()...)
+	statements = append(statements, s.generateFluxASTNotifyPipe())
+	statements = append(statements, s.generateEscalations()...)
+
+	return statements
+}
+
+func (s *Opsgenie) generateFluxASTSecrets(e *endpoint.Opsgenie) ast.Statement {
+	call := flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(e.APIKey.Key))))
+
+	return flux.DefineVariable("opsgenie_secret", call)
+}
+
+func (s *Opsgenie) generateFluxASTEndpoint(e *endpoint.Opsgenie) ast.Statement {
+	props := []*ast.Property{}
+	if e.URL != "" {
+		props = append(props, flux.Property("url", flux.String(e.URL)))
+	}
+	props = append(props, flux.Property("apiKey", flux.Identifier("opsgenie_secret")))
+	if e.Entity != "" {
+		props = append(props, flux.Property("entity", flux.String(e.Entity)))
+	}
+	if s.Retry != nil {
+		for _, k := range []string{"message", "alias", "description", "priority", "responders", "tags", "actions", "visibleTo", "details"} {
+			props = append(props, flux.Property(k, flux.Member("obj", k)))
+		}
+		send := flux.Function(flux.FunctionParams("r", "obj"), flux.Call(flux.Member("opsgenie", "sendAlert"), flux.Object(props...)))
+		return s.generateDeliveryEndpoint("opsgenie_endpoint", send, flux.Member("obj", "message"))
+	}
+	call := flux.Call(flux.Member("opsgenie", "endpoint"), flux.Object(props...))
+
+	return flux.DefineVariable("opsgenie_endpoint", call)
+}
+
+func (s *Opsgenie) generateFluxASTNotifyPipe() ast.Statement {
+	// the opsgenie endpoint reads every property of the mapFn result,
+	// so the ones the rule does not configure are set to their defaults.
+	endpointProps := []*ast.Property{}
+	endpointProps = append(endpointProps, flux.Property("message", s.message(flux.String(s.MessageTemplate))))
+	endpointProps = append(endpointProps, flux.Property("alias", flux.String("")))
+	endpointProps = append(endpointProps, flux.Property("description", flux.String(s.DescriptionTemplate)))
+	endpointProps = append(endpointProps, flux.Property("priority", s.generatePriority()))
+	endpointProps = append(endpointProps, flux.Property("responders", stringArray(s.Responders)))
+	endpointProps = append(endpointProps, flux.Property("tags", stringArray(s.Tags)))
+	endpointProps = append(endpointProps, flux.Property("actions", flux.Array()))
+	endpointProps = append(endpointProps, flux.Property("visibleTo", flux.Array()))
+	endpointProps = append(endpointProps, flux.Property("details", flux.String("{}")))
+	endpointFn := flux.Function(flux.FunctionParams("r"), flux.Object(endpointProps...))
+
+	props := []*ast.Property{}
+	props = append(props, flux.Property("data", flux.Identifier("notification")))
+	props = append(props, flux.Property("endpoint",
+		flux.Call(flux.Identifier("opsgenie_endpoint"), flux.Object(flux.Property("mapFn", endpointFn)))))
+
+	call := flux.Call(flux.Member("monitor", "notify"), flux.Object(props...))
+
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("all_statuses"), call))
+}
+
+func (s *Opsgenie) generatePriority() ast.Expression {
+	if s.Priority != "" {
+		return flux.String(s.Priority)
+	}
+	level := flux.Member("r", "_level")
+	return flux.If(
+		flux.Equal(level, flux.String("crit")),
+		flux.String("P1"),
+		flux.If(
+			flux.Equal(level, flux.String("warn")),
+			flux.String("P3"),
+			flux.String("P5"),
+		),
+	)
+}
+
+func stringArray(ss []string) *ast.ArrayExpression {
+	es := make([]ast.Expression, 0, len(ss))
+	for _, s := range ss {
+		es = append(es, flux.String(s))
+	}
+	return flux.Array(es...)
+}
+
+type opsgenieAlias Opsgenie
+
+// MarshalJSON implement json.Marshaler interface.
+func (s Opsgenie) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			opsgenieAlias
+			Type string `json:"type"`
+		}{
+			opsgenieAlias: opsgenieAlias(s),
+			Type:          s.Type(),
+		})
+}
+
+// Valid returns where the config is valid.
+func (s Opsgenie) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.MessageTemplate == "" && !s.MessageTemplateID.Valid() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Opsgenie MessageTemplate is invalid",
+		}
+	}
+	if s.Priority != "" && !opsgeniePriorities[s.Priority] {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("Opsgenie Priority %q is invalid, must be one of P1 to P5", s.Priority),
+		}
+	}
+	return nil
+}
+
+// Type returns the type of the rule config.
+func (s Opsgenie) Type() string {
+	return "opsgenie"
+}
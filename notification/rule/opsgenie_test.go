@@ -0,0 +1,249 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/andreyvit/diff"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	influxTesting "github.com/influxdata/influxdb/v2/testing"
+)
+
+var _ influxdb.NotificationRule = &rule.Opsgenie{}
+
+func TestOpsgenie_GenerateFlux(t *testing.T) {
+	base := rule.Base{
+		ID:         1,
+		EndpointID: 3,
+		Name:       "foo",
+		Every:      mustDuration("1h"),
+		StatusRules: []notification.StatusRule{
+			{
+				CurrentLevel: notification.Critical,
+			},
+		},
+		TagRules: []notification.TagRule{
+			{
+				Tag: influxdb.Tag{
+					Key:   "foo",
+					Value: "bar",
+				},
+				Operator: influxdb.Equal,
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		rule     *rule.Opsgenie
+		endpoint influxdb.NotificationEndpoint
+		script   string
+	}{
+		{
+			name: "incompatible with endpoint",
+			endpoint: &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:   idPtr(3),
+					Name: "foo",
+				},
+				URL: "http://whatever",
+			},
+			rule: &rule.Opsgenie{
+				Base:            base,
+				MessageTemplate: "blah",
+			},
+			script: "", //no script generater, because of incompatible endpoint
+		},
+		{
+			name: "priority from level",
+			endpoint: &endpoint.Opsgenie{
+				Base: endpoint.Base{
+					ID:   idPtr(3),
+					Name: "foo",
+				},
+				APIKey: influxdb.SecretField{Key: "3-apiKey"},
+			},
+			rule: &rule.Opsgenie{
+				Base:            base,
+				MessageTemplate: "blah",
+			},
+			script: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "contrib/sranka/opsgenie"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+opsgenie_secret = secrets["get"](key: "3-apiKey")
+opsgenie_endpoint = opsgenie["endpoint"](apiKey: opsgenie_secret)
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000003",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h, fn: (r) =>
+	(r["foo"] == "bar"))
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] >= experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: opsgenie_endpoint(mapFn: (r) =>
+		({
+			message: "blah",
+			alias: "",
+			description: "",
+			priority: if r["_level"] == "crit" then "P1" else if r["_level"] == "warn" then "P3" else "P5",
+			responders: [],
+			tags: [],
+			actions: [],
+			visibleTo: [],
+			details: "{}",
+		})))`,
+		},
+		{
+			name: "with url, entity, priority, responders and tags",
+			endpoint: &endpoint.Opsgenie{
+				Base: endpoint.Base{
+					ID:   idPtr(3),
+					Name: "foo",
+				},
+				URL:    "https://api.eu.opsgenie.com/v2/alerts",
+				APIKey: influxdb.SecretField{Key: "3-apiKey"},
+				Entity: "db",
+			},
+			rule: &rule.Opsgenie{
+				Base:                base,
+				MessageTemplate:     "blah",
+				DescriptionTemplate: "${r._message}",
+				Priority:            "P2",
+				Responders:          []string{"team:ops", "user:jo@example.com"},
+				Tags:                []string{"influxdb"},
+			},
+			script: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "contrib/sranka/opsgenie"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+opsgenie_secret = secrets["get"](key: "3-apiKey")
+opsgenie_endpoint = opsgenie["endpoint"](url: "https://api.eu.opsgenie.com/v2/alerts", apiKey: opsgenie_secret, entity: "db")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000003",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h, fn: (r) =>
+	(r["foo"] == "bar"))
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] >= experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: opsgenie_endpoint(mapFn: (r) =>
+		({
+			message: "blah",
+			alias: "",
+			description: "${r._message}",
+			priority: "P2",
+			responders: ["team:ops", "user:jo@example.com"],
+			tags: ["influxdb"],
+			actions: [],
+			visibleTo: [],
+			details: "{}",
+		})))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, err := tt.rule.GenerateFlux(tt.endpoint)
+			if err != nil {
+				if script != "" {
+					t.Errorf("Failed to generate flux: %v", err)
+				}
+				return
+			}
+
+			if got, want := script, tt.script; got != want {
+				t.Errorf("\n\nStrings do not match:\n\n%s", diff.LineDiff(got, want))
+			}
+		})
+	}
+}
+
+func TestOpsgenie_Valid(t *testing.T) {
+	base := rule.Base{
+		ID:         1,
+		EndpointID: 3,
+		OwnerID:    4,
+		OrgID:      5,
+		Name:       "foo",
+		Every:      mustDuration("1h"),
+		StatusRules: []notification.StatusRule{
+			{
+				CurrentLevel: notification.Critical,
+			},
+		},
+		TagRules: []notification.TagRule{},
+	}
+
+	cases := []struct {
+		name string
+		rule *rule.Opsgenie
+		err  error
+	}{
+		{
+			name: "valid template",
+			rule: &rule.Opsgenie{
+				Base:            base,
+				MessageTemplate: "blah",
+				Priority:        "P4",
+			},
+			err: nil,
+		},
+		{
+			name: "missing MessageTemplate",
+			rule: &rule.Opsgenie{
+				Base: base,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Opsgenie MessageTemplate is invalid",
+			},
+		},
+		{
+			name: "invalid priority",
+			rule: &rule.Opsgenie{
+				Base:            base,
+				MessageTemplate: "blah",
+				Priority:        "high",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `Opsgenie Priority "high" is invalid, must be one of P1 to P5`,
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.rule.Valid()
+			influxTesting.ErrorsEqual(t, got, c.err)
+		})
+	}
+}
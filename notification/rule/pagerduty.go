@@ -35,7 +35,7 @@ func (s PagerDuty) Valid() error {
 	if err := s.Base.valid(); err != nil {
 		return err
 	}
-	if s.MessageTemplate == "" {
+	if s.MessageTemplate == "" && !s.MessageTemplateID.Valid() {
 		return &influxdb.Error{
 			Code: influxdb.EInvalid,
 			Msg:  "pagerduty invalid message template",
@@ -66,7 +66,7 @@ func (s *PagerDuty) GenerateFlux(e influxdb.NotificationEndpoint) (string, error
 func (s *PagerDuty) GenerateFluxAST(e *endpoint.PagerDuty) (*ast.Package, error) {
 	f := flux.File(
 		s.Name,
-		flux.Imports("influxdata/influxdb/monitor", "pagerduty", "influxdata/influxdb/secrets", "experimental"),
+		s.generateImports("influxdata/influxdb/monitor", "pagerduty", "influxdata/influxdb/secrets", "experimental"),
 		s.generateFluxASTBody(e),
 	)
 	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
@@ -81,6 +81,7 @@ func (s *PagerDuty) generateFluxASTBody(e *endpoint.PagerDuty) []ast.Statement {
 	statements = append(statements, s.generateFluxASTStatuses())
 	statements = append(statements, s.generateLevelChecks()...)
 	statements = append(statements, s.generateFluxASTNotifyPipe(e.ClientURL))
+	statements = append(statements, s.generateEscalations()...)
 
 	return statements
 }
@@ -92,6 +93,15 @@ func (s *PagerDuty) generateFluxASTSecrets(e *endpoint.PagerDuty) ast.Statement
 }
 
 func (s *PagerDuty) generateFluxASTEndpoint(e *endpoint.PagerDuty) ast.Statement {
+	if s.Retry != nil {
+		props := []*ast.Property{flux.Property("dedupKey", flux.Member("r", "_pagerdutyDedupKey"))}
+		for _, k := range []string{"routingKey", "client", "clientURL", "class", "group", "severity", "eventAction", "source", "summary", "timestamp"} {
+			props = append(props, flux.Property(k, flux.Member("obj", k)))
+		}
+		send := flux.Function(flux.FunctionParams("r", "obj"), flux.Call(flux.Member("pagerduty", "sendEvent"), flux.Object(props...)))
+		dedupKey := flux.Call(flux.Member("pagerduty", "dedupKey"), flux.Object())
+		return s.generateDeliveryEndpoint("pagerduty_endpoint", send, flux.Member("obj", "summary"), dedupKey)
+	}
 	call := flux.Call(flux.Member("pagerduty", "endpoint"),
 		flux.Object(),
 	)
@@ -154,7 +164,7 @@ func (s *PagerDuty) generateFluxASTNotifyPipe(url string) ast.Statement {
 	// required
 	// string
 	// A brief text summary of the event, used to generate the summaries/titles of any associated alerts. The maximum permitted length of this property is 1024 characters.
-	endpointProps = append(endpointProps, flux.Property("summary", flux.Member("r", "_message")))
+	endpointProps = append(endpointProps, flux.Property("summary", s.message(flux.Member("r", "_message"))))
 
 	// timestamp:
 	// optional
@@ -17,6 +17,9 @@ var typeToRule = map[string](func() influxdb.NotificationRule){
 	"pagerduty": func() influxdb.NotificationRule { return &PagerDuty{} },
 	"http":      func() influxdb.NotificationRule { return &HTTP{} },
 	"telegram":  func() influxdb.NotificationRule { return &Telegram{} },
+	"smtp":      func() influxdb.NotificationRule { return &SMTP{} },
+	"teams":     func() influxdb.NotificationRule { return &Teams{} },
+	"opsgenie":  func() influxdb.NotificationRule { return &Opsgenie{} },
 }
 
 // UnmarshalJSON will convert
@@ -58,8 +61,29 @@ type Base struct {
 	RunbookLink string                    `json:"runbookLink"`
 	TagRules    []notification.TagRule    `json:"tagRules,omitempty"`
 	StatusRules []notification.StatusRule `json:"statusRules,omitempty"`
+	// SuppressAcknowledged suppresses the statuses of the series of acknowledged alerts.
+	SuppressAcknowledged bool `json:"suppressAcknowledged,omitempty"`
+	// NotifyResolved also notifies when a series returns to ok.
+	NotifyResolved bool `json:"notifyResolved,omitempty"`
+	// Escalation are the steps that notify additional endpoints of the
+	// statuses of the series whose alert is still open after their delay.
+	Escalation []EscalationStep `json:"escalation,omitempty"`
+	// MessageTemplateID references a message template of the organization
+	// that is used instead of the message template of the rule.
+	MessageTemplateID influxdb.ID `json:"messageTemplateID,omitempty"`
+	// Retry retries the notifications that fail with a transient error and
+	// records the delivery of every notification, see RetryPolicy.
+	Retry *RetryPolicy `json:"retry,omitempty"`
 	*influxdb.Limit
 	influxdb.CRUDLog
+
+	// Silences, Alerts, EscalationEndpoints and SharedMessageTemplate are
+	// compiled into the flux of the rule. They are set by the rule service
+	// and are not stored with it.
+	Silences              []*influxdb.Silence                           `json:"-"`
+	Alerts                []*influxdb.Alert                             `json:"-"`
+	EscalationEndpoints   map[influxdb.ID]influxdb.NotificationEndpoint `json:"-"`
+	SharedMessageTemplate *influxdb.MessageTemplate                     `json:"-"`
 }
 
 func (b Base) valid() error {
@@ -112,8 +136,13 @@ func (b Base) valid() error {
 			}
 		}
 	}
+	if b.Retry != nil {
+		if err := b.Retry.valid(b.Every); err != nil {
+			return err
+		}
+	}
 
-	return nil
+	return b.validEscalation()
 }
 func (b *Base) generateFluxASTNotificationDefinition(e influxdb.NotificationEndpoint) ast.Statement {
 	ruleID := flux.Property("_notification_rule_id", flux.String(b.ID.String()))
@@ -132,6 +161,11 @@ func (b *Base) generateLevelChecks() []ast.Statement {
 		tables = append(tables, table)
 		stmts = append(stmts, stmt)
 	}
+	if b.NotifyResolved {
+		stmt, table := b.generateResolved()
+		tables = append(tables, table)
+		stmts = append(stmts, stmt)
+	}
 
 	now := flux.Call(flux.Identifier("now"), flux.Object())
 	timeFilter := flux.Function(
@@ -183,6 +217,17 @@ func (b *Base) generateLevelChecks() []ast.Statement {
 		)
 	}
 
+	if as := b.suppressedAlerts(); len(as) > 0 {
+		stmt, filter := b.generateAcknowledged(as)
+		stmts = append(stmts, stmt)
+		pipe = flux.Pipe(pipe, filter)
+	}
+
+	if rss := b.matchingSilences(); len(rss) > 0 {
+		stmts = append(stmts, flux.DefineVariable("statuses_window", pipe))
+		return append(stmts, b.generateSilences(rss, flux.Identifier("statuses_window"))...)
+	}
+
 	stmts = append(stmts, flux.DefineVariable("all_statuses", pipe))
 
 	return stmts
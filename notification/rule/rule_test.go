@@ -130,6 +130,97 @@ func TestValidRule(t *testing.T) {
 				Msg:  "slack msg template is empty",
 			},
 		},
+		{
+			name: "empty slack message with message template",
+			src: &rule.Slack{
+				Base: rule.Base{
+					ID:                influxTesting.MustIDBase16(id1),
+					Name:              "name1",
+					OwnerID:           influxTesting.MustIDBase16(id2),
+					OrgID:             influxTesting.MustIDBase16(id3),
+					EndpointID:        1,
+					MessageTemplateID: 1,
+				},
+				Channel: "channel1",
+			},
+		},
+		{
+			name: "retry without attempts",
+			src: &rule.Slack{
+				Base: rule.Base{
+					ID:         influxTesting.MustIDBase16(id1),
+					Name:       "name1",
+					OwnerID:    influxTesting.MustIDBase16(id2),
+					OrgID:      influxTesting.MustIDBase16(id3),
+					EndpointID: 1,
+					Retry:      &rule.RetryPolicy{},
+				},
+				Channel:         "channel1",
+				MessageTemplate: "msg1",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "retry maxAttempts must be between 1 and 5",
+			},
+		},
+		{
+			name: "retry without backoff",
+			src: &rule.Slack{
+				Base: rule.Base{
+					ID:         influxTesting.MustIDBase16(id1),
+					Name:       "name1",
+					OwnerID:    influxTesting.MustIDBase16(id2),
+					OrgID:      influxTesting.MustIDBase16(id3),
+					EndpointID: 1,
+					Retry:      &rule.RetryPolicy{MaxAttempts: 3},
+				},
+				Channel:         "channel1",
+				MessageTemplate: "msg1",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "retry backoff must be larger than 0",
+			},
+		},
+		{
+			name: "retry backoff too large",
+			src: &rule.Slack{
+				Base: rule.Base{
+					ID:         influxTesting.MustIDBase16(id1),
+					Name:       "name1",
+					OwnerID:    influxTesting.MustIDBase16(id2),
+					OrgID:      influxTesting.MustIDBase16(id3),
+					EndpointID: 1,
+					Retry:      &rule.RetryPolicy{MaxAttempts: 2, Backoff: *mustDuration("2m")},
+				},
+				Channel:         "channel1",
+				MessageTemplate: "msg1",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "retry backoff must not be larger than 1m0s",
+			},
+		},
+		{
+			name: "retry backoffs longer than every",
+			src: &rule.Slack{
+				Base: rule.Base{
+					ID:         influxTesting.MustIDBase16(id1),
+					Name:       "name1",
+					OwnerID:    influxTesting.MustIDBase16(id2),
+					OrgID:      influxTesting.MustIDBase16(id3),
+					EndpointID: 1,
+					Every:      mustDuration("1m"),
+					Retry:      &rule.RetryPolicy{MaxAttempts: 4, Backoff: *mustDuration("10s")},
+				},
+				Channel:         "channel1",
+				MessageTemplate: "msg1",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "retry backoffs must not add up to more than the interval",
+			},
+		},
 		{
 			name: "empty pagerDuty message",
 			src: &rule.PagerDuty{
@@ -356,6 +447,86 @@ func TestJSON(t *testing.T) {
 				MessageTemplate: "blah",
 			},
 		},
+		{
+			name: "simple smtp",
+			src: &rule.SMTP{
+				Base: rule.Base{
+					ID:          influxTesting.MustIDBase16(id1),
+					OwnerID:     influxTesting.MustIDBase16(id2),
+					Name:        "name1",
+					OrgID:       influxTesting.MustIDBase16(id3),
+					RunbookLink: "runbooklink1",
+					Every:       mustDuration("1h"),
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				To:              "oncall@example.com, ops@example.com",
+				SubjectTemplate: "${r._check_name} is ${r._level}",
+				BodyTemplate:    "${r._message}",
+			},
+		},
+		{
+			name: "simple teams",
+			src: &rule.Teams{
+				Base: rule.Base{
+					ID:          influxTesting.MustIDBase16(id1),
+					OwnerID:     influxTesting.MustIDBase16(id2),
+					Name:        "name1",
+					OrgID:       influxTesting.MustIDBase16(id3),
+					RunbookLink: "runbooklink1",
+					Every:       mustDuration("1h"),
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Title:           "${r._check_name} is ${r._level}",
+				MessageTemplate: "${r._message}",
+				Summary:         "summary",
+			},
+		},
+		{
+			name: "simple opsgenie",
+			src: &rule.Opsgenie{
+				Base: rule.Base{
+					ID:          influxTesting.MustIDBase16(id1),
+					OwnerID:     influxTesting.MustIDBase16(id2),
+					Name:        "name1",
+					OrgID:       influxTesting.MustIDBase16(id3),
+					RunbookLink: "runbooklink1",
+					Every:       mustDuration("1h"),
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				MessageTemplate:     "${r._check_name} is ${r._level}",
+				DescriptionTemplate: "${r._message}",
+				Priority:            "P2",
+				Responders:          []string{"team:ops"},
+				Tags:                []string{"influxdb"},
+			},
+		},
+		{
+			name: "http with body template",
+			src: &rule.HTTP{
+				Base: rule.Base{
+					ID:          influxTesting.MustIDBase16(id1),
+					OwnerID:     influxTesting.MustIDBase16(id2),
+					Name:        "name1",
+					OrgID:       influxTesting.MustIDBase16(id3),
+					RunbookLink: "runbooklink1",
+					Every:       mustDuration("1h"),
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				BodyTemplate: `{"check": "${r._check_name}", "text": "${r._message}"}`,
+			},
+		},
 	}
 	for _, c := range cases {
 		b, err := json.Marshal(c.src)
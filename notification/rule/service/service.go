@@ -39,13 +39,54 @@ type RuleService struct {
 	tasks     influxdb.TaskService
 	orgs      influxdb.OrganizationService
 	endpoints influxdb.NotificationEndpointService
+	silences  influxdb.SilenceService
+	alerts    influxdb.AlertService
+
+	messageTemplates influxdb.MessageTemplateService
+	limit            kv.CreateLimit
 
 	idGenerator   influxdb.IDGenerator
 	timeGenerator influxdb.TimeGenerator
 }
 
+// Option configures the notification rule service.
+type Option func(*RuleService)
+
+// WithSilenceService compiles the silences of the organization of a
+// notification rule into the flux of its task.
+func WithSilenceService(silences influxdb.SilenceService) Option {
+	return func(s *RuleService) {
+		s.silences = silences
+	}
+}
+
+// WithAlertService compiles the unresolved alerts of the organization of a
+// notification rule into the flux of its task when the rule suppresses
+// acknowledged alerts or escalates open alerts.
+func WithAlertService(alerts influxdb.AlertService) Option {
+	return func(s *RuleService) {
+		s.alerts = alerts
+	}
+}
+
+// WithNotificationRuleLimit limits the number of notification rules of an
+// organization.
+func WithNotificationRuleLimit(limit kv.CreateLimit) Option {
+	return func(s *RuleService) {
+		s.limit = limit
+	}
+}
+
+// WithMessageTemplateService compiles the message template referenced by a
+// notification rule into the flux of its task.
+func WithMessageTemplateService(messageTemplates influxdb.MessageTemplateService) Option {
+	return func(s *RuleService) {
+		s.messageTemplates = messageTemplates
+	}
+}
+
 // New constructs and configures a notification rule service
-func New(logger *zap.Logger, store kv.Store, tasks influxdb.TaskService, orgs influxdb.OrganizationService, endpoints influxdb.NotificationEndpointService) (*RuleService, error) {
+func New(logger *zap.Logger, store kv.Store, tasks influxdb.TaskService, orgs influxdb.OrganizationService, endpoints influxdb.NotificationEndpointService, opts ...Option) (*RuleService, error) {
 	s := &RuleService{
 		log:           logger,
 		kv:            store,
@@ -55,6 +96,9 @@ func New(logger *zap.Logger, store kv.Store, tasks influxdb.TaskService, orgs in
 		timeGenerator: influxdb.RealTimeGenerator{},
 		idGenerator:   snowflake.NewIDGenerator(),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
 
 	ctx := context.Background()
 	if err := store.Update(ctx, func(tx kv.Tx) error {
@@ -148,6 +192,17 @@ func (s *RuleService) createNotificationRule(ctx context.Context, tx kv.Tx, nr i
 		return err
 	}
 
+	if s.limit != nil {
+		orgID := nr.GetOrgID()
+		err := s.limit(ctx, tx, orgID, func() (int, error) {
+			_, n, err := s.findNotificationRules(ctx, tx, influxdb.NotificationRuleFilter{OrgID: &orgID})
+			return n, err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	return s.putNotificationRule(ctx, tx, nr.NotificationRule)
 }
 
@@ -157,6 +212,22 @@ func (s *RuleService) createNotificationTask(ctx context.Context, r influxdb.Not
 		return nil, err
 	}
 
+	if err := s.setSilences(ctx, r.NotificationRule); err != nil {
+		return nil, err
+	}
+
+	if err := s.setAlerts(ctx, r.NotificationRule); err != nil {
+		return nil, err
+	}
+
+	if err := s.setEscalationEndpoints(ctx, r.NotificationRule); err != nil {
+		return nil, err
+	}
+
+	if err := s.setMessageTemplate(ctx, r.NotificationRule); err != nil {
+		return nil, err
+	}
+
 	script, err := r.GenerateFlux(ep)
 	if err != nil {
 		return nil, err
@@ -221,6 +292,22 @@ func (s *RuleService) updateNotificationTask(ctx context.Context, r influxdb.Not
 		return nil, err
 	}
 
+	if err := s.setSilences(ctx, r); err != nil {
+		return nil, err
+	}
+
+	if err := s.setAlerts(ctx, r); err != nil {
+		return nil, err
+	}
+
+	if err := s.setEscalationEndpoints(ctx, r); err != nil {
+		return nil, err
+	}
+
+	if err := s.setMessageTemplate(ctx, r); err != nil {
+		return nil, err
+	}
+
 	script, err := r.GenerateFlux(ep)
 	if err != nil {
 		return nil, err
@@ -240,6 +327,166 @@ func (s *RuleService) updateNotificationTask(ctx context.Context, r influxdb.Not
 	return t, nil
 }
 
+// silencer is implemented by the notification rules that compile silences into their flux.
+type silencer interface {
+	SetSilences(sls []*influxdb.Silence)
+}
+
+// setSilences sets the silences of the organization of the rule that have not expired.
+func (s *RuleService) setSilences(ctx context.Context, r influxdb.NotificationRule) error {
+	sr, ok := r.(silencer)
+	if s.silences == nil || !ok {
+		return nil
+	}
+
+	orgID := r.GetOrgID()
+	sls, err := s.silences.FindSilences(ctx, influxdb.SilenceFilter{OrgID: &orgID})
+	if err != nil {
+		return err
+	}
+
+	now := s.timeGenerator.Now()
+	active := sls[:0]
+	for _, sl := range sls {
+		if !sl.Expired(now) {
+			active = append(active, sl)
+		}
+	}
+	sr.SetSilences(active)
+	return nil
+}
+
+// alertTracker is implemented by the notification rules that compile the
+// alerts of their organization into their flux.
+type alertTracker interface {
+	SetAlerts(as []*influxdb.Alert)
+}
+
+// setAlerts sets the unresolved alerts of the organization of the rule.
+func (s *RuleService) setAlerts(ctx context.Context, r influxdb.NotificationRule) error {
+	at, ok := r.(alertTracker)
+	if s.alerts == nil || !ok {
+		return nil
+	}
+
+	orgID := r.GetOrgID()
+	as, err := s.alerts.FindAlerts(ctx, influxdb.AlertFilter{OrgID: &orgID})
+	if err != nil {
+		return err
+	}
+
+	unresolved := as[:0]
+	for _, a := range as {
+		if a.State != influxdb.AlertResolved {
+			unresolved = append(unresolved, a)
+		}
+	}
+	at.SetAlerts(unresolved)
+	return nil
+}
+
+// escalator is implemented by the notification rules that escalate to other endpoints.
+type escalator interface {
+	EscalationEndpointIDs() []influxdb.ID
+	SetEscalationEndpoints(es []influxdb.NotificationEndpoint) error
+}
+
+// setEscalationEndpoints sets the endpoints of the escalation steps of the rule.
+func (s *RuleService) setEscalationEndpoints(ctx context.Context, r influxdb.NotificationRule) error {
+	esc, ok := r.(escalator)
+	if !ok {
+		return nil
+	}
+
+	ids := esc.EscalationEndpointIDs()
+	es := make([]influxdb.NotificationEndpoint, 0, len(ids))
+	for _, id := range ids {
+		e, err := s.endpoints.FindNotificationEndpointByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if e.GetOrgID() != r.GetOrgID() {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "escalation endpoint must belong to the organization of the notification rule",
+			}
+		}
+		es = append(es, e)
+	}
+	return esc.SetEscalationEndpoints(es)
+}
+
+// messageTemplater is implemented by the notification rules that compile a message template into their flux.
+type messageTemplater interface {
+	GetMessageTemplateID() influxdb.ID
+	SetMessageTemplate(t *influxdb.MessageTemplate) error
+}
+
+// setMessageTemplate sets the message template referenced by the rule. A rule
+// whose template was deleted falls back to its own message template.
+func (s *RuleService) setMessageTemplate(ctx context.Context, r influxdb.NotificationRule) error {
+	mt, ok := r.(messageTemplater)
+	if s.messageTemplates == nil || !ok || !mt.GetMessageTemplateID().Valid() {
+		return nil
+	}
+
+	t, err := s.messageTemplates.FindMessageTemplateByID(ctx, mt.GetMessageTemplateID())
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return mt.SetMessageTemplate(nil)
+	}
+	if err != nil {
+		return err
+	}
+	if t.OrgID != r.GetOrgID() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "message template must belong to the organization of the notification rule",
+		}
+	}
+	return mt.SetMessageTemplate(t)
+}
+
+// SyncNotificationRuleTasks regenerates the tasks of the notification rules
+// of the organization with its current silences, alerts and message templates.
+func (s *RuleService) SyncNotificationRuleTasks(ctx context.Context, orgID influxdb.ID) error {
+	nrs, _, err := s.FindNotificationRules(ctx, influxdb.NotificationRuleFilter{OrgID: &orgID})
+	if err != nil {
+		return err
+	}
+
+	for _, nr := range nrs {
+		if _, err := s.updateNotificationTask(ctx, nr, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alertCompiler is implemented by the notification rules that compile alerts into their flux.
+type alertCompiler interface {
+	CompilesAlert(a *influxdb.Alert) bool
+}
+
+// SyncAlertRuleTasks regenerates the tasks of the notification rules of the
+// organization of the alert that compile it into their flux.
+func (s *RuleService) SyncAlertRuleTasks(ctx context.Context, a *influxdb.Alert) error {
+	nrs, _, err := s.FindNotificationRules(ctx, influxdb.NotificationRuleFilter{OrgID: &a.OrgID})
+	if err != nil {
+		return err
+	}
+
+	for _, nr := range nrs {
+		ac, ok := nr.(alertCompiler)
+		if !ok || !ac.CompilesAlert(a) {
+			continue
+		}
+		if _, err := s.updateNotificationTask(ctx, nr, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // PatchNotificationRule updates a single  notification rule with changeset.
 // Returns the new notification rule state after update.
 func (s *RuleService) PatchNotificationRule(ctx context.Context, id influxdb.ID, upd influxdb.NotificationRuleUpdate) (influxdb.NotificationRule, error) {
@@ -0,0 +1,265 @@
+package rule
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// SetSilences sets the silences of the organization of the rule. They are not
+// stored with the rule, the ones that can match the rule are compiled into its flux.
+func (b *Base) SetSilences(sls []*influxdb.Silence) {
+	b.Silences = sls
+}
+
+// ruleSilence is a silence that can match the statuses of the rule, the
+// matchers on the rule itself are already resolved.
+type ruleSilence struct {
+	silence  *influxdb.Silence
+	matchers []influxdb.TagRule
+}
+
+func (b *Base) matchingSilences() []ruleSilence {
+	ruleColumns := map[string]string{
+		"_notification_rule_id":     b.ID.String(),
+		"_notification_rule_name":   b.Name,
+		"_notification_endpoint_id": b.EndpointID.String(),
+	}
+
+	var rss []ruleSilence
+	for _, sl := range b.Silences {
+		rs := ruleSilence{silence: sl}
+		matches := true
+		for _, m := range sl.Matchers {
+			v, ok := ruleColumns[m.Key]
+			if !ok {
+				rs.matchers = append(rs.matchers, m)
+				continue
+			}
+			if !matchValue(m, v) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			rss = append(rss, rs)
+		}
+	}
+	return rss
+}
+
+func matchValue(m influxdb.TagRule, v string) bool {
+	switch m.Operator {
+	case influxdb.NotEqual:
+		return v != m.Value
+	case influxdb.RegexEqual, influxdb.NotRegexEqual:
+		re, err := regexp.Compile(m.Value)
+		if err != nil {
+			return false
+		}
+		return re.MatchString(v) == (m.Operator == influxdb.RegexEqual)
+	default:
+		return v == m.Value
+	}
+}
+
+func (b *Base) usesDate() bool {
+	for _, rs := range b.matchingSilences() {
+		if rs.silence.Recurrence != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// generateImports returns the import declarations of the packages, along with
+// the date package when a recurring silence is compiled into the rule, the
+// system package when it has a retry policy and the packages of the endpoints
+// of its escalation steps.
+func (b *Base) generateImports(pkgs ...string) []*ast.ImportDeclaration {
+	if b.usesDate() {
+		pkgs = append(pkgs, "date")
+	}
+	if b.Retry != nil {
+		pkgs = append(pkgs, "system")
+	}
+	pkgs = append(pkgs, b.escalationImports()...)
+
+	imported := make(map[string]bool, len(pkgs))
+	unique := pkgs[:0]
+	for _, pkg := range pkgs {
+		if !imported[pkg] {
+			imported[pkg] = true
+			unique = append(unique, pkg)
+		}
+	}
+	return flux.Imports(unique...)
+}
+
+// generateSilences splits the statuses of the window into all_statuses, the
+// statuses to notify, and the silenced statuses. The silenced statuses are
+// recorded as notifications that were not sent along with the ID of the
+// silence that muted them.
+func (b *Base) generateSilences(rss []ruleSilence, window ast.Expression) []ast.Statement {
+	var silenceID ast.Expression = flux.String("")
+	for i := len(rss) - 1; i >= 0; i-- {
+		silenceID = flux.If(
+			generateSilencePredicate(rss[i]),
+			flux.String(rss[i].silence.ID.String()),
+			silenceID,
+		)
+	}
+	callSilenceID := flux.Call(
+		flux.Identifier("silence_id"),
+		flux.Object(flux.Property("r", flux.Identifier("r"))),
+	)
+
+	notSilenced := flux.Function(flux.FunctionParams("r"), flux.Equal(callSilenceID, flux.String("")))
+	silenced := flux.Function(flux.FunctionParams("r"), &ast.BinaryExpression{
+		Operator: ast.NotEqualOperator,
+		Left:     callSilenceID,
+		Right:    flux.String(""),
+	})
+
+	record := flux.Function(
+		flux.FunctionParams("r"),
+		flux.ObjectWith("r",
+			flux.Property("_sent", flux.String("false")),
+			flux.Property("_silence_id", callSilenceID),
+		),
+	)
+	recordEndpoint := &ast.FunctionExpression{
+		Params: []*ast.Property{{
+			Key:   &ast.Identifier{Name: "tables"},
+			Value: &ast.PipeLiteral{},
+		}},
+		Body: flux.Pipe(
+			flux.Identifier("tables"),
+			flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", record))),
+		),
+	}
+
+	return []ast.Statement{
+		flux.DefineVariable("silence_id", flux.Function(flux.FunctionParams("r"), silenceID)),
+		flux.DefineVariable("all_statuses", flux.Pipe(
+			window,
+			flux.Call(flux.Identifier("filter"), flux.Object(flux.Property("fn", notSilenced))),
+		)),
+		flux.ExpressionStatement(flux.Pipe(
+			window,
+			flux.Call(flux.Identifier("filter"), flux.Object(flux.Property("fn", silenced))),
+			flux.Call(
+				flux.Member("monitor", "notify"),
+				flux.Object(
+					flux.Property("data", flux.Identifier("notification")),
+					flux.Property("endpoint", recordEndpoint),
+				),
+			),
+		)),
+	}
+}
+
+func generateSilencePredicate(rs ruleSilence) ast.Expression {
+	var exprs []ast.Expression
+	for _, m := range rs.matchers {
+		exprs = append(exprs, generateSilenceMatcher(m))
+	}
+
+	sl := rs.silence
+	if sl.StartsAt != nil {
+		exprs = append(exprs, &ast.BinaryExpression{
+			Operator: ast.GreaterThanEqualOperator,
+			Left:     flux.Member("r", "_time"),
+			Right:    &ast.DateTimeLiteral{Value: sl.StartsAt.UTC()},
+		})
+	}
+	if sl.EndsAt != nil {
+		exprs = append(exprs, flux.LessThan(
+			flux.Member("r", "_time"),
+			&ast.DateTimeLiteral{Value: sl.EndsAt.UTC()},
+		))
+	}
+	if sl.Recurrence != nil {
+		exprs = append(exprs, generateRecurrence(sl.Recurrence))
+	}
+
+	if len(exprs) == 0 {
+		return flux.Bool(true)
+	}
+	expr := exprs[0]
+	for _, e := range exprs[1:] {
+		expr = flux.And(expr, e)
+	}
+	return expr
+}
+
+func generateSilenceMatcher(m influxdb.TagRule) ast.Expression {
+	k := flux.Member("r", m.Key)
+	switch m.Operator {
+	case influxdb.NotEqual:
+		return &ast.BinaryExpression{Operator: ast.NotEqualOperator, Left: k, Right: flux.String(m.Value)}
+	case influxdb.RegexEqual, influxdb.NotRegexEqual:
+		op := ast.RegexpMatchOperator
+		if m.Operator == influxdb.NotRegexEqual {
+			op = ast.NotRegexpMatchOperator
+		}
+		return &ast.BinaryExpression{
+			Operator: op,
+			Left:     k,
+			Right:    &ast.RegexpLiteral{Value: regexp.MustCompile(m.Value)},
+		}
+	default:
+		return flux.Equal(k, flux.String(m.Value))
+	}
+}
+
+// generateRecurrence generates the test of a daily window, a window that ends
+// on the next day matches the day after its start days before its end.
+func generateRecurrence(r *influxdb.SilenceRecurrence) ast.Expression {
+	t := flux.Object(flux.Property("t", flux.Member("r", "_time")))
+	minute := flux.Add(
+		&ast.BinaryExpression{
+			Operator: ast.MultiplicationOperator,
+			Left:     flux.Call(flux.Member("date", "hour"), t),
+			Right:    flux.Integer(60),
+		},
+		flux.Call(flux.Member("date", "minute"), t),
+	)
+	weekDay := flux.Call(flux.Member("date", "weekDay"), t)
+
+	start, end := int64(r.StartMinute()), int64(r.EndMinute())
+	days := r.Weekdays()
+	afterStart := &ast.BinaryExpression{
+		Operator: ast.GreaterThanEqualOperator,
+		Left:     minute,
+		Right:    flux.Integer(start),
+	}
+	beforeEnd := flux.LessThan(minute, flux.Integer(end))
+
+	if end > start {
+		return withDays(weekDay, days, 0, flux.And(afterStart, beforeEnd))
+	}
+	return flux.Or(
+		withDays(weekDay, days, 0, afterStart),
+		withDays(weekDay, days, 1, beforeEnd),
+	)
+}
+
+// withDays restricts the test to the days, shifted by the number of days.
+func withDays(weekDay ast.Expression, days []time.Weekday, shift int, test ast.Expression) ast.Expression {
+	if len(days) == 7 {
+		return test
+	}
+	set := make([]ast.Expression, 0, len(days))
+	for _, d := range days {
+		set = append(set, flux.Integer(int64((int(d)+shift)%7)))
+	}
+	contains := flux.Call(flux.Identifier("contains"), flux.Object(
+		flux.Property("value", weekDay),
+		flux.Property("set", flux.Array(set...)),
+	))
+	return flux.And(contains, test)
+}
@@ -0,0 +1,116 @@
+package rule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+)
+
+func TestSlack_GenerateFlux_silences(t *testing.T) {
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Hour)
+
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+import "date"
+
+option task = {name: "foo", every: 1h}
+
+slack_endpoint = slack["endpoint"](url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h)
+any = statuses
+	|> filter(fn: (r) =>
+		(true))
+statuses_window = any
+	|> filter(fn: (r) =>
+		(r["_time"] >= experimental["subDuration"](from: now(), d: 1h)))
+silence_id = (r) =>
+	(if r["host"] =~ /db-.*/ and r["_time"] >= 2020-06-01T00:00:00Z and r["_time"] < 2020-06-01T04:00:00Z then "000000000000000a" else if r["env"] != "prod" and (contains(value: date["weekDay"](t: r["_time"]), set: [0]) and (date["hour"](t: r["_time"]) * 60 + date["minute"](t: r["_time"]) >= 120 and date["hour"](t: r["_time"]) * 60 + date["minute"](t: r["_time"]) < 240)) then "000000000000000b" else "")
+all_statuses = statuses_window
+	|> filter(fn: (r) =>
+		(silence_id(r: r) == ""))
+
+statuses_window
+	|> filter(fn: (r) =>
+		(silence_id(r: r) != ""))
+	|> monitor["notify"](data: notification, endpoint: (tables=<-) =>
+		(tables
+			|> map(fn: (r) =>
+				({r with _sent: "false", _silence_id: silence_id(r: r)}))))
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "bar", text: "blah", color: if r["_level"] == "crit" then "danger" else if r["_level"] == "warn" then "warning" else "good"})))`
+
+	s := &rule.Slack{
+		Channel:         "bar",
+		MessageTemplate: "blah",
+		Base: rule.Base{
+			ID:         1,
+			EndpointID: 2,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Any,
+				},
+			},
+		},
+	}
+	s.SetSilences([]*influxdb.Silence{
+		{
+			ID: 10,
+			Matchers: []influxdb.TagRule{
+				{Tag: influxdb.Tag{Key: "host", Value: "db-.*"}, Operator: influxdb.RegexEqual},
+			},
+			StartsAt: &start,
+			EndsAt:   &end,
+		},
+		{
+			ID: 11,
+			Matchers: []influxdb.TagRule{
+				{Tag: influxdb.Tag{Key: "_notification_rule_id", Value: "0000000000000001"}, Operator: influxdb.Equal},
+				{Tag: influxdb.Tag{Key: "env", Value: "prod"}, Operator: influxdb.NotEqual},
+			},
+			Recurrence: &influxdb.SilenceRecurrence{Days: []string{"sunday"}, Start: "02:00", End: "04:00"},
+		},
+		{
+			// matches another rule only.
+			ID: 12,
+			Matchers: []influxdb.TagRule{
+				{Tag: influxdb.Tag{Key: "_notification_rule_name", Value: "other"}, Operator: influxdb.Equal},
+			},
+			StartsAt: &start,
+			EndsAt:   &end,
+		},
+	})
+
+	e := &endpoint.Slack{
+		Base: endpoint.Base{
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
@@ -34,7 +34,7 @@ func (s *Slack) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
 func (s *Slack) GenerateFluxAST(e *endpoint.Slack) (*ast.Package, error) {
 	f := flux.File(
 		s.Name,
-		flux.Imports("influxdata/influxdb/monitor", "slack", "influxdata/influxdb/secrets", "experimental"),
+		s.generateImports("influxdata/influxdb/monitor", "slack", "influxdata/influxdb/secrets", "experimental"),
 		s.generateFluxASTBody(e),
 	)
 	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
@@ -51,6 +51,7 @@ func (s *Slack) generateFluxASTBody(e *endpoint.Slack) []ast.Statement {
 	statements = append(statements, s.generateFluxASTStatuses())
 	statements = append(statements, s.generateLevelChecks()...)
 	statements = append(statements, s.generateFluxASTNotifyPipe())
+	statements = append(statements, s.generateEscalations()...)
 
 	return statements
 }
@@ -69,6 +70,15 @@ func (s *Slack) generateFluxASTEndpoint(e *endpoint.Slack) ast.Statement {
 	if e.URL != "" {
 		props = append(props, flux.Property("url", flux.String(e.URL)))
 	}
+	if s.Retry != nil {
+		props = append(props,
+			flux.Property("channel", flux.Member("obj", "channel")),
+			flux.Property("text", flux.Member("obj", "text")),
+			flux.Property("color", flux.Member("obj", "color")),
+		)
+		send := flux.Function(flux.FunctionParams("r", "obj"), flux.Call(flux.Member("slack", "message"), flux.Object(props...)))
+		return s.generateDeliveryEndpoint("slack_endpoint", send, flux.Member("obj", "text"))
+	}
 	call := flux.Call(flux.Member("slack", "endpoint"), flux.Object(props...))
 
 	return flux.DefineVariable("slack_endpoint", call)
@@ -78,8 +88,8 @@ func (s *Slack) generateFluxASTNotifyPipe() ast.Statement {
 	endpointProps := []*ast.Property{}
 	endpointProps = append(endpointProps, flux.Property("channel", flux.String(s.Channel)))
 	// TODO(desa): are these values correct?
-	endpointProps = append(endpointProps, flux.Property("text", flux.String(s.MessageTemplate)))
-	endpointProps = append(endpointProps, flux.Property("color", s.generateSlackColors()))
+	endpointProps = append(endpointProps, flux.Property("text", s.message(flux.String(s.MessageTemplate))))
+	endpointProps = append(endpointProps, flux.Property("color", slackColors()))
 	endpointFn := flux.Function(flux.FunctionParams("r"), flux.Object(endpointProps...))
 
 	props := []*ast.Property{}
@@ -92,7 +102,7 @@ func (s *Slack) generateFluxASTNotifyPipe() ast.Statement {
 	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("all_statuses"), call))
 }
 
-func (s *Slack) generateSlackColors() ast.Expression {
+func slackColors() ast.Expression {
 	level := flux.Member("r", "_level")
 	return flux.If(
 		flux.Equal(level, flux.String("crit")),
@@ -124,7 +134,7 @@ func (s Slack) Valid() error {
 	if err := s.Base.valid(); err != nil {
 		return err
 	}
-	if s.MessageTemplate == "" {
+	if s.MessageTemplate == "" && !s.MessageTemplateID.Valid() {
 		return &influxdb.Error{
 			Code: influxdb.EInvalid,
 			Msg:  "slack msg template is empty",
@@ -105,6 +105,184 @@ all_statuses
 				URL: "http://localhost:7777",
 			},
 		},
+		{
+			name: "with shared message template",
+			want: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+slack_endpoint = slack["endpoint"](url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h, fn: (r) =>
+	(r["foo"] == "bar" and r["baz"] == "bang"))
+any = statuses
+	|> filter(fn: (r) =>
+		(true))
+all_statuses = any
+	|> filter(fn: (r) =>
+		(r["_time"] >= experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "bar", text: "${r["_check_name"]} is ${r["_level"]}: ${r["_message"]} https://runbooks.example.com/foo", color: if r["_level"] == "crit" then "danger" else if r["_level"] == "warn" then "warning" else "good"})))`,
+			rule: &rule.Slack{
+				Channel: "bar",
+				Base: rule.Base{
+					ID:                1,
+					EndpointID:        2,
+					Name:              "foo",
+					Every:             mustDuration("1h"),
+					RunbookLink:       "https://runbooks.example.com/foo",
+					MessageTemplateID: 3,
+					SharedMessageTemplate: &influxdb.MessageTemplate{
+						ID:       3,
+						OrgID:    1,
+						Name:     "cpu",
+						Template: "{{ check_name }} is {{ level }}: {{ message }} {{ runbook }}",
+					},
+					TagRules: []notification.TagRule{
+						{
+							Tag: influxdb.Tag{
+								Key:   "foo",
+								Value: "bar",
+							},
+							Operator: influxdb.Equal,
+						},
+						{
+							Tag: influxdb.Tag{
+								Key:   "baz",
+								Value: "bang",
+							},
+							Operator: influxdb.Equal,
+						},
+					},
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Any,
+						},
+					},
+				},
+			},
+			endpoint: &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:   idPtr(2),
+					Name: "foo",
+				},
+				URL: "http://localhost:7777",
+			},
+		},
+		{
+			name: "with retry policy",
+			want: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+import "system"
+
+option task = {name: "foo", every: 1h}
+
+slack_endpoint = (mapFn) =>
+	((tables=<-) =>
+		(tables
+			|> map(fn: (r) => {
+				send = (r, obj) =>
+					(slack["message"](
+						url: "http://localhost:7777",
+						channel: obj["channel"],
+						text: obj["text"],
+						color: obj["color"],
+					))
+				retry = (code) =>
+					(code == 429 or code >= 500)
+				obj = mapFn(r: r)
+				start = system["time"]()
+				code_1 = send(r: r, obj: obj)
+				code_2 = if retry(code: code_1) then send(r: r, obj: sleep(v: obj, duration: 1s)) else code_1
+				code_3 = if retry(code: code_2) then send(r: r, obj: sleep(v: obj, duration: 2s)) else code_2
+				stop = system["time"]()
+
+				return {r with 
+					_sent: string(v: 2 == code_3 / 100),
+					_status_code: code_3,
+					_attempts: if retry(code: code_2) then 3 else if retry(code: code_1) then 2 else 1,
+					_latency_ms: (int(v: stop) - int(v: start)) / 1000000,
+					_error: if 2 == code_3 / 100 then "" else "HTTP status " + string(v: code_3),
+					_notification_message: obj["text"],
+				}
+			})))
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h, fn: (r) =>
+	(r["foo"] == "bar" and r["baz"] == "bang"))
+any = statuses
+	|> filter(fn: (r) =>
+		(true))
+all_statuses = any
+	|> filter(fn: (r) =>
+		(r["_time"] >= experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "bar", text: "blah", color: if r["_level"] == "crit" then "danger" else if r["_level"] == "warn" then "warning" else "good"})))`,
+			rule: &rule.Slack{
+				Channel:         "bar",
+				MessageTemplate: "blah",
+				Base: rule.Base{
+					ID:         1,
+					EndpointID: 2,
+					Name:       "foo",
+					Every:      mustDuration("1h"),
+					Retry: &rule.RetryPolicy{
+						MaxAttempts: 3,
+						Backoff:     *mustDuration("1s"),
+					},
+					TagRules: []notification.TagRule{
+						{
+							Tag: influxdb.Tag{
+								Key:   "foo",
+								Value: "bar",
+							},
+							Operator: influxdb.Equal,
+						},
+						{
+							Tag: influxdb.Tag{
+								Key:   "baz",
+								Value: "bang",
+							},
+							Operator: influxdb.Equal,
+						},
+					},
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Any,
+						},
+					},
+				},
+			},
+			endpoint: &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:   idPtr(2),
+					Name: "foo",
+				},
+				URL: "http://localhost:7777",
+			},
+		},
 		{
 			name: "with url",
 			want: `package main
@@ -0,0 +1,234 @@
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// SMTP is the notification rule config of email sent through an SMTP relay.
+type SMTP struct {
+	Base
+	// To is a comma separated list of recipient addresses.
+	To string `json:"to"`
+	// SubjectTemplate and BodyTemplate are flux string templates,
+	// rendered for every status, i.e. "${r._check_name} is ${r._level}".
+	SubjectTemplate string `json:"subjectTemplate"`
+	BodyTemplate    string `json:"bodyTemplate"`
+}
+
+// GenerateFlux generates a flux script for the smtp notification rule.
+func (s *SMTP) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
+	smtpEndpoint, ok := e.(*endpoint.SMTP)
+	if !ok {
+		return "", fmt.Errorf("endpoint provided is a %s, not an SMTP endpoint", e.Type())
+	}
+	p, err := s.GenerateFluxAST(smtpEndpoint)
+	if err != nil {
+		return "", err
+	}
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST generates a flux AST for the smtp notification rule.
+// The message is posted to the smtp:// url of the endpoint, which influxd
+// delivers through the relay instead of issuing an http request.
+func (s *SMTP) GenerateFluxAST(e *endpoint.SMTP) (*ast.Package, error) {
+	to, err := s.Recipients()
+	if err != nil {
+		return nil, err
+	}
+	f := flux.File(
+		s.Name,
+		s.imports(e),
+		s.generateFluxASTBody(e, to),
+	)
+	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
+}
+
+func (s *SMTP) imports(e *endpoint.SMTP) []*ast.ImportDeclaration {
+	packages := []string{
+		"influxdata/influxdb/monitor",
+		"http",
+		"json",
+		"experimental",
+	}
+
+	if e.HasCredentials() {
+		packages = append(packages, "influxdata/influxdb/secrets")
+	}
+
+	return s.generateImports(packages...)
+}
+
+// Recipients returns the addresses of the comma separated To list.
+func (s *SMTP) Recipients() ([]string, error) {
+	return parseRecipients(s.To)
+}
+
+func parseRecipients(to string) ([]string, error) {
+	addrs, err := mail.ParseAddressList(to)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("SMTP To %q is not a list of email addresses", to),
+			Err:  err,
+		}
+	}
+	recipients := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		recipients = append(recipients, addr.Address)
+	}
+	return recipients, nil
+}
+
+func (s *SMTP) generateFluxASTBody(e *endpoint.SMTP, to []string) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, s.generateTaskOption())
+	statements = append(statements, s.generateHeaders(e))
+	statements = append(statements, s.generateFluxASTEndpoint(e))
+	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
+	statements = append(statements, s.generateFluxASTStatuses())
+	statements = append(statements, s.generateLevelChecks()...)
+	statements = append(statements, s.generateFluxASTNotifyPipe(e, to))
+	statements = append(statements, s.generateEscalations()...)
+
+	return statements
+}
+
+func (s *SMTP) generateHeaders(e *endpoint.SMTP) ast.Statement {
+	return flux.DefineVariable("headers", smtpHeaders(e))
+}
+
+func smtpHeaders(e *endpoint.SMTP) *ast.ObjectExpression {
+	props := []*ast.Property{
+		flux.Dictionary(
+			"Content-Type", flux.String("application/json"),
+		),
+	}
+
+	if e.HasCredentials() {
+		username := flux.Call(
+			flux.Member("secrets", "get"),
+			flux.Object(
+				flux.Property("key", flux.String(e.Username.Key)),
+			),
+		)
+		passwd := flux.Call(
+			flux.Member("secrets", "get"),
+			flux.Object(
+				flux.Property("key", flux.String(e.Password.Key)),
+			),
+		)
+
+		basic := flux.Call(
+			flux.Member("http", "basicAuth"),
+			flux.Object(
+				flux.Property("u", username),
+				flux.Property("p", passwd),
+			),
+		)
+
+		props = append(props, flux.Dictionary("Authorization", basic))
+	}
+	return flux.Object(props...)
+}
+
+func (s *SMTP) generateFluxASTEndpoint(e *endpoint.SMTP) ast.Statement {
+	if s.Retry != nil {
+		return s.generateDeliveryEndpoint("smtp_endpoint", httpSend(e.URL()), httpMessage())
+	}
+	call := flux.Call(flux.Member("http", "endpoint"), flux.Object(flux.Property("url", flux.String(e.URL()))))
+
+	return flux.DefineVariable("smtp_endpoint", call)
+}
+
+func (s *SMTP) generateFluxASTNotifyPipe(e *endpoint.SMTP, to []string) ast.Statement {
+	addrs := make([]ast.Expression, 0, len(to))
+	for _, addr := range to {
+		addrs = append(addrs, flux.String(addr))
+	}
+	body := flux.DefineVariable("body", flux.Object(
+		flux.Property("from", flux.String(e.From)),
+		flux.Property("to", flux.Array(addrs...)),
+		flux.Property("subject", flux.String(s.SubjectTemplate)),
+		flux.Property("body", s.message(flux.String(s.BodyTemplate))),
+	))
+
+	endpointBody := flux.Call(
+		flux.Member("json", "encode"),
+		flux.Object(flux.Property("v", flux.Identifier("body"))),
+	)
+	endpointFn := flux.FuncBlock(flux.FunctionParams("r"),
+		body,
+		&ast.ReturnStatement{
+			Argument: flux.Object(
+				flux.Property("headers", flux.Identifier("headers")),
+				flux.Property("data", endpointBody),
+			),
+		},
+	)
+
+	props := []*ast.Property{}
+	props = append(props, flux.Property("data", flux.Identifier("notification")))
+	props = append(props, flux.Property("endpoint",
+		flux.Call(flux.Identifier("smtp_endpoint"), flux.Object(flux.Property("mapFn", endpointFn)))))
+
+	call := flux.Call(flux.Member("monitor", "notify"), flux.Object(props...))
+
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("all_statuses"), call))
+}
+
+type smtpAlias SMTP
+
+// MarshalJSON implement json.Marshaler interface.
+func (s SMTP) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			smtpAlias
+			Type string `json:"type"`
+		}{
+			smtpAlias: smtpAlias(s),
+			Type:      s.Type(),
+		})
+}
+
+// Valid returns where the config is valid.
+func (s SMTP) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(s.To) == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "SMTP To is invalid",
+		}
+	}
+	if _, err := s.Recipients(); err != nil {
+		return err
+	}
+	if s.SubjectTemplate == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "SMTP SubjectTemplate is invalid",
+		}
+	}
+	if s.BodyTemplate == "" && !s.MessageTemplateID.Valid() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "SMTP BodyTemplate is invalid",
+		}
+	}
+	return nil
+}
+
+// Type returns the type of the rule config.
+func (s SMTP) Type() string {
+	return "smtp"
+}
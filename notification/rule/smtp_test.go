@@ -0,0 +1,281 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/andreyvit/diff"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	influxTesting "github.com/influxdata/influxdb/v2/testing"
+)
+
+var _ influxdb.NotificationRule = &rule.SMTP{}
+
+func TestSMTP_GenerateFlux(t *testing.T) {
+	base := rule.Base{
+		ID:         1,
+		EndpointID: 3,
+		Name:       "foo",
+		Every:      mustDuration("1h"),
+		StatusRules: []notification.StatusRule{
+			{
+				CurrentLevel: notification.Warn,
+			},
+		},
+		TagRules: []notification.TagRule{
+			{
+				Tag: influxdb.Tag{
+					Key:   "foo",
+					Value: "bar",
+				},
+				Operator: influxdb.Equal,
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		rule     *rule.SMTP
+		endpoint influxdb.NotificationEndpoint
+		script   string
+	}{
+		{
+			name: "incompatible with endpoint",
+			endpoint: &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:   idPtr(3),
+					Name: "foo",
+				},
+				URL: "http://whatever",
+			},
+			rule: &rule.SMTP{
+				Base:            base,
+				To:              "oncall@example.com",
+				SubjectTemplate: "subject",
+				BodyTemplate:    "body",
+			},
+			script: "", //no script generater, because of incompatible endpoint
+		},
+		{
+			name: "relay without credentials",
+			endpoint: &endpoint.SMTP{
+				Base: endpoint.Base{
+					ID:   idPtr(3),
+					Name: "relay",
+				},
+				Host: "smtp.example.com",
+				Port: 25,
+				From: "alerts@example.com",
+			},
+			rule: &rule.SMTP{
+				Base:            base,
+				To:              "oncall@example.com",
+				SubjectTemplate: "${r._check_name} is ${r._level}",
+				BodyTemplate:    "${r._message}",
+			},
+			script: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+headers = {"Content-Type": "application/json"}
+smtp_endpoint = http["endpoint"](url: "smtp://smtp.example.com:25")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000003",
+	_notification_endpoint_name: "relay",
+}
+statuses = monitor["from"](start: -2h, fn: (r) =>
+	(r["foo"] == "bar"))
+warn = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "warn"))
+all_statuses = warn
+	|> filter(fn: (r) =>
+		(r["_time"] >= experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: smtp_endpoint(mapFn: (r) => {
+		body = {
+			from: "alerts@example.com",
+			to: ["oncall@example.com"],
+			subject: "${r._check_name} is ${r._level}",
+			body: "${r._message}",
+		}
+
+		return {headers: headers, data: json["encode"](v: body)}
+	}))`,
+		},
+		{
+			name: "relay with credentials and starttls",
+			endpoint: &endpoint.SMTP{
+				Base: endpoint.Base{
+					ID:   idPtr(3),
+					Name: "relay",
+				},
+				Host:     "smtp.example.com",
+				Port:     587,
+				StartTLS: true,
+				Username: influxdb.SecretField{Key: "0000000000000003-username"},
+				Password: influxdb.SecretField{Key: "0000000000000003-password"},
+				From:     "alerts@example.com",
+			},
+			rule: &rule.SMTP{
+				Base:            base,
+				To:              "On Call <oncall@example.com>, ops@example.com",
+				SubjectTemplate: "${r._check_name} is ${r._level}",
+				BodyTemplate:    "${r._message}",
+			},
+			script: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "experimental"
+import "influxdata/influxdb/secrets"
+
+option task = {name: "foo", every: 1h}
+
+headers = {"Content-Type": "application/json", "Authorization": http["basicAuth"](u: secrets["get"](key: "0000000000000003-username"), p: secrets["get"](key: "0000000000000003-password"))}
+smtp_endpoint = http["endpoint"](url: "smtp://smtp.example.com:587?starttls=true")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000003",
+	_notification_endpoint_name: "relay",
+}
+statuses = monitor["from"](start: -2h, fn: (r) =>
+	(r["foo"] == "bar"))
+warn = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "warn"))
+all_statuses = warn
+	|> filter(fn: (r) =>
+		(r["_time"] >= experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: smtp_endpoint(mapFn: (r) => {
+		body = {
+			from: "alerts@example.com",
+			to: ["oncall@example.com", "ops@example.com"],
+			subject: "${r._check_name} is ${r._level}",
+			body: "${r._message}",
+		}
+
+		return {headers: headers, data: json["encode"](v: body)}
+	}))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, err := tt.rule.GenerateFlux(tt.endpoint)
+			if err != nil {
+				if script != "" {
+					t.Errorf("Failed to generate flux: %v", err)
+				}
+				return
+			}
+
+			if got, want := script, tt.script; got != want {
+				t.Errorf("\n\nStrings do not match:\n\n%s", diff.LineDiff(got, want))
+			}
+		})
+	}
+}
+
+func TestSMTP_Valid(t *testing.T) {
+	base := rule.Base{
+		ID:         1,
+		EndpointID: 3,
+		OwnerID:    4,
+		OrgID:      5,
+		Name:       "foo",
+		Every:      mustDuration("1h"),
+		StatusRules: []notification.StatusRule{
+			{
+				CurrentLevel: notification.Warn,
+			},
+		},
+		TagRules: []notification.TagRule{},
+	}
+
+	cases := []struct {
+		name string
+		rule *rule.SMTP
+		err  error
+	}{
+		{
+			name: "valid templates",
+			rule: &rule.SMTP{
+				Base:            base,
+				To:              "oncall@example.com",
+				SubjectTemplate: "subject",
+				BodyTemplate:    "body",
+			},
+			err: nil,
+		},
+		{
+			name: "missing recipients",
+			rule: &rule.SMTP{
+				Base:            base,
+				SubjectTemplate: "subject",
+				BodyTemplate:    "body",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "SMTP To is invalid",
+			},
+		},
+		{
+			name: "invalid recipient",
+			rule: &rule.SMTP{
+				Base:            base,
+				To:              "oncall",
+				SubjectTemplate: "subject",
+				BodyTemplate:    "body",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `SMTP To "oncall" is not a list of email addresses`,
+			},
+		},
+		{
+			name: "missing SubjectTemplate",
+			rule: &rule.SMTP{
+				Base:         base,
+				To:           "oncall@example.com",
+				BodyTemplate: "body",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "SMTP SubjectTemplate is invalid",
+			},
+		},
+		{
+			name: "missing BodyTemplate",
+			rule: &rule.SMTP{
+				Base:            base,
+				To:              "oncall@example.com",
+				SubjectTemplate: "subject",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "SMTP BodyTemplate is invalid",
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.rule.Valid()
+			influxTesting.ErrorsEqual(t, got, c.err)
+		})
+	}
+}
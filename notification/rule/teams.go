@@ -0,0 +1,140 @@
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// Teams is the notification rule config of Microsoft Teams.
+type Teams struct {
+	Base
+	Title           string `json:"title"`
+	MessageTemplate string `json:"messageTemplate"`
+	// Summary is shown in notifications, it defaults to the beginning of the message.
+	Summary string `json:"summary,omitempty"`
+}
+
+// GenerateFlux generates a flux script for the teams notification rule.
+func (s *Teams) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
+	teamsEndpoint, ok := e.(*endpoint.Teams)
+	if !ok {
+		return "", fmt.Errorf("endpoint provided is a %s, not a Teams endpoint", e.Type())
+	}
+	p, err := s.GenerateFluxAST(teamsEndpoint)
+	if err != nil {
+		return "", err
+	}
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST generates a flux AST for the teams notification rule.
+func (s *Teams) GenerateFluxAST(e *endpoint.Teams) (*ast.Package, error) {
+	packages := []string{"influxdata/influxdb/monitor", "contrib/sranka/teams"}
+	if e.SecretURLSuffix.Key != "" {
+		packages = append(packages, "influxdata/influxdb/secrets")
+	}
+	packages = append(packages, "experimental")
+
+	f := flux.File(
+		s.Name,
+		s.generateImports(packages...),
+		s.generateFluxASTBody(e),
+	)
+	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
+}
+
+func (s *Teams) generateFluxASTBody(e *endpoint.Teams) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, s.generateTaskOption())
+	if e.SecretURLSuffix.Key != "" {
+		statements = append(statements, s.generateFluxASTSecrets(e))
+	}
+	statements = append(statements, s.generateFluxASTEndpoint(e))
+	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
+	statements = append(statements, s.generateFluxASTStatuses())
+	statements = append(statements, s.generateLevelChecks()...)
+	statements = append(statements, s.generateFluxASTNotifyPipe())
+	statements = append(statements, s.generateEscalations()...)
+
+	return statements
+}
+
+func (s *Teams) generateFluxASTSecrets(e *endpoint.Teams) ast.Statement {
+	call := flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(e.SecretURLSuffix.Key))))
+
+	return flux.DefineVariable("teams_url_suffix", call)
+}
+
+func (s *Teams) generateFluxASTEndpoint(e *endpoint.Teams) ast.Statement {
+	var url ast.Expression = flux.String(e.URL)
+	if e.SecretURLSuffix.Key != "" {
+		url = flux.Add(url, flux.Identifier("teams_url_suffix"))
+	}
+	if s.Retry != nil {
+		send := flux.Function(flux.FunctionParams("r", "obj"), flux.Call(flux.Member("teams", "message"), flux.Object(
+			flux.Property("url", url),
+			flux.Property("title", flux.Member("obj", "title")),
+			flux.Property("text", flux.Member("obj", "text")),
+			flux.Property("summary", flux.Member("obj", "summary")),
+		)))
+		return s.generateDeliveryEndpoint("teams_endpoint", send, flux.Member("obj", "text"))
+	}
+	call := flux.Call(flux.Member("teams", "endpoint"), flux.Object(flux.Property("url", url)))
+
+	return flux.DefineVariable("teams_endpoint", call)
+}
+
+func (s *Teams) generateFluxASTNotifyPipe() ast.Statement {
+	endpointProps := []*ast.Property{}
+	endpointProps = append(endpointProps, flux.Property("title", flux.String(s.Title)))
+	endpointProps = append(endpointProps, flux.Property("text", s.message(flux.String(s.MessageTemplate))))
+	endpointProps = append(endpointProps, flux.Property("summary", flux.String(s.Summary)))
+	endpointFn := flux.Function(flux.FunctionParams("r"), flux.Object(endpointProps...))
+
+	props := []*ast.Property{}
+	props = append(props, flux.Property("data", flux.Identifier("notification")))
+	props = append(props, flux.Property("endpoint",
+		flux.Call(flux.Identifier("teams_endpoint"), flux.Object(flux.Property("mapFn", endpointFn)))))
+
+	call := flux.Call(flux.Member("monitor", "notify"), flux.Object(props...))
+
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("all_statuses"), call))
+}
+
+type teamsAlias Teams
+
+// MarshalJSON implement json.Marshaler interface.
+func (s Teams) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			teamsAlias
+			Type string `json:"type"`
+		}{
+			teamsAlias: teamsAlias(s),
+			Type:       s.Type(),
+		})
+}
+
+// Valid returns where the config is valid.
+func (s Teams) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.MessageTemplate == "" && !s.MessageTemplateID.Valid() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Teams MessageTemplate is invalid",
+		}
+	}
+	return nil
+}
+
+// Type returns the type of the rule config.
+func (s Teams) Type() string {
+	return "teams"
+}
@@ -0,0 +1,213 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/andreyvit/diff"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	influxTesting "github.com/influxdata/influxdb/v2/testing"
+)
+
+var _ influxdb.NotificationRule = &rule.Teams{}
+
+func TestTeams_GenerateFlux(t *testing.T) {
+	base := rule.Base{
+		ID:         1,
+		EndpointID: 3,
+		Name:       "foo",
+		Every:      mustDuration("1h"),
+		StatusRules: []notification.StatusRule{
+			{
+				CurrentLevel: notification.Critical,
+			},
+		},
+		TagRules: []notification.TagRule{
+			{
+				Tag: influxdb.Tag{
+					Key:   "foo",
+					Value: "bar",
+				},
+				Operator: influxdb.Equal,
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		rule     *rule.Teams
+		endpoint influxdb.NotificationEndpoint
+		script   string
+	}{
+		{
+			name: "incompatible with endpoint",
+			endpoint: &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:   idPtr(3),
+					Name: "foo",
+				},
+				URL: "http://whatever",
+			},
+			rule: &rule.Teams{
+				Base:            base,
+				MessageTemplate: "blah",
+			},
+			script: "", //no script generater, because of incompatible endpoint
+		},
+		{
+			name: "notify on crit",
+			endpoint: &endpoint.Teams{
+				Base: endpoint.Base{
+					ID:   idPtr(3),
+					Name: "foo",
+				},
+				URL: "https://outlook.office.com/webhook/",
+			},
+			rule: &rule.Teams{
+				Base:            base,
+				Title:           "${r._check_name}",
+				MessageTemplate: "blah",
+			},
+			script: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "contrib/sranka/teams"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+teams_endpoint = teams["endpoint"](url: "https://outlook.office.com/webhook/")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000003",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h, fn: (r) =>
+	(r["foo"] == "bar"))
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] >= experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: teams_endpoint(mapFn: (r) =>
+		({title: "${r._check_name}", text: "blah", summary: ""})))`,
+		},
+		{
+			name: "with secret URL suffix and summary",
+			endpoint: &endpoint.Teams{
+				Base: endpoint.Base{
+					ID:   idPtr(3),
+					Name: "foo",
+				},
+				URL:             "https://outlook.office.com/webhook/",
+				SecretURLSuffix: influxdb.SecretField{Key: "3-secretURLSuffix"},
+			},
+			rule: &rule.Teams{
+				Base:            base,
+				Title:           "t",
+				MessageTemplate: "blah",
+				Summary:         "s",
+			},
+			script: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "contrib/sranka/teams"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+teams_url_suffix = secrets["get"](key: "3-secretURLSuffix")
+teams_endpoint = teams["endpoint"](url: "https://outlook.office.com/webhook/" + teams_url_suffix)
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000003",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h, fn: (r) =>
+	(r["foo"] == "bar"))
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] >= experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: teams_endpoint(mapFn: (r) =>
+		({title: "t", text: "blah", summary: "s"})))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, err := tt.rule.GenerateFlux(tt.endpoint)
+			if err != nil {
+				if script != "" {
+					t.Errorf("Failed to generate flux: %v", err)
+				}
+				return
+			}
+
+			if got, want := script, tt.script; got != want {
+				t.Errorf("\n\nStrings do not match:\n\n%s", diff.LineDiff(got, want))
+			}
+		})
+	}
+}
+
+func TestTeams_Valid(t *testing.T) {
+	base := rule.Base{
+		ID:         1,
+		EndpointID: 3,
+		OwnerID:    4,
+		OrgID:      5,
+		Name:       "foo",
+		Every:      mustDuration("1h"),
+		StatusRules: []notification.StatusRule{
+			{
+				CurrentLevel: notification.Critical,
+			},
+		},
+		TagRules: []notification.TagRule{},
+	}
+
+	cases := []struct {
+		name string
+		rule *rule.Teams
+		err  error
+	}{
+		{
+			name: "valid template",
+			rule: &rule.Teams{
+				Base:            base,
+				MessageTemplate: "blah",
+			},
+			err: nil,
+		},
+		{
+			name: "missing MessageTemplate",
+			rule: &rule.Teams{
+				Base:  base,
+				Title: "blah",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Teams MessageTemplate is invalid",
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.rule.Valid()
+			influxTesting.ErrorsEqual(t, got, c.err)
+		})
+	}
+}
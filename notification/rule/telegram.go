@@ -35,7 +35,7 @@ func (s *Telegram) GenerateFlux(e influxdb.NotificationEndpoint) (string, error)
 func (s *Telegram) GenerateFluxAST(e *endpoint.Telegram) (*ast.Package, error) {
 	f := flux.File(
 		s.Name,
-		flux.Imports("influxdata/influxdb/monitor", "contrib/sranka/telegram", "influxdata/influxdb/secrets", "experimental"),
+		s.generateImports("influxdata/influxdb/monitor", "contrib/sranka/telegram", "influxdata/influxdb/secrets", "experimental"),
 		s.generateFluxASTBody(e),
 	)
 	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
@@ -52,6 +52,7 @@ func (s *Telegram) generateFluxASTBody(e *endpoint.Telegram) []ast.Statement {
 	statements = append(statements, s.generateFluxASTStatuses())
 	statements = append(statements, s.generateLevelChecks()...)
 	statements = append(statements, s.generateFluxASTNotifyPipe(e))
+	statements = append(statements, s.generateEscalations()...)
 
 	return statements
 }
@@ -71,6 +72,15 @@ func (s *Telegram) generateFluxASTEndpoint(e *endpoint.Telegram) ast.Statement {
 		props = append(props, flux.Property("parseMode", flux.String(s.ParseMode)))
 	}
 	props = append(props, flux.Property("disableWebPagePreview", flux.Bool(s.DisableWebPagePreview)))
+	if s.Retry != nil {
+		props = append(props,
+			flux.Property("channel", flux.Member("obj", "channel")),
+			flux.Property("text", flux.Member("obj", "text")),
+			flux.Property("silent", flux.Member("obj", "silent")),
+		)
+		send := flux.Function(flux.FunctionParams("r", "obj"), flux.Call(flux.Member("telegram", "message"), flux.Object(props...)))
+		return s.generateDeliveryEndpoint("telegram_endpoint", send, flux.Member("obj", "text"))
+	}
 	call := flux.Call(flux.Member("telegram", "endpoint"), flux.Object(props...))
 
 	return flux.DefineVariable("telegram_endpoint", call)
@@ -79,7 +89,7 @@ func (s *Telegram) generateFluxASTEndpoint(e *endpoint.Telegram) ast.Statement {
 func (s *Telegram) generateFluxASTNotifyPipe(e *endpoint.Telegram) ast.Statement {
 	endpointProps := []*ast.Property{}
 	endpointProps = append(endpointProps, flux.Property("channel", flux.String(e.Channel)))
-	endpointProps = append(endpointProps, flux.Property("text", flux.String(s.MessageTemplate)))
+	endpointProps = append(endpointProps, flux.Property("text", s.message(flux.String(s.MessageTemplate))))
 	endpointProps = append(endpointProps, flux.Property("silent", s.generateSilent()))
 	endpointFn := flux.Function(flux.FunctionParams("r"), flux.Object(endpointProps...))
 
@@ -125,7 +135,7 @@ func (s Telegram) Valid() error {
 	if err := s.Base.valid(); err != nil {
 		return err
 	}
-	if s.MessageTemplate == "" {
+	if s.MessageTemplate == "" && !s.MessageTemplateID.Valid() {
 		return &influxdb.Error{
 			Code: influxdb.EInvalid,
 			Msg:  "Telegram MessageTemplate is invalid",
@@ -0,0 +1,30 @@
+package silence
+
+import (
+	"github.com/influxdata/influxdb/v2"
+)
+
+var (
+	// ErrSilenceNotFound is used when the silence is not found.
+	ErrSilenceNotFound = &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "silence not found",
+	}
+)
+
+// ErrInvalidSilenceID is used when the ID of a silence cannot be encoded.
+func ErrInvalidSilenceID(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "invalid silence ID",
+		Err:  err,
+	}
+}
+
+// ErrInternalService is used when the error comes from an internal system.
+func ErrInternalService(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Err:  err,
+	}
+}
@@ -0,0 +1,175 @@
+package silence
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/influxdata/influxdb/v2"
+	pctx "github.com/influxdata/influxdb/v2/context"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"go.uber.org/zap"
+)
+
+// PrefixSilences is the prefix of the silence API.
+const PrefixSilences = "/api/v2/silences"
+
+// Handler serves the silences of notifications.
+type Handler struct {
+	chi.Router
+	api        *kithttp.API
+	log        *zap.Logger
+	silenceSvc influxdb.SilenceService
+}
+
+// NewHTTPHandler constructs a new http server for silences.
+func NewHTTPHandler(log *zap.Logger, svc influxdb.SilenceService) *Handler {
+	h := &Handler{
+		api:        kithttp.NewAPI(kithttp.WithLog(log)),
+		log:        log,
+		silenceSvc: svc,
+	}
+
+	r := chi.NewRouter()
+	r.Use(
+		middleware.Recoverer,
+		middleware.RequestID,
+		middleware.RealIP,
+	)
+
+	r.Route("/", func(r chi.Router) {
+		r.Get("/", h.handleGetSilences)
+		r.Post("/", h.handlePostSilence)
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h.handleGetSilence)
+			r.Patch("/", h.handlePatchSilence)
+			r.Delete("/", h.handleDeleteSilence)
+		})
+	})
+
+	h.Router = r
+	return h
+}
+
+// Prefix returns the prefix the handler is mounted at.
+func (h *Handler) Prefix() string {
+	return PrefixSilences
+}
+
+type silencesResponse struct {
+	Silences []*influxdb.Silence `json:"silences"`
+}
+
+// handleGetSilences is the HTTP handler for the GET /api/v2/silences route.
+// The orgID query parameter restricts the silences to an organization and
+// active=true to the silences that are active now.
+func (h *Handler) handleGetSilences(w http.ResponseWriter, r *http.Request) {
+	var filter influxdb.SilenceFilter
+	q := r.URL.Query()
+	if orgID := q.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			h.api.Err(w, r, err)
+			return
+		}
+		filter.OrgID = id
+	}
+	if q.Get("active") == "true" {
+		now := time.Now().UTC()
+		filter.Active = &now
+	}
+
+	sls, err := h.silenceSvc.FindSilences(r.Context(), filter)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Silences retrieved", zap.Int("count", len(sls)))
+
+	h.api.Respond(w, r, http.StatusOK, silencesResponse{Silences: sls})
+}
+
+// handlePostSilence is the HTTP handler for the POST /api/v2/silences route.
+func (h *Handler) handlePostSilence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var sl influxdb.Silence
+	if err := h.api.DecodeJSON(r.Body, &sl); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	auth, err := pctx.GetAuthorizer(ctx)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	sl.CreatedBy = auth.GetUserID()
+
+	if err := h.silenceSvc.CreateSilence(ctx, &sl); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Silence created", zap.String("id", sl.ID.String()))
+
+	h.api.Respond(w, r, http.StatusCreated, sl)
+}
+
+// handleGetSilence is the HTTP handler for the GET /api/v2/silences/:id route.
+func (h *Handler) handleGetSilence(w http.ResponseWriter, r *http.Request) {
+	id, err := influxdb.IDFromString(chi.URLParam(r, "id"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	sl, err := h.silenceSvc.FindSilenceByID(r.Context(), *id)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	h.api.Respond(w, r, http.StatusOK, sl)
+}
+
+// handlePatchSilence is the HTTP handler for the PATCH /api/v2/silences/:id route.
+func (h *Handler) handlePatchSilence(w http.ResponseWriter, r *http.Request) {
+	id, err := influxdb.IDFromString(chi.URLParam(r, "id"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	var upd influxdb.SilenceUpdate
+	if err := h.api.DecodeJSON(r.Body, &upd); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	sl, err := h.silenceSvc.UpdateSilence(r.Context(), *id, upd)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Silence updated", zap.String("id", id.String()))
+
+	h.api.Respond(w, r, http.StatusOK, sl)
+}
+
+// handleDeleteSilence is the HTTP handler for the DELETE /api/v2/silences/:id route.
+func (h *Handler) handleDeleteSilence(w http.ResponseWriter, r *http.Request) {
+	id, err := influxdb.IDFromString(chi.URLParam(r, "id"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	if err := h.silenceSvc.DeleteSilence(r.Context(), *id); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Silence deleted", zap.String("id", id.String()))
+
+	h.api.Respond(w, r, http.StatusNoContent, nil)
+}
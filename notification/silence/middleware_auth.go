@@ -0,0 +1,94 @@
+package silence
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+)
+
+var _ influxdb.SilenceService = (*AuthorizedService)(nil)
+
+// AuthorizedService restricts access to silences. Silences mute notification
+// rules, so they require the same permissions as the notification rules of
+// their organization.
+type AuthorizedService struct {
+	s influxdb.SilenceService
+}
+
+// NewAuthorizedService wraps a silence service with authorization checks.
+func NewAuthorizedService(s influxdb.SilenceService) *AuthorizedService {
+	return &AuthorizedService{s: s}
+}
+
+// FindSilenceByID checks that the authorizer may read the notification rules of the silence organization.
+func (s *AuthorizedService) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	sl, err := s.s.FindSilenceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := authorizer.AuthorizeOrgReadResource(ctx, influxdb.NotificationRuleResourceType, sl.OrgID); err != nil {
+		return nil, err
+	}
+	return sl, nil
+}
+
+// FindSilences returns only the silences of organizations whose notification rules the authorizer may read.
+func (s *AuthorizedService) FindSilences(ctx context.Context, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+	if filter.OrgID != nil {
+		if _, _, err := authorizer.AuthorizeOrgReadResource(ctx, influxdb.NotificationRuleResourceType, *filter.OrgID); err != nil {
+			return nil, err
+		}
+	}
+	sls, err := s.s.FindSilences(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	ssls := sls[:0]
+	for _, sl := range sls {
+		_, _, err := authorizer.AuthorizeOrgReadResource(ctx, influxdb.NotificationRuleResourceType, sl.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		ssls = append(ssls, sl)
+	}
+	return ssls, nil
+}
+
+// CreateSilence checks that the authorizer may write the notification rules of the organization.
+func (s *AuthorizedService) CreateSilence(ctx context.Context, sl *influxdb.Silence) error {
+	if _, _, err := authorizer.AuthorizeCreate(ctx, influxdb.NotificationRuleResourceType, sl.OrgID); err != nil {
+		return err
+	}
+	return s.s.CreateSilence(ctx, sl)
+}
+
+// UpdateSilence checks that the authorizer may write the notification rules of the silence organization.
+func (s *AuthorizedService) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	sl, err := s.s.FindSilenceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := authorizer.AuthorizeOrgWriteResource(ctx, influxdb.NotificationRuleResourceType, sl.OrgID); err != nil {
+		return nil, err
+	}
+	return s.s.UpdateSilence(ctx, id, upd)
+}
+
+// DeleteSilence checks that the authorizer may write the notification rules of the silence organization.
+func (s *AuthorizedService) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	sl, err := s.s.FindSilenceByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, _, err := authorizer.AuthorizeOrgWriteResource(ctx, influxdb.NotificationRuleResourceType, sl.OrgID); err != nil {
+		return err
+	}
+	return s.s.DeleteSilence(ctx, id)
+}
@@ -0,0 +1,196 @@
+package silence
+
+// The silence `Service` stores the silences of every organization in a single
+// kv bucket keyed by the encoded silence ID. Silences are only stored here, the
+// notification rule tasks of an organization are regenerated with its silences
+// by the SyncingService of this package.
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/snowflake"
+)
+
+var silenceBucket = []byte("silencesv1")
+
+var _ influxdb.SilenceService = (*Service)(nil)
+
+// Service is the kv backed implementation of influxdb.SilenceService.
+type Service struct {
+	store       kv.Store
+	idGenerator influxdb.IDGenerator
+	now         func() time.Time
+}
+
+// NewService returns a silence service backed by the provided kv store.
+func NewService(st kv.Store) *Service {
+	return &Service{
+		store:       st,
+		idGenerator: snowflake.NewIDGenerator(),
+		now:         time.Now,
+	}
+}
+
+// FindSilenceByID returns a single silence by ID.
+func (s *Service) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	key, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidSilenceID(err)
+	}
+
+	var sl *influxdb.Silence
+	err = s.store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(silenceBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		sl, err = getSilence(b, key)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sl, nil
+}
+
+// FindSilences returns the silences matching the filter.
+func (s *Service) FindSilences(ctx context.Context, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+	sls := []*influxdb.Silence{}
+	err := s.store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(silenceBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		cur, err := b.ForwardCursor(nil)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		defer cur.Close()
+
+		for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+			sl := &influxdb.Silence{}
+			if err := json.Unmarshal(v, sl); err != nil {
+				return ErrInternalService(err)
+			}
+			if filter.OrgID != nil && sl.OrgID != *filter.OrgID {
+				continue
+			}
+			if filter.Active != nil && !sl.ActiveAt(*filter.Active) {
+				continue
+			}
+			sls = append(sls, sl)
+		}
+		return cur.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sls, nil
+}
+
+// CreateSilence creates a new silence and sets s.ID with the new identifier.
+func (s *Service) CreateSilence(ctx context.Context, sl *influxdb.Silence) error {
+	if err := sl.Valid(); err != nil {
+		return err
+	}
+
+	sl.ID = s.idGenerator.ID()
+	now := s.now().UTC()
+	sl.CreatedAt = now
+	sl.UpdatedAt = now
+
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		return putSilence(tx, sl)
+	})
+}
+
+// UpdateSilence updates a single silence and returns the new silence.
+func (s *Service) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	key, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidSilenceID(err)
+	}
+
+	var sl *influxdb.Silence
+	err = s.store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(silenceBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		if sl, err = getSilence(b, key); err != nil {
+			return err
+		}
+
+		upd.Apply(sl)
+		if err := sl.Valid(); err != nil {
+			return err
+		}
+		sl.UpdatedAt = s.now().UTC()
+		return putSilence(tx, sl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sl, nil
+}
+
+// DeleteSilence removes a silence by ID.
+func (s *Service) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	key, err := id.Encode()
+	if err != nil {
+		return ErrInvalidSilenceID(err)
+	}
+
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(silenceBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		if _, err := getSilence(b, key); err != nil {
+			return err
+		}
+		if err := b.Delete(key); err != nil {
+			return ErrInternalService(err)
+		}
+		return nil
+	})
+}
+
+func getSilence(b kv.Bucket, key []byte) (*influxdb.Silence, error) {
+	v, err := b.Get(key)
+	if kv.IsNotFound(err) {
+		return nil, ErrSilenceNotFound
+	}
+	if err != nil {
+		return nil, ErrInternalService(err)
+	}
+
+	sl := &influxdb.Silence{}
+	if err := json.Unmarshal(v, sl); err != nil {
+		return nil, ErrInternalService(err)
+	}
+	return sl, nil
+}
+
+func putSilence(tx kv.Tx, sl *influxdb.Silence) error {
+	key, err := sl.ID.Encode()
+	if err != nil {
+		return ErrInvalidSilenceID(err)
+	}
+	b, err := tx.Bucket(silenceBucket)
+	if err != nil {
+		return ErrInternalService(err)
+	}
+	v, err := json.Marshal(sl)
+	if err != nil {
+		return ErrInternalService(err)
+	}
+	if err := b.Put(key, v); err != nil {
+		return ErrInternalService(err)
+	}
+	return nil
+}
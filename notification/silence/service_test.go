@@ -0,0 +1,92 @@
+package silence_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kv/migration/all"
+	"github.com/influxdata/influxdb/v2/notification/silence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestService(t *testing.T) *silence.Service {
+	t.Helper()
+
+	store := inmem.NewKVStore()
+	if err := all.Up(context.Background(), zaptest.NewLogger(t), store); err != nil {
+		t.Fatal(err)
+	}
+	return silence.NewService(store)
+}
+
+type ruleSyncer struct {
+	orgs []influxdb.ID
+}
+
+func (s *ruleSyncer) SyncNotificationRuleTasks(ctx context.Context, orgID influxdb.ID) error {
+	s.orgs = append(s.orgs, orgID)
+	return nil
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	syncer := &ruleSyncer{}
+	svc := silence.NewSyncingService(newTestService(t), syncer)
+
+	now := time.Now().UTC()
+	end := now.Add(time.Hour)
+	matchers := []influxdb.TagRule{{Tag: influxdb.Tag{Key: "host", Value: "a"}, Operator: influxdb.Equal}}
+
+	err := svc.CreateSilence(ctx, &influxdb.Silence{OrgID: 1, Matchers: matchers})
+	assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+	assert.Empty(t, syncer.orgs)
+
+	maintenance := &influxdb.Silence{OrgID: 1, Matchers: matchers, StartsAt: &now, EndsAt: &end, Comment: "upgrade", CreatedBy: 3}
+	require.NoError(t, svc.CreateSilence(ctx, maintenance))
+	assert.True(t, maintenance.ID.Valid())
+	weekly := &influxdb.Silence{OrgID: 2, Matchers: matchers, Recurrence: &influxdb.SilenceRecurrence{
+		Days:  []string{"sunday"},
+		Start: "02:00",
+		End:   "04:00",
+	}}
+	require.NoError(t, svc.CreateSilence(ctx, weekly))
+	assert.Equal(t, []influxdb.ID{1, 2}, syncer.orgs)
+
+	sl, err := svc.FindSilenceByID(ctx, maintenance.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "upgrade", sl.Comment)
+	assert.Equal(t, influxdb.ID(3), sl.CreatedBy)
+
+	orgID := influxdb.ID(1)
+	sls, err := svc.FindSilences(ctx, influxdb.SilenceFilter{OrgID: &orgID})
+	require.NoError(t, err)
+	require.Len(t, sls, 1)
+	assert.Equal(t, maintenance.ID, sls[0].ID)
+
+	later := now.Add(2 * time.Hour)
+	sls, err = svc.FindSilences(ctx, influxdb.SilenceFilter{OrgID: &orgID, Active: &later})
+	require.NoError(t, err)
+	assert.Empty(t, sls)
+
+	comment := "database upgrade"
+	sl, err = svc.UpdateSilence(ctx, maintenance.ID, influxdb.SilenceUpdate{EndsAt: &later, Comment: &comment})
+	require.NoError(t, err)
+	assert.Equal(t, comment, sl.Comment)
+	assert.True(t, sl.ActiveAt(later.Add(-time.Minute)))
+	assert.Equal(t, []influxdb.ID{1, 2, 1}, syncer.orgs)
+
+	_, err = svc.UpdateSilence(ctx, maintenance.ID, influxdb.SilenceUpdate{EndsAt: &now})
+	assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+
+	require.NoError(t, svc.DeleteSilence(ctx, weekly.ID))
+	assert.Equal(t, []influxdb.ID{1, 2, 1, 2}, syncer.orgs)
+	_, err = svc.FindSilenceByID(ctx, weekly.ID)
+	assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+	err = svc.DeleteSilence(ctx, weekly.ID)
+	assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+}
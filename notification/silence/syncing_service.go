@@ -0,0 +1,64 @@
+package silence
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+// RuleSyncer regenerates the notification rule tasks of an organization.
+type RuleSyncer interface {
+	// SyncNotificationRuleTasks regenerates the tasks of the notification
+	// rules of the organization with its current silences and alerts.
+	SyncNotificationRuleTasks(ctx context.Context, orgID influxdb.ID) error
+}
+
+var _ influxdb.SilenceService = (*SyncingService)(nil)
+
+// SyncingService regenerates the notification rule tasks of an organization
+// whenever one of its silences changes, the silences are compiled into the
+// Flux of the tasks.
+type SyncingService struct {
+	influxdb.SilenceService
+	rules RuleSyncer
+}
+
+// NewSyncingService wraps a silence service so that changes are applied to the notification rule tasks.
+func NewSyncingService(s influxdb.SilenceService, rules RuleSyncer) *SyncingService {
+	return &SyncingService{
+		SilenceService: s,
+		rules:          rules,
+	}
+}
+
+// CreateSilence creates the silence and regenerates the tasks of its organization.
+func (s *SyncingService) CreateSilence(ctx context.Context, sl *influxdb.Silence) error {
+	if err := s.SilenceService.CreateSilence(ctx, sl); err != nil {
+		return err
+	}
+	return s.rules.SyncNotificationRuleTasks(ctx, sl.OrgID)
+}
+
+// UpdateSilence updates the silence and regenerates the tasks of its organization.
+func (s *SyncingService) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	sl, err := s.SilenceService.UpdateSilence(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.rules.SyncNotificationRuleTasks(ctx, sl.OrgID); err != nil {
+		return nil, err
+	}
+	return sl, nil
+}
+
+// DeleteSilence removes the silence and regenerates the tasks of its organization.
+func (s *SyncingService) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	sl, err := s.SilenceService.FindSilenceByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.SilenceService.DeleteSilence(ctx, id); err != nil {
+		return err
+	}
+	return s.rules.SyncNotificationRuleTasks(ctx, sl.OrgID)
+}
@@ -0,0 +1,275 @@
+// Package smtp delivers notification emails through an SMTP relay.
+//
+// Flux has no builtin for sending mail, so email notification rules post
+// their messages with http.endpoint to an smtp:// URL. The Client in this
+// package is installed as the http client of the flux dependencies and
+// hands those requests to the relay instead of issuing an HTTP request, for
+// the queries of the tasks of email notification rules only.
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scheme is the url scheme of requests handled by the Client.
+const Scheme = "smtp"
+
+// TaskType is the type of the tasks of email notification rules.
+const TaskType = "smtp"
+
+// ErrDeliveryNotAllowed is returned by clients of NewTaskClient for the
+// smtp:// requests of queries that are not run by email notification rules.
+var ErrDeliveryNotAllowed = errors.New("smtp urls can only be posted to by the tasks of email notification rules")
+
+type contextKey int
+
+const deliveryKey contextKey = iota
+
+// WithDelivery returns a context whose smtp:// requests are delivered by the
+// clients of NewTaskClient.
+func WithDelivery(ctx context.Context) context.Context {
+	return context.WithValue(ctx, deliveryKey, true)
+}
+
+func deliveryAllowed(ctx context.Context) bool {
+	ok, _ := ctx.Value(deliveryKey).(bool)
+	return ok
+}
+
+// maxMessageSize caps the size of the json payload of a single message.
+const maxMessageSize = 1 << 20
+
+// Message is the json payload posted by an email notification rule.
+type Message struct {
+	From    string   `json:"from"`
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	Body    string   `json:"body"`
+}
+
+// Relay describes how to reach an SMTP relay.
+type Relay struct {
+	Host     string
+	Port     int
+	StartTLS bool
+	Username string
+	Password string
+}
+
+// URL returns the smtp:// url for a relay, without credentials.
+// Credentials are carried in the basic auth header of the request.
+func URL(host string, port int, startTLS bool) string {
+	u := url.URL{
+		Scheme: Scheme,
+		Host:   net.JoinHostPort(host, strconv.Itoa(port)),
+	}
+	if startTLS {
+		u.RawQuery = "starttls=true"
+	}
+	return u.String()
+}
+
+// HTTPClient is the interface of the flux http dependency.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Client sends requests for smtp:// urls through an SMTP relay and passes
+// everything else to the wrapped client.
+type Client struct {
+	next HTTPClient
+	send func(ctx context.Context, r Relay, m Message) error
+	// restricted only delivers the requests with a context from WithDelivery.
+	restricted bool
+}
+
+// NewClient wraps next so that smtp:// requests are delivered as email.
+func NewClient(next HTTPClient) *Client {
+	return &Client{
+		next: next,
+		send: Send,
+	}
+}
+
+// NewTaskClient wraps next like NewClient, but only delivers the smtp://
+// requests made with a context from WithDelivery, so that queries other than
+// the ones of email notification rule tasks can not reach SMTP relays.
+func NewTaskClient(next HTTPClient) *Client {
+	c := NewClient(next)
+	c.restricted = true
+	return c
+}
+
+// Do implements HTTPClient. Delivery failures are reported as a 502
+// response rather than an error so that monitor.notify records the
+// notification as not sent instead of failing the whole task.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != Scheme {
+		return c.next.Do(req)
+	}
+	if c.restricted && !deliveryAllowed(req.Context()) {
+		return nil, ErrDeliveryNotAllowed
+	}
+
+	relay, err := relayFromRequest(req)
+	if err != nil {
+		return response(req, http.StatusBadRequest, err), nil
+	}
+
+	var msg Message
+	if req.Body != nil {
+		defer req.Body.Close()
+		if err := json.NewDecoder(io.LimitReader(req.Body, maxMessageSize)).Decode(&msg); err != nil {
+			return response(req, http.StatusBadRequest, fmt.Errorf("invalid email message: %v", err)), nil
+		}
+	}
+
+	if err := c.send(req.Context(), relay, msg); err != nil {
+		return response(req, http.StatusBadGateway, err), nil
+	}
+	return response(req, http.StatusOK, nil), nil
+}
+
+func relayFromRequest(req *http.Request) (Relay, error) {
+	host, portStr, err := net.SplitHostPort(req.URL.Host)
+	if err != nil {
+		return Relay{}, fmt.Errorf("invalid smtp relay address %q: %v", req.URL.Host, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return Relay{}, fmt.Errorf("invalid smtp relay port %q", portStr)
+	}
+
+	r := Relay{
+		Host: host,
+		Port: port,
+	}
+	r.StartTLS, _ = strconv.ParseBool(req.URL.Query().Get("starttls"))
+	r.Username, r.Password, _ = req.BasicAuth()
+	return r, nil
+}
+
+func response(req *http.Request, code int, err error) *http.Response {
+	var body []byte
+	if err != nil {
+		body = []byte(err.Error())
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// Send delivers m through the relay r.
+func Send(ctx context.Context, r Relay, m Message) error {
+	if err := m.valid(); err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(r.Host, strconv.Itoa(r.Port))
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(time.Minute))
+	}
+
+	c, err := smtp.NewClient(conn, r.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if r.StartTLS {
+		if err := c.StartTLS(&tls.Config{ServerName: r.Host}); err != nil {
+			return err
+		}
+	}
+	if r.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", r.Username, r.Password, r.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(m.From); err != nil {
+		return err
+	}
+	for _, to := range m.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(m.bytes(time.Now())); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (m Message) valid() error {
+	if m.From == "" {
+		return errors.New("email message has no sender")
+	}
+	if len(m.To) == 0 {
+		return errors.New("email message has no recipients")
+	}
+	for _, addr := range append([]string{m.From}, m.To...) {
+		if strings.ContainsAny(addr, "\r\n") {
+			return fmt.Errorf("invalid email address %q", addr)
+		}
+	}
+	return nil
+}
+
+// bytes renders the message as a plain text RFC 5322 message.
+func (m Message) bytes(now time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", m.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(m.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", headerValue(m.Subject)))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(m.Body)
+	return buf.Bytes()
+}
+
+// headerValue folds a template result onto a single header line.
+func headerValue(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
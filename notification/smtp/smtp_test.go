@@ -0,0 +1,259 @@
+package smtp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2/notification/smtp"
+)
+
+// relay is a minimal SMTP server that records the messages it receives.
+type relay struct {
+	ln net.Listener
+	wg sync.WaitGroup
+
+	mu       sync.Mutex
+	from     string
+	to       []string
+	data     string
+	rejectTo string
+}
+
+func newRelay(t *testing.T) *relay {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &relay{ln: ln}
+	r.wg.Add(1)
+	go r.serve()
+	t.Cleanup(func() {
+		ln.Close()
+		r.wg.Wait()
+	})
+	return r
+}
+
+func (r *relay) hostPort(t *testing.T) (string, int) {
+	t.Helper()
+	addr := r.ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func (r *relay) serve() {
+	defer r.wg.Done()
+	for {
+		conn, err := r.ln.Accept()
+		if err != nil {
+			return
+		}
+		r.handle(textproto.NewConn(conn))
+	}
+}
+
+func (r *relay) handle(c *textproto.Conn) {
+	defer c.Close()
+	_ = c.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		arg := strings.TrimSpace(strings.TrimPrefix(line, strings.SplitN(line, " ", 2)[0]))
+		switch cmd {
+		case "EHLO", "HELO":
+			_ = c.PrintfLine("250 localhost")
+		case "MAIL":
+			r.mu.Lock()
+			r.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+			r.mu.Unlock()
+			_ = c.PrintfLine("250 OK")
+		case "RCPT":
+			to := strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>")
+			if to == r.rejectTo {
+				_ = c.PrintfLine("550 no such user")
+				continue
+			}
+			r.mu.Lock()
+			r.to = append(r.to, to)
+			r.mu.Unlock()
+			_ = c.PrintfLine("250 OK")
+		case "DATA":
+			_ = c.PrintfLine("354 go ahead")
+			b, err := c.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			r.mu.Lock()
+			r.data = string(b)
+			r.mu.Unlock()
+			_ = c.PrintfLine("250 OK")
+		case "QUIT":
+			_ = c.PrintfLine("221 bye")
+			return
+		default:
+			_ = c.PrintfLine("250 OK")
+		}
+	}
+}
+
+type passthrough struct {
+	called bool
+}
+
+func (p *passthrough) Do(*http.Request) (*http.Response, error) {
+	p.called = true
+	return &http.Response{StatusCode: http.StatusNoContent}, nil
+}
+
+func post(t *testing.T, c smtp.HTTPClient, url string, msg smtp.Message) *http.Response {
+	t.Helper()
+	b, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestClient_Send(t *testing.T) {
+	r := newRelay(t)
+	host, port := r.hostPort(t)
+	next := &passthrough{}
+	c := smtp.NewClient(next)
+
+	resp := post(t, c, smtp.URL(host, port, false), smtp.Message{
+		From:    "alerts@example.com",
+		To:      []string{"oncall@example.com", "ops@example.com"},
+		Subject: "cpu\nis warn",
+		Body:    "usage is 91%\n.\nend",
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+	if next.called {
+		t.Fatal("smtp request was passed to the wrapped client")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.from != "alerts@example.com" {
+		t.Errorf("unexpected sender: %q", r.from)
+	}
+	if got := strings.Join(r.to, ","); got != "oncall@example.com,ops@example.com" {
+		t.Errorf("unexpected recipients: %q", got)
+	}
+	for _, want := range []string{
+		"From: alerts@example.com\n",
+		"To: oncall@example.com, ops@example.com\n",
+		"Subject: cpu is warn\n",
+		"\nusage is 91%\n.\nend",
+	} {
+		if !strings.Contains(r.data, want) {
+			t.Errorf("message is missing %q:\n%s", want, r.data)
+		}
+	}
+}
+
+func TestClient_SendFailure(t *testing.T) {
+	r := newRelay(t)
+	r.rejectTo = "nobody@example.com"
+	host, port := r.hostPort(t)
+	c := smtp.NewClient(&passthrough{})
+
+	resp := post(t, c, smtp.URL(host, port, false), smtp.Message{
+		From: "alerts@example.com",
+		To:   []string{"nobody@example.com"},
+		Body: "hi",
+	})
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	resp = post(t, c, smtp.URL(host, port, false), smtp.Message{
+		From: "alerts@example.com",
+		Body: "hi",
+	})
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("unexpected status code for message without recipients: %d", resp.StatusCode)
+	}
+}
+
+func TestClient_Passthrough(t *testing.T) {
+	next := &passthrough{}
+	c := smtp.NewClient(next)
+
+	resp := post(t, c, "http://localhost:7777", smtp.Message{})
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+	if !next.called {
+		t.Fatal("http request was not passed to the wrapped client")
+	}
+}
+
+func TestTaskClient(t *testing.T) {
+	r := newRelay(t)
+	host, port := r.hostPort(t)
+	c := smtp.NewTaskClient(&passthrough{})
+	msg := smtp.Message{
+		From: "alerts@example.com",
+		To:   []string{"oncall@example.com"},
+		Body: "hi",
+	}
+	b, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := http.NewRequest("POST", smtp.URL(host, port, false), bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Do(req); err != smtp.ErrDeliveryNotAllowed {
+		t.Fatalf("unexpected error for a query that is not a rule task: %v", err)
+	}
+
+	resp := post(t, c, "http://localhost:7777", smtp.Message{})
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("unexpected status code of a passed through request: %d", resp.StatusCode)
+	}
+
+	req, err = http.NewRequest("POST", smtp.URL(host, port, false), bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = c.Do(req.WithContext(smtp.WithDelivery(context.Background())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+}
+
+func TestURL(t *testing.T) {
+	if got, want := smtp.URL("mail.example.com", 587, true), "smtp://mail.example.com:587?starttls=true"; got != want {
+		t.Errorf("unexpected url: got %q, want %q", got, want)
+	}
+	if got, want := smtp.URL("::1", 25, false), "smtp://[::1]:25"; got != want {
+		t.Errorf("unexpected url: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,256 @@
+// Package template implements the language of the message templates of
+// checks and notification rules.
+//
+// A template is text with placeholders between double braces, such as
+//
+//	{{ check_name }} is {{ level }} on {{ tags.host }}: {{ fields.usage_user }}
+//
+// The placeholders are:
+//
+//	check_id, check_name  the check of the status
+//	level                 the level of the status
+//	measurement           the measurement the check queried
+//	time                  the time of the point the status was computed from
+//	message               the message of the status, in notification rules only
+//	runbook               the runbook URL of the template
+//	tags.<key>            the value of a tag of the status
+//	fields.<key>          the value of a field of the status
+//
+// Templates can only read the status, unlike the flux string interpolation of
+// the inline message templates they can't evaluate arbitrary expressions, and
+// the text around the placeholders is never interpolated.
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// placeholder names
+const (
+	CheckID     = "check_id"
+	CheckName   = "check_name"
+	Level       = "level"
+	Measurement = "measurement"
+	Time        = "time"
+	Message     = "message"
+	Runbook     = "runbook"
+	Tags        = "tags"
+	Fields      = "fields"
+)
+
+var keyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type placeholder struct {
+	name string
+	// key is the tag or field key of the tags and fields placeholders.
+	key string
+}
+
+type part struct {
+	text string
+	ph   *placeholder
+}
+
+// Template is a parsed message template.
+type Template struct {
+	parts []part
+}
+
+// Parse parses the text of a message template.
+func Parse(text string) (*Template, error) {
+	t := &Template{}
+	for text != "" {
+		i := strings.Index(text, "{{")
+		if i < 0 {
+			t.parts = append(t.parts, part{text: text})
+			break
+		}
+		if i > 0 {
+			t.parts = append(t.parts, part{text: text[:i]})
+		}
+		rest := text[i+2:]
+		j := strings.Index(rest, "}}")
+		if j < 0 {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "message template has an unclosed placeholder",
+			}
+		}
+		ph, err := parsePlaceholder(strings.TrimSpace(rest[:j]))
+		if err != nil {
+			return nil, err
+		}
+		t.parts = append(t.parts, part{ph: ph})
+		text = rest[j+2:]
+	}
+	return t, nil
+}
+
+func parsePlaceholder(s string) (*placeholder, error) {
+	switch s {
+	case CheckID, CheckName, Level, Measurement, Time, Message, Runbook:
+		return &placeholder{name: s}, nil
+	}
+	if i := strings.IndexByte(s, '.'); i > 0 {
+		name, key := s[:i], s[i+1:]
+		if name == Tags || name == Fields {
+			if !keyPattern.MatchString(key) {
+				return nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  fmt.Sprintf("message template key %q is invalid", key),
+				}
+			}
+			return &placeholder{name: name, key: key}, nil
+		}
+	}
+	return nil, &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  fmt.Sprintf("message template placeholder %q is unknown", s),
+	}
+}
+
+// UsesMessage returns true when the template renders the message of the
+// status, which only notification rules have.
+func (t *Template) UsesMessage() bool {
+	for _, p := range t.parts {
+		if p.ph != nil && p.ph.name == Message {
+			return true
+		}
+	}
+	return false
+}
+
+// Status is a status that a template is rendered against.
+type Status struct {
+	CheckID     string                 `json:"checkID"`
+	CheckName   string                 `json:"checkName"`
+	Level       string                 `json:"level"`
+	Measurement string                 `json:"measurement"`
+	Time        time.Time              `json:"time"`
+	Message     string                 `json:"message,omitempty"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Fields      map[string]interface{} `json:"fields,omitempty"`
+}
+
+// Render renders the template against the status.
+func (t *Template) Render(runbookURL string, s Status) (string, error) {
+	var sb strings.Builder
+	for _, p := range t.parts {
+		if p.ph == nil {
+			sb.WriteString(p.text)
+			continue
+		}
+		switch p.ph.name {
+		case CheckID:
+			sb.WriteString(s.CheckID)
+		case CheckName:
+			sb.WriteString(s.CheckName)
+		case Level:
+			sb.WriteString(s.Level)
+		case Measurement:
+			sb.WriteString(s.Measurement)
+		case Time:
+			sb.WriteString(s.Time.UTC().Format(time.RFC3339Nano))
+		case Message:
+			sb.WriteString(s.Message)
+		case Runbook:
+			sb.WriteString(runbookURL)
+		case Tags:
+			v, ok := s.Tags[p.ph.key]
+			if !ok {
+				return "", &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  fmt.Sprintf("status has no tag %q", p.ph.key),
+				}
+			}
+			sb.WriteString(v)
+		case Fields:
+			v, ok := s.Fields[p.ph.key]
+			if !ok {
+				return "", &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  fmt.Sprintf("status has no field %q", p.ph.key),
+				}
+			}
+			sb.WriteString(fmt.Sprint(v))
+		}
+	}
+	return sb.String(), nil
+}
+
+// Expression returns the flux string expression that renders the template
+// against the status r of a check or a notification rule.
+func (t *Template) Expression(runbookURL string) *ast.StringExpression {
+	e := &ast.StringExpression{}
+	var text strings.Builder
+	flushText := func() {
+		e.Parts = append(e.Parts, textParts(text.String())...)
+		text.Reset()
+	}
+	for _, p := range t.parts {
+		if p.ph == nil {
+			text.WriteString(p.text)
+			continue
+		}
+		if p.ph.name == Runbook {
+			text.WriteString(runbookURL)
+			continue
+		}
+		flushText()
+		e.Parts = append(e.Parts, &ast.InterpolatedPart{Expression: p.ph.expression()})
+	}
+	flushText()
+	return e
+}
+
+func (p *placeholder) expression() ast.Expression {
+	switch p.name {
+	case CheckID:
+		return flux.Member("r", "_check_id")
+	case CheckName:
+		return flux.Member("r", "_check_name")
+	case Level:
+		return flux.Member("r", "_level")
+	case Measurement:
+		return flux.Member("r", "_source_measurement")
+	case Time:
+		ts := flux.Call(flux.Identifier("time"), flux.Object(flux.Property("v", flux.Member("r", "_source_timestamp"))))
+		return flux.Call(flux.Identifier("string"), flux.Object(flux.Property("v", ts)))
+	case Message:
+		return flux.Member("r", "_message")
+	case Tags:
+		return flux.Member("r", p.key)
+	case Fields:
+		return flux.Call(flux.Identifier("string"), flux.Object(flux.Property("v", flux.Member("r", p.key))))
+	}
+	return flux.String("")
+}
+
+// textParts returns the parts of a flux string expression of the text. A "${"
+// of the text would start an interpolation, so its "$" is interpolated as a
+// string literal instead.
+func textParts(text string) []ast.StringExpressionPart {
+	var parts []ast.StringExpressionPart
+	for {
+		i := strings.Index(text, "${")
+		if i < 0 {
+			break
+		}
+		if i > 0 {
+			parts = append(parts, &ast.TextPart{Value: text[:i]})
+		}
+		parts = append(parts, &ast.InterpolatedPart{Expression: flux.String("$")})
+		text = text[i+1:]
+	}
+	if text != "" {
+		parts = append(parts, &ast.TextPart{Value: text})
+	}
+	return parts
+}
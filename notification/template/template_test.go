@@ -0,0 +1,109 @@
+package template_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		err  string
+	}{
+		{
+			name: "text",
+			text: "cpu is high",
+		},
+		{
+			name: "placeholders",
+			text: "{{check_name}} is {{ level }} on {{ tags.host }}: {{ fields.usage_user }} {{ runbook }}",
+		},
+		{
+			name: "unclosed placeholder",
+			text: "{{ check_name } is {{ level",
+			err:  "message template has an unclosed placeholder",
+		},
+		{
+			name: "unknown placeholder",
+			text: "{{ r._check_name }}",
+			err:  `message template placeholder "r._check_name" is unknown`,
+		},
+		{
+			name: "expression",
+			text: `{{ tags.host + "x" }}`,
+			err:  `message template key "host + \"x\"" is invalid`,
+		},
+		{
+			name: "empty key",
+			text: "{{ fields. }}",
+			err:  `message template key "" is invalid`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := template.Parse(tt.text)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+			assert.Equal(t, tt.err, influxdb.ErrorMessage(err))
+		})
+	}
+}
+
+func TestUsesMessage(t *testing.T) {
+	tmpl, err := template.Parse("{{ check_name }}: {{ message }}")
+	require.NoError(t, err)
+	assert.True(t, tmpl.UsesMessage())
+
+	tmpl, err = template.Parse("{{ check_name }} is {{ level }}")
+	require.NoError(t, err)
+	assert.False(t, tmpl.UsesMessage())
+}
+
+func TestRender(t *testing.T) {
+	status := template.Status{
+		CheckID:     "020f755c3c082000",
+		CheckName:   "cpu",
+		Level:       "crit",
+		Measurement: "cpu",
+		Time:        time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC),
+		Message:     "cpu is crit",
+		Tags:        map[string]string{"host": "a"},
+		Fields:      map[string]interface{}{"usage_user": 91.5},
+	}
+
+	tmpl, err := template.Parse("[{{ level }}] {{ check_name }} on {{ tags.host }} at {{ time }}: {{ fields.usage_user }}% ${x} {{ runbook }}")
+	require.NoError(t, err)
+	msg, err := tmpl.Render("https://runbooks.example.com/cpu", status)
+	require.NoError(t, err)
+	assert.Equal(t, "[crit] cpu on a at 2020-06-01T12:00:00Z: 91.5% ${x} https://runbooks.example.com/cpu", msg)
+
+	tmpl, err = template.Parse("{{ tags.region }}")
+	require.NoError(t, err)
+	_, err = tmpl.Render("", status)
+	assert.Equal(t, `status has no tag "region"`, influxdb.ErrorMessage(err))
+
+	tmpl, err = template.Parse("{{ fields.usage_system }}")
+	require.NoError(t, err)
+	_, err = tmpl.Render("", status)
+	assert.Equal(t, `status has no field "usage_system"`, influxdb.ErrorMessage(err))
+}
+
+func TestExpression(t *testing.T) {
+	tmpl, err := template.Parse(`[{{ level }}] {{ check_name }} on {{ tags.host }} at {{ time }}: {{ fields.usage_user }}% "${x}" {{ runbook }}`)
+	require.NoError(t, err)
+
+	got := ast.Format(tmpl.Expression("https://runbooks.example.com/${cpu}"))
+	want := `"[${r["_level"]}] ${r["_check_name"]} on ${r["host"]} at ${string(v: time(v: r["_source_timestamp"]))}: ${string(v: r["usage_user"])}% \"${"$"}{x}\" https://runbooks.example.com/${"$"}{cpu}"`
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,94 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// NotificationDeliveryState is the state of a failed notification delivery.
+type NotificationDeliveryState string
+
+// notification delivery states
+const (
+	// NotificationDeliveryFailed is the state of a delivery that failed, and
+	// of one whose resends failed as well.
+	NotificationDeliveryFailed NotificationDeliveryState = "failed"
+	// NotificationDeliveryResent is the state of a failed delivery that was
+	// resent successfully.
+	NotificationDeliveryResent NotificationDeliveryState = "resent"
+)
+
+// Valid returns an error if the state is unknown.
+func (s NotificationDeliveryState) Valid() error {
+	switch s {
+	case NotificationDeliveryFailed, NotificationDeliveryResent:
+		return nil
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  "notification delivery state must be one of failed or resent",
+	}
+}
+
+// NotificationDelivery is a notification that a notification rule with a
+// retry policy failed to deliver to its endpoint. Every delivery attempt of
+// these rules is logged to the notifications measurement of the monitoring
+// bucket, the failed ones are kept so that they can be resent.
+type NotificationDelivery struct {
+	ID           ID     `json:"id"`
+	OrgID        ID     `json:"orgID"`
+	RuleID       ID     `json:"ruleID"`
+	RuleName     string `json:"ruleName"`
+	EndpointID   ID     `json:"endpointID"`
+	EndpointName string `json:"endpointName"`
+	CheckID      ID     `json:"checkID"`
+	CheckName    string `json:"checkName"`
+	Level        string `json:"level"`
+	// Tags are the tags of the series, without the columns of the check.
+	Tags []Tag `json:"tags"`
+	// Message is the message that was sent, the request body for http and
+	// smtp endpoints.
+	Message string `json:"message"`
+	// CheckType, SourceMeasurement, StatusMessage and StatusTime are the
+	// columns of the status of the notification, that resends notify again.
+	CheckType         string    `json:"checkType"`
+	SourceMeasurement string    `json:"sourceMeasurement"`
+	StatusMessage     string    `json:"statusMessage"`
+	StatusTime        time.Time `json:"statusTime"`
+
+	State      NotificationDeliveryState `json:"state"`
+	StatusCode int                       `json:"statusCode"`
+	Attempts   int                       `json:"attempts"`
+	LatencyMS  int64                     `json:"latencyMS"`
+	Error      string                    `json:"error,omitempty"`
+	// Time is the time of the failed delivery.
+	Time time.Time `json:"time"`
+
+	// Resends is the number of times the delivery was resent.
+	Resends      int        `json:"resends"`
+	LastResentAt *time.Time `json:"lastResentAt,omitempty"`
+}
+
+// NotificationDeliveryFilter represents a set of filters that restrict the
+// returned notification deliveries.
+type NotificationDeliveryFilter struct {
+	OrgID      *ID
+	RuleID     *ID
+	EndpointID *ID
+	State      *NotificationDeliveryState
+}
+
+// NotificationDeliveryService manages the failed deliveries of notification rules.
+type NotificationDeliveryService interface {
+	// FindNotificationDeliveryByID returns a single notification delivery by ID.
+	FindNotificationDeliveryByID(ctx context.Context, id ID) (*NotificationDelivery, error)
+
+	// FindNotificationDeliveries returns the notification deliveries matching
+	// the filter, most recent first.
+	FindNotificationDeliveries(ctx context.Context, filter NotificationDeliveryFilter) ([]*NotificationDelivery, error)
+
+	// ResendNotificationDelivery sends the notification of the delivery to
+	// the endpoint of its rule again. A resend that fails is recorded on the
+	// delivery and returned as an error.
+	ResendNotificationDelivery(ctx context.Context, id ID) (*NotificationDelivery, error)
+}
@@ -0,0 +1,29 @@
+package influxdb
+
+import "context"
+
+// NotificationTestResult is the response of an endpoint to a test notification.
+type NotificationTestResult struct {
+	EndpointID ID `json:"endpointID"`
+	// RuleID is the notification rule whose settings the notification was
+	// sent with, it is not set when an endpoint is tested.
+	RuleID *ID `json:"ruleID,omitempty"`
+	// Message is the message that was sent, the request body for http and
+	// smtp endpoints.
+	Message string `json:"message"`
+	// Sent is whether the endpoint accepted the notification, with a 2xx response.
+	Sent       bool `json:"sent"`
+	StatusCode int  `json:"statusCode"`
+}
+
+// NotificationTestService sends synthetic notifications to verify the
+// credentials of endpoints and the delivery settings and message templates of
+// notification rules without waiting for a status of a check.
+type NotificationTestService interface {
+	// TestNotificationEndpoint sends a test notification to the endpoint.
+	TestNotificationEndpoint(ctx context.Context, id ID) (*NotificationTestResult, error)
+
+	// TestNotificationRule sends a test notification, with the settings of
+	// the rule, to the endpoint of the rule.
+	TestNotificationRule(ctx context.Context, id ID) (*NotificationTestResult, error)
+}
@@ -50,17 +50,23 @@ var kindPriorities = map[Kind]int{
 	KindLabel:                         1,
 	KindBucket:                        2,
 	KindCheck:                         3,
-	KindCheckDeadman:                  4,
-	KindCheckThreshold:                5,
-	KindNotificationEndpoint:          6,
-	KindNotificationEndpointHTTP:      7,
-	KindNotificationEndpointPagerDuty: 8,
-	KindNotificationEndpointSlack:     9,
-	KindNotificationRule:              10,
-	KindTask:                          11,
-	KindVariable:                      12,
-	KindDashboard:                     13,
-	KindTelegraf:                      14,
+	KindCheckAbsence:                  4,
+	KindCheckAnomaly:                  5,
+	KindCheckDeadman:                  6,
+	KindCheckRateOfChange:             7,
+	KindCheckThreshold:                8,
+	KindNotificationEndpoint:          9,
+	KindNotificationEndpointHTTP:      10,
+	KindNotificationEndpointPagerDuty: 11,
+	KindNotificationEndpointSlack:     12,
+	KindNotificationEndpointSMTP:      13,
+	KindNotificationEndpointTeams:     14,
+	KindNotificationEndpointOpsgenie:  15,
+	KindNotificationRule:              16,
+	KindTask:                          17,
+	KindVariable:                      18,
+	KindDashboard:                     19,
+	KindTelegraf:                      20,
 }
 
 type exportKey struct {
@@ -238,7 +244,12 @@ func (ex *resourceExporter) resourceCloneToKind(ctx context.Context, r ResourceT
 		for _, bkt := range bkts {
 			mapResource(bkt.OrgID, bkt.ID, KindBucket, BucketToObject(r.Name, *bkt))
 		}
-	case r.Kind.is(KindCheck), r.Kind.is(KindCheckDeadman), r.Kind.is(KindCheckThreshold):
+	case r.Kind.is(KindCheck),
+		r.Kind.is(KindCheckAbsence),
+		r.Kind.is(KindCheckAnomaly),
+		r.Kind.is(KindCheckDeadman),
+		r.Kind.is(KindCheckRateOfChange),
+		r.Kind.is(KindCheckThreshold):
 		filter := influxdb.CheckFilter{}
 		if r.ID != influxdb.ID(0) {
 			filter.ID = &r.ID
@@ -315,7 +326,10 @@ func (ex *resourceExporter) resourceCloneToKind(ctx context.Context, r ResourceT
 	case r.Kind.is(KindNotificationEndpoint),
 		r.Kind.is(KindNotificationEndpointHTTP),
 		r.Kind.is(KindNotificationEndpointPagerDuty),
-		r.Kind.is(KindNotificationEndpointSlack):
+		r.Kind.is(KindNotificationEndpointSlack),
+		r.Kind.is(KindNotificationEndpointSMTP),
+		r.Kind.is(KindNotificationEndpointTeams),
+		r.Kind.is(KindNotificationEndpointOpsgenie):
 		var endpoints []influxdb.NotificationEndpoint
 
 		switch {
@@ -667,6 +681,45 @@ func CheckToObject(name string, ch influxdb.Check) Object {
 			thresholds = append(thresholds, convertThreshold(th))
 		}
 		o.Spec[fieldCheckThresholds] = thresholds
+	case *icheck.RateOfChange:
+		o.Kind = KindCheckRateOfChange
+		assignBase(cT.Base)
+		assignNonZeroFluxDurs(o.Spec, map[string]*notification.Duration{
+			fieldCheckPeriod: cT.Period,
+		})
+		assignNonZeroBools(o.Spec, map[string]bool{fieldCheckPercent: cT.Percent})
+		var thresholds []Resource
+		for _, th := range cT.Thresholds {
+			thresholds = append(thresholds, convertThreshold(th))
+		}
+		o.Spec[fieldCheckThresholds] = thresholds
+	case *icheck.Absence:
+		o.Kind = KindCheckAbsence
+		assignBase(cT.Base)
+		assignNonZeroFluxDurs(o.Spec, map[string]*notification.Duration{
+			fieldCheckLookback:  cT.Lookback,
+			fieldCheckTimeSince: cT.TimeSince,
+		})
+		o.Spec[fieldLevel] = cT.Level.String()
+		if len(cT.Series) > 0 {
+			o.Spec[fieldCheckSeries] = cT.Series
+		}
+	case *icheck.Anomaly:
+		o.Kind = KindCheckAnomaly
+		assignBase(cT.Base)
+		o.Spec[fieldCheckMethod] = cT.Method
+		assignNonZeroFluxDurs(o.Spec, map[string]*notification.Duration{
+			fieldCheckWindow: cT.Window,
+		})
+		assignNonZeroStrings(o.Spec, map[string]string{fieldCheckSeasonality: cT.Seasonality})
+		var thresholds []Resource
+		for _, th := range cT.Thresholds {
+			thresholds = append(thresholds, Resource{
+				fieldLevel:           th.Level.String(),
+				fieldCheckDeviations: th.Deviations,
+			})
+		}
+		o.Spec[fieldCheckThresholds] = thresholds
 	}
 	return o
 }
@@ -1172,6 +1225,33 @@ func NotificationEndpointToObject(name string, e influxdb.NotificationEndpoint)
 		assignNonZeroSecrets(o.Spec, map[string]influxdb.SecretField{
 			fieldNotificationEndpointToken: actual.Token,
 		})
+	case *endpoint.SMTP:
+		o.Kind = KindNotificationEndpointSMTP
+		o.Spec[fieldNotificationEndpointHost] = actual.Host
+		o.Spec[fieldNotificationEndpointPort] = actual.Port
+		o.Spec[fieldNotificationEndpointFrom] = actual.From
+		if actual.StartTLS {
+			o.Spec[fieldNotificationEndpointStartTLS] = true
+		}
+		assignNonZeroSecrets(o.Spec, map[string]influxdb.SecretField{
+			fieldNotificationEndpointPassword: actual.Password,
+			fieldNotificationEndpointUsername: actual.Username,
+		})
+	case *endpoint.Teams:
+		o.Kind = KindNotificationEndpointTeams
+		o.Spec[fieldNotificationEndpointURL] = actual.URL
+		assignNonZeroSecrets(o.Spec, map[string]influxdb.SecretField{
+			fieldNotificationEndpointSecretURLSuffix: actual.SecretURLSuffix,
+		})
+	case *endpoint.Opsgenie:
+		o.Kind = KindNotificationEndpointOpsgenie
+		assignNonZeroStrings(o.Spec, map[string]string{
+			fieldNotificationEndpointURL:    actual.URL,
+			fieldNotificationEndpointEntity: actual.Entity,
+		})
+		assignNonZeroSecrets(o.Spec, map[string]influxdb.SecretField{
+			fieldNotificationEndpointAPIKey: actual.APIKey,
+		})
 	}
 
 	return o
@@ -1225,6 +1305,7 @@ func NotificationRuleToObject(name, endpointPkgName string, iRule influxdb.Notif
 	switch t := iRule.(type) {
 	case *rule.HTTP:
 		assignBase(t.Base)
+		assignNonZeroStrings(o.Spec, map[string]string{fieldNotificationRuleMessageTemplate: t.BodyTemplate})
 	case *rule.PagerDuty:
 		assignBase(t.Base)
 		o.Spec[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
@@ -1232,6 +1313,28 @@ func NotificationRuleToObject(name, endpointPkgName string, iRule influxdb.Notif
 		assignBase(t.Base)
 		o.Spec[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
 		assignNonZeroStrings(o.Spec, map[string]string{fieldNotificationRuleChannel: t.Channel})
+	case *rule.SMTP:
+		assignBase(t.Base)
+		o.Spec[fieldNotificationRuleMessageTemplate] = t.BodyTemplate
+		o.Spec[fieldNotificationRuleSubjectTemplate] = t.SubjectTemplate
+		o.Spec[fieldNotificationRuleTo] = t.To
+	case *rule.Teams:
+		assignBase(t.Base)
+		o.Spec[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
+		assignNonZeroStrings(o.Spec, map[string]string{fieldNotificationRuleSubjectTemplate: t.Title})
+	case *rule.Opsgenie:
+		assignBase(t.Base)
+		o.Spec[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
+		assignNonZeroStrings(o.Spec, map[string]string{
+			fieldNotificationRuleDescriptionTemplate: t.DescriptionTemplate,
+			fieldNotificationRulePriority:            t.Priority,
+		})
+		if len(t.Responders) > 0 {
+			o.Spec[fieldNotificationRuleResponders] = t.Responders
+		}
+		if len(t.Tags) > 0 {
+			o.Spec[fieldNotificationRuleTags] = t.Tags
+		}
 	}
 
 	return o
@@ -416,7 +416,7 @@ func stackResLinks(r StackResource) RespStackResourceLinks {
 	switch r.Kind {
 	case KindBucket:
 		linkResource = "buckets"
-	case KindCheck, KindCheckDeadman, KindCheckThreshold:
+	case KindCheck, KindCheckAbsence, KindCheckAnomaly, KindCheckDeadman, KindCheckRateOfChange, KindCheckThreshold:
 		linkResource = "checks"
 	case KindDashboard:
 		linkResource = "dashboards"
@@ -425,7 +425,10 @@ func stackResLinks(r StackResource) RespStackResourceLinks {
 	case KindNotificationEndpoint,
 		KindNotificationEndpointHTTP,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointSMTP,
+		KindNotificationEndpointTeams,
+		KindNotificationEndpointOpsgenie:
 		linkResource = "notificationEndpoints"
 	case KindNotificationRule:
 		linkResource = "notificationRules"
@@ -16,7 +16,10 @@ const (
 	KindUnknown                       Kind = ""
 	KindBucket                        Kind = "Bucket"
 	KindCheck                         Kind = "Check"
+	KindCheckAbsence                  Kind = "CheckAbsence"
+	KindCheckAnomaly                  Kind = "CheckAnomaly"
 	KindCheckDeadman                  Kind = "CheckDeadman"
+	KindCheckRateOfChange             Kind = "CheckRateOfChange"
 	KindCheckThreshold                Kind = "CheckThreshold"
 	KindDashboard                     Kind = "Dashboard"
 	KindLabel                         Kind = "Label"
@@ -24,6 +27,9 @@ const (
 	KindNotificationEndpointHTTP      Kind = "NotificationEndpointHTTP"
 	KindNotificationEndpointPagerDuty Kind = "NotificationEndpointPagerDuty"
 	KindNotificationEndpointSlack     Kind = "NotificationEndpointSlack"
+	KindNotificationEndpointSMTP      Kind = "NotificationEndpointSMTP"
+	KindNotificationEndpointTeams     Kind = "NotificationEndpointTeams"
+	KindNotificationEndpointOpsgenie  Kind = "NotificationEndpointOpsgenie"
 	KindNotificationRule              Kind = "NotificationRule"
 	KindPackage                       Kind = "Package"
 	KindTask                          Kind = "Task"
@@ -43,7 +49,10 @@ func Kinds() []Kind {
 var kinds = map[Kind]bool{
 	KindBucket:                        true,
 	KindCheck:                         true,
+	KindCheckAbsence:                  true,
+	KindCheckAnomaly:                  true,
 	KindCheckDeadman:                  true,
+	KindCheckRateOfChange:             true,
 	KindCheckThreshold:                true,
 	KindDashboard:                     true,
 	KindLabel:                         true,
@@ -51,6 +60,9 @@ var kinds = map[Kind]bool{
 	KindNotificationEndpointHTTP:      true,
 	KindNotificationEndpointPagerDuty: true,
 	KindNotificationEndpointSlack:     true,
+	KindNotificationEndpointSMTP:      true,
+	KindNotificationEndpointTeams:     true,
+	KindNotificationEndpointOpsgenie:  true,
 	KindNotificationRule:              true,
 	KindTask:                          true,
 	KindTelegraf:                      true,
@@ -87,7 +99,7 @@ func (k Kind) ResourceType() influxdb.ResourceType {
 	switch k {
 	case KindBucket:
 		return influxdb.BucketsResourceType
-	case KindCheck, KindCheckDeadman, KindCheckThreshold:
+	case KindCheck, KindCheckAbsence, KindCheckAnomaly, KindCheckDeadman, KindCheckRateOfChange, KindCheckThreshold:
 		return influxdb.ChecksResourceType
 	case KindDashboard:
 		return influxdb.DashboardsResourceType
@@ -96,7 +108,10 @@ func (k Kind) ResourceType() influxdb.ResourceType {
 	case KindNotificationEndpoint,
 		KindNotificationEndpointHTTP,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointSMTP,
+		KindNotificationEndpointTeams,
+		KindNotificationEndpointOpsgenie:
 		return influxdb.NotificationEndpointResourceType
 	case KindNotificationRule:
 		return influxdb.NotificationRuleResourceType
@@ -373,11 +388,17 @@ type (
 		EndpointName string `json:"endpointName"`
 		EndpointType string `json:"endpointType"`
 
-		Every           string              `json:"every"`
-		Offset          string              `json:"offset"`
-		MessageTemplate string              `json:"messageTemplate"`
-		StatusRules     []SummaryStatusRule `json:"statusRules"`
-		TagRules        []SummaryTagRule    `json:"tagRules"`
+		Every               string              `json:"every"`
+		Offset              string              `json:"offset"`
+		MessageTemplate     string              `json:"messageTemplate"`
+		SubjectTemplate     string              `json:"subjectTemplate,omitempty"`
+		To                  string              `json:"to,omitempty"`
+		DescriptionTemplate string              `json:"descriptionTemplate,omitempty"`
+		Priority            string              `json:"priority,omitempty"`
+		Responders          []string            `json:"responders,omitempty"`
+		Tags                []string            `json:"tags,omitempty"`
+		StatusRules         []SummaryStatusRule `json:"statusRules"`
+		TagRules            []SummaryTagRule    `json:"tagRules"`
 	}
 )
 
@@ -598,12 +619,18 @@ type (
 		EndpointMetaName string `json:"endpointTemplateMetaName"`
 		EndpointType     string `json:"endpointType"`
 
-		Every           string              `json:"every"`
-		Offset          string              `json:"offset"`
-		MessageTemplate string              `json:"messageTemplate"`
-		Status          influxdb.Status     `json:"status"`
-		StatusRules     []SummaryStatusRule `json:"statusRules"`
-		TagRules        []SummaryTagRule    `json:"tagRules"`
+		Every               string              `json:"every"`
+		Offset              string              `json:"offset"`
+		MessageTemplate     string              `json:"messageTemplate"`
+		SubjectTemplate     string              `json:"subjectTemplate,omitempty"`
+		To                  string              `json:"to,omitempty"`
+		DescriptionTemplate string              `json:"descriptionTemplate,omitempty"`
+		Priority            string              `json:"priority,omitempty"`
+		Responders          []string            `json:"responders,omitempty"`
+		Tags                []string            `json:"tags,omitempty"`
+		Status              influxdb.Status     `json:"status"`
+		StatusRules         []SummaryStatusRule `json:"statusRules"`
+		TagRules            []SummaryTagRule    `json:"tagRules"`
 
 		LabelAssociations []SummaryLabel `json:"labelAssociations"`
 	}
@@ -491,7 +491,7 @@ func (p *Template) Contains(k Kind, pkgName string) bool {
 	case KindBucket:
 		_, ok := p.mBuckets[pkgName]
 		return ok
-	case KindCheck, KindCheckDeadman, KindCheckThreshold:
+	case KindCheck, KindCheckAbsence, KindCheckAnomaly, KindCheckDeadman, KindCheckRateOfChange, KindCheckThreshold:
 		_, ok := p.mChecks[pkgName]
 		return ok
 	case KindLabel:
@@ -500,7 +500,10 @@ func (p *Template) Contains(k Kind, pkgName string) bool {
 	case KindNotificationEndpoint,
 		KindNotificationEndpointHTTP,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointSMTP,
+		KindNotificationEndpointTeams,
+		KindNotificationEndpointOpsgenie:
 		_, ok := p.mNotificationEndpoints[pkgName]
 		return ok
 	case KindNotificationRule:
@@ -869,6 +872,9 @@ func (p *Template) graphChecks() *parseErr {
 		checkKind checkKind
 	}{
 		{kind: KindCheckThreshold, checkKind: checkKindThreshold},
+		{kind: KindCheckAnomaly, checkKind: checkKindAnomaly},
+		{kind: KindCheckRateOfChange, checkKind: checkKindRateOfChange},
+		{kind: KindCheckAbsence, checkKind: checkKindAbsence},
 		{kind: KindCheckDeadman, checkKind: checkKindDeadman},
 	}
 	var pErr parseErr
@@ -892,6 +898,13 @@ func (p *Template) graphChecks() *parseErr {
 				status:        normStr(o.Spec.stringShort(fieldStatus)),
 				statusMessage: o.Spec.stringShort(fieldCheckStatusMessageTemplate),
 				timeSince:     o.Spec.durationShort(fieldCheckTimeSince),
+				method:        normStr(o.Spec.stringShort(fieldCheckMethod)),
+				window:        o.Spec.durationShort(fieldCheckWindow),
+				seasonality:   strings.TrimSpace(o.Spec.stringShort(fieldCheckSeasonality)),
+				period:        o.Spec.durationShort(fieldCheckPeriod),
+				percent:       o.Spec.boolShort(fieldCheckPercent),
+				series:        o.Spec.slcStr(fieldCheckSeries),
+				lookback:      o.Spec.durationShort(fieldCheckLookback),
 			}
 			for _, tagRes := range o.Spec.slcResource(fieldCheckTags) {
 				ch.tags = append(ch.tags, struct{ k, v string }{
@@ -900,6 +913,13 @@ func (p *Template) graphChecks() *parseErr {
 				})
 			}
 			for _, th := range o.Spec.slcResource(fieldCheckThresholds) {
+				if ch.kind == checkKindAnomaly {
+					ch.deviations = append(ch.deviations, anomalyThreshold{
+						level:      strings.TrimSpace(strings.ToUpper(th.stringShort(fieldLevel))),
+						deviations: th.float64Short(fieldCheckDeviations),
+					})
+					continue
+				}
 				ch.thresholds = append(ch.thresholds, threshold{
 					threshType: thresholdType(normStr(th.stringShort(fieldType))),
 					allVals:    th.boolShort(fieldCheckAllValues),
@@ -994,6 +1014,18 @@ func (p *Template) graphNotificationEndpoints() *parseErr {
 			kind:             KindNotificationEndpointSlack,
 			notificationKind: notificationKindSlack,
 		},
+		{
+			kind:             KindNotificationEndpointSMTP,
+			notificationKind: notificationKindSMTP,
+		},
+		{
+			kind:             KindNotificationEndpointTeams,
+			notificationKind: notificationKindTeams,
+		},
+		{
+			kind:             KindNotificationEndpointOpsgenie,
+			notificationKind: notificationKindOpsgenie,
+		},
 	}
 
 	var pErr parseErr
@@ -1005,17 +1037,24 @@ func (p *Template) graphNotificationEndpoints() *parseErr {
 			}
 
 			endpoint := &notificationEndpoint{
-				kind:        nk.notificationKind,
-				identity:    ident,
-				description: o.Spec.stringShort(fieldDescription),
-				method:      strings.TrimSpace(strings.ToUpper(o.Spec.stringShort(fieldNotificationEndpointHTTPMethod))),
-				httpType:    normStr(o.Spec.stringShort(fieldType)),
-				password:    o.Spec.references(fieldNotificationEndpointPassword),
-				routingKey:  o.Spec.references(fieldNotificationEndpointRoutingKey),
-				status:      normStr(o.Spec.stringShort(fieldStatus)),
-				token:       o.Spec.references(fieldNotificationEndpointToken),
-				url:         o.Spec.stringShort(fieldNotificationEndpointURL),
-				username:    o.Spec.references(fieldNotificationEndpointUsername),
+				kind:            nk.notificationKind,
+				identity:        ident,
+				apiKey:          o.Spec.references(fieldNotificationEndpointAPIKey),
+				description:     o.Spec.stringShort(fieldDescription),
+				entity:          strings.TrimSpace(o.Spec.stringShort(fieldNotificationEndpointEntity)),
+				from:            strings.TrimSpace(o.Spec.stringShort(fieldNotificationEndpointFrom)),
+				host:            strings.TrimSpace(o.Spec.stringShort(fieldNotificationEndpointHost)),
+				method:          strings.TrimSpace(strings.ToUpper(o.Spec.stringShort(fieldNotificationEndpointHTTPMethod))),
+				httpType:        normStr(o.Spec.stringShort(fieldType)),
+				password:        o.Spec.references(fieldNotificationEndpointPassword),
+				port:            o.Spec.intShort(fieldNotificationEndpointPort),
+				routingKey:      o.Spec.references(fieldNotificationEndpointRoutingKey),
+				secretURLSuffix: o.Spec.references(fieldNotificationEndpointSecretURLSuffix),
+				startTLS:        o.Spec.boolShort(fieldNotificationEndpointStartTLS),
+				status:          normStr(o.Spec.stringShort(fieldStatus)),
+				token:           o.Spec.references(fieldNotificationEndpointToken),
+				url:             o.Spec.stringShort(fieldNotificationEndpointURL),
+				username:        o.Spec.references(fieldNotificationEndpointUsername),
 			}
 			failures := p.parseNestedLabels(o.Spec, func(l *label) error {
 				endpoint.labels = append(endpoint.labels, l)
@@ -1064,6 +1103,12 @@ func (p *Template) graphNotificationRules() *parseErr {
 			msgTemplate:  o.Spec.stringShort(fieldNotificationRuleMessageTemplate),
 			offset:       o.Spec.durationShort(fieldOffset),
 			status:       normStr(o.Spec.stringShort(fieldStatus)),
+			subject:      o.Spec.stringShort(fieldNotificationRuleSubjectTemplate),
+			to:           o.Spec.stringShort(fieldNotificationRuleTo),
+			descTmpl:     o.Spec.stringShort(fieldNotificationRuleDescriptionTemplate),
+			priority:     strings.TrimSpace(strings.ToUpper(o.Spec.stringShort(fieldNotificationRulePriority))),
+			responders:   o.Spec.slcStr(fieldNotificationRuleResponders),
+			tags:         o.Spec.slcStr(fieldNotificationRuleTags),
 		}
 
 		for _, sRule := range o.Spec.slcResource(fieldNotificationRuleStatusRules) {
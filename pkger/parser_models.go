@@ -2,6 +2,7 @@ package pkger
 
 import (
 	"fmt"
+	"net/mail"
 	"net/url"
 	"regexp"
 	"sort"
@@ -191,16 +192,27 @@ type checkKind int
 const (
 	checkKindDeadman checkKind = iota + 1
 	checkKindThreshold
+	checkKindAnomaly
+	checkKindRateOfChange
+	checkKindAbsence
 )
 
 const (
 	fieldCheckAllValues             = "allValues"
+	fieldCheckDeviations            = "deviations"
+	fieldCheckLookback              = "lookback"
+	fieldCheckMethod                = "method"
+	fieldCheckPercent               = "percent"
+	fieldCheckPeriod                = "period"
 	fieldCheckReportZero            = "reportZero"
+	fieldCheckSeasonality           = "seasonality"
+	fieldCheckSeries                = "series"
 	fieldCheckStaleTime             = "staleTime"
 	fieldCheckStatusMessageTemplate = "statusMessageTemplate"
 	fieldCheckTags                  = "tags"
 	fieldCheckThresholds            = "thresholds"
 	fieldCheckTimeSince             = "timeSince"
+	fieldCheckWindow                = "window"
 )
 
 const checkNameMinLength = 1
@@ -221,6 +233,14 @@ type check struct {
 	tags          []struct{ k, v string }
 	timeSince     time.Duration
 	thresholds    []threshold
+	method        string
+	window        time.Duration
+	seasonality   string
+	deviations    []anomalyThreshold
+	period        time.Duration
+	percent       bool
+	series        []string
+	lookback      time.Duration
 
 	labels sortedLabels
 }
@@ -269,6 +289,32 @@ func (c *check) summarize() SummaryCheck {
 			Base:       base,
 			Thresholds: toInfluxThresholds(c.thresholds...),
 		}
+	case checkKindAnomaly:
+		sum.Kind = KindCheckAnomaly
+		sum.Check = &icheck.Anomaly{
+			Base:        base,
+			Method:      c.method,
+			Window:      toNotificationDuration(c.window),
+			Seasonality: c.seasonality,
+			Thresholds:  toInfluxAnomalyThresholds(c.deviations...),
+		}
+	case checkKindRateOfChange:
+		sum.Kind = KindCheckRateOfChange
+		sum.Check = &icheck.RateOfChange{
+			Base:       base,
+			Period:     toNotificationDuration(c.period),
+			Percent:    c.percent,
+			Thresholds: toInfluxThresholds(c.thresholds...),
+		}
+	case checkKindAbsence:
+		sum.Kind = KindCheckAbsence
+		sum.Check = &icheck.Absence{
+			Base:      base,
+			Series:    c.series,
+			Lookback:  toNotificationDuration(c.lookback),
+			TimeSince: toNotificationDuration(c.timeSince),
+			Level:     notification.ParseCheckLevel(strings.ToUpper(c.level)),
+		}
 	case checkKindDeadman:
 		sum.Kind = KindCheckDeadman
 		sum.Check = &icheck.Deadman{
@@ -313,7 +359,13 @@ func (c *check) valid() []validationErr {
 	}
 
 	switch c.kind {
-	case checkKindThreshold:
+	case checkKindThreshold, checkKindRateOfChange:
+		if c.kind == checkKindRateOfChange && c.period <= c.every {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckPeriod,
+				Msg:   "duration value must be greater than every",
+			})
+		}
 		if len(c.thresholds) == 0 {
 			vErrs = append(vErrs, validationErr{
 				Field: fieldCheckThresholds,
@@ -326,6 +378,65 @@ func (c *check) valid() []validationErr {
 				vErrs = append(vErrs, fail)
 			}
 		}
+	case checkKindAnomaly:
+		if c.method != icheck.AnomalyStddev && c.method != icheck.AnomalyMAD {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckMethod,
+				Msg:   fmt.Sprintf("must be 1 in [%s, %s]; got=%q", icheck.AnomalyStddev, icheck.AnomalyMAD, c.method),
+			})
+		}
+		if c.window <= c.every {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckWindow,
+				Msg:   "duration value must be greater than every",
+			})
+		}
+		if c.seasonality != "" && c.seasonality != icheck.AnomalyHourOfWeek {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckSeasonality,
+				Msg:   fmt.Sprintf("must be empty or %s; got=%q", icheck.AnomalyHourOfWeek, c.seasonality),
+			})
+		}
+		if len(c.deviations) == 0 {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckThresholds,
+				Msg:   "must provide at least 1 threshold entry",
+			})
+		}
+		for i, th := range c.deviations {
+			for _, fail := range th.valid() {
+				fail.Index = intPtr(i)
+				vErrs = append(vErrs, fail)
+			}
+		}
+	case checkKindAbsence:
+		if c.timeSince == 0 {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckTimeSince,
+				Msg:   "duration value must be provided",
+			})
+		}
+		if c.lookback <= c.timeSince {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckLookback,
+				Msg:   "duration value must be greater than timeSince",
+			})
+		}
+		if notification.ParseCheckLevel(strings.ToUpper(c.level)) == notification.Unknown {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldLevel,
+				Msg:   fmt.Sprintf("must be 1 in [CRIT, WARN, INFO, OK]; got=%q", c.level),
+			})
+		}
+		for i, k := range c.series {
+			if k == "" {
+				vErrs = append(vErrs, validationErr{
+					Field: fieldCheckSeries,
+					Index: intPtr(i),
+					Msg:   "must provide a non zero value",
+				})
+			}
+		}
 	}
 
 	if len(vErrs) > 0 {
@@ -414,6 +525,39 @@ func toInfluxThresholds(thresholds ...threshold) []icheck.ThresholdConfig {
 	return iThresh
 }
 
+type anomalyThreshold struct {
+	level      string
+	deviations float64
+}
+
+func (t anomalyThreshold) valid() []validationErr {
+	var vErrs []validationErr
+	if notification.ParseCheckLevel(t.level) == notification.Unknown {
+		vErrs = append(vErrs, validationErr{
+			Field: fieldLevel,
+			Msg:   fmt.Sprintf("must be 1 in [CRIT, WARN, INFO, OK]; got=%q", t.level),
+		})
+	}
+	if t.deviations <= 0 {
+		vErrs = append(vErrs, validationErr{
+			Field: fieldCheckDeviations,
+			Msg:   "must be greater than 0",
+		})
+	}
+	return vErrs
+}
+
+func toInfluxAnomalyThresholds(thresholds ...anomalyThreshold) []icheck.AnomalyThreshold {
+	var iThresh []icheck.AnomalyThreshold
+	for _, th := range thresholds {
+		iThresh = append(iThresh, icheck.AnomalyThreshold{
+			Level:      notification.ParseCheckLevel(th.level),
+			Deviations: th.deviations,
+		})
+	}
+	return iThresh
+}
+
 // chartKind identifies what kind of chart is eluded too. Each
 // chart kind has their own requirements for what constitutes
 // a chart.
@@ -1441,14 +1585,20 @@ const (
 	notificationKindHTTP notificationEndpointKind = iota + 1
 	notificationKindPagerDuty
 	notificationKindSlack
+	notificationKindSMTP
+	notificationKindTeams
+	notificationKindOpsgenie
 )
 
 func (n notificationEndpointKind) String() string {
-	if n > 0 && n < 4 {
+	if n > 0 && n < 7 {
 		return [...]string{
 			endpoint.HTTPType,
 			endpoint.PagerDutyType,
 			endpoint.SlackType,
+			endpoint.SMTPType,
+			endpoint.TeamsType,
+			endpoint.OpsgenieType,
 		}[n-1]
 	}
 	return ""
@@ -1461,27 +1611,41 @@ const (
 )
 
 const (
-	fieldNotificationEndpointHTTPMethod = "method"
-	fieldNotificationEndpointPassword   = "password"
-	fieldNotificationEndpointRoutingKey = "routingKey"
-	fieldNotificationEndpointToken      = "token"
-	fieldNotificationEndpointURL        = "url"
-	fieldNotificationEndpointUsername   = "username"
+	fieldNotificationEndpointAPIKey          = "apiKey"
+	fieldNotificationEndpointEntity          = "entity"
+	fieldNotificationEndpointFrom            = "from"
+	fieldNotificationEndpointHost            = "host"
+	fieldNotificationEndpointHTTPMethod      = "method"
+	fieldNotificationEndpointPassword        = "password"
+	fieldNotificationEndpointPort            = "port"
+	fieldNotificationEndpointRoutingKey      = "routingKey"
+	fieldNotificationEndpointSecretURLSuffix = "secretURLSuffix"
+	fieldNotificationEndpointStartTLS        = "startTLS"
+	fieldNotificationEndpointToken           = "token"
+	fieldNotificationEndpointURL             = "url"
+	fieldNotificationEndpointUsername        = "username"
 )
 
 type notificationEndpoint struct {
 	identity
 
-	kind        notificationEndpointKind
-	description string
-	method      string
-	password    *references
-	routingKey  *references
-	status      string
-	token       *references
-	httpType    string
-	url         string
-	username    *references
+	kind            notificationEndpointKind
+	apiKey          *references
+	description     string
+	entity          string
+	from            string
+	host            string
+	method          string
+	password        *references
+	port            int
+	routingKey      *references
+	secretURLSuffix *references
+	startTLS        bool
+	status          string
+	token           *references
+	httpType        string
+	url             string
+	username        *references
 
 	labels sortedLabels
 }
@@ -1546,6 +1710,38 @@ func (n *notificationEndpoint) summarize() SummaryNotificationEndpoint {
 			URL:   n.url,
 			Token: n.token.SecretField(),
 		}
+	case notificationKindSMTP:
+		sum.Kind = KindNotificationEndpointSMTP
+		e := &endpoint.SMTP{
+			Base:     base,
+			Host:     n.host,
+			Port:     n.port,
+			StartTLS: n.startTLS,
+			From:     n.from,
+		}
+		if n.username.hasValue() {
+			e.Username = n.username.SecretField()
+			e.Password = n.password.SecretField()
+		}
+		sum.NotificationEndpoint = e
+	case notificationKindTeams:
+		sum.Kind = KindNotificationEndpointTeams
+		e := &endpoint.Teams{
+			Base: base,
+			URL:  n.url,
+		}
+		if n.secretURLSuffix.hasValue() {
+			e.SecretURLSuffix = n.secretURLSuffix.SecretField()
+		}
+		sum.NotificationEndpoint = e
+	case notificationKindOpsgenie:
+		sum.Kind = KindNotificationEndpointOpsgenie
+		sum.NotificationEndpoint = &endpoint.Opsgenie{
+			Base:   base,
+			URL:    n.url,
+			APIKey: n.apiKey.SecretField(),
+			Entity: n.entity,
+		}
 	}
 	return sum
 }
@@ -1574,7 +1770,9 @@ func (n *notificationEndpoint) valid() []validationErr {
 		failures = append(failures, err)
 	}
 
-	if _, err := url.Parse(n.url); err != nil || n.url == "" {
+	// smtp endpoints have no url and opsgenie defaults to the public alert API.
+	urlRequired := n.kind != notificationKindSMTP && n.kind != notificationKindOpsgenie
+	if _, err := url.Parse(n.url); err != nil || (urlRequired && n.url == "") {
 		failures = append(failures, validationErr{
 			Field: fieldNotificationEndpointURL,
 			Msg:   "must be valid url",
@@ -1639,6 +1837,38 @@ func (n *notificationEndpoint) valid() []validationErr {
 				),
 			})
 		}
+	case notificationKindSMTP:
+		if n.host == "" {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointHost,
+				Msg:   "must provide non empty string",
+			})
+		}
+		if n.port <= 0 || n.port > 65535 {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointPort,
+				Msg:   "must be a valid port",
+			})
+		}
+		if _, err := mail.ParseAddress(n.from); err != nil {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointFrom,
+				Msg:   "must be a valid email address",
+			})
+		}
+		if n.username.hasValue() != n.password.hasValue() {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointPassword,
+				Msg:   "must provide username and password together",
+			})
+		}
+	case notificationKindOpsgenie:
+		if !n.apiKey.hasValue() {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointAPIKey,
+				Msg:   "must be provided",
+			})
+		}
 	}
 
 	if len(failures) > 0 {
@@ -1651,13 +1881,19 @@ func (n *notificationEndpoint) valid() []validationErr {
 }
 
 const (
-	fieldNotificationRuleChannel         = "channel"
-	fieldNotificationRuleCurrentLevel    = "currentLevel"
-	fieldNotificationRuleEndpointName    = "endpointName"
-	fieldNotificationRuleMessageTemplate = "messageTemplate"
-	fieldNotificationRulePreviousLevel   = "previousLevel"
-	fieldNotificationRuleStatusRules     = "statusRules"
-	fieldNotificationRuleTagRules        = "tagRules"
+	fieldNotificationRuleChannel             = "channel"
+	fieldNotificationRuleCurrentLevel        = "currentLevel"
+	fieldNotificationRuleEndpointName        = "endpointName"
+	fieldNotificationRuleDescriptionTemplate = "descriptionTemplate"
+	fieldNotificationRuleMessageTemplate     = "messageTemplate"
+	fieldNotificationRulePreviousLevel       = "previousLevel"
+	fieldNotificationRulePriority            = "priority"
+	fieldNotificationRuleResponders          = "responders"
+	fieldNotificationRuleStatusRules         = "statusRules"
+	fieldNotificationRuleSubjectTemplate     = "subjectTemplate"
+	fieldNotificationRuleTagRules            = "tagRules"
+	fieldNotificationRuleTags                = "tags"
+	fieldNotificationRuleTo                  = "to"
 )
 
 type notificationRule struct {
@@ -1665,12 +1901,18 @@ type notificationRule struct {
 
 	channel     string
 	description string
+	descTmpl    string
 	every       time.Duration
 	msgTemplate string
 	offset      time.Duration
+	priority    string
+	responders  []string
 	status      string
 	statusRules []struct{ curLvl, prevLvl string }
+	subject     string
 	tagRules    []struct{ k, v, op string }
+	tags        []string
+	to          string
 
 	associatedEndpoint *notificationEndpoint
 	endpointName       *references
@@ -1718,17 +1960,23 @@ func (r *notificationRule) summarize() SummaryNotificationRule {
 			MetaName:      r.MetaName(),
 			EnvReferences: envRefs,
 		},
-		Name:              r.Name(),
-		EndpointMetaName:  endpointPkgName,
-		EndpointType:      endpointType,
-		Description:       r.description,
-		Every:             r.every.String(),
-		LabelAssociations: toSummaryLabels(r.labels...),
-		Offset:            r.offset.String(),
-		MessageTemplate:   r.msgTemplate,
-		Status:            r.Status(),
-		StatusRules:       toSummaryStatusRules(r.statusRules),
-		TagRules:          toSummaryTagRules(r.tagRules),
+		Name:                r.Name(),
+		EndpointMetaName:    endpointPkgName,
+		EndpointType:        endpointType,
+		Description:         r.description,
+		Every:               r.every.String(),
+		LabelAssociations:   toSummaryLabels(r.labels...),
+		Offset:              r.offset.String(),
+		MessageTemplate:     r.msgTemplate,
+		SubjectTemplate:     r.subject,
+		To:                  r.to,
+		DescriptionTemplate: r.descTmpl,
+		Priority:            r.priority,
+		Responders:          r.responders,
+		Tags:                r.tags,
+		Status:              r.Status(),
+		StatusRules:         toSummaryStatusRules(r.statusRules),
+		TagRules:            toSummaryTagRules(r.tagRules),
 	}
 }
 
@@ -1762,7 +2010,10 @@ func (r *notificationRule) toInfluxRule() influxdb.NotificationRule {
 
 	switch r.associatedEndpoint.kind {
 	case notificationKindHTTP:
-		return &rule.HTTP{Base: base}
+		return &rule.HTTP{
+			Base:         base,
+			BodyTemplate: r.msgTemplate,
+		}
 	case notificationKindPagerDuty:
 		return &rule.PagerDuty{
 			Base:            base,
@@ -1774,6 +2025,28 @@ func (r *notificationRule) toInfluxRule() influxdb.NotificationRule {
 			Channel:         r.channel,
 			MessageTemplate: r.msgTemplate,
 		}
+	case notificationKindSMTP:
+		return &rule.SMTP{
+			Base:            base,
+			To:              r.to,
+			SubjectTemplate: r.subject,
+			BodyTemplate:    r.msgTemplate,
+		}
+	case notificationKindTeams:
+		return &rule.Teams{
+			Base:            base,
+			Title:           r.subject,
+			MessageTemplate: r.msgTemplate,
+		}
+	case notificationKindOpsgenie:
+		return &rule.Opsgenie{
+			Base:                base,
+			MessageTemplate:     r.msgTemplate,
+			DescriptionTemplate: r.descTmpl,
+			Priority:            r.priority,
+			Responders:          r.responders,
+			Tags:                r.tags,
+		}
 	}
 	return nil
 }
@@ -1801,6 +2074,30 @@ func (r *notificationRule) valid() []validationErr {
 			Msg:   "must be provided",
 		})
 	}
+	if r.associatedEndpoint != nil && r.associatedEndpoint.kind == notificationKindSMTP {
+		if _, err := mail.ParseAddressList(r.to); err != nil {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldNotificationRuleTo,
+				Msg:   fmt.Sprintf("must be a comma separated list of email addresses; got=%q", r.to),
+			})
+		}
+		if r.subject == "" {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldNotificationRuleSubjectTemplate,
+				Msg:   "must be provided",
+			})
+		}
+	}
+	if r.associatedEndpoint != nil && r.associatedEndpoint.kind == notificationKindOpsgenie {
+		switch r.priority {
+		case "", "P1", "P2", "P3", "P4", "P5":
+		default:
+			vErrs = append(vErrs, validationErr{
+				Field: fieldNotificationRulePriority,
+				Msg:   fmt.Sprintf("must be 1 in [P1, P2, P3, P4, P5]; got=%q", r.priority),
+			})
+		}
+	}
 	if status := r.Status(); status != influxdb.Active && status != influxdb.Inactive {
 		vErrs = append(vErrs, validationErr{
 			Field: fieldStatus,
@@ -578,6 +578,93 @@ spec:
 			})
 		})
 
+		t.Run("anomaly check", func(t *testing.T) {
+			testfileRunner(t, "testdata/checks_anomaly.yml", func(t *testing.T, template *Template) {
+				sum := template.Summary()
+				require.Len(t, sum.Checks, 1)
+
+				check1 := sum.Checks[0]
+				assert.Equal(t, KindCheckAnomaly, check1.Kind)
+				anomalyCheck, ok := check1.Check.(*icheck.Anomaly)
+				require.Truef(t, ok, "got: %#v", check1)
+
+				expectedBase := icheck.Base{
+					Name:                  "check-2",
+					Description:           "desc_2",
+					Every:                 mustDuration(t, time.Hour),
+					StatusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }",
+				}
+				expectedBase.Query.Text = "from(bucket: \"rucket_1\")\n  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)\n  |> filter(fn: (r) => r._measurement == \"cpu\")\n  |> filter(fn: (r) => r._field == \"usage_idle\")\n  |> aggregateWindow(every: 1h, fn: mean)"
+				assert.Equal(t, expectedBase, anomalyCheck.Base)
+				assert.Equal(t, icheck.AnomalyMAD, anomalyCheck.Method)
+				assert.Equal(t, mustDuration(t, 4*7*24*time.Hour), anomalyCheck.Window)
+				assert.Equal(t, icheck.AnomalyHourOfWeek, anomalyCheck.Seasonality)
+
+				expectedThresholds := []icheck.AnomalyThreshold{
+					{Level: notification.Warn, Deviations: 3.0},
+					{Level: notification.Critical, Deviations: 5.0},
+				}
+				assert.Equal(t, expectedThresholds, anomalyCheck.Thresholds)
+				assert.Equal(t, influxdb.Active, check1.Status)
+			})
+		})
+
+		t.Run("rate of change check", func(t *testing.T) {
+			testfileRunner(t, "testdata/checks_rate_of_change.yml", func(t *testing.T, template *Template) {
+				sum := template.Summary()
+				require.Len(t, sum.Checks, 1)
+
+				check1 := sum.Checks[0]
+				assert.Equal(t, KindCheckRateOfChange, check1.Kind)
+				rateCheck, ok := check1.Check.(*icheck.RateOfChange)
+				require.Truef(t, ok, "got: %#v", check1)
+
+				expectedBase := icheck.Base{
+					Name:                  "check-3",
+					Description:           "desc_3",
+					Every:                 mustDuration(t, 5*time.Minute),
+					StatusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }",
+				}
+				expectedBase.Query.Text = "from(bucket: \"rucket_1\")\n  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)\n  |> filter(fn: (r) => r._measurement == \"disk\")\n  |> filter(fn: (r) => r._field == \"used_percent\")\n  |> aggregateWindow(every: 5m, fn: mean)"
+				assert.Equal(t, expectedBase, rateCheck.Base)
+				assert.Equal(t, mustDuration(t, time.Hour), rateCheck.Period)
+				assert.True(t, rateCheck.Percent)
+
+				expectedThresholds := []icheck.ThresholdConfig{
+					icheck.Greater{
+						ThresholdConfigBase: icheck.ThresholdConfigBase{Level: notification.Critical},
+						Value:               10.0,
+					},
+				}
+				assert.Equal(t, expectedThresholds, rateCheck.Thresholds)
+			})
+		})
+
+		t.Run("absence check", func(t *testing.T) {
+			testfileRunner(t, "testdata/checks_absence.yml", func(t *testing.T, template *Template) {
+				sum := template.Summary()
+				require.Len(t, sum.Checks, 1)
+
+				check1 := sum.Checks[0]
+				assert.Equal(t, KindCheckAbsence, check1.Kind)
+				absenceCheck, ok := check1.Check.(*icheck.Absence)
+				require.Truef(t, ok, "got: %#v", check1)
+
+				expectedBase := icheck.Base{
+					Name:                  "check-4",
+					Description:           "desc_4",
+					Every:                 mustDuration(t, time.Minute),
+					StatusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }",
+				}
+				expectedBase.Query.Text = "from(bucket: \"rucket_1\")\n  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)\n  |> filter(fn: (r) => r._measurement == \"cpu\")"
+				assert.Equal(t, expectedBase, absenceCheck.Base)
+				assert.Equal(t, []string{"host"}, absenceCheck.Series)
+				assert.Equal(t, mustDuration(t, 24*time.Hour), absenceCheck.Lookback)
+				assert.Equal(t, mustDuration(t, 10*time.Minute), absenceCheck.TimeSince)
+				assert.Equal(t, notification.Warn, absenceCheck.Level)
+			})
+		})
+
 		t.Run("with env refs should be successful", func(t *testing.T) {
 			testfileRunner(t, "testdata/checks_ref.yml", func(t *testing.T, template *Template) {
 				actual := template.Summary().Checks
@@ -811,6 +898,202 @@ spec:
     - type: greater
       level: CRIT
       value: 50.0
+`,
+					},
+				},
+				{
+					kind: KindCheckAnomaly,
+					resErr: testTemplateResourceError{
+						name:           "invalid anomaly method",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldCheckMethod},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: CheckAnomaly
+metadata:
+  name: check-2
+spec:
+  every: 1h
+  query:  >
+    from(bucket: "rucket_1") |> yield(name: "mean")
+  statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
+  method: zscore
+  window: 1d
+  thresholds:
+    - level: CRIT
+      deviations: 3.0
+`,
+					},
+				},
+				{
+					kind: KindCheckAnomaly,
+					resErr: testTemplateResourceError{
+						name:           "anomaly window not greater than every",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldCheckWindow},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: CheckAnomaly
+metadata:
+  name: check-2
+spec:
+  every: 1h
+  query:  >
+    from(bucket: "rucket_1") |> yield(name: "mean")
+  statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
+  method: stddev
+  window: 1h
+  thresholds:
+    - level: CRIT
+      deviations: 3.0
+`,
+					},
+				},
+				{
+					kind: KindCheckAnomaly,
+					resErr: testTemplateResourceError{
+						name:           "invalid anomaly seasonality",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldCheckSeasonality},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: CheckAnomaly
+metadata:
+  name: check-2
+spec:
+  every: 1h
+  query:  >
+    from(bucket: "rucket_1") |> yield(name: "mean")
+  statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
+  method: stddev
+  window: 4w
+  seasonality: dayOfWeek
+  thresholds:
+    - level: CRIT
+      deviations: 3.0
+`,
+					},
+				},
+				{
+					kind: KindCheckAnomaly,
+					resErr: testTemplateResourceError{
+						name:           "no anomaly thresholds provided",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldCheckThresholds},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: CheckAnomaly
+metadata:
+  name: check-2
+spec:
+  every: 1h
+  query:  >
+    from(bucket: "rucket_1") |> yield(name: "mean")
+  statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
+  method: stddev
+  window: 1d
+`,
+					},
+				},
+				{
+					kind: KindCheckAnomaly,
+					resErr: testTemplateResourceError{
+						name:           "anomaly threshold missing deviations",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldCheckDeviations},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: CheckAnomaly
+metadata:
+  name: check-2
+spec:
+  every: 1h
+  query:  >
+    from(bucket: "rucket_1") |> yield(name: "mean")
+  statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
+  method: stddev
+  window: 1d
+  thresholds:
+    - level: CRIT
+`,
+					},
+				},
+				{
+					kind: KindCheckRateOfChange,
+					resErr: testTemplateResourceError{
+						name:           "rate of change period not greater than every",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldCheckPeriod},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: CheckRateOfChange
+metadata:
+  name: check-3
+spec:
+  every: 1h
+  query:  >
+    from(bucket: "rucket_1") |> yield(name: "mean")
+  statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
+  period: 1h
+  thresholds:
+    - type: greater
+      level: CRIT
+      value: 10.0
+`,
+					},
+				},
+				{
+					kind: KindCheckRateOfChange,
+					resErr: testTemplateResourceError{
+						name:           "no rate of change thresholds provided",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldCheckThresholds},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: CheckRateOfChange
+metadata:
+  name: check-3
+spec:
+  every: 1h
+  query:  >
+    from(bucket: "rucket_1") |> yield(name: "mean")
+  statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
+  period: 1d
+`,
+					},
+				},
+				{
+					kind: KindCheckAbsence,
+					resErr: testTemplateResourceError{
+						name:           "absence lookback not greater than timeSince",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldCheckLookback},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: CheckAbsence
+metadata:
+  name: check-3
+spec:
+  every: 1h
+  query:  >
+    from(bucket: "rucket_1") |> yield(name: "mean")
+  statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
+  level: crit
+  lookback: 10m
+  timeSince: 10m
+`,
+					},
+				},
+				{
+					kind: KindCheckAbsence,
+					resErr: testTemplateResourceError{
+						name:           "invalid absence level",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldLevel},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: CheckAbsence
+metadata:
+  name: check-3
+spec:
+  every: 1h
+  query:  >
+    from(bucket: "rucket_1") |> yield(name: "mean")
+  statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
+  level: RANDO
+  lookback: 1d
+  timeSince: 10m
 `,
 					},
 				},
@@ -3154,6 +3437,80 @@ spec:
 			})
 		})
 
+		t.Run("smtp rule with smtp endpoint", func(t *testing.T) {
+			testfileRunner(t, "testdata/notification_rule_smtp.yml", func(t *testing.T, template *Template) {
+				sum := template.Summary()
+				require.Len(t, sum.NotificationEndpoints, 1)
+
+				e := sum.NotificationEndpoints[0]
+				assert.Equal(t, KindNotificationEndpointSMTP, e.Kind)
+				smtpEndpoint, ok := e.NotificationEndpoint.(*endpoint.SMTP)
+				require.True(t, ok)
+				assert.Equal(t, "smtp.example.com", smtpEndpoint.Host)
+				assert.Equal(t, 587, smtpEndpoint.Port)
+				assert.True(t, smtpEndpoint.StartTLS)
+				assert.Equal(t, "alerts@example.com", smtpEndpoint.From)
+				assert.Equal(t, "smtp-username", smtpEndpoint.Username.Key)
+				assert.Equal(t, "smtp-password", smtpEndpoint.Password.Key)
+
+				require.Len(t, sum.NotificationRules, 1)
+				rule := sum.NotificationRules[0]
+				assert.Equal(t, "smtp-relay", rule.EndpointMetaName)
+				assert.Equal(t, endpoint.SMTPType, rule.EndpointType)
+				assert.Equal(t, "${ r._check_name } is ${ r._level }", rule.SubjectTemplate)
+				assert.Equal(t, "${ r._message }", rule.MessageTemplate)
+				assert.Equal(t, "oncall@example.com, ops@example.com", rule.To)
+			})
+		})
+
+		t.Run("teams and opsgenie rules with their endpoints", func(t *testing.T) {
+			testfileRunner(t, "testdata/notification_rule_teams_opsgenie.yml", func(t *testing.T, template *Template) {
+				sum := template.Summary()
+				require.Len(t, sum.NotificationEndpoints, 2)
+
+				endpoints := make(map[string]SummaryNotificationEndpoint)
+				for _, e := range sum.NotificationEndpoints {
+					endpoints[e.MetaName] = e
+				}
+
+				e := endpoints["teams-channel"]
+				assert.Equal(t, KindNotificationEndpointTeams, e.Kind)
+				teamsEndpoint, ok := e.NotificationEndpoint.(*endpoint.Teams)
+				require.True(t, ok)
+				assert.Equal(t, "https://outlook.office.com/webhook/", teamsEndpoint.URL)
+				assert.Equal(t, "teams-suffix", teamsEndpoint.SecretURLSuffix.Key)
+
+				e = endpoints["opsgenie-ops"]
+				assert.Equal(t, KindNotificationEndpointOpsgenie, e.Kind)
+				opsgenieEndpoint, ok := e.NotificationEndpoint.(*endpoint.Opsgenie)
+				require.True(t, ok)
+				assert.Empty(t, opsgenieEndpoint.URL)
+				assert.Equal(t, "db", opsgenieEndpoint.Entity)
+				assert.Equal(t, "opsgenie-key", opsgenieEndpoint.APIKey.Key)
+
+				require.Len(t, sum.NotificationRules, 2)
+				rules := make(map[string]SummaryNotificationRule)
+				for _, r := range sum.NotificationRules {
+					rules[r.MetaName] = r
+				}
+
+				r := rules["rule-teams"]
+				assert.Equal(t, "teams-channel", r.EndpointMetaName)
+				assert.Equal(t, endpoint.TeamsType, r.EndpointType)
+				assert.Equal(t, "${ r._check_name } is ${ r._level }", r.SubjectTemplate)
+				assert.Equal(t, "${ r._message }", r.MessageTemplate)
+
+				r = rules["rule-opsgenie"]
+				assert.Equal(t, "opsgenie-ops", r.EndpointMetaName)
+				assert.Equal(t, endpoint.OpsgenieType, r.EndpointType)
+				assert.Equal(t, "${ r._check_name } is ${ r._level }", r.MessageTemplate)
+				assert.Equal(t, "${ r._message }", r.DescriptionTemplate)
+				assert.Equal(t, "P2", r.Priority)
+				assert.Equal(t, []string{"team:ops"}, r.Responders)
+				assert.Equal(t, []string{"influxdb"}, r.Tags)
+			})
+		})
+
 		t.Run("handles bad config", func(t *testing.T) {
 			templateWithValidEndpint := func(resource string) string {
 				return fmt.Sprintf(`
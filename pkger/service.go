@@ -1454,11 +1454,14 @@ func ApplyWithResourceSkip(action ActionSkipResource) ApplyOptFn {
 			opt.ResourcesToSkip = make(map[ActionSkipResource]bool)
 		}
 		switch action.Kind {
-		case KindCheckDeadman, KindCheckThreshold:
+		case KindCheckAbsence, KindCheckAnomaly, KindCheckDeadman, KindCheckRateOfChange, KindCheckThreshold:
 			action.Kind = KindCheck
 		case KindNotificationEndpointHTTP,
 			KindNotificationEndpointPagerDuty,
-			KindNotificationEndpointSlack:
+			KindNotificationEndpointSlack,
+			KindNotificationEndpointSMTP,
+			KindNotificationEndpointTeams,
+			KindNotificationEndpointOpsgenie:
 			action.Kind = KindNotificationEndpoint
 		}
 		opt.ResourcesToSkip[action] = true
@@ -1472,11 +1475,14 @@ func ApplyWithKindSkip(action ActionSkipKind) ApplyOptFn {
 			opt.KindsToSkip = make(map[Kind]bool)
 		}
 		switch action.Kind {
-		case KindCheckDeadman, KindCheckThreshold:
+		case KindCheckAbsence, KindCheckAnomaly, KindCheckDeadman, KindCheckRateOfChange, KindCheckThreshold:
 			action.Kind = KindCheck
 		case KindNotificationEndpointHTTP,
 			KindNotificationEndpointPagerDuty,
-			KindNotificationEndpointSlack:
+			KindNotificationEndpointSlack,
+			KindNotificationEndpointSMTP,
+			KindNotificationEndpointTeams,
+			KindNotificationEndpointOpsgenie:
 			action.Kind = KindNotificationEndpoint
 		}
 		opt.KindsToSkip[action.Kind] = true
@@ -2389,6 +2395,12 @@ func (s *Service) rollbackNotificationRules(ctx context.Context, userID influxdb
 				rr.EndpointID = endpointID
 			case *rule.Slack:
 				rr.EndpointID = endpointID
+			case *rule.SMTP:
+				rr.EndpointID = endpointID
+			case *rule.Teams:
+				rr.EndpointID = endpointID
+			case *rule.Opsgenie:
+				rr.EndpointID = endpointID
 			}
 			return r.existing
 		}
@@ -508,7 +508,7 @@ func (s *stateCoordinator) get(k Kind, metaName string) (interface{}, bool) {
 	case KindBucket:
 		v, ok := s.mBuckets[metaName]
 		return v, ok
-	case KindCheck, KindCheckDeadman, KindCheckThreshold:
+	case KindCheck, KindCheckAbsence, KindCheckAnomaly, KindCheckDeadman, KindCheckRateOfChange, KindCheckThreshold:
 		v, ok := s.mChecks[metaName]
 		return v, ok
 	case KindDashboard:
@@ -520,7 +520,10 @@ func (s *stateCoordinator) get(k Kind, metaName string) (interface{}, bool) {
 	case KindNotificationEndpoint,
 		KindNotificationEndpointHTTP,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointSMTP,
+		KindNotificationEndpointTeams,
+		KindNotificationEndpointOpsgenie:
 		v, ok := s.mEndpoints[metaName]
 		return v, ok
 	case KindNotificationRule:
@@ -582,7 +585,7 @@ func (s *stateCoordinator) addObjectForRemoval(k Kind, metaName string, id influ
 			parserBkt:   &bucket{identity: newIdentity},
 			stateStatus: StateStatusRemove,
 		}
-	case KindCheck, KindCheckDeadman, KindCheckThreshold:
+	case KindCheck, KindCheckAbsence, KindCheckAnomaly, KindCheckDeadman, KindCheckRateOfChange, KindCheckThreshold:
 		s.mChecks[metaName] = &stateCheck{
 			id:          id,
 			parserCheck: &check{identity: newIdentity},
@@ -603,7 +606,10 @@ func (s *stateCoordinator) addObjectForRemoval(k Kind, metaName string, id influ
 	case KindNotificationEndpoint,
 		KindNotificationEndpointHTTP,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointSMTP,
+		KindNotificationEndpointTeams,
+		KindNotificationEndpointOpsgenie:
 		s.mEndpoints[metaName] = &stateEndpoint{
 			id:             id,
 			parserEndpoint: &notificationEndpoint{identity: newIdentity},
@@ -644,7 +650,7 @@ func (s *stateCoordinator) getObjectIDSetter(k Kind, metaName string) (func(infl
 			r.id = id
 			r.stateStatus = StateStatusExists
 		}, ok
-	case KindCheck, KindCheckDeadman, KindCheckThreshold:
+	case KindCheck, KindCheckAbsence, KindCheckAnomaly, KindCheckDeadman, KindCheckRateOfChange, KindCheckThreshold:
 		r, ok := s.mChecks[metaName]
 		return func(id influxdb.ID) {
 			r.id = id
@@ -665,7 +671,10 @@ func (s *stateCoordinator) getObjectIDSetter(k Kind, metaName string) (func(infl
 	case KindNotificationEndpoint,
 		KindNotificationEndpointHTTP,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointSMTP,
+		KindNotificationEndpointTeams,
+		KindNotificationEndpointOpsgenie:
 		r, ok := s.mEndpoints[metaName]
 		return func(id influxdb.ID) {
 			r.id = id
@@ -1208,16 +1217,22 @@ func (r *stateRule) diffRule() DiffNotificationRule {
 			MetaName:    r.parserRule.MetaName(),
 		},
 		New: DiffNotificationRuleValues{
-			Name:            r.parserRule.Name(),
-			Description:     r.parserRule.description,
-			EndpointName:    r.endpointTemplateName(),
-			EndpointID:      SafeID(r.endpointID()),
-			EndpointType:    r.endpointType(),
-			Every:           r.parserRule.every.String(),
-			Offset:          r.parserRule.offset.String(),
-			MessageTemplate: r.parserRule.msgTemplate,
-			StatusRules:     toSummaryStatusRules(r.parserRule.statusRules),
-			TagRules:        toSummaryTagRules(r.parserRule.tagRules),
+			Name:                r.parserRule.Name(),
+			Description:         r.parserRule.description,
+			EndpointName:        r.endpointTemplateName(),
+			EndpointID:          SafeID(r.endpointID()),
+			EndpointType:        r.endpointType(),
+			Every:               r.parserRule.every.String(),
+			Offset:              r.parserRule.offset.String(),
+			MessageTemplate:     r.parserRule.msgTemplate,
+			SubjectTemplate:     r.parserRule.subject,
+			To:                  r.parserRule.to,
+			DescriptionTemplate: r.parserRule.descTmpl,
+			Priority:            r.parserRule.priority,
+			Responders:          r.parserRule.responders,
+			Tags:                r.parserRule.tags,
+			StatusRules:         toSummaryStatusRules(r.parserRule.statusRules),
+			TagRules:            toSummaryTagRules(r.parserRule.tagRules),
 		},
 	}
 
@@ -1259,12 +1274,29 @@ func (r *stateRule) diffRule() DiffNotificationRule {
 	switch p := r.existing.(type) {
 	case *rule.HTTP:
 		assignBase(p.Base)
+		sum.Old.MessageTemplate = p.BodyTemplate
 	case *rule.Slack:
 		assignBase(p.Base)
 		sum.Old.MessageTemplate = p.MessageTemplate
 	case *rule.PagerDuty:
 		assignBase(p.Base)
 		sum.Old.MessageTemplate = p.MessageTemplate
+	case *rule.SMTP:
+		assignBase(p.Base)
+		sum.Old.MessageTemplate = p.BodyTemplate
+		sum.Old.SubjectTemplate = p.SubjectTemplate
+		sum.Old.To = p.To
+	case *rule.Teams:
+		assignBase(p.Base)
+		sum.Old.MessageTemplate = p.MessageTemplate
+		sum.Old.SubjectTemplate = p.Title
+	case *rule.Opsgenie:
+		assignBase(p.Base)
+		sum.Old.MessageTemplate = p.MessageTemplate
+		sum.Old.DescriptionTemplate = p.DescriptionTemplate
+		sum.Old.Priority = p.Priority
+		sum.Old.Responders = p.Responders
+		sum.Old.Tags = p.Tags
 	}
 
 	return sum
@@ -1334,6 +1366,12 @@ func (r *stateRule) toInfluxRule() influxdb.NotificationRule {
 		e.EndpointID = r.associatedEndpoint.ID()
 	case *rule.Slack:
 		e.EndpointID = r.associatedEndpoint.ID()
+	case *rule.SMTP:
+		e.EndpointID = r.associatedEndpoint.ID()
+	case *rule.Teams:
+		e.EndpointID = r.associatedEndpoint.ID()
+	case *rule.Opsgenie:
+		e.EndpointID = r.associatedEndpoint.ID()
 	}
 
 	return influxRule
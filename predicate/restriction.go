@@ -0,0 +1,283 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage/reads/datatypes"
+)
+
+// Restrictions is a compiled set of permission restrictions.
+// Data is accessible when it matches any one of the restrictions.
+type Restrictions []Restriction
+
+// Restriction is a compiled influxdb.PermissionRestriction.
+type Restriction struct {
+	// measurements is nil when the restriction applies to every measurement.
+	measurements map[string]struct{}
+	// node is the tag predicate, nil when every series matches.
+	node *datatypes.Node
+}
+
+// NewRestrictions compiles the restrictions of a permission set.
+func NewRestrictions(rs []influxdb.PermissionRestriction) (Restrictions, error) {
+	out := make(Restrictions, 0, len(rs))
+	for _, r := range rs {
+		c, err := NewRestriction(r)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// NewRestriction compiles a single permission restriction.
+func NewRestriction(r influxdb.PermissionRestriction) (Restriction, error) {
+	if err := r.Valid(); err != nil {
+		return Restriction{}, err
+	}
+
+	var c Restriction
+	if len(r.Measurements) > 0 {
+		c.measurements = make(map[string]struct{}, len(r.Measurements))
+		for _, m := range r.Measurements {
+			c.measurements[m] = struct{}{}
+		}
+	}
+
+	n, err := Parse(r.Predicate)
+	if err != nil {
+		return Restriction{}, err
+	}
+	if n != nil {
+		if c.node, err = n.ToDataType(); err != nil {
+			return Restriction{}, err
+		}
+		if err := validateRestrictionNode(c.node); err != nil {
+			return Restriction{}, err
+		}
+	}
+	return c, nil
+}
+
+// RestrictionsFromContext returns the restrictions the authorizer of ctx is
+// subject to when performing action on the bucket. Nil restrictions are
+// returned when the access is unrestricted, or when ctx is marked unrestricted
+// by a trusted internal caller. A ctx without an authorizer is unauthorized.
+func RestrictionsFromContext(ctx context.Context, action influxdb.Action, orgID, bucketID influxdb.ID) (Restrictions, error) {
+	if icontext.IsUnrestricted(ctx) {
+		return nil, nil
+	}
+	auth, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "permission restrictions cannot be checked without an authorizer",
+			Err:  err,
+		}
+	}
+	ps, err := auth.PermissionSet()
+	if err != nil {
+		return nil, err
+	}
+	rs, ok := ps.Restrictions(influxdb.Permission{
+		Action: action,
+		Resource: influxdb.Resource{
+			Type:  influxdb.BucketsResourceType,
+			ID:    &bucketID,
+			OrgID: &orgID,
+		},
+	})
+	if !ok || len(rs) == 0 {
+		return nil, nil
+	}
+	return NewRestrictions(rs)
+}
+
+// validateRestrictionNode rejects predicates that cannot be evaluated
+// against a single series, such as predicates on the field key.
+func validateRestrictionNode(n *datatypes.Node) error {
+	if n.NodeType == datatypes.NodeTypeTagRef && n.GetTagRefValue() == models.FieldKeyTagKey {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "permission restriction predicates cannot reference _field",
+		}
+	}
+	for _, child := range n.Children {
+		if err := validateRestrictionNode(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AllowsMeasurement reports whether any restriction applies to the measurement.
+func (rs Restrictions) AllowsMeasurement(name string) bool {
+	for _, r := range rs {
+		if r.allowsMeasurement(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// MeasurementDataType returns the tag predicate series of the measurement
+// must match. The node is nil when every series of the measurement matches,
+// and ok is false when no series of the measurement matches.
+func (rs Restrictions) MeasurementDataType(name string) (n *datatypes.Node, ok bool) {
+	var nodes []*datatypes.Node
+	for _, r := range rs {
+		if !r.allowsMeasurement(name) {
+			continue
+		}
+		if r.node == nil {
+			return nil, true
+		}
+		nodes = append(nodes, r.node)
+	}
+	if len(nodes) == 0 {
+		return nil, false
+	}
+	return orNodes(nodes), true
+}
+
+// ToDataType converts the restrictions to a storage predicate that matches
+// every series allowed by any of the restrictions.
+func (rs Restrictions) ToDataType() (*datatypes.Node, error) {
+	if len(rs) == 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "no restrictions to convert",
+		}
+	}
+
+	nodes := make([]*datatypes.Node, 0, len(rs))
+	for _, r := range rs {
+		var parts []*datatypes.Node
+		if r.measurements != nil {
+			ms := make([]*datatypes.Node, 0, len(r.measurements))
+			for m := range r.measurements {
+				ms = append(ms, tagEqualNode(models.MeasurementTagKey, m))
+			}
+			parts = append(parts, orNodes(ms))
+		}
+		if r.node != nil {
+			parts = append(parts, r.node)
+		}
+		nodes = append(nodes, andNodes(parts))
+	}
+	return orNodes(nodes), nil
+}
+
+// Matches reports whether a series is allowed by any of the restrictions.
+func (rs Restrictions) Matches(name []byte, tags models.Tags) bool {
+	for _, r := range rs {
+		if !r.allowsMeasurement(string(name)) {
+			continue
+		}
+		if r.node == nil {
+			return true
+		}
+		ok, err := evalNode(r.node, name, tags)
+		if err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Restriction) allowsMeasurement(name string) bool {
+	if r.measurements == nil {
+		return true
+	}
+	_, ok := r.measurements[name]
+	return ok
+}
+
+// evalNode evaluates the nodes produced by Parse against a single series.
+func evalNode(n *datatypes.Node, name []byte, tags models.Tags) (bool, error) {
+	switch n.NodeType {
+	case datatypes.NodeTypeLogicalExpression:
+		and := n.GetLogical() == datatypes.LogicalAnd
+		for _, child := range n.Children {
+			ok, err := evalNode(child, name, tags)
+			if err != nil {
+				return false, err
+			}
+			if and && !ok {
+				return false, nil
+			}
+			if !and && ok {
+				return true, nil
+			}
+		}
+		return and, nil
+	case datatypes.NodeTypeParenExpression:
+		if len(n.Children) != 1 {
+			return false, fmt.Errorf("paren expression must have exactly one child")
+		}
+		return evalNode(n.Children[0], name, tags)
+	case datatypes.NodeTypeComparisonExpression:
+		if len(n.Children) != 2 {
+			return false, fmt.Errorf("comparison expression must have exactly two children")
+		}
+		key := n.Children[0].GetTagRefValue()
+		want := n.Children[1].GetStringValue()
+
+		var got string
+		if key == models.MeasurementTagKey {
+			got = string(name)
+		} else {
+			got = string(tags.Get([]byte(key)))
+		}
+
+		switch n.GetComparison() {
+		case datatypes.ComparisonEqual:
+			return got == want, nil
+		case datatypes.ComparisonNotEqual:
+			return got != want, nil
+		}
+		return false, fmt.Errorf("unsupported comparison %s", n.GetComparison())
+	}
+	return false, fmt.Errorf("unsupported node type %s", n.NodeType)
+}
+
+func tagEqualNode(key, value string) *datatypes.Node {
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeComparisonExpression,
+		Value:    &datatypes.Node_Comparison_{Comparison: datatypes.ComparisonEqual},
+		Children: []*datatypes.Node{
+			{
+				NodeType: datatypes.NodeTypeTagRef,
+				Value:    &datatypes.Node_TagRefValue{TagRefValue: key},
+			},
+			{
+				NodeType: datatypes.NodeTypeLiteral,
+				Value:    &datatypes.Node_StringValue{StringValue: value},
+			},
+		},
+	}
+}
+
+func andNodes(nodes []*datatypes.Node) *datatypes.Node {
+	return logicalNode(datatypes.LogicalAnd, nodes)
+}
+
+func orNodes(nodes []*datatypes.Node) *datatypes.Node {
+	return logicalNode(datatypes.LogicalOr, nodes)
+}
+
+func logicalNode(op datatypes.Node_Logical, nodes []*datatypes.Node) *datatypes.Node {
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeLogicalExpression,
+		Value:    &datatypes.Node_Logical_{Logical: op},
+		Children: nodes,
+	}
+}
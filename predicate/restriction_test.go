@@ -0,0 +1,146 @@
+package predicate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage/reads"
+	"github.com/influxdata/influxql"
+)
+
+func TestRestrictions_Matches(t *testing.T) {
+	rs, err := NewRestrictions([]influxdb.PermissionRestriction{
+		{Measurements: []string{"cpu", "mem"}, Predicate: `customer="acme"`},
+		{Measurements: []string{"disk"}},
+		{Predicate: `customer="globex" AND region!="eu"`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		key  string
+		exp  bool
+	}{
+		{name: "measurement and predicate", key: "cpu,customer=acme,host=a", exp: true},
+		{name: "measurement without predicate match", key: "mem,customer=initech", exp: false},
+		{name: "unrestricted measurement", key: "disk,customer=initech", exp: true},
+		{name: "any measurement with predicate", key: "net,customer=globex,region=us", exp: true},
+		{name: "not equal excludes", key: "net,customer=globex,region=eu", exp: false},
+		{name: "missing tag", key: "net,region=us", exp: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			name, tags := models.ParseKeyBytes([]byte(c.key))
+			if got := rs.Matches(name, tags); got != c.exp {
+				t.Errorf("unexpected match for %s: got %v, exp %v", c.key, got, c.exp)
+			}
+		})
+	}
+}
+
+func TestRestrictions_MeasurementDataType(t *testing.T) {
+	rs, err := NewRestrictions([]influxdb.PermissionRestriction{
+		{Measurements: []string{"cpu"}, Predicate: `customer="acme"`},
+		{Measurements: []string{"disk"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, ok := rs.MeasurementDataType("cpu"); !ok || n == nil {
+		t.Errorf("expected a predicate for cpu, got %v, %v", n, ok)
+	}
+	if n, ok := rs.MeasurementDataType("disk"); !ok || n != nil {
+		t.Errorf("expected disk to be unrestricted, got %v, %v", n, ok)
+	}
+	if _, ok := rs.MeasurementDataType("mem"); ok {
+		t.Error("expected mem not to be allowed")
+	}
+}
+
+func TestRestrictions_ToDataType(t *testing.T) {
+	rs, err := NewRestrictions([]influxdb.PermissionRestriction{
+		{Measurements: []string{"cpu"}, Predicate: `customer="acme"`},
+		{Predicate: `customer="globex"`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := rs.ToDataType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expr, err := reads.NodeToExpr(n, map[string]string{models.MeasurementTagKey: "_measurement"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := influxql.MustParseExpr(`_measurement::tag = 'cpu' AND customer::tag = 'acme' OR customer::tag = 'globex'`)
+	if got := influxql.Reduce(expr, nil).String(); got != exp.String() {
+		t.Errorf("unexpected expression: got %s, exp %s", got, exp)
+	}
+}
+
+func TestNewRestriction_Invalid(t *testing.T) {
+	cases := []struct {
+		name string
+		r    influxdb.PermissionRestriction
+	}{
+		{name: "empty", r: influxdb.PermissionRestriction{}},
+		{name: "empty measurement", r: influxdb.PermissionRestriction{Measurements: []string{""}}},
+		{name: "unparseable predicate", r: influxdb.PermissionRestriction{Predicate: `customer=`}},
+		{name: "field predicate", r: influxdb.PermissionRestriction{Predicate: `_field="usage"`}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := NewRestriction(c.r); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestRestrictionsFromContext(t *testing.T) {
+	orgID, bucketID, otherID := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+	bucketPerm := func(id influxdb.ID, action influxdb.Action, r *influxdb.PermissionRestriction) influxdb.Permission {
+		return influxdb.Permission{
+			Action: action,
+			Resource: influxdb.Resource{
+				Type:  influxdb.BucketsResourceType,
+				ID:    &id,
+				OrgID: &orgID,
+			},
+			Restriction: r,
+		}
+	}
+	ctx := icontext.SetAuthorizer(context.Background(), &influxdb.Authorization{
+		Status: influxdb.Active,
+		Permissions: []influxdb.Permission{
+			bucketPerm(bucketID, influxdb.ReadAction, &influxdb.PermissionRestriction{Predicate: `customer="acme"`}),
+			bucketPerm(otherID, influxdb.ReadAction, nil),
+		},
+	})
+
+	rs, err := RestrictionsFromContext(ctx, influxdb.ReadAction, orgID, bucketID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 1 {
+		t.Fatalf("expected 1 restriction, got %d", len(rs))
+	}
+
+	if rs, err := RestrictionsFromContext(ctx, influxdb.ReadAction, orgID, otherID); err != nil || rs != nil {
+		t.Errorf("expected unrestricted access to other bucket, got %v, %v", rs, err)
+	}
+	if _, err := RestrictionsFromContext(context.Background(), influxdb.ReadAction, orgID, bucketID); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Errorf("expected an unauthorized error without an authorizer, got %v", err)
+	}
+	if rs, err := RestrictionsFromContext(icontext.SetUnrestricted(ctx), influxdb.ReadAction, orgID, bucketID); err != nil || rs != nil {
+		t.Errorf("expected no restrictions for trusted callers, got %v, %v", rs, err)
+	}
+}
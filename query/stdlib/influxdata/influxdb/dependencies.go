@@ -6,6 +6,7 @@ import (
 	"github.com/influxdata/flux"
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/kit/prom"
+	"github.com/influxdata/influxdb/v2/notification/smtp"
 	"github.com/influxdata/influxdb/v2/query"
 	"github.com/influxdata/influxdb/v2/storage"
 	"github.com/prometheus/client_golang/prometheus"
@@ -77,6 +78,9 @@ func NewDependencies(
 ) (Dependencies, error) {
 	fdeps := flux.NewDefaultDependencies()
 	fdeps.Deps.SecretService = query.FromSecretService(ss)
+	// Email notification rules post to smtp:// urls; deliver those through the relay.
+	// Only the tasks of the rules are run with a context that allows it.
+	fdeps.Deps.HTTPClient = smtp.NewTaskClient(fdeps.Deps.HTTPClient)
 	deps := Dependencies{FluxDeps: fdeps}
 	bucketLookupSvc := query.FromBucketService(bucketSvc)
 	orgLookupSvc := query.FromOrganizationService(orgSvc)
@@ -0,0 +1,71 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// OrgQuota limits the resources an organization can use.
+// A zero limit means the resource is not limited.
+type OrgQuota struct {
+	OrgID ID `json:"orgID"`
+
+	MaxBuckets           int `json:"maxBuckets,omitempty"`
+	MaxTasks             int `json:"maxTasks,omitempty"`
+	MaxDashboards        int `json:"maxDashboards,omitempty"`
+	MaxChecks            int `json:"maxChecks,omitempty"`
+	MaxNotificationRules int `json:"maxNotificationRules,omitempty"`
+
+	// MaxRetentionSeconds is the longest retention period of a bucket. When set,
+	// buckets with an infinite retention period cannot be created.
+	MaxRetentionSeconds int64 `json:"maxRetentionSeconds,omitempty"`
+
+	// MaxWriteRequestsPerSecond and MaxWriteBytesPerSecond limit the write rate of the organization.
+	MaxWriteRequestsPerSecond int `json:"maxWriteRequestsPerSecond,omitempty"`
+	MaxWriteBytesPerSecond    int `json:"maxWriteBytesPerSecond,omitempty"`
+
+	// MaxSeriesCardinality is the number of series across all buckets of the organization.
+	MaxSeriesCardinality int64 `json:"maxSeriesCardinality,omitempty"`
+
+	CRUDLog
+}
+
+// MaxRetention returns the longest retention period of a bucket, zero means unlimited.
+func (q *OrgQuota) MaxRetention() time.Duration {
+	return time.Duration(q.MaxRetentionSeconds) * time.Second
+}
+
+// Valid returns an error if the quota is invalid.
+func (q *OrgQuota) Valid() error {
+	if !q.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "quota requires an organization id",
+		}
+	}
+	if q.MaxBuckets < 0 || q.MaxTasks < 0 || q.MaxDashboards < 0 || q.MaxChecks < 0 ||
+		q.MaxNotificationRules < 0 || q.MaxRetentionSeconds < 0 || q.MaxWriteRequestsPerSecond < 0 ||
+		q.MaxWriteBytesPerSecond < 0 || q.MaxSeriesCardinality < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "quota limits must not be negative",
+		}
+	}
+	return nil
+}
+
+// OrgQuotaService manages the quotas of organizations.
+type OrgQuotaService interface {
+	// FindOrgQuota returns the quota of the organization.
+	// An ENotFound error is returned when the organization has no quota.
+	FindOrgQuota(ctx context.Context, orgID ID) (*OrgQuota, error)
+
+	// FindOrgQuotas returns the quotas of every organization that has one.
+	FindOrgQuotas(ctx context.Context) ([]*OrgQuota, error)
+
+	// PutOrgQuota creates or replaces the quota of an organization.
+	PutOrgQuota(ctx context.Context, q *OrgQuota) error
+
+	// DeleteOrgQuota removes the quota of an organization.
+	DeleteOrgQuota(ctx context.Context, orgID ID) error
+}
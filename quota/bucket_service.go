@@ -0,0 +1,92 @@
+package quota
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.BucketService = (*BucketService)(nil)
+
+// BucketService enforces the retention quota of organizations, the bucket
+// count quota is enforced by the bucket store through BucketLimit. System
+// buckets are not limited.
+type BucketService struct {
+	influxdb.BucketService
+	quotas influxdb.OrgQuotaService
+}
+
+// NewBucketService wraps a bucket service with quota enforcement.
+func NewBucketService(s influxdb.BucketService, quotas influxdb.OrgQuotaService) *BucketService {
+	return &BucketService{
+		BucketService: s,
+		quotas:        quotas,
+	}
+}
+
+// CreateBucket checks that the retention of the bucket is within the quota of the organization.
+func (s *BucketService) CreateBucket(ctx context.Context, b *influxdb.Bucket) error {
+	if b.Type != influxdb.BucketTypeSystem {
+		q, err := findQuota(ctx, s.quotas, b.OrgID)
+		if err != nil {
+			return err
+		}
+		if q != nil {
+			if err := checkRetention(q, b.RetentionPeriod); err != nil {
+				return err
+			}
+		}
+	}
+	return s.BucketService.CreateBucket(ctx, b)
+}
+
+// UpdateBucket checks that an updated retention period is within the quota of the organization.
+func (s *BucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+	if upd.RetentionPeriod != nil {
+		b, err := s.BucketService.FindBucketByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if b.Type != influxdb.BucketTypeSystem {
+			q, err := findQuota(ctx, s.quotas, b.OrgID)
+			if err != nil {
+				return nil, err
+			}
+			if q != nil {
+				if err := checkRetention(q, *upd.RetentionPeriod); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return s.BucketService.UpdateBucket(ctx, id, upd)
+}
+
+// findOrgBuckets returns every bucket of the organization.
+func findOrgBuckets(ctx context.Context, s influxdb.BucketService, orgID influxdb.ID) ([]*influxdb.Bucket, error) {
+	var buckets []*influxdb.Bucket
+	for {
+		bs, _, err := s.FindBuckets(ctx, influxdb.BucketFilter{OrganizationID: &orgID}, influxdb.FindOptions{
+			Limit:  influxdb.MaxPageSize,
+			Offset: len(buckets),
+		})
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, bs...)
+		if len(bs) < influxdb.MaxPageSize {
+			return buckets, nil
+		}
+	}
+}
+
+// checkRetention returns an error when the retention period exceeds the quota,
+// an infinite retention period always exceeds a retention quota.
+func checkRetention(q *influxdb.OrgQuota, rp time.Duration) error {
+	max := q.MaxRetention()
+	if max > 0 && (rp == 0 || rp > max) {
+		return ErrRetentionExceeded(q)
+	}
+	return nil
+}
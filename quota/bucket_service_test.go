@@ -0,0 +1,62 @@
+package quota_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/quota"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketService(t *testing.T) {
+	ctx := context.Background()
+	quotas := newTestService(t)
+	require.NoError(t, quotas.PutOrgQuota(ctx, &influxdb.OrgQuota{
+		OrgID:               1,
+		MaxRetentionSeconds: int64((24 * time.Hour).Seconds()),
+	}))
+
+	var buckets []*influxdb.Bucket
+	underlying := mock.NewBucketService()
+	underlying.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		for _, b := range buckets {
+			if b.ID == id {
+				return b, nil
+			}
+		}
+		return nil, &influxdb.Error{Code: influxdb.ENotFound}
+	}
+	underlying.CreateBucketFn = func(ctx context.Context, b *influxdb.Bucket) error {
+		b.ID = influxdb.ID(len(buckets) + 1)
+		buckets = append(buckets, b)
+		return nil
+	}
+	underlying.UpdateBucketFn = func(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+		return &influxdb.Bucket{ID: id}, nil
+	}
+	svc := quota.NewBucketService(underlying, quotas)
+
+	// system buckets are not limited.
+	require.NoError(t, svc.CreateBucket(ctx, &influxdb.Bucket{OrgID: 1, Type: influxdb.BucketTypeSystem}))
+
+	err := svc.CreateBucket(ctx, &influxdb.Bucket{OrgID: 1})
+	assert.Equal(t, influxdb.EForbidden, influxdb.ErrorCode(err), "infinite retention exceeds the quota")
+	err = svc.CreateBucket(ctx, &influxdb.Bucket{OrgID: 1, RetentionPeriod: 48 * time.Hour})
+	assert.Equal(t, influxdb.EForbidden, influxdb.ErrorCode(err))
+
+	require.NoError(t, svc.CreateBucket(ctx, &influxdb.Bucket{OrgID: 1, RetentionPeriod: time.Hour}))
+
+	// organizations without a quota are not limited.
+	require.NoError(t, svc.CreateBucket(ctx, &influxdb.Bucket{OrgID: 2}))
+
+	rp := 72 * time.Hour
+	_, err = svc.UpdateBucket(ctx, 2, influxdb.BucketUpdate{RetentionPeriod: &rp})
+	assert.Equal(t, influxdb.EForbidden, influxdb.ErrorCode(err))
+	rp = time.Hour
+	_, err = svc.UpdateBucket(ctx, 2, influxdb.BucketUpdate{RetentionPeriod: &rp})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,56 @@
+package quota
+
+import (
+	"fmt"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var (
+	// ErrQuotaNotFound is used when the organization has no quota.
+	ErrQuotaNotFound = &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "quota not found",
+	}
+)
+
+// ErrInvalidOrgID is used when the ID of an organization cannot be encoded.
+func ErrInvalidOrgID(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "invalid organization ID",
+		Err:  err,
+	}
+}
+
+// ErrInternalService is used when the error comes from an internal system.
+func ErrInternalService(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Err:  err,
+	}
+}
+
+// ErrQuotaExceeded is used when creating a resource would exceed the quota of the organization.
+func ErrQuotaExceeded(resource string, max int) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EForbidden,
+		Msg:  fmt.Sprintf("organization has reached its quota of %d %s", max, resource),
+	}
+}
+
+// ErrRetentionExceeded is used when the retention period of a bucket exceeds the quota of the organization.
+func ErrRetentionExceeded(q *influxdb.OrgQuota) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EForbidden,
+		Msg:  fmt.Sprintf("retention period must not be longer than %s", q.MaxRetention()),
+	}
+}
+
+// ErrCardinalityExceeded is used when the series cardinality of the organization exceeds its quota.
+func ErrCardinalityExceeded(q *influxdb.OrgQuota) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EForbidden,
+		Msg:  fmt.Sprintf("organization has reached its quota of %d series", q.MaxSeriesCardinality),
+	}
+}
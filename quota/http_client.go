@@ -0,0 +1,69 @@
+package quota
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/pkg/httpc"
+)
+
+var _ influxdb.OrgQuotaService = (*ClientService)(nil)
+
+// ClientService connects to Influx via HTTP using tokens to manage organization quotas.
+type ClientService struct {
+	Client *httpc.Client
+}
+
+// FindOrgQuota returns the quota of the organization via HTTP.
+func (s *ClientService) FindOrgQuota(ctx context.Context, orgID influxdb.ID) (*influxdb.OrgQuota, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var q influxdb.OrgQuota
+	err := s.Client.
+		Get(PrefixQuotas, orgID.String()).
+		DecodeJSON(&q).
+		Do(ctx)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	return &q, nil
+}
+
+// FindOrgQuotas returns the quotas of every organization via HTTP.
+func (s *ClientService) FindOrgQuotas(ctx context.Context) ([]*influxdb.OrgQuota, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var resp quotasResponse
+	err := s.Client.
+		Get(PrefixQuotas).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	return resp.Quotas, nil
+}
+
+// PutOrgQuota creates or replaces the quota of an organization via HTTP.
+func (s *ClientService) PutOrgQuota(ctx context.Context, q *influxdb.OrgQuota) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.Client.
+		PutJSON(q, PrefixQuotas, q.OrgID.String()).
+		DecodeJSON(q).
+		Do(ctx)
+}
+
+// DeleteOrgQuota removes the quota of an organization via HTTP.
+func (s *ClientService) DeleteOrgQuota(ctx context.Context, orgID influxdb.ID) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.Client.
+		Delete(PrefixQuotas, orgID.String()).
+		Do(ctx)
+}
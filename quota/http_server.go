@@ -0,0 +1,131 @@
+package quota
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/influxdata/influxdb/v2"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"go.uber.org/zap"
+)
+
+// PrefixQuotas is the prefix of the organization quota API.
+const PrefixQuotas = "/api/v2/quotas"
+
+// Handler serves the quotas of organizations.
+type Handler struct {
+	chi.Router
+	api      *kithttp.API
+	log      *zap.Logger
+	quotaSvc influxdb.OrgQuotaService
+}
+
+// NewHTTPHandler constructs a new http server for organization quotas.
+func NewHTTPHandler(log *zap.Logger, svc influxdb.OrgQuotaService) *Handler {
+	h := &Handler{
+		api:      kithttp.NewAPI(kithttp.WithLog(log)),
+		log:      log,
+		quotaSvc: svc,
+	}
+
+	r := chi.NewRouter()
+	r.Use(
+		middleware.Recoverer,
+		middleware.RequestID,
+		middleware.RealIP,
+	)
+
+	r.Route("/", func(r chi.Router) {
+		r.Get("/", h.handleGetQuotas)
+
+		r.Route("/{orgID}", func(r chi.Router) {
+			r.Get("/", h.handleGetQuota)
+			r.Put("/", h.handlePutQuota)
+			r.Delete("/", h.handleDeleteQuota)
+		})
+	})
+
+	h.Router = r
+	return h
+}
+
+// Prefix returns the prefix the handler is mounted at.
+func (h *Handler) Prefix() string {
+	return PrefixQuotas
+}
+
+type quotasResponse struct {
+	Quotas []*influxdb.OrgQuota `json:"quotas"`
+}
+
+// handleGetQuotas is the HTTP handler for the GET /api/v2/quotas route.
+func (h *Handler) handleGetQuotas(w http.ResponseWriter, r *http.Request) {
+	qs, err := h.quotaSvc.FindOrgQuotas(r.Context())
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Quotas retrieved", zap.Int("count", len(qs)))
+
+	h.api.Respond(w, r, http.StatusOK, quotasResponse{Quotas: qs})
+}
+
+// handleGetQuota is the HTTP handler for the GET /api/v2/quotas/:orgID route.
+func (h *Handler) handleGetQuota(w http.ResponseWriter, r *http.Request) {
+	orgID, err := influxdb.IDFromString(chi.URLParam(r, "orgID"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	q, err := h.quotaSvc.FindOrgQuota(r.Context(), *orgID)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	h.api.Respond(w, r, http.StatusOK, q)
+}
+
+// handlePutQuota is the HTTP handler for the PUT /api/v2/quotas/:orgID route.
+func (h *Handler) handlePutQuota(w http.ResponseWriter, r *http.Request) {
+	orgID, err := influxdb.IDFromString(chi.URLParam(r, "orgID"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	var q influxdb.OrgQuota
+	if err := h.api.DecodeJSON(r.Body, &q); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	q.OrgID = *orgID
+
+	if err := h.quotaSvc.PutOrgQuota(r.Context(), &q); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Quota updated", zap.String("quota", fmt.Sprint(q)))
+
+	h.api.Respond(w, r, http.StatusOK, q)
+}
+
+// handleDeleteQuota is the HTTP handler for the DELETE /api/v2/quotas/:orgID route.
+func (h *Handler) handleDeleteQuota(w http.ResponseWriter, r *http.Request) {
+	orgID, err := influxdb.IDFromString(chi.URLParam(r, "orgID"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	if err := h.quotaSvc.DeleteOrgQuota(r.Context(), *orgID); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Quota deleted", zap.String("orgID", orgID.String()))
+
+	h.api.Respond(w, r, http.StatusNoContent, nil)
+}
@@ -0,0 +1,58 @@
+package quota
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/ratelimit"
+)
+
+var _ influxdb.OrgQuotaService = (*LimiterService)(nil)
+
+// LimiterService keeps the organization limits of a rate limiter in sync with
+// the write rate quotas.
+type LimiterService struct {
+	influxdb.OrgQuotaService
+	limiter *ratelimit.Limiter
+}
+
+// NewLimiterService applies the write rate quotas of every organization to the
+// limiter and wraps the quota service so that changes to quotas are applied too.
+func NewLimiterService(ctx context.Context, s influxdb.OrgQuotaService, l *ratelimit.Limiter) (*LimiterService, error) {
+	qs, err := s.FindOrgQuotas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range qs {
+		l.SetOrgLimits(q.OrgID, orgLimits(q))
+	}
+	return &LimiterService{
+		OrgQuotaService: s,
+		limiter:         l,
+	}, nil
+}
+
+// PutOrgQuota stores the quota and applies its write rate limits.
+func (s *LimiterService) PutOrgQuota(ctx context.Context, q *influxdb.OrgQuota) error {
+	if err := s.OrgQuotaService.PutOrgQuota(ctx, q); err != nil {
+		return err
+	}
+	s.limiter.SetOrgLimits(q.OrgID, orgLimits(q))
+	return nil
+}
+
+// DeleteOrgQuota removes the quota and its write rate limits.
+func (s *LimiterService) DeleteOrgQuota(ctx context.Context, orgID influxdb.ID) error {
+	if err := s.OrgQuotaService.DeleteOrgQuota(ctx, orgID); err != nil {
+		return err
+	}
+	s.limiter.SetOrgLimits(orgID, ratelimit.Limits{})
+	return nil
+}
+
+func orgLimits(q *influxdb.OrgQuota) ratelimit.Limits {
+	return ratelimit.Limits{
+		WriteRequestsPerSecond: q.MaxWriteRequestsPerSecond,
+		WriteBytesPerSecond:    q.MaxWriteBytesPerSecond,
+	}
+}
@@ -0,0 +1,54 @@
+package quota
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+)
+
+var _ influxdb.OrgQuotaService = (*AuthorizedService)(nil)
+
+// AuthorizedService restricts access to quotas. Members of an organization may
+// read its quota, only authorizers that can write every organization may change
+// quotas so that organizations cannot raise their own.
+type AuthorizedService struct {
+	s influxdb.OrgQuotaService
+}
+
+// NewAuthorizedService wraps a quota service with authorization checks.
+func NewAuthorizedService(s influxdb.OrgQuotaService) *AuthorizedService {
+	return &AuthorizedService{s: s}
+}
+
+// FindOrgQuota checks that the authorizer may read the organization.
+func (s *AuthorizedService) FindOrgQuota(ctx context.Context, orgID influxdb.ID) (*influxdb.OrgQuota, error) {
+	if _, _, err := authorizer.AuthorizeReadOrg(ctx, orgID); err != nil {
+		return nil, err
+	}
+	return s.s.FindOrgQuota(ctx, orgID)
+}
+
+// FindOrgQuotas checks that the authorizer may read every organization.
+func (s *AuthorizedService) FindOrgQuotas(ctx context.Context) ([]*influxdb.OrgQuota, error) {
+	if _, _, err := authorizer.AuthorizeReadGlobal(ctx, influxdb.OrgsResourceType); err != nil {
+		return nil, err
+	}
+	return s.s.FindOrgQuotas(ctx)
+}
+
+// PutOrgQuota checks that the authorizer may write every organization.
+func (s *AuthorizedService) PutOrgQuota(ctx context.Context, q *influxdb.OrgQuota) error {
+	if _, _, err := authorizer.AuthorizeWriteGlobal(ctx, influxdb.OrgsResourceType); err != nil {
+		return err
+	}
+	return s.s.PutOrgQuota(ctx, q)
+}
+
+// DeleteOrgQuota checks that the authorizer may write every organization.
+func (s *AuthorizedService) DeleteOrgQuota(ctx context.Context, orgID influxdb.ID) error {
+	if _, _, err := authorizer.AuthorizeWriteGlobal(ctx, influxdb.OrgsResourceType); err != nil {
+		return err
+	}
+	return s.s.DeleteOrgQuota(ctx, orgID)
+}
@@ -0,0 +1,98 @@
+package quota
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage"
+)
+
+// DefaultCardinalityTTL is how long the series cardinality of an organization is cached.
+const DefaultCardinalityTTL = 10 * time.Second
+
+// SeriesCardinality returns the number of series stored in a bucket.
+type SeriesCardinality interface {
+	SeriesCardinality(orgID, bucketID influxdb.ID) int64
+}
+
+var _ storage.PointsWriter = (*PointsWriter)(nil)
+
+// PointsWriter enforces the series cardinality quota of organizations. The
+// cardinality is summed over the buckets of the organization and cached, so
+// an organization may briefly exceed its quota. Once the quota is reached every
+// write is rejected until series are deleted or expire.
+type PointsWriter struct {
+	storage.PointsWriter
+	quotas      influxdb.OrgQuotaService
+	buckets     influxdb.BucketService
+	cardinality SeriesCardinality
+
+	TTL time.Duration
+	now func() time.Time
+
+	mu    sync.Mutex
+	cache map[influxdb.ID]cachedCardinality
+}
+
+type cachedCardinality struct {
+	n       int64
+	expires time.Time
+}
+
+// NewPointsWriter wraps a points writer with cardinality quota enforcement.
+func NewPointsWriter(w storage.PointsWriter, quotas influxdb.OrgQuotaService, buckets influxdb.BucketService, c SeriesCardinality) *PointsWriter {
+	return &PointsWriter{
+		PointsWriter: w,
+		quotas:       quotas,
+		buckets:      buckets,
+		cardinality:  c,
+		TTL:          DefaultCardinalityTTL,
+		now:          time.Now,
+		cache:        make(map[influxdb.ID]cachedCardinality),
+	}
+}
+
+// WritePoints rejects the points when the organization has reached its cardinality quota.
+func (w *PointsWriter) WritePoints(ctx context.Context, orgID influxdb.ID, bucketID influxdb.ID, points []models.Point) error {
+	q, err := findQuota(ctx, w.quotas, orgID)
+	if err != nil {
+		return err
+	}
+	if q != nil && q.MaxSeriesCardinality > 0 {
+		n, err := w.orgCardinality(ctx, orgID)
+		if err != nil {
+			return err
+		}
+		if n >= q.MaxSeriesCardinality {
+			return ErrCardinalityExceeded(q)
+		}
+	}
+	return w.PointsWriter.WritePoints(ctx, orgID, bucketID, points)
+}
+
+func (w *PointsWriter) orgCardinality(ctx context.Context, orgID influxdb.ID) (int64, error) {
+	now := w.now()
+	w.mu.Lock()
+	c, ok := w.cache[orgID]
+	w.mu.Unlock()
+	if ok && now.Before(c.expires) {
+		return c.n, nil
+	}
+
+	bs, err := findOrgBuckets(ctx, w.buckets, orgID)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	for _, b := range bs {
+		n += w.cardinality.SeriesCardinality(orgID, b.ID)
+	}
+
+	w.mu.Lock()
+	w.cache[orgID] = cachedCardinality{n: n, expires: now.Add(w.TTL)}
+	w.mu.Unlock()
+	return n, nil
+}
@@ -0,0 +1,50 @@
+package quota_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/quota"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type seriesCardinality map[influxdb.ID]int64
+
+func (c seriesCardinality) SeriesCardinality(orgID, bucketID influxdb.ID) int64 {
+	return c[bucketID]
+}
+
+func TestPointsWriter(t *testing.T) {
+	ctx := context.Background()
+	quotas := newTestService(t)
+	require.NoError(t, quotas.PutOrgQuota(ctx, &influxdb.OrgQuota{OrgID: 1, MaxSeriesCardinality: 100}))
+
+	buckets := mock.NewBucketService()
+	buckets.FindBucketsFn = func(ctx context.Context, filter influxdb.BucketFilter, opts ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+		return []*influxdb.Bucket{{ID: 10}, {ID: 11}}, 2, nil
+	}
+	cardinality := seriesCardinality{10: 40, 11: 50}
+
+	var written int
+	underlying := &mock.PointsWriter{}
+	underlying.WritePointsFn = func(ctx context.Context, orgID influxdb.ID, bucketID influxdb.ID, points []models.Point) error {
+		written++
+		return nil
+	}
+	w := quota.NewPointsWriter(underlying, quotas, buckets, cardinality)
+	w.TTL = 0
+
+	require.NoError(t, w.WritePoints(ctx, 1, 10, nil))
+
+	cardinality[11] = 60
+	err := w.WritePoints(ctx, 1, 10, nil)
+	assert.Equal(t, influxdb.EForbidden, influxdb.ErrorCode(err))
+
+	// organizations without a quota are not limited.
+	require.NoError(t, w.WritePoints(ctx, 2, 10, nil))
+	assert.Equal(t, 2, written)
+}
@@ -0,0 +1,229 @@
+package quota
+
+// The quota `Service` stores the quota of every organization in a single kv
+// bucket keyed by the encoded organization ID. The resource quotas are
+// enforced by the bucket, task, check, notification rule and dashboard stores
+// in the transaction that creates a resource, through the kv.CreateLimit of
+// each resource. The stores must share the kv store of the quota service.
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+var quotaBucket = []byte("orgquotasv1")
+
+var _ influxdb.OrgQuotaService = (*Service)(nil)
+
+// Service is the kv backed implementation of influxdb.OrgQuotaService.
+type Service struct {
+	store kv.Store
+	now   func() time.Time
+}
+
+// NewService returns a quota service backed by the provided kv store.
+func NewService(st kv.Store) *Service {
+	return &Service{
+		store: st,
+		now:   time.Now,
+	}
+}
+
+// FindOrgQuota returns the quota of the organization.
+func (s *Service) FindOrgQuota(ctx context.Context, orgID influxdb.ID) (*influxdb.OrgQuota, error) {
+	key, err := orgID.Encode()
+	if err != nil {
+		return nil, ErrInvalidOrgID(err)
+	}
+
+	var q *influxdb.OrgQuota
+	err = s.store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(quotaBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		q, err = getQuota(b, key)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// FindOrgQuotas returns the quotas of every organization that has one.
+func (s *Service) FindOrgQuotas(ctx context.Context) ([]*influxdb.OrgQuota, error) {
+	qs := []*influxdb.OrgQuota{}
+	err := s.store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(quotaBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		cur, err := b.ForwardCursor(nil)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		defer cur.Close()
+
+		for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+			q := &influxdb.OrgQuota{}
+			if err := json.Unmarshal(v, q); err != nil {
+				return ErrInternalService(err)
+			}
+			qs = append(qs, q)
+		}
+		return cur.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+// PutOrgQuota creates or replaces the quota of an organization.
+func (s *Service) PutOrgQuota(ctx context.Context, q *influxdb.OrgQuota) error {
+	if err := q.Valid(); err != nil {
+		return err
+	}
+	key, err := q.OrgID.Encode()
+	if err != nil {
+		return ErrInvalidOrgID(err)
+	}
+
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(quotaBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+
+		now := s.now().UTC()
+		q.CreatedAt = now
+		existing, err := getQuota(b, key)
+		if err == nil {
+			q.CreatedAt = existing.CreatedAt
+		} else if influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return err
+		}
+		q.UpdatedAt = now
+
+		v, err := json.Marshal(q)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		if err := b.Put(key, v); err != nil {
+			return ErrInternalService(err)
+		}
+		return nil
+	})
+}
+
+// DeleteOrgQuota removes the quota of an organization.
+func (s *Service) DeleteOrgQuota(ctx context.Context, orgID influxdb.ID) error {
+	key, err := orgID.Encode()
+	if err != nil {
+		return ErrInvalidOrgID(err)
+	}
+
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(quotaBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		if _, err := getQuota(b, key); err != nil {
+			return err
+		}
+		if err := b.Delete(key); err != nil {
+			return ErrInternalService(err)
+		}
+		return nil
+	})
+}
+
+// BucketLimit enforces the bucket quota of organizations.
+func (s *Service) BucketLimit() kv.CreateLimit {
+	return limit("buckets", func(q *influxdb.OrgQuota) int { return q.MaxBuckets })
+}
+
+// TaskLimit enforces the task quota of organizations.
+func (s *Service) TaskLimit() kv.CreateLimit {
+	return limit("tasks", func(q *influxdb.OrgQuota) int { return q.MaxTasks })
+}
+
+// CheckLimit enforces the check quota of organizations.
+func (s *Service) CheckLimit() kv.CreateLimit {
+	return limit("checks", func(q *influxdb.OrgQuota) int { return q.MaxChecks })
+}
+
+// NotificationRuleLimit enforces the notification rule quota of organizations.
+func (s *Service) NotificationRuleLimit() kv.CreateLimit {
+	return limit("notification rules", func(q *influxdb.OrgQuota) int { return q.MaxNotificationRules })
+}
+
+// DashboardLimit enforces the dashboard quota of organizations.
+func (s *Service) DashboardLimit() kv.CreateLimit {
+	return limit("dashboards", func(q *influxdb.OrgQuota) int { return q.MaxDashboards })
+}
+
+// limit reads the quota of the organization in the transaction creating a
+// resource, max returns the maximum number of the resource in a quota.
+func limit(resource string, max func(*influxdb.OrgQuota) int) kv.CreateLimit {
+	return func(ctx context.Context, tx kv.Tx, orgID influxdb.ID, count func() (int, error)) error {
+		key, err := orgID.Encode()
+		if err != nil {
+			return ErrInvalidOrgID(err)
+		}
+		b, err := tx.Bucket(quotaBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		q, err := getQuota(b, key)
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		m := max(q)
+		if m <= 0 {
+			return nil
+		}
+		n, err := count()
+		if err != nil {
+			return err
+		}
+		if n >= m {
+			return ErrQuotaExceeded(resource, m)
+		}
+		return nil
+	}
+}
+
+func getQuota(b kv.Bucket, key []byte) (*influxdb.OrgQuota, error) {
+	v, err := b.Get(key)
+	if kv.IsNotFound(err) {
+		return nil, ErrQuotaNotFound
+	}
+	if err != nil {
+		return nil, ErrInternalService(err)
+	}
+
+	q := &influxdb.OrgQuota{}
+	if err := json.Unmarshal(v, q); err != nil {
+		return nil, ErrInternalService(err)
+	}
+	return q, nil
+}
+
+// findQuota returns the quota of the organization, or nil when it has none.
+func findQuota(ctx context.Context, s influxdb.OrgQuotaService, orgID influxdb.ID) (*influxdb.OrgQuota, error) {
+	q, err := s.FindOrgQuota(ctx, orgID)
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return nil, nil
+	}
+	return q, err
+}
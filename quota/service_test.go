@@ -0,0 +1,119 @@
+package quota_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kv/migration/all"
+	"github.com/influxdata/influxdb/v2/quota"
+	"github.com/influxdata/influxdb/v2/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestService(t *testing.T) *quota.Service {
+	t.Helper()
+
+	svc, _ := newTestServiceWithStore(t)
+	return svc
+}
+
+func newTestServiceWithStore(t *testing.T) (*quota.Service, *inmem.KVStore) {
+	t.Helper()
+
+	store := inmem.NewKVStore()
+	if err := all.Up(context.Background(), zaptest.NewLogger(t), store); err != nil {
+		t.Fatal(err)
+	}
+	return quota.NewService(store), store
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+
+	_, err := svc.FindOrgQuota(ctx, 1)
+	assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+
+	err = svc.PutOrgQuota(ctx, &influxdb.OrgQuota{OrgID: 1, MaxBuckets: -1})
+	assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+
+	require.NoError(t, svc.PutOrgQuota(ctx, &influxdb.OrgQuota{OrgID: 1, MaxBuckets: 10}))
+	require.NoError(t, svc.PutOrgQuota(ctx, &influxdb.OrgQuota{OrgID: 2, MaxTasks: 5}))
+
+	first, err := svc.FindOrgQuota(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 10, first.MaxBuckets)
+
+	// replacing a quota keeps its creation time.
+	time.Sleep(time.Millisecond)
+	require.NoError(t, svc.PutOrgQuota(ctx, &influxdb.OrgQuota{OrgID: 1, MaxBuckets: 20, MaxRetentionSeconds: 3600}))
+	q, err := svc.FindOrgQuota(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 20, q.MaxBuckets)
+	assert.Equal(t, time.Hour, q.MaxRetention())
+	assert.Equal(t, first.CreatedAt, q.CreatedAt)
+	assert.True(t, q.UpdatedAt.After(first.UpdatedAt))
+
+	qs, err := svc.FindOrgQuotas(ctx)
+	require.NoError(t, err)
+	require.Len(t, qs, 2)
+	assert.Equal(t, influxdb.ID(1), qs[0].OrgID)
+	assert.Equal(t, influxdb.ID(2), qs[1].OrgID)
+
+	require.NoError(t, svc.DeleteOrgQuota(ctx, 1))
+	_, err = svc.FindOrgQuota(ctx, 1)
+	assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+	err = svc.DeleteOrgQuota(ctx, 1)
+	assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+}
+
+func TestService_BucketLimit(t *testing.T) {
+	ctx := context.Background()
+	quotas, store := newTestServiceWithStore(t)
+	ts := tenant.NewService(tenant.NewStore(store, tenant.WithBucketLimit(quotas.BucketLimit())))
+
+	org := &influxdb.Organization{Name: "org"}
+	require.NoError(t, ts.CreateOrganization(ctx, org))
+	require.NoError(t, quotas.PutOrgQuota(ctx, &influxdb.OrgQuota{OrgID: org.ID, MaxBuckets: 3}))
+
+	// concurrent creates cannot exceed the quota, the count and the create
+	// share the transaction.
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		created int
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := ts.CreateBucket(ctx, &influxdb.Bucket{OrgID: org.ID, Name: fmt.Sprintf("b%d", i)})
+			if err != nil {
+				assert.Equal(t, influxdb.EForbidden, influxdb.ErrorCode(err))
+				return
+			}
+			mu.Lock()
+			created++
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 3, created)
+
+	// system buckets are not limited.
+	require.NoError(t, ts.CreateBucket(ctx, &influxdb.Bucket{OrgID: org.ID, Name: "_system", Type: influxdb.BucketTypeSystem}))
+
+	// organizations without a quota are not limited.
+	other := &influxdb.Organization{Name: "other"}
+	require.NoError(t, ts.CreateOrganization(ctx, other))
+	for i := 0; i < 5; i++ {
+		require.NoError(t, ts.CreateBucket(ctx, &influxdb.Bucket{OrgID: other.ID, Name: fmt.Sprintf("b%d", i)}))
+	}
+}
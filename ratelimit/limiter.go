@@ -0,0 +1,325 @@
+// Package ratelimit throttles the write and query APIs per token, user and
+// organization.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+)
+
+// Scope identifies what the keys of a limit are.
+type Scope string
+
+// Scopes limits are applied to.
+const (
+	ScopeToken Scope = "token"
+	ScopeUser  Scope = "user"
+	ScopeOrg   Scope = "org"
+)
+
+// Kinds of limits, used to label metrics.
+const (
+	limitWriteRequests = "write_requests"
+	limitWriteBytes    = "write_bytes"
+	limitQueries       = "queries"
+)
+
+// Limits are applied to every key of a scope independently.
+// A zero value disables the limit.
+type Limits struct {
+	// WriteRequestsPerSecond is the sustained rate of write requests.
+	WriteRequestsPerSecond int
+	// WriteBytesPerSecond is the sustained rate of request body bytes written.
+	WriteBytesPerSecond int
+	// ConcurrentQueries is the number of queries that can run at the same time.
+	ConcurrentQueries int
+}
+
+func (l Limits) enabled() bool {
+	return l.WriteRequestsPerSecond > 0 || l.WriteBytesPerSecond > 0 || l.ConcurrentQueries > 0
+}
+
+// Config holds the limits of every scope.
+type Config struct {
+	Token Limits
+	User  Limits
+	Org   Limits
+}
+
+// Enabled reports whether any limit is configured.
+func (c Config) Enabled() bool {
+	return c.Token.enabled() || c.User.enabled() || c.Org.enabled()
+}
+
+// stricter combines two limits, keeping the lowest enabled value of each.
+func (l Limits) stricter(o Limits) Limits {
+	min := func(a, b int) int {
+		if a <= 0 || (b > 0 && b < a) {
+			return b
+		}
+		return a
+	}
+	return Limits{
+		WriteRequestsPerSecond: min(l.WriteRequestsPerSecond, o.WriteRequestsPerSecond),
+		WriteBytesPerSecond:    min(l.WriteBytesPerSecond, o.WriteBytesPerSecond),
+		ConcurrentQueries:      min(l.ConcurrentQueries, o.ConcurrentQueries),
+	}
+}
+
+func (c Config) limits(s Scope) Limits {
+	switch s {
+	case ScopeToken:
+		return c.Token
+	case ScopeUser:
+		return c.User
+	case ScopeOrg:
+		return c.Org
+	}
+	return Limits{}
+}
+
+// Key identifies the token, user or organization a request is accounted to.
+type Key struct {
+	Scope Scope
+	ID    influxdb.ID
+}
+
+// Limiter keeps track of the usage of every key.
+type Limiter struct {
+	config Config
+
+	mu            sync.Mutex
+	orgLimits     map[influxdb.ID]Limits
+	writeRequests map[Key]*rateLimiter
+	writeBytes    map[Key]*rateLimiter
+	queries       map[Key]int
+	lastSweep     time.Time
+
+	now     func() time.Time
+	metrics *metrics
+}
+
+// sweepInterval is how often rate limiters that are back to their full
+// burst are removed, so that keys that stopped writing use no memory.
+const sweepInterval = time.Minute
+
+// rateLimiter is a rate limiter with the time its bucket is full again.
+// A full limiter behaves as a new one and can be removed without effect.
+type rateLimiter struct {
+	*rate.Limiter
+	fullAt time.Time
+}
+
+// reserve accounts n tokens reserved at now.
+func (r *rateLimiter) reserve(now time.Time, n int) {
+	if r.fullAt.Before(now) {
+		r.fullAt = now
+	}
+	r.fullAt = r.fullAt.Add(time.Duration(float64(n) / float64(r.Limit()) * float64(time.Second)))
+}
+
+// NewLimiter constructs a limiter enforcing the configured limits.
+func NewLimiter(c Config) *Limiter {
+	return &Limiter{
+		config:        c,
+		orgLimits:     make(map[influxdb.ID]Limits),
+		writeRequests: make(map[Key]*rateLimiter),
+		writeBytes:    make(map[Key]*rateLimiter),
+		queries:       make(map[Key]int),
+		now:           time.Now,
+		metrics:       newMetrics(),
+	}
+}
+
+// PrometheusCollectors returns the metrics of the limiter.
+func (l *Limiter) PrometheusCollectors() []prometheus.Collector {
+	return l.metrics.PrometheusCollectors()
+}
+
+// SetOrgLimits applies limits to a single organization on top of the
+// configured organization limits, the stricter of the two is enforced.
+// Setting zero limits removes the organization specific limits.
+func (l *Limiter) SetOrgLimits(orgID influxdb.ID, limits Limits) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limits.enabled() {
+		l.orgLimits[orgID] = limits
+	} else {
+		delete(l.orgLimits, orgID)
+	}
+	// the rate limiters are recreated with the new rates on the next request.
+	k := Key{Scope: ScopeOrg, ID: orgID}
+	delete(l.writeRequests, k)
+	delete(l.writeBytes, k)
+}
+
+// limits returns the limits of the key, it must be called with the lock held.
+func (l *Limiter) limits(k Key) Limits {
+	limits := l.config.limits(k.Scope)
+	if k.Scope == ScopeOrg {
+		if o, ok := l.orgLimits[k.ID]; ok {
+			limits = limits.stricter(o)
+		}
+	}
+	return limits
+}
+
+// AllowWrite accounts a write request to the keys. When a limit is exceeded
+// nothing is accounted and the time after which the request may be retried
+// is returned.
+func (l *Limiter) AllowWrite(keys []Key) (retryAfter time.Duration, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	var reserved []*rate.Reservation
+	reserve := func(k Key, limit string, lim *rateLimiter) time.Duration {
+		r := lim.ReserveN(now, 1)
+		if d := r.DelayFrom(now); d > 0 {
+			r.CancelAt(now)
+			l.metrics.rejected.WithLabelValues(string(k.Scope), limit).Inc()
+			return d
+		}
+		// a reservation cancelled below only makes the limiter stay longer.
+		lim.reserve(now, 1)
+		reserved = append(reserved, r)
+		return 0
+	}
+
+	for _, k := range keys {
+		limits := l.limits(k)
+		if limits.WriteRequestsPerSecond > 0 {
+			retryAfter = reserve(k, limitWriteRequests, l.writeRequestLimiter(k, limits))
+		}
+		// byte usage is charged once the body has been read, a key that is
+		// already in debt is rejected until enough time has passed.
+		if retryAfter == 0 && limits.WriteBytesPerSecond > 0 {
+			retryAfter = reserve(k, limitWriteBytes, l.writeByteLimiter(k, limits))
+		}
+		if retryAfter > 0 {
+			for _, r := range reserved {
+				r.CancelAt(now)
+			}
+			return retryAfter, false
+		}
+	}
+	return 0, true
+}
+
+// ChargeWriteBytes accounts n bytes written to the keys.
+func (l *Limiter) ChargeWriteBytes(keys []Key, n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// AllowWrite already charged one byte.
+	n--
+	if n <= 0 {
+		return
+	}
+
+	now := l.now()
+	for _, k := range keys {
+		limits := l.limits(k)
+		if limits.WriteBytesPerSecond <= 0 {
+			continue
+		}
+		lim := l.writeByteLimiter(k, limits)
+		// reservations cannot exceed the burst, charge in chunks.
+		for rem := n; rem > 0; rem -= lim.Burst() {
+			chunk := rem
+			if chunk > lim.Burst() {
+				chunk = lim.Burst()
+			}
+			lim.ReserveN(now, chunk)
+		}
+		lim.reserve(now, n)
+	}
+}
+
+// AcquireQuery accounts a running query to the keys. When the number of
+// concurrent queries of a key is exceeded false is returned. Otherwise the
+// returned function must be called once the query completes.
+func (l *Limiter) AcquireQuery(keys []Key) (release func(), ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var acquired []Key
+	for _, k := range keys {
+		max := l.limits(k).ConcurrentQueries
+		if max <= 0 {
+			continue
+		}
+		if l.queries[k] >= max {
+			l.releaseQueries(acquired)
+			l.metrics.rejected.WithLabelValues(string(k.Scope), limitQueries).Inc()
+			return nil, false
+		}
+		l.queries[k]++
+		acquired = append(acquired, k)
+	}
+	l.metrics.activeQueries.Inc()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.releaseQueries(acquired)
+			l.metrics.activeQueries.Dec()
+		})
+	}, true
+}
+
+func (l *Limiter) releaseQueries(keys []Key) {
+	for _, k := range keys {
+		if l.queries[k]--; l.queries[k] <= 0 {
+			delete(l.queries, k)
+		}
+	}
+}
+
+// sweep removes the rate limiters that are full again, at most once per
+// sweep interval. It must be called with the lock held.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for _, m := range []map[Key]*rateLimiter{l.writeRequests, l.writeBytes} {
+		for k, lim := range m {
+			if !lim.fullAt.After(now) {
+				delete(m, k)
+			}
+		}
+	}
+}
+
+func (l *Limiter) writeRequestLimiter(k Key, limits Limits) *rateLimiter {
+	lim, ok := l.writeRequests[k]
+	if !ok {
+		lim = newRateLimiter(limits.WriteRequestsPerSecond)
+		l.writeRequests[k] = lim
+	}
+	return lim
+}
+
+func (l *Limiter) writeByteLimiter(k Key, limits Limits) *rateLimiter {
+	lim, ok := l.writeBytes[k]
+	if !ok {
+		lim = newRateLimiter(limits.WriteBytesPerSecond)
+		l.writeBytes[k] = lim
+	}
+	return lim
+}
+
+// newRateLimiter allows bursts of up to one second worth of the rate.
+func newRateLimiter(perSecond int) *rateLimiter {
+	return &rateLimiter{Limiter: rate.NewLimiter(rate.Limit(perSecond), perSecond)}
+}
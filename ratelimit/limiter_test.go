@@ -0,0 +1,190 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+func TestLimiter_AllowWrite(t *testing.T) {
+	l := NewLimiter(Config{
+		Token: Limits{WriteRequestsPerSecond: 2},
+	})
+	now := time.Unix(0, 0)
+	l.now = func() time.Time { return now }
+
+	token := []Key{{Scope: ScopeToken, ID: 1}, {Scope: ScopeOrg, ID: 10}}
+	other := []Key{{Scope: ScopeToken, ID: 2}, {Scope: ScopeOrg, ID: 10}}
+
+	for i := 0; i < 2; i++ {
+		if _, ok := l.AllowWrite(token); !ok {
+			t.Fatalf("write %d should be allowed", i)
+		}
+	}
+	retryAfter, ok := l.AllowWrite(token)
+	if ok {
+		t.Fatal("third write should be rejected")
+	}
+	if retryAfter != 500*time.Millisecond {
+		t.Errorf("unexpected retry after: %s", retryAfter)
+	}
+
+	// the limit is per token, other tokens of the organization are not affected.
+	if _, ok := l.AllowWrite(other); !ok {
+		t.Error("write with another token should be allowed")
+	}
+
+	now = now.Add(retryAfter)
+	if _, ok := l.AllowWrite(token); !ok {
+		t.Error("write should be allowed after waiting")
+	}
+}
+
+func TestLimiter_WriteBytes(t *testing.T) {
+	l := NewLimiter(Config{
+		Org: Limits{WriteBytesPerSecond: 100},
+	})
+	now := time.Unix(0, 0)
+	l.now = func() time.Time { return now }
+
+	keys := []Key{{Scope: ScopeUser, ID: 1}, {Scope: ScopeOrg, ID: 10}}
+	if _, ok := l.AllowWrite(keys); !ok {
+		t.Fatal("first write should be allowed")
+	}
+	l.ChargeWriteBytes(keys, 300)
+
+	retryAfter, ok := l.AllowWrite(keys)
+	if ok {
+		t.Fatal("write should be rejected while the org is in debt")
+	}
+	// 200 bytes of debt plus the byte reserved by the request.
+	if retryAfter < 2*time.Second || retryAfter > 2*time.Second+10*time.Millisecond {
+		t.Errorf("unexpected retry after: %s", retryAfter)
+	}
+
+	now = now.Add(retryAfter)
+	if _, ok := l.AllowWrite(keys); !ok {
+		t.Error("write should be allowed once the debt is paid")
+	}
+}
+
+func TestLimiter_AcquireQuery(t *testing.T) {
+	l := NewLimiter(Config{
+		User: Limits{ConcurrentQueries: 1},
+		Org:  Limits{ConcurrentQueries: 2},
+	})
+
+	user := func(id influxdb.ID) []Key {
+		return []Key{{Scope: ScopeUser, ID: id}, {Scope: ScopeOrg, ID: 10}}
+	}
+
+	release1, ok := l.AcquireQuery(user(1))
+	if !ok {
+		t.Fatal("first query should be allowed")
+	}
+	if _, ok := l.AcquireQuery(user(1)); ok {
+		t.Fatal("second query of the user should be rejected")
+	}
+	release2, ok := l.AcquireQuery(user(2))
+	if !ok {
+		t.Fatal("query of another user should be allowed")
+	}
+	if _, ok := l.AcquireQuery(user(3)); ok {
+		t.Fatal("third query of the org should be rejected")
+	}
+
+	release1()
+	release1()
+	if _, ok := l.AcquireQuery(user(3)); !ok {
+		t.Error("query should be allowed after a release")
+	}
+	release2()
+
+	if n := l.queries[Key{Scope: ScopeUser, ID: 3}]; n != 1 {
+		t.Errorf("unexpected number of queries for the user: %d", n)
+	}
+	if n := l.queries[Key{Scope: ScopeOrg, ID: 10}]; n != 1 {
+		t.Errorf("unexpected number of queries for the org: %d", n)
+	}
+}
+
+func TestLimiter_SetOrgLimits(t *testing.T) {
+	l := NewLimiter(Config{
+		Org: Limits{WriteRequestsPerSecond: 10},
+	})
+	now := time.Unix(0, 0)
+	l.now = func() time.Time { return now }
+
+	org := []Key{{Scope: ScopeOrg, ID: 10}}
+	other := []Key{{Scope: ScopeOrg, ID: 11}}
+
+	l.SetOrgLimits(10, Limits{WriteRequestsPerSecond: 1})
+	if _, ok := l.AllowWrite(org); !ok {
+		t.Fatal("first write should be allowed")
+	}
+	if _, ok := l.AllowWrite(org); ok {
+		t.Fatal("second write should be rejected by the org limit")
+	}
+	for i := 0; i < 2; i++ {
+		if _, ok := l.AllowWrite(other); !ok {
+			t.Fatalf("write %d of another org should be allowed", i)
+		}
+	}
+
+	// a looser org limit does not override the configured one.
+	l.SetOrgLimits(10, Limits{WriteRequestsPerSecond: 100})
+	if got := l.limits(org[0]).WriteRequestsPerSecond; got != 10 {
+		t.Errorf("unexpected write request limit: %d", got)
+	}
+
+	l.SetOrgLimits(10, Limits{})
+	if _, ok := l.orgLimits[10]; ok {
+		t.Error("org limits should be removed")
+	}
+	if _, ok := l.AllowWrite(org); !ok {
+		t.Error("write should be allowed once the org limits are removed")
+	}
+}
+
+func TestLimiter_Sweep(t *testing.T) {
+	l := NewLimiter(Config{
+		Token: Limits{WriteRequestsPerSecond: 1},
+		Org:   Limits{WriteBytesPerSecond: 100},
+	})
+	now := time.Unix(0, 0)
+	l.now = func() time.Time { return now }
+
+	idle := []Key{{Scope: ScopeToken, ID: 1}, {Scope: ScopeOrg, ID: 10}}
+	indebted := []Key{{Scope: ScopeToken, ID: 2}, {Scope: ScopeOrg, ID: 11}}
+	if _, ok := l.AllowWrite(idle); !ok {
+		t.Fatal("write should be allowed")
+	}
+	if _, ok := l.AllowWrite(indebted); !ok {
+		t.Fatal("write should be allowed")
+	}
+	// two minutes worth of bytes.
+	l.ChargeWriteBytes(indebted, 12000)
+
+	now = now.Add(sweepInterval)
+	l.AllowWrite([]Key{{Scope: ScopeToken, ID: 3}})
+
+	if _, ok := l.writeRequests[idle[0]]; ok {
+		t.Error("idle token limiter should be removed")
+	}
+	if _, ok := l.writeBytes[idle[1]]; ok {
+		t.Error("idle org limiter should be removed")
+	}
+	if _, ok := l.writeBytes[indebted[1]]; !ok {
+		t.Fatal("org limiter in debt should be kept")
+	}
+	if _, ok := l.AllowWrite(indebted); ok {
+		t.Error("org in debt should still be rejected")
+	}
+
+	now = now.Add(2 * sweepInterval)
+	l.AllowWrite([]Key{{Scope: ScopeToken, ID: 3}})
+	if _, ok := l.writeBytes[indebted[1]]; ok {
+		t.Error("org limiter should be removed once the debt is paid")
+	}
+}
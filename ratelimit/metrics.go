@@ -0,0 +1,38 @@
+package ratelimit
+
+import "github.com/prometheus/client_golang/prometheus"
+
+type metrics struct {
+	rejected      *prometheus.CounterVec
+	activeQueries prometheus.Gauge
+}
+
+func newMetrics() *metrics {
+	const (
+		namespace = "http"
+		subsystem = "ratelimit"
+	)
+
+	return &metrics{
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "rejected_requests_total",
+			Help:      "Number of requests rejected because a rate limit was exceeded",
+		}, []string{"scope", "limit"}),
+		activeQueries: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "active_queries",
+			Help:      "Number of queries currently accounted by the rate limiter",
+		}),
+	}
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *metrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.rejected,
+		m.activeQueries,
+	}
+}
@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"go.uber.org/zap"
+)
+
+// writePaths and queryPaths are the v2 and the 1.x compatibility endpoints.
+var (
+	writePaths = map[string]bool{"/api/v2/write": true, "/write": true}
+	queryPaths = map[string]bool{"/api/v2/query": true, "/query": true}
+)
+
+// NewHTTPMiddleware returns a middleware that rejects write and query
+// requests exceeding the limits with 429 Too Many Requests. It must be
+// installed behind the authentication handler so that the authorizer of
+// the request is available on its context. The organizations that requests
+// name with the org parameter are found with the organization service.
+func NewHTTPMiddleware(log *zap.Logger, l *Limiter, orgs influxdb.OrganizationService) kithttp.Middleware {
+	errHandler := kithttp.ErrorHandler(0)
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			isWrite := writePaths[r.URL.Path] && r.Method == http.MethodPost
+			isQuery := queryPaths[r.URL.Path]
+			if !isWrite && !isQuery {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			auth, err := icontext.GetAuthorizer(r.Context())
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			keys := requestKeys(r, auth, orgs)
+
+			reject := func(retryAfter time.Duration, msg string) {
+				secs := int(math.Ceil(retryAfter.Seconds()))
+				if secs < 1 {
+					secs = 1
+				}
+				log.Debug("Request rate limited",
+					zap.String("path", r.URL.Path),
+					zap.Int("retry_after", secs),
+				)
+				w.Header().Set("Retry-After", strconv.Itoa(secs))
+				errHandler.HandleHTTPError(r.Context(), &influxdb.Error{
+					Code: influxdb.ETooManyRequests,
+					Msg:  fmt.Sprintf("%s, retry in %d seconds", msg, secs),
+				}, w)
+			}
+
+			if isQuery {
+				release, ok := l.AcquireQuery(keys)
+				if !ok {
+					reject(time.Second, "too many concurrent queries")
+					return
+				}
+				defer release()
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if retryAfter, ok := l.AllowWrite(keys); !ok {
+				reject(retryAfter, "write rate limit exceeded")
+				return
+			}
+			body := &countingReadCloser{ReadCloser: r.Body}
+			if r.Body != nil {
+				r.Body = body
+			}
+			next.ServeHTTP(w, r)
+			l.ChargeWriteBytes(keys, body.n)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// requestKeys returns the token, user and organization the request is accounted to.
+func requestKeys(r *http.Request, auth influxdb.Authorizer, orgs influxdb.OrganizationService) []Key {
+	var keys []Key
+	var orgID influxdb.ID
+	if a, ok := auth.(*influxdb.Authorization); ok {
+		keys = append(keys, Key{Scope: ScopeToken, ID: a.ID})
+		orgID = a.OrgID
+	}
+	if id := auth.GetUserID(); id.Valid() {
+		keys = append(keys, Key{Scope: ScopeUser, ID: id})
+	}
+	if !orgID.Valid() {
+		orgID = requestOrgID(r, orgs)
+	}
+	if orgID.Valid() {
+		keys = append(keys, Key{Scope: ScopeOrg, ID: orgID})
+	}
+	return keys
+}
+
+// requestOrgID returns the organization that requests authorized by a
+// session name with the orgID or the org parameter, the latter being the ID
+// or the name of the organization.
+func requestOrgID(r *http.Request, orgs influxdb.OrganizationService) influxdb.ID {
+	q := r.URL.Query()
+	if id, err := influxdb.IDFromString(q.Get("orgID")); err == nil {
+		return *id
+	}
+	if id := kithttp.OrgIDFromContext(r.Context()); id != nil {
+		return *id
+	}
+	name := q.Get("org")
+	if name == "" {
+		return 0
+	}
+	if id, err := influxdb.IDFromString(name); err == nil {
+		return *id
+	}
+	if orgs == nil {
+		return 0
+	}
+	// an unknown organization is rejected by the handler.
+	o, err := orgs.FindOrganization(r.Context(), influxdb.OrganizationFilter{Name: &name})
+	if err != nil {
+		return 0
+	}
+	return o.ID
+}
+
+type countingReadCloser struct {
+	io.ReadCloser
+	n int
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += n
+	return n, err
+}
@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestHTTPMiddleware_Write(t *testing.T) {
+	l := NewLimiter(Config{
+		Token: Limits{WriteRequestsPerSecond: 1},
+	})
+	var served int
+	h := NewHTTPMiddleware(zaptest.NewLogger(t), l, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+		ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	auth := &influxdb.Authorization{ID: 1, OrgID: 10, UserID: 100}
+	do := func(path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader("m f=1"))
+		r = r.WithContext(icontext.SetAuthorizer(r.Context(), auth))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := do("/api/v2/write"); w.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status: %d", w.Code)
+	}
+	w := do("/write")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("unexpected status: %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("unexpected Retry-After: %q", got)
+	}
+
+	// other endpoints are not limited.
+	if w := do("/api/v2/buckets"); w.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status: %d", w.Code)
+	}
+	if served != 2 {
+		t.Errorf("expected 2 requests to be served, got %d", served)
+	}
+}
+
+func TestHTTPMiddleware_Query(t *testing.T) {
+	l := NewLimiter(Config{
+		Org: Limits{ConcurrentQueries: 1},
+	})
+
+	var inner *httptest.ResponseRecorder
+	var h http.Handler
+	do := func(auth influxdb.Authorizer) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/api/v2/query", nil)
+		r = r.WithContext(icontext.SetAuthorizer(r.Context(), auth))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+	h = NewHTTPMiddleware(zaptest.NewLogger(t), l, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a query issued while another query of the org is running.
+		if inner == nil {
+			inner = do(&influxdb.Authorization{ID: 2, OrgID: 10})
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	if w := do(&influxdb.Authorization{ID: 1, OrgID: 10}); w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", w.Code)
+	}
+	if inner.Code != http.StatusTooManyRequests {
+		t.Fatalf("unexpected status of concurrent query: %d", inner.Code)
+	}
+	if w := do(&influxdb.Authorization{ID: 2, OrgID: 10}); w.Code != http.StatusOK {
+		t.Fatalf("unexpected status after the first query completed: %d", w.Code)
+	}
+}
+
+func TestRequestKeys(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/api/v2/write?orgID=000000000000000a", nil)
+	keys := requestKeys(r, &influxdb.Session{UserID: 100}, nil)
+	exp := []Key{{Scope: ScopeUser, ID: 100}, {Scope: ScopeOrg, ID: 10}}
+	if len(keys) != len(exp) {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+	for i := range exp {
+		if keys[i] != exp[i] {
+			t.Errorf("unexpected key %d: %v, exp %v", i, keys[i], exp[i])
+		}
+	}
+}
+
+type orgService struct {
+	influxdb.OrganizationService
+	orgs map[string]influxdb.ID
+}
+
+func (s *orgService) FindOrganization(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+	id, ok := s.orgs[*filter.Name]
+	if !ok {
+		return nil, &influxdb.Error{Code: influxdb.ENotFound}
+	}
+	return &influxdb.Organization{ID: id, Name: *filter.Name}, nil
+}
+
+func TestRequestKeys_OrgName(t *testing.T) {
+	orgs := &orgService{orgs: map[string]influxdb.ID{"acme": 10}}
+	for _, tt := range []struct {
+		query string
+		exp   []Key
+	}{
+		{query: "org=acme", exp: []Key{{Scope: ScopeUser, ID: 100}, {Scope: ScopeOrg, ID: 10}}},
+		{query: "org=000000000000000b", exp: []Key{{Scope: ScopeUser, ID: 100}, {Scope: ScopeOrg, ID: 11}}},
+		{query: "org=unknown", exp: []Key{{Scope: ScopeUser, ID: 100}}},
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/api/v2/write?"+tt.query, nil)
+		keys := requestKeys(r, &influxdb.Session{UserID: 100}, orgs)
+		if !reflect.DeepEqual(keys, tt.exp) {
+			t.Errorf("%s: unexpected keys: %v, exp %v", tt.query, keys, tt.exp)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Silence mutes the notifications of an organization whose statuses match all
+// of its matchers while it is active. A silence is either active between
+// StartsAt and EndsAt, or recurringly as described by Recurrence, in which case
+// StartsAt and EndsAt optionally bound the period the recurrence applies to.
+type Silence struct {
+	ID    ID `json:"id,omitempty"`
+	OrgID ID `json:"orgID"`
+
+	// Matchers are compared to the tags of the statuses, the
+	// _check_id, _check_name, _notification_rule_id, _notification_rule_name
+	// and _notification_endpoint_id columns can be matched as well.
+	Matchers []TagRule `json:"matchers"`
+
+	StartsAt   *time.Time         `json:"startsAt,omitempty"`
+	EndsAt     *time.Time         `json:"endsAt,omitempty"`
+	Recurrence *SilenceRecurrence `json:"recurrence,omitempty"`
+
+	Comment   string `json:"comment,omitempty"`
+	CreatedBy ID     `json:"createdBy,omitempty"`
+
+	CRUDLog
+}
+
+// SilenceRecurrence is a daily window during which a silence is active.
+// Start and End are UTC times of day formatted as hh:mm, a window whose end is
+// not after its start ends on the next day.
+type SilenceRecurrence struct {
+	// Days are the lower case names of the week days the window starts on,
+	// every day when empty.
+	Days  []string `json:"days,omitempty"`
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+}
+
+var weekDays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// Weekdays returns the week days the window starts on.
+func (r *SilenceRecurrence) Weekdays() []time.Weekday {
+	if len(r.Days) == 0 {
+		return []time.Weekday{
+			time.Sunday, time.Monday, time.Tuesday, time.Wednesday,
+			time.Thursday, time.Friday, time.Saturday,
+		}
+	}
+	days := make([]time.Weekday, 0, len(r.Days))
+	for _, d := range r.Days {
+		if wd, ok := weekDays[strings.ToLower(d)]; ok {
+			days = append(days, wd)
+		}
+	}
+	return days
+}
+
+// StartMinute returns the minute of the day the window starts at.
+func (r *SilenceRecurrence) StartMinute() int {
+	m, _ := parseMinuteOfDay(r.Start)
+	return m
+}
+
+// EndMinute returns the minute of the day the window ends at.
+func (r *SilenceRecurrence) EndMinute() int {
+	m, _ := parseMinuteOfDay(r.End)
+	return m
+}
+
+// Valid returns an error if the recurrence is invalid.
+func (r *SilenceRecurrence) Valid() error {
+	for _, d := range r.Days {
+		if _, ok := weekDays[strings.ToLower(d)]; !ok {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("silence recurrence day %q is invalid", d),
+			}
+		}
+	}
+	if _, err := parseMinuteOfDay(r.Start); err != nil {
+		return err
+	}
+	if _, err := parseMinuteOfDay(r.End); err != nil {
+		return err
+	}
+	if r.Start == r.End {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence recurrence start and end must differ",
+		}
+	}
+	return nil
+}
+
+// activeAt returns true when t is within the window.
+func (r *SilenceRecurrence) activeAt(t time.Time) bool {
+	t = t.UTC()
+	minute := t.Hour()*60 + t.Minute()
+	start, end := r.StartMinute(), r.EndMinute()
+
+	day := t.Weekday()
+	if end <= start && minute < end {
+		// the window started the day before.
+		day = (day + 6) % 7
+	} else if minute < start || (end > start && minute >= end) {
+		return false
+	}
+	for _, wd := range r.Weekdays() {
+		if wd == day {
+			return true
+		}
+	}
+	return false
+}
+
+func parseMinuteOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("silence recurrence time %q must be formatted as hh:mm", s),
+		}
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Valid returns an error if the silence is invalid.
+func (s *Silence) Valid() error {
+	if !s.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence requires an organization id",
+		}
+	}
+	if len(s.Matchers) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence requires at least one matcher",
+		}
+	}
+	for _, m := range s.Matchers {
+		if m.Key == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "silence matcher key can't be empty",
+			}
+		}
+		if err := m.Operator.Valid(); err != nil {
+			return err
+		}
+		if m.Operator == RegexEqual || m.Operator == NotRegexEqual {
+			if _, err := regexp.Compile(m.Value); err != nil {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("silence matcher %q has an invalid regular expression", m.Key),
+					Err:  err,
+				}
+			}
+		}
+	}
+	if s.Recurrence == nil && (s.StartsAt == nil || s.EndsAt == nil) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence requires startsAt and endsAt or a recurrence",
+		}
+	}
+	if s.StartsAt != nil && s.EndsAt != nil && !s.EndsAt.After(*s.StartsAt) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence endsAt must be after startsAt",
+		}
+	}
+	if s.Recurrence != nil {
+		return s.Recurrence.Valid()
+	}
+	return nil
+}
+
+// ActiveAt returns true when the silence mutes notifications at t.
+func (s *Silence) ActiveAt(t time.Time) bool {
+	if s.StartsAt != nil && t.Before(*s.StartsAt) {
+		return false
+	}
+	if s.EndsAt != nil && !t.Before(*s.EndsAt) {
+		return false
+	}
+	if s.Recurrence != nil {
+		return s.Recurrence.activeAt(t)
+	}
+	return true
+}
+
+// Expired returns true when the silence can no longer become active after t.
+func (s *Silence) Expired(t time.Time) bool {
+	return s.EndsAt != nil && !t.Before(*s.EndsAt)
+}
+
+// SilenceFilter represents a set of filters that restrict the returned silences.
+type SilenceFilter struct {
+	OrgID *ID
+	// Active only returns the silences that are active at the given time.
+	Active *time.Time
+}
+
+// SilenceUpdate is the set of changes of a silence, nil fields are left unchanged.
+type SilenceUpdate struct {
+	Matchers   []TagRule          `json:"matchers,omitempty"`
+	StartsAt   *time.Time         `json:"startsAt,omitempty"`
+	EndsAt     *time.Time         `json:"endsAt,omitempty"`
+	Recurrence *SilenceRecurrence `json:"recurrence,omitempty"`
+	Comment    *string            `json:"comment,omitempty"`
+}
+
+// Apply applies the update to the silence.
+func (u SilenceUpdate) Apply(s *Silence) {
+	if u.Matchers != nil {
+		s.Matchers = u.Matchers
+	}
+	if u.StartsAt != nil {
+		s.StartsAt = u.StartsAt
+	}
+	if u.EndsAt != nil {
+		s.EndsAt = u.EndsAt
+	}
+	if u.Recurrence != nil {
+		s.Recurrence = u.Recurrence
+	}
+	if u.Comment != nil {
+		s.Comment = *u.Comment
+	}
+}
+
+// SilenceService manages the silences of notifications.
+type SilenceService interface {
+	// FindSilenceByID returns a single silence by ID.
+	FindSilenceByID(ctx context.Context, id ID) (*Silence, error)
+
+	// FindSilences returns the silences matching the filter.
+	FindSilences(ctx context.Context, filter SilenceFilter) ([]*Silence, error)
+
+	// CreateSilence creates a new silence and sets s.ID with the new identifier.
+	CreateSilence(ctx context.Context, s *Silence) error
+
+	// UpdateSilence updates a single silence and returns the new silence.
+	UpdateSilence(ctx context.Context, id ID, upd SilenceUpdate) (*Silence, error)
+
+	// DeleteSilence removes a silence by ID.
+	DeleteSilence(ctx context.Context, id ID) error
+}
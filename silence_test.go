@@ -0,0 +1,102 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSilence_Valid(t *testing.T) {
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	matchers := []influxdb.TagRule{{Tag: influxdb.Tag{Key: "host", Value: "a"}, Operator: influxdb.Equal}}
+
+	tests := []struct {
+		name    string
+		silence influxdb.Silence
+		wantErr bool
+	}{
+		{
+			name:    "one off window",
+			silence: influxdb.Silence{OrgID: 1, Matchers: matchers, StartsAt: &start, EndsAt: &end},
+		},
+		{
+			name:    "recurring window",
+			silence: influxdb.Silence{OrgID: 1, Matchers: matchers, Recurrence: &influxdb.SilenceRecurrence{Days: []string{"sunday"}, Start: "02:00", End: "04:00"}},
+		},
+		{
+			name:    "missing org",
+			silence: influxdb.Silence{Matchers: matchers, StartsAt: &start, EndsAt: &end},
+			wantErr: true,
+		},
+		{
+			name:    "missing matchers",
+			silence: influxdb.Silence{OrgID: 1, StartsAt: &start, EndsAt: &end},
+			wantErr: true,
+		},
+		{
+			name:    "missing end",
+			silence: influxdb.Silence{OrgID: 1, Matchers: matchers, StartsAt: &start},
+			wantErr: true,
+		},
+		{
+			name:    "end before start",
+			silence: influxdb.Silence{OrgID: 1, Matchers: matchers, StartsAt: &end, EndsAt: &start},
+			wantErr: true,
+		},
+		{
+			name: "invalid regular expression",
+			silence: influxdb.Silence{OrgID: 1, StartsAt: &start, EndsAt: &end, Matchers: []influxdb.TagRule{
+				{Tag: influxdb.Tag{Key: "host", Value: "("}, Operator: influxdb.RegexEqual},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid day",
+			silence: influxdb.Silence{OrgID: 1, Matchers: matchers, Recurrence: &influxdb.SilenceRecurrence{Days: []string{"someday"}, Start: "02:00", End: "04:00"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid time of day",
+			silence: influxdb.Silence{OrgID: 1, Matchers: matchers, Recurrence: &influxdb.SilenceRecurrence{Start: "2am", End: "04:00"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.silence.Valid()
+			if tt.wantErr {
+				assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSilence_ActiveAt(t *testing.T) {
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	oneOff := influxdb.Silence{StartsAt: &start, EndsAt: &end}
+	assert.False(t, oneOff.ActiveAt(start.Add(-time.Second)))
+	assert.True(t, oneOff.ActiveAt(start))
+	assert.False(t, oneOff.ActiveAt(end))
+
+	// 2020-06-07 is a Sunday.
+	sunday := time.Date(2020, 6, 7, 0, 0, 0, 0, time.UTC)
+	weekly := influxdb.Silence{Recurrence: &influxdb.SilenceRecurrence{Days: []string{"sunday"}, Start: "02:00", End: "04:00"}}
+	assert.False(t, weekly.ActiveAt(sunday.Add(time.Hour)))
+	assert.True(t, weekly.ActiveAt(sunday.Add(2*time.Hour)))
+	assert.True(t, weekly.ActiveAt(sunday.Add(3*time.Hour+59*time.Minute)))
+	assert.False(t, weekly.ActiveAt(sunday.Add(4*time.Hour)))
+	assert.False(t, weekly.ActiveAt(sunday.Add(26*time.Hour)))
+
+	// a window past midnight belongs to the day it starts on.
+	nightly := influxdb.Silence{Recurrence: &influxdb.SilenceRecurrence{Days: []string{"saturday"}, Start: "22:00", End: "02:00"}}
+	assert.True(t, nightly.ActiveAt(sunday.Add(-time.Hour)))
+	assert.True(t, nightly.ActiveAt(sunday.Add(time.Hour)))
+	assert.False(t, nightly.ActiveAt(sunday.Add(22*time.Hour)))
+	assert.False(t, nightly.ActiveAt(sunday.Add(2*time.Hour)))
+}
@@ -67,6 +67,50 @@ func (w *LoggingPointsWriter) WritePoints(ctx context.Context, orgID influxdb.ID
 	return err
 }
 
+// BucketWriteListener is notified of the points written to a bucket.
+type BucketWriteListener interface {
+	// BucketWritten is called after points with timestamps between min and
+	// max, inclusive, were written to the bucket, with the context of the
+	// write. It must not block.
+	BucketWritten(ctx context.Context, orgID, bucketID influxdb.ID, min, max time.Time)
+}
+
+// NotifyingPointsWriter wraps an underlying points writer and notifies a
+// listener of the time range of every successful write.
+type NotifyingPointsWriter struct {
+	Underlying PointsWriter
+	Listener   BucketWriteListener
+}
+
+// NewNotifyingPointsWriter returns a points writer notifying l of the writes to w.
+func NewNotifyingPointsWriter(w PointsWriter, l BucketWriteListener) *NotifyingPointsWriter {
+	return &NotifyingPointsWriter{
+		Underlying: w,
+		Listener:   l,
+	}
+}
+
+// WritePoints writes points to the underlying PointsWriter and notifies the listener on success.
+func (w *NotifyingPointsWriter) WritePoints(ctx context.Context, orgID influxdb.ID, bucketID influxdb.ID, p []models.Point) error {
+	if err := w.Underlying.WritePoints(ctx, orgID, bucketID, p); err != nil {
+		return err
+	}
+	if len(p) == 0 {
+		return nil
+	}
+
+	min, max := p[0].UnixNano(), p[0].UnixNano()
+	for _, pt := range p[1:] {
+		if ts := pt.UnixNano(); ts < min {
+			min = ts
+		} else if ts > max {
+			max = ts
+		}
+	}
+	w.Listener.BucketWritten(ctx, orgID, bucketID, time.Unix(0, min).UTC(), time.Unix(0, max).UTC())
+	return nil
+}
+
 type BufferedPointsWriter struct {
 	buf      []models.Point
 	orgID    influxdb.ID
@@ -0,0 +1,56 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage"
+)
+
+type bucketWrite struct {
+	orgID, bucketID influxdb.ID
+	min, max        time.Time
+}
+
+type writeListener struct {
+	writes []bucketWrite
+}
+
+func (l *writeListener) BucketWritten(ctx context.Context, orgID, bucketID influxdb.ID, min, max time.Time) {
+	l.writes = append(l.writes, bucketWrite{orgID: orgID, bucketID: bucketID, min: min, max: max})
+}
+
+func TestNotifyingPointsWriter(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := []models.Point{
+		models.MustNewPoint("m", nil, models.Fields{"f": 1.0}, t0.Add(time.Minute)),
+		models.MustNewPoint("m", nil, models.Fields{"f": 2.0}, t0),
+		models.MustNewPoint("m", nil, models.Fields{"f": 3.0}, t0.Add(time.Hour)),
+	}
+
+	underlying := &mock.PointsWriter{}
+	l := &writeListener{}
+	w := storage.NewNotifyingPointsWriter(underlying, l)
+
+	if err := w.WritePoints(context.Background(), 1, 2, points); err != nil {
+		t.Fatal(err)
+	}
+	exp := bucketWrite{orgID: 1, bucketID: 2, min: t0, max: t0.Add(time.Hour)}
+	if len(l.writes) != 1 || l.writes[0] != exp {
+		t.Fatalf("unexpected notifications: got %v, want %v", l.writes, exp)
+	}
+
+	// failed writes are not notified
+	underlying.ForceError(errors.New("write failed"))
+	if err := w.WritePoints(context.Background(), 1, 2, points); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(l.writes) != 1 {
+		t.Fatalf("expected failed write not to be notified, got %v", l.writes)
+	}
+}
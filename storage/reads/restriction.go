@@ -0,0 +1,47 @@
+package reads
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/storage/reads/datatypes"
+)
+
+// Restrictions limit the series of a bucket that can be read.
+type Restrictions interface {
+	// AllowsMeasurement reports whether any series of the measurement can be read.
+	AllowsMeasurement(name string) bool
+
+	// MeasurementDataType returns the tag predicate the series of the
+	// measurement must match. The node is nil when every series of the
+	// measurement can be read, and ok is false when none can.
+	MeasurementDataType(name string) (n *datatypes.Node, ok bool)
+
+	// ToDataType returns a predicate matching every series that can be read.
+	ToDataType() (*datatypes.Node, error)
+}
+
+// RestrictionFinder returns the restrictions that apply when the caller
+// identified by ctx reads from the bucket. Nil restrictions are returned
+// when the read is not restricted.
+type RestrictionFinder func(ctx context.Context, orgID, bucketID influxdb.ID) (Restrictions, error)
+
+// RestrictPredicate combines the predicate with the restrictions so that
+// only the series allowed by both are matched.
+func RestrictPredicate(pred *datatypes.Predicate, rs Restrictions) (*datatypes.Predicate, error) {
+	node, err := rs.ToDataType()
+	if err != nil {
+		return nil, err
+	}
+	if root := pred.GetRoot(); root != nil {
+		node = &datatypes.Node{
+			NodeType: datatypes.NodeTypeLogicalExpression,
+			Value:    &datatypes.Node_Logical_{Logical: datatypes.LogicalAnd},
+			Children: []*datatypes.Node{
+				{NodeType: datatypes.NodeTypeParenExpression, Children: []*datatypes.Node{root}},
+				{NodeType: datatypes.NodeTypeParenExpression, Children: []*datatypes.Node{node}},
+			},
+		}
+	}
+	return &datatypes.Predicate{Root: node}, nil
+}
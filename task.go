@@ -43,6 +43,12 @@ type Task struct {
 	Every           string                 `json:"every,omitempty"`
 	Cron            string                 `json:"cron,omitempty"`
 	Offset          time.Duration          `json:"offset,omitempty"`
+	DependsOn       []ID                   `json:"dependsOn,omitempty"`
+	Trigger         string                 `json:"trigger,omitempty"`
+	Debounce        time.Duration          `json:"debounce,omitempty"`
+	Timeout         time.Duration          `json:"timeout,omitempty"`
+	Catchup         string                 `json:"catchup,omitempty"`
+	Revision        int                    `json:"revision,omitempty"`
 	LatestCompleted time.Time              `json:"latestCompleted,omitempty"`
 	LatestScheduled time.Time              `json:"latestScheduled,omitempty"`
 	LatestSuccess   time.Time              `json:"latestSuccess,omitempty"`
@@ -79,9 +85,28 @@ type Run struct {
 	StartedAt    time.Time `json:"startedAt,omitempty"`   // StartedAt is the time the executor begins running the task
 	FinishedAt   time.Time `json:"finishedAt,omitempty"`  // FinishedAt is the time the executor finishes running the task
 	RequestedAt  time.Time `json:"requestedAt,omitempty"` // RequestedAt is the time the coordinator told the scheduler to schedule the task
+	RetryOf      ID        `json:"retryOf,omitempty"`     // RetryOf is the ID of the original failed run this run retries
+	Attempt      int       `json:"attempt,omitempty"`     // Attempt is the attempt number of a retry, starting at 2 for the first retry
+	Revision     int       `json:"revision,omitempty"`    // Revision is the revision of the script of the task the run used
 	Log          []Log     `json:"log,omitempty"`
 }
 
+// AttemptNumber returns the attempt number of the run, runs that are not retries are the first attempt.
+func (r *Run) AttemptNumber() int {
+	if r.Attempt < 1 {
+		return 1
+	}
+	return r.Attempt
+}
+
+// OriginalRunID returns the ID of the first attempt of the run.
+func (r *Run) OriginalRunID() ID {
+	if r.RetryOf.Valid() {
+		return r.RetryOf
+	}
+	return r.ID
+}
+
 // Log represents a link to a log resource
 type Log struct {
 	RunID   ID     `json:"runID,omitempty"`
@@ -141,6 +166,7 @@ type TaskCreate struct {
 	OrganizationID ID                     `json:"orgID,omitempty"`
 	Organization   string                 `json:"org,omitempty"`
 	OwnerID        ID                     `json:"-"`
+	DependsOn      []ID                   `json:"dependsOn,omitempty"`
 	Metadata       map[string]interface{} `json:"-"` // not to be set through a web request but rather used by a http service using tasks backend.
 }
 
@@ -162,6 +188,12 @@ type TaskUpdate struct {
 	Status      *string `json:"status,omitempty"`
 	Description *string `json:"description,omitempty"`
 
+	// DependsOn replaces the upstream tasks of the task when set, an empty list removes them.
+	DependsOn *[]ID `json:"dependsOn,omitempty"`
+
+	// RollbackOf marks the revision made by the update as a rollback to the given revision.
+	RollbackOf *int `json:"-"`
+
 	// LatestCompleted us to set latest completed on startup to skip task catchup
 	LatestCompleted *time.Time             `json:"-"`
 	LatestScheduled *time.Time             `json:"-"`
@@ -182,6 +214,7 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 		Status      *string `json:"status,omitempty"`
 		Name        string  `json:"name,omitempty"`
 		Description *string `json:"description,omitempty"`
+		DependsOn   *[]ID   `json:"dependsOn,omitempty"`
 
 		// Cron is a cron style time schedule that can be used in place of Every.
 		Cron string `json:"cron,omitempty"`
@@ -204,6 +237,7 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 	}
 	t.Options.Name = jo.Name
 	t.Description = jo.Description
+	t.DependsOn = jo.DependsOn
 	t.Options.Cron = jo.Cron
 	t.Options.Every = jo.Every
 	if jo.Offset != nil {
@@ -223,6 +257,7 @@ func (t *TaskUpdate) MarshalJSON() ([]byte, error) {
 		Status      *string `json:"status,omitempty"`
 		Name        string  `json:"name,omitempty"`
 		Description *string `json:"description,omitempty"`
+		DependsOn   *[]ID   `json:"dependsOn,omitempty"`
 
 		// Cron is a cron style time schedule that can be used in place of Every.
 		Cron string `json:"cron,omitempty"`
@@ -241,6 +276,7 @@ func (t *TaskUpdate) MarshalJSON() ([]byte, error) {
 	jo.Cron = t.Options.Cron
 	jo.Every = t.Options.Every
 	jo.Description = t.Description
+	jo.DependsOn = t.DependsOn
 	if t.Options.Offset != nil {
 		offset := *t.Options.Offset
 		jo.Offset = &offset
@@ -264,7 +300,7 @@ func (t *TaskUpdate) Validate() error {
 		if _, err := time.ParseDuration(t.Options.Offset.String()); err != nil {
 			return fmt.Errorf("offset: %s, %s is invalid, the largest unit supported is h", t.Options.Offset.String(), err)
 		}
-	case t.Flux == nil && t.Status == nil && t.Options.IsZero():
+	case t.Flux == nil && t.Status == nil && t.DependsOn == nil && t.Options.IsZero():
 		return errors.New("cannot update task without content")
 	case t.Status != nil && *t.Status != TaskStatusActive && *t.Status != TaskStatusInactive:
 		return fmt.Errorf("invalid task status: %q", *t.Status)
@@ -534,6 +570,7 @@ const (
 	RunFail
 	RunCanceled
 	RunScheduled
+	RunTimeout
 )
 
 func (r RunStatus) String() string {
@@ -548,6 +585,8 @@ func (r RunStatus) String() string {
 		return "canceled"
 	case RunScheduled:
 		return "scheduled"
+	case RunTimeout:
+		return "timeout"
 	}
 	panic(fmt.Sprintf("unknown RunStatus: %d", r))
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
 	"github.com/influxdata/influxdb/v2/query"
 	"github.com/influxdata/influxdb/v2/storage"
 	"go.uber.org/zap"
@@ -22,6 +23,10 @@ const (
 	startedAtField    = "startedAt"
 	finishedAtField   = "finishedAt"
 	requestedAtField  = "requestedAt"
+	runAtField        = "runAt"
+	retryOfField      = "retryOf"
+	attemptField      = "attempt"
+	revisionField     = "revision"
 	logField          = "logs"
 
 	taskIDTag = "taskID"
@@ -155,8 +160,8 @@ func (as *AnalyticalStorage) FindRuns(ctx context.Context, filter influxdb.RunFi
 
 	// creates flux script to filter based on time, if given
 	constructedTimeFilter := ""
+	parsedAfterTime := time.Time{}
 	if len(filter.AfterTime) > 0 || len(filter.BeforeTime) > 0 {
-		parsedAfterTime := time.Time{}
 		parsedBeforeTime := time.Now()
 		if len(filter.AfterTime) > 0 {
 			parsedAfterTime, err = time.Parse(time.RFC3339, filter.AfterTime)
@@ -181,9 +186,8 @@ func (as *AnalyticalStorage) FindRuns(ctx context.Context, filter influxdb.RunFi
 			parsedBeforeTime.Format(time.RFC3339))
 	}
 
-	// the data will be stored for 7 days in the system bucket so pulling 14d's is sufficient.
 	runsScript := fmt.Sprintf(`from(bucketID: %q)
-	  |> range(start: -14d)
+	  |> range(start: %s)
 	  |> filter(fn: (r) => r._field != "status")
 	  |> filter(fn: (r) => r._measurement == "runs" and r.taskID == %q)
 	  %s
@@ -193,7 +197,7 @@ func (as *AnalyticalStorage) FindRuns(ctx context.Context, filter influxdb.RunFi
 	  |> sort(columns:["scheduledFor"], desc: true)
 	  |> limit(n:%d)
 
-	  `, sb.ID.String(), filter.Task.String(), filterPart, constructedTimeFilter, filter.Limit-len(runs))
+	  `, sb.ID.String(), runHistoryStart(sb, parsedAfterTime), filter.Task.String(), filterPart, constructedTimeFilter, filter.Limit-len(runs))
 
 	// At this point we are behind authorization
 	// so we are faking a read only permission to the org's system bucket
@@ -215,7 +219,8 @@ func (as *AnalyticalStorage) FindRuns(ctx context.Context, filter influxdb.RunFi
 	}
 	request := &query.Request{Authorization: runAuth, OrganizationID: task.OrganizationID, Compiler: lang.FluxCompiler{Query: runsScript}}
 
-	ittr, err := as.qs.Query(ctx, request)
+	// the system bucket is read with the authorization above, not the one of ctx
+	ittr, err := as.qs.Query(icontext.SetUnrestricted(ctx), request)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -238,6 +243,27 @@ func (as *AnalyticalStorage) FindRuns(ctx context.Context, filter influxdb.RunFi
 
 }
 
+// maxRunHistoryLookback is how far back the run history is searched when the
+// system bucket keeps it longer, or forever.
+const maxRunHistoryLookback = 90 * 24 * time.Hour
+
+// runHistoryStart returns the start of the flux range covering the run
+// history kept in the system bucket, which is at least 14 days and at most
+// maxRunHistoryLookback. A non zero after narrows the range to the runs
+// started since.
+func runHistoryStart(sb *influxdb.Bucket, after time.Time) string {
+	lookback := 14 * 24 * time.Hour
+	if sb.RetentionPeriod == 0 || sb.RetentionPeriod > maxRunHistoryLookback {
+		lookback = maxRunHistoryLookback
+	} else if sb.RetentionPeriod > lookback {
+		lookback = sb.RetentionPeriod
+	}
+	if !after.IsZero() && time.Since(after) < lookback {
+		return after.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprintf("-%ds", int64(lookback/time.Second))
+}
+
 // remove any kv runs that exist in the list of completed runs
 func (as *AnalyticalStorage) combineRuns(currentRuns, completeRuns []*influxdb.Run) []*influxdb.Run {
 	crMap := map[influxdb.ID]int{}
@@ -282,15 +308,14 @@ func (as *AnalyticalStorage) FindRunByID(ctx context.Context, taskID, runID infl
 		return run, err
 	}
 
-	// the data will be stored for 7 days in the system bucket so pulling 14d's is sufficient.
 	findRunScript := fmt.Sprintf(`from(bucketID: %q)
-	|> range(start: -14d)
+	|> range(start: %s)
 	|> filter(fn: (r) => r._field != "status")
 	|> filter(fn: (r) => r._measurement == "runs" and r.taskID == %q)
 	|> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
 	|> group(columns: ["taskID"])
 	|> filter(fn: (r) => r.runID == %q)
-	  `, sb.ID.String(), taskID.String(), runID.String())
+	  `, sb.ID.String(), runHistoryStart(sb, time.Time{}), taskID.String(), runID.String())
 
 	// At this point we are behind authorization
 	// so we are faking a read only permission to the org's system bucket
@@ -312,13 +337,14 @@ func (as *AnalyticalStorage) FindRunByID(ctx context.Context, taskID, runID infl
 	}
 	request := &query.Request{Authorization: runAuth, OrganizationID: task.OrganizationID, Compiler: lang.FluxCompiler{Query: findRunScript}}
 
-	ittr, err := as.qs.Query(ctx, request)
+	// the system bucket is read with the authorization above, not the one of ctx
+	ittr, err := as.qs.Query(icontext.SetUnrestricted(ctx), request)
 	if err != nil {
 		return nil, err
 	}
 	defer ittr.Release()
 
-	re := &runReader{}
+	re := &runReader{log: as.log.With(zap.String("component", "run-reader"), zap.String("taskID", taskID.String()))}
 	for ittr.More() {
 		err := ittr.Next().Tables().Do(re.readTable)
 		if err != nil {
@@ -414,6 +440,15 @@ func (re *runReader) readRuns(cr flux.ColReader) error {
 					continue
 				}
 				r.RequestedAt = requested.UTC()
+			case runAtField:
+				if cr.Strings(j).ValueString(i) != "" {
+					runAt, err := time.Parse(time.RFC3339, cr.Strings(j).ValueString(i))
+					if err != nil {
+						re.log.Info("Failed to parse runAt time", zap.Error(err))
+						continue
+					}
+					r.RunAt = runAt.UTC()
+				}
 			case scheduledForField:
 				scheduled, err := time.Parse(time.RFC3339, cr.Strings(j).ValueString(i))
 				if err != nil {
@@ -430,6 +465,23 @@ func (re *runReader) readRuns(cr flux.ColReader) error {
 					continue
 				}
 				r.FinishedAt = finished.UTC()
+			case retryOfField:
+				if cr.Strings(j).ValueString(i) != "" {
+					id, err := influxdb.IDFromString(cr.Strings(j).ValueString(i))
+					if err != nil {
+						re.log.Info("Failed to parse retryOf", zap.Error(err))
+						continue
+					}
+					r.RetryOf = *id
+				}
+			case attemptField:
+				if cr.Ints(j).IsValid(i) {
+					r.Attempt = int(cr.Ints(j).Value(i))
+				}
+			case revisionField:
+				if cr.Ints(j).IsValid(i) {
+					r.Revision = int(cr.Ints(j).Value(i))
+				}
 			case logField:
 				logBytes := bytes.TrimSpace(cr.Strings(j).Value(i))
 				if len(logBytes) != 0 {
@@ -229,3 +229,99 @@ func newAnalyticalBackend(t *testing.T, orgSvc influxdb.OrganizationService, buc
 		storageEngine:   engine,
 	}
 }
+
+func TestFindTaskAnalytics(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	store := inmem.NewKVStore()
+	if err := all.Up(context.Background(), logger, store); err != nil {
+		t.Fatal(err)
+	}
+
+	tenantStore := tenant.NewStore(store)
+	ts := tenant.NewService(tenantStore)
+
+	metaClient := meta.NewClient(meta.NewConfig(), store)
+	require.NoError(t, metaClient.Open())
+
+	_, err := metaClient.CreateDatabase(influxdb.ID(10).String())
+	require.NoError(t, err)
+
+	ab := newAnalyticalBackend(t, ts.OrganizationService, ts.BucketService, metaClient)
+	defer ab.Close(t)
+
+	t0 := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+	run := func(id, taskID influxdb.ID, status influxdb.RunStatus, latency, duration time.Duration, logs ...string) *influxdb.Run {
+		r := &influxdb.Run{
+			ID:           id,
+			TaskID:       taskID,
+			Status:       status.String(),
+			ScheduledFor: t0,
+			RunAt:        t0.Add(time.Duration(id) * time.Minute),
+			StartedAt:    t0.Add(time.Duration(id)*time.Minute + latency),
+		}
+		r.FinishedAt = r.StartedAt.Add(duration)
+		for _, l := range logs {
+			r.Log = append(r.Log, influxdb.Log{Message: l})
+		}
+		return r
+	}
+	runs := []*influxdb.Run{
+		run(1, 2, influxdb.RunSuccess, time.Second, 2*time.Second),
+		run(2, 1, influxdb.RunSuccess, time.Second, time.Second),
+		run(3, 1, influxdb.RunSuccess, time.Second, 2*time.Second),
+		run(4, 1, influxdb.RunFail, 3*time.Second, 3*time.Second, "Started task from script", "bucket not found", "Completed(failed)"),
+		run(5, 1, influxdb.RunTimeout, time.Second, 10*time.Second, "run timed out after 10s", "Completed(timeout)"),
+		run(6, 1, influxdb.RunFail, time.Second, 4*time.Second, "bucket not found", "Completed(failed)"),
+	}
+	// a manual run waits from the time it was requested
+	runs[1].RequestedAt = runs[1].StartedAt.Add(-2 * time.Second)
+
+	rr := backend.NewStoragePointsWriterRecorder(logger, ab.PointsWriter())
+	for _, r := range runs {
+		if err := rr.Record(context.Background(), 20, "org", 10, influxdb.TasksSystemBucketName, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svcStack := backend.NewAnalyticalStorage(logger, &mock.TaskService{}, mock.NewBucketService(), &mock.TaskControlService{}, ab.PointsWriter(), ab.QueryService())
+
+	got, err := svcStack.FindTaskAnalytics(context.Background(), influxdb.TaskAnalyticsFilter{OrganizationID: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []*influxdb.TaskAnalytics{
+		{
+			TaskID: 1,
+			Runs:   5,
+			Statuses: map[string]int{
+				"success": 2,
+				"failed":  2,
+				"timeout": 1,
+			},
+			SuccessRate:  0.4,
+			Duration:     influxdb.DurationSummary{Mean: 4, P50: 3, P95: 10, Max: 10},
+			QueueLatency: influxdb.DurationSummary{Mean: 1.6, P50: 1, P95: 3, Max: 3},
+			FailureReasons: []influxdb.FailureReason{
+				{Reason: "bucket not found", Count: 2},
+				{Reason: "run timed out after 10s", Count: 1},
+			},
+		},
+		{
+			TaskID:         2,
+			Runs:           1,
+			Statuses:       map[string]int{"success": 1},
+			SuccessRate:    1,
+			Duration:       influxdb.DurationSummary{Mean: 2, P50: 2, P95: 2, Max: 2},
+			QueueLatency:   influxdb.DurationSummary{Mean: 1, P50: 1, P95: 1, Max: 1},
+			FailureReasons: []influxdb.FailureReason{},
+		},
+	}
+	require.Equal(t, exp, got)
+
+	taskID := influxdb.ID(2)
+	got, err = svcStack.FindTaskAnalytics(context.Background(), influxdb.TaskAnalyticsFilter{OrganizationID: 20, TaskID: &taskID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	require.Equal(t, exp[1:], got)
+}
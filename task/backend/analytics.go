@@ -0,0 +1,309 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/query"
+	"go.uber.org/zap"
+)
+
+var _ influxdb.TaskAnalyticsService = (*AnalyticalStorage)(nil)
+
+// FindTaskAnalytics aggregates the runs recorded in the system bucket of the
+// organization. The runs are counted and summarized by the query, only the
+// failed runs are read to find the reasons they failed.
+func (as *AnalyticalStorage) FindTaskAnalytics(ctx context.Context, filter influxdb.TaskAnalyticsFilter) ([]*influxdb.TaskAnalytics, error) {
+	if filter.Stop.IsZero() {
+		filter.Stop = time.Now().UTC()
+	}
+	if filter.Start.IsZero() {
+		filter.Start = filter.Stop.Add(-influxdb.DefaultTaskAnalyticsWindow)
+	}
+	if !filter.Start.Before(filter.Stop) {
+		return nil, influxdb.ErrInvalidTaskAnalyticsRange
+	}
+
+	sb, err := as.BucketService.FindBucketByName(ctx, filter.OrganizationID, influxdb.TasksSystemBucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	taskFilter := ""
+	if filter.TaskID != nil {
+		taskFilter = fmt.Sprintf(`and r.taskID == %q`, filter.TaskID.String())
+	}
+
+	analyticsScript := fmt.Sprintf(`runs = from(bucketID: %[1]q)
+	|> range(start: %[2]s, stop: %[3]s)
+	|> filter(fn: (r) => r._measurement == "runs" %[4]s)
+
+times = runs
+	|> filter(fn: (r) => r._field == "startedAt" or r._field == "finishedAt" or r._field == "requestedAt" or r._field == "runAt")
+	|> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
+
+times
+	|> group(columns: ["taskID", "status"])
+	|> count(column: "finishedAt")
+	|> yield(name: %[5]q)
+
+started = times
+	|> filter(fn: (r) => r.startedAt != %[9]q)
+
+durations = started
+	|> map(fn: (r) => ({taskID: r.taskID, _value: float(v: int(v: time(v: r.finishedAt)) - int(v: time(v: r.startedAt))) / 1000000000.0}))
+	|> group(columns: ["taskID"])
+
+requested = started
+	|> filter(fn: (r) => r.requestedAt != %[9]q)
+	|> map(fn: (r) => ({taskID: r.taskID, _value: float(v: int(v: time(v: r.startedAt)) - int(v: time(v: r.requestedAt))) / 1000000000.0}))
+
+scheduled = started
+	|> filter(fn: (r) => r.requestedAt == %[9]q and exists r.runAt)
+	|> map(fn: (r) => ({taskID: r.taskID, _value: float(v: int(v: time(v: r.startedAt)) - int(v: time(v: r.runAt))) / 1000000000.0}))
+
+latencies = union(tables: [requested, scheduled])
+	|> group(columns: ["taskID"])
+
+durations |> mean() |> yield(name: "%[6]s_mean")
+durations |> quantile(q: 0.5, method: "exact_selector") |> yield(name: "%[6]s_p50")
+durations |> quantile(q: 0.95, method: "exact_selector") |> yield(name: "%[6]s_p95")
+durations |> max() |> yield(name: "%[6]s_max")
+
+latencies |> mean() |> yield(name: "%[7]s_mean")
+latencies |> quantile(q: 0.5, method: "exact_selector") |> yield(name: "%[7]s_p50")
+latencies |> quantile(q: 0.95, method: "exact_selector") |> yield(name: "%[7]s_p95")
+latencies |> max() |> yield(name: "%[7]s_max")
+
+runs
+	|> filter(fn: (r) => r.status == %[10]q or r.status == %[11]q)
+	|> filter(fn: (r) => r._field == "runID" or r._field == "logs")
+	|> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
+	|> group(columns: ["taskID"])
+	|> yield(name: %[8]q)
+	`, sb.ID.String(), filter.Start.UTC().Format(time.RFC3339Nano), filter.Stop.UTC().Format(time.RFC3339Nano), taskFilter,
+		statusesResult, durationResult, queueLatencyResult, failedRunsResult,
+		time.Time{}.Format(time.RFC3339), influxdb.RunFail.String(), influxdb.RunTimeout.String())
+
+	request := &query.Request{
+		Authorization:  systemBucketReadAuth(filter.OrganizationID, sb.ID),
+		OrganizationID: filter.OrganizationID,
+		Compiler:       lang.FluxCompiler{Query: analyticsScript},
+	}
+
+	// the system bucket is read with the authorization above, not the one of ctx
+	ittr, err := as.qs.Query(icontext.SetUnrestricted(ctx), request)
+	if err != nil {
+		return nil, err
+	}
+	defer ittr.Release()
+
+	ar := newAnalyticsReader(as.log.With(zap.String("component", "run-reader"), zap.String("orgID", filter.OrganizationID.String())))
+	for ittr.More() {
+		if err := ar.readResult(ittr.Next()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ittr.Err(); err != nil {
+		return nil, fmt.Errorf("unexpected internal error while decoding run response: %v", err)
+	}
+
+	return ar.analytics(), nil
+}
+
+// FindTaskRunRetention returns the retention of the system bucket of the organization.
+func (as *AnalyticalStorage) FindTaskRunRetention(ctx context.Context, orgID influxdb.ID) (*influxdb.TaskRunRetention, error) {
+	sb, err := as.BucketService.FindBucketByName(ctx, orgID, influxdb.TasksSystemBucketName)
+	if err != nil {
+		return nil, err
+	}
+	return &influxdb.TaskRunRetention{OrganizationID: orgID, Retention: sb.RetentionPeriod}, nil
+}
+
+// UpdateTaskRunRetention changes the retention of the system bucket of the
+// organization, a retention of zero keeps the run history forever.
+func (as *AnalyticalStorage) UpdateTaskRunRetention(ctx context.Context, orgID influxdb.ID, retention time.Duration) (*influxdb.TaskRunRetention, error) {
+	if retention < 0 || (retention > 0 && retention < influxdb.MinTaskRunRetention) {
+		return nil, influxdb.ErrInvalidTaskRunRetention
+	}
+
+	sb, err := as.BucketService.FindBucketByName(ctx, orgID, influxdb.TasksSystemBucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	sb, err = as.BucketService.UpdateBucket(ctx, sb.ID, influxdb.BucketUpdate{RetentionPeriod: &retention})
+	if err != nil {
+		return nil, err
+	}
+	return &influxdb.TaskRunRetention{OrganizationID: orgID, Retention: sb.RetentionPeriod}, nil
+}
+
+// systemBucketReadAuth returns an authorization to read the system bucket of
+// the organization. The callers are behind authorization already.
+func systemBucketReadAuth(orgID, bucketID influxdb.ID) *influxdb.Authorization {
+	return &influxdb.Authorization{
+		ID:     bucketID,
+		Status: influxdb.Active,
+		OrgID:  orgID,
+		Permissions: []influxdb.Permission{
+			{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: &orgID,
+					ID:    &bucketID,
+				},
+			},
+		},
+	}
+}
+
+// names of the results of the task analytics query.
+const (
+	statusesResult     = "statuses"
+	durationResult     = "duration"
+	queueLatencyResult = "queue_latency"
+	failedRunsResult   = "failed"
+)
+
+// analyticsReader reads the results of the task analytics query by task.
+type analyticsReader struct {
+	statuses  map[influxdb.ID]map[string]int
+	durations map[influxdb.ID]*influxdb.DurationSummary
+	latencies map[influxdb.ID]*influxdb.DurationSummary
+	failed    *runReader
+	log       *zap.Logger
+}
+
+func newAnalyticsReader(log *zap.Logger) *analyticsReader {
+	return &analyticsReader{
+		statuses:  make(map[influxdb.ID]map[string]int),
+		durations: make(map[influxdb.ID]*influxdb.DurationSummary),
+		latencies: make(map[influxdb.ID]*influxdb.DurationSummary),
+		failed:    &runReader{log: log},
+		log:       log,
+	}
+}
+
+func (ar *analyticsReader) readResult(res flux.Result) error {
+	name := res.Name()
+	switch {
+	case name == statusesResult:
+		return res.Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(ar.readStatuses)
+		})
+	case name == failedRunsResult:
+		return res.Tables().Do(ar.failed.readTable)
+	case strings.HasPrefix(name, durationResult+"_"):
+		return ar.readSummaries(res, ar.durations, strings.TrimPrefix(name, durationResult+"_"))
+	case strings.HasPrefix(name, queueLatencyResult+"_"):
+		return ar.readSummaries(res, ar.latencies, strings.TrimPrefix(name, queueLatencyResult+"_"))
+	}
+	return res.Tables().Do(func(tbl flux.Table) error {
+		tbl.Done()
+		return nil
+	})
+}
+
+func (ar *analyticsReader) readStatuses(cr flux.ColReader) error {
+	taskCol, statusCol, countCol := colIndex(cr, taskIDTag), colIndex(cr, statusTag), colIndex(cr, finishedAtField)
+	if taskCol < 0 || statusCol < 0 || countCol < 0 {
+		return nil
+	}
+	for i := 0; i < cr.Len(); i++ {
+		id, ok := ar.taskID(cr, taskCol, i)
+		if !ok || !cr.Ints(countCol).IsValid(i) {
+			continue
+		}
+		if ar.statuses[id] == nil {
+			ar.statuses[id] = make(map[string]int)
+		}
+		ar.statuses[id][cr.Strings(statusCol).ValueString(i)] += int(cr.Ints(countCol).Value(i))
+	}
+	return nil
+}
+
+// readSummaries reads the statistic of the duration summaries of the tasks.
+func (ar *analyticsReader) readSummaries(res flux.Result, summaries map[influxdb.ID]*influxdb.DurationSummary, stat string) error {
+	return res.Tables().Do(func(tbl flux.Table) error {
+		return tbl.Do(func(cr flux.ColReader) error {
+			taskCol, valueCol := colIndex(cr, taskIDTag), colIndex(cr, "_value")
+			if taskCol < 0 || valueCol < 0 {
+				return nil
+			}
+			for i := 0; i < cr.Len(); i++ {
+				id, ok := ar.taskID(cr, taskCol, i)
+				if !ok || !cr.Floats(valueCol).IsValid(i) {
+					continue
+				}
+				s := summaries[id]
+				if s == nil {
+					s = &influxdb.DurationSummary{}
+					summaries[id] = s
+				}
+				v := cr.Floats(valueCol).Value(i)
+				switch stat {
+				case "mean":
+					s.Mean = v
+				case "p50":
+					s.P50 = v
+				case "p95":
+					s.P95 = v
+				case "max":
+					s.Max = v
+				}
+			}
+			return nil
+		})
+	})
+}
+
+func (ar *analyticsReader) taskID(cr flux.ColReader, j, i int) (influxdb.ID, bool) {
+	id, err := influxdb.IDFromString(cr.Strings(j).ValueString(i))
+	if err != nil {
+		ar.log.Info("Failed to parse taskID", zap.Error(err))
+		return 0, false
+	}
+	return *id, true
+}
+
+// analytics returns the analytics of the tasks with finished runs, ordered by task ID.
+func (ar *analyticsReader) analytics() []*influxdb.TaskAnalytics {
+	failed := make(map[influxdb.ID][]*influxdb.Run)
+	for _, r := range ar.failed.runs {
+		failed[r.TaskID] = append(failed[r.TaskID], r)
+	}
+
+	res := make([]*influxdb.TaskAnalytics, 0, len(ar.statuses))
+	for id, statuses := range ar.statuses {
+		var duration, latency influxdb.DurationSummary
+		if s := ar.durations[id]; s != nil {
+			duration = *s
+		}
+		if s := ar.latencies[id]; s != nil {
+			latency = *s
+		}
+		res = append(res, influxdb.NewTaskAnalytics(id, statuses, duration, latency, failed[id]))
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].TaskID < res[j].TaskID })
+	return res
+}
+
+// colIndex returns the index of the column with the label, or -1.
+func colIndex(cr flux.ColReader, label string) int {
+	for j, col := range cr.Cols() {
+		if col.Label == label {
+			return j
+		}
+	}
+	return -1
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/influxdata/influxdb/v2/task/backend/executor"
 	"github.com/influxdata/influxdb/v2/task/backend/middleware"
 	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
+	"github.com/influxdata/influxdb/v2/task/options"
 	"go.uber.org/zap"
 )
 
@@ -38,8 +39,9 @@ type CoordinatorOption func(*Coordinator)
 // SchedulableTask is a wrapper around the Task struct, giving it methods to make it compatible with the Scheduler
 type SchedulableTask struct {
 	*influxdb.Task
-	sch scheduler.Schedule
-	lsc time.Time
+	sch     scheduler.Schedule
+	lsc     time.Time
+	catchup scheduler.CatchupPolicy
 }
 
 func (t SchedulableTask) ID() scheduler.ID {
@@ -61,6 +63,11 @@ func (t SchedulableTask) LastScheduled() time.Time {
 	return t.lsc
 }
 
+// Catchup returns the policy parsed from the Task's catchup property
+func (t SchedulableTask) Catchup() scheduler.CatchupPolicy {
+	return t.catchup
+}
+
 func WithLimitOpt(i int) CoordinatorOption {
 	return func(c *Coordinator) {
 		c.limit = i
@@ -87,7 +94,16 @@ func NewSchedulableTask(task *influxdb.Task) (SchedulableTask, error) {
 	if err != nil {
 		return SchedulableTask{}, err
 	}
-	return SchedulableTask{Task: task, sch: sch, lsc: ts}, nil
+
+	var catchup scheduler.CatchupPolicy
+	max, err := options.ParseCatchup(task.Catchup)
+	if err != nil {
+		return SchedulableTask{}, err
+	}
+	if max >= 0 {
+		catchup = scheduler.CatchupPolicy{Limited: true, Max: max}
+	}
+	return SchedulableTask{Task: task, sch: sch, lsc: ts, catchup: catchup}, nil
 }
 
 func NewCoordinator(log *zap.Logger, scheduler scheduler.Scheduler, executor Executor, opts ...CoordinatorOption) *Coordinator {
@@ -107,6 +123,11 @@ func NewCoordinator(log *zap.Logger, scheduler scheduler.Scheduler, executor Exe
 
 // TaskCreated asks the Scheduler to schedule the newly created task
 func (c *Coordinator) TaskCreated(ctx context.Context, task *influxdb.Task) error {
+	// tasks run only by writes to their trigger bucket are not scheduled
+	if task.EffectiveCron() == "" && task.Trigger != "" {
+		return nil
+	}
+
 	t, err := NewSchedulableTask(task)
 
 	if err != nil {
@@ -124,6 +145,15 @@ func (c *Coordinator) TaskCreated(ctx context.Context, task *influxdb.Task) erro
 // TaskUpdated releases the task if it is being disabled, and schedules it otherwise
 func (c *Coordinator) TaskUpdated(ctx context.Context, from, to *influxdb.Task) error {
 	sid := scheduler.ID(to.ID)
+
+	// a task that lost its schedule for a trigger is only run by writes
+	if to.EffectiveCron() == "" && to.Trigger != "" {
+		if err := c.sch.Release(sid); err != nil && err != influxdb.ErrTaskNotClaimed {
+			return err
+		}
+		return nil
+	}
+
 	t, err := NewSchedulableTask(to)
 	if err != nil {
 		return err
@@ -124,6 +124,7 @@ func Test_Coordinator_Scheduler_Methods(t *testing.T) {
 		one   = influxdb.ID(1)
 		two   = influxdb.ID(2)
 		three = influxdb.ID(3)
+		four  = influxdb.ID(4)
 		now   = time.Now().UTC()
 
 		taskOne           = &influxdb.Task{ID: one, CreatedAt: now, Cron: "* * * * *"}
@@ -143,6 +144,8 @@ func Test_Coordinator_Scheduler_Methods(t *testing.T) {
 			CreatedAt: now,
 			Cron:      "* * * * *",
 		}
+		taskFour          = &influxdb.Task{ID: four, Status: "active", CreatedAt: now, Cron: "* * * * *"}
+		taskFourTriggered = &influxdb.Task{ID: four, Status: "active", CreatedAt: now, Trigger: "raw"}
 	)
 
 	schedulableT, err := NewSchedulableTask(taskOne)
@@ -225,6 +228,28 @@ func Test_Coordinator_Scheduler_Methods(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "TaskCreated - triggered task",
+			call: func(t *testing.T, c *Coordinator) {
+				if err := c.TaskCreated(context.Background(), taskFourTriggered); err != nil {
+					t.Errorf("expected nil error found %q", err)
+				}
+			},
+			scheduler: &schedulerC{},
+		},
+		{
+			name: "TaskUpdated - replace schedule with trigger",
+			call: func(t *testing.T, c *Coordinator) {
+				if err := c.TaskUpdated(context.Background(), taskFour, taskFourTriggered); err != nil {
+					t.Errorf("expected nil error found %q", err)
+				}
+			},
+			scheduler: &schedulerC{
+				calls: []interface{}{
+					releaseCallC{scheduler.ID(taskFour.ID)},
+				},
+			},
+		},
 		{
 			name: "TaskDeleted",
 			call: func(t *testing.T, c *Coordinator) {
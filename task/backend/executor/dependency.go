@@ -0,0 +1,282 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"go.uber.org/zap"
+)
+
+// Runs of tasks that depend on other tasks are gated on their upstream tasks.
+// When the scheduler triggers the run of a task for a window, the run is
+// created in the task store and starts once every upstream task has a
+// successful run scheduled for the same time. When the upstream run for the
+// window failed and is not retried, the run fails without executing the task.
+// Waiting runs are checked again every time a run of an upstream task
+// finishes, or the task is triggered for a following window. They stay in
+// the task store until they start, and are gated again when they are resumed
+// after a restart.
+//
+// Manually requested runs and runs started through PromisedExecute, such as
+// backfills, are not gated.
+
+// maxDependencyWaits is the largest number of runs of a task that wait for
+// upstream tasks, the oldest one is canceled when another run has to wait.
+const maxDependencyWaits = 100
+
+type upstreamState int
+
+const (
+	upstreamReady upstreamState = iota
+	upstreamWaiting
+	upstreamFailed
+)
+
+// dependencies tracks the runs waiting for upstream tasks.
+type dependencies struct {
+	mu        sync.Mutex
+	waits     map[influxdb.ID][]*influxdb.Run
+	upstreams map[influxdb.ID][]influxdb.ID
+}
+
+func newDependencies() *dependencies {
+	return &dependencies{
+		waits:     make(map[influxdb.ID][]*influxdb.Run),
+		upstreams: make(map[influxdb.ID][]influxdb.ID),
+	}
+}
+
+// add records the waiting run of the task, returning the run that was
+// dropped to make room for it, if any.
+func (d *dependencies) add(t *influxdb.Task, r *influxdb.Run) (dropped *influxdb.Run) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	waits := d.waits[t.ID]
+	for _, w := range waits {
+		if w.ID == r.ID {
+			return nil
+		}
+	}
+	waits = append(waits, r)
+	sort.Slice(waits, func(i, j int) bool { return waits[i].ScheduledFor.Before(waits[j].ScheduledFor) })
+	if len(waits) > maxDependencyWaits {
+		dropped = waits[0]
+		waits = waits[1:]
+	}
+	d.waits[t.ID] = waits
+	d.upstreams[t.ID] = t.DependsOn
+	return dropped
+}
+
+// take removes and returns the waiting runs of the task, oldest first.
+func (d *dependencies) take(id influxdb.ID) []*influxdb.Run {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	waits := d.waits[id]
+	delete(d.waits, id)
+	delete(d.upstreams, id)
+	return waits
+}
+
+// waiting reports whether the run waits for upstream tasks.
+func (d *dependencies) waiting(taskID, runID influxdb.ID) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, w := range d.waits[taskID] {
+		if w.ID == runID {
+			return true
+		}
+	}
+	return false
+}
+
+// downstream returns the tasks with waiting runs that depend on the upstream task.
+func (d *dependencies) downstream(upstream influxdb.ID) []influxdb.ID {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var ids []influxdb.ID
+	for id, ups := range d.upstreams {
+		for _, up := range ups {
+			if up == upstream {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	return ids
+}
+
+// waitUpstream gates the run on the upstream tasks of the task, it joins the
+// runs of the task that already wait and all of them are checked in order.
+func (e *Executor) waitUpstream(ctx context.Context, t *influxdb.Task, r *influxdb.Run) error {
+	if dropped := e.deps.add(t, r); dropped != nil {
+		e.log.Warn("Dropped run waiting for upstream tasks",
+			zap.String("taskID", t.ID.String()),
+			zap.Time("scheduledFor", dropped.ScheduledFor))
+		e.finishWaiting(ctx, dropped, influxdb.RunCanceled, fmt.Sprintf("More than %d runs wait for upstream tasks, the oldest one is canceled", maxDependencyWaits))
+	}
+	return e.releaseWaits(ctx, t)
+}
+
+// releaseWaits starts or fails the waiting runs of the task whose upstream
+// tasks are done with their windows, the others keep waiting.
+func (e *Executor) releaseWaits(ctx context.Context, t *influxdb.Task) error {
+	var firstErr error
+	for _, r := range e.deps.take(t.ID) {
+		if err := e.releaseWait(ctx, t, r); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (e *Executor) releaseWait(ctx context.Context, t *influxdb.Task, r *influxdb.Run) error {
+	state, failed, err := e.checkUpstream(ctx, t, r.ScheduledFor)
+	if err != nil {
+		e.deps.add(t, r)
+		return err
+	}
+
+	switch state {
+	case upstreamReady:
+		if _, err := e.createPromise(ctx, r, nil, false); err != nil {
+			e.finishWaiting(ctx, r, influxdb.RunFail, fmt.Sprintf("Failed to enqueue run: %s", err.Error()))
+			return err
+		}
+		e.startWorker()
+		return nil
+	case upstreamFailed:
+		return e.failDependent(ctx, t, r, failed)
+	default:
+		e.deps.add(t, r)
+		e.log.Debug("Waiting for upstream tasks", zap.String("taskID", t.ID.String()), zap.Time("scheduledFor", r.ScheduledFor))
+		return nil
+	}
+}
+
+// checkUpstream returns whether the upstream tasks of the task are done with
+// the window scheduled for scheduledFor, and the upstream task that failed it.
+func (e *Executor) checkUpstream(ctx context.Context, t *influxdb.Task, scheduledFor time.Time) (upstreamState, *influxdb.Task, error) {
+	state := upstreamReady
+	for _, id := range t.DependsOn {
+		u, err := e.ts.FindTaskByID(ctx, id)
+		if err != nil {
+			return state, nil, err
+		}
+
+		s, err := e.upstreamWindow(ctx, u, scheduledFor)
+		if err != nil {
+			return state, nil, err
+		}
+		switch s {
+		case upstreamFailed:
+			return upstreamFailed, u, nil
+		case upstreamWaiting:
+			state = upstreamWaiting
+		}
+	}
+	return state, nil, nil
+}
+
+// upstreamWindow returns the state of the runs of the upstream task scheduled
+// for the time. The window failed when it has a failed run and no run in
+// progress, retries are created before the run they retry finishes. The
+// latest runs of the task are checked first, older ones are found in the
+// run history.
+func (e *Executor) upstreamWindow(ctx context.Context, u *influxdb.Task, scheduledFor time.Time) (upstreamState, error) {
+	if u.LatestSuccess.Equal(scheduledFor) {
+		return upstreamReady, nil
+	}
+
+	current, err := e.tcs.CurrentlyRunning(ctx, u.ID)
+	if err != nil {
+		return upstreamWaiting, err
+	}
+	var inProgress bool
+	for _, r := range current {
+		if r.ScheduledFor.Equal(scheduledFor) {
+			inProgress = true
+		}
+	}
+
+	runs, _, err := e.ts.FindRuns(ctx, influxdb.RunFilter{
+		Task:       u.ID,
+		AfterTime:  scheduledFor.Add(-time.Second).Format(time.RFC3339),
+		BeforeTime: scheduledFor.Add(time.Second).Format(time.RFC3339),
+		Limit:      influxdb.TaskMaxPageSize,
+	})
+	if err != nil {
+		return upstreamWaiting, err
+	}
+	failed := u.LatestFailure.Equal(scheduledFor)
+	for _, r := range runs {
+		if !r.ScheduledFor.Equal(scheduledFor) {
+			continue
+		}
+		switch r.Status {
+		case influxdb.RunSuccess.String():
+			return upstreamReady, nil
+		case influxdb.RunFail.String(), influxdb.RunTimeout.String(), influxdb.RunCanceled.String():
+			failed = true
+		}
+	}
+
+	if failed && !inProgress {
+		return upstreamFailed, nil
+	}
+	return upstreamWaiting, nil
+}
+
+// failDependent fails the waiting run of the task for the window the upstream
+// task failed, and passes the failure on to the tasks depending on it.
+func (e *Executor) failDependent(ctx context.Context, t *influxdb.Task, r *influxdb.Run, failed *influxdb.Task) error {
+	if err := e.finishWaiting(ctx, r, influxdb.RunFail, fmt.Sprintf("Upstream task %q (%s) failed for %s", failed.Name, failed.ID, r.ScheduledFor.UTC().Format(time.RFC3339))); err != nil {
+		return err
+	}
+	e.metrics.LogUpstreamFailure(t)
+
+	e.releaseDependents(ctx, t.ID)
+	return nil
+}
+
+// finishWaiting finishes the waiting run without executing it.
+func (e *Executor) finishWaiting(ctx context.Context, r *influxdb.Run, state influxdb.RunStatus, msg string) error {
+	now := time.Now().UTC()
+	e.tcs.AddRunLog(ctx, r.TaskID, r.ID, now, msg)
+	e.tcs.UpdateRunState(ctx, r.TaskID, r.ID, now, state)
+	_, err := e.tcs.FinishRun(ctx, r.TaskID, r.ID)
+	return err
+}
+
+// releaseDependents checks the waiting runs of the tasks depending on the
+// upstream task, after one of its runs finished.
+func (e *Executor) releaseDependents(ctx context.Context, upstream influxdb.ID) {
+	for _, id := range e.deps.downstream(upstream) {
+		t, err := e.ts.FindTaskByID(ctx, id)
+		if err != nil {
+			e.log.Error("Failed to find task waiting for upstream tasks", zap.String("taskID", id.String()), zap.Error(err))
+			if influxdb.ErrorCode(err) == influxdb.ENotFound {
+				e.deps.take(id)
+			}
+			continue
+		}
+		if t.Status != influxdb.TaskStatusActive {
+			for _, r := range e.deps.take(id) {
+				e.finishWaiting(ctx, r, influxdb.RunCanceled, "Run canceled, the task is inactive")
+			}
+			continue
+		}
+
+		if err := e.releaseWaits(ctx, t); err != nil {
+			e.log.Error("Failed to release runs waiting for upstream tasks", zap.String("taskID", id.String()), zap.Error(err))
+		}
+	}
+}
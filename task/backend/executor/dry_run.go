@@ -0,0 +1,253 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/query"
+)
+
+// Dry runs execute the script of a task with the calls writing data, to()
+// and experimental.to(), replaced by yields. The results of the yields are
+// the tables the calls would have written.
+
+// dryRunYieldPrefix prefixes the names of the yields replacing writes.
+const dryRunYieldPrefix = "_dry_run_write_"
+
+var _ influxdb.TaskDryRunService = (*Executor)(nil)
+
+// DryRunTask runs the script of the task as if it was scheduled for now,
+// returning the tables it would have written instead of writing them.
+func (e *Executor) DryRunTask(ctx context.Context, taskID influxdb.ID, now time.Time) (*influxdb.TaskDryRun, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if e.lang == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "dry runs are not available",
+		}
+	}
+
+	t, err := e.ts.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	script, calls, err := interceptWrites(e.lang, t.Flux)
+	if err != nil {
+		return nil, influxdb.ErrFluxParseError(err)
+	}
+
+	perm, err := e.ps.FindPermissionForUser(ctx, t.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	auth := &influxdb.Authorization{
+		Status:      influxdb.Active,
+		UserID:      t.OwnerID,
+		ID:          influxdb.ID(1),
+		OrgID:       t.OrganizationID,
+		Permissions: perm,
+	}
+	ctx = icontext.SetAuthorizer(ctx, auth)
+
+	now = now.UTC()
+	buildCompiler := e.systemBuildCompiler
+	if t.Type != influxdb.TaskSystemType {
+		buildCompiler = e.nonSystemBuildCompiler
+	}
+	ts := CompilerBuilderTimestamps{
+		Now:           now,
+		LatestSuccess: t.LatestSuccess,
+	}
+	if t.Trigger != "" {
+		start := t.LatestSuccess
+		if start.IsZero() || start.After(now) {
+			start = now
+		}
+		ts.TriggerStart, ts.TriggerStop = start, now
+	}
+	compiler, err := buildCompiler(ctx, script, ts)
+	if err != nil {
+		return nil, influxdb.ErrFluxParseError(err)
+	}
+
+	if timeout := e.timeout(t); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	req := &query.Request{
+		Authorization:  auth,
+		OrganizationID: t.OrganizationID,
+		Compiler:       compiler,
+	}
+	it, err := e.qs.Query(ctx, req)
+	if err != nil {
+		return nil, influxdb.ErrQueryError(err)
+	}
+	defer it.Release()
+
+	dr := &influxdb.TaskDryRun{
+		TaskID: t.ID,
+		Now:    now,
+		Writes: make([]*influxdb.TaskDryRunWrite, len(calls)),
+	}
+	for i, call := range calls {
+		dr.Writes[i] = &influxdb.TaskDryRunWrite{
+			Call:   call,
+			Tables: []*influxdb.TaskDryRunTable{},
+		}
+	}
+
+	for it.More() {
+		res := it.Next()
+		var w *influxdb.TaskDryRunWrite
+		if strings.HasPrefix(res.Name(), dryRunYieldPrefix) {
+			var i int
+			if _, err := fmt.Sscanf(res.Name(), dryRunYieldPrefix+"%d", &i); err == nil && i < len(dr.Writes) {
+				w = dr.Writes[i]
+			}
+		}
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			if w == nil {
+				return tbl.Do(func(flux.ColReader) error { return nil })
+			}
+			dt, err := readDryRunTable(tbl)
+			if err != nil {
+				return err
+			}
+			w.Tables = append(w.Tables, dt)
+			w.Rows += dt.RowCount
+			return nil
+		}); err != nil {
+			return nil, influxdb.ErrRunExecutionError(err)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, influxdb.ErrResultIteratorError(err)
+	}
+	return dr, nil
+}
+
+// interceptWrites replaces the calls writing data in the script with yields,
+// returning the script and the calls replaced, in the order of their yields.
+func interceptWrites(lang influxdb.FluxLanguageService, script string) (string, []string, error) {
+	pkg, err := lang.Parse(script)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(pkg.Files) != 1 {
+		return "", nil, fmt.Errorf("expected a script with a single file, got %d", len(pkg.Files))
+	}
+
+	var calls []string
+	ast.Visit(pkg.Files[0], func(n ast.Node) {
+		call, ok := n.(*ast.CallExpression)
+		if !ok || !isWriteCall(call) {
+			return
+		}
+		calls = append(calls, ast.Format(call))
+
+		props := []*ast.Property{{
+			Key:   &ast.Identifier{Name: "name"},
+			Value: &ast.StringLiteral{Value: fmt.Sprintf("%s%d", dryRunYieldPrefix, len(calls)-1)},
+		}}
+		// calls outside of pipes pass the tables to write as a parameter
+		if len(call.Arguments) == 1 {
+			if obj, ok := call.Arguments[0].(*ast.ObjectExpression); ok {
+				for _, p := range obj.Properties {
+					if p.Key.Key() == "tables" {
+						props = append(props, p)
+					}
+				}
+			}
+		}
+		call.Callee = &ast.Identifier{Name: "yield"}
+		call.Arguments = []ast.Expression{&ast.ObjectExpression{Properties: props}}
+	})
+	return ast.Format(pkg.Files[0]), calls, nil
+}
+
+// isWriteCall returns whether the call is to to() or experimental.to().
+func isWriteCall(call *ast.CallExpression) bool {
+	switch callee := call.Callee.(type) {
+	case *ast.Identifier:
+		return callee.Name == "to"
+	case *ast.MemberExpression:
+		obj, ok := callee.Object.(*ast.Identifier)
+		return ok && obj.Name == "experimental" && callee.Property.Key() == "to"
+	}
+	return false
+}
+
+// readDryRunTable reads the group key, columns and the first rows of the table.
+func readDryRunTable(tbl flux.Table) (*influxdb.TaskDryRunTable, error) {
+	key := tbl.Key()
+	dt := &influxdb.TaskDryRunTable{
+		GroupKey: make(map[string]string, len(key.Cols())),
+		Rows:     [][]interface{}{},
+	}
+	for j, c := range key.Cols() {
+		dt.GroupKey[c.Label] = key.ValueString(j)
+	}
+	for _, c := range tbl.Cols() {
+		dt.Columns = append(dt.Columns, c.Label)
+	}
+
+	err := tbl.Do(func(cr flux.ColReader) error {
+		for i := 0; i < cr.Len(); i++ {
+			dt.RowCount++
+			if len(dt.Rows) >= influxdb.TaskDryRunMaxRows {
+				continue
+			}
+			row := make([]interface{}, len(cr.Cols()))
+			for j, c := range cr.Cols() {
+				row[j] = dryRunValue(cr, c.Type, i, j)
+			}
+			dt.Rows = append(dt.Rows, row)
+		}
+		return nil
+	})
+	return dt, err
+}
+
+// dryRunValue returns the value of a column in a row, nil when it is null.
+func dryRunValue(cr flux.ColReader, typ flux.ColType, i, j int) interface{} {
+	switch typ {
+	case flux.TBool:
+		if vs := cr.Bools(j); vs.IsValid(i) {
+			return vs.Value(i)
+		}
+	case flux.TInt:
+		if vs := cr.Ints(j); vs.IsValid(i) {
+			return vs.Value(i)
+		}
+	case flux.TUInt:
+		if vs := cr.UInts(j); vs.IsValid(i) {
+			return vs.Value(i)
+		}
+	case flux.TFloat:
+		if vs := cr.Floats(j); vs.IsValid(i) {
+			return vs.Value(i)
+		}
+	case flux.TString:
+		if vs := cr.Strings(j); vs.IsValid(i) {
+			return vs.ValueString(i)
+		}
+	case flux.TTime:
+		if vs := cr.Times(j); vs.IsValid(i) {
+			return time.Unix(0, vs.Value(i)).UTC()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+)
+
+func TestInterceptWrites(t *testing.T) {
+	script := `import "experimental"
+
+option task = {name: "downsample", every: 1h}
+
+data = from(bucket: "raw")
+	|> range(start: -task.every)
+
+data
+	|> aggregateWindow(every: 1m, fn: mean)
+	|> to(bucket: "downsampled", org: "my-org")
+
+experimental.to(bucket: "wide", tables: data)
+`
+
+	got, calls, err := interceptWrites(fluxlang.DefaultService, script)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expCalls := []string{
+		`to(bucket: "downsampled", org: "my-org")`,
+		`experimental.to(bucket: "wide", tables: data)`,
+	}
+	if len(calls) != len(expCalls) {
+		t.Fatalf("unexpected calls: got %q, want %q", calls, expCalls)
+	}
+	for i := range expCalls {
+		if calls[i] != expCalls[i] {
+			t.Fatalf("unexpected call %d: got %q, want %q", i, calls[i], expCalls[i])
+		}
+	}
+
+	for _, exp := range []string{
+		`|> yield(name: "_dry_run_write_0")`,
+		`yield(name: "_dry_run_write_1", tables: data)`,
+	} {
+		if !strings.Contains(got, exp) {
+			t.Errorf("expected script to contain %q, got:\n%s", exp, got)
+		}
+	}
+	if strings.Contains(got, "to(") {
+		t.Errorf("expected no writes left in script, got:\n%s", got)
+	}
+}
@@ -25,7 +25,13 @@ const (
 	maxPromises       = 1000
 	defaultMaxWorkers = 100
 
+	// DefaultRetryBackoff is the delay before the first retry of a failed run.
+	DefaultRetryBackoff = 30 * time.Second
+	// DefaultMaxRetryBackoff is the longest delay between retries of a failed run.
+	DefaultMaxRetryBackoff = 10 * time.Minute
+
 	lastSuccessOption = "tasks.lastSuccessTime"
+	triggerOption     = "trigger"
 )
 
 var _ scheduler.Executor = (*Executor)(nil)
@@ -61,6 +67,13 @@ type executorConfig struct {
 	systemBuildCompiler    CompilerBuilderFunc
 	nonSystemBuildCompiler CompilerBuilderFunc
 	flagger                feature.Flagger
+	lang                   influxdb.FluxLanguageService
+	retryBackoff           time.Duration
+	maxRetryBackoff        time.Duration
+	runTimeout             time.Duration
+	orgLimits              OrgLimits
+	orgLimitOverrides      map[influxdb.ID]OrgLimits
+	typeContexts           map[string]func(context.Context) context.Context
 }
 
 type executorOption func(*executorConfig)
@@ -81,6 +94,11 @@ type CompilerBuilderFunc func(ctx context.Context, query string, ts CompilerBuil
 type CompilerBuilderTimestamps struct {
 	Now           time.Time
 	LatestSuccess time.Time
+
+	// TriggerStart and TriggerStop bound the written time range of runs
+	// started by writes to the trigger bucket of a task, stop is exclusive.
+	TriggerStart time.Time
+	TriggerStop  time.Time
 }
 
 func (ts CompilerBuilderTimestamps) Extern() *ast.File {
@@ -97,6 +115,20 @@ func (ts CompilerBuilderTimestamps) Extern() *ast.File {
 		})
 	}
 
+	if !ts.TriggerStop.IsZero() {
+		body = append(body, &ast.OptionStatement{
+			Assignment: &ast.VariableAssignment{
+				ID: &ast.Identifier{Name: triggerOption},
+				Init: &ast.ObjectExpression{
+					Properties: []*ast.Property{
+						{Key: &ast.Identifier{Name: "start"}, Value: &ast.DateTimeLiteral{Value: ts.TriggerStart}},
+						{Key: &ast.Identifier{Name: "stop"}, Value: &ast.DateTimeLiteral{Value: ts.TriggerStop}},
+					},
+				},
+			},
+		})
+	}
+
 	return &ast.File{Body: body}
 }
 
@@ -124,12 +156,62 @@ func WithFlagger(flagger feature.Flagger) executorOption {
 	}
 }
 
+// WithFluxLanguageService is an Executor option that configures the language
+// service used to read the retry option of task scripts. Failed runs are not
+// retried without it.
+func WithFluxLanguageService(lang influxdb.FluxLanguageService) executorOption {
+	return func(o *executorConfig) {
+		o.lang = lang
+	}
+}
+
+// WithRetryBackoff is an Executor option that configures the delay before
+// retrying a failed run. The delay doubles with every attempt, up to max.
+func WithRetryBackoff(base, max time.Duration) executorOption {
+	return func(o *executorConfig) {
+		o.retryBackoff = base
+		o.maxRetryBackoff = max
+	}
+}
+
+// WithRunTimeout is an Executor option that configures how long runs of tasks
+// without the timeout option may take before they are canceled. Zero means
+// they are never canceled.
+func WithRunTimeout(d time.Duration) executorOption {
+	return func(o *executorConfig) {
+		o.runTimeout = d
+	}
+}
+
+// WithOrgLimits is an Executor option that configures the weight, concurrency
+// and queue depth of the runs of organizations, overridden for some of them.
+func WithOrgLimits(limits OrgLimits, overrides map[influxdb.ID]OrgLimits) executorOption {
+	return func(o *executorConfig) {
+		o.orgLimits = limits
+		o.orgLimitOverrides = overrides
+	}
+}
+
+// WithTaskTypeContext is an Executor option that decorates the context of the
+// queries run by tasks of the given type, like granting them access the
+// queries of other tasks do not have.
+func WithTaskTypeContext(taskType string, decorate func(context.Context) context.Context) executorOption {
+	return func(o *executorConfig) {
+		if o.typeContexts == nil {
+			o.typeContexts = make(map[string]func(context.Context) context.Context)
+		}
+		o.typeContexts[taskType] = decorate
+	}
+}
+
 // NewExecutor creates a new task executor
 func NewExecutor(log *zap.Logger, qs query.QueryService, us PermissionService, ts influxdb.TaskService, tcs backend.TaskControlService, opts ...executorOption) (*Executor, *ExecutorMetrics) {
 	cfg := &executorConfig{
 		maxWorkers:             defaultMaxWorkers,
 		systemBuildCompiler:    NewASTCompiler,
 		nonSystemBuildCompiler: NewASTCompiler,
+		retryBackoff:           DefaultRetryBackoff,
+		maxRetryBackoff:        DefaultMaxRetryBackoff,
 	}
 	for _, opt := range opts {
 		opt(cfg)
@@ -143,12 +225,19 @@ func NewExecutor(log *zap.Logger, qs query.QueryService, us PermissionService, t
 		ps:  us,
 
 		currentPromises:        sync.Map{},
-		promiseQueue:           make(chan *promise, maxPromises),
+		promiseQueue:           newFairQueue(maxPromises, cfg.orgLimits, cfg.orgLimitOverrides),
 		workerLimit:            make(chan struct{}, cfg.maxWorkers),
 		limitFunc:              func(*influxdb.Task, *influxdb.Run) error { return nil }, // noop
 		systemBuildCompiler:    cfg.systemBuildCompiler,
 		nonSystemBuildCompiler: cfg.nonSystemBuildCompiler,
 		flagger:                cfg.flagger,
+		lang:                   cfg.lang,
+		retryBackoff:           cfg.retryBackoff,
+		maxRetryBackoff:        cfg.maxRetryBackoff,
+		retryTimers:            make(map[influxdb.ID]*time.Timer),
+		runTimeout:             cfg.runTimeout,
+		deps:                   newDependencies(),
+		typeContexts:           cfg.typeContexts,
 	}
 
 	e.metrics = NewExecutorMetrics(e)
@@ -175,8 +264,8 @@ type Executor struct {
 	// currentPromises are all the promises we are made that have not been fulfilled
 	currentPromises sync.Map
 
-	// keep a pool of promise's we have in queue
-	promiseQueue chan *promise
+	// keep a pool of promise's we have in queue, by organization
+	promiseQueue *fairQueue
 
 	limitFunc LimitFunc
 
@@ -187,6 +276,27 @@ type Executor struct {
 	nonSystemBuildCompiler CompilerBuilderFunc
 	systemBuildCompiler    CompilerBuilderFunc
 	flagger                feature.Flagger
+
+	// lang reads the retry option of task scripts, failed runs are retried
+	// after a backoff starting at retryBackoff and capped at maxRetryBackoff.
+	lang            influxdb.FluxLanguageService
+	retryBackoff    time.Duration
+	maxRetryBackoff time.Duration
+
+	// retryTimers start the pending retries by run ID, they are stopped when
+	// the executor is closed.
+	retryMu     sync.Mutex
+	retryTimers map[influxdb.ID]*time.Timer
+	closed      bool
+
+	// runTimeout is the timeout of runs of tasks without the timeout option.
+	runTimeout time.Duration
+
+	// deps holds the runs waiting for their upstream tasks.
+	deps *dependencies
+
+	// typeContexts decorate the context of the queries of tasks by task type.
+	typeContexts map[string]func(context.Context) context.Context
 }
 
 // SetLimitFunc sets the limit func for this task executor
@@ -194,10 +304,15 @@ func (e *Executor) SetLimitFunc(l LimitFunc) {
 	e.limitFunc = l
 }
 
-// Execute is a executor to satisfy the needs of tasks
+// Execute is a executor to satisfy the needs of tasks.
+// Runs of tasks with upstream tasks start once the upstream tasks are done with the same window.
 func (e *Executor) Execute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) error {
-	_, err := e.PromisedExecute(ctx, id, scheduledFor, runAt)
-	return err
+	if _, err := e.createRun(ctx, influxdb.ID(id), scheduledFor, runAt, nil, true); err != nil {
+		return err
+	}
+
+	e.startWorker()
+	return nil
 }
 
 // PromisedExecute begins execution for the tasks id with a specific scheduledFor time.
@@ -208,7 +323,7 @@ func (e *Executor) Execute(ctx context.Context, id scheduler.ID, scheduledFor ti
 func (e *Executor) PromisedExecute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) (Promise, error) {
 	iid := influxdb.ID(id)
 	// create a run
-	p, err := e.createRun(ctx, iid, scheduledFor, runAt)
+	p, err := e.createRun(ctx, iid, scheduledFor, runAt, nil, false)
 	if err != nil {
 		return nil, err
 	}
@@ -223,13 +338,17 @@ func (e *Executor) ManualRun(ctx context.Context, id influxdb.ID, runID influxdb
 	if err != nil {
 		return nil, err
 	}
-	p, err := e.createPromise(ctx, r)
+	p, err := e.createPromise(ctx, r, nil, false)
 
 	e.startWorker()
 	e.metrics.manualRunsCounter.WithLabelValues(id.String()).Inc()
 	return p, err
 }
 
+// ResumeCurrentRun resumes a run that did not finish. A pending retry is
+// started once the rest of its backoff has passed, and a run of a task with
+// upstream tasks that did not start is gated again, no promise is returned
+// for them.
 func (e *Executor) ResumeCurrentRun(ctx context.Context, id influxdb.ID, runID influxdb.ID) (Promise, error) {
 	cr, err := e.tcs.CurrentlyRunning(ctx, id)
 	if err != nil {
@@ -241,8 +360,12 @@ func (e *Executor) ResumeCurrentRun(ctx context.Context, id influxdb.ID, runID i
 			if _, ok := e.currentPromises.Load(run.ID); ok {
 				continue
 			}
-
-			p, err := e.createPromise(ctx, run)
+			if pendingRetry(run) {
+				e.retryAfter(run, time.Until(run.RunAt), nil)
+				return nil, nil
+			}
+			// runs that did not start are gated again
+			p, err := e.createPromise(ctx, run, nil, run.Status == influxdb.RunScheduled.String())
 
 			e.startWorker()
 			e.metrics.resumeRunsCounter.WithLabelValues(id.String()).Inc()
@@ -252,12 +375,17 @@ func (e *Executor) ResumeCurrentRun(ctx context.Context, id influxdb.ID, runID i
 	return nil, influxdb.ErrRunNotFound
 }
 
-func (e *Executor) createRun(ctx context.Context, id influxdb.ID, scheduledFor time.Time, runAt time.Time) (*promise, error) {
+// createRun creates the run of the task and enqueues it, see createPromise
+// for gate.
+func (e *Executor) createRun(ctx context.Context, id influxdb.ID, scheduledFor time.Time, runAt time.Time, trigger *triggerRange, gate bool) (*promise, error) {
 	r, err := e.tcs.CreateRun(ctx, id, scheduledFor.UTC(), runAt.UTC())
 	if err != nil {
 		return nil, err
 	}
-	p, err := e.createPromise(ctx, r)
+	if trigger != nil {
+		e.tcs.AddRunLog(ctx, id, r.ID, time.Now().UTC(), fmt.Sprintf("Triggered by writes from %s to %s", trigger.start.Format(time.RFC3339Nano), trigger.stop.Format(time.RFC3339Nano)))
+	}
+	p, err := e.createPromise(ctx, r, trigger, gate)
 	if err != nil {
 		if err := e.tcs.AddRunLog(ctx, id, r.ID, time.Now().UTC(), fmt.Sprintf("Failed to enqueue run: %s", err.Error())); err != nil {
 			e.log.Error("failed to fail create run: AddRunLog:", zap.Error(err))
@@ -311,7 +439,9 @@ func (e *Executor) Cancel(ctx context.Context, runID influxdb.ID) error {
 	return nil
 }
 
-func (e *Executor) createPromise(ctx context.Context, run *influxdb.Run) (*promise, error) {
+// createPromise enqueues the run. With gate, the run of a task with upstream
+// tasks waits for them instead and no promise is returned.
+func (e *Executor) createPromise(ctx context.Context, run *influxdb.Run, trigger *triggerRange, gate bool) (*promise, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -319,6 +449,13 @@ func (e *Executor) createPromise(ctx context.Context, run *influxdb.Run) (*promi
 	if err != nil {
 		return nil, err
 	}
+	if gate && len(t.DependsOn) > 0 {
+		// the run keeps waiting when the upstream tasks cannot be checked
+		if err := e.waitUpstream(ctx, t, run); err != nil {
+			e.log.Error("Failed to check upstream tasks", zap.String("taskID", t.ID.String()), zap.String("runID", run.ID.String()), zap.Error(err))
+		}
+		return nil, nil
+	}
 
 	perm, err := e.ps.FindPermissionForUser(ctx, t.OwnerID)
 	if err != nil {
@@ -328,8 +465,9 @@ func (e *Executor) createPromise(ctx context.Context, run *influxdb.Run) (*promi
 	ctx, cancel := context.WithCancel(ctx)
 	// create promise
 	p := &promise{
-		run:  run,
-		task: t,
+		run:     run,
+		task:    t,
+		trigger: trigger,
 		auth: &influxdb.Authorization{
 			Status:      influxdb.Active,
 			UserID:      t.OwnerID,
@@ -345,7 +483,11 @@ func (e *Executor) createPromise(ctx context.Context, run *influxdb.Run) (*promi
 
 	// insert promise into queue to be worked
 	// when the queue gets full we will hand and apply back pressure to the scheduler
-	e.promiseQueue <- p
+	if err := e.promiseQueue.push(p); err != nil {
+		cancel()
+		e.metrics.LogOrgQueueFull(t.OrganizationID)
+		return nil, err
+	}
 
 	// insert the promise into the registry
 	e.currentPromises.Store(run.ID, p)
@@ -379,20 +521,13 @@ type worker struct {
 func (w *worker) work() {
 	// loop until we have no more work to do in the promise queue
 	for {
-		var prom *promise
 		// check to see if we can execute
-		select {
-		case p, ok := <-w.e.promiseQueue:
-
-			if !ok {
-				// the promiseQueue has been closed
-				return
-			}
-			prom = p
-		default:
-			// if nothing is left in the queue we are done
+		prom := w.e.promiseQueue.pop()
+		if prom == nil {
+			// if nothing is left in the queue we can take we are done
 			return
 		}
+		w.e.metrics.LogOrgQueueDelay(prom.task.OrganizationID, time.Since(prom.createdAt))
 
 		// check to make sure we are below the limits.
 		for {
@@ -412,6 +547,7 @@ func (w *worker) work() {
 				w.e.tcs.UpdateRunState(prom.ctx, prom.task.ID, prom.run.ID, time.Now().UTC(), influxdb.RunCanceled)
 				prom.err = influxdb.ErrRunCanceled
 				close(prom.done)
+				w.e.promiseQueue.done(prom)
 				return
 			case <-time.After(time.Second):
 			}
@@ -422,6 +558,7 @@ func (w *worker) work() {
 
 		// close promise done channel and set appropriate error
 		close(prom.done)
+		w.e.promiseQueue.done(prom)
 
 		// remove promise from registry
 		w.e.currentPromises.Delete(prom.run.ID)
@@ -479,9 +616,22 @@ func (w *worker) finish(p *promise, rs influxdb.RunStatus, err error) {
 		w.e.log.Debug("Completed successfully", zap.String("taskID", p.task.ID.String()))
 	}
 
+	var retry bool
+	if (rs == influxdb.RunFail || rs == influxdb.RunTimeout) && p.ctx.Err() == nil && !backend.IsUnrecoverable(err) {
+		retry = w.e.shouldRetry(p)
+	}
+
+	// the retry is created first, so that the window of the run is never
+	// seen failed while it is retried.
+	if retry {
+		w.e.scheduleRetry(p.task, p.run, p.trigger)
+	}
+
 	if _, err := w.e.tcs.FinishRun(p.ctx, p.task.ID, p.run.ID); err != nil {
 		w.e.log.Error("Failed to finish run", zap.String("taskID", p.task.ID.String()), zap.String("runID", p.run.ID.String()), zap.Error(err))
 	}
+
+	go w.e.releaseDependents(context.Background(), p.task.ID)
 }
 
 func (w *worker) executeQuery(p *promise) {
@@ -492,15 +642,32 @@ func (w *worker) executeQuery(p *promise) {
 	w.start(p)
 
 	ctx = icontext.SetAuthorizer(ctx, p.auth)
+	// the writes of the run are known not to trigger the task itself
+	ctx = icontext.SetTaskID(ctx, p.task.ID)
+	if decorate, ok := w.e.typeContexts[p.task.Type]; ok {
+		ctx = decorate(ctx)
+	}
+
+	// the query is canceled through its context once the run times out
+	timeout := w.e.timeout(p.task)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
 	buildCompiler := w.systemBuildCompiler
 	if p.task.Type != influxdb.TaskSystemType {
 		buildCompiler = w.nonSystemBuildCompiler
 	}
-	compiler, err := buildCompiler(ctx, p.task.Flux, CompilerBuilderTimestamps{
+	ts := CompilerBuilderTimestamps{
 		Now:           p.run.ScheduledFor,
 		LatestSuccess: p.task.LatestSuccess,
-	})
+	}
+	if tr := p.triggerRange(); tr != nil {
+		ts.TriggerStart, ts.TriggerStop = tr.start, tr.stop
+	}
+	compiler, err := buildCompiler(ctx, p.task.Flux, ts)
 	if err != nil {
 		w.finish(p, influxdb.RunFail, influxdb.ErrFluxParseError(err))
 		return
@@ -515,7 +682,7 @@ func (w *worker) executeQuery(p *promise) {
 	it, err := w.e.qs.Query(ctx, req)
 	if err != nil {
 		// Assume the error should not be part of the runResult.
-		w.finish(p, influxdb.RunFail, influxdb.ErrQueryError(err))
+		w.fail(ctx, p, timeout, influxdb.ErrQueryError(err))
 		return
 	}
 
@@ -538,18 +705,37 @@ func (w *worker) executeQuery(p *promise) {
 	}
 
 	if runErr != nil {
-		w.finish(p, influxdb.RunFail, influxdb.ErrRunExecutionError(runErr))
+		w.fail(ctx, p, timeout, influxdb.ErrRunExecutionError(runErr))
 		return
 	}
 
 	if it.Err() != nil {
-		w.finish(p, influxdb.RunFail, influxdb.ErrResultIteratorError(it.Err()))
+		w.fail(ctx, p, timeout, influxdb.ErrResultIteratorError(it.Err()))
 		return
 	}
 
 	w.finish(p, influxdb.RunSuccess, nil)
 }
 
+// fail finishes the failed run of the promise, as timed out when its query
+// was canceled for running longer than the timeout.
+func (w *worker) fail(ctx context.Context, p *promise, timeout time.Duration, err error) {
+	if ctx.Err() == context.DeadlineExceeded && p.ctx.Err() == nil {
+		w.e.metrics.LogTimeout(p.task)
+		w.finish(p, influxdb.RunTimeout, influxdb.ErrRunTimedOut(timeout))
+		return
+	}
+	w.finish(p, influxdb.RunFail, err)
+}
+
+// timeout returns how long a run of the task may take, zero for no limit.
+func (e *Executor) timeout(t *influxdb.Task) time.Duration {
+	if t.Timeout > 0 {
+		return t.Timeout
+	}
+	return e.runTimeout
+}
+
 // RunsActive returns the current number of workers, which is equivalent to
 // the number of runs actively running
 func (e *Executor) RunsActive() int {
@@ -563,7 +749,7 @@ func (e *Executor) WorkersBusy() float64 {
 
 // PromiseQueueUsage returns the percent of the Promise Queue that is currently filled
 func (e *Executor) PromiseQueueUsage() float64 {
-	return float64(len(e.promiseQueue)) / float64(cap(e.promiseQueue))
+	return float64(e.promiseQueue.len()) / float64(maxPromises)
 }
 
 // promise represents a promise the executor makes to finish a run's execution asynchronously.
@@ -572,6 +758,9 @@ type promise struct {
 	task *influxdb.Task
 	auth *influxdb.Authorization
 
+	// trigger is the written time range of a run started by writes, nil otherwise.
+	trigger *triggerRange
+
 	done chan struct{}
 	err  error
 
@@ -620,22 +809,28 @@ func exhaustResultIterators(res flux.Result) error {
 	})
 }
 
+// externJSON returns the options injected into the query of a run, if any.
+// The latest success time is only injected when its feature flag is on.
+func externJSON(ctx context.Context, ts CompilerBuilderTimestamps) ([]byte, error) {
+	if !feature.InjectLatestSuccessTime().Enabled(ctx) {
+		ts.LatestSuccess = time.Time{}
+	}
+	extern := ts.Extern()
+	if len(extern.Body) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(extern)
+}
+
 // NewASTCompiler parses a Flux query string into an AST representation.
 func NewASTCompiler(ctx context.Context, query string, ts CompilerBuilderTimestamps) (flux.Compiler, error) {
 	pkg, err := runtime.ParseToJSON(query)
 	if err != nil {
 		return nil, err
 	}
-	var externBytes []byte
-	if feature.InjectLatestSuccessTime().Enabled(ctx) {
-		extern := ts.Extern()
-		if len(extern.Body) > 0 {
-			var err error
-			externBytes, err = json.Marshal(extern)
-			if err != nil {
-				return nil, err
-			}
-		}
+	externBytes, err := externJSON(ctx, ts)
+	if err != nil {
+		return nil, err
 	}
 	return lang.ASTCompiler{
 		AST:    pkg,
@@ -646,16 +841,9 @@ func NewASTCompiler(ctx context.Context, query string, ts CompilerBuilderTimesta
 
 // NewFluxCompiler wraps a Flux query string in a raw-query representation.
 func NewFluxCompiler(ctx context.Context, query string, ts CompilerBuilderTimestamps) (flux.Compiler, error) {
-	var externBytes []byte
-	if feature.InjectLatestSuccessTime().Enabled(ctx) {
-		extern := ts.Extern()
-		if len(extern.Body) > 0 {
-			var err error
-			externBytes, err = json.Marshal(extern)
-			if err != nil {
-				return nil, err
-			}
-		}
+	externBytes, err := externJSON(ctx, ts)
+	if err != nil {
+		return nil, err
 	}
 	return lang.FluxCompiler{
 		Query:  query,
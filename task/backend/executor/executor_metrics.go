@@ -15,8 +15,15 @@ type ExecutorMetrics struct {
 	errorsCounter        *prometheus.CounterVec
 	manualRunsCounter    *prometheus.CounterVec
 	resumeRunsCounter    *prometheus.CounterVec
+	triggeredRunsCounter *prometheus.CounterVec
 	unrecoverableCounter *prometheus.CounterVec
+	retriesCounter       *prometheus.CounterVec
+	retriesExhausted     *prometheus.CounterVec
+	upstreamFailures     *prometheus.CounterVec
+	timeoutsCounter      *prometheus.CounterVec
 	runLatency           *prometheus.HistogramVec
+	orgQueueDelay        *prometheus.HistogramVec
+	orgQueueFull         *prometheus.CounterVec
 }
 
 type runCollector struct {
@@ -84,12 +91,61 @@ func NewExecutorMetrics(ex *Executor) *ExecutorMetrics {
 			Help:      "Total number of runs resumed by task ID",
 		}, []string{"taskID"}),
 
+		triggeredRunsCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "triggered_runs_counter",
+			Help:      "Total number of runs started by writes to the trigger bucket by task ID",
+		}, []string{"taskID"}),
+
+		retriesCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "retries_counter",
+			Help:      "Total number of retries of failed runs, by task type",
+		}, []string{"task_type"}),
+
+		retriesExhausted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "retries_exhausted_counter",
+			Help:      "The number of failed runs that exhausted their retries, by task ID",
+		}, []string{"taskID"}),
+
+		upstreamFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "upstream_failures_counter",
+			Help:      "The number of runs failed because an upstream task failed the same window, by task ID",
+		}, []string{"taskID"}),
+
+		timeoutsCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "timeouts_counter",
+			Help:      "The number of runs canceled for running longer than their timeout, by task ID",
+		}, []string{"taskID"}),
+
 		runLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: namespace,
 			Subsystem: subsystem,
 			Name:      "run_latency_seconds",
 			Help:      "Records the latency between the time the run was due to run and the time the task started execution, by task type",
 		}, []string{"task_type"}),
+
+		orgQueueDelay: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "org_queue_delay_seconds",
+			Help:      "The time runs waited in the queue of their organization for a worker, by organization ID",
+		}, []string{"orgID"}),
+
+		orgQueueFull: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "org_queue_full_counter",
+			Help:      "The number of runs that could not be queued because the queue of their organization was full, by organization ID",
+		}, []string{"orgID"}),
 	}
 }
 
@@ -128,8 +184,15 @@ func (em *ExecutorMetrics) PrometheusCollectors() []prometheus.Collector {
 		em.runDuration,
 		em.manualRunsCounter,
 		em.resumeRunsCounter,
+		em.triggeredRunsCounter,
 		em.unrecoverableCounter,
+		em.retriesCounter,
+		em.retriesExhausted,
+		em.upstreamFailures,
+		em.timeoutsCounter,
 		em.runLatency,
+		em.orgQueueDelay,
+		em.orgQueueFull,
 	}
 }
 
@@ -172,6 +235,36 @@ func (em *ExecutorMetrics) LogUnrecoverableError(taskID influxdb.ID, err error)
 	}
 }
 
+// LogRetry increments the count of retried runs.
+func (em *ExecutorMetrics) LogRetry(task *influxdb.Task) {
+	em.retriesCounter.WithLabelValues(task.Type).Inc()
+}
+
+// LogRetriesExhausted increments the count of failed runs of the task that have no retries left.
+func (em *ExecutorMetrics) LogRetriesExhausted(task *influxdb.Task) {
+	em.retriesExhausted.WithLabelValues(task.ID.String()).Inc()
+}
+
+// LogUpstreamFailure increments the count of runs of the task failed by an upstream task.
+func (em *ExecutorMetrics) LogUpstreamFailure(task *influxdb.Task) {
+	em.upstreamFailures.WithLabelValues(task.ID.String()).Inc()
+}
+
+// LogTimeout increments the count of runs of the task that timed out.
+func (em *ExecutorMetrics) LogTimeout(task *influxdb.Task) {
+	em.timeoutsCounter.WithLabelValues(task.ID.String()).Inc()
+}
+
+// LogOrgQueueDelay records the time a run of the organization waited for a worker.
+func (em *ExecutorMetrics) LogOrgQueueDelay(orgID influxdb.ID, delay time.Duration) {
+	em.orgQueueDelay.WithLabelValues(orgID.String()).Observe(delay.Seconds())
+}
+
+// LogOrgQueueFull increments the count of runs of the organization that could not be queued.
+func (em *ExecutorMetrics) LogOrgQueueFull(orgID influxdb.ID) {
+	em.orgQueueFull.WithLabelValues(orgID.String()).Inc()
+}
+
 // Describe returns all descriptions associated with the run collector.
 func (r *runCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- r.workersBusy
@@ -53,7 +53,7 @@ type tes struct {
 	tc      testCreds
 }
 
-func taskExecutorSystem(t *testing.T) tes {
+func taskExecutorSystem(t *testing.T, opts ...executorOption) tes {
 	var (
 		aqs = newFakeQueryService()
 		qs  = query.QueryServiceBridge{
@@ -84,7 +84,7 @@ func taskExecutorSystem(t *testing.T) tes {
 		})
 
 		tcs         = &taskControlService{TaskControlService: svc}
-		ex, metrics = NewExecutor(zaptest.NewLogger(t), qs, ps, svc, tcs)
+		ex, metrics = NewExecutor(zaptest.NewLogger(t), qs, ps, svc, tcs, opts...)
 	)
 	return tes{
 		svc:     aqs,
@@ -106,6 +106,11 @@ func TestTaskExecutor(t *testing.T) {
 	t.Run("Metrics", testMetrics)
 	t.Run("IteratorFailure", testIteratorFailure)
 	t.Run("ErrorHandling", testErrorHandling)
+	t.Run("Retry", testRetry)
+	t.Run("ResumeRetry", testResumingRetry)
+	t.Run("Timeout", testTimeout)
+	t.Run("Dependencies", testDependencies)
+	t.Run("TaskTypeContext", testTaskTypeContext)
 }
 
 func testQuerySuccess(t *testing.T) {
@@ -212,6 +217,250 @@ func testQueryFailure(t *testing.T) {
 	}
 }
 
+func testRetry(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t, WithFluxLanguageService(fluxlang.DefaultService), WithRetryBackoff(10*time.Millisecond, time.Second))
+
+	metrics := tes.metrics
+	reg := prom.NewRegistry(zaptest.NewLogger(t))
+	reg.MustRegister(metrics.PrometheusCollectors()...)
+
+	script := fmt.Sprintf(fmtTestScriptWithRetry, t.Name())
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script, Status: "active"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	originalID := promise.ID()
+
+	tes.svc.WaitForQueryLive(t, script)
+	tes.svc.FailQuery(script, errors.New("out of memory"))
+	<-promise.Done()
+
+	// the failed run is retried for the same scheduled time
+	var retry *influxdb.Run
+	for i := 0; i < 100 && retry == nil; i++ {
+		runs, err := tes.i.CurrentlyRunning(context.Background(), task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range runs {
+			if r.RetryOf == originalID {
+				retry = r
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if retry == nil {
+		t.Fatal("failed run was not retried")
+	}
+	if retry.Attempt != 2 {
+		t.Fatalf("expected attempt 2, got %d", retry.Attempt)
+	}
+	if !retry.ScheduledFor.Equal(time.Unix(123, 0).UTC()) {
+		t.Fatalf("expected retry scheduled for %s, got %s", time.Unix(123, 0).UTC(), retry.ScheduledFor)
+	}
+
+	// the retry fails again and exhausts the retries of the task
+	tes.svc.WaitForQueryLive(t, script)
+	tes.svc.FailQuery(script, errors.New("out of memory"))
+
+	var exhausted float64
+	for i := 0; i < 100 && exhausted == 0; i++ {
+		mg := promtest.MustGather(t, reg)
+		if m := promtest.FindMetric(mg, "task_executor_retries_exhausted_counter", map[string]string{"taskID": task.ID.String()}); m != nil {
+			exhausted = *m.Counter.Value
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if exhausted != 1 {
+		t.Fatalf("expected retries to be exhausted once, got %v", exhausted)
+	}
+
+	mg := promtest.MustGather(t, reg)
+	m := promtest.MustFindMetric(t, mg, "task_executor_retries_counter", map[string]string{"task_type": ""})
+	if got := *m.Counter.Value; got != 1 {
+		t.Fatalf("expected 1 retry, got %v", got)
+	}
+}
+
+func testResumingRetry(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+
+	script := fmt.Sprintf(fmtTestScript, t.Name())
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script, Status: "active"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a retry left pending by a previous executor.
+	failed, err := tes.i.CreateRun(ctx, task.ID, time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	retry, err := tes.i.CreateRetryRun(ctx, task.ID, failed.ID, 2, failed.ScheduledFor, time.Now().Add(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	promise, err := tes.ex.ResumeCurrentRun(ctx, task.ID, retry.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if promise != nil {
+		t.Fatal("pending retry should not be started before its backoff passed")
+	}
+
+	tes.svc.WaitForQueryLive(t, script)
+	tes.svc.SucceedQuery(script)
+
+	// the timers of pending retries are stopped when the executor is closed,
+	// the retry stays in the store.
+	closing, err := tes.i.CreateRetryRun(ctx, task.ID, failed.ID, 3, failed.ScheduledFor, time.Now().Add(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tes.ex.ResumeCurrentRun(ctx, task.ID, closing.ID); err != nil {
+		t.Fatal(err)
+	}
+	tes.ex.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	if _, err := tes.i.FindRunByID(ctx, task.ID, closing.ID); err != nil {
+		t.Fatalf("pending retry should stay in the store: %v", err)
+	}
+	if n := tes.ex.RunsActive(); n != 0 {
+		t.Fatalf("expected no active runs, got %d", n)
+	}
+}
+
+func testTimeout(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t, WithRunTimeout(50*time.Millisecond))
+
+	metrics := tes.metrics
+	reg := prom.NewRegistry(zaptest.NewLogger(t))
+	reg.MustRegister(metrics.PrometheusCollectors()...)
+
+	script := fmt.Sprintf(fmtTestScript, t.Name())
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the query is never finished, it is canceled once the run times out
+	tes.svc.WaitForQueryLive(t, script)
+	<-promise.Done()
+
+	if got := promise.Error(); got == nil || got.Error() != influxdb.ErrRunTimedOut(50*time.Millisecond).Error() {
+		t.Fatalf("expected run to time out, got %v", got)
+	}
+
+	task, err = tes.i.FindTaskByID(context.Background(), task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.LastRunStatus != influxdb.RunTimeout.String() {
+		t.Fatalf("expected last run status %q, got %q", influxdb.RunTimeout, task.LastRunStatus)
+	}
+	if !task.LatestFailure.Equal(time.Unix(123, 0).UTC()) {
+		t.Fatalf("expected timed out run to be the latest failure, got %s", task.LatestFailure)
+	}
+
+	mg := promtest.MustGather(t, reg)
+	m := promtest.MustFindMetric(t, mg, "task_executor_timeouts_counter", map[string]string{"taskID": task.ID.String()})
+	if got := *m.Counter.Value; got != 1 {
+		t.Fatalf("expected 1 timeout, got %v", got)
+	}
+}
+
+func testDependencies(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+
+	var (
+		upScript   = fmt.Sprintf(fmtTestScript, t.Name()+"-up")
+		downScript = fmt.Sprintf(fmtTestScript, t.Name()+"-down")
+		ctx        = icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	)
+	up, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: upScript, Status: "active"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	down, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: downScript, Status: "active", DependsOn: []influxdb.ID{up.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the downstream run waits for the upstream run of the same window
+	if err := tes.ex.Execute(ctx, scheduler.ID(down.ID), time.Unix(120, 0), time.Unix(126, 0)); err != nil {
+		t.Fatal(err)
+	}
+	runs, err := tes.i.CurrentlyRunning(context.Background(), down.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].Status != influxdb.RunScheduled.String() {
+		t.Fatalf("expected downstream run to wait, got %v", runs)
+	}
+
+	// the waiting run is kept in the store and gated again when resumed.
+	if p, err := tes.ex.ResumeCurrentRun(ctx, down.ID, runs[0].ID); err != nil || p != nil {
+		t.Fatalf("expected the resumed run to wait, got %v, %v", p, err)
+	}
+
+	if err := tes.ex.Execute(ctx, scheduler.ID(up.ID), time.Unix(120, 0), time.Unix(126, 0)); err != nil {
+		t.Fatal(err)
+	}
+	tes.svc.WaitForQueryLive(t, upScript)
+	tes.svc.SucceedQuery(upScript)
+
+	tes.svc.WaitForQueryLive(t, downScript)
+	tes.svc.SucceedQuery(downScript)
+
+	// a failed upstream run fails the downstream run of the window
+	if err := tes.ex.Execute(ctx, scheduler.ID(down.ID), time.Unix(180, 0), time.Unix(186, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tes.ex.Execute(ctx, scheduler.ID(up.ID), time.Unix(180, 0), time.Unix(186, 0)); err != nil {
+		t.Fatal(err)
+	}
+	tes.svc.WaitForQueryLive(t, upScript)
+	tes.svc.FailQuery(upScript, errors.New("out of memory"))
+
+	var failed *influxdb.Task
+	for i := 0; i < 100; i++ {
+		task, err := tes.i.FindTaskByID(context.Background(), down.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task.LatestFailure.Equal(time.Unix(180, 0)) {
+			failed = task
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if failed == nil {
+		t.Fatal("downstream run did not fail with its upstream run")
+	}
+	if !failed.LatestSuccess.Equal(time.Unix(120, 0)) {
+		t.Fatalf("expected latest success at %s, got %s", time.Unix(120, 0).UTC(), failed.LatestSuccess)
+	}
+}
+
 func testManualRun(t *testing.T) {
 	t.Parallel()
 	tes := taskExecutorSystem(t)
@@ -623,3 +872,32 @@ func (t *taskControlService) FinishRun(ctx context.Context, taskID influxdb.ID,
 	t.run, err = t.TaskControlService.FinishRun(ctx, taskID, runID)
 	return t.run, err
 }
+
+type testContextKey struct{}
+
+func testTaskTypeContext(t *testing.T) {
+	t.Parallel()
+
+	tes := taskExecutorSystem(t, WithTaskTypeContext("decorated", func(ctx context.Context) context.Context {
+		return context.WithValue(ctx, testContextKey{}, true)
+	}))
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+
+	for _, typ := range []string{"decorated", influxdb.TaskSystemType} {
+		script := fmt.Sprintf(fmtTestScript, t.Name()+typ)
+		task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script, Type: typ})
+		require.NoError(t, err)
+
+		promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+		require.NoError(t, err)
+
+		tes.svc.WaitForQueryLive(t, script)
+		tes.svc.mu.Lock()
+		decorated, _ := tes.svc.mostRecentCtx.Value(testContextKey{}).(bool)
+		tes.svc.mu.Unlock()
+		tes.svc.SucceedQuery(script)
+		<-promise.Done()
+
+		require.Equal(t, typ == "decorated", decorated, "context of %s task", typ)
+	}
+}
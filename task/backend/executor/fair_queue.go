@@ -0,0 +1,220 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+// Promises wait for a worker in a queue per organization, so the runs of an
+// organization with many tasks do not hold up the runs of the others. Workers
+// take promises from the queues by weighted fair queuing: every promise is
+// stamped with a virtual finish time, which grows by the inverse of the weight
+// of its organization with every promise the organization queues, and the
+// promise with the earliest finish time goes first. Organizations get workers
+// in proportion to their weights while they have runs waiting, and one that
+// queues after being idle starts from the current virtual time rather than
+// being credited for the time it was idle.
+
+// OrgLimits limit the runs of an organization in the executor.
+type OrgLimits struct {
+	// Weight is the share of the workers the organization gets relative to
+	// the other organizations with runs waiting. Zero is a weight of one.
+	Weight int
+	// MaxConcurrency is the most runs of the organization executing at once, zero is unlimited.
+	MaxConcurrency int
+	// QueueDepth is the most runs of the organization waiting for a worker, zero is unlimited.
+	QueueDepth int
+}
+
+func (l OrgLimits) weight() float64 {
+	if l.Weight <= 0 {
+		return 1
+	}
+	return float64(l.Weight)
+}
+
+// fairQueue holds the promises waiting for a worker, by organization.
+type fairQueue struct {
+	mu      sync.Mutex
+	notFull *sync.Cond
+
+	// size is the number of queued promises, pushing blocks at capacity.
+	size     int
+	capacity int
+
+	// vtime is the latest virtual finish time of the promises taken.
+	vtime float64
+	orgs  map[influxdb.ID]*orgQueue
+
+	limits    OrgLimits
+	orgLimits map[influxdb.ID]OrgLimits
+}
+
+// orgQueue holds the queued promises of an organization.
+type orgQueue struct {
+	limits   OrgLimits
+	promises []queuedPromise
+	running  int
+	// finish is the virtual finish time of the promise queued last.
+	finish float64
+}
+
+type queuedPromise struct {
+	p      *promise
+	finish float64
+}
+
+func newFairQueue(capacity int, limits OrgLimits, orgLimits map[influxdb.ID]OrgLimits) *fairQueue {
+	q := &fairQueue{
+		capacity:  capacity,
+		orgs:      make(map[influxdb.ID]*orgQueue),
+		limits:    limits,
+		orgLimits: orgLimits,
+	}
+	q.notFull = sync.NewCond(&q.mu)
+	return q
+}
+
+// push queues the promise of an organization. It blocks while the queue is
+// full, to apply back pressure to the scheduler, and fails when the queue of
+// the organization is full.
+func (q *fairQueue) push(p *promise) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	orgID := p.task.OrganizationID
+	for {
+		o := q.org(orgID)
+		if o.limits.QueueDepth > 0 && len(o.promises) >= o.limits.QueueDepth {
+			q.removeIdle(orgID, o)
+			return influxdb.ErrTaskOrgQueueFull(o.limits.QueueDepth)
+		}
+		if q.size < q.capacity {
+			break
+		}
+		q.notFull.Wait()
+	}
+
+	o := q.org(orgID)
+	start := o.finish
+	if q.vtime > start {
+		start = q.vtime
+	}
+	o.finish = start + 1/o.limits.weight()
+	o.promises = append(o.promises, queuedPromise{p: p, finish: o.finish})
+	q.size++
+	return nil
+}
+
+// pop takes the promise with the earliest virtual finish time of the
+// organizations below their concurrency limit, nil when there is none. The
+// promise counts towards the concurrency of its organization until done.
+func (q *fairQueue) pop() *promise {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var (
+		next   *orgQueue
+		nextID influxdb.ID
+	)
+	for id, o := range q.orgs {
+		if len(o.promises) == 0 {
+			continue
+		}
+		if o.limits.MaxConcurrency > 0 && o.running >= o.limits.MaxConcurrency {
+			continue
+		}
+		if next == nil || o.promises[0].finish < next.promises[0].finish ||
+			(o.promises[0].finish == next.promises[0].finish && id < nextID) {
+			next, nextID = o, id
+		}
+	}
+	if next == nil {
+		return nil
+	}
+
+	qp := next.promises[0]
+	next.promises[0] = queuedPromise{}
+	next.promises = next.promises[1:]
+	next.running++
+	// promises of organizations at their concurrency limit are passed over,
+	// so they may be taken after promises with a later finish time
+	if qp.finish > q.vtime {
+		q.vtime = qp.finish
+	}
+	q.size--
+	q.notFull.Signal()
+	return qp.p
+}
+
+// done releases the concurrency a promise taken from the queue held.
+func (q *fairQueue) done(p *promise) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	orgID := p.task.OrganizationID
+	o, ok := q.orgs[orgID]
+	if !ok {
+		return
+	}
+	o.running--
+	q.removeIdle(orgID, o)
+}
+
+// len returns the number of queued promises.
+func (q *fairQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size
+}
+
+func (q *fairQueue) org(id influxdb.ID) *orgQueue {
+	o, ok := q.orgs[id]
+	if !ok {
+		limits, ok := q.orgLimits[id]
+		if !ok {
+			limits = q.limits
+		}
+		o = &orgQueue{limits: limits}
+		q.orgs[id] = o
+	}
+	return o
+}
+
+// removeIdle forgets organizations without queued or running promises. Those
+// ahead of the virtual time are kept, so they are not credited when they queue
+// again.
+func (q *fairQueue) removeIdle(id influxdb.ID, o *orgQueue) {
+	if len(o.promises) == 0 && o.running <= 0 && o.finish <= q.vtime {
+		delete(q.orgs, id)
+	}
+}
+
+// ParseOrgLimits parses the limits of organizations keyed by organization ID,
+// given as weight:max-concurrency:queue-depth, such as 2:10:100.
+func ParseOrgLimits(m map[string]string) (map[influxdb.ID]OrgLimits, error) {
+	limits := make(map[influxdb.ID]OrgLimits, len(m))
+	for k, v := range m {
+		id, err := influxdb.IDFromString(k)
+		if err != nil {
+			return nil, fmt.Errorf("invalid organization ID %q: %v", k, err)
+		}
+
+		parts := strings.Split(v, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid limits %q of organization %s, expected weight:max-concurrency:queue-depth", v, k)
+		}
+		var ns [3]int
+		for i, p := range parts {
+			if ns[i], err = strconv.Atoi(p); err != nil || ns[i] < 0 {
+				return nil, fmt.Errorf("invalid limits %q of organization %s, expected non-negative integers", v, k)
+			}
+		}
+		limits[*id] = OrgLimits{Weight: ns[0], MaxConcurrency: ns[1], QueueDepth: ns[2]}
+	}
+	return limits, nil
+}
@@ -0,0 +1,130 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+func queuedPromiseOf(orgID influxdb.ID, runID influxdb.ID) *promise {
+	return &promise{
+		task: &influxdb.Task{ID: 1, OrganizationID: orgID},
+		run:  &influxdb.Run{ID: runID},
+	}
+}
+
+// popOrgs takes n promises from the queue, releasing them right away, and
+// returns their organizations.
+func popOrgs(t *testing.T, q *fairQueue, n int) []influxdb.ID {
+	t.Helper()
+
+	var orgs []influxdb.ID
+	for i := 0; i < n; i++ {
+		p := q.pop()
+		if p == nil {
+			t.Fatalf("expected a promise after %d pops", i)
+		}
+		orgs = append(orgs, p.task.OrganizationID)
+		q.done(p)
+	}
+	return orgs
+}
+
+func TestFairQueue_Fairness(t *testing.T) {
+	q := newFairQueue(100, OrgLimits{}, map[influxdb.ID]OrgLimits{3: {Weight: 2}})
+
+	// org 1 queues many runs before the others queue theirs
+	for i := 0; i < 10; i++ {
+		if err := q.push(queuedPromiseOf(1, influxdb.ID(100+i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 4; i++ {
+		if err := q.push(queuedPromiseOf(2, influxdb.ID(200+i))); err != nil {
+			t.Fatal(err)
+		}
+		if err := q.push(queuedPromiseOf(3, influxdb.ID(300+i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// org 3 has twice the weight of the others
+	got := popOrgs(t, q, 8)
+	counts := make(map[influxdb.ID]int)
+	for _, id := range got {
+		counts[id]++
+	}
+	if counts[1] != 2 || counts[2] != 2 || counts[3] != 4 {
+		t.Fatalf("unexpected share of the first runs taken: %v", got)
+	}
+
+	// the runs of an organization are taken in the order they were queued
+	p := q.pop()
+	for p != nil && p.task.OrganizationID != 1 {
+		q.done(p)
+		p = q.pop()
+	}
+	if p == nil || p.run.ID != 102 {
+		t.Fatalf("expected the third run of org 1, got %v", p)
+	}
+}
+
+func TestFairQueue_MaxConcurrency(t *testing.T) {
+	q := newFairQueue(100, OrgLimits{MaxConcurrency: 1}, nil)
+
+	for i := 0; i < 2; i++ {
+		if err := q.push(queuedPromiseOf(1, influxdb.ID(100+i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.push(queuedPromiseOf(2, 200)); err != nil {
+		t.Fatal(err)
+	}
+
+	first := q.pop()
+	if first == nil || first.task.OrganizationID != 1 {
+		t.Fatalf("expected a run of org 1, got %v", first)
+	}
+	// org 1 is at its limit, so only the run of org 2 can be taken
+	if p := q.pop(); p == nil || p.task.OrganizationID != 2 {
+		t.Fatalf("expected the run of org 2, got %v", p)
+	}
+	if p := q.pop(); p != nil {
+		t.Fatalf("expected no run while org 1 is at its limit, got %v", p)
+	}
+
+	q.done(first)
+	if p := q.pop(); p == nil || p.run.ID != 101 {
+		t.Fatalf("expected the second run of org 1, got %v", p)
+	}
+	if n := q.len(); n != 0 {
+		t.Fatalf("expected an empty queue, got %d", n)
+	}
+}
+
+func TestFairQueue_QueueDepth(t *testing.T) {
+	q := newFairQueue(100, OrgLimits{QueueDepth: 2}, map[influxdb.ID]OrgLimits{2: {QueueDepth: 1}})
+
+	for i := 0; i < 2; i++ {
+		if err := q.push(queuedPromiseOf(1, influxdb.ID(100+i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := q.push(queuedPromiseOf(1, 102))
+	if influxdb.ErrorCode(err) != influxdb.ETooManyRequests {
+		t.Fatalf("expected the queue of org 1 to be full, got %v", err)
+	}
+
+	if err := q.push(queuedPromiseOf(2, 200)); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.push(queuedPromiseOf(2, 201)); err == nil {
+		t.Fatal("expected the queue of org 2 to be full")
+	}
+
+	// taking a run makes room in the queue of its organization
+	popOrgs(t, q, 1)
+	if err := q.push(queuedPromiseOf(1, 102)); err != nil {
+		t.Fatal(err)
+	}
+}
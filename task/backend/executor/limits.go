@@ -20,11 +20,21 @@ func ConcurrencyLimit(exec *Executor, lang influxdb.FluxLanguageService) LimitFu
 			return nil
 		}
 
-		runs, err := exec.tcs.CurrentlyRunning(context.Background(), t.ID)
+		current, err := exec.tcs.CurrentlyRunning(context.Background(), t.ID)
 		if err != nil {
 			return err
 		}
 
+		// retries waiting for their backoff and runs waiting for upstream
+		// tasks do not run yet.
+		runs := current[:0]
+		for _, run := range current {
+			if run.ID != r.ID && (exec.retryPending(run.ID) || exec.deps.waiting(t.ID, run.ID)) {
+				continue
+			}
+			runs = append(runs, run)
+		}
+
 		// sort by scheduledFor time because we want to make sure older scheduled for times
 		// are higher priority
 		sort.SliceStable(runs, func(i, j int) bool {
@@ -0,0 +1,160 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/task/options"
+	"go.uber.org/zap"
+)
+
+// retryAttempts returns the number of attempts a run of the task is given,
+// read from the retry option of its script. Runs are attempted once when the
+// option cannot be read.
+func (e *Executor) retryAttempts(t *influxdb.Task) int {
+	if e.lang == nil {
+		return 1
+	}
+	o, err := options.FromScript(e.lang, t.Flux)
+	if err != nil || o.Retry == nil {
+		return 1
+	}
+	return int(*o.Retry)
+}
+
+// retryDelay returns the delay before the given attempt of a failed run.
+// The delay starts at the retry backoff for the second attempt and doubles
+// with every following attempt, up to the max retry backoff.
+func (e *Executor) retryDelay(attempt int) time.Duration {
+	d := e.retryBackoff
+	for i := 2; i < attempt; i++ {
+		d *= 2
+		if e.maxRetryBackoff > 0 && d >= e.maxRetryBackoff {
+			break
+		}
+	}
+	if e.maxRetryBackoff > 0 && d > e.maxRetryBackoff {
+		d = e.maxRetryBackoff
+	}
+	return d
+}
+
+// shouldRetry reports whether the failed run of the promise has attempts left.
+// The decision is added to the log of the run, which must not be finished yet.
+func (e *Executor) shouldRetry(p *promise) bool {
+	attempts := e.retryAttempts(p.task)
+	if attempts <= 1 {
+		return false
+	}
+
+	attempt := p.run.AttemptNumber()
+	if attempt >= attempts {
+		e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), fmt.Sprintf("Retries exhausted after %d attempts", attempt))
+		e.metrics.LogRetriesExhausted(p.task)
+		return false
+	}
+
+	e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), fmt.Sprintf("Retrying in %s, attempt %d of %d", e.retryDelay(attempt+1), attempt+1, attempts))
+	return true
+}
+
+// Retries are created in the task store as soon as a run fails, scheduled
+// to run once their backoff has passed, and are started by a timer. A retry
+// that is pending when the executor is closed stays in the store and is
+// resumed with ResumeCurrentRun, like the other runs that did not finish.
+
+// scheduleRetry creates the run retrying the failed run, it is started once
+// its backoff has passed.
+func (e *Executor) scheduleRetry(t *influxdb.Task, failed *influxdb.Run, trigger *triggerRange) {
+	ctx := context.Background()
+	attempt := failed.AttemptNumber() + 1
+	delay := e.retryDelay(attempt)
+
+	r, err := e.tcs.CreateRetryRun(ctx, t.ID, failed.OriginalRunID(), attempt, failed.ScheduledFor, time.Now().UTC().Add(delay))
+	if err != nil {
+		e.log.Error("Failed to create retry run", zap.String("taskID", t.ID.String()), zap.String("runID", failed.ID.String()), zap.Error(err))
+		return
+	}
+	e.tcs.AddRunLog(ctx, t.ID, r.ID, time.Now().UTC(), fmt.Sprintf("Retrying failed run %s, attempt %d", failed.ID, attempt))
+	e.retryAfter(r, delay, trigger)
+}
+
+// retryAfter starts the retry run after the delay, unless the executor is
+// closed first.
+func (e *Executor) retryAfter(r *influxdb.Run, delay time.Duration, trigger *triggerRange) {
+	e.retryMu.Lock()
+	defer e.retryMu.Unlock()
+	if e.closed {
+		return
+	}
+	if _, ok := e.retryTimers[r.ID]; ok {
+		return
+	}
+
+	e.retryTimers[r.ID] = time.AfterFunc(delay, func() {
+		e.retryMu.Lock()
+		delete(e.retryTimers, r.ID)
+		e.retryMu.Unlock()
+
+		ctx := context.Background()
+		started, err := e.startRetry(ctx, r, trigger)
+		if err != nil {
+			e.log.Error("Failed to retry run", zap.String("taskID", r.TaskID.String()), zap.String("runID", r.RetryOf.String()), zap.Error(err))
+		}
+		if !started {
+			// the window of the failed run is failed for good
+			e.releaseDependents(ctx, r.TaskID)
+		}
+	})
+}
+
+// startRetry starts the retry run, reporting whether it was started. The
+// retry of a run started by writes covers the same written time range.
+func (e *Executor) startRetry(ctx context.Context, r *influxdb.Run, trigger *triggerRange) (bool, error) {
+	// the task may have been disabled or deleted during the backoff
+	t, err := e.ts.FindTaskByID(ctx, r.TaskID)
+	if err == nil && t.Status != influxdb.TaskStatusActive {
+		e.tcs.AddRunLog(ctx, r.TaskID, r.ID, time.Now().UTC(), "Retry canceled, the task is inactive")
+		e.tcs.UpdateRunState(ctx, r.TaskID, r.ID, time.Now().UTC(), influxdb.RunCanceled)
+		_, err = e.tcs.FinishRun(ctx, r.TaskID, r.ID)
+		return false, err
+	}
+	if err == nil {
+		_, err = e.createPromise(ctx, r, trigger, false)
+	}
+	if err != nil {
+		e.tcs.UpdateRunState(ctx, r.TaskID, r.ID, time.Now().UTC(), influxdb.RunFail)
+		e.tcs.FinishRun(ctx, r.TaskID, r.ID)
+		return false, err
+	}
+	e.metrics.LogRetry(t)
+	e.startWorker()
+	return true, nil
+}
+
+// pendingRetry reports whether the run is a retry that has not started yet.
+func pendingRetry(r *influxdb.Run) bool {
+	return r.RetryOf.Valid() && r.Status == influxdb.RunScheduled.String()
+}
+
+// retryPending reports whether the run is a retry waiting for its backoff.
+func (e *Executor) retryPending(runID influxdb.ID) bool {
+	e.retryMu.Lock()
+	defer e.retryMu.Unlock()
+	_, ok := e.retryTimers[runID]
+	return ok
+}
+
+// Close stops the timers of pending retries, they are left in the task store
+// to be resumed.
+func (e *Executor) Close() {
+	e.retryMu.Lock()
+	defer e.retryMu.Unlock()
+	e.closed = true
+	for id, t := range e.retryTimers {
+		t.Stop()
+		delete(e.retryTimers, id)
+	}
+}
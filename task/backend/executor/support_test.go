@@ -296,3 +296,11 @@ option task = {
 			every: 1m,
 }
 from(bucket: "one") |> to(bucket: "two", orgID: "0000000000000000")`
+
+const fmtTestScriptWithRetry = `
+option task = {
+			name: %q,
+			every: 1m,
+			retry: 2,
+}
+from(bucket: "one") |> to(bucket: "two", orgID: "0000000000000000")`
@@ -0,0 +1,56 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+// Runs of tasks with a trigger are started by writes to the trigger bucket.
+// The written time range is available to the script of the task through the
+// trigger option, a record with the start and the exclusive stop time of the
+// points written since the previous triggered run:
+//
+//	from(bucket: "raw")
+//	    |> range(start: trigger.start, stop: trigger.stop)
+//
+// Runs of these tasks that were not started by writes, such as manual runs,
+// cover the time from the latest successful run to the time the run is
+// scheduled for.
+
+// triggerRange is the time range of the points written to the trigger bucket of a task.
+type triggerRange struct {
+	start, stop time.Time
+}
+
+// TriggeredExecute begins execution of the task for the points written
+// between start and stop, stop is exclusive.
+func (e *Executor) TriggeredExecute(ctx context.Context, id influxdb.ID, scheduledFor, start, stop time.Time) (Promise, error) {
+	p, err := e.createRun(ctx, id, scheduledFor, scheduledFor, &triggerRange{start: start.UTC(), stop: stop.UTC()}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	e.startWorker()
+	e.metrics.triggeredRunsCounter.WithLabelValues(id.String()).Inc()
+	return p, nil
+}
+
+// triggerRange returns the time range the run of the promise covers, nil
+// when the task has no trigger.
+func (p *promise) triggerRange() *triggerRange {
+	if p.trigger != nil {
+		return p.trigger
+	}
+	if p.task.Trigger == "" {
+		return nil
+	}
+
+	stop := p.run.ScheduledFor
+	start := p.task.LatestSuccess
+	if start.IsZero() || start.After(stop) {
+		start = stop
+	}
+	return &triggerRange{start: start, stop: stop}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+// CoordinatingRevisionService acts as a TaskRevisionService decorator that rolls tasks back
+// through a coordinating task service, so that the task owner acts on the restored script.
+type CoordinatingRevisionService struct {
+	influxdb.TaskRevisionService
+	taskService influxdb.TaskService
+}
+
+// NewRevisionService constructs a new coordinating task revision service
+func NewRevisionService(rs influxdb.TaskRevisionService, ts influxdb.TaskService) *CoordinatingRevisionService {
+	return &CoordinatingRevisionService{
+		TaskRevisionService: rs,
+		taskService:         ts,
+	}
+}
+
+// RollbackTask updates the task to the script of the revision through the task service.
+func (s *CoordinatingRevisionService) RollbackTask(ctx context.Context, taskID influxdb.ID, revision int) (*influxdb.Task, error) {
+	rev, err := s.TaskRevisionService.FindTaskRevision(ctx, taskID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.taskService.UpdateTask(ctx, taskID, influxdb.TaskUpdate{Flux: &rev.Flux, RollbackOf: &revision})
+}
@@ -0,0 +1,49 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/task/backend/middleware"
+)
+
+func TestRevisionRollback(t *testing.T) {
+	mocks := newMockServices()
+	ch := mocks.pipingCoordinator.taskUpdatedChan()
+
+	const flux = `option task = {name: "a task", every: 1h} from(bucket:"test") |> range(start:-1h)`
+	var upd influxdb.TaskUpdate
+	mocks.taskSvc.UpdateTaskFn = func(_ context.Context, id influxdb.ID, u influxdb.TaskUpdate) (*influxdb.Task, error) {
+		upd = u
+		return &influxdb.Task{ID: id, Flux: *u.Flux}, nil
+	}
+	revisions := &mock.TaskRevisionService{
+		FindTaskRevisionFn: func(_ context.Context, taskID influxdb.ID, revision int) (*influxdb.TaskRevision, error) {
+			return &influxdb.TaskRevision{TaskID: taskID, Revision: revision, Flux: flux}, nil
+		},
+	}
+	tasks := middleware.New(mocks.taskSvc, mocks.pipingCoordinator)
+	svc := middleware.NewRevisionService(revisions, tasks)
+
+	task, err := svc.RollbackTask(context.Background(), 4, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Flux != flux {
+		t.Fatalf("task was not rolled back to the script of the revision: %q", task.Flux)
+	}
+	if upd.RollbackOf == nil || *upd.RollbackOf != 2 {
+		t.Fatal("update did not mark the rollback")
+	}
+
+	select {
+	case task := <-ch:
+		if task.ID != 4 {
+			t.Fatalf("task sent to coordinator doesn't match expected")
+		}
+	default:
+		t.Fatal("didn't receive task")
+	}
+}
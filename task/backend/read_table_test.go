@@ -37,3 +37,42 @@ func TestReadTable(t *testing.T) {
 		t.Fatalf("got error from iterator %v", itr.Err())
 	}
 }
+
+func TestReadTable_Retry(t *testing.T) {
+	encoded := []byte(`group,false,false,true,true,false,true,false,false,false,false,false,false,false,false
+#datatype,string,long,dateTime:RFC3339,dateTime:RFC3339,dateTime:RFC3339,string,string,string,string,string,string,string,string,long
+#default,_result,,,,,,,,,,,,,
+,result,table,_start,_stop,_time,taskID,finishedAt,logs,runID,scheduledFor,startedAt,status,retryOf,attempt
+,,0,2019-07-23T20:06:24.369913228Z,2019-07-23T20:11:24.369913228Z,2019-07-23T20:06:30.232988837Z,0432e57782b51000,2019-07-23T20:06:30.300005674Z,,04341baa937a1000,2019-07-23T20:06:30Z,2019-07-23T20:06:30.232988837Z,failed,,
+,,0,2019-07-23T20:06:24.369913228Z,2019-07-23T20:11:24.369913228Z,2019-07-23T20:07:00.215226536Z,0432e57782b51000,2019-07-23T20:07:00.284116882Z,,04341bb4543a1000,2019-07-23T20:06:30Z,2019-07-23T20:07:00.215226536Z,success,04341baa937a1000,2`)
+
+	decoder := csv.NewMultiResultDecoder(csv.ResultDecoderConfig{})
+	itr, err := decoder.Decode(ioutil.NopCloser(bytes.NewReader(encoded)))
+	if err != nil {
+		t.Fatalf("got error decoding csv: %v", err)
+	}
+
+	defer itr.Release()
+	re := &runReader{log: zaptest.NewLogger(t)}
+
+	for itr.More() {
+		err := itr.Next().Tables().Do(re.readTable)
+		if err != nil {
+			t.Fatalf("received error in runs table: %v", err)
+		}
+	}
+
+	if itr.Err() != nil {
+		t.Fatalf("got error from iterator %v", itr.Err())
+	}
+
+	if len(re.runs) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(re.runs))
+	}
+	if r := re.runs[0]; r.RetryOf.Valid() || r.Attempt != 0 {
+		t.Fatalf("expected original run without retry, got retryOf %s attempt %d", r.RetryOf, r.Attempt)
+	}
+	if r := re.runs[1]; r.RetryOf.String() != "04341baa937a1000" || r.Attempt != 2 {
+		t.Fatalf("expected retry of 04341baa937a1000 attempt 2, got retryOf %s attempt %d", r.RetryOf, r.Attempt)
+	}
+}
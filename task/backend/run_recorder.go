@@ -48,6 +48,16 @@ func (s *StoragePointsWriterRecorder) Record(ctx context.Context, orgID influxdb
 	fields[finishedAtField] = run.FinishedAt.Format(time.RFC3339Nano)
 	fields[scheduledForField] = run.ScheduledFor.Format(time.RFC3339)
 	fields[requestedAtField] = run.RequestedAt.Format(time.RFC3339)
+	if !run.RunAt.IsZero() {
+		fields[runAtField] = run.RunAt.Format(time.RFC3339)
+	}
+	if run.RetryOf.Valid() {
+		fields[retryOfField] = run.RetryOf.String()
+		fields[attemptField] = int64(run.Attempt)
+	}
+	if run.Revision > 0 {
+		fields[revisionField] = int64(run.Revision)
+	}
 
 	startedAt := run.StartedAt
 	if startedAt.IsZero() {
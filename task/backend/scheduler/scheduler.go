@@ -42,6 +42,61 @@ type Schedulable interface {
 	// LastScheduled specifies last time this Schedulable was queued
 	// for execution.
 	LastScheduled() time.Time
+
+	// Catchup defines which of the runs due between LastScheduled and the
+	// time the Schedulable is scheduled are executed.
+	Catchup() CatchupPolicy
+}
+
+// CatchupPolicy is which of the runs a Schedulable missed before it was
+// scheduled, e.g. while the scheduler was stopped, are executed. The zero
+// policy executes all of them.
+type CatchupPolicy struct {
+	// Limited executes only the latest Max missed runs, none when Max is 0.
+	Limited bool
+	Max     int
+}
+
+// catchupFrom returns the time to schedule from, so that only the missed runs
+// the policy allows are executed. Runs are missed when they were due by now,
+// after last.
+func catchupFrom(sch Schedule, offset time.Duration, last, now time.Time, policy CatchupPolicy) (time.Time, error) {
+	if !policy.Limited {
+		return last, nil
+	}
+
+	var (
+		missed int
+		from   = last
+		// the times the latest missed runs follow, up to the policy max
+		froms = make([]time.Time, 0, policy.Max)
+	)
+	for {
+		next, err := sch.Next(from)
+		if err != nil {
+			return last, err
+		}
+		if next.Add(offset).After(now) {
+			break
+		}
+		missed++
+		if policy.Max > 0 {
+			if len(froms) == policy.Max {
+				froms = froms[1:]
+			}
+			froms = append(froms, from)
+		}
+		from = next
+	}
+
+	switch {
+	case missed <= policy.Max:
+		return last, nil
+	case policy.Max == 0:
+		// from is the latest missed run, the next run is the first one due
+		return from, nil
+	}
+	return froms[0], nil
 }
 
 // SchedulableService encapsulates the work necessary to schedule a job
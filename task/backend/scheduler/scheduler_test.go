@@ -23,6 +23,7 @@ type mockSchedulable struct {
 	schedule      Schedule
 	offset        time.Duration
 	lastScheduled time.Time
+	catchup       CatchupPolicy
 }
 
 func (s mockSchedulable) ID() ID {
@@ -38,6 +39,9 @@ func (s mockSchedulable) Offset() time.Duration {
 func (s mockSchedulable) LastScheduled() time.Time {
 	return s.lastScheduled
 }
+func (s mockSchedulable) Catchup() CatchupPolicy {
+	return s.catchup
+}
 
 func (e *mockExecutor) Execute(ctx context.Context, id ID, scheduledFor time.Time, runAt time.Time) error {
 	done := make(chan struct{}, 1)
@@ -666,3 +670,58 @@ func TestNewSchedule(t *testing.T) {
 		})
 	}
 }
+
+func TestCatchupFrom(t *testing.T) {
+	last := time.Date(2016, 01, 01, 01, 0, 0, 0, time.UTC)
+	// runs at 01:10, 01:20 and 01:30 were missed, the next one is at 01:40
+	now := time.Date(2016, 01, 01, 01, 35, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		offset time.Duration
+		policy CatchupPolicy
+		want   time.Time
+	}{
+		{
+			name: "all",
+			want: last,
+		},
+		{
+			name:   "latest",
+			policy: CatchupPolicy{Limited: true, Max: 1},
+			want:   time.Date(2016, 01, 01, 01, 20, 0, 0, time.UTC),
+		},
+		{
+			name:   "none",
+			policy: CatchupPolicy{Limited: true, Max: 0},
+			want:   time.Date(2016, 01, 01, 01, 30, 0, 0, time.UTC),
+		},
+		{
+			name:   "max 2",
+			policy: CatchupPolicy{Limited: true, Max: 2},
+			want:   time.Date(2016, 01, 01, 01, 10, 0, 0, time.UTC),
+		},
+		{
+			name:   "max more than missed",
+			policy: CatchupPolicy{Limited: true, Max: 5},
+			want:   last,
+		},
+		{
+			name:   "offset delays runs",
+			offset: 10 * time.Minute,
+			policy: CatchupPolicy{Limited: true, Max: 0},
+			want:   time.Date(2016, 01, 01, 01, 20, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := catchupFrom(mustCron("@every 10m"), tt.offset, last, now, tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("catchupFrom() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
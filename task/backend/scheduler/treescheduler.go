@@ -74,6 +74,7 @@ type TreeScheduler struct {
 	wg            sync.WaitGroup
 	checkpointer  SchedulableService
 	items         *itemList
+	catchup       chan struct{} // bounds the runs of Schedulables catching up executing at once
 
 	sm *SchedulerMetrics
 }
@@ -100,6 +101,19 @@ func WithMaxConcurrentWorkers(n int) treeSchedulerOptFunc {
 	}
 }
 
+// WithMaxCatchupWorkers is an option that sets the max number of runs of
+// Schedulables catching up on missed runs a TreeScheduler executes at once, so
+// that they leave workers for the runs that are on schedule.
+// A Schedulable is catching up while the run after the one being executed is due already.
+func WithMaxCatchupWorkers(n int) treeSchedulerOptFunc {
+	return func(t *TreeScheduler) error {
+		if n > 0 {
+			t.catchup = make(chan struct{}, n)
+		}
+		return nil
+	}
+}
+
 // WithTime is an option for NewScheduler that allows you to inject a clock.Clock from ben johnson's github.com/benbjohnson/clock library, for testing purposes.
 func WithTime(t clock.Clock) treeSchedulerOptFunc {
 	return func(sch *TreeScheduler) error {
@@ -248,13 +262,23 @@ func (s *TreeScheduler) iterator(ts time.Time) btree.ItemIterator {
 		if time.Unix(it.next+it.Offset, 0).After(ts) {
 			return false
 		}
+		// runs catching up wait for a catch-up worker
+		run := it
+		if s.catchingUp(it, ts) {
+			select {
+			case s.catchup <- struct{}{}:
+				run.catchup = true
+			default:
+				return true
+			}
+		}
 		// distribute to the right worker.
 		{
 			buf := [8]byte{}
 			binary.LittleEndian.PutUint64(buf[:], uint64(it.id))
 			wc := xxhash.Sum64(buf[:]) % uint64(len(s.workchans)) // we just hash so that the number is uniformly distributed
 			select {
-			case s.workchans[wc] <- it:
+			case s.workchans[wc] <- run:
 				s.items.toDelete = append(s.items.toDelete, it)
 				if err := it.updateNext(); err != nil {
 					// in this error case we can't schedule next, so we have to drop the task
@@ -264,8 +288,10 @@ func (s *TreeScheduler) iterator(ts time.Time) btree.ItemIterator {
 				s.items.toInsert = append(s.items.toInsert, it)
 
 			case <-s.done:
+				s.releaseCatchup(run)
 				return false
 			default:
+				s.releaseCatchup(run)
 				return true
 			}
 		}
@@ -273,6 +299,27 @@ func (s *TreeScheduler) iterator(ts time.Time) btree.ItemIterator {
 	}
 }
 
+// catchingUp reports whether the run of the item is catching up, its
+// following run being due by ts too. Runs are never catching up when the
+// catch-up workers aren't limited.
+func (s *TreeScheduler) catchingUp(it Item, ts time.Time) bool {
+	if s.catchup == nil {
+		return false
+	}
+	following, err := it.cron.Next(time.Unix(it.next, 0))
+	if err != nil {
+		return false
+	}
+	return !following.Add(time.Duration(it.Offset) * time.Second).After(ts)
+}
+
+// releaseCatchup frees the catch-up worker taken by the run, if any.
+func (s *TreeScheduler) releaseCatchup(run Item) {
+	if run.catchup {
+		<-s.catchup
+	}
+}
+
 // When gives us the next time the scheduler will run a task.
 func (s *TreeScheduler) When() time.Time {
 	s.mu.RLock()
@@ -333,6 +380,7 @@ func (s *TreeScheduler) work(ctx context.Context, ch chan Item) {
 		if err := s.checkpointer.UpdateLastScheduled(ctx, it.id, t); err != nil {
 			s.onErr(ctx, it.id, it.Next(), err)
 		}
+		s.releaseCatchup(it)
 	}
 }
 
@@ -345,7 +393,14 @@ func (s *TreeScheduler) Schedule(sch Schedulable) error {
 		Offset: int64(sch.Offset().Seconds()),
 		//last:   sch.LastScheduled().Unix(),
 	}
-	nt, err := it.cron.Next(sch.LastScheduled())
+	// skip the missed runs the catch-up policy leaves out
+	from, err := catchupFrom(it.cron, sch.Offset(), sch.LastScheduled(), s.time.Now().UTC(), sch.Catchup())
+	if err != nil {
+		s.sm.scheduleFail(it.id)
+		s.onErr(context.Background(), it.id, time.Time{}, err)
+		return err
+	}
+	nt, err := it.cron.Next(from)
 	if err != nil {
 		s.sm.scheduleFail(it.id)
 		s.onErr(context.Background(), it.id, time.Time{}, err)
@@ -386,11 +441,12 @@ func (s *TreeScheduler) Schedule(sch Schedulable) error {
 
 // Item is a task in the scheduler.
 type Item struct {
-	when   int64
-	id     ID
-	cron   Schedule
-	next   int64
-	Offset int64
+	when    int64
+	id      ID
+	cron    Schedule
+	next    int64
+	Offset  int64
+	catchup bool // the run holds a catch-up worker
 }
 
 func (it Item) Next() time.Time {
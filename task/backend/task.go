@@ -14,6 +14,9 @@ type TaskControlService interface {
 	// CreateRun creates a run with a scheduled for time.
 	CreateRun(ctx context.Context, taskID influxdb.ID, scheduledFor time.Time, runAt time.Time) (*influxdb.Run, error)
 
+	// CreateRetryRun creates a run retrying the failed run retryOf, with the given attempt number.
+	CreateRetryRun(ctx context.Context, taskID, retryOf influxdb.ID, attempt int, scheduledFor time.Time, runAt time.Time) (*influxdb.Run, error)
+
 	CurrentlyRunning(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Run, error)
 	ManualRuns(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Run, error)
 
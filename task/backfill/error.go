@@ -0,0 +1,73 @@
+package backfill
+
+import (
+	"fmt"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var (
+	// ErrBackfillNotFound is used when the backfill does not exist.
+	ErrBackfillNotFound = &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "backfill not found",
+	}
+
+	// ErrInvalidRange is used when the start of a backfill is not before its end.
+	ErrInvalidRange = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "backfill start must be before its end",
+	}
+
+	// ErrNoRuns is used when the schedule of the task does not trigger between the start and end of a backfill.
+	ErrNoRuns = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "task is not scheduled to run between the start and end of the backfill",
+	}
+
+	// ErrInactiveTask is used when backfilling a task that is not active.
+	ErrInactiveTask = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "inactive task cannot be backfilled",
+	}
+
+	// ErrNotRunning is used when canceling a backfill that is no longer running.
+	ErrNotRunning = &influxdb.Error{
+		Code: influxdb.EConflict,
+		Msg:  "backfill is not running",
+	}
+)
+
+// ErrInvalidBackfillID is used when the ID of a backfill cannot be encoded.
+func ErrInvalidBackfillID(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "invalid backfill ID",
+		Err:  err,
+	}
+}
+
+// ErrInvalidSchedule is used when the schedule of the task cannot be parsed.
+func ErrInvalidSchedule(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "task schedule is invalid",
+		Err:  err,
+	}
+}
+
+// ErrTooManyRuns is used when a backfill would start more than the maximum number of runs.
+func ErrTooManyRuns(max int) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  fmt.Sprintf("backfill must not start more than %d runs", max),
+	}
+}
+
+// ErrInternalService is used when the error comes from an internal system.
+func ErrInternalService(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Err:  err,
+	}
+}
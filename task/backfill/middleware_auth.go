@@ -0,0 +1,76 @@
+package backfill
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+)
+
+var _ influxdb.BackfillService = (*AuthorizedService)(nil)
+
+// AuthorizedService restricts access to backfills to the authorizers that may
+// read their task, and starting or canceling them to those that may write it.
+type AuthorizedService struct {
+	s     influxdb.BackfillService
+	tasks influxdb.TaskService
+}
+
+// NewAuthorizedService wraps a backfill service with authorization checks.
+// The task service is used to look up the organization of tasks, it must not
+// be authorized itself.
+func NewAuthorizedService(s influxdb.BackfillService, tasks influxdb.TaskService) *AuthorizedService {
+	return &AuthorizedService{
+		s:     s,
+		tasks: tasks,
+	}
+}
+
+// CreateBackfill checks that the authorizer may write the task.
+func (s *AuthorizedService) CreateBackfill(ctx context.Context, taskID influxdb.ID, start, end time.Time) (*influxdb.Backfill, error) {
+	t, err := s.tasks.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := authorizer.AuthorizeWrite(ctx, influxdb.TasksResourceType, t.ID, t.OrganizationID); err != nil {
+		return nil, err
+	}
+	return s.s.CreateBackfill(ctx, taskID, start, end)
+}
+
+// FindBackfillByID checks that the authorizer may read the task of the backfill.
+func (s *AuthorizedService) FindBackfillByID(ctx context.Context, taskID, id influxdb.ID) (*influxdb.Backfill, error) {
+	b, err := s.s.FindBackfillByID(ctx, taskID, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := authorizer.AuthorizeRead(ctx, influxdb.TasksResourceType, b.TaskID, b.OrganizationID); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// FindBackfills checks that the authorizer may read the task.
+func (s *AuthorizedService) FindBackfills(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Backfill, error) {
+	t, err := s.tasks.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := authorizer.AuthorizeRead(ctx, influxdb.TasksResourceType, t.ID, t.OrganizationID); err != nil {
+		return nil, err
+	}
+	return s.s.FindBackfills(ctx, taskID)
+}
+
+// CancelBackfill checks that the authorizer may write the task of the backfill.
+func (s *AuthorizedService) CancelBackfill(ctx context.Context, taskID, id influxdb.ID) (*influxdb.Backfill, error) {
+	b, err := s.s.FindBackfillByID(ctx, taskID, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := authorizer.AuthorizeWrite(ctx, influxdb.TasksResourceType, b.TaskID, b.OrganizationID); err != nil {
+		return nil, err
+	}
+	return s.s.CancelBackfill(ctx, taskID, id)
+}
@@ -0,0 +1,420 @@
+package backfill
+
+// The backfill `Service` runs a task for every time its schedule triggers on
+// between the start and end of a backfill. Backfills are stored in a kv bucket
+// so that their progress can be followed, and are run in the background by the
+// task executor. No more runs of a backfill are in progress at once than the
+// concurrency option of the task allows. As with the scheduler, the run for a
+// time runs after the offset of the task has passed, the times it has not yet
+// passed for are left to the scheduler.
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/snowflake"
+	"github.com/influxdata/influxdb/v2/task/backend/executor"
+	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
+	"github.com/influxdata/influxdb/v2/task/options"
+	"go.uber.org/zap"
+)
+
+// MaxRuns is the largest number of runs a single backfill may start.
+const MaxRuns = 10000
+
+var backfillBucket = []byte("taskbackfillsv1")
+
+var _ influxdb.BackfillService = (*Service)(nil)
+
+// Executor executes the runs of a backfill.
+type Executor interface {
+	PromisedExecute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) (executor.Promise, error)
+}
+
+// Service is the kv backed implementation of influxdb.BackfillService.
+type Service struct {
+	log      *zap.Logger
+	store    kv.Store
+	tasks    influxdb.TaskService
+	executor Executor
+	lang     influxdb.FluxLanguageService
+
+	IDGen influxdb.IDGenerator
+	now   func() time.Time
+
+	mu   sync.Mutex
+	jobs map[influxdb.ID]*job
+}
+
+// job is a backfill running in the background.
+type job struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewService returns a backfill service that stores backfills in the kv store
+// and runs them with the executor.
+func NewService(log *zap.Logger, st kv.Store, tasks influxdb.TaskService, ex Executor, lang influxdb.FluxLanguageService) *Service {
+	return &Service{
+		log:      log,
+		store:    st,
+		tasks:    tasks,
+		executor: ex,
+		lang:     lang,
+		IDGen:    snowflake.NewDefaultIDGenerator(),
+		now:      time.Now,
+		jobs:     make(map[influxdb.ID]*job),
+	}
+}
+
+// CreateBackfill starts a backfill of the task from start to end, both
+// inclusive. The end is moved back to the last time the offset of the task
+// has passed for.
+func (s *Service) CreateBackfill(ctx context.Context, taskID influxdb.ID, start, end time.Time) (*influxdb.Backfill, error) {
+	if !start.Before(end) {
+		return nil, ErrInvalidRange
+	}
+	t, err := s.tasks.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status != influxdb.TaskStatusActive {
+		return nil, ErrInactiveTask
+	}
+
+	now := s.now().UTC()
+	if due := now.Add(-t.Offset); end.After(due) {
+		end = due
+	}
+
+	total, err := countRuns(t, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if total == 0 {
+		return nil, ErrNoRuns
+	}
+	if total > MaxRuns {
+		return nil, ErrTooManyRuns(MaxRuns)
+	}
+
+	b := &influxdb.Backfill{
+		ID:             s.IDGen.ID(),
+		TaskID:         t.ID,
+		OrganizationID: t.OrganizationID,
+		Start:          start.UTC(),
+		End:            end.UTC(),
+		Status:         influxdb.BackfillStatusRunning,
+		Total:          total,
+		CRUDLog: influxdb.CRUDLog{
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+	}
+	if err := s.put(ctx, b); err != nil {
+		return nil, err
+	}
+
+	s.start(b)
+	return b, nil
+}
+
+// FindBackfillByID returns a single backfill of the task.
+func (s *Service) FindBackfillByID(ctx context.Context, taskID, id influxdb.ID) (*influxdb.Backfill, error) {
+	key, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidBackfillID(err)
+	}
+
+	var b *influxdb.Backfill
+	err = s.store.View(ctx, func(tx kv.Tx) error {
+		bkt, err := tx.Bucket(backfillBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		v, err := bkt.Get(key)
+		if kv.IsNotFound(err) {
+			return ErrBackfillNotFound
+		}
+		if err != nil {
+			return ErrInternalService(err)
+		}
+
+		b = &influxdb.Backfill{}
+		if err := json.Unmarshal(v, b); err != nil {
+			return ErrInternalService(err)
+		}
+		if b.TaskID != taskID {
+			return ErrBackfillNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// FindBackfills returns the backfills of the task.
+func (s *Service) FindBackfills(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Backfill, error) {
+	return s.findBackfills(ctx, func(b *influxdb.Backfill) bool {
+		return b.TaskID == taskID
+	})
+}
+
+func (s *Service) findBackfills(ctx context.Context, filter func(*influxdb.Backfill) bool) ([]*influxdb.Backfill, error) {
+	bs := []*influxdb.Backfill{}
+	err := s.store.View(ctx, func(tx kv.Tx) error {
+		bkt, err := tx.Bucket(backfillBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		cur, err := bkt.ForwardCursor(nil)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		defer cur.Close()
+
+		for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+			b := &influxdb.Backfill{}
+			if err := json.Unmarshal(v, b); err != nil {
+				return ErrInternalService(err)
+			}
+			if filter(b) {
+				bs = append(bs, b)
+			}
+		}
+		return cur.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
+// CancelBackfill stops a running backfill and cancels its runs in progress.
+func (s *Service) CancelBackfill(ctx context.Context, taskID, id influxdb.ID) (*influxdb.Backfill, error) {
+	b, err := s.FindBackfillByID(ctx, taskID, id)
+	if err != nil {
+		return nil, err
+	}
+	if b.Done() {
+		return nil, ErrNotRunning
+	}
+
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	s.mu.Unlock()
+	if !ok {
+		// the backfill is not running in this process, nothing but its status is left to change
+		b.Status = influxdb.BackfillStatusCanceled
+		b.UpdatedAt = s.now().UTC()
+		if err := s.put(ctx, b); err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+
+	j.cancel()
+	select {
+	case <-j.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return s.FindBackfillByID(ctx, taskID, id)
+}
+
+// Resume restarts the backfills that were running when the server stopped.
+// They continue after the latest run they started.
+func (s *Service) Resume(ctx context.Context) error {
+	bs, err := s.findBackfills(ctx, func(b *influxdb.Backfill) bool {
+		return !b.Done()
+	})
+	if err != nil {
+		return err
+	}
+	for _, b := range bs {
+		s.start(b)
+	}
+	return nil
+}
+
+func (s *Service) start(b *influxdb.Backfill) {
+	// the backfill is updated as it runs, callers keep their own copy
+	bf := *b
+	b = &bf
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &job{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	s.jobs[b.ID] = j
+	s.mu.Unlock()
+
+	go func() {
+		defer close(j.done)
+		defer cancel()
+
+		s.run(ctx, b)
+
+		s.mu.Lock()
+		delete(s.jobs, b.ID)
+		s.mu.Unlock()
+	}()
+}
+
+// run starts a run for every scheduled time of the backfill after the latest
+// one it started, waits for them to finish and records the outcome.
+func (s *Service) run(ctx context.Context, b *influxdb.Backfill) {
+	log := s.log.With(zap.String("backfillID", b.ID.String()), zap.String("taskID", b.TaskID.String()))
+
+	var (
+		mu      sync.Mutex
+		runs    sync.WaitGroup
+		failure error
+	)
+	update := func(fn func()) {
+		mu.Lock()
+		defer mu.Unlock()
+		fn()
+		b.UpdatedAt = s.now().UTC()
+		if err := s.put(context.Background(), b); err != nil {
+			log.Error("Failed to update backfill", zap.Error(err))
+		}
+	}
+
+	failure = func() error {
+		t, err := s.tasks.FindTaskByID(ctx, b.TaskID)
+		if err != nil {
+			return err
+		}
+
+		after := b.Start.Add(-time.Second)
+		if !b.LatestScheduled.IsZero() {
+			after = b.LatestScheduled
+		}
+		sch, from, err := scheduler.NewSchedule(t.EffectiveCron(), after)
+		if err != nil {
+			return ErrInvalidSchedule(err)
+		}
+
+		// limit the runs in progress to the concurrency of the task
+		limit := make(chan struct{}, s.concurrency(t))
+		for {
+			next, err := sch.Next(from)
+			if err != nil {
+				return ErrInvalidSchedule(err)
+			}
+			if next.After(b.End) {
+				return nil
+			}
+			from = next
+
+			select {
+			case limit <- struct{}{}:
+			case <-ctx.Done():
+				return nil
+			}
+
+			p, err := s.executor.PromisedExecute(ctx, scheduler.ID(b.TaskID), next, next.Add(t.Offset))
+			if err != nil {
+				<-limit
+				return err
+			}
+			update(func() { b.LatestScheduled = next })
+
+			runs.Add(1)
+			go func() {
+				defer runs.Done()
+				<-p.Done()
+				update(func() {
+					if p.Error() != nil {
+						b.Failed++
+					} else {
+						b.Completed++
+					}
+				})
+				<-limit
+			}()
+		}
+	}()
+
+	runs.Wait()
+
+	update(func() {
+		switch {
+		case ctx.Err() != nil:
+			b.Status = influxdb.BackfillStatusCanceled
+		case failure != nil:
+			b.Status = influxdb.BackfillStatusFailed
+			b.Error = failure.Error()
+			log.Info("Backfill failed", zap.Error(failure))
+		default:
+			b.Status = influxdb.BackfillStatusCompleted
+		}
+	})
+}
+
+// concurrency returns the number of runs of the task allowed in progress at once.
+func (s *Service) concurrency(t *influxdb.Task) int {
+	if s.lang == nil {
+		return 1
+	}
+	o, err := options.FromScript(s.lang, t.Flux)
+	if err != nil || o.Concurrency == nil || *o.Concurrency < 1 {
+		return 1
+	}
+	return int(*o.Concurrency)
+}
+
+func (s *Service) put(ctx context.Context, b *influxdb.Backfill) error {
+	key, err := b.ID.Encode()
+	if err != nil {
+		return ErrInvalidBackfillID(err)
+	}
+	v, err := json.Marshal(b)
+	if err != nil {
+		return ErrInternalService(err)
+	}
+
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		bkt, err := tx.Bucket(backfillBucket)
+		if err != nil {
+			return ErrInternalService(err)
+		}
+		if err := bkt.Put(key, v); err != nil {
+			return ErrInternalService(err)
+		}
+		return nil
+	})
+}
+
+// countRuns returns the number of times the schedule of the task triggers on
+// from start to end, counting no further than one past MaxRuns.
+func countRuns(t *influxdb.Task, start, end time.Time) (int, error) {
+	sch, from, err := scheduler.NewSchedule(t.EffectiveCron(), start.Add(-time.Second))
+	if err != nil {
+		return 0, ErrInvalidSchedule(err)
+	}
+
+	var n int
+	for n <= MaxRuns {
+		next, err := sch.Next(from)
+		if err != nil {
+			return 0, ErrInvalidSchedule(err)
+		}
+		if next.After(end) {
+			break
+		}
+		from = next
+		n++
+	}
+	return n, nil
+}
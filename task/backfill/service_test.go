@@ -0,0 +1,212 @@
+package backfill_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kv/migration/all"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/task/backend/executor"
+	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
+	"github.com/influxdata/influxdb/v2/task/backfill"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// fakeExecutor finishes every run at once, failing the runs scheduled for the
+// times in fail, unless it is blocked.
+type fakeExecutor struct {
+	mu        sync.Mutex
+	scheduled []time.Time
+	runAt     []time.Time
+	fail      map[time.Time]bool
+	block     chan struct{}
+}
+
+func (e *fakeExecutor) PromisedExecute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) (executor.Promise, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.scheduled = append(e.scheduled, scheduledFor)
+	e.runAt = append(e.runAt, runAt)
+
+	p := &fakePromise{done: make(chan struct{})}
+	if e.fail[scheduledFor] {
+		p.err = errors.New("run failed")
+	}
+	go func() {
+		if e.block != nil {
+			select {
+			case <-e.block:
+			case <-ctx.Done():
+				p.err = influxdb.ErrRunCanceled
+			}
+		}
+		close(p.done)
+	}()
+	return p, nil
+}
+
+func (e *fakeExecutor) Scheduled() []time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]time.Time(nil), e.scheduled...)
+}
+
+func (e *fakeExecutor) RunAt() []time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]time.Time(nil), e.runAt...)
+}
+
+type fakePromise struct {
+	done chan struct{}
+	err  error
+}
+
+func (p *fakePromise) ID() influxdb.ID            { return 1 }
+func (p *fakePromise) Cancel(ctx context.Context) {}
+func (p *fakePromise) Done() <-chan struct{}      { return p.done }
+func (p *fakePromise) Error() error               { <-p.done; return p.err }
+
+func newTestService(t *testing.T, ex backfill.Executor, task *influxdb.Task) *backfill.Service {
+	t.Helper()
+
+	store := inmem.NewKVStore()
+	if err := all.Up(context.Background(), zaptest.NewLogger(t), store); err != nil {
+		t.Fatal(err)
+	}
+	ts := &mock.TaskService{
+		FindTaskByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+			if id != task.ID {
+				return nil, influxdb.ErrTaskNotFound
+			}
+			return task, nil
+		},
+	}
+	svc := backfill.NewService(zaptest.NewLogger(t), store, ts, ex, nil)
+	svc.IDGen = mock.NewIncrementingIDGenerator(1)
+	return svc
+}
+
+func waitDone(t *testing.T, svc *backfill.Service, taskID, id influxdb.ID) *influxdb.Backfill {
+	t.Helper()
+
+	for i := 0; i < 100; i++ {
+		b, err := svc.FindBackfillByID(context.Background(), taskID, id)
+		require.NoError(t, err)
+		if b.Done() {
+			return b
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("backfill did not finish")
+	return nil
+}
+
+func TestService_CreateBackfill(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	task := &influxdb.Task{ID: 10, OrganizationID: 20, Status: influxdb.TaskStatusActive, Every: "1h"}
+	ex := &fakeExecutor{fail: map[time.Time]bool{start.Add(2 * time.Hour): true}}
+	svc := newTestService(t, ex, task)
+
+	_, err := svc.CreateBackfill(ctx, task.ID, start, start)
+	assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+
+	_, err = svc.CreateBackfill(ctx, task.ID, start.Add(time.Minute), start.Add(30*time.Minute))
+	assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+
+	_, err = svc.CreateBackfill(ctx, task.ID, start, start.Add((backfill.MaxRuns+1)*time.Hour))
+	assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+
+	// both the start and the end of the range are scheduled.
+	b, err := svc.CreateBackfill(ctx, task.ID, start, start.Add(4*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, influxdb.ID(20), b.OrganizationID)
+	assert.Equal(t, 5, b.Total)
+
+	b = waitDone(t, svc, task.ID, b.ID)
+	assert.Equal(t, influxdb.BackfillStatusCompleted, b.Status)
+	assert.Equal(t, 4, b.Completed)
+	assert.Equal(t, 1, b.Failed)
+	assert.Equal(t, start.Add(4*time.Hour), b.LatestScheduled)
+	assert.Equal(t, []time.Time{
+		start,
+		start.Add(time.Hour),
+		start.Add(2 * time.Hour),
+		start.Add(3 * time.Hour),
+		start.Add(4 * time.Hour),
+	}, ex.Scheduled())
+
+	bs, err := svc.FindBackfills(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, bs, 1)
+	assert.Equal(t, b.ID, bs[0].ID)
+
+	bs, err = svc.FindBackfills(ctx, 99)
+	require.NoError(t, err)
+	assert.Len(t, bs, 0)
+
+	_, err = svc.FindBackfillByID(ctx, 99, b.ID)
+	assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+
+	_, err = svc.CancelBackfill(ctx, task.ID, b.ID)
+	assert.Equal(t, influxdb.EConflict, influxdb.ErrorCode(err))
+}
+
+func TestService_CreateBackfill_Offset(t *testing.T) {
+	ctx := context.Background()
+	hour := time.Now().UTC().Truncate(time.Hour)
+	task := &influxdb.Task{ID: 10, OrganizationID: 20, Status: influxdb.TaskStatusActive, Every: "1h", Offset: 90 * time.Minute}
+	ex := &fakeExecutor{}
+	svc := newTestService(t, ex, task)
+
+	// the offset has not passed for the last two hours, the scheduler runs them.
+	b, err := svc.CreateBackfill(ctx, task.ID, hour.Add(-3*time.Hour), hour)
+	require.NoError(t, err)
+	assert.Equal(t, 2, b.Total)
+
+	b = waitDone(t, svc, task.ID, b.ID)
+	assert.Equal(t, influxdb.BackfillStatusCompleted, b.Status)
+	assert.Equal(t, []time.Time{hour.Add(-3 * time.Hour), hour.Add(-2 * time.Hour)}, ex.Scheduled())
+	assert.Equal(t, []time.Time{hour.Add(-90 * time.Minute), hour.Add(-30 * time.Minute)}, ex.RunAt())
+
+	_, err = svc.CreateBackfill(ctx, task.ID, hour.Add(-time.Hour), hour)
+	assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+}
+
+func TestService_CreateBackfill_InactiveTask(t *testing.T) {
+	task := &influxdb.Task{ID: 10, OrganizationID: 20, Status: influxdb.TaskStatusInactive, Every: "1h"}
+	svc := newTestService(t, &fakeExecutor{}, task)
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := svc.CreateBackfill(context.Background(), task.ID, start, start.Add(time.Hour))
+	assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+}
+
+func TestService_CancelBackfill(t *testing.T) {
+	ctx := context.Background()
+	task := &influxdb.Task{ID: 10, OrganizationID: 20, Status: influxdb.TaskStatusActive, Every: "1m"}
+	ex := &fakeExecutor{block: make(chan struct{})}
+	svc := newTestService(t, ex, task)
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	b, err := svc.CreateBackfill(ctx, task.ID, start, start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 61, b.Total)
+
+	// without a concurrency option a single run is in progress at once.
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, ex.Scheduled(), 1)
+
+	b, err = svc.CancelBackfill(ctx, task.ID, b.ID)
+	require.NoError(t, err)
+	assert.Equal(t, influxdb.BackfillStatusCanceled, b.Status)
+	assert.Len(t, ex.Scheduled(), 1)
+}
@@ -73,6 +73,19 @@ func (t *TaskControlService) CreateRun(_ context.Context, taskID influxdb.ID, sc
 	return runs[runID], nil
 }
 
+func (t *TaskControlService) CreateRetryRun(ctx context.Context, taskID, retryOf influxdb.ID, attempt int, scheduledFor time.Time, runAt time.Time) (*influxdb.Run, error) {
+	r, err := t.CreateRun(ctx, taskID, scheduledFor, runAt)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	r.RetryOf = retryOf
+	r.Attempt = attempt
+	return r, nil
+}
+
 func (t *TaskControlService) StartManualRun(_ context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -141,7 +154,7 @@ func (d *TaskControlService) UpdateRunState(ctx context.Context, taskID, runID i
 	switch state {
 	case influxdb.RunStarted:
 		run.StartedAt = when
-	case influxdb.RunSuccess, influxdb.RunFail, influxdb.RunCanceled:
+	case influxdb.RunSuccess, influxdb.RunFail, influxdb.RunCanceled, influxdb.RunTimeout:
 		run.FinishedAt = when
 	case influxdb.RunScheduled:
 		// nothing
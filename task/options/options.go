@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -40,6 +41,22 @@ type Options struct {
 	Concurrency *int64 `json:"concurrency,omitempty"`
 
 	Retry *int64 `json:"retry,omitempty"`
+
+	// Trigger is the name of a bucket, writes to which run the task.
+	// A task with a trigger may leave out cron and every.
+	Trigger string `json:"trigger,omitempty"`
+
+	// Debounce is how long writes to the trigger bucket are gathered before the task runs,
+	// it defaults to 10s.
+	Debounce *Duration `json:"debounce,omitempty"`
+
+	// Timeout is how long a run of the task may take before it is canceled.
+	Timeout *Duration `json:"timeout,omitempty"`
+
+	// Catchup is which of the runs the task missed while it wasn't scheduled,
+	// e.g. while influxd was down, are run: "all" of them, the "latest", "none"
+	// or the latest N with "max N". It defaults to all.
+	Catchup string `json:"catchup,omitempty"`
 }
 
 // Duration is a time span that supports the same units as the flux parser's time duration, as well as negative length time spans.
@@ -118,6 +135,10 @@ func (o *Options) Clear() {
 	o.Offset = nil
 	o.Concurrency = nil
 	o.Retry = nil
+	o.Trigger = ""
+	o.Debounce = nil
+	o.Timeout = nil
+	o.Catchup = ""
 }
 
 // IsZero tells us if the options has been zeroed out.
@@ -127,7 +148,11 @@ func (o *Options) IsZero() bool {
 		o.Every.IsZero() &&
 		(o.Offset == nil || o.Offset.IsZero()) &&
 		o.Concurrency == nil &&
-		o.Retry == nil
+		o.Retry == nil &&
+		o.Trigger == "" &&
+		(o.Debounce == nil || o.Debounce.IsZero()) &&
+		(o.Timeout == nil || o.Timeout.IsZero()) &&
+		o.Catchup == ""
 }
 
 // All the task option names we accept.
@@ -138,8 +163,38 @@ const (
 	optOffset      = "offset"
 	optConcurrency = "concurrency"
 	optRetry       = "retry"
+	optTrigger     = "trigger"
+	optDebounce    = "debounce"
+	optTimeout     = "timeout"
+	optCatchup     = "catchup"
 )
 
+// The catch-up policies of tasks, besides "max N".
+const (
+	CatchupAll    = "all"
+	CatchupLatest = "latest"
+	CatchupNone   = "none"
+)
+
+// ParseCatchup returns how many of the latest missed runs the catch-up
+// policy runs, -1 when it runs all of them.
+func ParseCatchup(policy string) (int, error) {
+	switch policy {
+	case "", CatchupAll:
+		return -1, nil
+	case CatchupLatest:
+		return 1, nil
+	case CatchupNone:
+		return 0, nil
+	}
+	if strings.HasPrefix(policy, "max ") {
+		if n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(policy, "max "))); err == nil && n >= 0 {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("catchup must be %q, %q, %q or \"max N\", got %q", CatchupAll, CatchupLatest, CatchupNone, policy)
+}
+
 // contains is a helper function to see if an array of strings contains a string
 func contains(s []string, e string) bool {
 	for i := range s {
@@ -151,7 +206,7 @@ func contains(s []string, e string) bool {
 }
 
 func grabTaskOptionAST(p *ast.Package, keys ...string) map[string]ast.Expression {
-	res := make(map[string]ast.Expression, 2) // we preallocate two keys for the map, as that is how many we will use at maximum (offset and every)
+	res := make(map[string]ast.Expression, len(keys))
 	for i := range p.Files {
 		for j := range p.Files[i].Body {
 			if p.Files[i].Body[j].Type() != "OptionStatement" {
@@ -281,6 +336,9 @@ var taskOptionExtractors = []extractFn{
 	extractOffsetOption,
 	extractConcurrencyOption,
 	extractRetryOption,
+	extractTriggerOptions,
+	extractTimeoutOption,
+	extractCatchupOption,
 }
 
 func extractNameOption(opts *Options, objExpr *ast.ObjectExpression) error {
@@ -304,6 +362,10 @@ func extractScheduleOptions(opts *Options, objExpr *ast.ObjectExpression) error
 		return ErrDuplicateIntervalField
 	}
 	if cronErr != nil && everyErr != nil {
+		if _, err := edit.GetProperty(objExpr, optTrigger); err == nil {
+			// write triggered tasks need no schedule
+			return nil
+		}
 		return errMissingRequiredTaskOption("cron or every")
 	}
 
@@ -364,6 +426,52 @@ func extractConcurrencyOption(opts *Options, objExpr *ast.ObjectExpression) erro
 	return nil
 }
 
+func extractTriggerOptions(opts *Options, objExpr *ast.ObjectExpression) error {
+	if triggerExpr, err := edit.GetProperty(objExpr, optTrigger); err == nil {
+		triggerStr, ok := triggerExpr.(*ast.StringLiteral)
+		if !ok {
+			return errParseTaskOptionField(optTrigger)
+		}
+		opts.Trigger = ast.StringFromLiteral(triggerStr)
+	}
+
+	if debounceExpr, err := edit.GetProperty(objExpr, optDebounce); err == nil {
+		debounceDur, ok := debounceExpr.(*ast.DurationLiteral)
+		if !ok {
+			return errParseTaskOptionField(optDebounce)
+		}
+		opts.Debounce = &Duration{Node: *debounceDur}
+	}
+
+	return nil
+}
+
+func extractTimeoutOption(opts *Options, objExpr *ast.ObjectExpression) error {
+	timeoutExpr, err := edit.GetProperty(objExpr, optTimeout)
+	if err != nil {
+		return nil
+	}
+	timeoutDur, ok := timeoutExpr.(*ast.DurationLiteral)
+	if !ok {
+		return errParseTaskOptionField(optTimeout)
+	}
+	opts.Timeout = &Duration{Node: *timeoutDur}
+	return nil
+}
+
+func extractCatchupOption(opts *Options, objExpr *ast.ObjectExpression) error {
+	catchupExpr, err := edit.GetProperty(objExpr, optCatchup)
+	if err != nil {
+		return nil
+	}
+	catchupStr, ok := catchupExpr.(*ast.StringLiteral)
+	if !ok {
+		return errParseTaskOptionField(optCatchup)
+	}
+	opts.Catchup = ast.StringFromLiteral(catchupStr)
+	return nil
+}
+
 func extractRetryOption(opts *Options, objExpr *ast.ObjectExpression) error {
 	retryExpr, err := edit.GetProperty(objExpr, optRetry)
 	if err != nil {
@@ -388,7 +496,7 @@ func FromScript(lang FluxLanguageService, script string) (Options, error) {
 	if err != nil {
 		return opt, err
 	}
-	durTypes := grabTaskOptionAST(fluxAST, optEvery, optOffset)
+	durTypes := grabTaskOptionAST(fluxAST, optEvery, optOffset, optDebounce, optTimeout)
 	// TODO(desa): should be dependencies.NewEmpty(), but for now we'll hack things together
 	ctx := newDeps().Inject(context.Background())
 	_, scope, err := evalAST(ctx, lang, fluxAST)
@@ -425,7 +533,8 @@ func FromScript(lang FluxLanguageService, script string) (Options, error) {
 		return opt, ErrDuplicateIntervalField
 	}
 
-	if !cronOK && !everyOK {
+	triggerVal, triggerOK := optObject.Get(optTrigger)
+	if !cronOK && !everyOK && !triggerOK {
 		return opt, errMissingRequiredTaskOption("cron or every is required")
 	}
 
@@ -491,6 +600,52 @@ func FromScript(lang FluxLanguageService, script string) (Options, error) {
 		opt.Retry = pointer.Int64(retryVal.Int())
 	}
 
+	if triggerOK {
+		if err := checkNature(triggerVal.Type().Nature(), semantic.String); err != nil {
+			return opt, err
+		}
+		opt.Trigger = triggerVal.Str()
+	}
+
+	if debounceVal, ok := optObject.Get(optDebounce); ok {
+		if err := checkNature(debounceVal.Type().Nature(), semantic.Duration); err != nil {
+			return opt, err
+		}
+		dur, ok := durTypes[optDebounce]
+		if !ok || dur == nil {
+			return opt, errParseTaskOptionField(optDebounce)
+		}
+		durNode, err := ParseSignedDuration(dur.Location().Source)
+		if err != nil {
+			return opt, err
+		}
+		durNode.BaseNode = ast.BaseNode{}
+		opt.Debounce = &Duration{Node: *durNode}
+	}
+
+	if timeoutVal, ok := optObject.Get(optTimeout); ok {
+		if err := checkNature(timeoutVal.Type().Nature(), semantic.Duration); err != nil {
+			return opt, err
+		}
+		dur, ok := durTypes[optTimeout]
+		if !ok || dur == nil {
+			return opt, errParseTaskOptionField(optTimeout)
+		}
+		durNode, err := ParseSignedDuration(dur.Location().Source)
+		if err != nil {
+			return opt, err
+		}
+		durNode.BaseNode = ast.BaseNode{}
+		opt.Timeout = &Duration{Node: *durNode}
+	}
+
+	if catchupVal, ok := optObject.Get(optCatchup); ok {
+		if err := checkNature(catchupVal.Type().Nature(), semantic.String); err != nil {
+			return opt, err
+		}
+		opt.Catchup = catchupVal.Str()
+	}
+
 	if err := opt.Validate(); err != nil {
 		return opt, err
 	}
@@ -508,8 +663,8 @@ func (o *Options) Validate() error {
 
 	cronPresent := o.Cron != ""
 	everyPresent := !o.Every.IsZero()
-	if cronPresent == everyPresent {
-		// They're both present or both missing.
+	if cronPresent && everyPresent || (!cronPresent && !everyPresent && o.Trigger == "") {
+		// They're both present or both missing, without a trigger to run the task instead.
 		errs = append(errs, "must specify exactly one of either cron or every")
 	} else if cronPresent {
 		_, err := cron.ParseUTC(o.Cron)
@@ -537,6 +692,39 @@ func (o *Options) Validate() error {
 			errs = append(errs, "offset option must be expressible as whole seconds")
 		}
 	}
+	if o.Debounce != nil {
+		if o.Trigger == "" {
+			errs = append(errs, "debounce requires a trigger")
+		}
+		debounce, err := o.Debounce.DurationFrom(now)
+		if err != nil {
+			return err
+		}
+		if debounce < time.Second {
+			errs = append(errs, "debounce option must be at least 1 second")
+		} else if debounce.Truncate(time.Second) != debounce {
+			errs = append(errs, "debounce option must be expressible as whole seconds")
+		}
+	}
+	if o.Timeout != nil {
+		timeout, err := o.Timeout.DurationFrom(now)
+		if err != nil {
+			return err
+		}
+		if timeout < time.Second {
+			errs = append(errs, "timeout option must be at least 1 second")
+		} else if timeout.Truncate(time.Second) != timeout {
+			errs = append(errs, "timeout option must be expressible as whole seconds")
+		}
+	}
+	if o.Catchup != "" {
+		if !cronPresent && !everyPresent {
+			errs = append(errs, "catchup requires cron or every")
+		}
+		if _, err := ParseCatchup(o.Catchup); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
 	if o.Concurrency != nil {
 		if *o.Concurrency < 1 {
 			errs = append(errs, "concurrency must be at least 1")
@@ -592,7 +780,7 @@ func validateOptionNames(o values.Object) error {
 	var unexpected []string
 	o.Range(func(name string, _ values.Value) {
 		switch name {
-		case optName, optCron, optEvery, optOffset, optConcurrency, optRetry:
+		case optName, optCron, optEvery, optOffset, optConcurrency, optRetry, optTrigger, optDebounce, optTimeout, optCatchup:
 			// Known option. Nothing to do.
 		default:
 			unexpected = append(unexpected, name)
@@ -601,7 +789,7 @@ func validateOptionNames(o values.Object) error {
 
 	if len(unexpected) > 0 {
 		u := strings.Join(unexpected, ", ")
-		v := strings.Join([]string{optName, optCron, optEvery, optOffset, optConcurrency, optRetry}, ", ")
+		v := strings.Join([]string{optName, optCron, optEvery, optOffset, optConcurrency, optRetry, optTrigger, optDebounce, optTimeout, optCatchup}, ", ")
 		return fmt.Errorf("unknown task option(s): %s. valid options are %s", u, v)
 	}
 
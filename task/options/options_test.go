@@ -36,6 +36,18 @@ func scriptGenerator(opt options.Options, body string) string {
 	if opt.Retry != nil && *opt.Retry != 0 {
 		taskData = fmt.Sprintf("%s  retry: %d,\n", taskData, *opt.Retry)
 	}
+	if opt.Trigger != "" {
+		taskData = fmt.Sprintf("%s  trigger: %q,\n", taskData, opt.Trigger)
+	}
+	if opt.Debounce != nil && !(*opt.Debounce).IsZero() {
+		taskData = fmt.Sprintf("%s  debounce: %s,\n", taskData, opt.Debounce.String())
+	}
+	if opt.Timeout != nil && !(*opt.Timeout).IsZero() {
+		taskData = fmt.Sprintf("%s  timeout: %s,\n", taskData, opt.Timeout.String())
+	}
+	if opt.Catchup != "" {
+		taskData = fmt.Sprintf("%s  catchup: %q,\n", taskData, opt.Catchup)
+	}
 	if body == "" {
 		body = `from(bucket: "test")
     |> range(start:-1h)`
@@ -81,6 +93,16 @@ func TestFromScriptAST(t *testing.T) {
 		{script: "option task = {\n  name: \"name8\",\n  retry: 0,\n  every: 1m0s,\n\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
 		{script: scriptGenerator(options.Options{Name: "name9"}, ""), shouldErr: true},
 		{script: scriptGenerator(options.Options{}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name12", Trigger: "raw", Debounce: options.MustParseDuration("30s")}, ""),
+			exp: options.Options{Name: "name12", Trigger: "raw", Debounce: options.MustParseDuration("30s"), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name13", Every: *(options.MustParseDuration("1h")), Trigger: "raw"}, ""),
+			exp: options.Options{Name: "name13", Every: *(options.MustParseDuration("1h")), Trigger: "raw", Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name14", Every: *(options.MustParseDuration("1h")), Debounce: options.MustParseDuration("30s")}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name15", Every: *(options.MustParseDuration("1h")), Timeout: options.MustParseDuration("5m")}, ""),
+			exp: options.Options{Name: "name15", Every: *(options.MustParseDuration("1h")), Timeout: options.MustParseDuration("5m"), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name16", Every: *(options.MustParseDuration("1h")), Catchup: "max 3"}, ""),
+			exp: options.Options{Name: "name16", Every: *(options.MustParseDuration("1h")), Catchup: "max 3", Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name17", Every: *(options.MustParseDuration("1h")), Catchup: "some"}, ""), shouldErr: true},
 		{script: `option task = {
 			name: "name10",
 			every: 1d,
@@ -147,6 +169,16 @@ func TestFromScript(t *testing.T) {
 		{script: "option task = {\n  name: \"name8\",\n  retry: 0,\n  every: 1m0s,\n\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
 		{script: scriptGenerator(options.Options{Name: "name9"}, ""), shouldErr: true},
 		{script: scriptGenerator(options.Options{}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name12", Trigger: "raw", Debounce: options.MustParseDuration("30s")}, ""),
+			exp: options.Options{Name: "name12", Trigger: "raw", Debounce: options.MustParseDuration("30s"), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name13", Every: *(options.MustParseDuration("1h")), Trigger: "raw"}, ""),
+			exp: options.Options{Name: "name13", Every: *(options.MustParseDuration("1h")), Trigger: "raw", Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name14", Every: *(options.MustParseDuration("1h")), Debounce: options.MustParseDuration("30s")}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name15", Every: *(options.MustParseDuration("1h")), Timeout: options.MustParseDuration("5m")}, ""),
+			exp: options.Options{Name: "name15", Every: *(options.MustParseDuration("1h")), Timeout: options.MustParseDuration("5m"), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name16", Every: *(options.MustParseDuration("1h")), Catchup: "max 3"}, ""),
+			exp: options.Options{Name: "name16", Every: *(options.MustParseDuration("1h")), Catchup: "max 3", Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name17", Every: *(options.MustParseDuration("1h")), Catchup: "some"}, ""), shouldErr: true},
 		{script: `option task = {
 			name: "name10",
 			every: 1d,
@@ -223,6 +255,29 @@ func TestFromScriptWithUnknownOptions(t *testing.T) {
 	}
 }
 
+func TestParseCatchup(t *testing.T) {
+	for policy, exp := range map[string]int{
+		"":       -1,
+		"all":    -1,
+		"latest": 1,
+		"none":   0,
+		"max 5":  5,
+	} {
+		n, err := options.ParseCatchup(policy)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", policy, err)
+		} else if n != exp {
+			t.Errorf("expected %d runs for %q, got %d", exp, policy, n)
+		}
+	}
+
+	for _, policy := range []string{"first", "max", "max -1", "max x"} {
+		if _, err := options.ParseCatchup(policy); err == nil {
+			t.Errorf("expected error for %q", policy)
+		}
+	}
+}
+
 func TestValidate(t *testing.T) {
 	good := options.Options{Name: "x", Cron: "* * * * *", Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}
 	if err := good.Validate(); err != nil {
@@ -267,6 +322,32 @@ func TestValidate(t *testing.T) {
 		t.Error("expected error for sub-second delay resolution")
 	}
 
+	*bad = good
+	bad.Cron = ""
+	bad.Trigger = "raw"
+	bad.Debounce = options.MustParseDuration("1500ms")
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for sub-second debounce resolution")
+	}
+
+	*bad = good
+	bad.Debounce = options.MustParseDuration("10s")
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for debounce without trigger")
+	}
+
+	*bad = good
+	bad.Timeout = options.MustParseDuration("500ms")
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for sub-second timeout")
+	}
+
+	*bad = good
+	bad.Catchup = "max -1"
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for negative catchup")
+	}
+
 	*bad = good
 	bad.Concurrency = pointer.Int64(0)
 	if err := bad.Validate(); err == nil {
@@ -299,6 +380,13 @@ func TestValidate(t *testing.T) {
 		t.Error("expected no error for days every")
 	}
 
+	*notbad = good
+	notbad.Cron = ""
+	notbad.Trigger = "raw"
+	if err := notbad.Validate(); err != nil {
+		t.Error("expected no error for trigger without cron or every")
+	}
+
 }
 
 func TestEffectiveCronString(t *testing.T) {
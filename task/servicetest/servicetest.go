@@ -294,6 +294,7 @@ func testTaskCRUD(t *testing.T, sys *System) {
 		Status:          string(influxdb.DefaultTaskStatus),
 		Flux:            fmt.Sprintf(scriptFmt, 0),
 		Type:            influxdb.TaskSystemType,
+		Revision:        1,
 	}
 
 	for fn, f := range found {
@@ -0,0 +1,240 @@
+package trigger
+
+// The trigger `Service` runs tasks when points are written to their trigger
+// bucket. It listens to the writes of the storage points writer, which must
+// not be held up, so writes are only recorded when notified and matched
+// against tasks in the background by `Run`. Writes are recorded from the time
+// the service is created, before the task service and the executor it runs
+// tasks with are passed to `Run`. The first write to the trigger
+// bucket of a task starts its debounce period, the time range of the writes
+// gathered during the period is passed to the run that starts at its end.
+// Writes made by the runs of a task do not trigger the task itself.
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/storage"
+	"github.com/influxdata/influxdb/v2/task/backend/executor"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultDebounce is the debounce period of tasks without the debounce option.
+	DefaultDebounce = 10 * time.Second
+
+	// DefaultRefreshInterval is how long the triggered tasks of an organization are cached.
+	DefaultRefreshInterval = 10 * time.Second
+)
+
+var _ storage.BucketWriteListener = (*Service)(nil)
+
+// Executor executes the runs of triggered tasks.
+type Executor interface {
+	TriggeredExecute(ctx context.Context, id influxdb.ID, scheduledFor, start, stop time.Time) (executor.Promise, error)
+}
+
+// Service runs tasks on writes to their trigger bucket.
+type Service struct {
+	log     *zap.Logger
+	buckets influxdb.BucketService
+
+	// tasks and executor are set by Run.
+	tasks    influxdb.TaskService
+	executor Executor
+
+	// RefreshInterval is how long the triggered tasks of an organization are cached.
+	RefreshInterval time.Duration
+	now             func() time.Time
+
+	mu      sync.Mutex
+	writes  map[bucketKey]timeRange
+	pending map[influxdb.ID]*timeRange
+	signal  chan struct{}
+
+	// orgs is only used by Run.
+	orgs map[influxdb.ID]*orgTasks
+}
+
+// bucketKey is the bucket written to, along with the task whose run wrote
+// to it, if any.
+type bucketKey struct {
+	orgID, bucketID, taskID influxdb.ID
+}
+
+// timeRange is the time range of written points, both ends are inclusive.
+type timeRange struct {
+	min, max time.Time
+}
+
+func (r *timeRange) merge(o timeRange) {
+	if o.min.Before(r.min) {
+		r.min = o.min
+	}
+	if o.max.After(r.max) {
+		r.max = o.max
+	}
+}
+
+// orgTasks are the active triggered tasks of an organization by trigger bucket.
+type orgTasks struct {
+	loadedAt time.Time
+	byBucket map[influxdb.ID][]*influxdb.Task
+}
+
+// NewService returns a trigger service recording the writes to buckets.
+func NewService(log *zap.Logger, buckets influxdb.BucketService) *Service {
+	return &Service{
+		log:             log,
+		buckets:         buckets,
+		RefreshInterval: DefaultRefreshInterval,
+		now:             time.Now,
+		writes:          make(map[bucketKey]timeRange),
+		pending:         make(map[influxdb.ID]*timeRange),
+		signal:          make(chan struct{}, 1),
+		orgs:            make(map[influxdb.ID]*orgTasks),
+	}
+}
+
+// BucketWritten records the write for Run to match against triggered tasks.
+func (s *Service) BucketWritten(ctx context.Context, orgID, bucketID influxdb.ID, min, max time.Time) {
+	k := bucketKey{orgID: orgID, bucketID: bucketID}
+	if id, ok := icontext.GetTaskID(ctx); ok {
+		k.taskID = id
+	}
+	w := timeRange{min: min, max: max}
+
+	s.mu.Lock()
+	if prev, ok := s.writes[k]; ok {
+		w.merge(prev)
+	}
+	s.writes[k] = w
+	s.mu.Unlock()
+
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+// Run matches the recorded writes against the triggered tasks of the task
+// service, and runs them with the executor, until the context is done.
+func (s *Service) Run(ctx context.Context, tasks influxdb.TaskService, ex Executor) {
+	s.tasks = tasks
+	s.executor = ex
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.signal:
+			s.dispatch(ctx)
+		}
+	}
+}
+
+// dispatch passes the recorded writes on to the tasks triggered by them.
+func (s *Service) dispatch(ctx context.Context) {
+	s.mu.Lock()
+	writes := s.writes
+	s.writes = make(map[bucketKey]timeRange)
+	s.mu.Unlock()
+
+	for k, w := range writes {
+		tasks, err := s.triggeredTasks(ctx, k.orgID)
+		if err != nil {
+			s.log.Error("Failed to find triggered tasks", zap.String("orgID", k.orgID.String()), zap.Error(err))
+			continue
+		}
+		for _, t := range tasks[k.bucketID] {
+			// a task writing to its own trigger bucket would run forever
+			if t.ID == k.taskID {
+				continue
+			}
+			s.written(ctx, t, w)
+		}
+	}
+}
+
+// written adds the write to the pending range of the task, starting its
+// debounce period when there was none.
+func (s *Service) written(ctx context.Context, t *influxdb.Task, w timeRange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.pending[t.ID]; ok {
+		r.merge(w)
+		return
+	}
+	s.pending[t.ID] = &w
+
+	debounce := t.Debounce
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+	id := t.ID
+	time.AfterFunc(debounce, func() {
+		s.execute(ctx, id)
+	})
+}
+
+// execute runs the task for the writes gathered during its debounce period.
+func (s *Service) execute(ctx context.Context, id influxdb.ID) {
+	s.mu.Lock()
+	r, ok := s.pending[id]
+	delete(s.pending, id)
+	s.mu.Unlock()
+	if !ok || ctx.Err() != nil {
+		return
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+	// the stop of the range passed to the task is exclusive
+	if _, err := s.executor.TriggeredExecute(ctx, id, now, r.min, r.max.Add(time.Nanosecond)); err != nil {
+		s.log.Error("Failed to execute triggered task", zap.String("taskID", id.String()), zap.Error(err))
+	}
+}
+
+// triggeredTasks returns the active triggered tasks of the organization by
+// trigger bucket, loading them when the cached ones are too old.
+func (s *Service) triggeredTasks(ctx context.Context, orgID influxdb.ID) (map[influxdb.ID][]*influxdb.Task, error) {
+	if o, ok := s.orgs[orgID]; ok && s.now().Sub(o.loadedAt) < s.RefreshInterval {
+		return o.byBucket, nil
+	}
+
+	byBucket := make(map[influxdb.ID][]*influxdb.Task)
+	status := influxdb.TaskStatusActive
+	filter := influxdb.TaskFilter{
+		OrganizationID: &orgID,
+		Status:         &status,
+		Limit:          influxdb.TaskMaxPageSize,
+	}
+	for {
+		tasks, _, err := s.tasks.FindTasks(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tasks {
+			if t.Trigger == "" || t.Status != influxdb.TaskStatusActive {
+				continue
+			}
+			b, err := s.buckets.FindBucketByName(ctx, orgID, t.Trigger)
+			if err != nil {
+				if influxdb.ErrorCode(err) == influxdb.ENotFound {
+					continue
+				}
+				return nil, err
+			}
+			byBucket[b.ID] = append(byBucket[b.ID], t)
+		}
+		if len(tasks) < filter.Limit {
+			break
+		}
+		filter.After = &tasks[len(tasks)-1].ID
+	}
+
+	s.orgs[orgID] = &orgTasks{loadedAt: s.now(), byBucket: byBucket}
+	return byBucket, nil
+}
@@ -0,0 +1,147 @@
+package trigger_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/task/backend/executor"
+	"github.com/influxdata/influxdb/v2/task/trigger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type triggeredRun struct {
+	taskID      influxdb.ID
+	start, stop time.Time
+}
+
+// fakeExecutor records the triggered runs.
+type fakeExecutor struct {
+	mu   sync.Mutex
+	runs []triggeredRun
+	ran  chan struct{}
+}
+
+func (e *fakeExecutor) TriggeredExecute(ctx context.Context, id influxdb.ID, scheduledFor, start, stop time.Time) (executor.Promise, error) {
+	e.mu.Lock()
+	e.runs = append(e.runs, triggeredRun{taskID: id, start: start, stop: stop})
+	e.mu.Unlock()
+	e.ran <- struct{}{}
+	return nil, nil
+}
+
+func (e *fakeExecutor) Runs() []triggeredRun {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]triggeredRun(nil), e.runs...)
+}
+
+const (
+	orgID       = influxdb.ID(1)
+	rawBucketID = influxdb.ID(10)
+	otherBucket = influxdb.ID(11)
+)
+
+func newTestService(t *testing.T, tasks ...*influxdb.Task) (*trigger.Service, influxdb.TaskService, *fakeExecutor) {
+	t.Helper()
+
+	ts := &mock.TaskService{
+		FindTasksFn: func(_ context.Context, f influxdb.TaskFilter) ([]*influxdb.Task, int, error) {
+			if f.OrganizationID == nil || *f.OrganizationID != orgID {
+				return nil, 0, nil
+			}
+			return tasks, len(tasks), nil
+		},
+	}
+	bs := mock.NewBucketService()
+	bs.FindBucketByNameFn = func(_ context.Context, _ influxdb.ID, name string) (*influxdb.Bucket, error) {
+		switch name {
+		case "raw":
+			return &influxdb.Bucket{ID: rawBucketID, Name: name}, nil
+		case "other":
+			return &influxdb.Bucket{ID: otherBucket, Name: name}, nil
+		}
+		return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "bucket not found"}
+	}
+
+	ex := &fakeExecutor{ran: make(chan struct{}, 10)}
+	return trigger.NewService(zaptest.NewLogger(t), bs), ts, ex
+}
+
+func waitRun(t *testing.T, ex *fakeExecutor) {
+	t.Helper()
+	select {
+	case <-ex.ran:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for triggered run")
+	}
+}
+
+func TestService_BucketWritten(t *testing.T) {
+	task := &influxdb.Task{ID: 100, OrganizationID: orgID, Status: influxdb.TaskStatusActive, Trigger: "raw", Debounce: 100 * time.Millisecond}
+	other := &influxdb.Task{ID: 101, OrganizationID: orgID, Status: influxdb.TaskStatusActive, Trigger: "other", Debounce: 100 * time.Millisecond}
+	scheduled := &influxdb.Task{ID: 102, OrganizationID: orgID, Status: influxdb.TaskStatusActive, Every: "1m"}
+	missing := &influxdb.Task{ID: 103, OrganizationID: orgID, Status: influxdb.TaskStatusActive, Trigger: "missing", Debounce: 100 * time.Millisecond}
+	svc, ts, ex := newTestService(t, task, other, scheduled, missing)
+
+	// writes are recorded before the service runs.
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc.BucketWritten(context.Background(), orgID, rawBucketID, t0.Add(time.Minute), t0.Add(2*time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.Run(ctx, ts, ex)
+
+	svc.BucketWritten(context.Background(), orgID, rawBucketID, t0, t0.Add(time.Minute))
+	// writes to buckets of other organizations trigger nothing
+	svc.BucketWritten(context.Background(), influxdb.ID(2), rawBucketID, t0, t0)
+	// writes of the runs of the task do not trigger the task
+	svc.BucketWritten(icontext.SetTaskID(context.Background(), task.ID), orgID, rawBucketID, t0.Add(-time.Hour), t0.Add(-time.Hour))
+
+	waitRun(t, ex)
+	select {
+	case <-ex.ran:
+		t.Fatal("expected a single triggered run")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	runs := ex.Runs()
+	require.Len(t, runs, 1)
+	assert.Equal(t, task.ID, runs[0].taskID)
+	assert.Equal(t, t0, runs[0].start)
+	assert.Equal(t, t0.Add(2*time.Minute+time.Nanosecond), runs[0].stop)
+
+	// a later write starts another debounce period
+	svc.BucketWritten(context.Background(), orgID, rawBucketID, t0.Add(time.Hour), t0.Add(time.Hour))
+	waitRun(t, ex)
+
+	runs = ex.Runs()
+	require.Len(t, runs, 2)
+	assert.Equal(t, task.ID, runs[1].taskID)
+	assert.Equal(t, t0.Add(time.Hour), runs[1].start)
+	assert.Equal(t, t0.Add(time.Hour+time.Nanosecond), runs[1].stop)
+}
+
+func TestService_BucketWritten_InactiveTask(t *testing.T) {
+	task := &influxdb.Task{ID: 100, OrganizationID: orgID, Status: influxdb.TaskStatusInactive, Trigger: "raw", Debounce: 10 * time.Millisecond}
+	svc, ts, ex := newTestService(t, task)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.Run(ctx, ts, ex)
+
+	now := time.Now()
+	svc.BucketWritten(context.Background(), orgID, rawBucketID, now, now)
+
+	select {
+	case <-ex.ran:
+		t.Fatal("expected inactive task not to run")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
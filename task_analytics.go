@@ -0,0 +1,128 @@
+package influxdb
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultTaskAnalyticsWindow is the time range task analytics cover when none is given.
+	DefaultTaskAnalyticsWindow = 30 * 24 * time.Hour
+
+	// MinTaskRunRetention is the shortest time the run history of an organization may be kept.
+	MinTaskRunRetention = time.Hour
+
+	// maxFailureReasons is the largest number of failure reasons in task analytics.
+	maxFailureReasons = 10
+)
+
+// TaskAnalyticsFilter selects the runs aggregated into task analytics.
+type TaskAnalyticsFilter struct {
+	OrganizationID ID
+	TaskID         *ID
+
+	// Start and Stop bound the time the runs started at, stop is exclusive.
+	Start time.Time
+	Stop  time.Time
+}
+
+// TaskAnalytics aggregates the finished runs of a task in a time range.
+type TaskAnalytics struct {
+	TaskID ID `json:"taskID"`
+
+	// Runs is the number of finished runs, by status in Statuses.
+	Runs        int            `json:"runs"`
+	Statuses    map[string]int `json:"statuses"`
+	SuccessRate float64        `json:"successRate"`
+
+	// Duration summarizes the time from the start to the end of the runs.
+	Duration DurationSummary `json:"duration"`
+	// QueueLatency summarizes the time runs waited between being due and starting.
+	QueueLatency DurationSummary `json:"queueLatency"`
+
+	// FailureReasons are the most frequent errors of the failed runs, most frequent first.
+	FailureReasons []FailureReason `json:"failureReasons"`
+}
+
+// DurationSummary summarizes a set of durations, in seconds.
+type DurationSummary struct {
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P95  float64 `json:"p95"`
+	Max  float64 `json:"max"`
+}
+
+// FailureReason is an error of failed runs and the number of runs it failed.
+type FailureReason struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// TaskRunRetention is how long the run history of the tasks of an organization is kept.
+type TaskRunRetention struct {
+	OrganizationID ID            `json:"orgID"`
+	Retention      time.Duration `json:"retention"`
+}
+
+// TaskAnalyticsService aggregates the run history of tasks.
+type TaskAnalyticsService interface {
+	// FindTaskAnalytics returns the analytics of the tasks matching the filter
+	// that have runs in its time range, ordered by task ID.
+	FindTaskAnalytics(ctx context.Context, filter TaskAnalyticsFilter) ([]*TaskAnalytics, error)
+
+	// FindTaskRunRetention returns how long the run history of the organization is kept.
+	FindTaskRunRetention(ctx context.Context, orgID ID) (*TaskRunRetention, error)
+
+	// UpdateTaskRunRetention changes how long the run history of the organization is kept.
+	UpdateTaskRunRetention(ctx context.Context, orgID ID, retention time.Duration) (*TaskRunRetention, error)
+}
+
+// NewTaskAnalytics returns the analytics of a task from the number of its
+// finished runs by status, the summaries of their durations and queue
+// latencies, and its failed runs, whose logs give the failure reasons.
+func NewTaskAnalytics(taskID ID, statuses map[string]int, duration, queueLatency DurationSummary, failed []*Run) *TaskAnalytics {
+	a := &TaskAnalytics{
+		TaskID:         taskID,
+		Statuses:       statuses,
+		Duration:       duration,
+		QueueLatency:   queueLatency,
+		FailureReasons: []FailureReason{},
+	}
+	for _, n := range statuses {
+		a.Runs += n
+	}
+	if a.Runs > 0 {
+		a.SuccessRate = float64(statuses[RunSuccess.String()]) / float64(a.Runs)
+	}
+
+	reasons := make(map[string]int)
+	for _, r := range failed {
+		reasons[r.failureReason()]++
+	}
+	for reason, n := range reasons {
+		a.FailureReasons = append(a.FailureReasons, FailureReason{Reason: reason, Count: n})
+	}
+	sort.Slice(a.FailureReasons, func(i, j int) bool {
+		if a.FailureReasons[i].Count != a.FailureReasons[j].Count {
+			return a.FailureReasons[i].Count > a.FailureReasons[j].Count
+		}
+		return a.FailureReasons[i].Reason < a.FailureReasons[j].Reason
+	})
+	if len(a.FailureReasons) > maxFailureReasons {
+		a.FailureReasons = a.FailureReasons[:maxFailureReasons]
+	}
+	return a
+}
+
+// failureReason returns the error of a failed run, the log message before the
+// last one, which only records the run completed.
+func (r *Run) failureReason() string {
+	switch {
+	case len(r.Log) > 1:
+		return r.Log[len(r.Log)-2].Message
+	case len(r.Log) > 0:
+		return r.Log[len(r.Log)-1].Message
+	}
+	return "unknown"
+}
@@ -0,0 +1,55 @@
+package influxdb_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+func TestNewTaskAnalytics(t *testing.T) {
+	failed := func(logs ...string) *influxdb.Run {
+		r := &influxdb.Run{TaskID: 1}
+		for _, l := range logs {
+			r.Log = append(r.Log, influxdb.Log{Message: l})
+		}
+		return r
+	}
+
+	statuses := map[string]int{
+		"success": 2,
+		"failed":  2,
+		"timeout": 1,
+	}
+	duration := influxdb.DurationSummary{Mean: 4, P50: 3, P95: 10, Max: 10}
+	latency := influxdb.DurationSummary{Mean: 1.6, P50: 1, P95: 3, Max: 3}
+	runs := []*influxdb.Run{
+		failed("Started task from script", "bucket not found", "Completed(failed)"),
+		failed("run timed out after 10s", "Completed(timeout)"),
+		failed("bucket not found", "Completed(failed)"),
+		failed(),
+	}
+
+	got := influxdb.NewTaskAnalytics(1, statuses, duration, latency, runs)
+	exp := &influxdb.TaskAnalytics{
+		TaskID:       1,
+		Runs:         5,
+		Statuses:     statuses,
+		SuccessRate:  0.4,
+		Duration:     duration,
+		QueueLatency: latency,
+		FailureReasons: []influxdb.FailureReason{
+			{Reason: "bucket not found", Count: 2},
+			{Reason: "run timed out after 10s", Count: 1},
+			{Reason: "unknown", Count: 1},
+		},
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected task analytics, got %+v", *got)
+	}
+
+	got = influxdb.NewTaskAnalytics(2, map[string]int{}, influxdb.DurationSummary{}, influxdb.DurationSummary{}, nil)
+	if got.Runs != 0 || got.SuccessRate != 0 || len(got.FailureReasons) != 0 {
+		t.Fatalf("unexpected task analytics without runs, got %+v", *got)
+	}
+}
@@ -0,0 +1,44 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// TaskDryRunMaxRows is the most rows of each table a dry run returns.
+const TaskDryRunMaxRows = 100
+
+// TaskDryRun is the result of running the script of a task without writing
+// anything, the tables the task would have written with to() or
+// experimental.to(). Other side effects of the script are not prevented.
+type TaskDryRun struct {
+	TaskID ID `json:"taskID"`
+	// Now is the time the script ran with.
+	Now    time.Time          `json:"now"`
+	Writes []*TaskDryRunWrite `json:"writes"`
+}
+
+// TaskDryRunWrite is the data a call writing data would have written.
+type TaskDryRunWrite struct {
+	// Call is the call as written in the script, such as to(bucket: "b").
+	Call   string             `json:"call"`
+	Rows   int                `json:"rows"`
+	Tables []*TaskDryRunTable `json:"tables"`
+}
+
+// TaskDryRunTable is a table that would have been written.
+type TaskDryRunTable struct {
+	GroupKey map[string]string `json:"groupKey"`
+	Columns  []string          `json:"columns"`
+	// RowCount is the number of rows of the table, only the first
+	// TaskDryRunMaxRows of them are in Rows.
+	RowCount int             `json:"rowCount"`
+	Rows     [][]interface{} `json:"rows"`
+}
+
+// TaskDryRunService runs the scripts of tasks without writing their results.
+type TaskDryRunService interface {
+	// DryRunTask runs the script of the task as if it was scheduled for now,
+	// returning the tables it would have written.
+	DryRunTask(ctx context.Context, taskID ID, now time.Time) (*TaskDryRun, error)
+}
@@ -2,6 +2,7 @@ package influxdb
 
 import (
 	"fmt"
+	"time"
 )
 
 var (
@@ -41,6 +42,12 @@ var (
 		Msg:  "task not found",
 	}
 
+	// ErrTaskRevisionNotFound is returned when searching for a revision of a task that doesn't exist.
+	ErrTaskRevisionNotFound = &Error{
+		Code: ENotFound,
+		Msg:  "task revision not found",
+	}
+
 	// ErrRunNotFound is returned when searching for a single run that doesn't exist.
 	ErrRunNotFound = &Error{
 		Code: ENotFound,
@@ -88,8 +95,48 @@ var (
 		Code: EInvalid,
 		Msg:  "cannot create task with invalid ownerID",
 	}
+
+	// ErrTaskDependencyCycle is returned when the dependencies of a task would form a cycle.
+	ErrTaskDependencyCycle = &Error{
+		Code: EInvalid,
+		Msg:  "task dependencies must not form a cycle",
+	}
+
+	// ErrTaskHasDependents is returned when deleting a task other tasks depend on.
+	ErrTaskHasDependents = &Error{
+		Code: EConflict,
+		Msg:  "task cannot be deleted while other tasks depend on it",
+	}
+
+	// ErrInvalidTaskRunRetention is returned when the run history retention of an organization is too short.
+	ErrInvalidTaskRunRetention = &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("run history retention must be at least %s", MinTaskRunRetention),
+	}
+
+	// ErrInvalidTaskAnalyticsRange is returned when the time range of task analytics is empty.
+	ErrInvalidTaskAnalyticsRange = &Error{
+		Code: EInvalid,
+		Msg:  "task analytics start must be before stop",
+	}
 )
 
+// ErrTaskDependencyNotFound is returned when a task depends on a task that does not exist in its organization.
+func ErrTaskDependencyNotFound(id ID) *Error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("upstream task %s not found in the organization of the task", id),
+	}
+}
+
+// ErrTaskDependencySchedule is returned when a task and its upstream task are not scheduled alike.
+func ErrTaskDependencySchedule(upstreamID, taskID ID) *Error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("task %s depends on task %s and must have the same every or cron schedule", taskID, upstreamID),
+	}
+}
+
 // ErrFluxParseError is returned when an error is thrown by Flux.Parse in the task executor
 func ErrFluxParseError(err error) *Error {
 	return &Error{
@@ -167,6 +214,15 @@ func ErrJsonMarshalError(err error) *Error {
 	}
 }
 
+// ErrRunTimedOut is returned when a run is canceled for running longer than its timeout.
+func ErrRunTimedOut(timeout time.Duration) *Error {
+	return &Error{
+		Code: EInternal,
+		Msg:  fmt.Sprintf("run timed out after %s", timeout),
+		Op:   "taskExecutor",
+	}
+}
+
 func ErrRunExecutionError(err error) *Error {
 	return &Error{
 		Code: EInternal,
@@ -176,6 +232,16 @@ func ErrRunExecutionError(err error) *Error {
 	}
 }
 
+// ErrTaskOrgQueueFull is returned when a run can not be queued because the
+// organization has too many runs waiting for the executor.
+func ErrTaskOrgQueueFull(depth int) *Error {
+	return &Error{
+		Code: ETooManyRequests,
+		Msg:  fmt.Sprintf("could not queue task run, the organization has %d runs waiting", depth),
+		Op:   "taskExecutor",
+	}
+}
+
 func ErrTaskConcurrencyLimitReached(runsInFront int) *Error {
 	return &Error{
 		Code: ETooManyRequests,
@@ -0,0 +1,122 @@
+package influxdb
+
+import (
+	"sort"
+	"time"
+)
+
+// TaskGraph is the graph of the dependencies between tasks.
+type TaskGraph struct {
+	Nodes []TaskGraphNode `json:"nodes"`
+	Edges []TaskGraphEdge `json:"edges"`
+}
+
+// TaskGraphNode is a task in a task graph along with the outcome of its latest runs.
+type TaskGraphNode struct {
+	TaskID        ID        `json:"taskID"`
+	Name          string    `json:"name"`
+	Status        string    `json:"status"`
+	LastRunStatus string    `json:"lastRunStatus,omitempty"`
+	LatestSuccess time.Time `json:"latestSuccess,omitempty"`
+	LatestFailure time.Time `json:"latestFailure,omitempty"`
+}
+
+// TaskGraphEdge connects an upstream task to a task that depends on it.
+type TaskGraphEdge struct {
+	Upstream   ID `json:"upstream"`
+	Downstream ID `json:"downstream"`
+}
+
+// NewTaskGraph returns the graph of the tasks connected to the task with the
+// given id through their dependencies, in either direction. The nodes are in
+// topological order, upstream tasks come before the tasks depending on them.
+// Dependencies on tasks missing from tasks are left out.
+func NewTaskGraph(id ID, tasks []*Task) *TaskGraph {
+	byID := make(map[ID]*Task, len(tasks))
+	downstream := make(map[ID][]ID)
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	for _, t := range tasks {
+		for _, up := range t.DependsOn {
+			if _, ok := byID[up]; ok {
+				downstream[up] = append(downstream[up], t.ID)
+			}
+		}
+	}
+
+	g := &TaskGraph{
+		Nodes: []TaskGraphNode{},
+		Edges: []TaskGraphEdge{},
+	}
+	if _, ok := byID[id]; !ok {
+		return g
+	}
+
+	// collect the connected tasks
+	connected := map[ID]bool{id: true}
+	queue := []ID{id}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		neighbours := append([]ID{}, downstream[cur]...)
+		for _, up := range byID[cur].DependsOn {
+			if _, ok := byID[up]; ok {
+				neighbours = append(neighbours, up)
+			}
+		}
+		for _, n := range neighbours {
+			if !connected[n] {
+				connected[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	// order them topologically, breaking ties by ID so the order is stable
+	indegree := make(map[ID]int, len(connected))
+	for cid := range connected {
+		for _, up := range byID[cid].DependsOn {
+			if connected[up] {
+				indegree[cid]++
+				g.Edges = append(g.Edges, TaskGraphEdge{Upstream: up, Downstream: cid})
+			}
+		}
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].Upstream != g.Edges[j].Upstream {
+			return g.Edges[i].Upstream < g.Edges[j].Upstream
+		}
+		return g.Edges[i].Downstream < g.Edges[j].Downstream
+	})
+
+	var ready []ID
+	for cid := range connected {
+		if indegree[cid] == 0 {
+			ready = append(ready, cid)
+		}
+	}
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return ready[i] < ready[j] })
+		cur := ready[0]
+		ready = ready[1:]
+
+		t := byID[cur]
+		g.Nodes = append(g.Nodes, TaskGraphNode{
+			TaskID:        t.ID,
+			Name:          t.Name,
+			Status:        t.Status,
+			LastRunStatus: t.LastRunStatus,
+			LatestSuccess: t.LatestSuccess,
+			LatestFailure: t.LatestFailure,
+		})
+		for _, d := range downstream[cur] {
+			indegree[d]--
+			if indegree[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+	return g
+}
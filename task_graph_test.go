@@ -0,0 +1,40 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTaskGraph(t *testing.T) {
+	tasks := []*influxdb.Task{
+		{ID: 4, Name: "daily", DependsOn: []influxdb.ID{2, 3}},
+		{ID: 3, Name: "hourly-b", DependsOn: []influxdb.ID{1}},
+		{ID: 2, Name: "hourly-a", DependsOn: []influxdb.ID{1}},
+		{ID: 1, Name: "raw"},
+		{ID: 5, Name: "unrelated"},
+		{ID: 6, Name: "missing upstream", DependsOn: []influxdb.ID{99}},
+	}
+
+	g := influxdb.NewTaskGraph(3, tasks)
+
+	var ids []influxdb.ID
+	for _, n := range g.Nodes {
+		ids = append(ids, n.TaskID)
+	}
+	assert.Equal(t, []influxdb.ID{1, 2, 3, 4}, ids)
+	assert.Equal(t, []influxdb.TaskGraphEdge{
+		{Upstream: 1, Downstream: 2},
+		{Upstream: 1, Downstream: 3},
+		{Upstream: 2, Downstream: 4},
+		{Upstream: 3, Downstream: 4},
+	}, g.Edges)
+
+	g = influxdb.NewTaskGraph(6, tasks)
+	assert.Len(t, g.Nodes, 1)
+	assert.Empty(t, g.Edges)
+
+	g = influxdb.NewTaskGraph(99, tasks)
+	assert.Empty(t, g.Nodes)
+}
@@ -0,0 +1,179 @@
+package influxdb
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// TaskRevision is the script of a task, along with the options in it, as of
+// an update of the task. Revisions are numbered from one, in the order the
+// task was updated.
+type TaskRevision struct {
+	TaskID   ID       `json:"taskID"`
+	Revision int      `json:"revision"`
+	Flux     string   `json:"flux"`
+	Name     string   `json:"name"`
+	Every    string   `json:"every,omitempty"`
+	Cron     string   `json:"cron,omitempty"`
+	Offset   Duration `json:"offset"`
+	Trigger  string   `json:"trigger,omitempty"`
+	Debounce Duration `json:"debounce"`
+	Timeout  Duration `json:"timeout"`
+	Catchup  string   `json:"catchup,omitempty"`
+	// AuthorID is the user that made the revision, unknown for revisions
+	// of tasks created before revisions were kept.
+	AuthorID  ID        `json:"authorID,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	// RollbackOf is the revision the task was rolled back to by the revision.
+	RollbackOf int `json:"rollbackOf,omitempty"`
+}
+
+// NewTaskRevision returns the revision of the current script of the task.
+func NewTaskRevision(t *Task, authorID ID, createdAt time.Time) *TaskRevision {
+	return &TaskRevision{
+		TaskID:    t.ID,
+		Revision:  t.Revision,
+		Flux:      t.Flux,
+		Name:      t.Name,
+		Every:     t.Every,
+		Cron:      t.Cron,
+		Offset:    Duration{Duration: t.Offset},
+		Trigger:   t.Trigger,
+		Debounce:  Duration{Duration: t.Debounce},
+		Timeout:   Duration{Duration: t.Timeout},
+		Catchup:   t.Catchup,
+		AuthorID:  authorID,
+		CreatedAt: createdAt,
+	}
+}
+
+// TaskRevisionService keeps the revisions of the scripts of tasks.
+type TaskRevisionService interface {
+	// FindTaskRevisions returns the revisions of a task, oldest first.
+	FindTaskRevisions(ctx context.Context, taskID ID) ([]*TaskRevision, error)
+
+	// FindTaskRevision returns a single revision of a task.
+	FindTaskRevision(ctx context.Context, taskID ID, revision int) (*TaskRevision, error)
+
+	// RollbackTask updates the task to the script of one of its revisions,
+	// making a new revision.
+	RollbackTask(ctx context.Context, taskID ID, revision int) (*Task, error)
+}
+
+// The operations of the lines of a diff.
+const (
+	TaskDiffEqual  = "equal"
+	TaskDiffInsert = "insert"
+	TaskDiffDelete = "delete"
+)
+
+// TaskRevisionDiff is the difference between two revisions of a task.
+type TaskRevisionDiff struct {
+	TaskID  ID                 `json:"taskID"`
+	From    int                `json:"from"`
+	To      int                `json:"to"`
+	Options []TaskOptionChange `json:"options"`
+	Lines   []TaskDiffLine     `json:"lines"`
+}
+
+// TaskOptionChange is an option of a task that differs between revisions.
+type TaskOptionChange struct {
+	Option string `json:"option"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// TaskDiffLine is a line of the script of a task kept, inserted or deleted
+// between revisions.
+type TaskDiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// String returns the line prefixed as in a unified diff.
+func (l TaskDiffLine) String() string {
+	switch l.Op {
+	case TaskDiffInsert:
+		return "+" + l.Text
+	case TaskDiffDelete:
+		return "-" + l.Text
+	}
+	return " " + l.Text
+}
+
+// DiffTaskRevisions returns the changes to the options and the lines of the
+// script going from one revision to another.
+func DiffTaskRevisions(from, to *TaskRevision) *TaskRevisionDiff {
+	d := &TaskRevisionDiff{
+		TaskID:  to.TaskID,
+		From:    from.Revision,
+		To:      to.Revision,
+		Options: []TaskOptionChange{},
+	}
+
+	for _, o := range []TaskOptionChange{
+		{Option: "name", From: from.Name, To: to.Name},
+		{Option: "every", From: from.Every, To: to.Every},
+		{Option: "cron", From: from.Cron, To: to.Cron},
+		{Option: "offset", From: durationOption(from.Offset), To: durationOption(to.Offset)},
+		{Option: "trigger", From: from.Trigger, To: to.Trigger},
+		{Option: "debounce", From: durationOption(from.Debounce), To: durationOption(to.Debounce)},
+		{Option: "timeout", From: durationOption(from.Timeout), To: durationOption(to.Timeout)},
+		{Option: "catchup", From: from.Catchup, To: to.Catchup},
+	} {
+		if o.From != o.To {
+			d.Options = append(d.Options, o)
+		}
+	}
+
+	d.Lines = diffLines(strings.Split(from.Flux, "\n"), strings.Split(to.Flux, "\n"))
+	return d
+}
+
+func durationOption(d Duration) string {
+	if d.Duration == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// diffLines returns the lines of b as edits of the lines of a, keeping their
+// longest common subsequence.
+func diffLines(a, b []string) []TaskDiffLine {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := make([]TaskDiffLine, 0, len(a)+len(b)-lcs[0][0])
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, TaskDiffLine{Op: TaskDiffEqual, Text: a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, TaskDiffLine{Op: TaskDiffDelete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, TaskDiffLine{Op: TaskDiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	return lines
+}
@@ -0,0 +1,65 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTaskRevisions(t *testing.T) {
+	from := &influxdb.TaskRevision{
+		TaskID:   1,
+		Revision: 1,
+		Name:     "rollup",
+		Every:    "1h",
+		Flux: `option task = {name: "rollup", every: 1h}
+
+from(bucket: "raw")
+	|> range(start: -task.every)
+	|> to(bucket: "rollups")`,
+	}
+	to := &influxdb.TaskRevision{
+		TaskID:   1,
+		Revision: 2,
+		Name:     "rollup",
+		Every:    "30m",
+		Timeout:  influxdb.Duration{Duration: time.Minute},
+		Flux: `option task = {name: "rollup", every: 30m, timeout: 1m}
+
+from(bucket: "raw")
+	|> range(start: -task.every)
+	|> aggregateWindow(every: 1m, fn: mean)
+	|> to(bucket: "rollups")`,
+	}
+
+	d := influxdb.DiffTaskRevisions(from, to)
+	assert.Equal(t, 1, d.From)
+	assert.Equal(t, 2, d.To)
+	assert.Equal(t, []influxdb.TaskOptionChange{
+		{Option: "every", From: "1h", To: "30m"},
+		{Option: "timeout", From: "", To: "1m0s"},
+	}, d.Options)
+
+	var lines []string
+	for _, l := range d.Lines {
+		lines = append(lines, l.String())
+	}
+	assert.Equal(t, []string{
+		`-option task = {name: "rollup", every: 1h}`,
+		`+option task = {name: "rollup", every: 30m, timeout: 1m}`,
+		` `,
+		` from(bucket: "raw")`,
+		` 	|> range(start: -task.every)`,
+		`+	|> aggregateWindow(every: 1m, fn: mean)`,
+		` 	|> to(bucket: "rollups")`,
+	}, lines)
+
+	// a revision has no changes from itself
+	d = influxdb.DiffTaskRevisions(to, to)
+	assert.Empty(t, d.Options)
+	for _, l := range d.Lines {
+		assert.Equal(t, influxdb.TaskDiffEqual, l.Op)
+	}
+}
@@ -23,10 +23,20 @@ type Store struct {
 	now func() time.Time
 
 	urmByUserIndex *kv.Index
+
+	bucketLimit kv.CreateLimit
 }
 
 type StoreOption func(*Store)
 
+// WithBucketLimit limits the number of buckets of an organization, system
+// buckets do not count towards the limit.
+func WithBucketLimit(l kv.CreateLimit) StoreOption {
+	return func(s *Store) {
+		s.bucketLimit = l
+	}
+}
+
 func NewStore(kvStore kv.Store, opts ...StoreOption) *Store {
 	store := &Store{
 		kvStore:     kvStore,
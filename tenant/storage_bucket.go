@@ -264,7 +264,39 @@ func (s *Store) listBucketsByOrg(ctx context.Context, tx kv.Tx, orgID influxdb.I
 	return bs, cursor.Err()
 }
 
+// countBuckets returns the number of buckets of the organization that are not
+// system buckets.
+func (s *Store) countBuckets(ctx context.Context, tx kv.Tx, orgID influxdb.ID) (int, error) {
+	var n int
+	for offset := 0; ; offset += influxdb.MaxPageSize {
+		bs, err := s.listBucketsByOrg(ctx, tx, orgID, influxdb.FindOptions{
+			Limit:  influxdb.MaxPageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return 0, err
+		}
+		for _, b := range bs {
+			if b.Type != influxdb.BucketTypeSystem {
+				n++
+			}
+		}
+		if len(bs) < influxdb.MaxPageSize {
+			return n, nil
+		}
+	}
+}
+
 func (s *Store) CreateBucket(ctx context.Context, tx kv.Tx, bucket *influxdb.Bucket) (err error) {
+	if s.bucketLimit != nil && bucket.Type != influxdb.BucketTypeSystem {
+		err := s.bucketLimit(ctx, tx, bucket.OrgID, func() (int, error) {
+			return s.countBuckets(ctx, tx, bucket.OrgID)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	// generate new bucket ID
 	bucket.ID, err = s.generateSafeID(ctx, tx, bucketBucket, s.BucketIDGen)
 	if err != nil {
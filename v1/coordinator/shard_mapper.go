@@ -8,6 +8,8 @@ import (
 
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/influxql/query"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage/reads"
 	"github.com/influxdata/influxdb/v2/tsdb"
 	"github.com/influxdata/influxdb/v2/v1/services/meta"
 	"github.com/influxdata/influxql"
@@ -31,12 +33,17 @@ type LocalShardMapper struct {
 	}
 
 	DBRP influxdb.DBRPMappingServiceV2
+
+	// FindRestrictions, when set, narrows reads down to the series the
+	// caller is allowed to read.
+	FindRestrictions reads.RestrictionFinder
 }
 
 // MapShards maps the sources to the appropriate shards into an IteratorCreator.
 func (e *LocalShardMapper) MapShards(ctx context.Context, sources influxql.Sources, t influxql.TimeRange, opt query.SelectOptions) (query.ShardGroup, error) {
 	a := &LocalShardMapping{
-		ShardMap: make(map[Source]tsdb.ShardGroup),
+		ShardMap:     make(map[Source]tsdb.ShardGroup),
+		Restrictions: make(map[Source]reads.Restrictions),
 	}
 
 	tmin := time.Unix(0, t.MinTimeNano())
@@ -75,6 +82,15 @@ func (e *LocalShardMapper) mapShards(ctx context.Context, a *LocalShardMapping,
 				}
 
 				mapping := mappings[0]
+				if e.FindRestrictions != nil {
+					rs, err := e.FindRestrictions(ctx, mapping.OrganizationID, mapping.BucketID)
+					if err != nil {
+						return err
+					} else if rs != nil {
+						a.Restrictions[source] = rs
+					}
+				}
+
 				groups, err := e.MetaClient.ShardGroupsByTimeRange(mapping.BucketID.String(), meta.DefaultRetentionPolicyName, tmin, tmax)
 				if err != nil {
 					return err
@@ -106,6 +122,10 @@ func (e *LocalShardMapper) mapShards(ctx context.Context, a *LocalShardMapping,
 type LocalShardMapping struct {
 	ShardMap map[Source]tsdb.ShardGroup
 
+	// Restrictions limit the series that can be read from a source.
+	// Sources without restrictions are not present in the map.
+	Restrictions map[Source]reads.Restrictions
+
 	// MinTime is the minimum time that this shard mapper will allow.
 	// Any attempt to use a time before this one will automatically result in using
 	// this time instead.
@@ -137,6 +157,7 @@ func (a *LocalShardMapping) FieldDimensions(ctx context.Context, m *influxql.Mea
 	} else {
 		measurements = []string{m.Name}
 	}
+	measurements = a.allowedMeasurements(source, measurements)
 
 	f, d, err := sg.FieldDimensions(measurements)
 	if err != nil {
@@ -208,9 +229,16 @@ func (a *LocalShardMapping) CreateIterator(ctx context.Context, m *influxql.Meas
 			// Create a Measurement for each returned matching measurement value
 			// from the regex.
 			for _, measurement := range measurements {
+				mopt, ok, err := a.restrictOptions(source, measurement, opt)
+				if err != nil {
+					return err
+				} else if !ok {
+					continue
+				}
+
 				mm := m.Clone()
 				mm.Name = measurement // Set the name to this matching regex value.
-				input, err := sg.CreateIterator(ctx, mm, opt)
+				input, err := sg.CreateIterator(ctx, mm, mopt)
 				if err != nil {
 					return err
 				}
@@ -224,9 +252,57 @@ func (a *LocalShardMapping) CreateIterator(ctx context.Context, m *influxql.Meas
 
 		return query.Iterators(inputs).Merge(opt)
 	}
+
+	opt, ok, err := a.restrictOptions(source, m.Name, opt)
+	if err != nil || !ok {
+		return nil, err
+	}
 	return sg.CreateIterator(ctx, m, opt)
 }
 
+// allowedMeasurements filters out the measurements that cannot be read
+// from the source.
+func (a *LocalShardMapping) allowedMeasurements(source Source, measurements []string) []string {
+	rs, ok := a.Restrictions[source]
+	if !ok {
+		return measurements
+	}
+	allowed := measurements[:0:0]
+	for _, name := range measurements {
+		if rs.AllowsMeasurement(name) {
+			allowed = append(allowed, name)
+		}
+	}
+	return allowed
+}
+
+// restrictOptions adds the restrictions of the source to the condition of the
+// iterator options. It returns false when the measurement cannot be read at all.
+func (a *LocalShardMapping) restrictOptions(source Source, name string, opt query.IteratorOptions) (query.IteratorOptions, bool, error) {
+	rs, ok := a.Restrictions[source]
+	if !ok {
+		return opt, true, nil
+	}
+	node, ok := rs.MeasurementDataType(name)
+	if !ok || node == nil {
+		return opt, ok, nil
+	}
+
+	expr, err := reads.NodeToExpr(node, map[string]string{models.MeasurementTagKey: "_name"})
+	if err != nil {
+		return opt, false, err
+	}
+	if opt.Condition != nil {
+		expr = &influxql.BinaryExpr{
+			Op:  influxql.AND,
+			LHS: &influxql.ParenExpr{Expr: opt.Condition},
+			RHS: &influxql.ParenExpr{Expr: expr},
+		}
+	}
+	opt.Condition = expr
+	return opt, true, nil
+}
+
 func (a *LocalShardMapping) IteratorCost(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.IteratorCost, error) {
 	source := Source{
 		Database:        m.Database,
@@ -11,6 +11,8 @@ import (
 	"github.com/influxdata/influxdb/v2/dbrp/mocks"
 	"github.com/influxdata/influxdb/v2/influxql/query"
 	"github.com/influxdata/influxdb/v2/internal"
+	"github.com/influxdata/influxdb/v2/storage/reads"
+	"github.com/influxdata/influxdb/v2/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/v2/tsdb"
 	"github.com/influxdata/influxdb/v2/v1/coordinator"
 	"github.com/influxdata/influxdb/v2/v1/services/meta"
@@ -122,3 +124,98 @@ func TestLocalShardMapper(t *testing.T) {
 		t.Fatalf("unexpected error: %s", err)
 	}
 }
+
+type restrictions struct {
+	measurement string
+	node        *datatypes.Node
+}
+
+func (r restrictions) AllowsMeasurement(name string) bool { return name == r.measurement }
+
+func (r restrictions) MeasurementDataType(name string) (*datatypes.Node, bool) {
+	return r.node, name == r.measurement
+}
+
+func (r restrictions) ToDataType() (*datatypes.Node, error) { return r.node, nil }
+
+func TestLocalShardMapper_Restrictions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbrp := mocks.NewMockDBRPMappingServiceV2(ctrl)
+	orgID := influxdb.ID(0xff00)
+	bucketID := influxdb.ID(0xffee)
+	db := "db0"
+	rp := "rp0"
+	filt := influxdb.DBRPMappingFilterV2{OrgID: &orgID, Database: &db, RetentionPolicy: &rp}
+	res := []*influxdb.DBRPMappingV2{{Database: db, RetentionPolicy: rp, OrganizationID: orgID, BucketID: bucketID}}
+	dbrp.EXPECT().
+		FindMany(gomock.Any(), filt).
+		Return(res, 1, nil)
+
+	var metaClient MetaClient
+	metaClient.ShardGroupsByTimeRangeFn = func(database, policy string, min, max time.Time) ([]meta.ShardGroupInfo, error) {
+		return []meta.ShardGroupInfo{
+			{ID: 1, Shards: []meta.ShardInfo{{ID: 1, Owners: []meta.ShardOwner{{NodeID: 0}}}}},
+		}, nil
+	}
+
+	var conditions []string
+	tsdbStore := &internal.TSDBStoreMock{}
+	tsdbStore.ShardGroupFn = func(ids []uint64) tsdb.ShardGroup {
+		var sh MockShard
+		sh.CreateIteratorFn = func(ctx context.Context, measurement *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+			if measurement.Name != "cpu" {
+				t.Errorf("unexpected measurement: %s", measurement.Name)
+			}
+			conditions = append(conditions, opt.Condition.String())
+			return &FloatIterator{}, nil
+		}
+		return &sh
+	}
+
+	shardMapper := &coordinator.LocalShardMapper{
+		MetaClient: &metaClient,
+		TSDBStore:  tsdbStore,
+		DBRP:       dbrp,
+		FindRestrictions: func(ctx context.Context, oid, bid influxdb.ID) (reads.Restrictions, error) {
+			if oid != orgID || bid != bucketID {
+				t.Errorf("unexpected bucket: %s/%s", oid, bid)
+			}
+			return restrictions{
+				measurement: "cpu",
+				node: &datatypes.Node{
+					NodeType: datatypes.NodeTypeComparisonExpression,
+					Value:    &datatypes.Node_Comparison_{Comparison: datatypes.ComparisonEqual},
+					Children: []*datatypes.Node{
+						{NodeType: datatypes.NodeTypeTagRef, Value: &datatypes.Node_TagRefValue{TagRefValue: "customer"}},
+						{NodeType: datatypes.NodeTypeLiteral, Value: &datatypes.Node_StringValue{StringValue: "acme"}},
+					},
+				},
+			}, nil
+		},
+	}
+
+	cpu := &influxql.Measurement{Database: db, RetentionPolicy: rp, Name: "cpu"}
+	mem := &influxql.Measurement{Database: db, RetentionPolicy: rp, Name: "mem"}
+	ic, err := shardMapper.MapShards(context.Background(), []influxql.Source{cpu, mem}, influxql.TimeRange{}, query.SelectOptions{OrgID: orgID})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	opt := query.IteratorOptions{OrgID: orgID, Condition: influxql.MustParseExpr(`host = 'a'`)}
+	if itr, err := ic.CreateIterator(context.Background(), cpu, opt); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if itr == nil {
+		t.Fatal("expected an iterator for cpu")
+	}
+	if itr, err := ic.CreateIterator(context.Background(), mem, opt); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if itr != nil {
+		t.Fatal("expected no iterator for mem")
+	}
+
+	if exp := []string{`(host = 'a') AND (customer::tag = 'acme')`}; !reflect.DeepEqual(conditions, exp) {
+		t.Errorf("unexpected conditions: %v, exp %v", conditions, exp)
+	}
+}
@@ -53,6 +53,10 @@ type Store struct {
 	TSDBStore  TSDBStore
 	MetaClient MetaClient
 	Logger     *zap.Logger
+
+	// FindRestrictions, when set, narrows reads down to the series the
+	// caller is allowed to read.
+	FindRestrictions reads.RestrictionFinder
 }
 
 func (s *Store) WindowAggregate(ctx context.Context, req *datatypes.ReadWindowAggregateRequest) (reads.ResultSet, error) {
@@ -65,6 +69,10 @@ func (s *Store) WindowAggregate(ctx context.Context, req *datatypes.ReadWindowAg
 		return nil, err
 	}
 
+	if req.Predicate, err = s.restrictPredicate(ctx, source, req.Predicate); err != nil {
+		return nil, err
+	}
+
 	database, rp, start, end, err := s.validateArgs(source.OrganizationID, source.BucketID, req.Range.Start, req.Range.End)
 	if err != nil {
 		return nil, err
@@ -103,6 +111,19 @@ func (s *Store) WithLogger(log *zap.Logger) {
 	s.Logger = log.With(zap.String("service", "store"))
 }
 
+// restrictPredicate narrows the predicate of a read request down to the
+// series the caller is allowed to read in the bucket.
+func (s *Store) restrictPredicate(ctx context.Context, source readSource, pred *datatypes.Predicate) (*datatypes.Predicate, error) {
+	if s.FindRestrictions == nil {
+		return pred, nil
+	}
+	rs, err := s.FindRestrictions(ctx, source.GetOrgID(), source.GetBucketID())
+	if err != nil || rs == nil {
+		return pred, err
+	}
+	return reads.RestrictPredicate(pred, rs)
+}
+
 func (s *Store) findShardIDs(database, rp string, desc bool, start, end int64) ([]uint64, error) {
 	groups, err := s.MetaClient.ShardGroupsByTimeRange(database, rp, time.Unix(0, start), time.Unix(0, end))
 	if err != nil {
@@ -161,6 +182,10 @@ func (s *Store) ReadFilter(ctx context.Context, req *datatypes.ReadFilterRequest
 		return nil, err
 	}
 
+	if req.Predicate, err = s.restrictPredicate(ctx, source, req.Predicate); err != nil {
+		return nil, err
+	}
+
 	database, rp, start, end, err := s.validateArgs(source.OrganizationID, source.BucketID, req.Range.Start, req.Range.End)
 	if err != nil {
 		return nil, err
@@ -199,6 +224,10 @@ func (s *Store) ReadGroup(ctx context.Context, req *datatypes.ReadGroupRequest)
 		return nil, err
 	}
 
+	if req.Predicate, err = s.restrictPredicate(ctx, source, req.Predicate); err != nil {
+		return nil, err
+	}
+
 	database, rp, start, end, err := s.validateArgs(source.OrganizationID, source.BucketID, req.Range.Start, req.Range.End)
 	if err != nil {
 		return nil, err
@@ -284,6 +313,10 @@ func (s *Store) TagKeys(ctx context.Context, req *datatypes.TagKeysRequest) (cur
 	if err != nil {
 		return nil, err
 	}
+
+	if req.Predicate, err = s.restrictPredicate(ctx, source, req.Predicate); err != nil {
+		return nil, err
+	}
 	db, rp, start, end, err := s.validateArgs(source.OrganizationID, source.BucketID, req.Range.Start, req.Range.End)
 	if err != nil {
 		return nil, err
@@ -360,6 +393,10 @@ func (s *Store) TagValues(ctx context.Context, req *datatypes.TagValuesRequest)
 		return nil, err
 	}
 
+	if req.Predicate, err = s.restrictPredicate(ctx, source, req.Predicate); err != nil {
+		return nil, err
+	}
+
 	db, rp, start, end, err := s.validateArgs(source.OrganizationID, source.BucketID, req.Range.Start, req.Range.End)
 	if err != nil {
 		return nil, err